@@ -48,7 +48,7 @@ func main() {
 		TemperatureUnit: os.Getenv("GAMWICH_WEATHER_UNITS"),
 	}
 	settingsStore := store.NewSettingsStore(db)
-	if dbWeather, err := settingsStore.GetWeatherSettings(); err == nil {
+	if dbWeather, err := settingsStore.GetWeatherSettings(store.DefaultHouseholdID); err == nil {
 		if v := dbWeather["weather_latitude"]; v != "" {
 			weatherCfg.Latitude = v
 		}
@@ -75,7 +75,7 @@ func main() {
 
 	// License client: DB value takes priority, env var as fallback
 	licenseKey := os.Getenv("GAMWICH_LICENSE_KEY")
-	if dbKey, err := settingsStore.Get("license_key"); err == nil && dbKey != "" {
+	if dbKey, err := settingsStore.Get(store.DefaultHouseholdID, "license_key"); err == nil && dbKey != "" {
 		licenseKey = dbKey
 	}
 	licenseClient := license.NewClient(license.Config{
@@ -94,7 +94,7 @@ func main() {
 			SecretKey: os.Getenv("GAMWICH_BACKUP_S3_SECRET_KEY"),
 		},
 	}
-	if dbS3, err := settingsStore.GetS3Settings(); err == nil {
+	if dbS3, err := settingsStore.GetS3Settings(store.DefaultHouseholdID); err == nil {
 		if v := dbS3["backup_s3_endpoint"]; v != "" {
 			backupCfg.S3.Endpoint = v
 		}
@@ -117,7 +117,7 @@ func main() {
 		VAPIDPublicKey:  os.Getenv("GAMWICH_VAPID_PUBLIC_KEY"),
 		VAPIDPrivateKey: os.Getenv("GAMWICH_VAPID_PRIVATE_KEY"),
 	}
-	if dbVAPID, err := settingsStore.GetVAPIDSettings(); err == nil {
+	if dbVAPID, err := settingsStore.GetVAPIDSettings(store.DefaultHouseholdID); err == nil {
 		if v := dbVAPID["vapid_public_key"]; v != "" {
 			pushCfg.VAPIDPublicKey = v
 		}
@@ -133,8 +133,8 @@ func main() {
 			pushCfg.VAPIDPublicKey = pub
 			pushCfg.VAPIDPrivateKey = priv
 			// Persist to DB so keys survive restarts without env vars
-			settingsStore.Set("vapid_public_key", pub)
-			settingsStore.Set("vapid_private_key", priv)
+			settingsStore.Set(store.DefaultHouseholdID, "vapid_public_key", pub)
+			settingsStore.Set(store.DefaultHouseholdID, "vapid_private_key", priv)
 			slog.Info("auto-generated and persisted VAPID keys")
 		}
 	}
@@ -177,6 +177,21 @@ func main() {
 		srv.PushScheduler().Start(pushCtx)
 	}
 
+	// Start calendar subscription and CalDAV sync
+	calSyncCtx, calSyncCancel := context.WithCancel(context.Background())
+	defer calSyncCancel()
+	srv.CalendarSyncScheduler().Start(calSyncCtx)
+
+	// Start closing out missed chore occurrences
+	choreCtx, choreCancel := context.WithCancel(context.Background())
+	defer choreCancel()
+	srv.ChoreCloser().Start(choreCtx)
+
+	// Start paying weekly allowances
+	allowanceCtx, allowanceCancel := context.WithCancel(context.Background())
+	defer allowanceCancel()
+	srv.AllowanceDepositor().Start(allowanceCtx)
+
 	// Background cleanup goroutine
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
 	defer cleanupCancel()
@@ -230,6 +245,12 @@ func main() {
 	if srv.PushScheduler() != nil {
 		srv.PushScheduler().Stop()
 	}
+	calSyncCancel()
+	srv.CalendarSyncScheduler().Stop()
+	choreCancel()
+	srv.ChoreCloser().Stop()
+	allowanceCancel()
+	srv.AllowanceDepositor().Stop()
 	cleanupCancel()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
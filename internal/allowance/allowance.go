@@ -0,0 +1,81 @@
+// Package allowance turns a member's points into pocket money: weekly
+// allowance deposits into the point ledger, and monthly statements of what
+// came in and went out.
+package allowance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+const dateLayout = "2006-01-02"
+
+// DepositDays returns the deposit days (YYYY-MM-DD) an allowance is owed as
+// of today, oldest first: each of its weekdays from the day it started,
+// after the last one paid, up to and including today. Weeks missed while
+// nothing was running are all returned, so they are caught up.
+func DepositDays(a model.Allowance, today time.Time) []string {
+	if a.WeeklyPoints <= 0 {
+		return nil
+	}
+	loc := today.Location()
+	start, err := time.ParseInLocation(dateLayout, a.StartsOn, loc)
+	if err != nil {
+		return nil
+	}
+	if a.LastDeposit != "" {
+		last, err := time.ParseInLocation(dateLayout, a.LastDeposit, loc)
+		if err == nil && !last.Before(start) {
+			start = last.AddDate(0, 0, 1)
+		}
+	}
+	start = start.AddDate(0, 0, (int(a.Weekday)-int(start.Weekday())+7)%7)
+
+	end := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+	var days []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 7) {
+		days = append(days, d.Format(dateLayout))
+	}
+	return days
+}
+
+// FormatCents formats an amount of money in cents, as in "$4.50".
+func FormatCents(cents int) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s$%d.%02d", sign, cents/100, cents%100)
+}
+
+// ParseCents parses an amount of money such as "0.25", "$1" or "1.5" into
+// cents. Negative amounts and fractions of a cent are rejected.
+func ParseCents(s string) (int, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "$")
+	if s == "" {
+		return 0, nil
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	dollars, err := strconv.Atoi(whole)
+	if err != nil || dollars < 0 || strings.HasPrefix(whole, "+") {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	cents := 0
+	if frac != "" {
+		frac += strings.Repeat("0", 2-len(frac))
+		if cents, err = strconv.Atoi(frac); err != nil || cents < 0 || strings.HasPrefix(frac, "+") {
+			return 0, fmt.Errorf("invalid amount %q", s)
+		}
+	}
+	return dollars*100 + cents, nil
+}
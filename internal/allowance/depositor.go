@@ -0,0 +1,118 @@
+package allowance
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/store"
+)
+
+// Depositor pays weekly allowances into members' point ledgers. It runs
+// hourly, paying each household's allowances soon after midnight on their
+// deposit day, and catches up on any deposits missed while it was down.
+type Depositor struct {
+	mu       sync.RWMutex
+	rewards  *store.RewardStore
+	settings *store.SettingsStore
+	interval time.Duration
+	logger   *slog.Logger
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewDepositor creates an allowance depositor.
+func NewDepositor(rewardStore *store.RewardStore, settingsStore *store.SettingsStore, logger *slog.Logger) *Depositor {
+	return &Depositor{
+		rewards:  rewardStore,
+		settings: settingsStore,
+		interval: time.Hour,
+		logger:   logger,
+	}
+}
+
+// Start begins the depositor loop.
+func (d *Depositor) Start(ctx context.Context) {
+	d.mu.Lock()
+	ctx, d.cancel = context.WithCancel(ctx)
+	d.done = make(chan struct{})
+	d.mu.Unlock()
+
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		d.tick()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.tick()
+			}
+		}
+	}()
+}
+
+// Stop gracefully stops the depositor.
+func (d *Depositor) Stop() {
+	d.mu.RLock()
+	cancel := d.cancel
+	done := d.done
+	d.mu.RUnlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if done != nil {
+		<-done
+	}
+}
+
+func (d *Depositor) tick() {
+	householdIDs, err := d.rewards.ListAllowanceHouseholdIDs()
+	if err != nil {
+		d.logger.Error("list allowance households", "error", err)
+		return
+	}
+
+	for _, hid := range householdIDs {
+		loc, err := d.settings.Location(hid)
+		if err != nil {
+			d.logger.Error("household timezone", "household_id", hid, "error", err)
+		}
+		n, err := d.DepositHousehold(hid, time.Now().In(loc))
+		if err != nil {
+			d.logger.Error("deposit allowances", "household_id", hid, "error", err)
+			continue
+		}
+		if n > 0 {
+			d.logger.Info("deposited allowances", "household_id", hid, "count", n)
+		}
+	}
+}
+
+// DepositHousehold pays every allowance deposit in the household that is
+// owed as of today. It returns how many it paid.
+func (d *Depositor) DepositHousehold(householdID int64, today time.Time) (int, error) {
+	allowances, err := d.rewards.ListAllowances(householdID)
+	if err != nil {
+		return 0, err
+	}
+
+	paid := 0
+	for _, a := range allowances {
+		for _, day := range DepositDays(a, today) {
+			ok, err := d.rewards.DepositAllowance(a.FamilyMemberID, householdID, day)
+			if err != nil {
+				return paid, err
+			}
+			if ok {
+				paid++
+			}
+		}
+	}
+	return paid, nil
+}
@@ -0,0 +1,149 @@
+package allowance
+
+import (
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/database"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/store"
+)
+
+func TestDepositDays(t *testing.T) {
+	// Saturday, 2026-02-14.
+	today := time.Date(2026, 2, 14, 7, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		a    model.Allowance
+		want []string
+	}{
+		{
+			name: "nothing paid yet",
+			a:    model.Allowance{WeeklyPoints: 10, Weekday: time.Saturday, StartsOn: "2026-02-01"},
+			want: []string{"2026-02-07", "2026-02-14"},
+		},
+		{
+			name: "starts on a deposit day",
+			a:    model.Allowance{WeeklyPoints: 10, Weekday: time.Saturday, StartsOn: "2026-02-07"},
+			want: []string{"2026-02-07", "2026-02-14"},
+		},
+		{
+			name: "paid up to last week",
+			a:    model.Allowance{WeeklyPoints: 10, Weekday: time.Saturday, StartsOn: "2026-01-01", LastDeposit: "2026-02-07"},
+			want: []string{"2026-02-14"},
+		},
+		{
+			name: "paid up",
+			a:    model.Allowance{WeeklyPoints: 10, Weekday: time.Saturday, StartsOn: "2026-01-01", LastDeposit: "2026-02-14"},
+		},
+		{
+			name: "deposit day later in the week",
+			a:    model.Allowance{WeeklyPoints: 10, Weekday: time.Sunday, StartsOn: "2026-02-10"},
+		},
+		{
+			name: "no weekly points",
+			a:    model.Allowance{CentsPerPoint: 10, Weekday: time.Saturday, StartsOn: "2026-01-01"},
+		},
+		{
+			name: "restarted after a pause",
+			a:    model.Allowance{WeeklyPoints: 10, Weekday: time.Saturday, StartsOn: "2026-02-10", LastDeposit: "2026-01-03"},
+			want: []string{"2026-02-14"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DepositDays(tt.a, today); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DepositDays() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCents(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"0.25", 25, false},
+		{"$1", 100, false},
+		{"1.5", 150, false},
+		{".05", 5, false},
+		{"-1", 0, true},
+		{"0.125", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCents(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCents(%q) = %d, %v; want %d, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+	if got := FormatCents(-1250); got != "-$12.50" {
+		t.Errorf("FormatCents(-1250) = %q", got)
+	}
+}
+
+func TestDepositHousehold(t *testing.T) {
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	hid := store.DefaultHouseholdID
+	rs := store.NewRewardStore(db)
+	ms := store.NewFamilyMemberStore(db)
+	kid, _ := ms.Create(hid, "Kid", "#FF0000", "K")
+	saver, _ := ms.Create(hid, "Saver", "#00FF00", "S")
+
+	if _, err := rs.SetAllowance(kid.ID, hid, 10, 20, time.Saturday, "2026-02-01"); err != nil {
+		t.Fatalf("set allowance: %v", err)
+	}
+	// Points are worth money to Saver, but they get no allowance.
+	if _, err := rs.SetAllowance(saver.ID, hid, 5, 0, time.Saturday, "2026-02-01"); err != nil {
+		t.Fatalf("set allowance: %v", err)
+	}
+
+	depositor := NewDepositor(rs, store.NewSettingsStore(db), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	today := time.Date(2026, 2, 14, 1, 0, 0, 0, time.UTC)
+	n, err := depositor.DepositHousehold(hid, today)
+	if err != nil {
+		t.Fatalf("deposit: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("paid %d deposits, want 2", n)
+	}
+
+	// Running again the same day pays nothing more.
+	if n, _ := depositor.DepositHousehold(hid, today); n != 0 {
+		t.Errorf("paid %d deposits on rerun, want 0", n)
+	}
+
+	bal, _ := rs.GetPointBalance(kid.ID, hid)
+	if bal.Balance != 40 || bal.ValueCents != 400 {
+		t.Errorf("balance = %d (%d cents), want 40 (400 cents)", bal.Balance, bal.ValueCents)
+	}
+	txns, _ := rs.ListTransactions(kid.ID, hid, 0)
+	for _, tx := range txns {
+		if tx.Kind != model.PointsAllowance {
+			t.Errorf("transaction kind = %q, want allowance", tx.Kind)
+		}
+	}
+	if bal, _ := rs.GetPointBalance(saver.ID, hid); bal.Balance != 0 {
+		t.Errorf("saver balance = %d, want 0", bal.Balance)
+	}
+
+	a, _ := rs.GetAllowance(kid.ID, hid)
+	if a.LastDeposit != "2026-02-14" {
+		t.Errorf("last deposit = %q, want 2026-02-14", a.LastDeposit)
+	}
+}
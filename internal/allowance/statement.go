@@ -0,0 +1,122 @@
+package allowance
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+// Statement is a member's points for one calendar month: the balance going
+// in, every change to it during the month, and the balance coming out.
+type Statement struct {
+	MemberName    string
+	Month         time.Time // midnight on the first of the month
+	CentsPerPoint int
+	Opening       int
+	Closing       int
+	In            int // points added during the month
+	Out           int // points taken away during the month
+	Entries       []StatementEntry
+}
+
+// StatementEntry is one ledger entry on a statement, with the balance
+// after it.
+type StatementEntry struct {
+	model.PointTransaction
+	Balance int
+}
+
+// MonthStart returns midnight on the first of the month t falls in.
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// BuildStatement builds the statement for the month starting at month from
+// a member's whole ledger, in any order. Entries before the month make up
+// the opening balance and entries after it are left out.
+func BuildStatement(memberName string, txns []model.PointTransaction, month time.Time, centsPerPoint int) Statement {
+	start := MonthStart(month)
+	end := start.AddDate(0, 1, 0)
+
+	sorted := make([]model.PointTransaction, len(txns))
+	copy(sorted, txns)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	st := Statement{MemberName: memberName, Month: start, CentsPerPoint: centsPerPoint}
+	for _, t := range sorted {
+		switch {
+		case t.CreatedAt.Before(start):
+			st.Opening += t.Amount
+			continue
+		case !t.CreatedAt.Before(end):
+			continue
+		}
+		if t.Amount >= 0 {
+			st.In += t.Amount
+		} else {
+			st.Out -= t.Amount
+		}
+		st.Entries = append(st.Entries, StatementEntry{PointTransaction: t, Balance: st.Opening + st.In - st.Out})
+	}
+	st.Closing = st.Opening + st.In - st.Out
+	return st
+}
+
+// Filename is the name a statement is downloaded as.
+func (st Statement) Filename() string {
+	name := make([]rune, 0, len(st.MemberName))
+	for _, r := range st.MemberName {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			name = append(name, r)
+		case len(name) > 0 && name[len(name)-1] != '-':
+			name = append(name, '-')
+		}
+	}
+	prefix := strings.Trim(string(name), "-")
+	if prefix == "" {
+		prefix = "points"
+	}
+	return prefix + "-" + st.Month.Format("2006-01") + ".csv"
+}
+
+// WriteCSV writes the statement as CSV, opening and closing with the
+// balance. When points are worth money, each row also has the value of the
+// balance.
+func (st Statement) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"Date", "Type", "Description", "Points", "Balance"}
+	if st.CentsPerPoint > 0 {
+		header = append(header, "Value")
+	}
+	rows := [][]string{header}
+
+	row := func(date time.Time, kind, description, points string, balance int) {
+		r := []string{date.Format(dateLayout), kind, description, points, strconv.Itoa(balance)}
+		if st.CentsPerPoint > 0 {
+			r = append(r, FormatCents(balance*st.CentsPerPoint))
+		}
+		rows = append(rows, r)
+	}
+
+	row(st.Month, "opening", "Opening balance", "", st.Opening)
+	for _, e := range st.Entries {
+		row(e.CreatedAt.In(st.Month.Location()), string(e.Kind), e.Reason, strconv.Itoa(e.Amount), e.Balance)
+	}
+	row(st.Month.AddDate(0, 1, -1), "closing", "Closing balance", "", st.Closing)
+
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
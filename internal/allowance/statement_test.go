@@ -0,0 +1,56 @@
+package allowance
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+func TestBuildStatement(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC) }
+	txns := []model.PointTransaction{
+		{ID: 5, Kind: model.PointsEarned, Amount: 3, Reason: "Too late", CreatedAt: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 4, Kind: model.PointsSpent, Amount: -15, Reason: "Movie night", CreatedAt: at(20, 18)},
+		{ID: 3, Kind: model.PointsAllowance, Amount: 10, Reason: "Weekly allowance", CreatedAt: at(7, 0)},
+		{ID: 2, Kind: model.PointsEarned, Amount: 5, Reason: "Feed cat", CreatedAt: at(2, 8)},
+		{ID: 1, Kind: model.PointsBonus, Amount: 20, Reason: "Birthday", CreatedAt: time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC)},
+	}
+
+	st := BuildStatement("Kid", txns, at(15, 0), 10)
+	if st.Opening != 20 || st.In != 15 || st.Out != 15 || st.Closing != 20 {
+		t.Errorf("opening %d, in %d, out %d, closing %d; want 20, 15, 15, 20", st.Opening, st.In, st.Out, st.Closing)
+	}
+	if len(st.Entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(st.Entries))
+	}
+	wantBalances := []int{25, 35, 20}
+	for i, e := range st.Entries {
+		if e.Balance != wantBalances[i] {
+			t.Errorf("entry %d (%s) balance = %d, want %d", i, e.Reason, e.Balance, wantBalances[i])
+		}
+	}
+
+	var b strings.Builder
+	if err := st.WriteCSV(&b); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	want := `Date,Type,Description,Points,Balance,Value
+2026-03-01,opening,Opening balance,,20,$2.00
+2026-03-02,earn,Feed cat,5,25,$2.50
+2026-03-07,allowance,Weekly allowance,10,35,$3.50
+2026-03-20,spend,Movie night,-15,20,$2.00
+2026-03-31,closing,Closing balance,,20,$2.00
+`
+	if b.String() != want {
+		t.Errorf("csv =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	if got := st.Filename(); got != "Kid-2026-03.csv" {
+		t.Errorf("filename = %q", got)
+	}
+	if got := (Statement{MemberName: "Anne Marie!", Month: at(1, 0)}).Filename(); got != "Anne-Marie-2026-03.csv" {
+		t.Errorf("filename = %q", got)
+	}
+}
@@ -215,7 +215,8 @@ func (m *Manager) HasCachedKey(householdID int64) bool {
 func (m *Manager) checkSchedule(ctx context.Context) {
 	now := time.Now().UTC()
 
-	settings, err := m.settingsStore.GetBackupSettings()
+	householdID := store.DefaultHouseholdID
+	settings, err := m.settingsStore.GetBackupSettings(householdID)
 	if err != nil {
 		return
 	}
@@ -224,12 +225,15 @@ func (m *Manager) checkSchedule(ctx context.Context) {
 		return
 	}
 
+	loc, err := m.settingsStore.Location(householdID)
+	if err != nil {
+		m.logger.Warn("backup schedule timezone", "household_id", householdID, "error", err)
+	}
 	hour, _ := strconv.Atoi(settings["backup_schedule_hour"])
-	if now.Hour() != hour || now.Minute() != 0 {
+	if !scheduledAt(now, hour, loc) {
 		return
 	}
 
-	var householdID int64 = 1
 	m.mu.RLock()
 	creds, hasCached := m.cachedCreds[householdID]
 	m.mu.RUnlock()
@@ -252,6 +256,19 @@ func (m *Manager) checkSchedule(ctx context.Context) {
 	}
 }
 
+// scheduledAt reports whether now is the minute a daily backup at hour, on
+// loc's clock, should start. An hour skipped by a daylight saving change
+// runs when the clocks jump past it, and an hour that repeats runs once.
+func scheduledAt(now time.Time, hour int, loc *time.Location) bool {
+	local := now.In(loc)
+	at := time.Date(local.Year(), local.Month(), local.Day(), hour, 0, 0, 0, loc)
+	if h := at.In(loc).Hour(); h != hour {
+		// time.Date may resolve a skipped hour to the hour before the gap.
+		at = at.Add(time.Duration(hour-h) * time.Hour)
+	}
+	return now.Truncate(time.Minute).Equal(at)
+}
+
 // RunNow runs a backup immediately with the provided passphrase.
 func (m *Manager) RunNow(ctx context.Context, householdID int64, passphrase string) (int64, error) {
 	m.mu.RLock()
@@ -261,7 +278,7 @@ func (m *Manager) RunNow(ctx context.Context, householdID int64, passphrase stri
 		return 0, fmt.Errorf("backup not configured: S3 credentials missing")
 	}
 
-	settings, err := m.settingsStore.GetBackupSettings()
+	settings, err := m.settingsStore.GetBackupSettings(householdID)
 	if err != nil {
 		return 0, fmt.Errorf("get backup settings: %w", err)
 	}
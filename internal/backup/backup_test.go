@@ -191,3 +191,41 @@ func TestManagerDisabledNoStart(t *testing.T) {
 	// Stop should not block
 	m.Stop()
 }
+
+func TestScheduledAtHouseholdTimezone(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+
+	// countRuns returns the UTC minutes in the local day starting at
+	// midnight that start a backup at hour.
+	countRuns := func(day time.Time, hour int) []time.Time {
+		var runs []time.Time
+		for t := day; t.Before(day.AddDate(0, 0, 1)); t = t.Add(time.Minute) {
+			if scheduledAt(t, hour, ny) {
+				runs = append(runs, t.UTC())
+			}
+		}
+		return runs
+	}
+
+	// A 3am backup runs at 3am New York time, not 3am UTC.
+	runs := countRuns(time.Date(2026, 2, 10, 0, 0, 0, 0, ny), 3)
+	if len(runs) != 1 || !runs[0].Equal(time.Date(2026, 2, 10, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("3am runs = %v, want 08:00 UTC", runs)
+	}
+
+	// 2am does not exist on Mar 8, 2026; the backup runs once when the
+	// clocks jump to 3am EDT.
+	runs = countRuns(time.Date(2026, 3, 8, 0, 0, 0, 0, ny), 2)
+	if len(runs) != 1 || !runs[0].Equal(time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("2am runs on spring forward = %v, want 07:00 UTC", runs)
+	}
+
+	// 1am happens twice on Nov 1, 2026; the backup still runs once.
+	runs = countRuns(time.Date(2026, 11, 1, 0, 0, 0, 0, ny), 1)
+	if len(runs) != 1 {
+		t.Errorf("1am runs on fall back = %v, want one", runs)
+	}
+}
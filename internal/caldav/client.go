@@ -0,0 +1,294 @@
+// Package caldav implements the parts of WebDAV and CalDAV (RFC 4918,
+// RFC 4791) used to sync a single calendar collection.
+package caldav
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Namespaces used in requests and responses.
+const (
+	NSDAV            = "DAV:"
+	NSCalDAV         = "urn:ietf:params:xml:ns:caldav"
+	NSCalendarServer = "http://calendarserver.org/ns/"
+)
+
+// ErrPreconditionFailed is returned when an If-Match or If-None-Match
+// condition fails because the remote object changed.
+var ErrPreconditionFailed = errors.New("remote object changed")
+
+// Object is a calendar object resource in a collection. Data is only set
+// when the object was fetched with its contents.
+type Object struct {
+	Href string
+	ETag string
+	Data string
+}
+
+// Client talks to one CalDAV server with HTTP basic auth.
+type Client struct {
+	http     *http.Client
+	username string
+	password string
+}
+
+func NewClient(username, password string) *Client {
+	return &Client{
+		http:     &http.Client{Timeout: 30 * time.Second},
+		username: username,
+		password: password,
+	}
+}
+
+// CTag returns the collection's getctag, which changes whenever any object
+// in it changes. It is empty if the server does not support it.
+func (c *Client) CTag(ctx context.Context, collectionURL string) (string, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?>` +
+		`<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">` +
+		`<d:prop><cs:getctag/></d:prop></d:propfind>`
+	ms, err := c.multistatus(ctx, "PROPFIND", collectionURL, "0", body)
+	if err != nil {
+		return "", err
+	}
+	for _, resp := range ms.Responses {
+		if prop, ok := resp.OKProp(); ok {
+			return prop.CTag, nil
+		}
+	}
+	return "", nil
+}
+
+// List returns the href and ETag of every event in the collection.
+func (c *Client) List(ctx context.Context, collectionURL string) ([]Object, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?>` +
+		`<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">` +
+		`<d:prop><d:getetag/></d:prop>` +
+		`<c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT"/></c:comp-filter></c:filter>` +
+		`</c:calendar-query>`
+	ms, err := c.multistatus(ctx, "REPORT", collectionURL, "1", body)
+	if err != nil {
+		return nil, err
+	}
+	return objects(collectionURL, ms), nil
+}
+
+// Multiget fetches the contents of the given objects in one request.
+func (c *Client) Multiget(ctx context.Context, collectionURL string, hrefs []string) ([]Object, error) {
+	if len(hrefs) == 0 {
+		return nil, nil
+	}
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
+	b.WriteString(`<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">`)
+	b.WriteString(`<d:prop><d:getetag/><c:calendar-data/></d:prop>`)
+	for _, href := range hrefs {
+		b.WriteString("<d:href>")
+		xml.EscapeText(&b, []byte(hrefPath(href)))
+		b.WriteString("</d:href>")
+	}
+	b.WriteString(`</c:calendar-multiget>`)
+
+	ms, err := c.multistatus(ctx, "REPORT", collectionURL, "1", b.String())
+	if err != nil {
+		return nil, err
+	}
+	return objects(collectionURL, ms), nil
+}
+
+// Put stores an object. An empty etag creates it and fails if it already
+// exists; otherwise the update only succeeds if the ETag still matches.
+// It returns the new ETag, which is empty if the server did not send one.
+func (c *Client) Put(ctx context.Context, href, data, etag string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, href, strings.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	if etag == "" {
+		req.Header.Set("If-None-Match", "*")
+	} else {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return "", ErrPreconditionFailed
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return "", fmt.Errorf("put %s: unexpected status %d", href, resp.StatusCode)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// Delete removes an object if its ETag still matches. Objects that are
+// already gone are not an error.
+func (c *Client) Delete(ctx context.Context, href, etag string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, href, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("delete %s: unexpected status %d", href, resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *Client) multistatus(ctx context.Context, method, target, depth, body string) (*Multistatus, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", depth)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("%s %s: unexpected status %d", method, target, resp.StatusCode)
+	}
+
+	var ms Multistatus
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 50<<20)).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("decode multistatus: %w", err)
+	}
+	return &ms, nil
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	req.Header.Set("User-Agent", "Gamwich")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Redacted(), err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: not authorized (status %d)", req.Method, req.URL.Redacted(), resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// objects collects the successful responses in ms, resolving hrefs against
+// the collection URL and skipping the collection itself.
+func objects(collectionURL string, ms *Multistatus) []Object {
+	base, err := url.Parse(collectionURL)
+	if err != nil {
+		return nil
+	}
+	var out []Object
+	for _, resp := range ms.Responses {
+		prop, ok := resp.OKProp()
+		if !ok || prop.ResourceType.Collection != nil {
+			continue
+		}
+		ref, err := url.Parse(resp.Href)
+		if err != nil {
+			continue
+		}
+		abs := base.ResolveReference(ref)
+		if strings.TrimSuffix(abs.Path, "/") == strings.TrimSuffix(base.Path, "/") {
+			continue
+		}
+		out = append(out, Object{Href: abs.String(), ETag: prop.ETag, Data: prop.CalendarData})
+	}
+	return out
+}
+
+// hrefPath returns the path of an absolute href, as servers expect in
+// multiget requests.
+func hrefPath(href string) string {
+	u, err := url.Parse(href)
+	if err != nil || u.Path == "" {
+		return href
+	}
+	return u.EscapedPath()
+}
+
+// CollectionURL returns u with a trailing slash, so object hrefs resolve
+// inside the collection.
+func CollectionURL(u string) string {
+	if strings.HasSuffix(u, "/") {
+		return u
+	}
+	return u + "/"
+}
+
+// ObjectURL returns the href for a new object with the given UID.
+func ObjectURL(collectionURL, uid string) string {
+	return CollectionURL(collectionURL) + url.PathEscape(uid) + ".ics"
+}
+
+// Multistatus is a WebDAV 207 Multi-Status response body.
+type Multistatus struct {
+	XMLName   xml.Name   `xml:"DAV: multistatus"`
+	Responses []Response `xml:"DAV: response"`
+}
+
+type Response struct {
+	Href      string     `xml:"DAV: href"`
+	Propstats []Propstat `xml:"DAV: propstat"`
+	Status    string     `xml:"DAV: status,omitempty"`
+}
+
+type Propstat struct {
+	Prop   Prop   `xml:"DAV: prop"`
+	Status string `xml:"DAV: status"`
+}
+
+type Prop struct {
+	ETag         string       `xml:"DAV: getetag,omitempty"`
+	CTag         string       `xml:"http://calendarserver.org/ns/ getctag,omitempty"`
+	CalendarData string       `xml:"urn:ietf:params:xml:ns:caldav calendar-data,omitempty"`
+	ResourceType ResourceType `xml:"DAV: resourcetype"`
+}
+
+type ResourceType struct {
+	Collection *struct{} `xml:"DAV: collection"`
+	Calendar   *struct{} `xml:"urn:ietf:params:xml:ns:caldav calendar"`
+}
+
+// OKProp returns the properties from the response's 200 propstat.
+func (r Response) OKProp() (Prop, bool) {
+	for _, ps := range r.Propstats {
+		if strings.Contains(ps.Status, " 200") {
+			return ps.Prop, true
+		}
+	}
+	return Prop{}, false
+}
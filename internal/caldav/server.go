@@ -0,0 +1,276 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// NSAppleICal is the namespace of Apple's calendar color and order properties.
+const NSAppleICal = "http://apple.com/ns/ical/"
+
+// Properties served by the built-in CalDAV server.
+var (
+	PropResourceType            = xml.Name{Space: NSDAV, Local: "resourcetype"}
+	PropDisplayName             = xml.Name{Space: NSDAV, Local: "displayname"}
+	PropGetETag                 = xml.Name{Space: NSDAV, Local: "getetag"}
+	PropGetContentType          = xml.Name{Space: NSDAV, Local: "getcontenttype"}
+	PropCurrentUserPrincipal    = xml.Name{Space: NSDAV, Local: "current-user-principal"}
+	PropPrincipalURL            = xml.Name{Space: NSDAV, Local: "principal-URL"}
+	PropCurrentUserPrivilegeSet = xml.Name{Space: NSDAV, Local: "current-user-privilege-set"}
+	PropSupportedReportSet      = xml.Name{Space: NSDAV, Local: "supported-report-set"}
+	PropCalendarHomeSet         = xml.Name{Space: NSCalDAV, Local: "calendar-home-set"}
+	PropCalendarData            = xml.Name{Space: NSCalDAV, Local: "calendar-data"}
+	PropSupportedComponentSet   = xml.Name{Space: NSCalDAV, Local: "supported-calendar-component-set"}
+	PropGetCTag                 = xml.Name{Space: NSCalendarServer, Local: "getctag"}
+	PropCalendarColor           = xml.Name{Space: NSAppleICal, Local: "calendar-color"}
+)
+
+// Reports handled by the built-in CalDAV server.
+var (
+	ReportCalendarQuery    = xml.Name{Space: NSCalDAV, Local: "calendar-query"}
+	ReportCalendarMultiget = xml.Name{Space: NSCalDAV, Local: "calendar-multiget"}
+)
+
+// ErrBadRequest is returned when a request body is not well-formed.
+var ErrBadRequest = errors.New("malformed request body")
+
+// Property is a property in a multistatus response. Inner is the property
+// value as XML and may be empty.
+type Property struct {
+	Name  xml.Name
+	Inner string
+}
+
+// TextProperty returns a property whose value is escaped text.
+func TextProperty(name xml.Name, value string) Property {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return Property{Name: name, Inner: b.String()}
+}
+
+// HrefProperty returns a property whose value is a DAV:href.
+func HrefProperty(name xml.Name, href string) Property {
+	var b strings.Builder
+	b.WriteString("<d:href>")
+	xml.EscapeText(&b, []byte(href))
+	b.WriteString("</d:href>")
+	return Property{Name: name, Inner: b.String()}
+}
+
+// PropResponse describes one resource in a multistatus response. A non-zero
+// Status reports the whole resource, for example a multiget href that does
+// not exist, instead of its properties.
+type PropResponse struct {
+	Href     string
+	Props    []Property
+	NotFound []xml.Name
+	Status   int
+}
+
+var prefixes = map[string]string{
+	NSDAV:            "d",
+	NSCalDAV:         "c",
+	NSCalendarServer: "cs",
+	NSAppleICal:      "ical",
+}
+
+// WriteMultistatus writes a 207 Multi-Status response.
+func WriteMultistatus(w http.ResponseWriter, responses []PropResponse) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/" xmlns:ical="http://apple.com/ns/ical/">`)
+	for _, r := range responses {
+		b.WriteString("<d:response><d:href>")
+		xml.EscapeText(&b, []byte(r.Href))
+		b.WriteString("</d:href>")
+		if r.Status != 0 {
+			writeStatus(&b, r.Status)
+		} else {
+			if len(r.Props) > 0 || len(r.NotFound) == 0 {
+				b.WriteString("<d:propstat><d:prop>")
+				for _, p := range r.Props {
+					writeElement(&b, p.Name, p.Inner)
+				}
+				b.WriteString("</d:prop>")
+				writeStatus(&b, http.StatusOK)
+				b.WriteString("</d:propstat>")
+			}
+			if len(r.NotFound) > 0 {
+				b.WriteString("<d:propstat><d:prop>")
+				for _, name := range r.NotFound {
+					writeElement(&b, name, "")
+				}
+				b.WriteString("</d:prop>")
+				writeStatus(&b, http.StatusNotFound)
+				b.WriteString("</d:propstat>")
+			}
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	io.WriteString(w, b.String())
+}
+
+// WriteError writes a WebDAV error response naming the precondition that
+// failed, such as CALDAV:valid-calendar-data.
+func WriteError(w http.ResponseWriter, status int, condition xml.Name) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<d:error xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">`)
+	writeElement(&b, condition, "")
+	b.WriteString("</d:error>")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(status)
+	io.WriteString(w, b.String())
+}
+
+func writeStatus(b *strings.Builder, status int) {
+	fmt.Fprintf(b, "<d:status>HTTP/1.1 %d %s</d:status>", status, http.StatusText(status))
+}
+
+// writeElement writes an element, declaring its namespace inline when it
+// has no well-known prefix.
+func writeElement(b *strings.Builder, name xml.Name, inner string) {
+	tag := name.Local
+	var decl string
+	if prefix, ok := prefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		var ns strings.Builder
+		xml.EscapeText(&ns, []byte(name.Space))
+		decl = ` xmlns:x="` + ns.String() + `"`
+	}
+	if inner == "" {
+		b.WriteString("<" + tag + decl + "/>")
+		return
+	}
+	b.WriteString("<" + tag + decl + ">" + inner + "</" + tag + ">")
+}
+
+// ParsePropfind returns the properties requested by a PROPFIND body. It
+// returns nil for an empty body or DAV:allprop.
+func ParsePropfind(r io.Reader) ([]xml.Name, error) {
+	dec := xml.NewDecoder(r)
+	var props []xml.Name
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return props, nil
+		}
+		if err != nil {
+			return nil, ErrBadRequest
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Space != NSDAV {
+			continue
+		}
+		switch start.Name.Local {
+		case "allprop", "propname":
+			return nil, nil
+		case "prop":
+			names, err := childNames(dec)
+			if err != nil {
+				return nil, err
+			}
+			props = append(props, names...)
+		}
+	}
+}
+
+// Report is a parsed REPORT body.
+type Report struct {
+	// Name is the report's root element.
+	Name xml.Name
+	// Props are the requested properties; nil means all of them.
+	Props []xml.Name
+	// Hrefs are the resources named by a calendar-multiget.
+	Hrefs []string
+	// Start and End bound a calendar-query time-range filter. Either may be
+	// zero when the range is open on that side.
+	Start, End time.Time
+}
+
+// ParseReport reads a REPORT body.
+func ParseReport(r io.Reader) (Report, error) {
+	dec := xml.NewDecoder(r)
+	var rep Report
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Report{}, ErrBadRequest
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if rep.Name.Local == "" {
+			rep.Name = start.Name
+			continue
+		}
+		switch start.Name {
+		case xml.Name{Space: NSDAV, Local: "prop"}:
+			names, err := childNames(dec)
+			if err != nil {
+				return Report{}, err
+			}
+			rep.Props = append(rep.Props, names...)
+		case xml.Name{Space: NSDAV, Local: "href"}:
+			var href string
+			if err := dec.DecodeElement(&href, &start); err != nil {
+				return Report{}, ErrBadRequest
+			}
+			rep.Hrefs = append(rep.Hrefs, strings.TrimSpace(href))
+		case xml.Name{Space: NSCalDAV, Local: "time-range"}:
+			for _, attr := range start.Attr {
+				t, err := time.Parse("20060102T150405Z", attr.Value)
+				if err != nil {
+					return Report{}, ErrBadRequest
+				}
+				switch attr.Name.Local {
+				case "start":
+					rep.Start = t
+				case "end":
+					rep.End = t
+				}
+			}
+		}
+	}
+	if rep.Name.Local == "" {
+		return Report{}, ErrBadRequest
+	}
+	return rep, nil
+}
+
+// childNames returns the names of the child elements of the element just
+// opened on dec, consuming it.
+func childNames(dec *xml.Decoder) ([]xml.Name, error) {
+	var names []xml.Name
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, ErrBadRequest
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			names = append(names, t.Name)
+			if err := dec.Skip(); err != nil {
+				return nil, ErrBadRequest
+			}
+		case xml.EndElement:
+			return names, nil
+		}
+	}
+}
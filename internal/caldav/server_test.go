@@ -0,0 +1,272 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const davNS = `xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/"`
+
+func TestParsePropfind(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []xml.Name
+	}{
+		{"empty body", "", nil},
+		{"allprop", `<d:propfind ` + davNS + `><d:allprop/></d:propfind>`, nil},
+		{"propname", `<d:propfind ` + davNS + `><d:propname/></d:propfind>`, nil},
+		{
+			"prop",
+			`<d:propfind ` + davNS + `><d:prop><d:displayname/><cs:getctag/><c:calendar-home-set/></d:prop></d:propfind>`,
+			[]xml.Name{PropDisplayName, PropGetCTag, PropCalendarHomeSet},
+		},
+		{
+			"unknown properties are kept",
+			`<d:propfind ` + davNS + ` xmlns:x="urn:example"><d:prop><x:flavor/><d:getetag/></d:prop></d:propfind>`,
+			[]xml.Name{{Space: "urn:example", Local: "flavor"}, PropGetETag},
+		},
+		{
+			"property values are skipped",
+			`<d:propfind ` + davNS + `><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop></d:propfind>`,
+			[]xml.Name{PropResourceType},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePropfind(strings.NewReader(tt.body))
+		if err != nil {
+			t.Errorf("%s: error: %v", tt.name, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: props = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParsePropfindMalformed(t *testing.T) {
+	tests := []string{
+		`<d:propfind ` + davNS + `><d:prop><d:displayname/>`,
+		`<d:propfind ` + davNS + `><d:prop></d:propfind>`,
+		`not xml <`,
+	}
+	for _, body := range tests {
+		if _, err := ParsePropfind(strings.NewReader(body)); !errors.Is(err, ErrBadRequest) {
+			t.Errorf("ParsePropfind(%q) error = %v, want ErrBadRequest", body, err)
+		}
+	}
+}
+
+func TestParseReport(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want Report
+	}{
+		{
+			"calendar-query with time-range",
+			`<c:calendar-query ` + davNS + `><d:prop><d:getetag/><c:calendar-data/></d:prop>` +
+				`<c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT">` +
+				`<c:time-range start="20260301T000000Z" end="20260401T000000Z"/>` +
+				`</c:comp-filter></c:comp-filter></c:filter></c:calendar-query>`,
+			Report{
+				Name:  ReportCalendarQuery,
+				Props: []xml.Name{PropGetETag, PropCalendarData},
+				Start: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			"time-range open at the end",
+			`<c:calendar-query ` + davNS + `><c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT">` +
+				`<c:time-range start="20260301T120000Z"/></c:comp-filter></c:comp-filter></c:filter></c:calendar-query>`,
+			Report{
+				Name:  ReportCalendarQuery,
+				Start: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			"calendar-query without a filter or props",
+			`<c:calendar-query ` + davNS + `/>`,
+			Report{Name: ReportCalendarQuery},
+		},
+		{
+			"calendar-multiget",
+			`<c:calendar-multiget ` + davNS + `><d:prop><d:getetag/></d:prop>` +
+				`<d:href>/dav/calendars/1/a.ics</d:href><d:href>
+					/dav/calendars/1/b.ics
+				</d:href></c:calendar-multiget>`,
+			Report{
+				Name:  ReportCalendarMultiget,
+				Props: []xml.Name{PropGetETag},
+				Hrefs: []string{"/dav/calendars/1/a.ics", "/dav/calendars/1/b.ics"},
+			},
+		},
+		{
+			"unsupported reports are named",
+			`<d:sync-collection ` + davNS + `><d:sync-token/></d:sync-collection>`,
+			Report{Name: xml.Name{Space: NSDAV, Local: "sync-collection"}},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseReport(strings.NewReader(tt.body))
+		if err != nil {
+			t.Errorf("%s: error: %v", tt.name, err)
+			continue
+		}
+		if got.Name != tt.want.Name || !slices.Equal(got.Props, tt.want.Props) || !slices.Equal(got.Hrefs, tt.want.Hrefs) ||
+			!got.Start.Equal(tt.want.Start) || !got.End.Equal(tt.want.End) {
+			t.Errorf("%s: report = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseReportMalformed(t *testing.T) {
+	tests := map[string]string{
+		"empty body":       "",
+		"unclosed root":    `<c:calendar-query ` + davNS + `><d:prop><d:getetag/></d:prop>`,
+		"mismatched tags":  `<c:calendar-multiget ` + davNS + `><d:href>/a.ics</d:prop></c:calendar-multiget>`,
+		"local time range": `<c:calendar-query ` + davNS + `><c:time-range start="20260301T000000"/></c:calendar-query>`,
+		"date time range":  `<c:calendar-query ` + davNS + `><c:time-range end="20260301"/></c:calendar-query>`,
+		"not xml":          `calendar-query <`,
+	}
+	for name, body := range tests {
+		if _, err := ParseReport(strings.NewReader(body)); !errors.Is(err, ErrBadRequest) {
+			t.Errorf("%s: error = %v, want ErrBadRequest", name, err)
+		}
+	}
+}
+
+// multistatus is a parsed multistatus body.
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Status   string `xml:"status"`
+		Propstat []struct {
+			Prop struct {
+				Props []struct {
+					XMLName xml.Name
+					Inner   string `xml:",innerxml"`
+				} `xml:",any"`
+			} `xml:"prop"`
+			Status string `xml:"status"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+func TestWriteMultistatus(t *testing.T) {
+	custom := xml.Name{Space: "urn:example", Local: "flavor"}
+	tests := []struct {
+		name      string
+		response  PropResponse
+		status    string
+		propstats map[string][]xml.Name
+	}{
+		{
+			name: "found properties",
+			response: PropResponse{Href: "/dav/calendars/1/", Props: []Property{
+				TextProperty(PropDisplayName, "Family & friends"),
+				HrefProperty(PropCalendarHomeSet, "/dav/calendars/"),
+			}},
+			propstats: map[string][]xml.Name{"HTTP/1.1 200 OK": {PropDisplayName, PropCalendarHomeSet}},
+		},
+		{
+			name: "unknown properties get a 404 propstat",
+			response: PropResponse{
+				Href:     "/dav/calendars/1/",
+				Props:    []Property{TextProperty(PropGetETag, `"abc"`)},
+				NotFound: []xml.Name{PropCalendarColor, custom},
+			},
+			propstats: map[string][]xml.Name{
+				"HTTP/1.1 200 OK":        {PropGetETag},
+				"HTTP/1.1 404 Not Found": {PropCalendarColor, custom},
+			},
+		},
+		{
+			name:      "only unknown properties",
+			response:  PropResponse{Href: "/dav/", NotFound: []xml.Name{custom}},
+			propstats: map[string][]xml.Name{"HTTP/1.1 404 Not Found": {custom}},
+		},
+		{
+			name:      "no properties",
+			response:  PropResponse{Href: "/dav/"},
+			propstats: map[string][]xml.Name{"HTTP/1.1 200 OK": nil},
+		},
+		{
+			name:     "missing resource",
+			response: PropResponse{Href: "/dav/calendars/1/gone.ics", Status: http.StatusNotFound},
+			status:   "HTTP/1.1 404 Not Found",
+		},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		WriteMultistatus(rec, []PropResponse{tt.response})
+		if rec.Code != http.StatusMultiStatus {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, http.StatusMultiStatus)
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/xml") {
+			t.Errorf("%s: content type = %q", tt.name, ct)
+		}
+
+		var ms multistatus
+		if err := xml.Unmarshal(rec.Body.Bytes(), &ms); err != nil {
+			t.Errorf("%s: response is not well-formed: %v\n%s", tt.name, err, rec.Body.String())
+			continue
+		}
+		if len(ms.Responses) != 1 {
+			t.Errorf("%s: %d responses, want 1", tt.name, len(ms.Responses))
+			continue
+		}
+		resp := ms.Responses[0]
+		if resp.Href != tt.response.Href || resp.Status != tt.status {
+			t.Errorf("%s: href %q status %q, want %q %q", tt.name, resp.Href, resp.Status, tt.response.Href, tt.status)
+		}
+		got := make(map[string][]xml.Name)
+		for _, ps := range resp.Propstat {
+			names := []xml.Name{}
+			for _, p := range ps.Prop.Props {
+				names = append(names, p.XMLName)
+			}
+			got[ps.Status] = names
+		}
+		if len(got) != len(tt.propstats) {
+			t.Errorf("%s: propstats = %v, want %v", tt.name, got, tt.propstats)
+			continue
+		}
+		for status, want := range tt.propstats {
+			if names, ok := got[status]; !ok || len(names) != len(want) || (len(want) > 0 && !slices.Equal(names, want)) {
+				t.Errorf("%s: %s props = %v, want %v", tt.name, status, names, want)
+			}
+		}
+	}
+}
+
+func TestWriteMultistatusEscapesValues(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteMultistatus(rec, []PropResponse{{
+		Href:  "/dav/calendars/1/a&b.ics",
+		Props: []Property{TextProperty(PropDisplayName, "<Mom & Dad>")},
+	}})
+	body, _ := io.ReadAll(rec.Body)
+
+	var ms multistatus
+	if err := xml.Unmarshal(body, &ms); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, body)
+	}
+	if got := ms.Responses[0].Href; got != "/dav/calendars/1/a&b.ics" {
+		t.Errorf("href = %q", got)
+	}
+	if got := ms.Responses[0].Propstat[0].Prop.Props[0].Inner; got != "&lt;Mom &amp; Dad&gt;" {
+		t.Errorf("displayname = %q, want escaped text", got)
+	}
+}
@@ -0,0 +1,126 @@
+package calendar
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+// ConflictKind says why two occurrences conflict.
+type ConflictKind string
+
+const (
+	// ConflictMember is a family member with two overlapping events.
+	ConflictMember ConflictKind = "member"
+	// ConflictDriver is two overlapping events at different places that
+	// both have a driver, so the household needs two drivers at once.
+	ConflictDriver ConflictKind = "driver"
+)
+
+// Conflict is a pair of overlapping timed occurrences. Start and End bound
+// the overlap.
+type Conflict struct {
+	Kind           ConflictKind  `json:"kind"`
+	FamilyMemberID *int64        `json:"family_member_id,omitempty"`
+	Start          time.Time     `json:"start"`
+	End            time.Time     `json:"end"`
+	Events         [2]Occurrence `json:"events"`
+}
+
+// Conflicts expands the household's events in [rangeStart, rangeEnd) and
+// returns the conflicts between them. Driver conflicts are only reported
+// if drivers is set.
+func (s *Service) Conflicts(householdID int64, rangeStart, rangeEnd time.Time, drivers bool) ([]Conflict, error) {
+	occs, err := s.Expand(householdID, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, err
+	}
+	return FindConflicts(occs, drivers), nil
+}
+
+// FindConflicts returns the pairs of occurrences that overlap for the same
+// family member, ordered by when the overlap starts. If drivers is set,
+// overlapping occurrences at different locations that both have an
+// attendee in the driver role are also reported, whoever the drivers are.
+// All-day events never conflict, and optional attendees are not counted.
+func FindConflicts(occs []Occurrence, drivers bool) []Conflict {
+	var timed []Occurrence
+	for _, o := range occs {
+		if !o.AllDay && o.EndTime.After(o.StartTime) {
+			timed = append(timed, o)
+		}
+	}
+	sort.SliceStable(timed, func(i, j int) bool {
+		return timed[i].StartTime.Before(timed[j].StartTime)
+	})
+
+	var conflicts []Conflict
+	for i, a := range timed {
+		for _, b := range timed[i+1:] {
+			if !b.StartTime.Before(a.EndTime) {
+				break
+			}
+			c := Conflict{Start: b.StartTime, End: a.EndTime, Events: [2]Occurrence{a, b}}
+			if b.EndTime.Before(c.End) {
+				c.End = b.EndTime
+			}
+			switch shared := sharedMember(a, b); {
+			case shared != nil:
+				c.Kind = ConflictMember
+				c.FamilyMemberID = shared
+			case drivers && hasDriver(a) && hasDriver(b) && differentPlaces(a.Location, b.Location):
+				c.Kind = ConflictDriver
+			default:
+				continue
+			}
+			conflicts = append(conflicts, c)
+		}
+	}
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		return conflicts[i].Start.Before(conflicts[j].Start)
+	})
+	return conflicts
+}
+
+// ConflictingKeys returns the keys of the occurrences involved in conflicts.
+func ConflictingKeys(conflicts []Conflict) map[string]bool {
+	keys := make(map[string]bool)
+	for _, c := range conflicts {
+		keys[c.Events[0].Key()] = true
+		keys[c.Events[1].Key()] = true
+	}
+	return keys
+}
+
+// sharedMember returns a family member who is needed at both a and b.
+func sharedMember(a, b Occurrence) *int64 {
+	for _, x := range a.Attendees {
+		if x.Role == model.RoleOptional {
+			continue
+		}
+		for _, y := range b.Attendees {
+			if y.FamilyMemberID == x.FamilyMemberID && y.Role != model.RoleOptional {
+				id := x.FamilyMemberID
+				return &id
+			}
+		}
+	}
+	return nil
+}
+
+// hasDriver reports whether someone is driving to o.
+func hasDriver(o Occurrence) bool {
+	for _, a := range o.Attendees {
+		if a.Role == model.RoleDriver {
+			return true
+		}
+	}
+	return false
+}
+
+func differentPlaces(a, b string) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	return a != "" && b != "" && !strings.EqualFold(a, b)
+}
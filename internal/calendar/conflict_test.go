@@ -0,0 +1,141 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+func occ(id int64, title string, member *int64, start, end int, location string) Occurrence {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	o := Occurrence{CalendarEvent: model.CalendarEvent{
+		ID:             id,
+		Title:          title,
+		StartTime:      day.Add(time.Duration(start) * time.Minute),
+		EndTime:        day.Add(time.Duration(end) * time.Minute),
+		FamilyMemberID: member,
+		Location:       location,
+	}}
+	if member != nil {
+		o.Attendees = []model.Attendee{{FamilyMemberID: *member, Role: model.RoleAttendee}}
+	}
+	return o
+}
+
+func TestFindConflictsSameMember(t *testing.T) {
+	alice, bob := int64(1), int64(2)
+	occs := []Occurrence{
+		occ(1, "Soccer", &alice, 17*60, 18*60, "Field"),
+		occ(2, "Piano", &alice, 17*60+30, 18*60+30, "Studio"),
+		occ(3, "Swim", &bob, 17*60, 18*60, "Pool"),
+		occ(4, "Dinner", &alice, 18*60, 19*60, ""), // starts as Soccer ends
+	}
+
+	conflicts := FindConflicts(occs, false)
+	if len(conflicts) != 2 {
+		t.Fatalf("got %d conflicts, want 2: %+v", len(conflicts), conflicts)
+	}
+	c := conflicts[0]
+	if c.Kind != ConflictMember || *c.FamilyMemberID != alice {
+		t.Errorf("conflict = %+v, want Alice's", c)
+	}
+	if c.Events[0].Title != "Soccer" || c.Events[1].Title != "Piano" {
+		t.Errorf("events = %q, %q", c.Events[0].Title, c.Events[1].Title)
+	}
+	if c.Start.Hour() != 17 || c.Start.Minute() != 30 || c.End.Hour() != 18 || c.End.Minute() != 0 {
+		t.Errorf("overlap = %v-%v, want 17:30-18:00", c.Start, c.End)
+	}
+	if c := conflicts[1]; c.Events[0].Title != "Piano" || c.Events[1].Title != "Dinner" {
+		t.Errorf("second conflict = %q, %q; want Piano and Dinner", c.Events[0].Title, c.Events[1].Title)
+	}
+}
+
+func TestFindConflictsAttendeeRoles(t *testing.T) {
+	alice, bob, dad := int64(1), int64(2), int64(3)
+	soccer := occ(1, "Soccer", &alice, 17*60, 18*60, "Field")
+	soccer.Attendees = append(soccer.Attendees, model.Attendee{FamilyMemberID: dad, Role: model.RoleDriver})
+	swim := occ(2, "Swim", &bob, 17*60+15, 18*60, "Pool")
+	swim.Attendees = append(swim.Attendees, model.Attendee{FamilyMemberID: dad, Role: model.RoleDriver})
+	party := occ(3, "Party", &bob, 17*60+30, 19*60, "")
+	party.Attendees = append(party.Attendees, model.Attendee{FamilyMemberID: alice, Role: model.RoleOptional})
+
+	conflicts := FindConflicts([]Occurrence{soccer, swim, party}, false)
+	if len(conflicts) != 2 {
+		t.Fatalf("got %d conflicts, want 2: %+v", len(conflicts), conflicts)
+	}
+	if c := conflicts[0]; *c.FamilyMemberID != dad || c.Events[1].Title != "Swim" {
+		t.Errorf("first conflict = %+v, want Dad driving to Soccer and Swim", c)
+	}
+	if c := conflicts[1]; *c.FamilyMemberID != bob || c.Events[1].Title != "Party" {
+		t.Errorf("second conflict = %+v, want Bob at Swim and Party", c)
+	}
+}
+
+func TestFindConflictsIgnoresAllDayAndUnassigned(t *testing.T) {
+	alice := int64(1)
+	birthday := occ(1, "Birthday", &alice, 0, 24*60, "")
+	birthday.AllDay = true
+	occs := []Occurrence{
+		birthday,
+		occ(2, "Dentist", &alice, 9*60, 10*60, ""),
+		occ(3, "Plumber", nil, 9*60, 10*60, ""),
+		occ(4, "Garbage day", nil, 9*60, 10*60, ""),
+	}
+	if conflicts := FindConflicts(occs, false); len(conflicts) != 0 {
+		t.Errorf("got %+v, want no conflicts", conflicts)
+	}
+}
+
+func TestFindConflictsDrivers(t *testing.T) {
+	alice, bob, mom, dad := int64(1), int64(2), int64(3), int64(4)
+	driven := func(o Occurrence, driver int64) Occurrence {
+		o.Attendees = append(o.Attendees, model.Attendee{FamilyMemberID: driver, Role: model.RoleDriver})
+		return o
+	}
+	occs := []Occurrence{
+		driven(occ(1, "Soccer", &alice, 17*60, 18*60, "Field"), mom),
+		driven(occ(2, "Swim", &bob, 17*60+15, 18*60, "Pool"), dad),
+		driven(occ(3, "Karate", &bob, 19*60, 20*60, "Dojo"), mom),
+		driven(occ(4, "Tutor", &alice, 19*60, 20*60, " dojo "), dad),
+		// Somewhere else, but nobody needs driving.
+		occ(5, "Library", &alice, 21*60, 22*60, "Library"),
+		driven(occ(6, "Band", &bob, 21*60, 22*60, "School"), mom),
+	}
+
+	if conflicts := FindConflicts(occs, false); len(conflicts) != 0 {
+		t.Errorf("without drivers got %+v, want none", conflicts)
+	}
+	conflicts := FindConflicts(occs, true)
+	if len(conflicts) != 1 {
+		t.Fatalf("got %d conflicts, want 1: %+v", len(conflicts), conflicts)
+	}
+	if c := conflicts[0]; c.Kind != ConflictDriver || c.FamilyMemberID != nil || c.Events[1].Title != "Swim" {
+		t.Errorf("conflict = %+v, want a driver conflict for Soccer and Swim", c)
+	}
+}
+
+func TestFindConflictsDriversNeedDriverRole(t *testing.T) {
+	alice, bob := int64(1), int64(2)
+	occs := []Occurrence{
+		occ(1, "Soccer", &alice, 17*60, 18*60, "Field"),
+		occ(2, "Swim", &bob, 17*60+15, 18*60, "Pool"),
+		occ(3, "Dinner", nil, 17*60+30, 19*60, "Grandma's"),
+	}
+	if conflicts := FindConflicts(occs, true); len(conflicts) != 0 {
+		t.Errorf("got %+v, want no conflicts for events without drivers", conflicts)
+	}
+}
+
+func TestConflictingKeys(t *testing.T) {
+	alice := int64(1)
+	// Two virtual occurrences of one recurring event share its ID.
+	a := occ(1, "Practice", &alice, 9*60, 10*60, "")
+	b := occ(1, "Practice", &alice, 33*60, 34*60, "")
+	c := occ(2, "Appointment", &alice, 33*60+30, 35*60, "")
+
+	keys := ConflictingKeys(FindConflicts([]Occurrence{a, b, c}, false))
+	if keys[a.Key()] || !keys[b.Key()] || !keys[c.Key()] {
+		t.Errorf("keys = %v, want only the second practice and the appointment", keys)
+	}
+}
@@ -0,0 +1,138 @@
+// Package calendar works with a household's calendar as a whole: the
+// occurrences of its events within a date range, and the conflicts
+// between them.
+package calendar
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/recurrence"
+	"github.com/dukerupert/gamwich/internal/store"
+)
+
+// Occurrence is a flattened event: a one-off event, a virtual occurrence of
+// a recurring event, or an exception that replaces one.
+type Occurrence struct {
+	model.CalendarEvent
+	IsRecurring    bool   `json:"is_recurring"`
+	ParentID       int64  `json:"parent_id,omitempty"`
+	OccurrenceDate string `json:"occurrence_date"` // "2006-01-02" for identifying specific occurrences
+}
+
+// Key identifies the occurrence among others in a range. Virtual
+// occurrences share their parent's ID, so the start time is part of it.
+func (o Occurrence) Key() string {
+	return strconv.FormatInt(o.ID, 10) + "@" + o.StartTime.Format(time.RFC3339)
+}
+
+// MaxRange is the longest range the calendar is expanded over at once: a
+// month, which covers every calendar view.
+const MaxRange = 31 * 24 * time.Hour
+
+// Service expands a household's events, in the household's timezone.
+type Service struct {
+	events   *store.EventStore
+	settings *store.SettingsStore
+	logger   *slog.Logger
+}
+
+func NewService(es *store.EventStore, ss *store.SettingsStore, logger *slog.Logger) *Service {
+	return &Service{events: es, settings: ss, logger: logger}
+}
+
+// Expand returns both non-recurring and expanded recurring events for a
+// date range, all-day events first and then by start time. Cancelled
+// occurrences are left out.
+func (s *Service) Expand(householdID int64, rangeStart, rangeEnd time.Time) ([]Occurrence, error) {
+	// 1. Get non-recurring events
+	nonRecurring, err := s.events.ListByDateRange(householdID, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("list events: %w", err)
+	}
+
+	var results []Occurrence
+	for _, e := range nonRecurring {
+		results = append(results, Occurrence{
+			CalendarEvent:  e,
+			OccurrenceDate: e.StartTime.Format("2006-01-02"),
+		})
+	}
+
+	// 2. Get recurring parent events
+	recurring, err := s.events.ListRecurring(householdID, rangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("list recurring: %w", err)
+	}
+
+	// 3. Expand each recurring event
+	loc, err := s.settings.Location(householdID)
+	if err != nil {
+		s.logger.Warn("household timezone", "household_id", householdID, "error", err)
+	}
+	for _, parent := range recurring {
+		rule, err := recurrence.Parse(parent.RecurrenceRule)
+		if err != nil {
+			s.logger.Error("skip recurring event", "event_id", parent.ID, "rule", parent.RecurrenceRule, "error", err)
+			continue
+		}
+
+		occurrences := recurrence.Set{Rule: rule, IncludeStart: parent.Imported()}.ExpandIn(parent.StartTime, parent.EndTime, rangeStart, rangeEnd, loc)
+
+		// Get exceptions for this parent
+		exceptions, err := s.events.ListExceptions(parent.ID, householdID)
+		if err != nil {
+			return nil, fmt.Errorf("list exceptions for %d: %w", parent.ID, err)
+		}
+
+		// Build exception lookup by original start time
+		excMap := make(map[string]model.CalendarEvent)
+		for _, exc := range exceptions {
+			if exc.OriginalStartTime != nil {
+				key := exc.OriginalStartTime.Format("2006-01-02T15:04:05Z")
+				excMap[key] = exc
+			}
+		}
+
+		for _, occ := range occurrences {
+			key := occ.Start.Format("2006-01-02T15:04:05Z")
+			if exc, found := excMap[key]; found {
+				if exc.Cancelled {
+					continue // skip cancelled occurrence
+				}
+				// Use exception's data
+				results = append(results, Occurrence{
+					CalendarEvent:  exc,
+					IsRecurring:    true,
+					ParentID:       parent.ID,
+					OccurrenceDate: occ.Start.Format("2006-01-02"),
+				})
+			} else {
+				// Virtual occurrence from parent data
+				virtual := parent
+				virtual.StartTime = occ.Start
+				virtual.EndTime = occ.End
+				results = append(results, Occurrence{
+					CalendarEvent:  virtual,
+					IsRecurring:    true,
+					ParentID:       parent.ID,
+					OccurrenceDate: occ.Start.Format("2006-01-02"),
+				})
+			}
+		}
+	}
+
+	// Sort: all-day first, then by start time
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].AllDay != results[j].AllDay {
+			return results[i].AllDay
+		}
+		return results[i].StartTime.Before(results[j].StartTime)
+	})
+
+	return results, nil
+}
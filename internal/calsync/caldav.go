@@ -0,0 +1,467 @@
+package calsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/caldav"
+	"github.com/dukerupert/gamwich/internal/ical"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/store"
+)
+
+// CalDAVRefreshInterval is how often each CalDAV collection is synced when
+// nothing changes locally.
+const CalDAVRefreshInterval = 15 * time.Minute
+
+// SyncResult counts the changes made by a CalDAV sync. Conflicts counts
+// objects changed on both sides since the last sync.
+type SyncResult struct {
+	Pulled    int `json:"pulled"`
+	Pushed    int `json:"pushed"`
+	Conflicts int `json:"conflicts"`
+}
+
+// CalDAVSyncer keeps household events in two-way sync with CalDAV
+// collections. Each event series maps to one calendar object resource.
+//
+// Changes are detected with the collection's ctag and each object's ETag
+// on the remote side, and with the dirty flag that calendar_events triggers
+// set on the local side. When both sides changed, the remote copy wins
+// unless the local series was modified strictly after the remote object's
+// LAST-MODIFIED (or DTSTAMP); an edit on either side wins over a delete.
+type CalDAVSyncer struct {
+	mu       sync.Mutex
+	store    *store.CalDAVStore
+	events   *store.EventStore
+	settings *store.SettingsStore
+	logger   *slog.Logger
+	notify   func(householdID int64)
+}
+
+// NewCalDAVSyncer creates a syncer. Times are read into each household's
+// timezone. notify, if set, is called after a sync changes a household's
+// events.
+func NewCalDAVSyncer(cs *store.CalDAVStore, es *store.EventStore, settings *store.SettingsStore, notify func(householdID int64), logger *slog.Logger) *CalDAVSyncer {
+	return &CalDAVSyncer{
+		store:    cs,
+		events:   es,
+		settings: settings,
+		logger:   logger,
+		notify:   notify,
+	}
+}
+
+// NormalizeCalDAVURL validates a collection URL.
+func NormalizeCalDAVURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid CalDAV URL")
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+	default:
+		return "", fmt.Errorf("CalDAV URL must use http or https")
+	}
+	return raw, nil
+}
+
+// SyncCollection reconciles a collection with the household's events and
+// records the outcome on the collection.
+func (s *CalDAVSyncer) SyncCollection(ctx context.Context, coll model.CalDAVCollection) (SyncResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := &collectionSync{
+		s:      s,
+		coll:   coll,
+		client: caldav.NewClient(coll.Username, coll.Password),
+		url:    caldav.CollectionURL(coll.URL),
+		loc:    householdLocation(s.settings, coll.HouseholdID, s.logger),
+	}
+	ctag, err := cs.run(ctx)
+
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+		ctag = ""
+	}
+	if recErr := s.store.RecordSync(coll.ID, ctag, time.Now(), errMsg); recErr != nil {
+		s.logger.Error("record caldav sync", "collection_id", coll.ID, "error", recErr)
+	}
+	if cs.res.Pulled > 0 && s.notify != nil {
+		s.notify(coll.HouseholdID)
+	}
+	return cs.res, err
+}
+
+// SyncHousehold syncs every collection of a household, logging failures.
+func (s *CalDAVSyncer) SyncHousehold(ctx context.Context, householdID int64) {
+	collections, err := s.store.ListCollections(householdID)
+	if err != nil {
+		s.logger.Error("list caldav collections", "household_id", householdID, "error", err)
+		return
+	}
+	for _, coll := range collections {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := s.SyncCollection(ctx, coll); err != nil {
+			s.logger.Warn("sync caldav collection", "collection_id", coll.ID, "error", err)
+		}
+	}
+}
+
+// collectionSync holds the state of one sync run.
+type collectionSync struct {
+	s      *CalDAVSyncer
+	coll   model.CalDAVCollection
+	client *caldav.Client
+	url    string
+	loc    *time.Location
+	res    SyncResult
+}
+
+// pull is a remote object to fetch and apply, with the local state it
+// replaces. conflict is set when the local series also changed.
+type pull struct {
+	object   *model.CalDAVObject
+	event    *model.CalendarEvent
+	conflict bool
+}
+
+// run performs the sync and returns the ctag to record, which is empty if
+// the collection must be listed again next time.
+func (c *collectionSync) run(ctx context.Context) (string, error) {
+	objects, err := c.s.store.ListObjects(c.coll.ID)
+	if err != nil {
+		return "", err
+	}
+	ctag, err := c.client.CTag(ctx, c.url)
+	if err != nil {
+		return "", err
+	}
+
+	// remote maps each remote href to its current ETag. An unchanged ctag
+	// means the objects are as they were after the last sync.
+	remote := make(map[string]string)
+	if ctag != "" && ctag == c.coll.CTag {
+		for _, o := range objects {
+			remote[o.Href] = o.ETag
+		}
+	} else {
+		listed, err := c.client.List(ctx, c.url)
+		if err != nil {
+			return "", err
+		}
+		for _, o := range listed {
+			remote[o.Href] = o.ETag
+		}
+	}
+
+	pulls := make(map[string]pull)
+	known := make(map[string]bool, len(objects))
+	for i := range objects {
+		o := &objects[i]
+		known[o.Href] = true
+		etag, exists := remote[o.Href]
+		remoteChanged := exists && etag != o.ETag
+
+		event, err := c.localEvent(o)
+		if err != nil {
+			return "", err
+		}
+
+		switch {
+		case event == nil || !c.belongs(event):
+			// Deleted locally, or reassigned to a member this collection
+			// does not sync. A remote edit wins over either.
+			switch {
+			case remoteChanged:
+				pulls[o.Href] = pull{object: o, event: event, conflict: true}
+			case !exists:
+				err = c.s.store.DeleteObject(o.ID)
+			default:
+				err = c.deleteRemote(ctx, o)
+			}
+		case o.Dirty:
+			switch {
+			case remoteChanged:
+				pulls[o.Href] = pull{object: o, event: event, conflict: true}
+			case !exists:
+				// Deleted remotely but edited locally: recreate it.
+				err = c.push(ctx, event, o.Href, o.UID, "")
+			default:
+				err = c.push(ctx, event, o.Href, o.UID, o.ETag)
+			}
+		default:
+			switch {
+			case remoteChanged:
+				pulls[o.Href] = pull{object: o, event: event}
+			case !exists:
+				err = c.deleteLocal(o, event)
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	for href := range remote {
+		if !known[href] {
+			pulls[href] = pull{}
+		}
+	}
+
+	if err := c.pullAll(ctx, pulls); err != nil {
+		return "", err
+	}
+
+	unsynced, err := c.s.store.ListUnsyncedEvents(c.coll.HouseholdID, c.coll.FamilyMemberID)
+	if err != nil {
+		return "", err
+	}
+	for i := range unsynced {
+		e := &unsynced[i]
+		uid := ical.UID(e.ID)
+		if err := c.push(ctx, e, caldav.ObjectURL(c.url, uid), uid, ""); err != nil {
+			return "", err
+		}
+	}
+
+	// Our own writes changed the ctag, so list again next time.
+	if c.res.Pushed > 0 {
+		ctag = ""
+	}
+	return ctag, nil
+}
+
+// localEvent returns the series an object is linked to, or nil if it was
+// deleted.
+func (c *collectionSync) localEvent(o *model.CalDAVObject) (*model.CalendarEvent, error) {
+	if o.EventID == nil {
+		return nil, nil
+	}
+	return c.s.events.GetByID(*o.EventID, c.coll.HouseholdID)
+}
+
+// belongs reports whether a local event is synced by this collection.
+func (c *collectionSync) belongs(e *model.CalendarEvent) bool {
+	return !e.Imported() && sameID(e.FamilyMemberID, c.coll.FamilyMemberID)
+}
+
+func (c *collectionSync) pullAll(ctx context.Context, pulls map[string]pull) error {
+	if len(pulls) == 0 {
+		return nil
+	}
+	hrefs := make([]string, 0, len(pulls))
+	for href := range pulls {
+		hrefs = append(hrefs, href)
+	}
+	fetched, err := c.client.Multiget(ctx, c.url, hrefs)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range fetched {
+		p, ok := pulls[obj.Href]
+		if !ok {
+			continue
+		}
+		if p.conflict {
+			c.res.Conflicts++
+			if p.event != nil && c.belongs(p.event) {
+				localWins, err := c.localIsNewer(p.event, obj)
+				if err != nil {
+					return err
+				}
+				if localWins {
+					if err := c.push(ctx, p.event, obj.Href, p.object.UID, obj.ETag); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+		}
+		if err := c.apply(obj, p.object, p.event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localIsNewer reports whether the local series was modified after the
+// remote object.
+func (c *collectionSync) localIsNewer(e *model.CalendarEvent, obj caldav.Object) (bool, error) {
+	localAt, err := c.s.events.SeriesUpdatedAt(e.ID, c.coll.HouseholdID)
+	if err != nil {
+		return false, err
+	}
+	var remoteAt time.Time
+	parsed, err := ical.Parse(strings.NewReader(obj.Data), c.loc)
+	if err == nil {
+		for _, pe := range parsed {
+			if pe.LastModified.After(remoteAt) {
+				remoteAt = pe.LastModified
+			}
+		}
+	}
+	return localAt.After(remoteAt), nil
+}
+
+// apply writes a remote object to the local calendar, replacing the linked
+// series if there is one. Events take the collection's family member.
+func (c *collectionSync) apply(obj caldav.Object, o *model.CalDAVObject, local *model.CalendarEvent) error {
+	parsed, err := ical.Parse(strings.NewReader(obj.Data), c.loc)
+	if err != nil {
+		c.s.logger.Warn("skip unreadable caldav object", "href", obj.Href, "error", err)
+		return nil
+	}
+
+	master, overrides := SplitObject(parsed)
+	if master == nil || master.Cancelled {
+		if o == nil {
+			return nil
+		}
+		return c.deleteLocal(o, local)
+	}
+
+	rule := importableRule(c.s.logger, master.UID, master.RRule)
+	event, err := SaveSeries(c.s.events, c.coll.HouseholdID, local, c.coll.FamilyMemberID, *master, overrides, rule)
+	if err != nil {
+		return err
+	}
+
+	if err := c.s.store.SaveObject(c.coll.ID, event.ID, obj.Href, master.UID, obj.ETag); err != nil {
+		return err
+	}
+	c.res.Pulled++
+	return nil
+}
+
+// push writes a local series to href. An empty etag creates the object.
+// If the remote object changed in the meantime the series stays dirty and
+// is reconciled on the next sync.
+func (c *collectionSync) push(ctx context.Context, e *model.CalendarEvent, href, uid, etag string) error {
+	exceptions, err := c.s.events.ListExceptions(e.ID, c.coll.HouseholdID)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := ical.EncodeObject(&buf, uid, *e, exceptions, c.loc, time.Now()); err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	newETag, err := c.client.Put(ctx, href, buf.String(), etag)
+	if errors.Is(err, caldav.ErrPreconditionFailed) {
+		c.s.logger.Info("caldav object changed during sync, retrying later", "href", href)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if newETag == "" {
+		// Some servers omit the ETag when they rewrite the data on PUT.
+		fetched, err := c.client.Multiget(ctx, c.url, []string{href})
+		if err != nil {
+			return err
+		}
+		if len(fetched) > 0 {
+			newETag = fetched[0].ETag
+		}
+	}
+
+	if err := c.s.store.SaveObject(c.coll.ID, e.ID, href, uid, newETag); err != nil {
+		return err
+	}
+	c.res.Pushed++
+	return nil
+}
+
+func (c *collectionSync) deleteRemote(ctx context.Context, o *model.CalDAVObject) error {
+	err := c.client.Delete(ctx, o.Href, o.ETag)
+	if errors.Is(err, caldav.ErrPreconditionFailed) {
+		c.s.logger.Info("caldav object changed during sync, retrying later", "href", o.Href)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.s.store.DeleteObject(o.ID); err != nil {
+		return err
+	}
+	c.res.Pushed++
+	return nil
+}
+
+func (c *collectionSync) deleteLocal(o *model.CalDAVObject, local *model.CalendarEvent) error {
+	if local != nil {
+		if err := c.s.events.Delete(local.ID, c.coll.HouseholdID); err != nil {
+			return err
+		}
+		c.res.Pulled++
+	}
+	return c.s.store.DeleteObject(o.ID)
+}
+
+// SplitObject separates the events of a calendar object resource into the
+// series master and its RECURRENCE-ID overrides. An object with only
+// overrides has no series to attach them to, so its first override is
+// returned as a standalone master. master is nil for an empty object.
+func SplitObject(events []ical.Event) (master *ical.Event, overrides []ical.Event) {
+	for i := range events {
+		switch {
+		case events[i].RecurrenceID != nil:
+			overrides = append(overrides, events[i])
+		case master == nil:
+			master = &events[i]
+		}
+	}
+	if master == nil && len(overrides) > 0 {
+		standalone := overrides[0]
+		standalone.RecurrenceID = nil
+		master, overrides = &standalone, nil
+	}
+	return master, overrides
+}
+
+// SaveSeries writes a calendar object's master and overrides as a local
+// event series assigned to familyMemberID, replacing existing and its
+// exceptions when existing is not nil. rule is the master's recurrence rule
+// in the form the recurrence package expands; overrides are dropped when it
+// is empty.
+func SaveSeries(es *store.EventStore, householdID int64, existing *model.CalendarEvent, familyMemberID *int64, master ical.Event, overrides []ical.Event, rule string) (*model.CalendarEvent, error) {
+	e := toModel(master, nil)
+
+	var event *model.CalendarEvent
+	var err error
+	if existing != nil {
+		event, err = es.UpdateWithRecurrence(existing.ID, householdID, e.Title, e.Description, e.StartTime, e.EndTime, e.AllDay, familyMemberID, e.Location, rule)
+		if err == nil {
+			err = es.DeleteExceptions(existing.ID, householdID)
+		}
+	} else {
+		event, err = es.CreateWithRecurrence(householdID, e.Title, e.Description, e.StartTime, e.EndTime, e.AllDay, familyMemberID, e.Location, rule)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if rule != "" {
+		for _, exc := range seriesExceptions(master, overrides) {
+			_, err := es.CreateException(householdID, event.ID, *exc.OriginalStartTime, exc.Title, exc.Description,
+				exc.StartTime, exc.EndTime, exc.AllDay, familyMemberID, exc.Location, exc.Cancelled)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return event, nil
+}
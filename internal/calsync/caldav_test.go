@@ -0,0 +1,404 @@
+package calsync
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/caldav"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/store"
+)
+
+// davServer is a minimal in-memory CalDAV collection at /cal/.
+type davServer struct {
+	mu      sync.Mutex
+	objects map[string]davObject
+	ctag    int
+	seq     int
+	writes  int
+}
+
+type davObject struct {
+	etag string
+	data string
+}
+
+func newDAVServer(t *testing.T) (*davServer, string) {
+	t.Helper()
+	d := &davServer{objects: make(map[string]davObject)}
+	srv := httptest.NewServer(d)
+	t.Cleanup(srv.Close)
+	return d, srv.URL + "/cal/"
+}
+
+// set stores an object as another client would.
+func (d *davServer) set(path string, lines ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.store(path, calendarObject(lines...))
+}
+
+func (d *davServer) remove(path string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.objects, path)
+	d.ctag++
+}
+
+func (d *davServer) get(path string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	o, ok := d.objects[path]
+	return o.data, ok
+}
+
+func (d *davServer) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.objects)
+}
+
+func (d *davServer) store(path, data string) string {
+	d.seq++
+	d.ctag++
+	etag := fmt.Sprintf(`"%d"`, d.seq)
+	d.objects[path] = davObject{etag: etag, data: data}
+	return etag
+}
+
+func (d *davServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch r.Method {
+	case "PROPFIND":
+		d.multistatus(w, []caldav.Response{okResponse(r.URL.Path, caldav.Prop{
+			CTag:         fmt.Sprint(d.ctag),
+			ResourceType: caldav.ResourceType{Collection: &struct{}{}},
+		})})
+
+	case "REPORT":
+		body, _ := io.ReadAll(r.Body)
+		var responses []caldav.Response
+		if strings.Contains(string(body), "calendar-multiget") {
+			var req struct {
+				Hrefs []string `xml:"DAV: href"`
+			}
+			xml.Unmarshal(body, &req)
+			for _, href := range req.Hrefs {
+				if o, ok := d.objects[href]; ok {
+					responses = append(responses, okResponse(href, caldav.Prop{ETag: o.etag, CalendarData: o.data}))
+				}
+			}
+		} else {
+			for path, o := range d.objects {
+				responses = append(responses, okResponse(path, caldav.Prop{ETag: o.etag}))
+			}
+		}
+		d.multistatus(w, responses)
+
+	case http.MethodPut:
+		o, exists := d.objects[r.URL.Path]
+		if (r.Header.Get("If-None-Match") == "*" && exists) ||
+			(r.Header.Get("If-Match") != "" && (!exists || r.Header.Get("If-Match") != o.etag)) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		d.writes++
+		w.Header().Set("ETag", d.store(r.URL.Path, string(body)))
+		w.WriteHeader(http.StatusCreated)
+
+	case http.MethodDelete:
+		o, exists := d.objects[r.URL.Path]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if m := r.Header.Get("If-Match"); m != "" && m != o.etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		d.writes++
+		delete(d.objects, r.URL.Path)
+		d.ctag++
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (d *davServer) multistatus(w http.ResponseWriter, responses []caldav.Response) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	xml.NewEncoder(w).Encode(caldav.Multistatus{Responses: responses})
+}
+
+func okResponse(href string, prop caldav.Prop) caldav.Response {
+	return caldav.Response{Href: href, Propstats: []caldav.Propstat{{Prop: prop, Status: "HTTP/1.1 200 OK"}}}
+}
+
+func calendarObject(lines ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+}
+
+func (f *fixture) collection(t *testing.T, url string, memberID *int64) *model.CalDAVCollection {
+	t.Helper()
+	coll, err := f.caldav.CreateCollection(householdID, "Family", url, "user", "secret", memberID)
+	if err != nil {
+		t.Fatalf("create collection: %v", err)
+	}
+	return coll
+}
+
+func (f *fixture) syncCollection(t *testing.T, coll *model.CalDAVCollection) SyncResult {
+	t.Helper()
+	// Reload so the stored ctag from the previous sync is used.
+	current, err := f.caldav.GetCollection(coll.ID, householdID)
+	if err != nil || current == nil {
+		t.Fatalf("get collection: %v", err)
+	}
+	res, err := f.syncer.SyncCollection(context.Background(), *current)
+	if err != nil {
+		t.Fatalf("sync collection: %v", err)
+	}
+	return res
+}
+
+func (f *fixture) linkedEvent(t *testing.T, collID int64, href string) *model.CalendarEvent {
+	t.Helper()
+	objects, err := f.caldav.ListObjects(collID)
+	if err != nil {
+		t.Fatalf("list objects: %v", err)
+	}
+	for _, o := range objects {
+		if strings.HasSuffix(o.Href, href) && o.EventID != nil {
+			e, err := f.events.GetByID(*o.EventID, householdID)
+			if err != nil {
+				t.Fatalf("get event: %v", err)
+			}
+			return e
+		}
+	}
+	return nil
+}
+
+func TestCalDAVPullsRemoteEvents(t *testing.T) {
+	f := setup(t)
+	dav, url := newDAVServer(t)
+	kid, _ := f.members.Create(householdID, "Sam", "#00FF00", "S")
+	coll := f.collection(t, url, &kid.ID)
+
+	dav.set("/cal/practice.ics", concat(
+		vevent("UID:practice", "SUMMARY:Practice", "DTSTART:20260105T170000", "DTEND:20260105T180000",
+			"RRULE:FREQ=WEEKLY;COUNT=4", "EXDATE:20260112T170000"),
+		vevent("UID:practice", "RECURRENCE-ID:20260119T170000", "SUMMARY:Practice (away)",
+			"DTSTART:20260119T180000", "DTEND:20260119T190000"),
+	)...)
+
+	if res := f.syncCollection(t, coll); res.Pulled != 1 || res.Pushed != 0 {
+		t.Fatalf("first sync = %+v, want 1 pulled", res)
+	}
+
+	e := f.linkedEvent(t, coll.ID, "/cal/practice.ics")
+	if e == nil {
+		t.Fatal("expected a linked local event")
+	}
+	if e.Title != "Practice" || e.RecurrenceRule != "FREQ=WEEKLY;COUNT=4" {
+		t.Errorf("event = %q %q", e.Title, e.RecurrenceRule)
+	}
+	if e.FamilyMemberID == nil || *e.FamilyMemberID != kid.ID {
+		t.Errorf("family member = %v, want %d", e.FamilyMemberID, kid.ID)
+	}
+	if e.Imported() {
+		t.Error("synced events should be editable")
+	}
+	exceptions, _ := f.events.ListExceptions(e.ID, householdID)
+	if len(exceptions) != 2 {
+		t.Fatalf("exceptions = %d, want 2", len(exceptions))
+	}
+
+	res := f.syncCollection(t, coll)
+	if res != (SyncResult{}) {
+		t.Errorf("second sync = %+v, want no changes", res)
+	}
+	if dav.writes != 0 {
+		t.Errorf("remote writes = %d, want 0", dav.writes)
+	}
+}
+
+func TestCalDAVPushesLocalChanges(t *testing.T) {
+	f := setup(t)
+	dav, url := newDAVServer(t)
+	coll := f.collection(t, url, nil)
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	e, err := f.events.Create(householdID, "Dentist", "", start, start.Add(time.Hour), false, nil, "")
+	if err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+
+	if res := f.syncCollection(t, coll); res.Pushed != 1 {
+		t.Fatalf("sync = %+v, want 1 pushed", res)
+	}
+	path := fmt.Sprintf("/cal/event-%d@gamwich.ics", e.ID)
+	data, ok := dav.get(path)
+	if !ok || !strings.Contains(data, "SUMMARY:Dentist") {
+		t.Fatalf("remote object = %q", data)
+	}
+
+	if _, err := f.events.Update(e.ID, householdID, "Dentist (moved)", "", start, start.Add(time.Hour), false, nil, ""); err != nil {
+		t.Fatalf("update event: %v", err)
+	}
+	if res := f.syncCollection(t, coll); res.Pushed != 1 || res.Pulled != 0 {
+		t.Fatalf("sync after edit = %+v, want 1 pushed", res)
+	}
+	data, _ = dav.get(path)
+	if !strings.Contains(data, "SUMMARY:Dentist (moved)") {
+		t.Errorf("remote object not updated: %q", data)
+	}
+
+	// Our own writes must not come back as remote changes.
+	if res := f.syncCollection(t, coll); res != (SyncResult{}) {
+		t.Errorf("sync after push = %+v, want no changes", res)
+	}
+}
+
+func TestCalDAVDeletes(t *testing.T) {
+	f := setup(t)
+	dav, url := newDAVServer(t)
+	coll := f.collection(t, url, nil)
+
+	dav.set("/cal/a.ics", vevent("UID:a", "SUMMARY:A", "DTSTART:20260301T100000", "DTEND:20260301T110000")...)
+	dav.set("/cal/b.ics", vevent("UID:b", "SUMMARY:B", "DTSTART:20260302T100000", "DTEND:20260302T110000")...)
+	f.syncCollection(t, coll)
+
+	a := f.linkedEvent(t, coll.ID, "/cal/a.ics")
+	b := f.linkedEvent(t, coll.ID, "/cal/b.ics")
+	if a == nil || b == nil {
+		t.Fatal("expected both events to be pulled")
+	}
+
+	// Local delete removes the remote object.
+	if err := f.events.Delete(a.ID, householdID); err != nil {
+		t.Fatalf("delete event: %v", err)
+	}
+	// Remote delete removes the local event.
+	dav.remove("/cal/b.ics")
+
+	f.syncCollection(t, coll)
+	if _, ok := dav.get("/cal/a.ics"); ok {
+		t.Error("remote object for locally deleted event still exists")
+	}
+	if got, _ := f.events.GetByID(b.ID, householdID); got != nil {
+		t.Error("local event for remotely deleted object still exists")
+	}
+	if objects, _ := f.caldav.ListObjects(coll.ID); len(objects) != 0 {
+		t.Errorf("sync state rows = %d, want 0", len(objects))
+	}
+}
+
+func TestCalDAVConflicts(t *testing.T) {
+	f := setup(t)
+	dav, url := newDAVServer(t)
+	coll := f.collection(t, url, nil)
+
+	dav.set("/cal/old.ics", vevent("UID:old", "SUMMARY:Old", "DTSTART:20260301T100000", "DTEND:20260301T110000")...)
+	dav.set("/cal/new.ics", vevent("UID:new", "SUMMARY:New", "DTSTART:20260302T100000", "DTEND:20260302T110000")...)
+	f.syncCollection(t, coll)
+
+	older := f.linkedEvent(t, coll.ID, "/cal/old.ics")
+	newer := f.linkedEvent(t, coll.ID, "/cal/new.ics")
+	for _, e := range []*model.CalendarEvent{older, newer} {
+		if _, err := f.events.Update(e.ID, householdID, "Local edit", "", e.StartTime, e.EndTime, false, nil, ""); err != nil {
+			t.Fatalf("update event: %v", err)
+		}
+	}
+
+	// One remote edit predates the local edits, the other follows them.
+	dav.set("/cal/old.ics", vevent("UID:old", "SUMMARY:Remote edit", "LAST-MODIFIED:20200101T000000Z",
+		"DTSTART:20260301T100000", "DTEND:20260301T110000")...)
+	dav.set("/cal/new.ics", vevent("UID:new", "SUMMARY:Remote edit", "LAST-MODIFIED:20990101T000000Z",
+		"DTSTART:20260302T100000", "DTEND:20260302T110000")...)
+
+	res := f.syncCollection(t, coll)
+	if res.Conflicts != 2 {
+		t.Errorf("conflicts = %d, want 2", res.Conflicts)
+	}
+
+	data, _ := dav.get("/cal/old.ics")
+	if !strings.Contains(data, "SUMMARY:Local edit") {
+		t.Errorf("newer local edit should win, remote = %q", data)
+	}
+	if got, _ := f.events.GetByID(older.ID, householdID); got.Title != "Local edit" {
+		t.Errorf("local title = %q, want Local edit", got.Title)
+	}
+	if got, _ := f.events.GetByID(newer.ID, householdID); got.Title != "Remote edit" {
+		t.Errorf("newer remote edit should win, local title = %q", got.Title)
+	}
+
+	if res := f.syncCollection(t, coll); res != (SyncResult{}) {
+		t.Errorf("sync after conflicts = %+v, want no changes", res)
+	}
+}
+
+func TestCalDAVMemberMapping(t *testing.T) {
+	f := setup(t)
+	dav, url := newDAVServer(t)
+	kid, _ := f.members.Create(householdID, "Sam", "#00FF00", "S")
+	other, _ := f.members.Create(householdID, "Alex", "#FF0000", "A")
+	coll := f.collection(t, url, &kid.ID)
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	mine, _ := f.events.Create(householdID, "Soccer", "", start, start.Add(time.Hour), false, &kid.ID, "")
+	f.events.Create(householdID, "Book club", "", start, start.Add(time.Hour), false, &other.ID, "")
+	f.events.Create(householdID, "Bins", "", start, start.Add(time.Hour), false, nil, "")
+
+	f.syncCollection(t, coll)
+	if dav.count() != 1 {
+		t.Fatalf("remote objects = %d, want only the member's event", dav.count())
+	}
+
+	// Reassigning the event to someone else takes it out of the collection.
+	if err := f.events.SetFamilyMember(mine.ID, householdID, &other.ID); err != nil {
+		t.Fatalf("set family member: %v", err)
+	}
+	f.syncCollection(t, coll)
+	if dav.count() != 0 {
+		t.Errorf("remote objects = %d, want 0 after reassignment", dav.count())
+	}
+	if got, _ := f.events.GetByID(mine.ID, householdID); got == nil {
+		t.Error("reassigned event should stay on the local calendar")
+	}
+}
+
+func TestCalDAVRecordsErrors(t *testing.T) {
+	f := setup(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	coll := f.collection(t, srv.URL+"/cal/", nil)
+
+	if _, err := f.syncer.SyncCollection(context.Background(), *coll); err == nil {
+		t.Fatal("expected an error")
+	}
+	got, _ := f.caldav.GetCollection(coll.ID, householdID)
+	if got.LastError == "" || got.LastSyncedAt == nil {
+		t.Errorf("sync outcome not recorded: %+v", got)
+	}
+}
+
+func newTestSyncer(cs *store.CalDAVStore, es *store.EventStore, ss *store.SettingsStore) *CalDAVSyncer {
+	return NewCalDAVSyncer(cs, es, ss, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
@@ -0,0 +1,424 @@
+// Package calsync keeps the household calendar in step with external
+// calendars: one-way imports from .ics files and subscribed feeds, and
+// two-way sync with CalDAV collections.
+package calsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/ical"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/recurrence"
+	"github.com/dukerupert/gamwich/internal/store"
+)
+
+// MaxCalendarSize caps how much of an .ics file or subscription is read.
+const MaxCalendarSize = 10 << 20
+
+var ErrTooLarge = errors.New("calendar is larger than 10 MB")
+
+// Result counts the events changed by an import.
+type Result struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Deleted int `json:"deleted"`
+}
+
+func (r Result) Changed() bool {
+	return r.Created+r.Updated+r.Deleted > 0
+}
+
+// Importer turns iCalendar data into calendar events. Events are matched to
+// earlier imports by UID and RECURRENCE-ID, so importing the same source
+// again updates events in place instead of duplicating them.
+type Importer struct {
+	events   *store.EventStore
+	subs     *store.CalendarSubscriptionStore
+	client   *http.Client
+	settings *store.SettingsStore
+	logger   *slog.Logger
+	notify   func(householdID int64)
+}
+
+// NewImporter creates an importer. Times are read into each household's
+// timezone. notify, if set, is called after a subscription sync changes a
+// household's events.
+func NewImporter(es *store.EventStore, ss *store.CalendarSubscriptionStore, settings *store.SettingsStore, notify func(householdID int64), logger *slog.Logger) *Importer {
+	return &Importer{
+		events:   es,
+		subs:     ss,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		settings: settings,
+		logger:   logger,
+		notify:   notify,
+	}
+}
+
+// householdLocation returns the timezone event times are converted to for
+// a household, falling back to UTC if it cannot be read.
+func householdLocation(settings *store.SettingsStore, householdID int64, logger *slog.Logger) *time.Location {
+	loc, err := settings.Location(householdID)
+	if err != nil {
+		logger.Warn("household timezone", "household_id", householdID, "error", err)
+	}
+	return loc
+}
+
+// NormalizeURL validates a subscription URL. webcal:// URLs are accepted
+// and fetched over HTTPS.
+func NormalizeURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid calendar URL")
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "webcal", "webcals":
+	default:
+		return "", fmt.Errorf("calendar URL must use http, https, or webcal")
+	}
+	return raw, nil
+}
+
+// ImportFile imports an uploaded .ics file. Events already imported from
+// a file with the same UIDs are updated; nothing is deleted except stale
+// exceptions of series present in the file.
+func (im *Importer) ImportFile(householdID int64, familyMemberID *int64, r io.Reader) (Result, error) {
+	data, err := readLimited(r)
+	if err != nil {
+		return Result{}, err
+	}
+	parsed, err := ical.Parse(bytes.NewReader(data), householdLocation(im.settings, householdID, im.logger))
+	if err != nil {
+		return Result{}, err
+	}
+	return im.apply(householdID, nil, familyMemberID, parsed, false)
+}
+
+// SyncSubscription fetches a subscription and reconciles its events: new
+// UIDs are created, changed ones updated, and ones no longer in the feed
+// removed. The outcome is recorded on the subscription.
+func (im *Importer) SyncSubscription(ctx context.Context, sub model.CalendarSubscription) (Result, error) {
+	res, err := im.syncSubscription(ctx, sub)
+
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+	if recErr := im.subs.RecordSync(sub.ID, time.Now(), errMsg); recErr != nil {
+		im.logger.Error("record subscription sync", "subscription_id", sub.ID, "error", recErr)
+	}
+	if res.Changed() && im.notify != nil {
+		im.notify(sub.HouseholdID)
+	}
+	return res, err
+}
+
+func (im *Importer) syncSubscription(ctx context.Context, sub model.CalendarSubscription) (Result, error) {
+	data, err := im.fetch(ctx, sub.URL)
+	if err != nil {
+		return Result{}, err
+	}
+	parsed, err := ical.Parse(bytes.NewReader(data), householdLocation(im.settings, sub.HouseholdID, im.logger))
+	if err != nil {
+		return Result{}, err
+	}
+	return im.apply(sub.HouseholdID, &sub.ID, sub.FamilyMemberID, parsed, true)
+}
+
+func (im *Importer) fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	fetchURL := rawURL
+	if scheme, rest, ok := strings.Cut(rawURL, "://"); ok && strings.HasPrefix(strings.ToLower(scheme), "webcal") {
+		fetchURL = "https://" + rest
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "text/calendar")
+	req.Header.Set("User-Agent", "Gamwich")
+
+	resp, err := im.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch calendar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch calendar: unexpected status %d", resp.StatusCode)
+	}
+	return readLimited(resp.Body)
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxCalendarSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read calendar: %w", err)
+	}
+	if len(data) > MaxCalendarSize {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+// eventKey identifies an imported row: the series UID plus the original
+// start of the occurrence it replaces (zero for the series itself).
+type eventKey struct {
+	uid string
+	rid int64
+}
+
+func keyOf(e model.CalendarEvent) eventKey {
+	k := eventKey{uid: e.SourceUID}
+	if e.OriginalStartTime != nil {
+		k.rid = e.OriginalStartTime.Unix()
+	}
+	return k
+}
+
+// apply reconciles parsed events with the rows previously imported from the
+// same source. Stale rows are removed when prune is set, and stale
+// exceptions of any series present in events are always removed.
+func (im *Importer) apply(householdID int64, subscriptionID, familyMemberID *int64, events []ical.Event, prune bool) (Result, error) {
+	existing, err := im.events.ListImported(householdID, subscriptionID)
+	if err != nil {
+		return Result{}, err
+	}
+	byKey := make(map[eventKey]model.CalendarEvent, len(existing))
+	for _, e := range existing {
+		byKey[keyOf(e)] = e
+	}
+
+	masters := make(map[string]ical.Event)
+	overrides := make(map[string][]ical.Event)
+	var uids []string
+	for _, e := range events {
+		if _, ok := masters[e.UID]; !ok && len(overrides[e.UID]) == 0 {
+			uids = append(uids, e.UID)
+		}
+		if e.RecurrenceID != nil {
+			overrides[e.UID] = append(overrides[e.UID], e)
+		} else {
+			masters[e.UID] = e
+		}
+	}
+
+	a := &applier{im: im, householdID: householdID, subscriptionID: subscriptionID, byKey: byKey, seen: make(map[eventKey]bool)}
+	for _, uid := range uids {
+		master, ok := masters[uid]
+		if !ok {
+			// Overrides without their series become standalone events.
+			for _, o := range overrides[uid] {
+				if o.Cancelled {
+					continue
+				}
+				if err := a.upsert(toModel(o, o.RecurrenceID), nil, familyMemberID); err != nil {
+					return a.res, err
+				}
+			}
+			continue
+		}
+		if master.Cancelled {
+			continue
+		}
+		if err := a.upsertSeries(master, overrides[uid], familyMemberID); err != nil {
+			return a.res, err
+		}
+	}
+
+	incoming := make(map[string]bool, len(uids))
+	for _, uid := range uids {
+		incoming[uid] = true
+	}
+	stale := make(map[int64]bool)
+	for k, e := range byKey {
+		if !a.seen[k] && (prune || incoming[k.uid]) {
+			stale[e.ID] = true
+		}
+	}
+	for _, e := range existing {
+		// Exceptions go with their parent via ON DELETE CASCADE.
+		if !stale[e.ID] || (e.RecurrenceParentID != nil && stale[*e.RecurrenceParentID]) {
+			continue
+		}
+		if err := im.events.Delete(e.ID, householdID); err != nil {
+			return a.res, err
+		}
+		a.res.Deleted++
+	}
+	return a.res, nil
+}
+
+type applier struct {
+	im             *Importer
+	householdID    int64
+	subscriptionID *int64
+	byKey          map[eventKey]model.CalendarEvent
+	seen           map[eventKey]bool
+	res            Result
+}
+
+func (a *applier) upsertSeries(master ical.Event, overrides []ical.Event, familyMemberID *int64) error {
+	e := toModel(master, nil)
+	e.RecurrenceRule = importableRule(a.im.logger, master.UID, e.RecurrenceRule)
+
+	parent, err := a.upsertRow(e, nil, familyMemberID)
+	if err != nil || parent.RecurrenceRule == "" {
+		return err
+	}
+
+	for _, exc := range seriesExceptions(master, overrides) {
+		if err := a.upsert(exc, &parent.ID, parent.FamilyMemberID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seriesExceptions turns a series' EXDATEs and RECURRENCE-ID overrides into
+// exception rows, in source order. An override takes precedence over an
+// EXDATE for the same occurrence.
+func seriesExceptions(master ical.Event, overrides []ical.Event) []model.CalendarEvent {
+	exceptions := make(map[int64]model.CalendarEvent)
+	var order []int64
+	duration := master.End.Sub(master.Start)
+	for _, ex := range master.ExDates {
+		rid := ex
+		exc := toModel(master, &rid)
+		exc.RecurrenceRule = ""
+		exc.StartTime, exc.EndTime = rid, rid.Add(duration)
+		exc.Cancelled = true
+		if _, ok := exceptions[rid.Unix()]; !ok {
+			order = append(order, rid.Unix())
+		}
+		exceptions[rid.Unix()] = exc
+	}
+	for _, o := range overrides {
+		exc := toModel(o, o.RecurrenceID)
+		exc.RecurrenceRule = ""
+		if _, ok := exceptions[o.RecurrenceID.Unix()]; !ok {
+			order = append(order, o.RecurrenceID.Unix())
+		}
+		exceptions[o.RecurrenceID.Unix()] = exc
+	}
+
+	result := make([]model.CalendarEvent, 0, len(order))
+	for _, rid := range order {
+		result = append(result, exceptions[rid])
+	}
+	return result
+}
+
+func (a *applier) upsert(e model.CalendarEvent, parentID, familyMemberID *int64) error {
+	_, err := a.upsertRow(e, parentID, familyMemberID)
+	return err
+}
+
+// upsertRow creates or refreshes the row for e. New rows are assigned to
+// familyMemberID; existing rows keep whatever member they were given.
+func (a *applier) upsertRow(e model.CalendarEvent, parentID, familyMemberID *int64) (*model.CalendarEvent, error) {
+	k := keyOf(e)
+	a.seen[k] = true
+	e.SubscriptionID = a.subscriptionID
+	e.RecurrenceParentID = parentID
+
+	if old, ok := a.byKey[k]; ok {
+		if sameID(old.RecurrenceParentID, parentID) {
+			if !changed(old, e) {
+				return &old, nil
+			}
+			updated, err := a.im.events.UpdateImported(old.ID, a.householdID, e)
+			if err != nil {
+				return nil, err
+			}
+			a.res.Updated++
+			return updated, nil
+		}
+		// The row moved in or out of a series; replace it.
+		if err := a.im.events.Delete(old.ID, a.householdID); err != nil {
+			return nil, err
+		}
+		familyMemberID = old.FamilyMemberID
+		a.res.Deleted++
+	}
+
+	e.FamilyMemberID = familyMemberID
+	created, err := a.im.events.CreateImported(a.householdID, e)
+	if err != nil {
+		return nil, err
+	}
+	a.res.Created++
+	return created, nil
+}
+
+func toModel(e ical.Event, recurrenceID *time.Time) model.CalendarEvent {
+	title := e.Summary
+	if title == "" {
+		title = "(No title)"
+	}
+	return model.CalendarEvent{
+		Title:             title,
+		Description:       e.Description,
+		StartTime:         e.Start,
+		EndTime:           e.End,
+		AllDay:            e.AllDay,
+		Location:          e.Location,
+		RecurrenceRule:    e.RRule,
+		OriginalStartTime: recurrenceID,
+		Cancelled:         e.Cancelled,
+		SourceUID:         e.UID,
+	}
+}
+
+// importableRule returns the form of rule the recurrence package expands.
+// Unsupported rules are dropped, leaving only the first occurrence.
+func importableRule(logger *slog.Logger, uid, rule string) string {
+	if rule == "" {
+		return ""
+	}
+	supported, err := SupportedRule(rule)
+	if err != nil {
+		logger.Warn("unsupported recurrence rule, importing first occurrence only", "uid", uid, "rrule", rule, "error", err)
+		return ""
+	}
+	return supported
+}
+
+// SupportedRule returns rule in the normalized form the recurrence package
+// expands, or an error if it uses parts that package does not support, such
+// as BYHOUR or BYWEEKNO.
+func SupportedRule(rule string) (string, error) {
+	parsed, err := recurrence.Parse(strings.TrimSuffix(rule, ";"))
+	if err != nil {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+func changed(old, e model.CalendarEvent) bool {
+	return old.Title != e.Title ||
+		old.Description != e.Description ||
+		old.Location != e.Location ||
+		!old.StartTime.Equal(e.StartTime) ||
+		!old.EndTime.Equal(e.EndTime) ||
+		old.AllDay != e.AllDay ||
+		old.RecurrenceRule != e.RecurrenceRule ||
+		old.Cancelled != e.Cancelled
+}
+
+func sameID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
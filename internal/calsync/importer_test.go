@@ -0,0 +1,329 @@
+package calsync
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dukerupert/gamwich/internal/database"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/store"
+)
+
+const householdID = store.DefaultHouseholdID
+
+type fixture struct {
+	importer *Importer
+	events   *store.EventStore
+	subs     *store.CalendarSubscriptionStore
+	members  *store.FamilyMemberStore
+	caldav   *store.CalDAVStore
+	syncer   *CalDAVSyncer
+	settings *store.SettingsStore
+}
+
+func setup(t *testing.T) *fixture {
+	t.Helper()
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	f := &fixture{
+		events:   store.NewEventStore(db),
+		subs:     store.NewCalendarSubscriptionStore(db),
+		members:  store.NewFamilyMemberStore(db),
+		caldav:   store.NewCalDAVStore(db),
+		settings: store.NewSettingsStore(db),
+	}
+	f.syncer = newTestSyncer(f.caldav, f.events, f.settings)
+	f.importer = NewImporter(f.events, f.subs, f.settings, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return f
+}
+
+// feed serves a calendar body that tests can swap between syncs.
+type feed struct {
+	mu   sync.Mutex
+	body string
+}
+
+func (f *feed) set(lines ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.body = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+}
+
+func (f *feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "text/calendar")
+	io.WriteString(w, f.body)
+}
+
+func vevent(props ...string) []string {
+	return append(append([]string{"BEGIN:VEVENT"}, props...), "END:VEVENT")
+}
+
+func concat(groups ...[]string) []string {
+	var out []string
+	for _, g := range groups {
+		out = append(out, g...)
+	}
+	return out
+}
+
+func (f *fixture) sync(t *testing.T, sub *model.CalendarSubscription) Result {
+	t.Helper()
+	res, err := f.importer.SyncSubscription(context.Background(), *sub)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	return res
+}
+
+func (f *fixture) imported(t *testing.T, subID *int64) []model.CalendarEvent {
+	t.Helper()
+	events, err := f.events.ListImported(householdID, subID)
+	if err != nil {
+		t.Fatalf("list imported: %v", err)
+	}
+	return events
+}
+
+func TestSyncSubscriptionIsIdempotent(t *testing.T) {
+	f := setup(t)
+	fd := &feed{}
+	srv := httptest.NewServer(fd)
+	defer srv.Close()
+
+	fd.set(concat(
+		vevent("UID:a@example.com", "SUMMARY:Soccer", "DTSTART:20260302T170000", "DTEND:20260302T180000"),
+		vevent("UID:b@example.com", "SUMMARY:Piano", "DTSTART:20260303T160000", "DTEND:20260303T163000"),
+	)...)
+	sub, _ := f.subs.Create(householdID, "Activities", srv.URL, nil)
+
+	if res := f.sync(t, sub); res.Created != 2 {
+		t.Errorf("first sync = %+v, want 2 created", res)
+	}
+	if res := f.sync(t, sub); res.Changed() {
+		t.Errorf("second sync = %+v, want no changes", res)
+	}
+	if got := len(f.imported(t, &sub.ID)); got != 2 {
+		t.Errorf("imported = %d, want 2", got)
+	}
+
+	synced, _ := f.subs.GetByID(sub.ID, householdID)
+	if synced.LastSyncedAt == nil || synced.LastError != "" {
+		t.Errorf("sync not recorded: %+v", synced)
+	}
+}
+
+func TestSyncSubscriptionUpdatesAndRemoves(t *testing.T) {
+	f := setup(t)
+	fd := &feed{}
+	srv := httptest.NewServer(fd)
+	defer srv.Close()
+
+	fd.set(concat(
+		vevent("UID:a@example.com", "SUMMARY:Soccer", "DTSTART:20260302T170000", "DTEND:20260302T180000"),
+		vevent("UID:b@example.com", "SUMMARY:Piano", "DTSTART:20260303T160000", "DTEND:20260303T163000"),
+	)...)
+	sub, _ := f.subs.Create(householdID, "Activities", srv.URL, nil)
+	f.sync(t, sub)
+
+	fd.set(vevent("UID:a@example.com", "SUMMARY:Soccer (moved)", "DTSTART:20260302T173000", "DTEND:20260302T183000")...)
+	res := f.sync(t, sub)
+	if res.Updated != 1 || res.Deleted != 1 || res.Created != 0 {
+		t.Errorf("resync = %+v, want 1 updated, 1 deleted", res)
+	}
+
+	events := f.imported(t, &sub.ID)
+	if len(events) != 1 {
+		t.Fatalf("imported = %d, want 1", len(events))
+	}
+	if events[0].Title != "Soccer (moved)" || events[0].StartTime.Hour() != 17 || events[0].StartTime.Minute() != 30 {
+		t.Errorf("event not updated: %+v", events[0])
+	}
+}
+
+func TestSyncSubscriptionRecurrenceExceptions(t *testing.T) {
+	f := setup(t)
+	fd := &feed{}
+	srv := httptest.NewServer(fd)
+	defer srv.Close()
+
+	fd.set(concat(
+		vevent("UID:s@example.com", "SUMMARY:Practice", "DTSTART:20260302T170000", "DTEND:20260302T180000",
+			"RRULE:FREQ=WEEKLY;INTERVAL=1;WKST=SU;BYDAY=MO", "EXDATE:20260309T170000,20260316T170000"),
+		vevent("UID:s@example.com", "SUMMARY:Practice (late)", "RECURRENCE-ID:20260316T170000",
+			"DTSTART:20260316T190000", "DTEND:20260316T200000"),
+	)...)
+	sub, _ := f.subs.Create(householdID, "Team", srv.URL, nil)
+	f.sync(t, sub)
+
+	events := f.imported(t, &sub.ID)
+	if len(events) != 3 {
+		t.Fatalf("imported = %d, want parent + 2 exceptions", len(events))
+	}
+	parent := events[0]
+	if parent.RecurrenceRule != "FREQ=WEEKLY;BYDAY=MO;WKST=SU" {
+		t.Errorf("rule = %q, want it normalized", parent.RecurrenceRule)
+	}
+	byStart := map[int]model.CalendarEvent{}
+	for _, e := range events[1:] {
+		if e.RecurrenceParentID == nil || *e.RecurrenceParentID != parent.ID {
+			t.Errorf("exception %d not attached to parent", e.ID)
+		}
+		byStart[e.OriginalStartTime.Day()] = e
+	}
+	if !byStart[9].Cancelled {
+		t.Error("expected EXDATE to become a cancelled exception")
+	}
+	if late := byStart[16]; late.Cancelled || late.Title != "Practice (late)" || late.StartTime.Hour() != 19 {
+		t.Errorf("expected override to win over EXDATE, got %+v", late)
+	}
+
+	// Dropping the override restores the EXDATE; dropping the EXDATE removes it.
+	fd.set(vevent("UID:s@example.com", "SUMMARY:Practice", "DTSTART:20260302T170000", "DTEND:20260302T180000",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO", "EXDATE:20260316T170000")...)
+	f.sync(t, sub)
+	events = f.imported(t, &sub.ID)
+	if len(events) != 2 || !events[1].Cancelled || events[1].OriginalStartTime.Day() != 16 {
+		t.Errorf("after resync = %+v", events)
+	}
+}
+
+func TestSyncSubscriptionKeepsMemberAssignments(t *testing.T) {
+	f := setup(t)
+	fd := &feed{}
+	srv := httptest.NewServer(fd)
+	defer srv.Close()
+
+	kid, _ := f.members.Create(householdID, "Sam", "#00FF00", "S")
+	parentMember, _ := f.members.Create(householdID, "Alex", "#FF0000", "A")
+
+	fd.set(vevent("UID:a@example.com", "SUMMARY:Soccer", "DTSTART:20260302T170000", "DTEND:20260302T180000",
+		"RRULE:FREQ=WEEKLY")...)
+	sub, _ := f.subs.Create(householdID, "Team", srv.URL, &kid.ID)
+	f.sync(t, sub)
+
+	events := f.imported(t, &sub.ID)
+	if events[0].FamilyMemberID == nil || *events[0].FamilyMemberID != kid.ID {
+		t.Fatalf("expected subscription member on new events, got %+v", events[0].FamilyMemberID)
+	}
+
+	if err := f.events.SetFamilyMember(events[0].ID, householdID, &parentMember.ID); err != nil {
+		t.Fatalf("set member: %v", err)
+	}
+	fd.set(vevent("UID:a@example.com", "SUMMARY:Soccer", "DTSTART:20260302T170000", "DTEND:20260302T180000",
+		"RRULE:FREQ=WEEKLY", "EXDATE:20260309T170000")...)
+	f.sync(t, sub)
+
+	for _, e := range f.imported(t, &sub.ID) {
+		if e.FamilyMemberID == nil || *e.FamilyMemberID != parentMember.ID {
+			t.Errorf("event %d member = %v, want reassigned member kept", e.ID, e.FamilyMemberID)
+		}
+	}
+}
+
+func TestSyncSubscriptionRecordsErrors(t *testing.T) {
+	f := setup(t)
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	sub, _ := f.subs.Create(householdID, "Gone", srv.URL, nil)
+	if _, err := f.importer.SyncSubscription(context.Background(), *sub); err == nil {
+		t.Fatal("expected error for 404 feed")
+	}
+	got, _ := f.subs.GetByID(sub.ID, householdID)
+	if got.LastSyncedAt == nil || !strings.Contains(got.LastError, "404") {
+		t.Errorf("error not recorded: %+v", got)
+	}
+}
+
+func TestImportFileDoesNotDuplicate(t *testing.T) {
+	f := setup(t)
+	ics := "BEGIN:VCALENDAR\r\n" + strings.Join(concat(
+		vevent("UID:r@example.com", "SUMMARY:Recital", "DTSTART;VALUE=DATE:20260410"),
+		vevent("UID:orphan@example.com", "RECURRENCE-ID:20260412T100000", "SUMMARY:Moved meeting",
+			"DTSTART:20260412T110000", "DTEND:20260412T120000"),
+	), "\r\n") + "\r\nEND:VCALENDAR\r\n"
+
+	res, err := f.importer.ImportFile(householdID, nil, strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if res.Created != 2 {
+		t.Errorf("import = %+v, want 2 created", res)
+	}
+	res, _ = f.importer.ImportFile(householdID, nil, strings.NewReader(ics))
+	if res.Changed() {
+		t.Errorf("reimport = %+v, want no changes", res)
+	}
+
+	events := f.imported(t, nil)
+	if len(events) != 2 {
+		t.Fatalf("imported = %d, want 2", len(events))
+	}
+	if !events[0].AllDay || events[0].SubscriptionID != nil {
+		t.Errorf("unexpected file event: %+v", events[0])
+	}
+	if events[1].RecurrenceParentID != nil || events[1].OriginalStartTime == nil {
+		t.Errorf("orphan override should be standalone: %+v", events[1])
+	}
+}
+
+func TestImportFileUsesHouseholdTimezone(t *testing.T) {
+	f := setup(t)
+	if err := f.settings.Set(householdID, store.TimezoneKey, "America/New_York"); err != nil {
+		t.Fatalf("set timezone: %v", err)
+	}
+	// UTC times on either side of the Mar 8, 2026 DST change.
+	ics := "BEGIN:VCALENDAR\r\n" + strings.Join(concat(
+		vevent("UID:before@example.com", "SUMMARY:Before", "DTSTART:20260307T150000Z", "DTEND:20260307T160000Z"),
+		vevent("UID:after@example.com", "SUMMARY:After", "DTSTART:20260309T150000Z", "DTEND:20260309T160000Z"),
+	), "\r\n") + "\r\nEND:VCALENDAR\r\n"
+
+	if _, err := f.importer.ImportFile(householdID, nil, strings.NewReader(ics)); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	events := f.imported(t, nil)
+	if len(events) != 2 {
+		t.Fatalf("imported = %d, want 2", len(events))
+	}
+	if got := events[0].StartTime.Format("2006-01-02 15:04"); got != "2026-03-07 10:00" {
+		t.Errorf("EST start = %s, want 10:00 wall-clock time", got)
+	}
+	if got := events[1].StartTime.Format("2006-01-02 15:04"); got != "2026-03-09 11:00" {
+		t.Errorf("EDT start = %s, want 11:00 wall-clock time", got)
+	}
+}
+
+func TestImportFileRejectsNonCalendar(t *testing.T) {
+	f := setup(t)
+	if _, err := f.importer.ImportFile(householdID, nil, strings.NewReader("hello")); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	for _, ok := range []string{"https://example.com/a.ics", "webcal://example.com/a.ics", " http://x/y "} {
+		if _, err := NormalizeURL(ok); err != nil {
+			t.Errorf("NormalizeURL(%q) = %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "ftp://example.com/a.ics", "example.com/a.ics", "file:///etc/passwd"} {
+		if _, err := NormalizeURL(bad); err == nil {
+			t.Errorf("NormalizeURL(%q) succeeded, want error", bad)
+		}
+	}
+}
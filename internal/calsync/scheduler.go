@@ -0,0 +1,146 @@
+package calsync
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/store"
+)
+
+// RefreshInterval is how often each subscription is re-fetched.
+const RefreshInterval = time.Hour
+
+// Scheduler periodically re-fetches calendar subscriptions and syncs CalDAV
+// collections that are due. Local edits can request an earlier CalDAV sync
+// with Notify.
+type Scheduler struct {
+	mu       sync.RWMutex
+	importer *Importer
+	subs     *store.CalendarSubscriptionStore
+	syncer   *CalDAVSyncer
+	caldav   *store.CalDAVStore
+	interval time.Duration
+	logger   *slog.Logger
+	pending  chan int64
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewScheduler creates a calendar sync scheduler.
+func NewScheduler(importer *Importer, subs *store.CalendarSubscriptionStore, syncer *CalDAVSyncer, caldavStore *store.CalDAVStore, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		importer: importer,
+		subs:     subs,
+		syncer:   syncer,
+		caldav:   caldavStore,
+		interval: 5 * time.Minute,
+		logger:   logger,
+		pending:  make(chan int64, 64),
+	}
+}
+
+// Notify asks for the household's CalDAV collections to be synced soon,
+// after its events changed locally. It never blocks and is safe to call on
+// a nil Scheduler.
+func (s *Scheduler) Notify(householdID int64) {
+	if s == nil {
+		return
+	}
+	select {
+	case s.pending <- householdID:
+	default:
+	}
+}
+
+// Start begins the scheduler loop.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	s.mu.Unlock()
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case householdID := <-s.pending:
+				s.syncer.SyncHousehold(ctx, householdID)
+			case <-ticker.C:
+				s.tick(ctx)
+			}
+		}
+	}()
+}
+
+// Stop gracefully stops the scheduler.
+func (s *Scheduler) Stop() {
+	s.mu.RLock()
+	cancel := s.cancel
+	done := s.done
+	s.mu.RUnlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if done != nil {
+		<-done
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	s.syncSubscriptions(ctx)
+	s.syncCollections(ctx)
+}
+
+func (s *Scheduler) syncSubscriptions(ctx context.Context) {
+	due, err := s.subs.ListDue(time.Now().Add(-RefreshInterval))
+	if err != nil {
+		s.logger.Error("list due subscriptions", "error", err)
+		return
+	}
+
+	for _, sub := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		res, err := s.importer.SyncSubscription(ctx, sub)
+		if err != nil {
+			s.logger.Warn("sync calendar subscription", "subscription_id", sub.ID, "error", err)
+			continue
+		}
+		if res.Changed() {
+			s.logger.Info("synced calendar subscription", "subscription_id", sub.ID,
+				"created", res.Created, "updated", res.Updated, "deleted", res.Deleted)
+		}
+	}
+}
+
+func (s *Scheduler) syncCollections(ctx context.Context) {
+	due, err := s.caldav.ListCollectionsDue(time.Now().Add(-CalDAVRefreshInterval))
+	if err != nil {
+		s.logger.Error("list due caldav collections", "error", err)
+		return
+	}
+
+	for _, coll := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		res, err := s.syncer.SyncCollection(ctx, coll)
+		if err != nil {
+			s.logger.Warn("sync caldav collection", "collection_id", coll.ID, "error", err)
+			continue
+		}
+		if res.Pulled+res.Pushed > 0 {
+			s.logger.Info("synced caldav collection", "collection_id", coll.ID,
+				"pulled", res.Pulled, "pushed", res.Pushed, "conflicts", res.Conflicts)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package chore
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/store"
+)
+
+// LoadHistory walks every recurring chore in the household against its
+// completions and recorded occurrences, keyed by chore ID.
+func LoadHistory(cs *store.ChoreStore, householdID int64, today time.Time) ([]model.Chore, map[int64][]OccurrenceResult, error) {
+	chores, err := cs.List(householdID)
+	if err != nil {
+		return nil, nil, err
+	}
+	history := make(map[int64][]OccurrenceResult)
+	for _, c := range chores {
+		if c.RecurrenceRule == "" {
+			continue
+		}
+		if history[c.ID], err = LoadChoreHistory(cs, c, householdID, today); err != nil {
+			return nil, nil, err
+		}
+	}
+	return chores, history, nil
+}
+
+// LoadChoreHistory returns one chore's occurrence history, oldest first.
+func LoadChoreHistory(cs *store.ChoreStore, c model.Chore, householdID int64, today time.Time) ([]OccurrenceResult, error) {
+	completions, err := cs.ListCompletionsByChore(c.ID, householdID)
+	if err != nil {
+		return nil, err
+	}
+	var events []model.ChoreRotationEvent
+	if c.Rotates() {
+		if events, err = cs.ListRotationEvents(c.ID, householdID); err != nil {
+			return nil, err
+		}
+	}
+	recorded, err := cs.ListOccurrences(c.ID, householdID)
+	if err != nil {
+		return nil, err
+	}
+	return History(c, completions, events, recorded, today), nil
+}
+
+// Closer records the outcome of each recurring chore occurrence once the
+// next one falls due, so missed occurrences stay in the history. It runs
+// hourly, closing out each household's day soon after its midnight.
+type Closer struct {
+	mu       sync.RWMutex
+	chores   *store.ChoreStore
+	settings *store.SettingsStore
+	interval time.Duration
+	logger   *slog.Logger
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewCloser creates a chore occurrence closer.
+func NewCloser(choreStore *store.ChoreStore, settingsStore *store.SettingsStore, logger *slog.Logger) *Closer {
+	return &Closer{
+		chores:   choreStore,
+		settings: settingsStore,
+		interval: time.Hour,
+		logger:   logger,
+	}
+}
+
+// Start begins the closer loop.
+func (c *Closer) Start(ctx context.Context) {
+	c.mu.Lock()
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	c.mu.Unlock()
+
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		c.tick()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.tick()
+			}
+		}
+	}()
+}
+
+// Stop gracefully stops the closer.
+func (c *Closer) Stop() {
+	c.mu.RLock()
+	cancel := c.cancel
+	done := c.done
+	c.mu.RUnlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if done != nil {
+		<-done
+	}
+}
+
+func (c *Closer) tick() {
+	householdIDs, err := c.chores.ListHouseholdIDs()
+	if err != nil {
+		c.logger.Error("list chore households", "error", err)
+		return
+	}
+
+	for _, hid := range householdIDs {
+		loc, err := c.settings.Location(hid)
+		if err != nil {
+			c.logger.Error("household timezone", "household_id", hid, "error", err)
+		}
+		n, err := c.CloseHousehold(hid, time.Now().In(loc))
+		if err != nil {
+			c.logger.Error("close chore occurrences", "household_id", hid, "error", err)
+			continue
+		}
+		if n > 0 {
+			c.logger.Info("closed chore occurrences", "household_id", hid, "count", n)
+		}
+	}
+}
+
+// CloseHousehold records every occurrence in the household whose window
+// has ended and that has no outcome yet, as of today. It returns how many
+// it recorded.
+func (c *Closer) CloseHousehold(householdID int64, today time.Time) (int, error) {
+	_, history, err := LoadHistory(c.chores, householdID, today)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for choreID, results := range history {
+		// The latest occurrence stays open until the next one is due.
+		for i := 0; i < len(results)-1; i++ {
+			r := results[i]
+			var status model.OccurrenceStatus
+			switch {
+			case r.Outcome.Done():
+				status = model.OccurrenceDone
+			case r.Outcome == OutcomeMissed:
+				status = model.OccurrenceMissed
+			default:
+				continue
+			}
+			added, err := c.chores.CloseOccurrence(choreID, householdID, DateKey(r.DueDate), status, r.MemberID, r.CompletedBy)
+			if err != nil {
+				return closed, err
+			}
+			if added {
+				closed++
+			}
+		}
+	}
+	return closed, nil
+}
@@ -0,0 +1,102 @@
+package chore
+
+import (
+	"database/sql"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/database"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/store"
+)
+
+func setupCloserTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestCloseHousehold(t *testing.T) {
+	db := setupCloserTestDB(t)
+
+	hid := store.DefaultHouseholdID
+	cs := store.NewChoreStore(db)
+	kid, _ := store.NewFamilyMemberStore(db).Create(hid, "Kid", "#FF0000", "K")
+	c, _ := cs.Create(hid, "Feed cat", "", nil, 1, "FREQ=DAILY", &kid.ID)
+	cs.Create(hid, "Buy shelves", "", nil, 1, "", nil)
+
+	db.Exec(`UPDATE chores SET created_at = ? WHERE id = ?`, time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC), c.ID)
+	comp, _ := cs.CreateCompletion(c.ID, hid, &kid.ID, 1)
+	db.Exec(`UPDATE chore_completions SET completed_at = ? WHERE id = ?`, time.Date(2026, 2, 2, 18, 0, 0, 0, time.UTC), comp.ID)
+	// Feb 3 was a sick day.
+	if _, err := cs.SetOccurrence(c.ID, hid, "2026-02-03", model.OccurrenceExcused, &kid.ID, nil, "flu"); err != nil {
+		t.Fatalf("excuse: %v", err)
+	}
+
+	closer := NewCloser(cs, store.NewSettingsStore(db), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	today := time.Date(2026, 2, 5, 12, 0, 0, 0, time.UTC)
+	n, err := closer.CloseHousehold(hid, today)
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	// Feb 1 missed, Feb 2 done, Feb 4 missed; Feb 3 was already excused and
+	// Feb 5 is still open.
+	if n != 3 {
+		t.Errorf("closed %d occurrences, want 3", n)
+	}
+
+	occurrences, _ := cs.ListOccurrences(c.ID, hid)
+	want := map[string]model.OccurrenceStatus{
+		"2026-02-01": model.OccurrenceMissed,
+		"2026-02-02": model.OccurrenceDone,
+		"2026-02-03": model.OccurrenceExcused,
+		"2026-02-04": model.OccurrenceMissed,
+	}
+	if len(occurrences) != len(want) {
+		t.Fatalf("got %d occurrences, want %d", len(occurrences), len(want))
+	}
+	for _, o := range occurrences {
+		if o.Status != want[o.DueDate] {
+			t.Errorf("%s: status = %q, want %q", o.DueDate, o.Status, want[o.DueDate])
+		}
+		if o.MemberID == nil || *o.MemberID != kid.ID {
+			t.Errorf("%s: member = %v, want %d", o.DueDate, o.MemberID, kid.ID)
+		}
+	}
+
+	// Closing again records nothing new.
+	if n, _ := closer.CloseHousehold(hid, today); n != 0 {
+		t.Errorf("second close recorded %d, want 0", n)
+	}
+}
+
+func TestCloseHouseholdCreatedOnOtherDay(t *testing.T) {
+	db := setupCloserTestDB(t)
+
+	hid := store.DefaultHouseholdID
+	cs := store.NewChoreStore(db)
+	c, _ := cs.Create(hid, "Take out bins", "", nil, 1, "FREQ=WEEKLY;BYDAY=MO", nil)
+	// Created on Wednesday Feb 4, so the first occurrence is Monday Feb 9.
+	db.Exec(`UPDATE chores SET created_at = ? WHERE id = ?`, time.Date(2026, 2, 4, 9, 0, 0, 0, time.UTC), c.ID)
+
+	closer := NewCloser(cs, store.NewSettingsStore(db), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	today := time.Date(2026, 2, 17, 12, 0, 0, 0, time.UTC)
+	if _, err := closer.CloseHousehold(hid, today); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	// Feb 9 was missed; Feb 16 is still open.
+	occurrences, _ := cs.ListOccurrences(c.ID, hid)
+	if len(occurrences) != 1 || occurrences[0].DueDate != "2026-02-09" || occurrences[0].Status != model.OccurrenceMissed {
+		t.Errorf("occurrences = %+v, want only 2026-02-09 missed", occurrences)
+	}
+}
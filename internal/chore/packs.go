@@ -0,0 +1,151 @@
+package chore
+
+import "github.com/dukerupert/gamwich/internal/model"
+
+// StarterPack is a built-in set of chores suited to one age band, for a
+// household to start from instead of adding every chore by hand. Chores
+// are filed under the areas every household starts out with. Chores that
+// are unsafe for younger members have a minimum age.
+type StarterPack struct {
+	Key         string                `json:"key"`
+	Name        string                `json:"name"`
+	AgeBand     string                `json:"age_band"`
+	Description string                `json:"description"`
+	Chores      []model.TemplateChore `json:"chores"`
+}
+
+const (
+	daily       = "FREQ=DAILY"
+	weekdays    = "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"
+	saturday    = "FREQ=WEEKLY;BYDAY=SA"
+	sunday      = "FREQ=WEEKLY;BYDAY=SU"
+	twiceWeekly = "FREQ=WEEKLY;BYDAY=TU,FR"
+	monthly     = "FREQ=MONTHLY;BYMONTHDAY=1"
+)
+
+var starterPacks = []StarterPack{
+	{
+		Key:         "little-helpers",
+		Name:        "Little Helpers",
+		AgeBand:     "Ages 3-5",
+		Description: "Simple daily habits, done with a grown-up nearby.",
+		Chores: []model.TemplateChore{
+			{Title: "Put toys away", Area: "Bedroom", Points: 1, RecurrenceRule: daily, DueTime: "19:00"},
+			{Title: "Put dirty clothes in the hamper", Area: "Bedroom", Points: 1, RecurrenceRule: daily},
+			{Title: "Make bed (with help)", Area: "Bedroom", Points: 1, RecurrenceRule: daily},
+			{Title: "Brush teeth", Area: "Bathroom", Points: 1, RecurrenceRule: daily, DueTime: "20:00"},
+			{Title: "Clear own plate", Area: "Kitchen", Points: 1, RecurrenceRule: daily},
+			{Title: "Feed the pet", Area: "General", Points: 1, RecurrenceRule: daily, Description: "A grown-up measures the food."},
+			{Title: "Match socks from the laundry", Area: "General", Points: 2, RecurrenceRule: saturday},
+		},
+	},
+	{
+		Key:         "growing-up",
+		Name:        "Growing Up",
+		AgeBand:     "Ages 6-9",
+		Description: "Own routines plus a first share of the housework.",
+		Chores: []model.TemplateChore{
+			{Title: "Make bed", Area: "Bedroom", Points: 1, RecurrenceRule: daily, DueTime: "08:00"},
+			{Title: "Tidy bedroom", Area: "Bedroom", Points: 3, RecurrenceRule: saturday},
+			{Title: "Set the table", Area: "Kitchen", Points: 2, RecurrenceRule: daily, DueTime: "18:00"},
+			{Title: "Unload the dishwasher", Area: "Kitchen", Points: 3, RecurrenceRule: weekdays},
+			{Title: "Wipe the bathroom sink", Area: "Bathroom", Points: 2, RecurrenceRule: twiceWeekly},
+			{Title: "Water the plants", Area: "Yard", Points: 2, RecurrenceRule: twiceWeekly},
+			{Title: "Feed the pet", Area: "General", Points: 1, RecurrenceRule: daily},
+			{Title: "Put away clean laundry", Area: "General", Points: 2, RecurrenceRule: sunday},
+		},
+	},
+	{
+		Key:         "pitching-in",
+		Name:        "Pitching In",
+		AgeBand:     "Ages 10-13",
+		Description: "Real jobs around the house, some checked by a parent.",
+		Chores: []model.TemplateChore{
+			{Title: "Load the dishwasher", Area: "Kitchen", Points: 3, RecurrenceRule: daily, DueTime: "20:00"},
+			{Title: "Wipe kitchen counters", Area: "Kitchen", Points: 2, RecurrenceRule: daily},
+			{Title: "Clean the bathroom", Area: "Bathroom", Points: 8, RecurrenceRule: saturday, RequiresApproval: true},
+			{Title: "Vacuum bedroom", Area: "Bedroom", Points: 4, RecurrenceRule: saturday},
+			{Title: "Change bed sheets", Area: "Bedroom", Points: 4, RecurrenceRule: sunday},
+			{Title: "Rake leaves or weed a bed", Area: "Yard", Points: 6, RecurrenceRule: saturday, RequiresApproval: true},
+			{Title: "Take out the trash", Area: "General", Points: 3, RecurrenceRule: twiceWeekly},
+			{Title: "Do own laundry", Area: "General", Points: 5, RecurrenceRule: sunday},
+		},
+	},
+	{
+		Key:         "teens",
+		Name:        "Teen Responsibilities",
+		AgeBand:     "Ages 14+",
+		Description: "Bigger jobs that keep the household running.",
+		Chores: []model.TemplateChore{
+			{Title: "Cook dinner", Area: "Kitchen", Points: 10, RecurrenceRule: "FREQ=WEEKLY;BYDAY=WE", DueTime: "18:30", MinAge: 14},
+			{Title: "Clean out the fridge", Area: "Kitchen", Points: 6, RecurrenceRule: monthly, RequiresApproval: true},
+			{Title: "Deep clean the bathroom", Area: "Bathroom", Points: 10, RecurrenceRule: saturday, RequiresApproval: true},
+			{Title: "Mow the lawn", Area: "Yard", Points: 10, RecurrenceRule: saturday, RequiresApproval: true, MinAge: 14},
+			{Title: "Take bins to the curb", Area: "Yard", Points: 3, RecurrenceRule: "FREQ=WEEKLY;BYDAY=TH", DueTime: "21:00"},
+			{Title: "Vacuum the living room", Area: "General", Points: 5, RecurrenceRule: twiceWeekly},
+			{Title: "Do own laundry", Area: "General", Points: 5, RecurrenceRule: sunday},
+		},
+	},
+	{
+		Key:         "household-basics",
+		Name:        "Household Basics",
+		AgeBand:     "Adults",
+		Description: "The weekly and monthly upkeep grown-ups share.",
+		Chores: []model.TemplateChore{
+			{Title: "Run the dishwasher", Area: "Kitchen", Points: 2, RecurrenceRule: daily},
+			{Title: "Plan meals and groceries", Area: "Kitchen", Points: 5, RecurrenceRule: sunday},
+			{Title: "Clean the bathrooms", Area: "Bathroom", Points: 8, RecurrenceRule: saturday},
+			{Title: "Wash bedding and towels", Area: "Bedroom", Points: 5, RecurrenceRule: sunday},
+			{Title: "Mop the floors", Area: "General", Points: 6, RecurrenceRule: saturday},
+			{Title: "Pay the bills", Area: "General", Points: 5, RecurrenceRule: monthly, MinAge: model.AdultAge},
+			{Title: "Check smoke alarms", Area: "General", Points: 2, RecurrenceRule: "FREQ=MONTHLY;INTERVAL=6;BYMONTHDAY=1"},
+			{Title: "Clean the gutters", Area: "Yard", Points: 10, RecurrenceRule: "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=1", MinAge: model.AdultAge},
+		},
+	},
+}
+
+// StarterPacks returns the built-in starter packs, youngest first.
+func StarterPacks() []StarterPack {
+	return starterPacks
+}
+
+// FindStarterPack returns the starter pack with the given key.
+func FindStarterPack(key string) (StarterPack, bool) {
+	for _, p := range starterPacks {
+		if p.Key == key {
+			return p, true
+		}
+	}
+	return StarterPack{}, false
+}
+
+// AreaChores is the chores in one area of a template.
+type AreaChores struct {
+	Area   string
+	Chores []model.TemplateChore
+}
+
+// ByArea groups template chores by area, in the order the areas first
+// appear. Chores with no area come last.
+func ByArea(chores []model.TemplateChore) []AreaChores {
+	var groups []AreaChores
+	index := make(map[string]int)
+	var none []model.TemplateChore
+	for _, c := range chores {
+		if c.Area == "" {
+			none = append(none, c)
+			continue
+		}
+		i, ok := index[c.Area]
+		if !ok {
+			i = len(groups)
+			index[c.Area] = i
+			groups = append(groups, AreaChores{Area: c.Area})
+		}
+		groups[i].Chores = append(groups[i].Chores, c)
+	}
+	if len(none) > 0 {
+		groups = append(groups, AreaChores{Area: "Other", Chores: none})
+	}
+	return groups
+}
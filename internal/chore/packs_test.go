@@ -0,0 +1,60 @@
+package chore
+
+import (
+	"testing"
+
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/recurrence"
+)
+
+func TestStarterPacks(t *testing.T) {
+	// The areas every household starts out with.
+	areas := map[string]bool{"Kitchen": true, "Bathroom": true, "Bedroom": true, "Yard": true, "General": true}
+
+	keys := make(map[string]bool)
+	for _, p := range StarterPacks() {
+		if p.Key == "" || keys[p.Key] {
+			t.Errorf("pack %q: missing or duplicate key", p.Name)
+		}
+		keys[p.Key] = true
+		if found, ok := FindStarterPack(p.Key); !ok || found.Name != p.Name {
+			t.Errorf("FindStarterPack(%q) = %v, %v", p.Key, found.Name, ok)
+		}
+
+		titles := make(map[string]bool)
+		for _, c := range p.Chores {
+			if titles[c.Title] {
+				t.Errorf("pack %s: duplicate chore %q", p.Key, c.Title)
+			}
+			titles[c.Title] = true
+			if !areas[c.Area] {
+				t.Errorf("pack %s: chore %q is in unknown area %q", p.Key, c.Title, c.Area)
+			}
+			if _, err := recurrence.Parse(c.RecurrenceRule); err != nil {
+				t.Errorf("pack %s: chore %q: %v", p.Key, c.Title, err)
+			}
+			if c.Points <= 0 {
+				t.Errorf("pack %s: chore %q earns no points", p.Key, c.Title)
+			}
+		}
+	}
+
+	if _, ok := FindStarterPack("nope"); ok {
+		t.Error("found a pack that does not exist")
+	}
+}
+
+func TestByArea(t *testing.T) {
+	groups := ByArea([]model.TemplateChore{
+		{Title: "Dishes", Area: "Kitchen"},
+		{Title: "Errand"},
+		{Title: "Beds", Area: "Bedroom"},
+		{Title: "Counters", Area: "Kitchen"},
+	})
+	if len(groups) != 3 {
+		t.Fatalf("got %d groups, want 3", len(groups))
+	}
+	if groups[0].Area != "Kitchen" || len(groups[0].Chores) != 2 || groups[1].Area != "Bedroom" || groups[2].Area != "Other" {
+		t.Errorf("groups = %+v", groups)
+	}
+}
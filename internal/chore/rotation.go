@@ -0,0 +1,156 @@
+package chore
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/recurrence"
+)
+
+// Rotation is where a rotating chore stands on a due date.
+type Rotation struct {
+	Turn     int       `json:"turn"`
+	DueDate  time.Time `json:"due_date"`
+	MemberID *int64    `json:"member_id"`
+}
+
+// RotationDay returns the day a chore's turn is counted for: its current
+// due date, or today if nothing is due.
+func RotationDay(dueDate *time.Time, today time.Time) time.Time {
+	if dueDate != nil {
+		return startOfDay(*dueDate)
+	}
+	return startOfDay(today)
+}
+
+// Turn returns the zero-based turn number for a chore on day. Per-occurrence
+// rotations advance once for every due date before day, weekly rotations
+// once per Monday-to-Sunday week since the chore was created, and
+// on-completion rotations once per completion before day.
+func Turn(c model.Chore, day time.Time, completionsBefore int) int {
+	loc := day.Location()
+	day = startOfDay(day)
+	created := startOfDay(c.CreatedAt.In(loc))
+
+	switch c.RotationCadence {
+	case model.RotationOnCompletion:
+		return completionsBefore
+	case model.RotationWeekly:
+		weeks := daysBetween(startOfWeek(created), startOfWeek(day)) / 7
+		if weeks < 0 {
+			return 0
+		}
+		return weeks
+	case model.RotationOccurrence:
+		if c.RecurrenceRule == "" {
+			return 0
+		}
+		rule, err := recurrence.Parse(c.RecurrenceRule)
+		if err != nil {
+			slog.Error("invalid recurrence rule", "chore_id", c.ID, "rule", c.RecurrenceRule, "error", err)
+			return 0
+		}
+		start := c.CreatedAt.In(loc)
+		occurrences := recurrence.Expand(rule, start, start.Add(time.Hour), start, day)
+		turn := 0
+		for _, occ := range occurrences {
+			if startOfDay(occ.Start).Before(day) {
+				turn++
+			}
+		}
+		return turn
+	}
+	return 0
+}
+
+// TurnMember returns the member whose turn it is. A skip pushes everyone
+// from its turn onwards along by one. A swap hands its turn to the other
+// member, who gives back their next turn in return.
+func TurnMember(members []int64, turn int, events []model.ChoreRotationEvent) *int64 {
+	if len(members) == 0 {
+		return nil
+	}
+
+	base := func(t int) int64 {
+		shift := 0
+		for _, e := range events {
+			if e.Kind == model.RotationSkip && e.Turn <= t {
+				shift++
+			}
+		}
+		return members[(t+shift)%len(members)]
+	}
+
+	member := base(turn)
+	for _, e := range events {
+		if e.Kind != model.RotationSwap || e.FromMemberID == nil || e.ToMemberID == nil {
+			continue
+		}
+		switch {
+		case e.Turn == turn:
+			member = *e.ToMemberID
+		case e.Turn < turn && member == *e.ToMemberID:
+			for t := e.Turn + 1; t <= turn; t++ {
+				if base(t) == *e.ToMemberID {
+					if t == turn {
+						member = *e.FromMemberID
+					}
+					break
+				}
+			}
+		}
+	}
+	return &member
+}
+
+// Responsible returns who is responsible for a chore on day. Chores that
+// do not rotate fall to their assignee. Rotation members too young for
+// the chore on day are passed over.
+func Responsible(c model.Chore, day time.Time, completionsBefore int, events []model.ChoreRotationEvent) Rotation {
+	if !c.Rotates() {
+		return responsibleForTurn(c, day, 0, events)
+	}
+	return responsibleForTurn(c, day, Turn(c, day, completionsBefore), events)
+}
+
+// responsibleForTurn is Responsible for a caller that already knows the
+// turn on day.
+func responsibleForTurn(c model.Chore, day time.Time, turn int, events []model.ChoreRotationEvent) Rotation {
+	r := Rotation{DueDate: startOfDay(day), MemberID: c.AssignedTo}
+	if !c.Rotates() {
+		return r
+	}
+	r.Turn = turn
+	r.MemberID = TurnMember(EligibleMembers(c, day), turn, events)
+	return r
+}
+
+// EligibleMembers returns the rotation members old enough for a chore on
+// day, in rotation order.
+func EligibleMembers(c model.Chore, day time.Time) []int64 {
+	if c.MinAge <= 0 {
+		return c.RotationMembers
+	}
+	members := []int64{}
+	for _, id := range c.RotationMembers {
+		if c.RotationProfiles[id].OldEnough(c.MinAge, day) {
+			members = append(members, id)
+		}
+	}
+	return members
+}
+
+// startOfWeek returns the Monday on or before t.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
+
+// daysBetween counts calendar days from a to b, ignoring daylight saving
+// shifts in between.
+func daysBetween(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
@@ -0,0 +1,185 @@
+package chore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func TestTurnPerOccurrence(t *testing.T) {
+	c := model.Chore{
+		ID: 1, RecurrenceRule: "FREQ=DAILY",
+		RotationCadence: model.RotationOccurrence,
+		RotationMembers: []int64{1, 2, 3},
+		CreatedAt:       time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		day  time.Time
+		want int
+	}{
+		{time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), 0},
+		{time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC), 1},
+		{time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC), 4},
+	}
+	for _, tt := range tests {
+		if got := Turn(c, tt.day, 0); got != tt.want {
+			t.Errorf("Turn(%s) = %d, want %d", tt.day.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestTurnWeekly(t *testing.T) {
+	// Created on a Wednesday; the turn changes each Monday.
+	c := model.Chore{
+		ID: 1, RecurrenceRule: "FREQ=DAILY",
+		RotationCadence: model.RotationWeekly,
+		RotationMembers: []int64{1, 2},
+		CreatedAt:       time.Date(2026, 2, 4, 9, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		day  time.Time
+		want int
+	}{
+		{time.Date(2026, 2, 8, 0, 0, 0, 0, time.UTC), 0},  // Sunday
+		{time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC), 1},  // Monday
+		{time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC), 3}, // Monday
+	}
+	for _, tt := range tests {
+		if got := Turn(c, tt.day, 0); got != tt.want {
+			t.Errorf("Turn(%s) = %d, want %d", tt.day.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestTurnWeeklyAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	c := model.Chore{
+		ID:              1,
+		RotationCadence: model.RotationWeekly,
+		RotationMembers: []int64{1, 2},
+		CreatedAt:       time.Date(2026, 3, 2, 9, 0, 0, 0, loc),
+	}
+	// 2026-03-08 is the spring-forward Sunday.
+	day := time.Date(2026, 3, 9, 0, 0, 0, 0, loc)
+	if got := Turn(c, day, 0); got != 1 {
+		t.Errorf("Turn = %d, want 1", got)
+	}
+}
+
+func TestTurnOnCompletion(t *testing.T) {
+	c := model.Chore{
+		ID:              1,
+		RotationCadence: model.RotationOnCompletion,
+		RotationMembers: []int64{1, 2},
+	}
+	if got := Turn(c, time.Now(), 5); got != 5 {
+		t.Errorf("Turn = %d, want 5", got)
+	}
+}
+
+func TestTurnMemberCycles(t *testing.T) {
+	members := []int64{10, 20, 30}
+	for turn, want := range []int64{10, 20, 30, 10, 20} {
+		got := TurnMember(members, turn, nil)
+		if got == nil || *got != want {
+			t.Errorf("turn %d: got %v, want %d", turn, got, want)
+		}
+	}
+}
+
+func TestTurnMemberSkip(t *testing.T) {
+	members := []int64{10, 20, 30}
+	events := []model.ChoreRotationEvent{
+		{Kind: model.RotationSkip, Turn: 1, FromMemberID: int64Ptr(20), ToMemberID: int64Ptr(30)},
+	}
+	for turn, want := range []int64{10, 30, 10, 20} {
+		got := TurnMember(members, turn, events)
+		if got == nil || *got != want {
+			t.Errorf("turn %d: got %v, want %d", turn, got, want)
+		}
+	}
+}
+
+func TestTurnMemberSwap(t *testing.T) {
+	members := []int64{10, 20, 30}
+	// 10 hands turn 0 to 30; 10 takes 30's next turn (turn 2) in return.
+	events := []model.ChoreRotationEvent{
+		{Kind: model.RotationSwap, Turn: 0, FromMemberID: int64Ptr(10), ToMemberID: int64Ptr(30)},
+	}
+	for turn, want := range []int64{30, 20, 10, 10, 20, 30} {
+		got := TurnMember(members, turn, events)
+		if got == nil || *got != want {
+			t.Errorf("turn %d: got %v, want %d", turn, got, want)
+		}
+	}
+}
+
+func TestTurnMemberEmpty(t *testing.T) {
+	if got := TurnMember(nil, 3, nil); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestResponsibleWithoutRotation(t *testing.T) {
+	c := model.Chore{ID: 1, AssignedTo: int64Ptr(7)}
+	r := Responsible(c, time.Now(), 0, nil)
+	if r.MemberID == nil || *r.MemberID != 7 {
+		t.Errorf("member = %v, want 7", r.MemberID)
+	}
+}
+
+func TestResponsibleWithRotation(t *testing.T) {
+	c := model.Chore{
+		ID: 1, RecurrenceRule: "FREQ=DAILY",
+		AssignedTo:      int64Ptr(7),
+		RotationCadence: model.RotationOccurrence,
+		RotationMembers: []int64{1, 2},
+		CreatedAt:       time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+	}
+	r := Responsible(c, time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC), 0, nil)
+	if r.Turn != 3 {
+		t.Errorf("turn = %d, want 3", r.Turn)
+	}
+	if r.MemberID == nil || *r.MemberID != 2 {
+		t.Errorf("member = %v, want 2", r.MemberID)
+	}
+}
+
+func TestResponsibleSkipsTooYoung(t *testing.T) {
+	c := model.Chore{
+		ID: 1, RecurrenceRule: "FREQ=DAILY", MinAge: 10,
+		RotationCadence: model.RotationOnCompletion,
+		RotationMembers: []int64{1, 2, 3},
+		RotationProfiles: map[int64]model.MemberProfile{
+			1: {Birthdate: "2014-03-10"},
+			2: {Birthdate: "2017-06-01"},
+			3: {Role: model.RoleChild},
+		},
+	}
+
+	// Member 2 turns 10 on 2027-06-01; member 3 has no birthdate, so is
+	// never old enough.
+	before := time.Date(2027, 5, 31, 0, 0, 0, 0, time.UTC)
+	for turn := 0; turn < 3; turn++ {
+		if r := Responsible(c, before, turn, nil); r.MemberID == nil || *r.MemberID != 1 {
+			t.Errorf("turn %d before birthday = %v, want 1", turn, r.MemberID)
+		}
+	}
+	after := time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)
+	if r := Responsible(c, after, 1, nil); r.MemberID == nil || *r.MemberID != 2 {
+		t.Errorf("turn 1 on birthday = %v, want 2", r.MemberID)
+	}
+
+	c.MinAge = 0
+	if r := Responsible(c, before, 2, nil); r.MemberID == nil || *r.MemberID != 3 {
+		t.Errorf("turn 2 without min age = %v, want 3", r.MemberID)
+	}
+}
@@ -0,0 +1,218 @@
+package chore
+
+import (
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/recurrence"
+)
+
+// Outcome is how an expected occurrence of a recurring chore turned out.
+type Outcome string
+
+const (
+	OutcomeOnTime Outcome = "on_time"
+	OutcomeLate   Outcome = "late"
+	OutcomeMissed Outcome = "missed"
+	// OutcomeExcused and OutcomeSkipped are occurrences let off by hand.
+	// They neither count against anyone nor break a streak.
+	OutcomeExcused Outcome = "excused"
+	OutcomeSkipped Outcome = "skipped"
+	// OutcomeOpen is the latest occurrence while it can still be done.
+	OutcomeOpen Outcome = "open"
+)
+
+// Done reports whether the occurrence was completed.
+func (o Outcome) Done() bool {
+	return o == OutcomeOnTime || o == OutcomeLate
+}
+
+// OccurrenceResult is one expected occurrence of a chore and who it fell to.
+type OccurrenceResult struct {
+	ChoreID     int64     `json:"chore_id"`
+	DueDate     time.Time `json:"due_date"`
+	MemberID    *int64    `json:"member_id"`
+	CompletedBy *int64    `json:"completed_by"`
+	Outcome     Outcome   `json:"outcome"`
+	Note        string    `json:"note"`
+}
+
+// History walks a recurring chore's occurrences from creation through today
+// and matches each against the first completion before the next one is
+// due. Completing on the due date, by its due time if it has one, is on
+// time; completing later is late.
+// Occurrences fall to whoever was responsible on the day, or to whoever
+// completed them if nobody was. Without a matching completion, a recorded
+// outcome takes precedence; a recorded "done" counts as late, since when it
+// was done is no longer known. One-off chores have no history.
+func History(c model.Chore, completions []model.ChoreCompletion, events []model.ChoreRotationEvent, recorded []model.ChoreOccurrence, today time.Time) []OccurrenceResult {
+	if c.RecurrenceRule == "" {
+		return nil
+	}
+	rule, err := recurrence.Parse(c.RecurrenceRule)
+	if err != nil {
+		slog.Error("invalid recurrence rule", "chore_id", c.ID, "rule", c.RecurrenceRule, "error", err)
+		return nil
+	}
+
+	loc := today.Location()
+	endOfToday := startOfDay(today).AddDate(0, 0, 1)
+	created := c.CreatedAt.In(loc)
+	occurrences := recurrence.Expand(rule, created, created.Add(time.Hour), created, endOfToday)
+
+	var dueDates []time.Time
+	for _, occ := range occurrences {
+		day := startOfDay(occ.Start)
+		if day.Before(endOfToday) && (len(dueDates) == 0 || day.After(dueDates[len(dueDates)-1])) {
+			dueDates = append(dueDates, day)
+		}
+	}
+
+	done := make([]model.ChoreCompletion, len(completions))
+	copy(done, completions)
+	sort.Slice(done, func(i, j int) bool { return done[i].CompletedAt.Before(done[j].CompletedAt) })
+
+	byDate := make(map[string]model.ChoreOccurrence, len(recorded))
+	for _, o := range recorded {
+		byDate[o.DueDate] = o
+	}
+
+	results := make([]OccurrenceResult, 0, len(dueDates))
+	next := 0              // first completion not yet matched or passed over
+	completionsBefore := 0 // completions before the due date
+	occurrencesBefore := 0 // occurrences on days before the due date
+	for i, due := range dueDates {
+		var windowEnd *time.Time
+		if i+1 < len(dueDates) {
+			windowEnd = &dueDates[i+1]
+		}
+
+		// Count the turn as Turn would, without expanding the rule again
+		// for every occurrence.
+		for completionsBefore < len(done) && done[completionsBefore].CompletedAt.Before(due) {
+			completionsBefore++
+		}
+		for occurrencesBefore < len(occurrences) && startOfDay(occurrences[occurrencesBefore].Start).Before(due) {
+			occurrencesBefore++
+		}
+		turn := 0
+		if c.RotationCadence == model.RotationOccurrence {
+			turn = occurrencesBefore
+		} else if c.Rotates() {
+			turn = Turn(c, due, completionsBefore)
+		}
+		rot := responsibleForTurn(c, due, turn, events)
+		res := OccurrenceResult{ChoreID: c.ID, DueDate: due, MemberID: rot.MemberID, Outcome: OutcomeMissed}
+		if windowEnd == nil {
+			res.Outcome = OutcomeOpen
+		}
+
+		for next < len(done) && startOfDay(done[next].CompletedAt.In(loc)).Before(due) {
+			next++
+		}
+		if next < len(done) {
+			day := startOfDay(done[next].CompletedAt.In(loc))
+			if windowEnd == nil || day.Before(*windowEnd) {
+				res.CompletedBy = done[next].CompletedBy
+				res.Outcome = OutcomeLate
+				if deadline := Deadline(c, &due); day.Equal(due) && (deadline == nil || !done[next].CompletedAt.After(*deadline)) {
+					res.Outcome = OutcomeOnTime
+				}
+				next++
+			}
+		}
+		if o, ok := byDate[DateKey(due)]; ok {
+			if o.MemberID != nil {
+				res.MemberID = o.MemberID
+			}
+			res.Note = o.Note
+			if !res.Outcome.Done() {
+				res.CompletedBy = o.CompletedBy
+				res.Outcome = recordedOutcome(o.Status)
+			}
+		}
+		if res.MemberID == nil {
+			res.MemberID = res.CompletedBy
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func recordedOutcome(status model.OccurrenceStatus) Outcome {
+	switch status {
+	case model.OccurrenceDone:
+		return OutcomeLate
+	case model.OccurrenceExcused:
+		return OutcomeExcused
+	case model.OccurrenceSkipped:
+		return OutcomeSkipped
+	}
+	return OutcomeMissed
+}
+
+// DateKey formats a due date the way occurrences are recorded.
+func DateKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// Stats summarises a run of occurrences. Open occurrences only count
+// towards the totals once they are done; excused and skipped ones never do.
+type Stats struct {
+	Occurrences   int     `json:"occurrences"`
+	Completed     int     `json:"completed"`
+	OnTime        int     `json:"on_time"`
+	Missed        int     `json:"missed"`
+	OnTimeRate    float64 `json:"on_time_rate"`
+	CurrentStreak int     `json:"current_streak"`
+	BestStreak    int     `json:"best_streak"`
+}
+
+// Summarize computes stats for results. A streak is a run of consecutive
+// occurrences that were done, on time or late; an open occurrence does not
+// break the current streak.
+func Summarize(results []OccurrenceResult) Stats {
+	sorted := make([]OccurrenceResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].DueDate.Before(sorted[j].DueDate) })
+
+	var s Stats
+	run := 0
+	for _, r := range sorted {
+		switch r.Outcome {
+		case OutcomeOpen, OutcomeExcused, OutcomeSkipped:
+			continue
+		case OutcomeMissed:
+			s.Missed++
+			run = 0
+		case OutcomeOnTime:
+			s.OnTime++
+			fallthrough
+		case OutcomeLate:
+			s.Completed++
+			run++
+			if run > s.BestStreak {
+				s.BestStreak = run
+			}
+		}
+		s.Occurrences++
+	}
+	s.CurrentStreak = run
+	if s.Occurrences > 0 {
+		s.OnTimeRate = float64(s.OnTime) / float64(s.Occurrences)
+	}
+	return s
+}
+
+// ForMember returns the results that fell to memberID.
+func ForMember(results []OccurrenceResult, memberID int64) []OccurrenceResult {
+	var out []OccurrenceResult
+	for _, r := range results {
+		if r.MemberID != nil && *r.MemberID == memberID {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,247 @@
+package chore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+func completionAt(t time.Time, by int64) model.ChoreCompletion {
+	return model.ChoreCompletion{CompletedAt: t, CompletedBy: &by}
+}
+
+func TestHistoryOutcomes(t *testing.T) {
+	c := model.Chore{
+		ID: 1, RecurrenceRule: "FREQ=DAILY",
+		AssignedTo: int64Ptr(7),
+		CreatedAt:  time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+	}
+	completions := []model.ChoreCompletion{
+		completionAt(time.Date(2026, 2, 1, 18, 0, 0, 0, time.UTC), 7), // on time
+		completionAt(time.Date(2026, 2, 3, 8, 0, 0, 0, time.UTC), 7),  // Feb 2 missed, Feb 3 on time
+		completionAt(time.Date(2026, 2, 3, 9, 0, 0, 0, time.UTC), 7),  // extra, ignored
+	}
+	today := time.Date(2026, 2, 4, 12, 0, 0, 0, time.UTC)
+
+	results := History(c, completions, nil, nil, today)
+	want := []Outcome{OutcomeOnTime, OutcomeMissed, OutcomeOnTime, OutcomeOpen}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		if r.Outcome != want[i] {
+			t.Errorf("%s: outcome = %q, want %q", r.DueDate.Format("2006-01-02"), r.Outcome, want[i])
+		}
+		if r.MemberID == nil || *r.MemberID != 7 {
+			t.Errorf("%s: member = %v, want 7", r.DueDate.Format("2006-01-02"), r.MemberID)
+		}
+	}
+}
+
+func TestHistoryLateCompletion(t *testing.T) {
+	c := model.Chore{
+		ID: 1, RecurrenceRule: "FREQ=WEEKLY",
+		CreatedAt: time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC), // Monday
+	}
+	completions := []model.ChoreCompletion{
+		completionAt(time.Date(2026, 2, 4, 10, 0, 0, 0, time.UTC), 3),
+	}
+	today := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+
+	results := History(c, completions, nil, nil, today)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0].Outcome != OutcomeLate {
+		t.Errorf("outcome = %q, want late", results[0].Outcome)
+	}
+	// Unassigned occurrences fall to whoever did them.
+	if results[0].MemberID == nil || *results[0].MemberID != 3 {
+		t.Errorf("member = %v, want 3", results[0].MemberID)
+	}
+	if results[1].Outcome != OutcomeOpen || results[1].MemberID != nil {
+		t.Errorf("current occurrence = %+v, want open and unassigned", results[1])
+	}
+}
+
+func TestHistoryDueTime(t *testing.T) {
+	c := model.Chore{
+		ID: 1, RecurrenceRule: "FREQ=DAILY", DueTime: "08:00",
+		CreatedAt: time.Date(2026, 2, 1, 6, 0, 0, 0, time.UTC),
+	}
+	completions := []model.ChoreCompletion{
+		completionAt(time.Date(2026, 2, 1, 7, 45, 0, 0, time.UTC), 3),
+		completionAt(time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC), 3), // after the due time
+	}
+	today := time.Date(2026, 2, 3, 7, 0, 0, 0, time.UTC)
+
+	results := History(c, completions, nil, nil, today)
+	want := []Outcome{OutcomeOnTime, OutcomeLate, OutcomeOpen}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		if r.Outcome != want[i] {
+			t.Errorf("%s: outcome = %q, want %q", r.DueDate.Format("2006-01-02"), r.Outcome, want[i])
+		}
+	}
+}
+
+func TestHistoryRotation(t *testing.T) {
+	c := model.Chore{
+		ID: 1, RecurrenceRule: "FREQ=DAILY",
+		RotationCadence: model.RotationOccurrence,
+		RotationMembers: []int64{1, 2},
+		CreatedAt:       time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+	}
+	today := time.Date(2026, 2, 4, 12, 0, 0, 0, time.UTC)
+
+	results := History(c, nil, nil, nil, today)
+	for i, r := range results {
+		want := c.RotationMembers[i%2]
+		if r.MemberID == nil || *r.MemberID != want {
+			t.Errorf("%s: member = %v, want %d", r.DueDate.Format("2006-01-02"), r.MemberID, want)
+		}
+	}
+	if len(ForMember(results, 2)) != 2 {
+		t.Errorf("member 2 has %d occurrences, want 2", len(ForMember(results, 2)))
+	}
+}
+
+func TestHistoryRotationMatchesResponsible(t *testing.T) {
+	events := []model.ChoreRotationEvent{
+		{Kind: model.RotationSkip, Turn: 3, FromMemberID: int64Ptr(1), ToMemberID: int64Ptr(2)},
+		{Kind: model.RotationSwap, Turn: 10, FromMemberID: int64Ptr(3), ToMemberID: int64Ptr(1)},
+	}
+	completions := []model.ChoreCompletion{
+		completionAt(time.Date(2026, 2, 4, 18, 0, 0, 0, time.UTC), 1),
+		completionAt(time.Date(2026, 2, 9, 18, 0, 0, 0, time.UTC), 2),
+		completionAt(time.Date(2026, 2, 20, 18, 0, 0, 0, time.UTC), 3),
+	}
+	today := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	for _, cadence := range []model.RotationCadence{model.RotationOccurrence, model.RotationWeekly, model.RotationOnCompletion} {
+		c := model.Chore{
+			ID: 1, RecurrenceRule: "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			RotationCadence: cadence,
+			RotationMembers: []int64{1, 2, 3},
+			CreatedAt:       time.Date(2026, 2, 3, 9, 0, 0, 0, time.UTC),
+		}
+		for _, r := range History(c, completions, events, nil, today) {
+			before := 0
+			for _, comp := range completions {
+				if comp.CompletedAt.Before(r.DueDate) {
+					before++
+				}
+			}
+			want := Responsible(c, r.DueDate, before, events).MemberID
+			if r.MemberID == nil || *r.MemberID != *want {
+				t.Errorf("%s %s: member = %v, want %d", cadence, r.DueDate.Format("2006-01-02"), r.MemberID, *want)
+			}
+		}
+	}
+}
+
+func TestHistoryOneOff(t *testing.T) {
+	c := model.Chore{ID: 1, CreatedAt: time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)}
+	if results := History(c, nil, nil, nil, time.Now()); results != nil {
+		t.Errorf("one-off chore history = %v, want nil", results)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 2, d, 0, 0, 0, 0, time.UTC) }
+	results := []OccurrenceResult{
+		{DueDate: day(1), Outcome: OutcomeOnTime},
+		{DueDate: day(2), Outcome: OutcomeLate},
+		{DueDate: day(3), Outcome: OutcomeOnTime},
+		{DueDate: day(4), Outcome: OutcomeMissed},
+		{DueDate: day(5), Outcome: OutcomeOnTime},
+		{DueDate: day(6), Outcome: OutcomeOpen},
+	}
+
+	s := Summarize(results)
+	if s.Occurrences != 5 || s.Completed != 4 || s.OnTime != 3 || s.Missed != 1 {
+		t.Errorf("totals = %+v", s)
+	}
+	if s.OnTimeRate != 0.6 {
+		t.Errorf("on-time rate = %v, want 0.6", s.OnTimeRate)
+	}
+	if s.CurrentStreak != 1 || s.BestStreak != 3 {
+		t.Errorf("streaks = %d current, %d best; want 1, 3", s.CurrentStreak, s.BestStreak)
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	if s := Summarize(nil); s != (Stats{}) {
+		t.Errorf("empty stats = %+v", s)
+	}
+}
+
+func TestHistoryRecordedOutcomes(t *testing.T) {
+	c := model.Chore{
+		ID: 1, RecurrenceRule: "FREQ=DAILY",
+		AssignedTo: int64Ptr(7),
+		CreatedAt:  time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+	}
+	completions := []model.ChoreCompletion{
+		completionAt(time.Date(2026, 2, 2, 18, 0, 0, 0, time.UTC), 7),
+	}
+	recorded := []model.ChoreOccurrence{
+		{DueDate: "2026-02-01", Status: model.OccurrenceExcused, Note: "sick"},
+		{DueDate: "2026-02-02", Status: model.OccurrenceSkipped}, // completion wins
+		{DueDate: "2026-02-03", Status: model.OccurrenceDone, CompletedBy: int64Ptr(8)},
+		{DueDate: "2026-02-04", Status: model.OccurrenceSkipped},
+	}
+	today := time.Date(2026, 2, 4, 12, 0, 0, 0, time.UTC)
+
+	results := History(c, completions, nil, recorded, today)
+	want := []Outcome{OutcomeExcused, OutcomeOnTime, OutcomeLate, OutcomeSkipped}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		if r.Outcome != want[i] {
+			t.Errorf("%s: outcome = %q, want %q", r.DueDate.Format("2006-01-02"), r.Outcome, want[i])
+		}
+	}
+	if results[0].Note != "sick" {
+		t.Errorf("note = %q, want %q", results[0].Note, "sick")
+	}
+	if results[2].CompletedBy == nil || *results[2].CompletedBy != 8 {
+		t.Errorf("completed by = %v, want 8", results[2].CompletedBy)
+	}
+
+	// Excused and skipped occurrences do not break the streak.
+	s := Summarize(results)
+	if s.Occurrences != 2 || s.Missed != 0 || s.CurrentStreak != 2 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestApplyHistory(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 2, d, 0, 0, 0, 0, time.UTC) }
+	history := []OccurrenceResult{
+		{DueDate: day(1), Outcome: OutcomeOnTime},
+		{DueDate: day(2), Outcome: OutcomeMissed},
+		{DueDate: day(3), Outcome: OutcomeSkipped},
+		{DueDate: day(4), Outcome: OutcomeMissed},
+		{DueDate: day(5), Outcome: OutcomeExcused},
+	}
+
+	status, missed := ApplyHistory(StatusOverdue, history)
+	if status != StatusExcused {
+		t.Errorf("status = %q, want %q", status, StatusExcused)
+	}
+	if missed != 2 {
+		t.Errorf("missed = %d, want 2", missed)
+	}
+
+	if status, _ := ApplyHistory(StatusCompleted, history); status != StatusCompleted {
+		t.Errorf("completed status = %q, want unchanged", status)
+	}
+	if status, missed := ApplyHistory(StatusPending, nil); status != StatusPending || missed != 0 {
+		t.Errorf("no history = %q, %d", status, missed)
+	}
+}
@@ -15,29 +15,56 @@ const (
 	StatusCompleted Status = "completed"
 	StatusOverdue   Status = "overdue"
 	StatusNotDue    Status = "not_due"
+	// StatusExcused and StatusSkipped are a current due date let off by hand.
+	StatusExcused Status = "excused"
+	StatusSkipped Status = "skipped"
 )
 
 type ChoreWithStatus struct {
 	model.Chore
-	Status         Status
-	DueDate        *time.Time
+	Status  Status
+	DueDate *time.Time
+	// Deadline is when the current due date must be done by, if the chore
+	// has a due time or, for one-off chores, a due date.
+	Deadline       *time.Time
 	LastCompletion *time.Time
 	AreaName       string
 	MemberName     string
 	MemberColor    string
 	MemberEmoji    string
+	// Missed counts the occurrences missed in a row before the current one.
+	Missed int
+	// AwaitingApproval is set when the completion that finished the chore
+	// is waiting for a parent to approve it.
+	AwaitingApproval bool
+	// SwapOptions are the other rotation members who can take this turn.
+	SwapOptions []model.FamilyMember
 }
 
 // ComputeStatus determines the status and due date for a chore given its last completion.
+// Days are counted in today's location, which should be the household's timezone,
+// so due dates stay on the same local day across daylight saving changes.
+// today should be the current time: a chore with a deadline is overdue as
+// soon as its deadline passes, not just once its due date does.
 func ComputeStatus(chore model.Chore, lastCompletion *time.Time, today time.Time) (Status, *time.Time) {
+	loc := today.Location()
+	now := today
 	today = startOfDay(today)
 
 	// One-off chore (no recurrence rule)
 	if chore.RecurrenceRule == "" {
+		var dueAt *time.Time
+		if chore.DueAt != nil {
+			t := chore.DueAt.In(loc)
+			dueAt = &t
+		}
 		if lastCompletion != nil {
-			return StatusCompleted, nil
+			return StatusCompleted, dueAt
 		}
-		return StatusPending, nil
+		if dueAt != nil && now.After(*dueAt) {
+			return StatusOverdue, dueAt
+		}
+		return StatusPending, dueAt
 	}
 
 	// Recurring chore — expand to find the current due date
@@ -52,17 +79,18 @@ func ComputeStatus(chore model.Chore, lastCompletion *time.Time, today time.Time
 	}
 
 	// Expand from creation through end of tomorrow to find all relevant due dates
-	tomorrow := today.Add(48 * time.Hour)
+	tomorrow := today.AddDate(0, 0, 2)
 	// Use a 1-hour event duration for expansion purposes
-	eventEnd := chore.CreatedAt.Add(time.Hour)
-	occurrences := recurrence.Expand(rule, chore.CreatedAt, eventEnd, chore.CreatedAt, tomorrow)
+	created := chore.CreatedAt.In(loc)
+	eventEnd := created.Add(time.Hour)
+	occurrences := recurrence.Expand(rule, created, eventEnd, created, tomorrow)
 
 	if len(occurrences) == 0 {
 		return StatusNotDue, nil
 	}
 
 	// Find the most recent due date that is <= today (end of today)
-	endOfToday := today.Add(24 * time.Hour)
+	endOfToday := today.AddDate(0, 0, 1)
 	var currentDue *time.Time
 	for i := len(occurrences) - 1; i >= 0; i-- {
 		occDate := startOfDay(occurrences[i].Start)
@@ -78,19 +106,82 @@ func ComputeStatus(chore model.Chore, lastCompletion *time.Time, today time.Time
 	}
 
 	// Check if completed since the current due date
-	if lastCompletion != nil && !startOfDay(*lastCompletion).Before(*currentDue) {
+	if lastCompletion != nil && !startOfDay(lastCompletion.In(loc)).Before(*currentDue) {
 		return StatusCompleted, currentDue
 	}
 
-	// Check if overdue: due date is before today
+	// Check if overdue: due date is before today, or today's deadline has passed
 	if currentDue.Before(today) {
 		return StatusOverdue, currentDue
 	}
+	if deadline := Deadline(chore, currentDue); deadline != nil && now.After(*deadline) {
+		return StatusOverdue, currentDue
+	}
 
 	return StatusPending, currentDue
 }
 
-// IsDueOnDate checks if a chore has a due occurrence on the given date.
+// Deadline returns when a chore due on dueDate must be done by: its due
+// date for one-off chores, or its due time on dueDate for recurring ones.
+// It returns nil if the chore only has to be done some time that day.
+func Deadline(chore model.Chore, dueDate *time.Time) *time.Time {
+	if chore.RecurrenceRule == "" {
+		if chore.DueAt == nil {
+			return nil
+		}
+		t := *chore.DueAt
+		if dueDate != nil {
+			t = t.In(dueDate.Location())
+		}
+		return &t
+	}
+	if dueDate == nil || chore.DueTime == "" {
+		return nil
+	}
+	clock, err := time.Parse("15:04", chore.DueTime)
+	if err != nil {
+		slog.Error("invalid due time", "chore_id", chore.ID, "due_time", chore.DueTime, "error", err)
+		return nil
+	}
+	d := *dueDate
+	t := time.Date(d.Year(), d.Month(), d.Day(), clock.Hour(), clock.Minute(), 0, 0, d.Location())
+	return &t
+}
+
+// ApplyHistory adjusts a status computed by ComputeStatus for the chore's
+// occurrence history: a current due date that was excused or skipped is no
+// longer pending or overdue. It also returns how many occurrences were
+// missed in a row before the current one, passing over excused and skipped
+// ones.
+func ApplyHistory(status Status, history []OccurrenceResult) (Status, int) {
+	if len(history) == 0 {
+		return status, 0
+	}
+	current := history[len(history)-1]
+	if status == StatusPending || status == StatusOverdue {
+		switch current.Outcome {
+		case OutcomeExcused:
+			status = StatusExcused
+		case OutcomeSkipped:
+			status = StatusSkipped
+		}
+	}
+	missed := 0
+	for i := len(history) - 2; i >= 0; i-- {
+		switch history[i].Outcome {
+		case OutcomeMissed:
+			missed++
+			continue
+		case OutcomeExcused, OutcomeSkipped:
+			continue
+		}
+		break
+	}
+	return status, missed
+}
+
+// IsDueOnDate checks if a chore has a due occurrence on the given date, in
+// the date's location.
 func IsDueOnDate(chore model.Chore, date time.Time) bool {
 	if chore.RecurrenceRule == "" {
 		// One-off chores are always "due" until completed
@@ -103,9 +194,10 @@ func IsDueOnDate(chore model.Chore, date time.Time) bool {
 	}
 
 	dayStart := startOfDay(date)
-	dayEnd := dayStart.Add(24 * time.Hour)
-	eventEnd := chore.CreatedAt.Add(time.Hour)
-	occurrences := recurrence.Expand(rule, chore.CreatedAt, eventEnd, dayStart, dayEnd)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	created := chore.CreatedAt.In(date.Location())
+	eventEnd := created.Add(time.Hour)
+	occurrences := recurrence.Expand(rule, created, eventEnd, dayStart, dayEnd)
 	return len(occurrences) > 0
 }
 
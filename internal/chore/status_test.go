@@ -128,6 +128,25 @@ func TestWeeklyNotDue(t *testing.T) {
 	}
 }
 
+func TestWeeklyByDayCreatedOnOtherDay(t *testing.T) {
+	c := model.Chore{
+		ID: 1, Title: "Take out bins",
+		RecurrenceRule: "FREQ=WEEKLY;BYDAY=MO",
+		CreatedAt:      time.Date(2026, 2, 4, 9, 0, 0, 0, time.UTC), // Wednesday
+	}
+	// Friday Feb 6 — the creation day is not an occurrence, so nothing is due
+	// until Monday Feb 9.
+	today := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+
+	status, due := ComputeStatus(c, nil, today)
+	if status != StatusNotDue {
+		t.Errorf("status = %q, want %q", status, StatusNotDue)
+	}
+	if due != nil {
+		t.Errorf("due = %v, want nil", due)
+	}
+}
+
 func TestWeeklyDue(t *testing.T) {
 	c := model.Chore{
 		ID: 1, Title: "Weekly clean",
@@ -247,3 +266,114 @@ func TestIsDueOnDateWeekly(t *testing.T) {
 		t.Error("expected weekly chore not to be due on Tuesday")
 	}
 }
+
+func TestComputeStatusHouseholdTimezoneAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	// Created Sunday Mar 1, 2026 at 8pm EST, which is already Monday in UTC.
+	c := model.Chore{
+		ID: 1, Title: "Take out trash",
+		RecurrenceRule: "FREQ=WEEKLY",
+		CreatedAt:      time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC),
+	}
+
+	// Sunday Mar 8 is the first day of EDT; the chore is due that Sunday.
+	today := time.Date(2026, 3, 8, 21, 0, 0, 0, ny)
+	status, due := ComputeStatus(c, nil, today)
+	if status != StatusPending {
+		t.Errorf("status = %q, want %q", status, StatusPending)
+	}
+	if due == nil || !due.Equal(time.Date(2026, 3, 8, 0, 0, 0, 0, ny)) {
+		t.Errorf("due = %v, want Mar 8 in New York", due)
+	}
+
+	// Completed at 11:30pm EDT on the due day, which is Monday in UTC.
+	completed := time.Date(2026, 3, 9, 3, 30, 0, 0, time.UTC)
+	status, _ = ComputeStatus(c, &completed, today)
+	if status != StatusCompleted {
+		t.Errorf("status after late completion = %q, want %q", status, StatusCompleted)
+	}
+
+	// Monday after the change, it's overdue rather than due today.
+	status, _ = ComputeStatus(c, nil, time.Date(2026, 3, 9, 8, 0, 0, 0, ny))
+	if status != StatusOverdue {
+		t.Errorf("status on Monday = %q, want %q", status, StatusOverdue)
+	}
+
+	if !IsDueOnDate(c, time.Date(2026, 3, 15, 0, 0, 0, 0, ny)) {
+		t.Error("expected chore to be due on Sunday Mar 15 in New York")
+	}
+	if IsDueOnDate(c, time.Date(2026, 3, 16, 0, 0, 0, 0, ny)) {
+		t.Error("expected chore not to be due on Monday Mar 16 in New York")
+	}
+}
+
+func TestOneOffDueAt(t *testing.T) {
+	dueAt := time.Date(2026, 2, 5, 15, 0, 0, 0, time.UTC)
+	c := model.Chore{
+		ID: 1, Title: "Return library books",
+		DueAt:     &dueAt,
+		CreatedAt: time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+	}
+
+	status, due := ComputeStatus(c, nil, time.Date(2026, 2, 5, 14, 59, 0, 0, time.UTC))
+	if status != StatusPending {
+		t.Errorf("status before deadline = %q, want %q", status, StatusPending)
+	}
+	if due == nil || !due.Equal(dueAt) {
+		t.Errorf("due = %v, want %v", due, dueAt)
+	}
+
+	status, _ = ComputeStatus(c, nil, time.Date(2026, 2, 5, 15, 1, 0, 0, time.UTC))
+	if status != StatusOverdue {
+		t.Errorf("status after deadline = %q, want %q", status, StatusOverdue)
+	}
+
+	completed := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+	status, _ = ComputeStatus(c, &completed, time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC))
+	if status != StatusCompleted {
+		t.Errorf("status after completion = %q, want %q", status, StatusCompleted)
+	}
+}
+
+func TestDailyDueTime(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	c := model.Chore{
+		ID: 1, Title: "Make bed",
+		RecurrenceRule: "FREQ=DAILY",
+		DueTime:        "08:00",
+		CreatedAt:      time.Date(2026, 2, 1, 6, 0, 0, 0, ny),
+	}
+
+	status, due := ComputeStatus(c, nil, time.Date(2026, 2, 5, 7, 30, 0, 0, ny))
+	if status != StatusPending {
+		t.Errorf("status at 7:30 = %q, want %q", status, StatusPending)
+	}
+	if due == nil || !due.Equal(time.Date(2026, 2, 5, 0, 0, 0, 0, ny)) {
+		t.Errorf("due = %v, want Feb 5", due)
+	}
+	deadline := Deadline(c, due)
+	if deadline == nil || !deadline.Equal(time.Date(2026, 2, 5, 8, 0, 0, 0, ny)) {
+		t.Errorf("deadline = %v, want 8am Feb 5 in New York", deadline)
+	}
+
+	status, _ = ComputeStatus(c, nil, time.Date(2026, 2, 5, 8, 30, 0, 0, ny))
+	if status != StatusOverdue {
+		t.Errorf("status at 8:30 = %q, want %q", status, StatusOverdue)
+	}
+
+	completed := time.Date(2026, 2, 5, 8, 15, 0, 0, ny)
+	status, _ = ComputeStatus(c, &completed, time.Date(2026, 2, 5, 8, 30, 0, 0, ny))
+	if status != StatusCompleted {
+		t.Errorf("status after late completion = %q, want %q", status, StatusCompleted)
+	}
+
+	if Deadline(model.Chore{RecurrenceRule: "FREQ=DAILY"}, due) != nil {
+		t.Error("expected no deadline without a due time")
+	}
+}
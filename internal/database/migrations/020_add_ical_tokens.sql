@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- Tokens for read-only iCalendar feed subscriptions. Only a SHA-256 hash
+-- of each token is stored.
+CREATE TABLE ical_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    label TEXT NOT NULL DEFAULT '',
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    last_used_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_ical_tokens_household ON ical_tokens(household_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS ical_tokens;
@@ -0,0 +1,39 @@
+-- +goose Up
+
+-- Subscribed iCalendar URLs that are re-fetched on a schedule
+CREATE TABLE calendar_subscriptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    family_member_id INTEGER REFERENCES family_members(id) ON DELETE SET NULL,
+    last_synced_at DATETIME,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT (datetime('now')),
+    updated_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_calendar_subscriptions_household ON calendar_subscriptions(household_id);
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_calendar_subscriptions_updated_at
+AFTER UPDATE ON calendar_subscriptions
+FOR EACH ROW
+BEGIN
+    UPDATE calendar_subscriptions SET updated_at = datetime('now') WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- Imported events remember where they came from. source_uid is the iCalendar
+-- UID; subscription_id is NULL for one-off file imports.
+ALTER TABLE calendar_events ADD COLUMN subscription_id INTEGER REFERENCES calendar_subscriptions(id) ON DELETE CASCADE;
+ALTER TABLE calendar_events ADD COLUMN source_uid TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_calendar_events_source ON calendar_events(household_id, subscription_id, source_uid);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_calendar_events_source;
+ALTER TABLE calendar_events DROP COLUMN source_uid;
+ALTER TABLE calendar_events DROP COLUMN subscription_id;
+DROP TRIGGER IF EXISTS trg_calendar_subscriptions_updated_at;
+DROP TABLE IF EXISTS calendar_subscriptions;
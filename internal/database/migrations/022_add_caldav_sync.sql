@@ -0,0 +1,83 @@
+-- +goose Up
+
+-- External CalDAV calendars kept in two-way sync. A collection belongs to a
+-- family member, or to the household when family_member_id is NULL.
+CREATE TABLE caldav_collections (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    username TEXT NOT NULL DEFAULT '',
+    password TEXT NOT NULL DEFAULT '',
+    family_member_id INTEGER REFERENCES family_members(id) ON DELETE SET NULL,
+    ctag TEXT NOT NULL DEFAULT '',
+    last_synced_at DATETIME,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT (datetime('now')),
+    updated_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_caldav_collections_household ON caldav_collections(household_id);
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_caldav_collections_updated_at
+AFTER UPDATE ON caldav_collections
+FOR EACH ROW
+BEGIN
+    UPDATE caldav_collections SET updated_at = datetime('now') WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- Per-event sync state: which remote object an event series maps to, the
+-- last seen ETag, and whether the local copy changed since the last sync.
+-- event_id becomes NULL when the local event is deleted, which marks the
+-- remote object for deletion.
+CREATE TABLE caldav_objects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id INTEGER NOT NULL REFERENCES caldav_collections(id) ON DELETE CASCADE,
+    event_id INTEGER REFERENCES calendar_events(id) ON DELETE SET NULL,
+    href TEXT NOT NULL,
+    uid TEXT NOT NULL,
+    etag TEXT NOT NULL DEFAULT '',
+    dirty INTEGER NOT NULL DEFAULT 0,
+    synced_at DATETIME NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (collection_id, href)
+);
+
+CREATE UNIQUE INDEX idx_caldav_objects_event ON caldav_objects(event_id);
+
+-- Any change to a linked series, including its exceptions, marks it dirty.
+-- +goose StatementBegin
+CREATE TRIGGER trg_caldav_dirty_insert
+AFTER INSERT ON calendar_events
+FOR EACH ROW WHEN NEW.recurrence_parent_id IS NOT NULL
+BEGIN
+    UPDATE caldav_objects SET dirty = 1 WHERE event_id = NEW.recurrence_parent_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_caldav_dirty_update
+AFTER UPDATE ON calendar_events
+FOR EACH ROW
+BEGIN
+    UPDATE caldav_objects SET dirty = 1 WHERE event_id = COALESCE(NEW.recurrence_parent_id, NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_caldav_dirty_delete
+AFTER DELETE ON calendar_events
+FOR EACH ROW WHEN OLD.recurrence_parent_id IS NOT NULL
+BEGIN
+    UPDATE caldav_objects SET dirty = 1 WHERE event_id = OLD.recurrence_parent_id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_caldav_dirty_delete;
+DROP TRIGGER IF EXISTS trg_caldav_dirty_update;
+DROP TRIGGER IF EXISTS trg_caldav_dirty_insert;
+DROP TABLE IF EXISTS caldav_objects;
+DROP TRIGGER IF EXISTS trg_caldav_collections_updated_at;
+DROP TABLE IF EXISTS caldav_collections;
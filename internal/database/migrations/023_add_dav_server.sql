@@ -0,0 +1,30 @@
+-- +goose Up
+
+-- App-specific passwords let CalDAV clients sign in as a user without a
+-- session. Only a SHA-256 hash of the generated password is stored.
+CREATE TABLE app_passwords (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    label TEXT NOT NULL DEFAULT '',
+    password_hash TEXT NOT NULL UNIQUE,
+    last_used_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_app_passwords_user ON app_passwords(user_id, household_id);
+
+-- Resource names and UIDs chosen by CalDAV clients for the event series
+-- they create. Series without a row are served under a name derived from
+-- the event ID.
+CREATE TABLE dav_resources (
+    event_id INTEGER PRIMARY KEY REFERENCES calendar_events(id) ON DELETE CASCADE,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    uid TEXT NOT NULL,
+    UNIQUE (household_id, name)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS dav_resources;
+DROP TABLE IF EXISTS app_passwords;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Seed the household timezone; existing households keep the UTC behavior
+-- they had until they pick a timezone.
+INSERT OR IGNORE INTO settings (household_id, key, value)
+SELECT id, 'timezone', 'UTC' FROM households;
+
+-- +goose Down
+DELETE FROM settings WHERE key = 'timezone';
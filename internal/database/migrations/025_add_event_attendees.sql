@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- Family members taking part in an event, and how. calendar_events.family_member_id
+-- stays as the event's primary attendee, which per-member feeds and CalDAV
+-- collections are keyed on.
+CREATE TABLE event_attendees (
+    event_id INTEGER NOT NULL REFERENCES calendar_events(id) ON DELETE CASCADE,
+    family_member_id INTEGER NOT NULL REFERENCES family_members(id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'attendee' CHECK (role IN ('attendee', 'driver', 'optional')),
+    PRIMARY KEY (event_id, family_member_id)
+);
+
+CREATE INDEX idx_event_attendees_member ON event_attendees(family_member_id);
+
+INSERT INTO event_attendees (event_id, family_member_id, role)
+SELECT id, family_member_id, 'attendee' FROM calendar_events WHERE family_member_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_event_attendees_member;
+DROP TABLE IF EXISTS event_attendees;
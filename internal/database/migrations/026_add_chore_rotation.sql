@@ -0,0 +1,34 @@
+-- +goose Up
+
+-- A rotating chore passes between its rotation members in order. The cadence
+-- says when the turn moves on: 'occurrence' (each due date), 'weekly', or
+-- 'completion'. An empty cadence means the chore does not rotate.
+ALTER TABLE chores ADD COLUMN rotation_cadence TEXT NOT NULL DEFAULT ''
+    CHECK (rotation_cadence IN ('', 'occurrence', 'weekly', 'completion'));
+
+CREATE TABLE chore_rotation_members (
+    chore_id INTEGER NOT NULL REFERENCES chores(id) ON DELETE CASCADE,
+    family_member_id INTEGER NOT NULL REFERENCES family_members(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (chore_id, family_member_id)
+);
+
+-- Skipped and swapped turns, kept so whose turn it is can be explained.
+CREATE TABLE chore_rotation_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    chore_id INTEGER NOT NULL REFERENCES chores(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('skip', 'swap')),
+    turn INTEGER NOT NULL,
+    due_date TEXT NOT NULL DEFAULT '',
+    from_member_id INTEGER REFERENCES family_members(id) ON DELETE SET NULL,
+    to_member_id INTEGER REFERENCES family_members(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_chore_rotation_events_chore ON chore_rotation_events(chore_id, turn);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_chore_rotation_events_chore;
+DROP TABLE IF EXISTS chore_rotation_events;
+DROP TABLE IF EXISTS chore_rotation_members;
+ALTER TABLE chores DROP COLUMN rotation_cadence;
@@ -0,0 +1,21 @@
+-- +goose Up
+
+-- The recorded outcome of each occurrence of a recurring chore, keyed by its
+-- due date (YYYY-MM-DD in the household's timezone). Occurrences are closed
+-- out as 'done' or 'missed' once the next one falls due; 'excused' and
+-- 'skipped' are set by hand.
+CREATE TABLE chore_occurrences (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    chore_id INTEGER NOT NULL REFERENCES chores(id) ON DELETE CASCADE,
+    due_date TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('done', 'missed', 'excused', 'skipped')),
+    member_id INTEGER REFERENCES family_members(id) ON DELETE SET NULL,
+    completed_by INTEGER REFERENCES family_members(id) ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT (datetime('now')),
+    updated_at DATETIME NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (chore_id, due_date)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS chore_occurrences;
@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- due_at is when a one-off chore must be done by. due_time is the time of
+-- day ("08:00") each occurrence of a recurring chore must be done by, on the
+-- household's clock; empty means any time that day.
+ALTER TABLE chores ADD COLUMN due_at DATETIME;
+ALTER TABLE chores ADD COLUMN due_time TEXT NOT NULL DEFAULT '';
+
+-- The family member a device belongs to, so per-chore reminders reach
+-- whoever the chore is for.
+ALTER TABLE push_subscriptions ADD COLUMN family_member_id INTEGER REFERENCES family_members(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE push_subscriptions DROP COLUMN family_member_id;
+ALTER TABLE chores DROP COLUMN due_time;
+ALTER TABLE chores DROP COLUMN due_at;
@@ -0,0 +1,22 @@
+-- +goose Up
+
+-- Completions of a chore that requires approval start out pending and earn
+-- no points until a parent approves them. Rejected completions are kept for
+-- the record but do not count as the chore being done.
+ALTER TABLE chores ADD COLUMN requires_approval INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE chore_completions ADD COLUMN status TEXT NOT NULL DEFAULT 'approved'
+    CHECK (status IN ('pending', 'approved', 'rejected'));
+ALTER TABLE chore_completions ADD COLUMN reviewed_by INTEGER REFERENCES family_members(id) ON DELETE SET NULL;
+ALTER TABLE chore_completions ADD COLUMN reviewed_at DATETIME;
+ALTER TABLE chore_completions ADD COLUMN review_comment TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_chore_completions_status ON chore_completions(status);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_chore_completions_status;
+ALTER TABLE chore_completions DROP COLUMN review_comment;
+ALTER TABLE chore_completions DROP COLUMN reviewed_at;
+ALTER TABLE chore_completions DROP COLUMN reviewed_by;
+ALTER TABLE chore_completions DROP COLUMN status;
+ALTER TABLE chores DROP COLUMN requires_approval;
@@ -0,0 +1,36 @@
+-- +goose Up
+
+-- Every change to a member's points: earned for a chore, spent on a reward,
+-- or adjusted by hand as a bonus, penalty or correction. Amounts are signed
+-- and a member's balance is the sum of their transactions. The actor is the
+-- member who made the change, if known. Entries outlive the completion or
+-- redemption they came from, so deleting a chore or reward keeps the points.
+CREATE TABLE point_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    family_member_id INTEGER NOT NULL REFERENCES family_members(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('earn', 'spend', 'bonus', 'penalty', 'correction')),
+    amount INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    actor_id INTEGER REFERENCES family_members(id) ON DELETE SET NULL,
+    completion_id INTEGER REFERENCES chore_completions(id) ON DELETE SET NULL,
+    redemption_id INTEGER REFERENCES reward_redemptions(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_point_transactions_member ON point_transactions(household_id, family_member_id, created_at);
+CREATE INDEX idx_point_transactions_completion ON point_transactions(completion_id);
+
+-- Carry over the points already earned and spent.
+INSERT INTO point_transactions (household_id, family_member_id, kind, amount, reason, actor_id, completion_id, created_at)
+    SELECT ch.household_id, cc.completed_by, 'earn', cc.points_earned, ch.title, COALESCE(cc.reviewed_by, cc.completed_by), cc.id, COALESCE(cc.reviewed_at, cc.completed_at)
+    FROM chore_completions cc JOIN chores ch ON ch.id = cc.chore_id
+    WHERE cc.completed_by IS NOT NULL AND cc.points_earned != 0;
+
+INSERT INTO point_transactions (household_id, family_member_id, kind, amount, reason, actor_id, redemption_id, created_at)
+    SELECT r.household_id, rr.redeemed_by, 'spend', -rr.points_spent, r.title, rr.redeemed_by, rr.id, rr.redeemed_at
+    FROM reward_redemptions rr JOIN rewards r ON r.id = rr.reward_id
+    WHERE rr.redeemed_by IS NOT NULL AND rr.points_spent != 0;
+
+-- +goose Down
+DROP TABLE IF EXISTS point_transactions;
@@ -0,0 +1,31 @@
+-- +goose Up
+
+-- A reward can be limited to a number in stock, taken off each time it is
+-- redeemed, and to once per member every so many days. Unlimited rewards
+-- have no stock.
+ALTER TABLE rewards ADD COLUMN stock INTEGER;
+ALTER TABLE rewards ADD COLUMN cooldown_days INTEGER NOT NULL DEFAULT 0;
+
+-- Redemptions start out requested and are approved by a parent, then
+-- fulfilled once the reward has been handed over. Cancelled redemptions
+-- are refunded. Redemptions made before this were handed over on the spot.
+ALTER TABLE reward_redemptions ADD COLUMN status TEXT NOT NULL DEFAULT 'fulfilled'
+    CHECK (status IN ('requested', 'approved', 'fulfilled', 'cancelled'));
+ALTER TABLE reward_redemptions ADD COLUMN reviewed_by INTEGER REFERENCES family_members(id) ON DELETE SET NULL;
+ALTER TABLE reward_redemptions ADD COLUMN reviewed_at DATETIME;
+ALTER TABLE reward_redemptions ADD COLUMN fulfilled_by INTEGER REFERENCES family_members(id) ON DELETE SET NULL;
+ALTER TABLE reward_redemptions ADD COLUMN fulfilled_at DATETIME;
+ALTER TABLE reward_redemptions ADD COLUMN note TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_reward_redemptions_status ON reward_redemptions(status);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_reward_redemptions_status;
+ALTER TABLE reward_redemptions DROP COLUMN note;
+ALTER TABLE reward_redemptions DROP COLUMN fulfilled_at;
+ALTER TABLE reward_redemptions DROP COLUMN fulfilled_by;
+ALTER TABLE reward_redemptions DROP COLUMN reviewed_at;
+ALTER TABLE reward_redemptions DROP COLUMN reviewed_by;
+ALTER TABLE reward_redemptions DROP COLUMN status;
+ALTER TABLE rewards DROP COLUMN cooldown_days;
+ALTER TABLE rewards DROP COLUMN stock;
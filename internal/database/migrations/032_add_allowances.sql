@@ -0,0 +1,74 @@
+-- +goose Up
+
+-- Weekly allowances are paid into the point ledger, so it needs a kind for
+-- them. SQLite cannot change a CHECK constraint, so rebuild the table.
+CREATE TABLE point_transactions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    family_member_id INTEGER NOT NULL REFERENCES family_members(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('earn', 'spend', 'bonus', 'penalty', 'correction', 'allowance')),
+    amount INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    actor_id INTEGER REFERENCES family_members(id) ON DELETE SET NULL,
+    completion_id INTEGER REFERENCES chore_completions(id) ON DELETE SET NULL,
+    redemption_id INTEGER REFERENCES reward_redemptions(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+INSERT INTO point_transactions_new (id, household_id, family_member_id, kind, amount, reason, actor_id, completion_id, redemption_id, created_at)
+    SELECT id, household_id, family_member_id, kind, amount, reason, actor_id, completion_id, redemption_id, created_at FROM point_transactions;
+DROP TABLE point_transactions;
+ALTER TABLE point_transactions_new RENAME TO point_transactions;
+CREATE INDEX idx_point_transactions_member ON point_transactions(household_id, family_member_id, created_at);
+CREATE INDEX idx_point_transactions_completion ON point_transactions(completion_id);
+
+-- What a member's points are worth in money, and the points paid to them
+-- every week on the given weekday (0 is Sunday). Deposits start from the
+-- date the allowance was set up; last_deposit is the latest deposit day
+-- paid, so none is paid twice.
+CREATE TABLE member_allowances (
+    family_member_id INTEGER PRIMARY KEY REFERENCES family_members(id) ON DELETE CASCADE,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    cents_per_point INTEGER NOT NULL DEFAULT 0,
+    weekly_points INTEGER NOT NULL DEFAULT 0,
+    weekday INTEGER NOT NULL DEFAULT 6 CHECK (weekday BETWEEN 0 AND 6),
+    starts_on TEXT NOT NULL,
+    last_deposit TEXT
+);
+
+CREATE INDEX idx_member_allowances_household ON member_allowances(household_id);
+
+-- Something a member is saving their points towards.
+CREATE TABLE savings_goals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    family_member_id INTEGER NOT NULL REFERENCES family_members(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    target_points INTEGER NOT NULL CHECK (target_points > 0),
+    created_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_savings_goals_member ON savings_goals(household_id, family_member_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS savings_goals;
+DROP TABLE IF EXISTS member_allowances;
+
+-- Allowance deposits become bonuses under the old constraint.
+CREATE TABLE point_transactions_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    family_member_id INTEGER NOT NULL REFERENCES family_members(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('earn', 'spend', 'bonus', 'penalty', 'correction')),
+    amount INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    actor_id INTEGER REFERENCES family_members(id) ON DELETE SET NULL,
+    completion_id INTEGER REFERENCES chore_completions(id) ON DELETE SET NULL,
+    redemption_id INTEGER REFERENCES reward_redemptions(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+INSERT INTO point_transactions_old (id, household_id, family_member_id, kind, amount, reason, actor_id, completion_id, redemption_id, created_at)
+    SELECT id, household_id, family_member_id, CASE WHEN kind = 'allowance' THEN 'bonus' ELSE kind END, amount, reason, actor_id, completion_id, redemption_id, created_at FROM point_transactions;
+DROP TABLE point_transactions;
+ALTER TABLE point_transactions_old RENAME TO point_transactions;
+CREATE INDEX idx_point_transactions_member ON point_transactions(household_id, family_member_id, created_at);
+CREATE INDEX idx_point_transactions_completion ON point_transactions(completion_id);
@@ -0,0 +1,35 @@
+-- +goose Up
+
+-- A set of chores a household saved to reuse. Another household can start
+-- out with the same chores by giving the share code when it is created.
+CREATE TABLE chore_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    share_code TEXT NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_chore_templates_household ON chore_templates(household_id);
+
+-- The chores in a template. Each household has its own areas, so the area
+-- is kept by name, and nobody is assigned.
+CREATE TABLE chore_template_chores (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    template_id INTEGER NOT NULL REFERENCES chore_templates(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    area TEXT NOT NULL DEFAULT '',
+    points INTEGER NOT NULL DEFAULT 0,
+    recurrence_rule TEXT NOT NULL DEFAULT '',
+    due_time TEXT NOT NULL DEFAULT '',
+    requires_approval INTEGER NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_chore_template_chores_template ON chore_template_chores(template_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS chore_template_chores;
+DROP TABLE IF EXISTS chore_templates;
@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- A member's birthdate, "2006-01-02", and role are both optional. Without
+-- a role, one is worked out from the birthdate. points_percent overrides
+-- the multiplier the role gives the points the member earns.
+ALTER TABLE family_members ADD COLUMN birthdate TEXT;
+ALTER TABLE family_members ADD COLUMN role TEXT NOT NULL DEFAULT ''
+    CHECK (role IN ('', 'adult', 'teen', 'child'));
+ALTER TABLE family_members ADD COLUMN points_percent INTEGER CHECK (points_percent > 0);
+
+-- The youngest a member can be to take on a chore; 0 is any age.
+ALTER TABLE chores ADD COLUMN min_age INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE chore_template_chores ADD COLUMN min_age INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE chore_template_chores DROP COLUMN min_age;
+ALTER TABLE chores DROP COLUMN min_age;
+ALTER TABLE family_members DROP COLUMN points_percent;
+ALTER TABLE family_members DROP COLUMN role;
+ALTER TABLE family_members DROP COLUMN birthdate;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Lists that are not in use, such as a seasonal one, can be archived rather
+-- than deleted. Archived lists keep their items but are left out of the
+-- list switcher and the dashboard.
+ALTER TABLE grocery_lists ADD COLUMN archived INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE grocery_lists DROP COLUMN archived;
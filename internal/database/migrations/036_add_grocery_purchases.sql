@@ -0,0 +1,24 @@
+-- +goose Up
+
+-- Every item checked off a grocery list, kept after the item itself is
+-- cleared so the household's buying habits can be learned. item_id links a
+-- purchase to the item while it is still on the list, so unchecking it
+-- by mistake takes the purchase back out.
+CREATE TABLE grocery_purchases (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    list_id INTEGER REFERENCES grocery_lists(id) ON DELETE SET NULL,
+    item_id INTEGER REFERENCES grocery_items(id) ON DELETE SET NULL,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT 'Other',
+    quantity TEXT NOT NULL DEFAULT '',
+    unit TEXT NOT NULL DEFAULT '',
+    purchased_by INTEGER REFERENCES family_members(id) ON DELETE SET NULL,
+    purchased_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_grocery_purchases_list ON grocery_purchases(household_id, list_id, purchased_at);
+CREATE INDEX idx_grocery_purchases_item ON grocery_purchases(item_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS grocery_purchases;
@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- The category a household has chosen for an item, by its normalized name,
+-- learned whenever someone moves an item to a different category. These
+-- win over the built-in categorization.
+CREATE TABLE grocery_category_rules (
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    item_key TEXT NOT NULL,
+    category TEXT NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (household_id, item_key)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS grocery_category_rules;
@@ -0,0 +1,29 @@
+-- +goose Up
+
+-- A store the household shops at. Its sections are the household's
+-- grocery categories in the order you walk past them there, each with an
+-- optional aisle. Categories without a section come after the rest.
+CREATE TABLE grocery_stores (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_grocery_stores_household ON grocery_stores(household_id);
+
+CREATE TABLE grocery_store_sections (
+    store_id INTEGER NOT NULL REFERENCES grocery_stores(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES grocery_categories(id) ON DELETE CASCADE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    aisle TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (store_id, category_id)
+);
+
+-- A list can be shopped at one store, and is sorted in its walking order.
+ALTER TABLE grocery_lists ADD COLUMN store_id INTEGER REFERENCES grocery_stores(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE grocery_lists DROP COLUMN store_id;
+DROP TABLE IF EXISTS grocery_store_sections;
+DROP TABLE IF EXISTS grocery_stores;
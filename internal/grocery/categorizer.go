@@ -0,0 +1,30 @@
+package grocery
+
+import (
+	"slices"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+// Categorizer categorizes items for one household. The categories it has
+// chosen for items before win over the built-in tables, and built-in
+// categories the household no longer has become Other.
+type Categorizer struct {
+	// Learned maps item keys, see ItemKey, to categories.
+	Learned map[string]string
+	// Categories are the household's category names. If empty, any
+	// built-in category is used as is.
+	Categories []string
+}
+
+// Categorize returns the category for the given item name.
+func (c Categorizer) Categorize(itemName string) string {
+	if cat, ok := c.Learned[ItemKey(itemName)]; ok {
+		return cat
+	}
+	cat := Categorize(itemName)
+	if len(c.Categories) == 0 || slices.Contains(c.Categories, cat) {
+		return cat
+	}
+	return model.GroceryCategoryOther
+}
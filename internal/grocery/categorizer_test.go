@@ -0,0 +1,28 @@
+package grocery
+
+import "testing"
+
+func TestCategorizer(t *testing.T) {
+	c := Categorizer{
+		Learned:    map[string]string{"oat milk": "Pantry"},
+		Categories: []string{"Produce", "Dairy", "Pantry", "Other"},
+	}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Oat  Milk", "Pantry"},    // learned
+		{"milk", "Dairy"},          // built in
+		{"chicken", "Other"},       // built in, but the household has no Meat & Seafood
+		{"something odd", "Other"}, // unknown
+	}
+	for _, tt := range tests {
+		if got := c.Categorize(tt.input); got != tt.want {
+			t.Errorf("Categorize(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	if got := (Categorizer{}).Categorize("chicken"); got != "Meat & Seafood" {
+		t.Errorf("zero Categorizer = %q, want the built-in category", got)
+	}
+}
@@ -0,0 +1,395 @@
+package grocery
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Quantity is an amount of something in a normalized unit, such as 1.5
+// "lb". Unit is "" for a plain count.
+type Quantity struct {
+	Amount float64
+	Unit   string
+}
+
+// String formats the quantity as it is written, such as "1.5 lb" or "2".
+func (q Quantity) String() string {
+	if q.Unit == "" {
+		return FormatAmount(q.Amount)
+	}
+	return FormatAmount(q.Amount) + " " + q.Unit
+}
+
+// FormatAmount writes an amount with at most two decimals and no trailing
+// zeros, such as "2" or "1.25".
+func FormatAmount(amount float64) string {
+	return strconv.FormatFloat(math.Round(amount*100)/100, 'f', -1, 64)
+}
+
+// unitAliases maps the ways units are written to their normalized form.
+var unitAliases = map[string]string{
+	"g": "g", "gram": "g", "grams": "g",
+	"kg": "kg", "kgs": "kg", "kilo": "kg", "kilos": "kg", "kilogram": "kg", "kilograms": "kg",
+	"oz": "oz", "ounce": "oz", "ounces": "oz",
+	"lb": "lb", "lbs": "lb", "pound": "lb", "pounds": "lb",
+	"ml": "ml", "milliliter": "ml", "milliliters": "ml", "millilitre": "ml", "millilitres": "ml",
+	"l": "l", "liter": "l", "liters": "l", "litre": "l", "litres": "l",
+	"tsp": "tsp", "teaspoon": "tsp", "teaspoons": "tsp",
+	"tbsp": "tbsp", "tablespoon": "tbsp", "tablespoons": "tbsp",
+	"fl oz": "fl oz", "floz": "fl oz",
+	"cup": "cup", "cups": "cup",
+	"pt": "pt", "pint": "pt", "pints": "pt",
+	"qt": "qt", "quart": "qt", "quarts": "qt",
+	"gal": "gal", "gallon": "gal", "gallons": "gal",
+	"can": "can", "cans": "can", "tin": "can", "tins": "can",
+	"bottle": "bottle", "bottles": "bottle",
+	"box": "box", "boxes": "box",
+	"bag": "bag", "bags": "bag",
+	"pack": "pack", "packs": "pack", "pk": "pack", "package": "pack", "packages": "pack",
+	"jar": "jar", "jars": "jar",
+	"carton": "carton", "cartons": "carton",
+	"bunch": "bunch", "bunches": "bunch",
+	"head": "head", "heads": "head",
+	"loaf": "loaf", "loaves": "loaf",
+	"roll": "roll", "rolls": "roll",
+	"ea": "", "each": "", "ct": "", "count": "", "pc": "", "pcs": "", "piece": "", "pieces": "",
+}
+
+// NormalizeUnit returns the normalized form of a unit, such as "lb" for
+// "Pounds", and false if it is not a known unit. The empty unit is a count.
+func NormalizeUnit(unit string) (string, bool) {
+	unit = strings.Join(strings.Fields(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(unit), "."))), " ")
+	if unit == "" {
+		return "", true
+	}
+	u, ok := unitAliases[unit]
+	return u, ok
+}
+
+// Units of weight and volume in grams and milliliters, so quantities in
+// different units of the same kind can be added up.
+var (
+	gramsPer = map[string]float64{"g": 1, "kg": 1000, "oz": 28.3495, "lb": 453.592}
+	mlPer    = map[string]float64{"ml": 1, "l": 1000, "tsp": 4.92892, "tbsp": 14.7868, "fl oz": 29.5735, "cup": 236.588, "pt": 473.176, "qt": 946.353, "gal": 3785.41}
+)
+
+// Add returns a plus b in a's unit, and false if they are not in units of
+// the same kind, such as pounds and cans. A plain count adds to a count of
+// packages, so 3 cans and 2 are 5 cans, but not to a weight or volume.
+func Add(a, b Quantity) (Quantity, bool) {
+	switch {
+	case a.Unit == b.Unit:
+		return Quantity{Amount: a.Amount + b.Amount, Unit: a.Unit}, true
+	case a.Unit == "" && isPackage(b.Unit):
+		return Quantity{Amount: a.Amount + b.Amount, Unit: b.Unit}, true
+	case b.Unit == "" && isPackage(a.Unit):
+		return Quantity{Amount: a.Amount + b.Amount, Unit: a.Unit}, true
+	}
+	for _, per := range []map[string]float64{gramsPer, mlPer} {
+		fromA, okA := per[a.Unit]
+		fromB, okB := per[b.Unit]
+		if okA && okB {
+			return Quantity{Amount: a.Amount + b.Amount*fromB/fromA, Unit: a.Unit}, true
+		}
+	}
+	return Quantity{}, false
+}
+
+// isPackage reports whether unit counts things, such as cans or boxes,
+// rather than measuring them.
+func isPackage(unit string) bool {
+	_, weight := gramsPer[unit]
+	_, volume := mlPer[unit]
+	return unit != "" && !weight && !volume
+}
+
+// numberWords are amounts written as words.
+var numberWords = map[string]float64{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+	"half": 0.5, "½": 0.5, "¼": 0.25, "¾": 0.75,
+}
+
+// vulgarFractions are the fractions written as one character, which may be
+// written against a whole number, as in "2½".
+const vulgarFractions = "½¼¾"
+
+// plainNumber is an amount in decimal digits, with a decimal point or comma.
+var plainNumber = regexp.MustCompile(`^\d+(?:[.,]\d+)?$`)
+
+// gluedUnit splits an amount from a unit or "x" written against it, as in
+// "1.5lb", "x6" and "6x".
+var gluedUnit = regexp.MustCompile(`^(?:(\d+(?:[.,]\d+)?|\d+/\d+)([a-z]+)|(x)(\d+(?:[.,]\d+)?))$`)
+
+// tokenize splits s into words, separating amounts from units and count
+// markers written against them.
+func tokenize(s string) []string {
+	var tokens []string
+	for _, f := range strings.Fields(strings.ReplaceAll(s, "×", "x")) {
+		// "2½" is 2 and ½.
+		if i := strings.IndexAny(f, vulgarFractions); i > 0 && plainNumber.MatchString(f[:i]) {
+			tokens = append(tokens, f[:i])
+			f = f[i:]
+		}
+		m := gluedUnit.FindStringSubmatch(strings.ToLower(f))
+		switch {
+		case m == nil:
+			tokens = append(tokens, f)
+		case m[1] != "":
+			if _, ok := NormalizeUnit(m[2]); !ok && m[2] != "x" {
+				tokens = append(tokens, f)
+				continue
+			}
+			tokens = append(tokens, m[1], f[len(m[1]):])
+		default:
+			tokens = append(tokens, "x", m[4])
+		}
+	}
+	return tokens
+}
+
+// parseNumber parses a single amount token: "2", "1.5", "1,5", "1/2" or a
+// number word. Numbers are plain decimal digits, so "1e3" is not one.
+func parseNumber(token string) (float64, bool) {
+	token = strings.ToLower(token)
+	if n, ok := numberWords[token]; ok {
+		return n, true
+	}
+	if num, den, ok := strings.Cut(token, "/"); ok {
+		if !plainNumber.MatchString(num) || !plainNumber.MatchString(den) {
+			return 0, false
+		}
+		n, _ := parseNumber(num)
+		d, _ := parseNumber(den)
+		if d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	if !plainNumber.MatchString(token) {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(strings.Replace(token, ",", ".", 1), 64)
+	if err != nil || math.IsInf(n, 0) {
+		return 0, false
+	}
+	return n, true
+}
+
+// isFraction reports whether token is a fraction that can follow a whole
+// number, as in "1 1/2" or "2 ½".
+func isFraction(token string) bool {
+	return strings.Contains(token, "/") || (token != "" && strings.Trim(token, vulgarFractions) == "")
+}
+
+// parseAmount reads an amount from the start of tokens, such as "2",
+// "1 1/2", "x6", "6 x", "a dozen" or "half a dozen", and returns how many
+// tokens it used, or 0 if there is none.
+func parseAmount(tokens []string) (float64, int) {
+	i := 0
+	word := func() string {
+		if i < len(tokens) {
+			return strings.ToLower(tokens[i])
+		}
+		return ""
+	}
+	if word() == "x" {
+		i++
+	}
+	amount, ok := parseNumber(word())
+	if !ok {
+		// "dozen eggs" is a dozen of them.
+		if word() == "dozen" {
+			return 12, i + 1
+		}
+		return 0, 0
+	}
+	// "half" is only an amount of something measured: "half gallon
+	// milk", but not "half and half".
+	if word() == "half" && i+1 < len(tokens) {
+		next := tokens[i+1:]
+		if len(next) > 1 && isArticle(next[0]) {
+			next = next[1:]
+		}
+		if _, m := parseUnit(next); m == 0 && !strings.EqualFold(next[0], "dozen") {
+			return 0, 0
+		}
+	}
+	i++
+	// A whole number and a fraction: "1 1/2", "2 ½" or "2½".
+	if amount == math.Trunc(amount) && isFraction(word()) {
+		if frac, ok := parseNumber(word()); ok && frac < 1 {
+			amount += frac
+			i++
+		}
+	}
+	// "half a dozen"
+	if amount == 0.5 && isArticle(word()) && i+1 < len(tokens) && strings.EqualFold(tokens[i+1], "dozen") {
+		i++
+	}
+	if word() == "dozen" {
+		amount *= 12
+		i++
+	}
+	if word() == "x" {
+		i++
+	}
+	return amount, i
+}
+
+func isArticle(token string) bool {
+	return strings.EqualFold(token, "a") || strings.EqualFold(token, "an")
+}
+
+// parseUnit reads a unit from the start of tokens and returns it
+// normalized, with how many tokens it used, or 0 if there is none.
+func parseUnit(tokens []string) (string, int) {
+	if len(tokens) >= 2 {
+		if u, ok := NormalizeUnit(tokens[0] + " " + tokens[1]); ok {
+			return u, 2
+		}
+	}
+	if len(tokens) >= 1 {
+		if u, ok := NormalizeUnit(tokens[0]); ok && tokens[0] != "" {
+			return u, 1
+		}
+	}
+	return "", 0
+}
+
+// ParseQuantity parses a quantity such as "2", "1.5 lb", "x6" or "a
+// dozen". It returns false if s is not a quantity on its own.
+func ParseQuantity(s string) (Quantity, bool) {
+	tokens := tokenize(s)
+	amount, n := parseAmount(tokens)
+	if n == 0 {
+		return Quantity{}, false
+	}
+	unit, m := parseUnit(tokens[n:])
+	if n+m != len(tokens) {
+		return Quantity{}, false
+	}
+	return Quantity{Amount: amount, Unit: unit}, true
+}
+
+// itemQuantity parses an item's quantity and unit fields. An item with no
+// quantity counts as one of it.
+func itemQuantity(quantity, unit string) (Quantity, bool) {
+	quantity = strings.TrimSpace(quantity)
+	if quantity == "" {
+		if u, ok := NormalizeUnit(unit); ok {
+			return Quantity{Amount: 1, Unit: u}, true
+		}
+		return Quantity{}, false
+	}
+	return ParseQuantity(quantity + " " + unit)
+}
+
+// MergeQuantities adds the quantity and unit of an item being added to
+// those of the same item already on a list, and returns what the existing
+// item should have. The result keeps the existing item's unit as written.
+// If one of them has no quantity and the other is measured in a unit, the
+// measured one is kept. It returns false if they cannot be added up, such
+// as pounds and cans or a quantity that is not a number.
+func MergeQuantities(quantity, unit, addQuantity, addUnit string) (string, string, bool) {
+	have, ok := itemQuantity(quantity, unit)
+	if !ok {
+		return "", "", false
+	}
+	add, ok := itemQuantity(addQuantity, addUnit)
+	if !ok {
+		return "", "", false
+	}
+
+	hasQuantity, addHasQuantity := strings.TrimSpace(quantity) != "", strings.TrimSpace(addQuantity) != ""
+	switch {
+	case !addHasQuantity && have.Unit != "":
+		return quantity, unit, true
+	case !hasQuantity && add.Unit != "":
+		q, u := written(add, addUnit)
+		return q, u, true
+	}
+
+	sum, ok := Add(have, add)
+	if !ok {
+		return "", "", false
+	}
+	if have.Unit != sum.Unit {
+		// A plain count took on the unit of the packages added to it.
+		unit = addUnit
+	}
+	q, u := written(sum, unit)
+	return q, u, true
+}
+
+// written returns the quantity and unit fields for q, where unit is how
+// the unit was written. A unit that was written in the quantity field, as
+// in "2 lb", stays there.
+func written(q Quantity, unit string) (string, string) {
+	if strings.TrimSpace(unit) == "" {
+		return q.String(), ""
+	}
+	return FormatAmount(q.Amount), unit
+}
+
+// Item is an item as typed into quick-add, such as "3 cans black beans
+// (low sodium)": Name "black beans", Quantity "3", Unit "can" and Notes
+// "low sodium".
+type Item struct {
+	Name     string
+	Quantity string
+	Unit     string
+	Notes    string
+}
+
+var parenthesized = regexp.MustCompile(`\(([^()]*)\)`)
+
+// ParseItem reads a quantity, unit, name and notes from quick-add text.
+// The quantity comes first, as in "2 lb chicken" or "a dozen eggs", or
+// last, as in "eggs x12". Anything in parentheses is a note. Text without
+// a quantity is all name, and text with nothing left for a name is kept
+// as it was typed.
+func ParseItem(s string) Item {
+	var item Item
+	var notes []string
+	for _, m := range parenthesized.FindAllStringSubmatch(s, -1) {
+		if note := strings.TrimSpace(m[1]); note != "" {
+			notes = append(notes, note)
+		}
+	}
+	item.Notes = strings.Join(notes, "; ")
+	rest := strings.TrimSpace(parenthesized.ReplaceAllString(s, " "))
+
+	tokens := tokenize(rest)
+	name := tokens
+	if amount, n := parseAmount(tokens); n > 0 && n < len(tokens) {
+		unit, m := parseUnit(tokens[n:])
+		if n+m < len(tokens) && strings.EqualFold(tokens[n+m], "of") {
+			m++
+		}
+		if n+m < len(tokens) {
+			item.Quantity, item.Unit, name = FormatAmount(amount), unit, tokens[n+m:]
+		}
+	} else {
+		// The quantity may come last: "eggs x12", "chicken 2 lb".
+		for i := 1; i < len(tokens); i++ {
+			tail := strings.Join(tokens[i:], " ")
+			if !strings.ContainsAny(tail, "0123456789") && !strings.Contains(strings.ToLower(tail), "dozen") {
+				// "vitamin a" is not one vitamin.
+				continue
+			}
+			if q, ok := ParseQuantity(tail); ok {
+				item.Quantity, item.Unit, name = FormatAmount(q.Amount), q.Unit, tokens[:i]
+				break
+			}
+		}
+	}
+
+	item.Name = strings.Trim(strings.Join(name, " "), " ,;:-")
+	if item.Name == "" {
+		return Item{Name: strings.Join(strings.Fields(s), " ")}
+	}
+	return item
+}
@@ -0,0 +1,110 @@
+package grocery
+
+import "testing"
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Quantity
+		ok   bool
+	}{
+		{"2", Quantity{2, ""}, true},
+		{"1.5 lb", Quantity{1.5, "lb"}, true},
+		{"1.5lbs", Quantity{1.5, "lb"}, true},
+		{"x6", Quantity{6, ""}, true},
+		{"6x", Quantity{6, ""}, true},
+		{"a dozen", Quantity{12, ""}, true},
+		{"2 dozen", Quantity{24, ""}, true},
+		{"half a dozen", Quantity{6, ""}, true},
+		{"1 1/2 cups", Quantity{1.5, "cup"}, true},
+		{"3 Gallons", Quantity{3, "gal"}, true},
+		{"12 fl oz", Quantity{12, "fl oz"}, true},
+		{"4 each", Quantity{4, ""}, true},
+		{"", Quantity{}, false},
+		{"some", Quantity{}, false},
+		{"2 handfuls", Quantity{}, false},
+		{"2½ cups", Quantity{2.5, "cup"}, true},
+		{"1e3", Quantity{}, false},
+		{"1_000", Quantity{}, false},
+		{"0x10", Quantity{}, false},
+		{"1/2 1/2", Quantity{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseQuantity(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseQuantity(%q) = %+v, %v, want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMergeQuantities(t *testing.T) {
+	tests := []struct {
+		quantity, unit, addQuantity, addUnit string
+		wantQuantity, wantUnit               string
+		ok                                   bool
+	}{
+		// No quantity counts as one.
+		{"", "", "", "", "2", "", true},
+		{"2", "", "x6", "", "8", "", true},
+		{"1", "gallon", "2", "gal", "3", "gallon", true},
+		// Different units of the same kind are added in the existing unit.
+		{"1", "lb", "8", "oz", "1.5", "lb", true},
+		{"1 lb", "", "8 oz", "", "1.5 lb", "", true},
+		{"1", "qt", "2", "cups", "1.5", "qt", true},
+		// A measured quantity is kept over none.
+		{"2", "lb", "", "", "2", "lb", true},
+		{"", "", "3", "cans", "3", "cans", true},
+		// A plain count adds to packages, but not to weights.
+		{"3", "cans", "x2", "", "5", "cans", true},
+		{"2", "", "1 box", "", "3 box", "", true},
+		{"2", "lb", "3", "", "", "", false},
+		{"2", "lb", "1", "can", "", "", false},
+		{"a few", "", "2", "", "", "", false},
+	}
+	for _, tt := range tests {
+		q, u, ok := MergeQuantities(tt.quantity, tt.unit, tt.addQuantity, tt.addUnit)
+		if ok != tt.ok || q != tt.wantQuantity || u != tt.wantUnit {
+			t.Errorf("MergeQuantities(%q, %q, %q, %q) = %q, %q, %v, want %q, %q, %v",
+				tt.quantity, tt.unit, tt.addQuantity, tt.addUnit, q, u, ok, tt.wantQuantity, tt.wantUnit, tt.ok)
+		}
+	}
+}
+
+func TestParseItem(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Item
+	}{
+		{"3 cans black beans (low sodium)", Item{Name: "black beans", Quantity: "3", Unit: "can", Notes: "low sodium"}},
+		{"2 lb chicken thighs", Item{Name: "chicken thighs", Quantity: "2", Unit: "lb"}},
+		{"1.5kg flour", Item{Name: "flour", Quantity: "1.5", Unit: "kg"}},
+		{"a dozen eggs", Item{Name: "eggs", Quantity: "12"}},
+		{"2 bags of rice", Item{Name: "rice", Quantity: "2", Unit: "bag"}},
+		{"half gallon milk", Item{Name: "milk", Quantity: "0.5", Unit: "gal"}},
+		{"Eggs x12", Item{Name: "Eggs", Quantity: "12"}},
+		{"ground beef 1 lb", Item{Name: "ground beef", Quantity: "1", Unit: "lb"}},
+		{"4 apples", Item{Name: "apples", Quantity: "4"}},
+		{"Milk", Item{Name: "Milk"}},
+		{"half and half", Item{Name: "half and half"}},
+		{"vitamin a", Item{Name: "vitamin a"}},
+		{"7up", Item{Name: "7up"}},
+		{"2 lb", Item{Name: "2 lb"}},
+		{"bread (sourdough) (sliced)", Item{Name: "bread", Notes: "sourdough; sliced"}},
+		// A whole number and a fraction are one quantity.
+		{"2 1/2 cups flour", Item{Name: "flour", Quantity: "2.5", Unit: "cup"}},
+		{"2 ½ cups sugar", Item{Name: "sugar", Quantity: "2.5", Unit: "cup"}},
+		{"2½ cups sugar", Item{Name: "sugar", Quantity: "2.5", Unit: "cup"}},
+		{"flour 2 1/2 cups", Item{Name: "flour", Quantity: "2.5", Unit: "cup"}},
+		// Only plain decimal digits are numbers.
+		{"1e3 eggs", Item{Name: "1e3 eggs"}},
+		{"eggs x1e3", Item{Name: "eggs x1e3"}},
+		// With nothing left for a name, the text is kept as typed.
+		{"(organic)", Item{Name: "(organic)"}},
+		{"2 lb -", Item{Name: "2 lb -"}},
+	}
+	for _, tt := range tests {
+		if got := ParseItem(tt.in); got != tt.want {
+			t.Errorf("ParseItem(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
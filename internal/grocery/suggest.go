@@ -0,0 +1,172 @@
+package grocery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+// HistoryWindow is how far back purchases are looked at. Habits older than
+// this no longer say much about what the household buys now.
+const HistoryWindow = 365 * 24 * time.Hour
+
+// DueRatio is how much of an item's usual interval has to have passed since
+// it was last bought for it to be suggested, so it comes up a little before
+// it runs out.
+const DueRatio = 0.8
+
+// Suggestion is an item the household has bought before, with how often
+// they buy it.
+type Suggestion struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	// Purchases counts the days the item was bought on.
+	Purchases     int       `json:"purchases"`
+	LastPurchased time.Time `json:"last_purchased"`
+	// IntervalDays is the usual number of days between purchases, or 0 if
+	// the item has only been bought once.
+	IntervalDays int `json:"interval_days"`
+	// Due is the share of IntervalDays that has passed since the item was
+	// last bought; 1 means it is due today.
+	Due float64 `json:"due"`
+}
+
+// IsDue reports whether the item is likely needed again.
+func (s Suggestion) IsDue() bool {
+	return s.IntervalDays > 0 && s.Due >= DueRatio
+}
+
+// Every describes how often the item is bought, such as "every 2 weeks",
+// or "" if that is not known yet.
+func (s Suggestion) Every() string {
+	switch {
+	case s.IntervalDays <= 0:
+		return ""
+	case s.IntervalDays == 1:
+		return "every day"
+	case s.IntervalDays == 7:
+		return "every week"
+	case s.IntervalDays%7 == 0:
+		return fmt.Sprintf("every %d weeks", s.IntervalDays/7)
+	}
+	return fmt.Sprintf("every %d days", s.IntervalDays)
+}
+
+// ItemKey returns the name items are matched by: lower case, with runs of
+// spaces collapsed, so "Eggs" and " eggs " are the same item.
+func ItemKey(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// Suggest returns the items that are due, most overdue first. history must
+// be oldest first. Items whose key is in onList are left out.
+func Suggest(history []model.GroceryPurchase, onList map[string]bool, now time.Time) []Suggestion {
+	var due []Suggestion
+	for _, s := range summarize(history, onList, now) {
+		if s.IsDue() {
+			due = append(due, s)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].Due > due[j].Due
+	})
+	return due
+}
+
+// Frequent returns up to limit of the items bought most often, leaving out
+// those whose key is in onList. Items bought equally often are ordered by
+// the most recently bought.
+func Frequent(history []model.GroceryPurchase, onList map[string]bool, now time.Time, limit int) []Suggestion {
+	items := summarize(history, onList, now)
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Purchases != items[j].Purchases {
+			return items[i].Purchases > items[j].Purchases
+		}
+		return items[i].LastPurchased.After(items[j].LastPurchased)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// summarize groups history by item, oldest first, and works out how often
+// each is bought. Several purchases on the same day count as one trip.
+func summarize(history []model.GroceryPurchase, onList map[string]bool, now time.Time) []Suggestion {
+	type item struct {
+		suggestion Suggestion
+		days       []time.Time
+	}
+	byKey := make(map[string]*item)
+	var keys []string
+	for _, p := range history {
+		key := ItemKey(p.Name)
+		if key == "" || onList[key] {
+			continue
+		}
+		it, ok := byKey[key]
+		if !ok {
+			it = &item{}
+			byKey[key] = it
+			keys = append(keys, key)
+		}
+		// The latest spelling and category win.
+		it.suggestion.Name = strings.TrimSpace(p.Name)
+		it.suggestion.Category = p.Category
+
+		day := startOfDay(p.PurchasedAt.In(now.Location()))
+		if n := len(it.days); n == 0 || !it.days[n-1].Equal(day) {
+			it.days = append(it.days, day)
+		}
+		if p.PurchasedAt.After(it.suggestion.LastPurchased) {
+			it.suggestion.LastPurchased = p.PurchasedAt
+		}
+	}
+
+	today := startOfDay(now)
+	out := make([]Suggestion, 0, len(keys))
+	for _, key := range keys {
+		it := byKey[key]
+		s := it.suggestion
+		s.Purchases = len(it.days)
+		s.IntervalDays = medianGap(it.days)
+		if s.IntervalDays > 0 {
+			since := daysBetween(it.days[len(it.days)-1], today)
+			s.Due = float64(since) / float64(s.IntervalDays)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// medianGap returns the median number of days between consecutive days,
+// or 0 if there are fewer than two. The median keeps one long gap, such as
+// a holiday, from skewing the interval.
+func medianGap(days []time.Time) int {
+	if len(days) < 2 {
+		return 0
+	}
+	gaps := make([]int, 0, len(days)-1)
+	for i := 1; i < len(days); i++ {
+		gaps = append(gaps, daysBetween(days[i-1], days[i]))
+	}
+	sort.Ints(gaps)
+	mid := len(gaps) / 2
+	if len(gaps)%2 == 1 {
+		return gaps[mid]
+	}
+	return (gaps[mid-1] + gaps[mid] + 1) / 2
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween counts calendar days from a to b, both at midnight.
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours()/24 + 0.5)
+}
@@ -0,0 +1,74 @@
+package grocery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+func purchasesOn(name string, days ...string) []model.GroceryPurchase {
+	var out []model.GroceryPurchase
+	for _, d := range days {
+		at, _ := time.Parse("2006-01-02 15:04", d)
+		out = append(out, model.GroceryPurchase{Name: name, Category: "Other", PurchasedAt: at})
+	}
+	return out
+}
+
+func TestSuggest(t *testing.T) {
+	var history []model.GroceryPurchase
+	// Eggs every two weeks, last bought 12 days ago.
+	history = append(history, purchasesOn("Eggs", "2026-04-01 10:00", "2026-04-15 10:00", "2026-04-29 09:00", "2026-05-13 18:00")...)
+	// Milk weekly, bought twice on the last trip, last bought 2 days ago.
+	history = append(history, purchasesOn("milk", "2026-05-04 10:00", "2026-05-11 10:00", "2026-05-23 10:00", "2026-05-23 11:00")...)
+	// Batteries once.
+	history = append(history, purchasesOn("Batteries", "2026-01-10 10:00")...)
+	// Coffee every 10 days, long overdue, but already on the list.
+	history = append(history, purchasesOn("Coffee", "2026-04-01 10:00", "2026-04-11 10:00")...)
+
+	now := time.Date(2026, 5, 25, 8, 0, 0, 0, time.UTC)
+	got := Suggest(history, map[string]bool{"coffee": true}, now)
+	if len(got) != 1 {
+		t.Fatalf("suggestions = %+v, want only eggs", got)
+	}
+	eggs := got[0]
+	if eggs.Name != "Eggs" || eggs.IntervalDays != 14 || eggs.Purchases != 4 {
+		t.Errorf("eggs = %+v, want every 14 days over 4 purchases", eggs)
+	}
+	if got := eggs.Every(); got != "every 2 weeks" {
+		t.Errorf("eggs.Every() = %q, want %q", got, "every 2 weeks")
+	}
+	if want := 12.0 / 14.0; eggs.Due != want {
+		t.Errorf("eggs due = %v, want %v", eggs.Due, want)
+	}
+
+	// A week later milk is due too, and eggs are more overdue.
+	got = Suggest(history, nil, now.AddDate(0, 0, 7))
+	if len(got) != 3 || got[0].Name != "Coffee" || got[1].Name != "Eggs" || got[2].Name != "milk" {
+		t.Errorf("suggestions = %+v, want coffee, eggs, milk", got)
+	}
+}
+
+func TestFrequent(t *testing.T) {
+	var history []model.GroceryPurchase
+	history = append(history, purchasesOn("Bananas", "2026-05-01 10:00", "2026-05-08 10:00")...)
+	history = append(history, purchasesOn("Bread", "2026-05-01 10:00", "2026-05-05 10:00", "2026-05-10 10:00")...)
+	history = append(history, purchasesOn("Butter", "2026-05-02 10:00", "2026-05-09 10:00")...)
+	history = append(history, purchasesOn(" bread ", "2026-05-12 10:00")...)
+
+	now := time.Date(2026, 5, 13, 8, 0, 0, 0, time.UTC)
+	got := Frequent(history, nil, now, 2)
+	if len(got) != 2 || got[0].Name != "bread" || got[0].Purchases != 4 || got[1].Name != "Butter" {
+		t.Errorf("frequent = %+v, want bread then butter", got)
+	}
+	if got := Frequent(history, map[string]bool{"bread": true}, now, 5); len(got) != 2 {
+		t.Errorf("frequent = %+v, want bread left out", got)
+	}
+}
+
+func TestItemKey(t *testing.T) {
+	if got := ItemKey("  Green   Beans "); got != "green beans" {
+		t.Errorf("ItemKey = %q, want %q", got, "green beans")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/dukerupert/gamwich/internal/auth"
+	"github.com/dukerupert/gamwich/internal/chore"
 	"github.com/dukerupert/gamwich/internal/email"
 	"github.com/dukerupert/gamwich/internal/model"
 	"github.com/dukerupert/gamwich/internal/store"
@@ -30,6 +31,7 @@ type AuthHandler struct {
 	householdStore *store.HouseholdStore
 	sessionStore   *store.SessionStore
 	magicLinkStore *store.MagicLinkStore
+	choreStore     *store.ChoreStore
 	emailClient    *email.Client
 	baseURL        string
 	templates      *template.Template
@@ -41,6 +43,7 @@ func NewAuthHandler(
 	hs *store.HouseholdStore,
 	ss *store.SessionStore,
 	mls *store.MagicLinkStore,
+	cs *store.ChoreStore,
 	ec *email.Client,
 	baseURL string,
 	logger *slog.Logger,
@@ -51,6 +54,7 @@ func NewAuthHandler(
 		householdStore: hs,
 		sessionStore:   ss,
 		magicLinkStore: mls,
+		choreStore:     cs,
 		emailClient:    ec,
 		baseURL:        baseURL,
 		templates:      tmpl,
@@ -105,7 +109,32 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AuthHandler) RegisterPage(w http.ResponseWriter, r *http.Request) {
-	h.templates.ExecuteTemplate(w, "auth_register.html", nil)
+	h.templates.ExecuteTemplate(w, "auth_register.html", map[string]any{"Packs": chore.StarterPacks()})
+}
+
+// starterChores returns the chores a new household asked to start out
+// with: a starter pack, another household's template by its share code,
+// or both. It returns a message for the user if either is not found.
+func (h *AuthHandler) starterChores(r *http.Request) ([]model.TemplateChore, string, error) {
+	var chores []model.TemplateChore
+	if key := r.FormValue("starter_pack"); key != "" {
+		pack, ok := chore.FindStarterPack(key)
+		if !ok {
+			return nil, "Unknown starter pack", nil
+		}
+		chores = append(chores, pack.Chores...)
+	}
+	if code := strings.TrimSpace(r.FormValue("template_code")); code != "" {
+		t, err := h.choreStore.GetTemplateByShareCode(code)
+		if err != nil {
+			return nil, "", err
+		}
+		if t == nil {
+			return nil, "Template code not found", nil
+		}
+		chores = append(chores, t.Chores...)
+	}
+	return chores, "", nil
 }
 
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
@@ -117,6 +146,17 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	starter, msg, err := h.starterChores(r)
+	if err != nil {
+		h.logger.Error("register starter chores", "error", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
 	// Check if user already exists
 	existing, err := h.userStore.GetByEmail(emailAddr)
 	if err != nil {
@@ -157,7 +197,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Seed defaults for the new household
-	if err := h.householdStore.SeedDefaults(household.ID); err != nil {
+	if err := h.householdStore.SeedDefaults(household.ID, starter...); err != nil {
 		h.logger.Error("seed defaults", "error", err)
 		http.Error(w, "Internal error", http.StatusInternalServerError)
 		return
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dukerupert/gamwich/internal/auth"
+	"github.com/dukerupert/gamwich/internal/calsync"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/store"
+	"github.com/dukerupert/gamwich/internal/websocket"
+)
+
+// CalDAVHandler manages CalDAV collections kept in two-way sync.
+type CalDAVHandler struct {
+	caldavStore *store.CalDAVStore
+	memberStore *store.FamilyMemberStore
+	syncer      *calsync.CalDAVSyncer
+	hub         *websocket.Hub
+	logger      *slog.Logger
+}
+
+func NewCalDAVHandler(cs *store.CalDAVStore, ms *store.FamilyMemberStore, syncer *calsync.CalDAVSyncer, hub *websocket.Hub, logger *slog.Logger) *CalDAVHandler {
+	return &CalDAVHandler{caldavStore: cs, memberStore: ms, syncer: syncer, hub: hub, logger: logger}
+}
+
+func (h *CalDAVHandler) broadcast(householdID int64, msg websocket.Message) {
+	if h.hub != nil {
+		h.hub.BroadcastToHousehold(householdID, msg)
+	}
+}
+
+// collectionRequest is the body for creating or updating a collection. The
+// password is write-only; leaving it empty on update keeps the stored one.
+type collectionRequest struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	Username       string `json:"username"`
+	Password       string `json:"password"`
+	FamilyMemberID *int64 `json:"family_member_id"`
+}
+
+type collectionSyncResponse struct {
+	Collection *model.CalDAVCollection `json:"collection"`
+	Result     calsync.SyncResult      `json:"result"`
+	Error      string                  `json:"error,omitempty"`
+}
+
+func (h *CalDAVHandler) parseAndValidate(r *http.Request, w http.ResponseWriter) (*collectionRequest, bool) {
+	householdID := auth.HouseholdID(r.Context())
+	var req collectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return nil, false
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+		return nil, false
+	}
+	u, err := calsync.NormalizeCalDAVURL(req.URL)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return nil, false
+	}
+	req.URL = u
+	req.Username = strings.TrimSpace(req.Username)
+
+	if !memberExists(w, h.memberStore, householdID, req.FamilyMemberID) {
+		return nil, false
+	}
+	return &req, true
+}
+
+func (h *CalDAVHandler) List(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	collections, err := h.caldavStore.ListCollections(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list collections"})
+		return
+	}
+	if collections == nil {
+		collections = []model.CalDAVCollection{}
+	}
+	writeJSON(w, http.StatusOK, collections)
+}
+
+// Create adds a collection and syncs it straight away. A failed first sync
+// is reported in the response but still keeps the collection.
+func (h *CalDAVHandler) Create(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	req, ok := h.parseAndValidate(r, w)
+	if !ok {
+		return
+	}
+
+	coll, err := h.caldavStore.CreateCollection(householdID, req.Name, req.URL, req.Username, req.Password, req.FamilyMemberID)
+	if err != nil {
+		h.logger.Error("create caldav collection", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create collection"})
+		return
+	}
+
+	resp := h.sync(r, coll)
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *CalDAVHandler) Update(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	existing, err := h.caldavStore.GetCollection(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get collection"})
+		return
+	}
+	if existing == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "collection not found"})
+		return
+	}
+
+	req, ok := h.parseAndValidate(r, w)
+	if !ok {
+		return
+	}
+
+	coll, err := h.caldavStore.UpdateCollection(id, householdID, req.Name, req.URL, req.Username, req.Password, req.FamilyMemberID)
+	if err != nil {
+		h.logger.Error("update caldav collection", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update collection"})
+		return
+	}
+
+	if !sameMember(existing.FamilyMemberID, req.FamilyMemberID) {
+		h.broadcast(householdID, websocket.NewMessage("calendar_event", "updated", 0, nil))
+	}
+
+	writeJSON(w, http.StatusOK, coll)
+}
+
+// Delete stops syncing a collection. Its events stay on the local calendar
+// and on the server.
+func (h *CalDAVHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	existing, err := h.caldavStore.GetCollection(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get collection"})
+		return
+	}
+	if existing == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "collection not found"})
+		return
+	}
+
+	if err := h.caldavStore.DeleteCollection(id, householdID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete collection"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Sync handles POST /api/caldav-collections/{id}/sync.
+func (h *CalDAVHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	coll, err := h.caldavStore.GetCollection(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get collection"})
+		return
+	}
+	if coll == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "collection not found"})
+		return
+	}
+
+	resp := h.sync(r, coll)
+	status := http.StatusOK
+	if resp.Error != "" {
+		status = http.StatusBadGateway
+	}
+	writeJSON(w, status, resp)
+}
+
+func (h *CalDAVHandler) sync(r *http.Request, coll *model.CalDAVCollection) collectionSyncResponse {
+	var resp collectionSyncResponse
+	res, err := h.syncer.SyncCollection(r.Context(), *coll)
+	resp.Result = res
+	if err != nil {
+		h.logger.Warn("sync caldav collection", "collection_id", coll.ID, "error", err)
+		resp.Error = err.Error()
+	}
+
+	resp.Collection = coll
+	if refreshed, err := h.caldavStore.GetCollection(coll.ID, coll.HouseholdID); err == nil && refreshed != nil {
+		resp.Collection = refreshed
+	}
+	return resp
+}
@@ -8,6 +8,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dukerupert/gamwich/internal/auth"
+	"github.com/dukerupert/gamwich/internal/calendar"
+	"github.com/dukerupert/gamwich/internal/calsync"
 	"github.com/dukerupert/gamwich/internal/model"
 	"github.com/dukerupert/gamwich/internal/recurrence"
 	"github.com/dukerupert/gamwich/internal/store"
@@ -15,19 +18,61 @@ import (
 )
 
 type CalendarEventHandler struct {
-	eventStore  *store.EventStore
-	memberStore *store.FamilyMemberStore
-	hub         *websocket.Hub
-	logger      *slog.Logger
+	eventStore    *store.EventStore
+	memberStore   *store.FamilyMemberStore
+	settingsStore *store.SettingsStore
+	calService    *calendar.Service
+	hub           *websocket.Hub
+	calSync       *calsync.Scheduler
+	logger        *slog.Logger
 }
 
-func NewCalendarEventHandler(es *store.EventStore, ms *store.FamilyMemberStore, hub *websocket.Hub, logger *slog.Logger) *CalendarEventHandler {
-	return &CalendarEventHandler{eventStore: es, memberStore: ms, hub: hub, logger: logger}
+func NewCalendarEventHandler(es *store.EventStore, ms *store.FamilyMemberStore, ss *store.SettingsStore, cal *calendar.Service, hub *websocket.Hub, cs *calsync.Scheduler, logger *slog.Logger) *CalendarEventHandler {
+	return &CalendarEventHandler{eventStore: es, memberStore: ms, settingsStore: ss, calService: cal, hub: hub, calSync: cs, logger: logger}
 }
 
-func (h *CalendarEventHandler) broadcast(msg websocket.Message) {
+// conflictLookahead is how far ahead a recurring event is checked for
+// conflicts when it is saved.
+const conflictLookahead = calendar.MaxRange
+
+// eventResponse is a saved event with a warning about any events it
+// overlaps. The event is saved either way.
+type eventResponse struct {
+	*model.CalendarEvent
+	Conflicts []calendar.Conflict `json:"conflicts,omitempty"`
+}
+
+// conflictsFor returns the member conflicts that involve event, over its
+// own span or, for a recurring event, the next conflictLookahead of it.
+func (h *CalendarEventHandler) conflictsFor(householdID int64, event *model.CalendarEvent) []calendar.Conflict {
+	if event.AllDay {
+		return nil
+	}
+	end := event.EndTime
+	if event.RecurrenceRule != "" {
+		end = event.StartTime.Add(conflictLookahead)
+	}
+	conflicts, err := h.calService.Conflicts(householdID, event.StartTime, end, false)
+	if err != nil {
+		h.logger.Error("check event conflicts", "event_id", event.ID, "error", err)
+		return nil
+	}
+
+	var involved []calendar.Conflict
+	for _, c := range conflicts {
+		for _, o := range c.Events {
+			if o.ID == event.ID || o.ParentID == event.ID {
+				involved = append(involved, c)
+				break
+			}
+		}
+	}
+	return involved
+}
+
+func (h *CalendarEventHandler) broadcast(householdID int64, msg websocket.Message) {
 	if h.hub != nil {
-		h.hub.Broadcast(msg)
+		h.hub.BroadcastToHousehold(householdID, msg)
 	}
 }
 
@@ -38,11 +83,29 @@ type eventRequest struct {
 	EndTime        string `json:"end_time"`
 	AllDay         bool   `json:"all_day"`
 	FamilyMemberID *int64 `json:"family_member_id"`
-	Location       string `json:"location"`
-	RecurrenceRule string `json:"recurrence_rule"`
+	// Attendees replaces family_member_id when set. A bare
+	// family_member_id is treated as a single attendee.
+	Attendees      []model.Attendee `json:"attendees"`
+	Location       string           `json:"location"`
+	RecurrenceRule string           `json:"recurrence_rule"`
+}
+
+// event returns the event to save from a validated request.
+func (req *eventRequest) event(startTime, endTime time.Time) model.CalendarEvent {
+	return model.CalendarEvent{
+		Title:          req.Title,
+		Description:    req.Description,
+		StartTime:      startTime,
+		EndTime:        endTime,
+		AllDay:         req.AllDay,
+		Attendees:      req.Attendees,
+		Location:       req.Location,
+		RecurrenceRule: req.RecurrenceRule,
+	}
 }
 
 func (h *CalendarEventHandler) parseAndValidate(r *http.Request, w http.ResponseWriter) (*eventRequest, time.Time, time.Time, bool) {
+	householdID := auth.HouseholdID(r.Context())
 	var req eventRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
@@ -72,8 +135,24 @@ func (h *CalendarEventHandler) parseAndValidate(r *http.Request, w http.Response
 		return nil, time.Time{}, time.Time{}, false
 	}
 
-	if req.FamilyMemberID != nil {
-		member, err := h.memberStore.GetByID(*req.FamilyMemberID)
+	if req.Attendees == nil && req.FamilyMemberID != nil {
+		req.Attendees = []model.Attendee{{FamilyMemberID: *req.FamilyMemberID, Role: model.RoleAttendee}}
+	}
+	seen := make(map[int64]bool)
+	for i, a := range req.Attendees {
+		if a.Role == "" {
+			req.Attendees[i].Role = model.RoleAttendee
+		} else if !a.Role.Valid() {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "attendee role must be attendee, driver or optional"})
+			return nil, time.Time{}, time.Time{}, false
+		}
+		if seen[a.FamilyMemberID] {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "family member listed twice"})
+			return nil, time.Time{}, time.Time{}, false
+		}
+		seen[a.FamilyMemberID] = true
+
+		member, err := h.memberStore.GetByID(a.FamilyMemberID, householdID)
 		if err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to check family member"})
 			return nil, time.Time{}, time.Time{}, false
@@ -83,29 +162,33 @@ func (h *CalendarEventHandler) parseAndValidate(r *http.Request, w http.Response
 			return nil, time.Time{}, time.Time{}, false
 		}
 	}
+	req.FamilyMemberID = model.PrimaryAttendee(req.Attendees)
 
 	return &req, startTime, endTime, true
 }
 
 func (h *CalendarEventHandler) Create(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	req, startTime, endTime, ok := h.parseAndValidate(r, w)
 	if !ok {
 		return
 	}
 
-	event, err := h.eventStore.CreateWithRecurrence(req.Title, req.Description, startTime, endTime, req.AllDay, req.FamilyMemberID, req.Location, req.RecurrenceRule)
+	event, err := h.eventStore.CreateWithAttendees(householdID, req.event(startTime, endTime))
 	if err != nil {
 		h.logger.Error("create calendar event", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create event"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("calendar_event", "created", event.ID, nil))
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "created", event.ID, nil))
+	h.calSync.Notify(householdID)
 
-	writeJSON(w, http.StatusCreated, event)
+	writeJSON(w, http.StatusCreated, eventResponse{CalendarEvent: event, Conflicts: h.conflictsFor(householdID, event)})
 }
 
 func (h *CalendarEventHandler) List(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	startStr := r.URL.Query().Get("start")
 	endStr := r.URL.Query().Get("end")
 
@@ -127,7 +210,7 @@ func (h *CalendarEventHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get non-recurring events
-	events, err := h.eventStore.ListByDateRange(start, end)
+	events, err := h.eventStore.ListByDateRange(householdID, start, end)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list events"})
 		return
@@ -137,12 +220,13 @@ func (h *CalendarEventHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Expand recurring events
-	recurring, err := h.eventStore.ListRecurring(end)
+	recurring, err := h.eventStore.ListRecurring(householdID, end)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list recurring events"})
 		return
 	}
 
+	loc := householdLocation(h.settingsStore, householdID, h.logger)
 	for _, parent := range recurring {
 		rule, err := recurrence.Parse(parent.RecurrenceRule)
 		if err != nil {
@@ -150,9 +234,9 @@ func (h *CalendarEventHandler) List(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		occurrences := recurrence.Expand(rule, parent.StartTime, parent.EndTime, start, end)
+		occurrences := recurrence.Set{Rule: rule, IncludeStart: parent.Imported()}.ExpandIn(parent.StartTime, parent.EndTime, start, end, loc)
 
-		exceptions, err := h.eventStore.ListExceptions(parent.ID)
+		exceptions, err := h.eventStore.ListExceptions(parent.ID, householdID)
 		if err != nil {
 			continue
 		}
@@ -188,13 +272,14 @@ func (h *CalendarEventHandler) List(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *CalendarEventHandler) Get(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	event, err := h.eventStore.GetByID(id)
+	event, err := h.eventStore.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get event"})
 		return
@@ -208,13 +293,14 @@ func (h *CalendarEventHandler) Get(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *CalendarEventHandler) Update(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.eventStore.GetByID(id)
+	existing, err := h.eventStore.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get event"})
 		return
@@ -223,31 +309,87 @@ func (h *CalendarEventHandler) Update(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusNotFound, map[string]string{"error": "event not found"})
 		return
 	}
+	if existing.Imported() {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "imported events are read-only"})
+		return
+	}
 
 	req, startTime, endTime, ok := h.parseAndValidate(r, w)
 	if !ok {
 		return
 	}
 
-	event, err := h.eventStore.UpdateWithRecurrence(id, req.Title, req.Description, startTime, endTime, req.AllDay, req.FamilyMemberID, req.Location, req.RecurrenceRule)
+	event, err := h.eventStore.UpdateWithAttendees(id, householdID, req.event(startTime, endTime))
 	if err != nil {
+		h.logger.Error("update calendar event", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update event"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("calendar_event", "updated", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "updated", id, nil))
+	h.calSync.Notify(householdID)
 
-	writeJSON(w, http.StatusOK, event)
+	writeJSON(w, http.StatusOK, eventResponse{CalendarEvent: event, Conflicts: h.conflictsFor(householdID, event)})
+}
+
+// Conflicts handles GET /api/events/conflicts. Overlapping events for the
+// same family member are always reported; drivers=true also reports
+// overlapping events at different locations that both have a driver. The
+// range may be at most calendar.MaxRange long.
+func (h *CalendarEventHandler) Conflicts(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	startStr := r.URL.Query().Get("start")
+	endStr := r.URL.Query().Get("end")
+
+	if startStr == "" || endStr == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "start and end query parameters are required"})
+		return
+	}
+
+	start, err := parseFlexibleTime(startStr)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "start must be RFC3339 or YYYY-MM-DD format"})
+		return
+	}
+
+	end, err := parseFlexibleTime(endStr)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "end must be RFC3339 or YYYY-MM-DD format"})
+		return
+	}
+
+	if !end.After(start) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "end must be after start"})
+		return
+	}
+	if end.Sub(start) > calendar.MaxRange {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "range must be at most 31 days"})
+		return
+	}
+
+	drivers := r.URL.Query().Get("drivers") == "true"
+	conflicts, err := h.calService.Conflicts(householdID, start, end, drivers)
+	if err != nil {
+		h.logger.Error("list event conflicts", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list conflicts"})
+		return
+	}
+	if conflicts == nil {
+		conflicts = []calendar.Conflict{}
+	}
+
+	writeJSON(w, http.StatusOK, conflicts)
 }
 
 func (h *CalendarEventHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.eventStore.GetByID(id)
+	existing, err := h.eventStore.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get event"})
 		return
@@ -256,13 +398,18 @@ func (h *CalendarEventHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusNotFound, map[string]string{"error": "event not found"})
 		return
 	}
+	if existing.Imported() {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "imported events are read-only"})
+		return
+	}
 
-	if err := h.eventStore.Delete(id); err != nil {
+	if err := h.eventStore.Delete(id, householdID); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete event"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("calendar_event", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "deleted", id, nil))
+	h.calSync.Notify(householdID)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -273,3 +420,64 @@ func parseFlexibleTime(s string) (time.Time, error) {
 	}
 	return time.Parse("2006-01-02", s)
 }
+
+// AssignMember handles PUT /api/events/{id}/member. It is the one change
+// allowed on imported events; the whole series is reassigned.
+func (h *CalendarEventHandler) AssignMember(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	existing, err := h.eventStore.GetByID(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get event"})
+		return
+	}
+	if existing == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "event not found"})
+		return
+	}
+
+	var req struct {
+		FamilyMemberID *int64 `json:"family_member_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+	if req.FamilyMemberID != nil {
+		member, err := h.memberStore.GetByID(*req.FamilyMemberID, householdID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to check family member"})
+			return
+		}
+		if member == nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "family member not found"})
+			return
+		}
+	}
+
+	seriesID := id
+	if existing.RecurrenceParentID != nil {
+		seriesID = *existing.RecurrenceParentID
+	}
+	if err := h.eventStore.SetFamilyMember(seriesID, householdID, req.FamilyMemberID); err != nil {
+		h.logger.Error("assign event member", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update event"})
+		return
+	}
+
+	event, err := h.eventStore.GetByID(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get event"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "updated", seriesID, nil))
+	h.calSync.Notify(householdID)
+
+	writeJSON(w, http.StatusOK, event)
+}
@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dukerupert/gamwich/internal/auth"
+	"github.com/dukerupert/gamwich/internal/calsync"
+	"github.com/dukerupert/gamwich/internal/ical"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/store"
+	"github.com/dukerupert/gamwich/internal/websocket"
+)
+
+// CalendarSubscriptionHandler manages subscribed iCal URLs and .ics imports.
+type CalendarSubscriptionHandler struct {
+	subStore    *store.CalendarSubscriptionStore
+	eventStore  *store.EventStore
+	memberStore *store.FamilyMemberStore
+	importer    *calsync.Importer
+	hub         *websocket.Hub
+	logger      *slog.Logger
+}
+
+func NewCalendarSubscriptionHandler(ss *store.CalendarSubscriptionStore, es *store.EventStore, ms *store.FamilyMemberStore, im *calsync.Importer, hub *websocket.Hub, logger *slog.Logger) *CalendarSubscriptionHandler {
+	return &CalendarSubscriptionHandler{subStore: ss, eventStore: es, memberStore: ms, importer: im, hub: hub, logger: logger}
+}
+
+func (h *CalendarSubscriptionHandler) broadcast(householdID int64, msg websocket.Message) {
+	if h.hub != nil {
+		h.hub.BroadcastToHousehold(householdID, msg)
+	}
+}
+
+type subscriptionRequest struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	FamilyMemberID *int64 `json:"family_member_id"`
+}
+
+type syncResponse struct {
+	Subscription *model.CalendarSubscription `json:"subscription"`
+	Result       calsync.Result              `json:"result"`
+	Error        string                      `json:"error,omitempty"`
+}
+
+func (h *CalendarSubscriptionHandler) parseAndValidate(r *http.Request, w http.ResponseWriter) (*subscriptionRequest, bool) {
+	householdID := auth.HouseholdID(r.Context())
+	var req subscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return nil, false
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+		return nil, false
+	}
+	u, err := calsync.NormalizeURL(req.URL)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return nil, false
+	}
+	req.URL = u
+
+	if !memberExists(w, h.memberStore, householdID, req.FamilyMemberID) {
+		return nil, false
+	}
+	return &req, true
+}
+
+// memberExists checks an optional family member ID, writing an error if it
+// is not in the household.
+func memberExists(w http.ResponseWriter, ms *store.FamilyMemberStore, householdID int64, memberID *int64) bool {
+	if memberID == nil {
+		return true
+	}
+	member, err := ms.GetByID(*memberID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to check family member"})
+		return false
+	}
+	if member == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "family member not found"})
+		return false
+	}
+	return true
+}
+
+func (h *CalendarSubscriptionHandler) List(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	subs, err := h.subStore.List(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list subscriptions"})
+		return
+	}
+	if subs == nil {
+		subs = []model.CalendarSubscription{}
+	}
+	writeJSON(w, http.StatusOK, subs)
+}
+
+// Create adds a subscription and fetches it straight away. A failed first
+// fetch is reported in the response but still keeps the subscription.
+func (h *CalendarSubscriptionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	req, ok := h.parseAndValidate(r, w)
+	if !ok {
+		return
+	}
+
+	sub, err := h.subStore.Create(householdID, req.Name, req.URL, req.FamilyMemberID)
+	if err != nil {
+		h.logger.Error("create calendar subscription", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create subscription"})
+		return
+	}
+
+	resp := h.sync(r, sub)
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *CalendarSubscriptionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	existing, err := h.subStore.GetByID(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get subscription"})
+		return
+	}
+	if existing == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "subscription not found"})
+		return
+	}
+
+	req, ok := h.parseAndValidate(r, w)
+	if !ok {
+		return
+	}
+
+	sub, err := h.subStore.Update(id, householdID, req.Name, req.URL, req.FamilyMemberID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update subscription"})
+		return
+	}
+
+	if !sameMember(existing.FamilyMemberID, req.FamilyMemberID) {
+		if err := h.eventStore.SetSubscriptionFamilyMember(id, householdID, req.FamilyMemberID); err != nil {
+			h.logger.Error("reassign subscription events", "error", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update subscription"})
+			return
+		}
+		h.broadcast(householdID, websocket.NewMessage("calendar_event", "updated", 0, nil))
+	}
+
+	writeJSON(w, http.StatusOK, sub)
+}
+
+func (h *CalendarSubscriptionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	existing, err := h.subStore.GetByID(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get subscription"})
+		return
+	}
+	if existing == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "subscription not found"})
+		return
+	}
+
+	if err := h.subStore.Delete(id, householdID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete subscription"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "deleted", 0, nil))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Sync handles POST /api/calendar-subscriptions/{id}/sync.
+func (h *CalendarSubscriptionHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	sub, err := h.subStore.GetByID(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get subscription"})
+		return
+	}
+	if sub == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "subscription not found"})
+		return
+	}
+
+	resp := h.sync(r, sub)
+	status := http.StatusOK
+	if resp.Error != "" {
+		status = http.StatusBadGateway
+	}
+	writeJSON(w, status, resp)
+}
+
+func (h *CalendarSubscriptionHandler) sync(r *http.Request, sub *model.CalendarSubscription) syncResponse {
+	var resp syncResponse
+	res, err := h.importer.SyncSubscription(r.Context(), *sub)
+	resp.Result = res
+	if err != nil {
+		h.logger.Warn("sync calendar subscription", "subscription_id", sub.ID, "error", err)
+		resp.Error = err.Error()
+	}
+
+	resp.Subscription = sub
+	if refreshed, err := h.subStore.GetByID(sub.ID, sub.HouseholdID); err == nil && refreshed != nil {
+		resp.Subscription = refreshed
+	}
+	return resp
+}
+
+// Import handles POST /api/events/import. The calendar is read from a
+// multipart "file" field or, for any other content type, the raw body.
+// An optional family_member_id query parameter assigns the new events.
+func (h *CalendarSubscriptionHandler) Import(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+
+	var memberID *int64
+	if v := r.URL.Query().Get("family_member_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid family_member_id"})
+			return
+		}
+		memberID = &id
+	}
+	if !memberExists(w, h.memberStore, householdID, memberID) {
+		return
+	}
+
+	body, closeBody, err := calendarUpload(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "file is required"})
+		return
+	}
+	defer closeBody()
+
+	res, err := h.importer.ImportFile(householdID, memberID, body)
+	if err != nil {
+		if errors.Is(err, ical.ErrNotCalendar) || errors.Is(err, calsync.ErrTooLarge) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		h.logger.Error("import calendar file", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to import calendar"})
+		return
+	}
+
+	if res.Changed() {
+		h.broadcast(householdID, websocket.NewMessage("calendar_event", "imported", 0, nil))
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// calendarUpload returns the uploaded calendar from a multipart form's
+// "file" field, or the request body for non-multipart requests.
+func calendarUpload(r *http.Request) (io.Reader, func(), error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, func() {}, nil
+	}
+	if err := r.ParseMultipartForm(calsync.MaxCalendarSize); err != nil {
+		return nil, nil, err
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, func() { file.Close() }, nil
+}
+
+func sameMember(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
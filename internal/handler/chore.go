@@ -2,30 +2,38 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dukerupert/gamwich/internal/auth"
+	"github.com/dukerupert/gamwich/internal/chore"
 	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/push"
 	"github.com/dukerupert/gamwich/internal/store"
 	"github.com/dukerupert/gamwich/internal/websocket"
 )
 
 type ChoreHandler struct {
-	choreStore  *store.ChoreStore
-	memberStore *store.FamilyMemberStore
-	hub         *websocket.Hub
-	logger      *slog.Logger
+	choreStore    *store.ChoreStore
+	memberStore   *store.FamilyMemberStore
+	settingsStore *store.SettingsStore
+	hub           *websocket.Hub
+	pushScheduler *push.Scheduler
+	logger        *slog.Logger
 }
 
-func NewChoreHandler(cs *store.ChoreStore, ms *store.FamilyMemberStore, hub *websocket.Hub, logger *slog.Logger) *ChoreHandler {
-	return &ChoreHandler{choreStore: cs, memberStore: ms, hub: hub, logger: logger}
+func NewChoreHandler(cs *store.ChoreStore, ms *store.FamilyMemberStore, ss *store.SettingsStore, hub *websocket.Hub, pushSched *push.Scheduler, logger *slog.Logger) *ChoreHandler {
+	return &ChoreHandler{choreStore: cs, memberStore: ms, settingsStore: ss, hub: hub, pushScheduler: pushSched, logger: logger}
 }
 
-func (h *ChoreHandler) broadcast(msg websocket.Message) {
+func (h *ChoreHandler) broadcast(householdID int64, msg websocket.Message) {
 	if h.hub != nil {
-		h.hub.Broadcast(msg)
+		h.hub.BroadcastToHousehold(householdID, msg)
 	}
 }
 
@@ -36,9 +44,84 @@ type choreRequest struct {
 	Points         int    `json:"points"`
 	RecurrenceRule string `json:"recurrence_rule"`
 	AssignedTo     *int64 `json:"assigned_to"`
+	// DueAt is for one-off chores and DueTime, "HH:MM", for recurring ones.
+	DueAt   *time.Time `json:"due_at"`
+	DueTime string     `json:"due_time"`
+	// RequiresApproval holds completions for a parent to approve.
+	RequiresApproval bool `json:"requires_approval"`
+	// MinAge is the youngest a member can be to be given the chore.
+	MinAge int `json:"min_age"`
+	// RotationMembers and RotationCadence are left unchanged on update
+	// when omitted.
+	RotationMembers *[]int64              `json:"rotation_members"`
+	RotationCadence model.RotationCadence `json:"rotation_cadence"`
 }
 
+// validate checks the rotation and that the assignee and rotation members
+// belong to the household, and that the assignee is old enough for the
+// chore. Rotation members who are too young are passed over until they
+// are old enough. It returns a message for the client if the request is
+// invalid.
+func (h *ChoreHandler) validate(householdID int64, req choreRequest) (string, error) {
+	if !req.RotationCadence.Valid() {
+		return `rotation_cadence must be "occurrence", "weekly" or "completion"`, nil
+	}
+	if req.DueTime != "" && !timeFormatRegexp.MatchString(req.DueTime) {
+		return "due_time must be HH:MM format", nil
+	}
+	if req.DueAt != nil && req.RecurrenceRule != "" {
+		return "due_at is for one-off chores; use due_time for recurring chores", nil
+	}
+	if req.DueTime != "" && req.RecurrenceRule == "" {
+		return "due_time is for recurring chores; use due_at for one-off chores", nil
+	}
+	if req.MinAge < 0 || req.MinAge > maxMinAge {
+		return fmt.Sprintf("min_age must be between 0 and %d", maxMinAge), nil
+	}
+
+	ids := []int64{}
+	if req.AssignedTo != nil {
+		ids = append(ids, *req.AssignedTo)
+	}
+	if req.RotationMembers != nil {
+		seen := make(map[int64]bool)
+		for _, id := range *req.RotationMembers {
+			if seen[id] {
+				return "rotation_members must not repeat a member", nil
+			}
+			seen[id] = true
+		}
+		ids = append(ids, *req.RotationMembers...)
+	}
+
+	for _, id := range ids {
+		member, err := h.memberStore.GetByID(id, householdID)
+		if err != nil {
+			return "", err
+		}
+		if member == nil {
+			return "family member not found", nil
+		}
+	}
+
+	if req.AssignedTo != nil && req.MinAge > 0 {
+		member, err := h.memberStore.GetByID(*req.AssignedTo, householdID)
+		if err != nil {
+			return "", err
+		}
+		today := time.Now().In(householdLocation(h.settingsStore, householdID, h.logger))
+		if !member.OldEnough(req.MinAge, today) {
+			return member.Name + " is too young for this chore", nil
+		}
+	}
+	return "", nil
+}
+
+// maxMinAge is the highest minimum age a chore can have.
+const maxMinAge = 99
+
 func (h *ChoreHandler) Create(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	var req choreRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
@@ -51,32 +134,47 @@ func (h *ChoreHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.AssignedTo != nil {
-		member, err := h.memberStore.GetByID(*req.AssignedTo)
-		if err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to check family member"})
-			return
-		}
-		if member == nil {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "family member not found"})
-			return
-		}
+	if msg, err := h.validate(householdID, req); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to check family member"})
+		return
+	} else if msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
 	}
 
-	chore, err := h.choreStore.Create(req.Title, req.Description, req.AreaID, req.Points, req.RecurrenceRule, req.AssignedTo)
+	chore, err := h.choreStore.Create(householdID, req.Title, req.Description, req.AreaID, req.Points, req.RecurrenceRule, req.AssignedTo)
 	if err != nil {
 		h.logger.Error("create chore", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create chore"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("chore", "created", chore.ID, nil))
+	if req.RotationMembers != nil {
+		chore, err = h.saveRotation(chore.ID, householdID, *req.RotationMembers, req.RotationCadence)
+		if err != nil {
+			h.logger.Error("set chore rotation", "error", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create chore"})
+			return
+		}
+	}
+
+	if req.DueAt != nil || req.DueTime != "" || req.RequiresApproval || req.MinAge > 0 {
+		chore, err = h.saveOptions(chore.ID, householdID, req)
+		if err != nil {
+			h.logger.Error("set chore options", "error", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create chore"})
+			return
+		}
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "created", chore.ID, nil))
 
 	writeJSON(w, http.StatusCreated, chore)
 }
 
 func (h *ChoreHandler) List(w http.ResponseWriter, r *http.Request) {
-	chores, err := h.choreStore.List()
+	householdID := auth.HouseholdID(r.Context())
+	chores, err := h.choreStore.List(householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list chores"})
 		return
@@ -88,13 +186,14 @@ func (h *ChoreHandler) List(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ChoreHandler) Update(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.choreStore.GetByID(id)
+	existing, err := h.choreStore.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get chore"})
 		return
@@ -116,25 +215,49 @@ func (h *ChoreHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	chore, err := h.choreStore.Update(id, req.Title, req.Description, req.AreaID, req.Points, req.RecurrenceRule, req.AssignedTo)
+	if msg, err := h.validate(householdID, req); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to check family member"})
+		return
+	} else if msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
+
+	if _, err := h.choreStore.Update(id, householdID, req.Title, req.Description, req.AreaID, req.Points, req.RecurrenceRule, req.AssignedTo); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update chore"})
+		return
+	}
+
+	chore, err := h.saveOptions(id, householdID, req)
 	if err != nil {
+		h.logger.Error("set chore options", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update chore"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("chore", "updated", id, nil))
+	if req.RotationMembers != nil {
+		chore, err = h.saveRotation(id, householdID, *req.RotationMembers, req.RotationCadence)
+		if err != nil {
+			h.logger.Error("set chore rotation", "error", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update chore"})
+			return
+		}
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "updated", id, nil))
 
 	writeJSON(w, http.StatusOK, chore)
 }
 
 func (h *ChoreHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.choreStore.GetByID(id)
+	existing, err := h.choreStore.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get chore"})
 		return
@@ -144,24 +267,25 @@ func (h *ChoreHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.choreStore.Delete(id); err != nil {
+	if err := h.choreStore.Delete(id, householdID); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete chore"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("chore", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("chore", "deleted", id, nil))
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *ChoreHandler) Complete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.choreStore.GetByID(id)
+	existing, err := h.choreStore.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get chore"})
 		return
@@ -176,19 +300,30 @@ func (h *ChoreHandler) Complete(w http.ResponseWriter, r *http.Request) {
 	}
 	json.NewDecoder(r.Body).Decode(&req)
 
-	completion, err := h.choreStore.CreateCompletion(id, req.CompletedBy, existing.Points)
+	today := time.Now().In(householdLocation(h.settingsStore, householdID, h.logger))
+	points, err := earnedPoints(h.memberStore, *existing, householdID, req.CompletedBy, today)
+	if err != nil {
+		h.writeChoreError(w, "get family member", err)
+		return
+	}
+
+	completion, err := h.choreStore.CreateCompletion(id, householdID, req.CompletedBy, points)
 	if err != nil {
 		h.logger.Error("complete chore", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to complete chore"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("chore", "completed", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("chore", "completed", id, nil))
+	if completion.Status == model.CompletionPending {
+		requestApproval(h.memberStore, h.pushScheduler, householdID, *existing, completion)
+	}
 
 	writeJSON(w, http.StatusCreated, completion)
 }
 
 func (h *ChoreHandler) UndoComplete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	completionIDStr := r.PathValue("completion_id")
 	completionID, err := strconv.ParseInt(completionIDStr, 10, 64)
 	if err != nil {
@@ -196,13 +331,762 @@ func (h *ChoreHandler) UndoComplete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.choreStore.DeleteCompletion(completionID); err != nil {
+	if err := h.choreStore.DeleteCompletion(completionID, householdID); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to undo completion"})
 		return
 	}
 
 	choreID, _ := parseIDParam(r)
-	h.broadcast(websocket.NewMessage("chore", "completion_undone", choreID, nil))
+	h.broadcast(householdID, websocket.NewMessage("chore", "completion_undone", choreID, nil))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ChoreHandler) saveRotation(id, householdID int64, members []int64, cadence model.RotationCadence) (*model.Chore, error) {
+	if err := h.choreStore.SetRotation(id, householdID, members, cadence); err != nil {
+		return nil, err
+	}
+	return h.choreStore.GetByID(id, householdID)
+}
+
+// saveOptions saves a chore's deadline, whether it needs approval and
+// its minimum age.
+func (h *ChoreHandler) saveOptions(id, householdID int64, req choreRequest) (*model.Chore, error) {
+	if err := h.choreStore.SetDeadline(id, householdID, req.DueAt, req.DueTime); err != nil {
+		return nil, err
+	}
+	if err := h.choreStore.SetRequiresApproval(id, householdID, req.RequiresApproval); err != nil {
+		return nil, err
+	}
+	if err := h.choreStore.SetMinAge(id, householdID, req.MinAge); err != nil {
+		return nil, err
+	}
+	return h.choreStore.GetByID(id, householdID)
+}
+
+// rotationResponse is a chore's rotation with who is up now and the
+// history of skipped and swapped turns.
+type rotationResponse struct {
+	ChoreID int64                      `json:"chore_id"`
+	Cadence model.RotationCadence      `json:"cadence"`
+	Members []int64                    `json:"members"`
+	Current chore.Rotation             `json:"current"`
+	History []model.ChoreRotationEvent `json:"history"`
+}
+
+func (h *ChoreHandler) choreFromPath(w http.ResponseWriter, r *http.Request) (*model.Chore, bool) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return nil, false
+	}
+
+	c, err := h.choreStore.GetByID(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get chore"})
+		return nil, false
+	}
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "chore not found"})
+		return nil, false
+	}
+	return c, true
+}
+
+func (h *ChoreHandler) writeRotation(w http.ResponseWriter, status int, c *model.Chore, householdID int64) {
+	today := time.Now().In(householdLocation(h.settingsStore, householdID, h.logger))
+	current, events, err := currentRotation(h.choreStore, *c, householdID, today)
+	if err != nil {
+		h.logger.Error("chore rotation", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get rotation"})
+		return
+	}
+	if events == nil {
+		events = []model.ChoreRotationEvent{}
+	}
+	writeJSON(w, status, rotationResponse{
+		ChoreID: c.ID,
+		Cadence: c.RotationCadence,
+		Members: c.RotationMembers,
+		Current: current,
+		History: events,
+	})
+}
+
+// GetRotation returns who is responsible for a chore's current due date.
+func (h *ChoreHandler) GetRotation(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.choreFromPath(w, r)
+	if !ok {
+		return
+	}
+	h.writeRotation(w, http.StatusOK, c, auth.HouseholdID(r.Context()))
+}
+
+// SkipTurn passes the current turn to the next member in the rotation.
+func (h *ChoreHandler) SkipTurn(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	c, ok := h.choreFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	today := time.Now().In(householdLocation(h.settingsStore, householdID, h.logger))
+	if _, err := skipTurn(h.choreStore, *c, householdID, today); err != nil {
+		h.writeChoreError(w, "skip turn", err)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "updated", c.ID, nil))
+	h.writeRotation(w, http.StatusCreated, c, householdID)
+}
+
+// SwapTurn hands the current turn to another rotation member.
+func (h *ChoreHandler) SwapTurn(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	c, ok := h.choreFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		MemberID int64 `json:"member_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	today := time.Now().In(householdLocation(h.settingsStore, householdID, h.logger))
+	if _, err := swapTurn(h.choreStore, *c, householdID, today, req.MemberID); err != nil {
+		h.writeChoreError(w, "swap turn", err)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "updated", c.ID, nil))
+	h.writeRotation(w, http.StatusCreated, c, householdID)
+}
+
+func (h *ChoreHandler) writeChoreError(w http.ResponseWriter, action string, err error) {
+	var choreErr choreError
+	if errors.As(err, &choreErr) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": choreErr.Error()})
+		return
+	}
+	h.logger.Error(action, "error", err)
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to " + action})
+}
+
+// choreError is a change to a chore that its schedule or rotation does
+// not allow.
+type choreError string
+
+func (e choreError) Error() string { return string(e) }
+
+const (
+	errNotRotating      = choreError("chore does not rotate")
+	errTooFewMembers    = choreError("rotation needs at least two members")
+	errNotInRotation    = choreError("member is not in the rotation")
+	errAlreadyTheirTurn = choreError("it is already that member's turn")
+	errTooYoung         = choreError("member is too young for this chore")
+)
+
+// currentRotation works out who is responsible for a chore's current due
+// date, along with the chore's skip and swap history.
+func currentRotation(cs *store.ChoreStore, c model.Chore, householdID int64, today time.Time) (chore.Rotation, []model.ChoreRotationEvent, error) {
+	last, err := cs.LastCompletionForChore(c.ID, householdID)
+	if err != nil {
+		return chore.Rotation{}, nil, err
+	}
+	var lastTime *time.Time
+	if last != nil {
+		lastTime = &last.CompletedAt
+	}
+	_, dueDate := chore.ComputeStatus(c, lastTime, today)
+	return choreRotation(cs, c, householdID, chore.RotationDay(dueDate, today))
+}
+
+// choreRotation works out who is responsible for a chore on day.
+func choreRotation(cs *store.ChoreStore, c model.Chore, householdID int64, day time.Time) (chore.Rotation, []model.ChoreRotationEvent, error) {
+	if !c.Rotates() {
+		return chore.Responsible(c, day, 0, nil), nil, nil
+	}
+	completions, err := cs.CountCompletionsBefore(c.ID, householdID, day)
+	if err != nil {
+		return chore.Rotation{}, nil, err
+	}
+	events, err := cs.ListRotationEvents(c.ID, householdID)
+	if err != nil {
+		return chore.Rotation{}, nil, err
+	}
+	return chore.Responsible(c, day, completions, events), events, nil
+}
+
+// skipTurn records the current turn as skipped, passing it to the next
+// member in the rotation.
+func skipTurn(cs *store.ChoreStore, c model.Chore, householdID int64, today time.Time) (*model.ChoreRotationEvent, error) {
+	if !c.Rotates() {
+		return nil, errNotRotating
+	}
+	current, events, err := currentRotation(cs, c, householdID, today)
+	if err != nil {
+		return nil, err
+	}
+	members := chore.EligibleMembers(c, current.DueDate)
+	if len(members) < 2 {
+		return nil, errTooFewMembers
+	}
+
+	skip := model.ChoreRotationEvent{Kind: model.RotationSkip, Turn: current.Turn}
+	next := chore.TurnMember(members, current.Turn, append(events, skip))
+	return cs.CreateRotationEvent(c.ID, householdID, model.RotationSkip, current.Turn, current.DueDate.Format("2006-01-02"), current.MemberID, next)
+}
+
+// swapTurn records the current turn as handed to memberID, who gives their
+// next turn back in return.
+func swapTurn(cs *store.ChoreStore, c model.Chore, householdID int64, today time.Time, memberID int64) (*model.ChoreRotationEvent, error) {
+	if !c.Rotates() {
+		return nil, errNotRotating
+	}
+	inRotation := false
+	for _, id := range c.RotationMembers {
+		if id == memberID {
+			inRotation = true
+			break
+		}
+	}
+	if !inRotation {
+		return nil, errNotInRotation
+	}
+	current, _, err := currentRotation(cs, c, householdID, today)
+	if err != nil {
+		return nil, err
+	}
+	if current.MemberID != nil && *current.MemberID == memberID {
+		return nil, errAlreadyTheirTurn
+	}
+	if !c.RotationProfiles[memberID].OldEnough(c.MinAge, current.DueDate) {
+		return nil, errTooYoung
+	}
+	return cs.CreateRotationEvent(c.ID, householdID, model.RotationSwap, current.Turn, current.DueDate.Format("2006-01-02"), current.MemberID, &memberID)
+}
+
+// choreStats is a member's stats for one chore.
+type choreStats struct {
+	ChoreID int64  `json:"chore_id"`
+	Title   string `json:"title"`
+	chore.Stats
+}
+
+// memberStats is a member's stats across every recurring chore that has
+// fallen to them, with a breakdown per chore.
+type memberStats struct {
+	MemberID int64 `json:"member_id"`
+	chore.Stats
+	Chores []choreStats `json:"chores"`
+}
+
+// statsForMember summarises the occurrences in history that fell to memberID.
+func statsForMember(chores []model.Chore, history map[int64][]chore.OccurrenceResult, memberID int64) memberStats {
+	ms := memberStats{MemberID: memberID, Chores: []choreStats{}}
+	var all []chore.OccurrenceResult
+	for _, c := range chores {
+		results := chore.ForMember(history[c.ID], memberID)
+		if len(results) == 0 {
+			continue
+		}
+		all = append(all, results...)
+		ms.Chores = append(ms.Chores, choreStats{ChoreID: c.ID, Title: c.Title, Stats: chore.Summarize(results)})
+	}
+	ms.Stats = chore.Summarize(all)
+	return ms
+}
+
+// MemberStats returns a family member's streaks, on-time rate and missed
+// occurrences across recurring chores.
+func (h *ChoreHandler) MemberStats(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	memberID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid member id"})
+		return
+	}
+
+	member, err := h.memberStore.GetByID(memberID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get family member"})
+		return
+	}
+	if member == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "family member not found"})
+		return
+	}
+
+	today := time.Now().In(householdLocation(h.settingsStore, householdID, h.logger))
+	chores, history, err := chore.LoadHistory(h.choreStore, householdID, today)
+	if err != nil {
+		h.logger.Error("chore history", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get stats"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, statsForMember(chores, history, memberID))
+}
+
+const (
+	errNotRecurring    = choreError("chore does not repeat")
+	errNotAnOccurrence = choreError("chore is not due on that date")
+	errAlreadyDone     = choreError("that occurrence is already done")
+	errInvalidLetOff   = choreError(`status must be "excused" or "skipped"`)
+	errInvalidDueDate  = choreError("due date must be YYYY-MM-DD")
+)
+
+// occurrenceResponse is one occurrence in a chore's history. Status is
+// "done", "missed", "excused", "skipped", or "open" for the latest
+// occurrence while it can still be done.
+type occurrenceResponse struct {
+	DueDate     string `json:"due_date"`
+	Status      string `json:"status"`
+	OnTime      bool   `json:"on_time"`
+	MemberID    *int64 `json:"member_id"`
+	CompletedBy *int64 `json:"completed_by"`
+	Note        string `json:"note"`
+}
+
+func newOccurrenceResponse(r chore.OccurrenceResult) occurrenceResponse {
+	status := string(r.Outcome)
+	if r.Outcome.Done() {
+		status = string(model.OccurrenceDone)
+	}
+	return occurrenceResponse{
+		DueDate:     chore.DateKey(r.DueDate),
+		Status:      status,
+		OnTime:      r.Outcome == chore.OutcomeOnTime,
+		MemberID:    r.MemberID,
+		CompletedBy: r.CompletedBy,
+		Note:        r.Note,
+	}
+}
+
+// letOffOccurrence marks the occurrence due on dueDate as excused or
+// skipped, so it does not count as missed.
+func letOffOccurrence(cs *store.ChoreStore, c model.Chore, householdID int64, today time.Time, dueDate string, status model.OccurrenceStatus, note string) (*model.ChoreOccurrence, error) {
+	if status != model.OccurrenceExcused && status != model.OccurrenceSkipped {
+		return nil, errInvalidLetOff
+	}
+	if c.RecurrenceRule == "" {
+		return nil, errNotRecurring
+	}
+	history, err := chore.LoadChoreHistory(cs, c, householdID, today)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range history {
+		if chore.DateKey(r.DueDate) != dueDate {
+			continue
+		}
+		if r.Outcome.Done() {
+			return nil, errAlreadyDone
+		}
+		return cs.SetOccurrence(c.ID, householdID, dueDate, status, r.MemberID, nil, strings.TrimSpace(note))
+	}
+	return nil, errNotAnOccurrence
+}
+
+// ListOccurrences returns the occurrence history of a recurring chore.
+func (h *ChoreHandler) ListOccurrences(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	c, ok := h.choreFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	today := time.Now().In(householdLocation(h.settingsStore, householdID, h.logger))
+	history, err := chore.LoadChoreHistory(h.choreStore, *c, householdID, today)
+	if err != nil {
+		h.logger.Error("chore occurrences", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list occurrences"})
+		return
+	}
 
+	out := make([]occurrenceResponse, 0, len(history))
+	for _, res := range history {
+		out = append(out, newOccurrenceResponse(res))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// SetOccurrence excuses or skips one occurrence of a recurring chore.
+func (h *ChoreHandler) SetOccurrence(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	c, ok := h.choreFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	dueDate := r.PathValue("date")
+	if _, err := time.Parse("2006-01-02", dueDate); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": errInvalidDueDate.Error()})
+		return
+	}
+
+	var req struct {
+		Status model.OccurrenceStatus `json:"status"`
+		Note   string                 `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	today := time.Now().In(householdLocation(h.settingsStore, householdID, h.logger))
+	occ, err := letOffOccurrence(h.choreStore, *c, householdID, today, dueDate, req.Status, req.Note)
+	if err != nil {
+		h.writeChoreError(w, "set occurrence", err)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "updated", c.ID, nil))
+	writeJSON(w, http.StatusOK, occ)
+}
+
+// ClearOccurrence removes an occurrence's recorded outcome, so it is worked
+// out from completions again.
+func (h *ChoreHandler) ClearOccurrence(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	c, ok := h.choreFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.choreStore.DeleteOccurrence(c.ID, householdID, r.PathValue("date")); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to clear occurrence"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "updated", c.ID, nil))
 	w.WriteHeader(http.StatusNoContent)
 }
+
+const (
+	errInvalidReview  = choreError(`status must be "approved" or "rejected"`)
+	errNotPending     = choreError("completion is not waiting for approval")
+	errOwnCompletion  = choreError("members cannot approve their own chores")
+	errCannotApprove  = choreError("only adults with a PIN can approve chores")
+	errMemberNotFound = choreError("family member not found")
+)
+
+// requestApproval tells parents that a completion is waiting for them.
+func requestApproval(ms *store.FamilyMemberStore, pushSched *push.Scheduler, householdID int64, c model.Chore, completion *model.ChoreCompletion) {
+	if pushSched == nil {
+		return
+	}
+	var name string
+	if completion.CompletedBy != nil {
+		if m, err := ms.GetByID(*completion.CompletedBy, householdID); err == nil && m != nil {
+			name = m.Name
+		}
+	}
+	go pushSched.SendApprovalRequest(householdID, c.Title, name)
+}
+
+// earnedPoints returns the points completing c earns memberID on today:
+// the chore's points as they stand, scaled by the member's multiplier.
+// The member must belong to the household.
+func earnedPoints(ms *store.FamilyMemberStore, c model.Chore, householdID int64, memberID *int64, today time.Time) (int, error) {
+	if memberID == nil {
+		return c.Points, nil
+	}
+	member, err := ms.GetByID(*memberID, householdID)
+	if err != nil {
+		return 0, err
+	}
+	if member == nil {
+		return 0, errMemberNotFound
+	}
+	return member.ScalePoints(c.Points, today), nil
+}
+
+// reviewCompletion approves or rejects a pending completion on behalf of
+// reviewerID, whose PIN the caller has already checked. Only members who
+// can approve may review, and not their own completions. An approved completion earns the points
+// the chore is worth on today.
+func reviewCompletion(cs *store.ChoreStore, ms *store.FamilyMemberStore, householdID, completionID int64, status model.CompletionStatus, reviewerID int64, comment string, today time.Time) (*model.ChoreCompletion, error) {
+	if status != model.CompletionApproved && status != model.CompletionRejected {
+		return nil, errInvalidReview
+	}
+	existing, err := cs.GetCompletion(completionID, householdID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil || existing.Status != model.CompletionPending {
+		return nil, errNotPending
+	}
+	if existing.CompletedBy != nil && *existing.CompletedBy == reviewerID {
+		return nil, errOwnCompletion
+	}
+	reviewer, err := ms.GetByID(reviewerID, householdID)
+	if err != nil {
+		return nil, err
+	}
+	if reviewer == nil || !reviewer.CanApprove(today) {
+		return nil, errCannotApprove
+	}
+	c, err := cs.GetByID(existing.ChoreID, householdID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, errNotPending
+	}
+	points, err := earnedPoints(ms, *c, householdID, existing.CompletedBy, today)
+	if err != nil {
+		return nil, err
+	}
+	completion, err := cs.ReviewCompletion(completionID, householdID, status, reviewerID, points, strings.TrimSpace(comment))
+	if err != nil {
+		return nil, err
+	}
+	if completion == nil {
+		return nil, errNotPending
+	}
+	return completion, nil
+}
+
+// ListApprovals returns the completions waiting for a parent's approval.
+func (h *ChoreHandler) ListApprovals(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	completions, err := h.choreStore.ListPendingCompletions(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list approvals"})
+		return
+	}
+	if completions == nil {
+		completions = []model.ChoreCompletion{}
+	}
+	writeJSON(w, http.StatusOK, completions)
+}
+
+// ReviewCompletion approves or rejects a pending completion. The reviewer
+// must give their PIN.
+func (h *ChoreHandler) ReviewCompletion(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	completionID, err := strconv.ParseInt(r.PathValue("completion_id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid completion_id"})
+		return
+	}
+
+	var req struct {
+		Status     model.CompletionStatus `json:"status"`
+		ReviewedBy int64                  `json:"reviewed_by"`
+		PIN        string                 `json:"pin"`
+		Comment    string                 `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	if status, msg := verifyMemberPIN(h.memberStore, req.ReviewedBy, householdID, req.PIN); msg != "" {
+		writeJSON(w, status, map[string]string{"error": msg})
+		return
+	}
+
+	today := time.Now().In(householdLocation(h.settingsStore, householdID, h.logger))
+	completion, err := reviewCompletion(h.choreStore, h.memberStore, householdID, completionID, req.Status, req.ReviewedBy, req.Comment, today)
+	if errors.Is(err, errCannotApprove) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.writeChoreError(w, "review completion", err)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "reviewed", completion.ChoreID, nil))
+	writeJSON(w, http.StatusOK, completion)
+}
+
+// approvalView is a completion in the approval queue.
+type approvalView struct {
+	CompletionID int64
+	ChoreTitle   string
+	CompletedBy  int64
+	MemberName   string
+	MemberEmoji  string
+	CompletedAt  string
+	Points       int
+}
+
+// buildApprovalQueueData returns the household's completions waiting for a
+// parent's approval, oldest first.
+func (h *TemplateHandler) buildApprovalQueueData(householdID int64) ([]approvalView, error) {
+	pending, err := h.choreStore.ListPendingCompletions(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list pending completions: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	chores, err := h.choreStore.List(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list chores: %w", err)
+	}
+	choreMap := make(map[int64]model.Chore)
+	for _, c := range chores {
+		choreMap[c.ID] = c
+	}
+
+	members, err := h.store.List(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list members: %w", err)
+	}
+	memberMap := make(map[int64]model.FamilyMember)
+	for _, m := range members {
+		memberMap[m.ID] = m
+	}
+
+	loc := h.location(householdID)
+	views := make([]approvalView, 0, len(pending))
+	for _, p := range pending {
+		c := choreMap[p.ChoreID]
+		v := approvalView{
+			CompletionID: p.ID,
+			ChoreTitle:   c.Title,
+			CompletedAt:  p.CompletedAt.In(loc).Format("Mon 3:04 PM"),
+			Points:       c.Points,
+		}
+		if p.CompletedBy != nil {
+			v.CompletedBy = *p.CompletedBy
+			if m, ok := memberMap[*p.CompletedBy]; ok {
+				v.MemberName = m.Name
+				v.MemberEmoji = m.AvatarEmoji
+			}
+		}
+		views = append(views, v)
+	}
+	return views, nil
+}
+
+func (h *TemplateHandler) renderApprovalQueue(w http.ResponseWriter, householdID int64) {
+	approvals, err := h.buildApprovalQueueData(householdID)
+	if err != nil {
+		h.logger.Error("build approval queue", "error", err)
+		http.Error(w, "failed to load approvals", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "approval-queue", map[string]any{"Approvals": approvals})
+}
+
+// ChoreApprovalQueue renders the completions waiting for approval.
+func (h *TemplateHandler) ChoreApprovalQueue(w http.ResponseWriter, r *http.Request) {
+	h.renderApprovalQueue(w, auth.HouseholdID(r.Context()))
+}
+
+// ChoreApprovalReview starts approving or rejecting a completion. Without
+// an approver it asks which parent is reviewing; with one it asks for their
+// PIN, which is checked by PINVerifyThenAct.
+func (h *TemplateHandler) ChoreApprovalReview(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	action := r.URL.Query().Get("action")
+	if action != "approve" && action != "reject" {
+		http.Error(w, "invalid action", http.StatusBadRequest)
+		return
+	}
+
+	approvals, err := h.buildApprovalQueueData(householdID)
+	if err != nil {
+		h.logger.Error("build approval queue", "error", err)
+		http.Error(w, "failed to load approvals", http.StatusInternalServerError)
+		return
+	}
+	var item *approvalView
+	for i := range approvals {
+		if approvals[i].CompletionID == id {
+			item = &approvals[i]
+			break
+		}
+	}
+	if item == nil {
+		h.renderToast(w, "error", errNotPending.Error())
+		h.renderPartial(w, "approval-queue", map[string]any{"Approvals": approvals})
+		return
+	}
+
+	if approverID, err := strconv.ParseInt(r.URL.Query().Get("approver"), 10, 64); err == nil {
+		approver, err := h.store.GetByID(approverID, householdID)
+		if err != nil || approver == nil || !approver.CanApprove(time.Now()) {
+			http.Error(w, "family member not found", http.StatusNotFound)
+			return
+		}
+		h.renderPartial(w, "approval-pin", approvalPINData(*item, *approver, action))
+		return
+	}
+
+	members, err := h.store.List(householdID)
+	if err != nil {
+		http.Error(w, "failed to load family members", http.StatusInternalServerError)
+		return
+	}
+	var approvers []model.FamilyMember
+	for _, m := range members {
+		if m.CanApprove(time.Now()) && m.ID != item.CompletedBy {
+			approvers = append(approvers, m)
+		}
+	}
+
+	h.renderPartial(w, "approval-approver", map[string]any{
+		"Item":      item,
+		"Action":    action,
+		"Approvers": approvers,
+	})
+}
+
+func approvalPINData(item approvalView, approver model.FamilyMember, action string) map[string]any {
+	return map[string]any{
+		"CompletionID": item.CompletionID,
+		"ChoreTitle":   item.ChoreTitle,
+		"ApproverID":   approver.ID,
+		"ApproverName": approver.Name,
+		"Action":       action,
+	}
+}
+
+// reviewAfterPIN approves or rejects a completion once the reviewer's PIN
+// has been checked, then re-renders the approval queue.
+func (h *TemplateHandler) reviewAfterPIN(w http.ResponseWriter, r *http.Request, householdID, reviewerID int64, action string) {
+	completionID, err := strconv.ParseInt(r.FormValue("completion_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid completion_id", http.StatusBadRequest)
+		return
+	}
+
+	status, toastMsg := model.CompletionApproved, "Chore approved"
+	if action == "reject" {
+		status, toastMsg = model.CompletionRejected, "Chore sent back"
+	}
+	today := time.Now().In(h.location(householdID))
+	completion, err := reviewCompletion(h.choreStore, h.store, householdID, completionID, status, reviewerID, r.FormValue("comment"), today)
+	if err != nil {
+		var choreErr choreError
+		if !errors.As(err, &choreErr) {
+			h.logger.Error("review completion", "error", err)
+			http.Error(w, "failed to review completion", http.StatusInternalServerError)
+			return
+		}
+		h.renderToast(w, "error", choreErr.Error())
+	} else {
+		h.broadcast(householdID, websocket.NewMessage("chore", "reviewed", completion.ChoreID, nil))
+		h.renderToast(w, "success", toastMsg)
+	}
+
+	h.renderApprovalQueue(w, householdID)
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dukerupert/gamwich/internal/auth"
+	"github.com/dukerupert/gamwich/internal/chore"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/websocket"
+)
+
+// ListStarterPacks returns the built-in starter packs.
+func (h *ChoreHandler) ListStarterPacks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, chore.StarterPacks())
+}
+
+// ImportStarterPack adds a starter pack's chores to the household.
+func (h *ChoreHandler) ImportStarterPack(w http.ResponseWriter, r *http.Request) {
+	pack, ok := chore.FindStarterPack(r.PathValue("key"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "starter pack not found"})
+		return
+	}
+	h.importChores(w, auth.HouseholdID(r.Context()), pack.Chores)
+}
+
+// importChores adds template chores to the household and reports how many
+// were new.
+func (h *ChoreHandler) importChores(w http.ResponseWriter, householdID int64, chores []model.TemplateChore) {
+	n, err := h.choreStore.ImportTemplate(householdID, chores)
+	if err != nil {
+		h.logger.Error("import chores", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to import chores"})
+		return
+	}
+	if n > 0 {
+		h.broadcast(householdID, websocket.NewMessage("chore", "imported", 0, nil))
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"created": n})
+}
+
+// ListTemplates returns the chore templates the household has saved.
+func (h *ChoreHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	templates, err := h.choreStore.ListTemplates(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list templates"})
+		return
+	}
+	if templates == nil {
+		templates = []model.ChoreTemplate{}
+	}
+	writeJSON(w, http.StatusOK, templates)
+}
+
+// hasRecurringChores reports whether the household has any chores worth
+// saving as a template.
+func hasRecurringChores(chores []model.Chore) bool {
+	for _, c := range chores {
+		if c.RecurrenceRule != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveTemplate saves the household's recurring chores as a template.
+func (h *ChoreHandler) SaveTemplate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+		return
+	}
+
+	chores, err := h.choreStore.List(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list chores"})
+		return
+	}
+	if !hasRecurringChores(chores) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "there are no recurring chores to save"})
+		return
+	}
+
+	saved, err := h.choreStore.SaveTemplate(householdID, req.Name, strings.TrimSpace(req.Description))
+	if err != nil {
+		h.logger.Error("save chore template", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save template"})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, saved)
+}
+
+// ImportTemplate adds one of the household's saved templates back to its
+// chores, such as after clearing them out.
+func (h *ChoreHandler) ImportTemplate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("template_id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid template_id"})
+		return
+	}
+
+	saved, err := h.choreStore.GetTemplate(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get template"})
+		return
+	}
+	if saved == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "template not found"})
+		return
+	}
+	h.importChores(w, householdID, saved.Chores)
+}
+
+// ImportSharedTemplate adds the chores of another household's template,
+// given its share code.
+func (h *ChoreHandler) ImportSharedTemplate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ShareCode string `json:"share_code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	saved, err := h.choreStore.GetTemplateByShareCode(req.ShareCode)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get template"})
+		return
+	}
+	if saved == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "template not found"})
+		return
+	}
+	h.importChores(w, auth.HouseholdID(r.Context()), saved.Chores)
+}
+
+// DeleteTemplate removes a saved template. Chores already made from it are
+// kept.
+func (h *ChoreHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("template_id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid template_id"})
+		return
+	}
+
+	if err := h.choreStore.DeleteTemplate(id, householdID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete template"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
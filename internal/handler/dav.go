@@ -0,0 +1,897 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/auth"
+	"github.com/dukerupert/gamwich/internal/caldav"
+	"github.com/dukerupert/gamwich/internal/calsync"
+	"github.com/dukerupert/gamwich/internal/ical"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/recurrence"
+	"github.com/dukerupert/gamwich/internal/store"
+	"github.com/dukerupert/gamwich/internal/websocket"
+)
+
+// davRoot is the path the built-in CalDAV server is mounted at.
+const davRoot = "/dav/"
+
+// householdCollection is the path segment of the collection holding events
+// without a family member.
+const householdCollection = "household"
+
+const maxDAVBody = 1 << 20
+
+// DAVHandler serves the household calendar over CalDAV so phones and desktop
+// clients can add it as an account. Clients sign in with a user's email and
+// an app password. Each household has one collection for household-wide
+// events and one per family member, at
+// /dav/calendars/{household}/{household|memberID}/.
+type DAVHandler struct {
+	davStore       *store.DAVStore
+	eventStore     *store.EventStore
+	memberStore    *store.FamilyMemberStore
+	householdStore *store.HouseholdStore
+	calSync        *calsync.Scheduler
+	settingsStore  *store.SettingsStore
+	hub            *websocket.Hub
+	logger         *slog.Logger
+}
+
+func NewDAVHandler(ds *store.DAVStore, es *store.EventStore, ms *store.FamilyMemberStore, hs *store.HouseholdStore, ss *store.SettingsStore, cs *calsync.Scheduler, hub *websocket.Hub, logger *slog.Logger) *DAVHandler {
+	return &DAVHandler{
+		davStore:       ds,
+		eventStore:     es,
+		memberStore:    ms,
+		householdStore: hs,
+		settingsStore:  ss,
+		calSync:        cs,
+		hub:            hub,
+		logger:         logger,
+	}
+}
+
+func (h *DAVHandler) broadcast(householdID int64, msg websocket.Message) {
+	if h.hub != nil {
+		h.hub.BroadcastToHousehold(householdID, msg)
+	}
+}
+
+// WellKnown handles /.well-known/caldav by pointing clients at the server root.
+func (h *DAVHandler) WellKnown(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, davRoot, http.StatusMovedPermanently)
+}
+
+// davCollection is a calendar collection: the household's events, or one
+// family member's.
+type davCollection struct {
+	householdID int64
+	segment     string
+	name        string
+	color       string
+	memberID    *int64
+}
+
+func (c davCollection) href() string {
+	return davRoot + "calendars/" + strconv.FormatInt(c.householdID, 10) + "/" + c.segment + "/"
+}
+
+func (c davCollection) contains(e model.CalendarEvent) bool {
+	return e.RecurrenceParentID == nil && sameMember(e.FamilyMemberID, c.memberID)
+}
+
+// davObject is an event series served as a calendar object resource.
+type davObject struct {
+	name  string
+	uid   string
+	event model.CalendarEvent
+	data  []byte
+	etag  string
+}
+
+// davPath is a parsed request path below davRoot.
+type davPath struct {
+	kind        string // "root", "principal", "home", "collection", or "object"
+	principalID int64
+	householdID int64
+	collection  string
+	object      string
+}
+
+func parseDAVPath(p string) (davPath, bool) {
+	rest, ok := strings.CutPrefix(p, davRoot)
+	if !ok {
+		return davPath{}, false
+	}
+	rest = strings.TrimSuffix(rest, "/")
+	if rest == "" {
+		return davPath{kind: "root"}, true
+	}
+	parts := strings.Split(rest, "/")
+	switch {
+	case parts[0] == "principals" && len(parts) == 2:
+		id, err := strconv.ParseInt(parts[1], 10, 64)
+		return davPath{kind: "principal", principalID: id}, err == nil
+	case parts[0] == "calendars" && len(parts) >= 2 && len(parts) <= 4:
+		id, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return davPath{}, false
+		}
+		dp := davPath{kind: "home", householdID: id}
+		if len(parts) >= 3 {
+			dp.kind, dp.collection = "collection", parts[2]
+		}
+		if len(parts) == 4 {
+			dp.kind, dp.object = "object", parts[3]
+		}
+		return dp, true
+	}
+	return davPath{}, false
+}
+
+// ServeHTTP handles every request below /dav/.
+func (h *DAVHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.Header().Set("DAV", "1, 3, calendar-access")
+		w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	ap := h.authenticate(w, r)
+	if ap == nil {
+		return
+	}
+
+	dp, ok := parseDAVPath(r.URL.Path)
+	if !ok || (dp.householdID != 0 && dp.householdID != ap.HouseholdID) || (dp.principalID != 0 && dp.principalID != ap.UserID) {
+		http.NotFound(w, r)
+		return
+	}
+
+	var coll *davCollection
+	if dp.collection != "" {
+		c, err := h.collection(ap.HouseholdID, dp.collection)
+		if err != nil {
+			h.logger.Error("load dav collection", "error", err)
+			http.Error(w, "failed to load calendar", http.StatusInternalServerError)
+			return
+		}
+		if c == nil {
+			http.NotFound(w, r)
+			return
+		}
+		coll = c
+	}
+
+	switch r.Method {
+	case "PROPFIND":
+		h.propfind(w, r, ap, dp, coll)
+	case "REPORT":
+		if coll == nil || dp.object != "" {
+			http.Error(w, "reports are only supported on calendars", http.StatusMethodNotAllowed)
+			return
+		}
+		h.report(w, r, *coll)
+	case http.MethodGet, http.MethodHead:
+		if dp.object == "" {
+			http.Error(w, "not a calendar object", http.StatusMethodNotAllowed)
+			return
+		}
+		h.get(w, r, *coll, dp.object)
+	case http.MethodPut:
+		if dp.object == "" {
+			http.Error(w, "not a calendar object", http.StatusMethodNotAllowed)
+			return
+		}
+		h.put(w, r, *coll, dp.object)
+	case http.MethodDelete:
+		if dp.object == "" {
+			http.Error(w, "calendars cannot be deleted", http.StatusForbidden)
+			return
+		}
+		h.delete(w, r, *coll, dp.object)
+	default:
+		w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authenticate checks HTTP Basic credentials against the app passwords of
+// users still in the password's household, writing a 401 if they fail.
+func (h *DAVHandler) authenticate(w http.ResponseWriter, r *http.Request) *model.AppPassword {
+	email, password, ok := r.BasicAuth()
+	if ok {
+		ap, err := h.davStore.Authenticate(email, password)
+		if err != nil {
+			h.logger.Error("authenticate app password", "error", err)
+			http.Error(w, "failed to authenticate", http.StatusInternalServerError)
+			return nil
+		}
+		if ap != nil {
+			member, err := h.householdStore.GetMember(ap.HouseholdID, ap.UserID)
+			if err == nil && member != nil {
+				if err := h.davStore.MarkAppPasswordUsed(ap.ID); err != nil {
+					h.logger.Warn("mark app password used", "error", err)
+				}
+				return ap
+			}
+		}
+	}
+	w.Header().Set("WWW-Authenticate", `Basic realm="Gamwich", charset="UTF-8"`)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+	return nil
+}
+
+// collection resolves a collection path segment, or returns nil if the
+// household has no such collection.
+func (h *DAVHandler) collection(householdID int64, segment string) (*davCollection, error) {
+	if segment == householdCollection {
+		name := "Household"
+		if household, err := h.householdStore.GetByID(householdID); err == nil && household != nil {
+			name = household.Name
+		}
+		return &davCollection{householdID: householdID, segment: segment, name: name}, nil
+	}
+	id, err := strconv.ParseInt(segment, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+	member, err := h.memberStore.GetByID(id, householdID)
+	if err != nil || member == nil {
+		return nil, err
+	}
+	return &davCollection{householdID: householdID, segment: segment, name: member.Name, color: member.Color, memberID: &member.ID}, nil
+}
+
+func (h *DAVHandler) collections(householdID int64) ([]davCollection, error) {
+	household, err := h.collection(householdID, householdCollection)
+	if err != nil {
+		return nil, err
+	}
+	members, err := h.memberStore.List(householdID)
+	if err != nil {
+		return nil, err
+	}
+	colls := []davCollection{*household}
+	for _, m := range members {
+		colls = append(colls, davCollection{
+			householdID: householdID,
+			segment:     strconv.FormatInt(m.ID, 10),
+			name:        m.Name,
+			color:       m.Color,
+			memberID:    &m.ID,
+		})
+	}
+	return colls, nil
+}
+
+// objects returns the series in a collection, using the same window as the
+// iCalendar feeds.
+func (h *DAVHandler) objects(coll davCollection) ([]davObject, error) {
+	events, err := h.eventStore.ListForFeed(coll.householdID, time.Now().Add(-feedLookback))
+	if err != nil {
+		return nil, err
+	}
+	resources, err := h.davStore.ListResources(coll.householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions := make(map[int64][]model.CalendarEvent)
+	for _, e := range events {
+		if e.RecurrenceParentID != nil {
+			exceptions[*e.RecurrenceParentID] = append(exceptions[*e.RecurrenceParentID], e)
+		}
+	}
+
+	loc := householdLocation(h.settingsStore, coll.householdID, h.logger)
+	var objects []davObject
+	for _, e := range events {
+		if !coll.contains(e) {
+			continue
+		}
+		res, ok := resources[e.ID]
+		obj, err := h.encodeObject(e, exceptions[e.ID], res, ok, loc)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// object returns the series stored under name in a collection, or nil.
+func (h *DAVHandler) object(coll davCollection, name string) (*davObject, error) {
+	var res model.DAVResource
+	var eventID int64
+	found, err := h.davStore.GetResource(coll.householdID, name)
+	if err != nil {
+		return nil, err
+	}
+	if found != nil {
+		res, eventID = *found, found.EventID
+	} else if eventID = defaultEventID(name); eventID == 0 {
+		return nil, nil
+	}
+
+	e, err := h.eventStore.GetByID(eventID, coll.householdID)
+	if err != nil || e == nil || !coll.contains(*e) {
+		return nil, err
+	}
+	exceptions, err := h.eventStore.ListExceptions(e.ID, coll.householdID)
+	if err != nil {
+		return nil, err
+	}
+	loc := householdLocation(h.settingsStore, coll.householdID, h.logger)
+	obj, err := h.encodeObject(*e, exceptions, res, found != nil, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+// defaultEventID parses the name of a series no client has named, returning
+// 0 if name is not one.
+func defaultEventID(name string) int64 {
+	uid, ok := strings.CutSuffix(name, ".ics")
+	if !ok {
+		return 0
+	}
+	idStr, ok := strings.CutPrefix(uid, "event-")
+	if !ok {
+		return 0
+	}
+	idStr, ok = strings.CutSuffix(idStr, "@gamwich")
+	if !ok {
+		return 0
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || ical.UID(id) != uid {
+		return 0
+	}
+	return id
+}
+
+// encodeObject renders a series with its times in loc. The DTSTAMP is the
+// series' last change so the data, and the ETag derived from it, only
+// change when the series or the household timezone does.
+func (h *DAVHandler) encodeObject(e model.CalendarEvent, exceptions []model.CalendarEvent, res model.DAVResource, named bool, loc *time.Location) (davObject, error) {
+	obj := davObject{name: ical.UID(e.ID) + ".ics", uid: ical.UID(e.ID), event: e}
+	if named {
+		obj.name, obj.uid = res.Name, res.UID
+	}
+
+	stamp := e.UpdatedAt
+	for _, exc := range exceptions {
+		if exc.UpdatedAt.After(stamp) {
+			stamp = exc.UpdatedAt
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := ical.EncodeObject(&buf, obj.uid, e, exceptions, loc, stamp); err != nil {
+		return davObject{}, err
+	}
+	obj.data = buf.Bytes()
+	sum := sha256.Sum256(obj.data)
+	obj.etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	return obj, nil
+}
+
+func (o davObject) href(coll davCollection) string {
+	return coll.href() + url.PathEscape(o.name)
+}
+
+// ctag changes whenever any object in the collection does.
+func ctag(objects []davObject) string {
+	keys := make([]string, 0, len(objects))
+	for _, o := range objects {
+		keys = append(keys, o.name+"\x00"+o.etag)
+	}
+	sort.Strings(keys)
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (h *DAVHandler) propfind(w http.ResponseWriter, r *http.Request, ap *model.AppPassword, dp davPath, coll *davCollection) {
+	requested, err := caldav.ParsePropfind(io.LimitReader(r.Body, maxDAVBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	depth1 := r.Header.Get("Depth") != "0"
+
+	principal := davRoot + "principals/" + strconv.FormatInt(ap.UserID, 10) + "/"
+	home := davRoot + "calendars/" + strconv.FormatInt(ap.HouseholdID, 10) + "/"
+	var responses []caldav.PropResponse
+
+	switch dp.kind {
+	case "root", "principal":
+		href := davRoot
+		if dp.kind == "principal" {
+			href = principal
+		}
+		responses = append(responses, selectProps(href, requested, []caldav.Property{
+			{Name: caldav.PropResourceType, Inner: principalResourceType(dp.kind)},
+			caldav.HrefProperty(caldav.PropCurrentUserPrincipal, principal),
+			caldav.HrefProperty(caldav.PropPrincipalURL, principal),
+			caldav.HrefProperty(caldav.PropCalendarHomeSet, home),
+			caldav.TextProperty(caldav.PropDisplayName, "Gamwich"),
+		}))
+
+	case "home":
+		responses = append(responses, selectProps(home, requested, []caldav.Property{
+			{Name: caldav.PropResourceType, Inner: "<d:collection/>"},
+			caldav.HrefProperty(caldav.PropCurrentUserPrincipal, principal),
+			caldav.TextProperty(caldav.PropDisplayName, "Calendars"),
+		}))
+		if depth1 {
+			colls, err := h.collections(ap.HouseholdID)
+			if err != nil {
+				h.logger.Error("list dav collections", "error", err)
+				http.Error(w, "failed to load calendars", http.StatusInternalServerError)
+				return
+			}
+			for _, c := range colls {
+				resp, err := h.collectionResponse(c, principal, requested)
+				if err != nil {
+					h.logger.Error("load dav collection", "error", err)
+					http.Error(w, "failed to load calendar", http.StatusInternalServerError)
+					return
+				}
+				responses = append(responses, resp)
+			}
+		}
+
+	case "collection":
+		objects, err := h.objects(*coll)
+		if err != nil {
+			h.logger.Error("list dav objects", "error", err)
+			http.Error(w, "failed to load events", http.StatusInternalServerError)
+			return
+		}
+		responses = append(responses, collectionProps(*coll, principal, ctag(objects), requested))
+		if depth1 {
+			for _, o := range objects {
+				responses = append(responses, objectProps(*coll, o, requested, false))
+			}
+		}
+
+	case "object":
+		obj, err := h.object(*coll, dp.object)
+		if err != nil {
+			h.logger.Error("get dav object", "error", err)
+			http.Error(w, "failed to load event", http.StatusInternalServerError)
+			return
+		}
+		if obj == nil {
+			http.NotFound(w, r)
+			return
+		}
+		responses = append(responses, objectProps(*coll, *obj, requested, false))
+	}
+
+	caldav.WriteMultistatus(w, responses)
+}
+
+func principalResourceType(kind string) string {
+	if kind == "principal" {
+		return "<d:principal/>"
+	}
+	return "<d:collection/>"
+}
+
+func (h *DAVHandler) collectionResponse(coll davCollection, principal string, requested []xml.Name) (caldav.PropResponse, error) {
+	objects, err := h.objects(coll)
+	if err != nil {
+		return caldav.PropResponse{}, err
+	}
+	return collectionProps(coll, principal, ctag(objects), requested), nil
+}
+
+func collectionProps(coll davCollection, principal, ctag string, requested []xml.Name) caldav.PropResponse {
+	props := []caldav.Property{
+		{Name: caldav.PropResourceType, Inner: "<d:collection/><c:calendar/>"},
+		caldav.TextProperty(caldav.PropDisplayName, coll.name),
+		caldav.TextProperty(caldav.PropGetCTag, ctag),
+		caldav.HrefProperty(caldav.PropCurrentUserPrincipal, principal),
+		{Name: caldav.PropSupportedComponentSet, Inner: `<c:comp name="VEVENT"/>`},
+		{Name: caldav.PropSupportedReportSet, Inner: "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>"},
+		{Name: caldav.PropCurrentUserPrivilegeSet, Inner: "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>" +
+			"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege><d:privilege><d:unbind/></d:privilege>"},
+	}
+	if coll.color != "" {
+		props = append(props, caldav.TextProperty(caldav.PropCalendarColor, coll.color))
+	}
+	return selectProps(coll.href(), requested, props)
+}
+
+// objectProps describes a calendar object. calendar-data is only included
+// when asked for by name.
+func objectProps(coll davCollection, o davObject, requested []xml.Name, withData bool) caldav.PropResponse {
+	props := []caldav.Property{
+		{Name: caldav.PropResourceType},
+		caldav.TextProperty(caldav.PropGetETag, o.etag),
+		caldav.TextProperty(caldav.PropGetContentType, "text/calendar; charset=utf-8; component=VEVENT"),
+	}
+	if withData || containsName(requested, caldav.PropCalendarData) {
+		props = append(props, caldav.TextProperty(caldav.PropCalendarData, string(o.data)))
+	}
+	return selectProps(o.href(coll), requested, props)
+}
+
+// selectProps keeps the requested properties, reporting the ones the
+// resource does not have as not found. A nil request selects everything.
+func selectProps(href string, requested []xml.Name, available []caldav.Property) caldav.PropResponse {
+	resp := caldav.PropResponse{Href: href}
+	if requested == nil {
+		resp.Props = available
+		return resp
+	}
+	for _, name := range requested {
+		found := false
+		for _, p := range available {
+			if p.Name == name {
+				resp.Props = append(resp.Props, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			resp.NotFound = append(resp.NotFound, name)
+		}
+	}
+	return resp
+}
+
+func containsName(names []xml.Name, name xml.Name) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *DAVHandler) report(w http.ResponseWriter, r *http.Request, coll davCollection) {
+	rep, err := caldav.ParseReport(io.LimitReader(r.Body, maxDAVBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if rep.Name != caldav.ReportCalendarQuery && rep.Name != caldav.ReportCalendarMultiget {
+		caldav.WriteError(w, http.StatusForbidden, xml.Name{Space: caldav.NSDAV, Local: "supported-report"})
+		return
+	}
+
+	objects, err := h.objects(coll)
+	if err != nil {
+		h.logger.Error("list dav objects", "error", err)
+		http.Error(w, "failed to load events", http.StatusInternalServerError)
+		return
+	}
+	withData := rep.Props == nil
+
+	var responses []caldav.PropResponse
+	if rep.Name == caldav.ReportCalendarMultiget {
+		byName := make(map[string]davObject, len(objects))
+		for _, o := range objects {
+			byName[o.name] = o
+		}
+		for _, raw := range rep.Hrefs {
+			name, inColl := strings.CutPrefix(pathOf(raw), coll.href())
+			o, ok := byName[name]
+			if inColl && !ok {
+				// Objects outside the feed window are still fetchable by name.
+				if obj, err := h.object(coll, name); err == nil && obj != nil {
+					o, ok = *obj, true
+				}
+			}
+			if !ok {
+				responses = append(responses, caldav.PropResponse{Href: raw, Status: http.StatusNotFound})
+				continue
+			}
+			responses = append(responses, objectProps(coll, o, rep.Props, withData))
+		}
+	} else {
+		// Event times are wall clock in the household timezone; the
+		// time-range is in UTC.
+		loc := householdLocation(h.settingsStore, coll.householdID, h.logger)
+		start, end := rep.Start, rep.End
+		if !start.IsZero() {
+			start = recurrence.WallClock(start, loc)
+		}
+		if !end.IsZero() {
+			end = recurrence.WallClock(end, loc)
+		}
+		for _, o := range objects {
+			if inRange(o.event, start, end) {
+				responses = append(responses, objectProps(coll, o, rep.Props, withData))
+			}
+		}
+	}
+
+	caldav.WriteMultistatus(w, responses)
+}
+
+func pathOf(href string) string {
+	if u, err := url.Parse(href); err == nil {
+		return u.Path
+	}
+	return href
+}
+
+// inRange reports whether a series may have occurrences between start and
+// end. Recurring series are matched on their first start and UNTIL, which
+// can include a series whose occurrences all fall outside the range;
+// clients filter those themselves.
+func inRange(e model.CalendarEvent, start, end time.Time) bool {
+	if !end.IsZero() && !e.StartTime.Before(end) {
+		return false
+	}
+	if start.IsZero() {
+		return true
+	}
+	if e.RecurrenceRule == "" {
+		return e.EndTime.After(start)
+	}
+	rule, err := recurrence.Parse(e.RecurrenceRule)
+	if err != nil || rule.Until == nil {
+		return true
+	}
+	return !rule.Until.Before(start.Add(-e.EndTime.Sub(e.StartTime)))
+}
+
+func (h *DAVHandler) get(w http.ResponseWriter, r *http.Request, coll davCollection, name string) {
+	obj, err := h.object(coll, name)
+	if err != nil {
+		h.logger.Error("get dav object", "error", err)
+		http.Error(w, "failed to load event", http.StatusInternalServerError)
+		return
+	}
+	if obj == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("ETag", obj.etag)
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.Write(obj.data)
+}
+
+var (
+	condValidData      = xml.Name{Space: caldav.NSCalDAV, Local: "valid-calendar-data"}
+	condValidObject    = xml.Name{Space: caldav.NSCalDAV, Local: "valid-calendar-object-resource"}
+	condSupportedData  = xml.Name{Space: caldav.NSCalDAV, Local: "supported-calendar-data"}
+	condNoUIDConflict  = xml.Name{Space: caldav.NSCalDAV, Local: "no-uid-conflict"}
+	condNeedPrivileges = xml.Name{Space: caldav.NSDAV, Local: "need-privileges"}
+)
+
+// put creates or replaces a series from a calendar object. Recurrence rules
+// are stored in the form recurrence.Rule writes them; objects with rules it
+// cannot expand are rejected rather than saved without their recurrence.
+func (h *DAVHandler) put(w http.ResponseWriter, r *http.Request, coll davCollection, name string) {
+	existing, err := h.object(coll, name)
+	if err != nil {
+		h.logger.Error("get dav object", "error", err)
+		http.Error(w, "failed to load event", http.StatusInternalServerError)
+		return
+	}
+	if !preconditionsMet(r, existing) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	if existing != nil && existing.event.Imported() {
+		caldav.WriteError(w, http.StatusForbidden, condNeedPrivileges)
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(strings.ToLower(ct), "text/calendar") {
+		caldav.WriteError(w, http.StatusUnsupportedMediaType, condSupportedData)
+		return
+	}
+
+	parsed, err := ical.Parse(io.LimitReader(r.Body, maxDAVBody), householdLocation(h.settingsStore, coll.householdID, h.logger))
+	if err != nil {
+		caldav.WriteError(w, http.StatusBadRequest, condValidData)
+		return
+	}
+	master, overrides := calsync.SplitObject(parsed)
+	if master == nil {
+		caldav.WriteError(w, http.StatusForbidden, condValidObject)
+		return
+	}
+	for _, o := range overrides {
+		if o.UID != master.UID {
+			caldav.WriteError(w, http.StatusForbidden, condValidObject)
+			return
+		}
+	}
+
+	if existing == nil {
+		taken, err := h.davStore.GetResourceByUID(coll.householdID, master.UID)
+		if err != nil {
+			h.logger.Error("get dav resource", "error", err)
+			http.Error(w, "failed to save event", http.StatusInternalServerError)
+			return
+		}
+		if taken != nil || defaultEventID(master.UID+".ics") != 0 {
+			caldav.WriteError(w, http.StatusForbidden, condNoUIDConflict)
+			return
+		}
+	} else if master.UID != existing.uid {
+		caldav.WriteError(w, http.StatusForbidden, condNoUIDConflict)
+		return
+	}
+
+	var rule string
+	if master.RRule != "" {
+		supported, err := calsync.SupportedRule(master.RRule)
+		if err != nil {
+			h.logger.Info("rejecting unsupported recurrence rule", "rrule", master.RRule, "error", err)
+			caldav.WriteError(w, http.StatusForbidden, condValidObject)
+			return
+		}
+		rule = supported
+	}
+
+	var local *model.CalendarEvent
+	if existing != nil {
+		local = &existing.event
+	}
+	event, err := calsync.SaveSeries(h.eventStore, coll.householdID, local, coll.memberID, *master, overrides, rule)
+	if err != nil {
+		h.logger.Error("save dav object", "error", err)
+		http.Error(w, "failed to save event", http.StatusInternalServerError)
+		return
+	}
+	if err := h.davStore.SaveResource(coll.householdID, event.ID, name, master.UID); err != nil {
+		h.logger.Error("save dav resource", "error", err)
+		http.Error(w, "failed to save event", http.StatusInternalServerError)
+		return
+	}
+
+	// The stored object is rewritten from the saved series, so no ETag is
+	// returned and clients fetch it again.
+	if existing != nil {
+		h.broadcast(coll.householdID, websocket.NewMessage("calendar_event", "updated", event.ID, nil))
+		w.WriteHeader(http.StatusNoContent)
+	} else {
+		h.broadcast(coll.householdID, websocket.NewMessage("calendar_event", "created", event.ID, nil))
+		w.WriteHeader(http.StatusCreated)
+	}
+	h.calSync.Notify(coll.householdID)
+}
+
+func (h *DAVHandler) delete(w http.ResponseWriter, r *http.Request, coll davCollection, name string) {
+	existing, err := h.object(coll, name)
+	if err != nil {
+		h.logger.Error("get dav object", "error", err)
+		http.Error(w, "failed to load event", http.StatusInternalServerError)
+		return
+	}
+	if existing == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if !preconditionsMet(r, existing) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	if existing.event.Imported() {
+		caldav.WriteError(w, http.StatusForbidden, condNeedPrivileges)
+		return
+	}
+
+	if err := h.eventStore.Delete(existing.event.ID, coll.householdID); err != nil {
+		h.logger.Error("delete dav object", "error", err)
+		http.Error(w, "failed to delete event", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(coll.householdID, websocket.NewMessage("calendar_event", "deleted", existing.event.ID, nil))
+	h.calSync.Notify(coll.householdID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// preconditionsMet checks If-Match and If-None-Match against the object's
+// current ETag.
+func preconditionsMet(r *http.Request, existing *davObject) bool {
+	if match := r.Header.Get("If-Match"); match != "" {
+		if existing == nil {
+			return false
+		}
+		if match != "*" && !etagListContains(match, existing.etag) {
+			return false
+		}
+	}
+	if none := r.Header.Get("If-None-Match"); none != "" && existing != nil {
+		if none == "*" || etagListContains(none, existing.etag) {
+			return false
+		}
+	}
+	return true
+}
+
+func etagListContains(list, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// appPasswordResponse is returned once, when an app password is created.
+type appPasswordResponse struct {
+	*model.AppPassword
+	Password  string `json:"password"`
+	ServerURL string `json:"server_url"`
+}
+
+// ListAppPasswords handles GET /api/app-passwords for the signed-in user.
+func (h *DAVHandler) ListAppPasswords(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	passwords, err := h.davStore.ListAppPasswords(auth.UserID(r.Context()), householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list app passwords"})
+		return
+	}
+	if passwords == nil {
+		passwords = []model.AppPassword{}
+	}
+	writeJSON(w, http.StatusOK, passwords)
+}
+
+// CreateAppPassword handles POST /api/app-passwords. The password is only
+// included in this response.
+func (h *DAVHandler) CreateAppPassword(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	var req struct {
+		Label string `json:"label"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	ap, password, err := h.davStore.CreateAppPassword(auth.UserID(r.Context()), householdID, strings.TrimSpace(req.Label))
+	if err != nil {
+		h.logger.Error("create app password", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create app password"})
+		return
+	}
+	writeJSON(w, http.StatusCreated, appPasswordResponse{AppPassword: ap, Password: password, ServerURL: feedBaseURL(r) + davRoot})
+}
+
+// DeleteAppPassword handles DELETE /api/app-passwords/{id}.
+func (h *DAVHandler) DeleteAppPassword(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+	if err := h.davStore.DeleteAppPassword(id, auth.UserID(r.Context()), householdID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete app password"})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
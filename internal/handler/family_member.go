@@ -2,12 +2,15 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dukerupert/gamwich/internal/auth"
 	"github.com/dukerupert/gamwich/internal/model"
 	"github.com/dukerupert/gamwich/internal/store"
 	"github.com/dukerupert/gamwich/internal/websocket"
@@ -26,14 +29,15 @@ func NewFamilyMemberHandler(s *store.FamilyMemberStore, hub *websocket.Hub, logg
 	return &FamilyMemberHandler{store: s, hub: hub, logger: logger}
 }
 
-func (h *FamilyMemberHandler) broadcast(msg websocket.Message) {
+func (h *FamilyMemberHandler) broadcast(householdID int64, msg websocket.Message) {
 	if h.hub != nil {
-		h.hub.Broadcast(msg)
+		h.hub.BroadcastToHousehold(householdID, msg)
 	}
 }
 
 func (h *FamilyMemberHandler) List(w http.ResponseWriter, r *http.Request) {
-	members, err := h.store.List()
+	householdID := auth.HouseholdID(r.Context())
+	members, err := h.store.List(householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list family members"})
 		return
@@ -45,10 +49,12 @@ func (h *FamilyMemberHandler) List(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *FamilyMemberHandler) Create(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	var req struct {
 		Name        string `json:"name"`
 		Color       string `json:"color"`
 		AvatarEmoji string `json:"avatar_emoji"`
+		profileRequest
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
@@ -73,7 +79,13 @@ func (h *FamilyMemberHandler) Create(w http.ResponseWriter, r *http.Request) {
 		req.AvatarEmoji = "😀"
 	}
 
-	exists, err := h.store.NameExists(req.Name, 0)
+	profile := req.apply(model.FamilyMember{})
+	if msg := validateProfile(profile); msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
+
+	exists, err := h.store.NameExists(householdID, req.Name, 0)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to check name"})
 		return
@@ -83,26 +95,40 @@ func (h *FamilyMemberHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	member, err := h.store.Create(req.Name, req.Color, req.AvatarEmoji)
+	member, err := h.store.Create(householdID, req.Name, req.Color, req.AvatarEmoji)
 	if err != nil {
 		h.logger.Error("create family member", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create family member"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("family_member", "created", member.ID, nil))
+	if profile.Birthdate != "" || profile.Role != model.RoleUnset || profile.PointsPercent != nil {
+		if err := h.store.SetProfile(member.ID, householdID, profile.Birthdate, profile.Role, profile.PointsPercent); err != nil {
+			h.logger.Error("set family member profile", "error", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create family member"})
+			return
+		}
+		member, err = h.store.GetByID(member.ID, householdID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get family member"})
+			return
+		}
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("family_member", "created", member.ID, nil))
 
 	writeJSON(w, http.StatusCreated, member)
 }
 
 func (h *FamilyMemberHandler) Update(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.store.GetByID(id)
+	existing, err := h.store.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get family member"})
 		return
@@ -116,6 +142,7 @@ func (h *FamilyMemberHandler) Update(w http.ResponseWriter, r *http.Request) {
 		Name        string `json:"name"`
 		Color       string `json:"color"`
 		AvatarEmoji string `json:"avatar_emoji"`
+		profileRequest
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
@@ -140,7 +167,13 @@ func (h *FamilyMemberHandler) Update(w http.ResponseWriter, r *http.Request) {
 		req.AvatarEmoji = existing.AvatarEmoji
 	}
 
-	exists, err := h.store.NameExists(req.Name, id)
+	profile := req.apply(*existing)
+	if msg := validateProfile(profile); msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
+
+	exists, err := h.store.NameExists(householdID, req.Name, id)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to check name"})
 		return
@@ -150,25 +183,35 @@ func (h *FamilyMemberHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	member, err := h.store.Update(id, req.Name, req.Color, req.AvatarEmoji)
-	if err != nil {
+	if _, err := h.store.Update(id, householdID, req.Name, req.Color, req.AvatarEmoji); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update family member"})
 		return
 	}
+	if err := h.store.SetProfile(id, householdID, profile.Birthdate, profile.Role, profile.PointsPercent); err != nil {
+		h.logger.Error("set family member profile", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update family member"})
+		return
+	}
+	member, err := h.store.GetByID(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get family member"})
+		return
+	}
 
-	h.broadcast(websocket.NewMessage("family_member", "updated", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("family_member", "updated", id, nil))
 
 	writeJSON(w, http.StatusOK, member)
 }
 
 func (h *FamilyMemberHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.store.GetByID(id)
+	existing, err := h.store.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get family member"})
 		return
@@ -178,17 +221,18 @@ func (h *FamilyMemberHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.Delete(id); err != nil {
+	if err := h.store.Delete(id, householdID); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete family member"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("family_member", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("family_member", "deleted", id, nil))
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *FamilyMemberHandler) UpdateSortOrder(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	var req struct {
 		IDs []int64 `json:"ids"`
 	}
@@ -202,7 +246,7 @@ func (h *FamilyMemberHandler) UpdateSortOrder(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	if err := h.store.UpdateSortOrder(req.IDs); err != nil {
+	if err := h.store.UpdateSortOrder(householdID, req.IDs); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update sort order"})
 		return
 	}
@@ -211,13 +255,14 @@ func (h *FamilyMemberHandler) UpdateSortOrder(w http.ResponseWriter, r *http.Req
 }
 
 func (h *FamilyMemberHandler) SetPIN(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.store.GetByID(id)
+	existing, err := h.store.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get family member"})
 		return
@@ -246,7 +291,7 @@ func (h *FamilyMemberHandler) SetPIN(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.SetPIN(id, string(hash)); err != nil {
+	if err := h.store.SetPIN(id, householdID, string(hash)); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to set PIN"})
 		return
 	}
@@ -255,13 +300,14 @@ func (h *FamilyMemberHandler) SetPIN(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *FamilyMemberHandler) ClearPIN(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	if err := h.store.ClearPIN(id); err != nil {
+	if err := h.store.ClearPIN(id, householdID); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to clear PIN"})
 		return
 	}
@@ -270,6 +316,7 @@ func (h *FamilyMemberHandler) ClearPIN(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *FamilyMemberHandler) VerifyPIN(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
@@ -284,7 +331,7 @@ func (h *FamilyMemberHandler) VerifyPIN(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	hash, err := h.store.GetPINHash(id)
+	hash, err := h.store.GetPINHash(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get PIN"})
 		return
@@ -302,6 +349,84 @@ func (h *FamilyMemberHandler) VerifyPIN(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, http.StatusOK, map[string]string{"status": "verified"})
 }
 
+// profileRequest is the optional birthdate, role and point multiplier in a
+// family member request. Fields left out keep their current value; an
+// empty birthdate or role, or a points_percent of 0, clears it.
+type profileRequest struct {
+	Birthdate     *string           `json:"birthdate"`
+	Role          *model.MemberRole `json:"role"`
+	PointsPercent *int              `json:"points_percent"`
+}
+
+// apply returns m with the request's profile fields set.
+func (p profileRequest) apply(m model.FamilyMember) model.FamilyMember {
+	if p.Birthdate != nil {
+		m.Birthdate = strings.TrimSpace(*p.Birthdate)
+	}
+	if p.Role != nil {
+		m.Role = *p.Role
+	}
+	if p.PointsPercent != nil {
+		m.PointsPercent = p.PointsPercent
+		if *p.PointsPercent == 0 {
+			m.PointsPercent = nil
+		}
+	}
+	return m
+}
+
+// maxPointsPercent is the highest point multiplier a member can have.
+const maxPointsPercent = 500
+
+// validateProfile checks a member's birthdate, role and point multiplier.
+// It returns a message for the client if any is invalid.
+func validateProfile(m model.FamilyMember) string {
+	if m.Birthdate != "" {
+		born, err := time.Parse("2006-01-02", m.Birthdate)
+		if err != nil {
+			return "birthdate must be YYYY-MM-DD format"
+		}
+		if born.After(time.Now()) {
+			return "birthdate cannot be in the future"
+		}
+	}
+	if !m.Role.Valid() {
+		return `role must be "adult", "teen" or "child"`
+	}
+	if m.PointsPercent != nil && (*m.PointsPercent < 1 || *m.PointsPercent > maxPointsPercent) {
+		return fmt.Sprintf("points_percent must be between 1 and %d", maxPointsPercent)
+	}
+	return ""
+}
+
+// verifyMemberPIN checks pin against a member's PIN, for actions only a
+// parent may take. It returns a status and message for the client if the
+// member is not in the household, is a teen or child, has no PIN, or the
+// PIN does not match.
+func verifyMemberPIN(ms *store.FamilyMemberStore, memberID, householdID int64, pin string) (int, string) {
+	member, err := ms.GetByID(memberID, householdID)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to get family member"
+	}
+	if member == nil {
+		return http.StatusBadRequest, "family member not found"
+	}
+	if role := member.EffectiveRole(time.Now()); role == model.RoleTeen || role == model.RoleChild {
+		return http.StatusForbidden, "only adults can do this"
+	}
+	hash, err := ms.GetPINHash(memberID, householdID)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to get PIN"
+	}
+	if hash == "" {
+		return http.StatusBadRequest, "no PIN set for this member"
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(pin)); err != nil {
+		return http.StatusUnauthorized, "incorrect PIN"
+	}
+	return http.StatusOK, ""
+}
+
 func parseIDParam(r *http.Request) (int64, error) {
 	idStr := r.PathValue("id")
 	return strconv.ParseInt(idStr, 10, 64)
@@ -2,11 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dukerupert/gamwich/internal/auth"
 	"github.com/dukerupert/gamwich/internal/grocery"
 	"github.com/dukerupert/gamwich/internal/model"
 	"github.com/dukerupert/gamwich/internal/store"
@@ -24,12 +27,241 @@ func NewGroceryHandler(gs *store.GroceryStore, ms *store.FamilyMemberStore, hub
 	return &GroceryHandler{groceryStore: gs, memberStore: ms, hub: hub, logger: logger}
 }
 
-func (h *GroceryHandler) broadcast(msg websocket.Message) {
+func (h *GroceryHandler) broadcast(householdID int64, msg websocket.Message) {
 	if h.hub != nil {
-		h.hub.Broadcast(msg)
+		h.hub.BroadcastToHousehold(householdID, msg)
 	}
 }
 
+type groceryListRequest struct {
+	Name     *string `json:"name"`
+	Archived *bool   `json:"archived"`
+}
+
+func parseListIDParam(r *http.Request) (int64, error) {
+	return strconv.ParseInt(r.PathValue("list_id"), 10, 64)
+}
+
+func (h *GroceryHandler) ListLists(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	lists, err := h.groceryStore.ListLists(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list lists"})
+		return
+	}
+	if lists == nil {
+		lists = []model.GroceryList{}
+	}
+	writeJSON(w, http.StatusOK, lists)
+}
+
+func (h *GroceryHandler) GetList(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	listID, err := parseListIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid list_id"})
+		return
+	}
+
+	list, err := h.groceryStore.GetListByID(listID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get list"})
+		return
+	}
+	if list == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "list not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (h *GroceryHandler) CreateList(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	var req groceryListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+	if req.Name == nil || strings.TrimSpace(*req.Name) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+		return
+	}
+
+	list, err := h.groceryStore.CreateList(householdID, strings.TrimSpace(*req.Name))
+	if err != nil {
+		h.logger.Error("create grocery list", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create list"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_list", "created", list.ID, nil))
+
+	writeJSON(w, http.StatusCreated, list)
+}
+
+// UpdateList renames, archives or restores a list. Fields left out of the
+// request are not changed.
+func (h *GroceryHandler) UpdateList(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	listID, err := parseListIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid list_id"})
+		return
+	}
+
+	existing, err := h.groceryStore.GetListByID(listID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get list"})
+		return
+	}
+	if existing == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "list not found"})
+		return
+	}
+
+	var req groceryListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+		return
+	}
+
+	list := existing
+	if req.Archived != nil && *req.Archived != list.Archived {
+		list, err = h.groceryStore.SetListArchived(listID, householdID, *req.Archived)
+		if errors.Is(err, store.ErrLastGroceryList) {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "cannot archive the only list"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update list"})
+			return
+		}
+	}
+	if req.Name != nil {
+		if list, err = h.groceryStore.RenameList(listID, householdID, strings.TrimSpace(*req.Name)); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update list"})
+			return
+		}
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_list", "updated", listID, nil))
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (h *GroceryHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	listID, err := parseListIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid list_id"})
+		return
+	}
+
+	existing, err := h.groceryStore.GetListByID(listID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get list"})
+		return
+	}
+	if existing == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "list not found"})
+		return
+	}
+
+	err = h.groceryStore.DeleteList(listID, householdID)
+	if errors.Is(err, store.ErrLastGroceryList) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "cannot delete the only list"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete list"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_list", "deleted", listID, nil))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *GroceryHandler) UpdateListSortOrder(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	var req struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ids are required"})
+		return
+	}
+
+	if err := h.groceryStore.UpdateListSortOrder(householdID, req.IDs); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update sort order"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_list", "reordered", 0, nil))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Suggestions lists the items bought from a list before that are likely
+// needed again, most overdue first. Items already on the list are left out.
+func (h *GroceryHandler) Suggestions(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	listID, err := parseListIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid list_id"})
+		return
+	}
+
+	list, err := h.groceryStore.GetListByID(listID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get list"})
+		return
+	}
+	if list == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "list not found"})
+		return
+	}
+
+	now := time.Now()
+	history, err := h.groceryStore.ListPurchases(listID, householdID, now.Add(-grocery.HistoryWindow))
+	if err != nil {
+		h.logger.Error("list grocery purchases", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list suggestions"})
+		return
+	}
+	items, err := h.groceryStore.ListItemsByList(listID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list suggestions"})
+		return
+	}
+
+	suggestions := grocery.Suggest(history, uncheckedKeys(items), now)
+	if suggestions == nil {
+		suggestions = []grocery.Suggestion{}
+	}
+	writeJSON(w, http.StatusOK, suggestions)
+}
+
+// uncheckedKeys returns the item keys of the items still to buy.
+func uncheckedKeys(items []model.GroceryItem) map[string]bool {
+	keys := make(map[string]bool)
+	for _, item := range items {
+		if !item.Checked {
+			keys[grocery.ItemKey(item.Name)] = true
+		}
+	}
+	return keys
+}
+
 type groceryItemRequest struct {
 	Name     string `json:"name"`
 	Quantity string `json:"quantity"`
@@ -39,8 +271,12 @@ type groceryItemRequest struct {
 	AddedBy  *int64 `json:"added_by"`
 }
 
+// CreateItem adds an item to a list. If the same item is already on the
+// list to buy, the quantity is added to it instead and the merged item is
+// returned with 200 rather than 201.
 func (h *GroceryHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
-	listID, err := strconv.ParseInt(r.PathValue("list_id"), 10, 64)
+	householdID := auth.HouseholdID(r.Context())
+	listID, err := parseListIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid list_id"})
 		return
@@ -58,31 +294,90 @@ func (h *GroceryHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.addItem(w, householdID, listID, req)
+}
+
+// QuickAdd adds an item to a list from text such as "3 cans black beans
+// (low sodium)", reading its quantity, unit and notes from it. Like
+// CreateItem, it merges the item into the same one already on the list.
+func (h *GroceryHandler) QuickAdd(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	listID, err := parseListIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid list_id"})
+		return
+	}
+
+	var req struct {
+		Text    string `json:"text"`
+		AddedBy *int64 `json:"added_by"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	parsed := grocery.ParseItem(req.Text)
+	if parsed.Name == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "text is required"})
+		return
+	}
+
+	h.addItem(w, householdID, listID, groceryItemRequest{
+		Name:     parsed.Name,
+		Quantity: parsed.Quantity,
+		Unit:     parsed.Unit,
+		Notes:    parsed.Notes,
+		AddedBy:  req.AddedBy,
+	})
+}
+
+// addItem adds req to a list, categorizing it if it has no category.
+func (h *GroceryHandler) addItem(w http.ResponseWriter, householdID, listID int64, req groceryItemRequest) {
+	if !memberExists(w, h.memberStore, householdID, req.AddedBy) {
+		return
+	}
+
 	// Auto-categorize if no category provided
 	if req.Category == "" {
-		req.Category = grocery.Categorize(req.Name)
+		categorizer, err := groceryCategorizer(h.groceryStore, householdID)
+		if err != nil {
+			h.logger.Error("load grocery categorizer", "error", err)
+		}
+		req.Category = categorizer.Categorize(req.Name)
 	}
 
-	item, err := h.groceryStore.CreateItem(listID, req.Name, req.Quantity, req.Unit, req.Notes, req.Category, req.AddedBy)
+	item, merged, err := h.groceryStore.AddItem(listID, householdID, req.Name, req.Quantity, req.Unit, req.Notes, req.Category, req.AddedBy)
 	if err != nil {
 		h.logger.Error("create grocery item", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create item"})
 		return
 	}
+	if item == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "list not found"})
+		return
+	}
 
-	h.broadcast(websocket.NewMessage("grocery_item", "created", item.ID, map[string]any{"list_id": listID}))
+	if merged {
+		h.broadcast(householdID, websocket.NewMessage("grocery_item", "updated", item.ID, map[string]any{"list_id": listID}))
+		writeJSON(w, http.StatusOK, item)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_item", "created", item.ID, map[string]any{"list_id": listID}))
 
 	writeJSON(w, http.StatusCreated, item)
 }
 
 func (h *GroceryHandler) ListItems(w http.ResponseWriter, r *http.Request) {
-	listID, err := strconv.ParseInt(r.PathValue("list_id"), 10, 64)
+	householdID := auth.HouseholdID(r.Context())
+	listID, err := parseListIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid list_id"})
 		return
 	}
 
-	items, err := h.groceryStore.ListItemsByList(listID)
+	items, err := h.groceryStore.ListItemsByList(listID, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list items"})
 		return
@@ -94,13 +389,14 @@ func (h *GroceryHandler) ListItems(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *GroceryHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.groceryStore.GetItemByID(id)
+	existing, err := h.groceryStore.GetItemByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get item"})
 		return
@@ -126,25 +422,29 @@ func (h *GroceryHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 		req.Category = existing.Category
 	}
 
-	item, err := h.groceryStore.UpdateItem(id, req.Name, req.Quantity, req.Unit, req.Notes, req.Category)
+	item, err := h.groceryStore.UpdateItem(id, householdID, req.Name, req.Quantity, req.Unit, req.Notes, req.Category)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update item"})
 		return
 	}
+	if err := learnCategory(h.groceryStore, householdID, existing, req.Category); err != nil {
+		h.logger.Error("learn grocery category", "error", err)
+	}
 
-	h.broadcast(websocket.NewMessage("grocery_item", "updated", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("grocery_item", "updated", id, nil))
 
 	writeJSON(w, http.StatusOK, item)
 }
 
 func (h *GroceryHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.groceryStore.GetItemByID(id)
+	existing, err := h.groceryStore.GetItemByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get item"})
 		return
@@ -154,17 +454,18 @@ func (h *GroceryHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.groceryStore.DeleteItem(id); err != nil {
+	if err := h.groceryStore.DeleteItem(id, householdID); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete item"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("grocery_item", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("grocery_item", "deleted", id, nil))
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *GroceryHandler) ToggleChecked(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
@@ -175,8 +476,11 @@ func (h *GroceryHandler) ToggleChecked(w http.ResponseWriter, r *http.Request) {
 		CheckedBy *int64 `json:"checked_by"`
 	}
 	json.NewDecoder(r.Body).Decode(&req)
+	if !memberExists(w, h.memberStore, householdID, req.CheckedBy) {
+		return
+	}
 
-	item, err := h.groceryStore.ToggleChecked(id, req.CheckedBy)
+	item, err := h.groceryStore.ToggleChecked(id, householdID, req.CheckedBy)
 	if err != nil {
 		h.logger.Error("toggle checked", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to toggle checked"})
@@ -187,25 +491,88 @@ func (h *GroceryHandler) ToggleChecked(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("grocery_item", "checked", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("grocery_item", "checked", id, nil))
+
+	writeJSON(w, http.StatusOK, item)
+}
+
+// MoveItem moves an item to the list given in the request.
+func (h *GroceryHandler) MoveItem(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	var req struct {
+		ListID int64 `json:"list_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	existing, err := h.groceryStore.GetItemByID(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get item"})
+		return
+	}
+	if existing == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "item not found"})
+		return
+	}
+
+	target, err := h.groceryStore.GetListByID(req.ListID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get list"})
+		return
+	}
+	if target == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "list not found"})
+		return
+	}
+	if target.Archived {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "list is archived"})
+		return
+	}
+
+	item, merged, err := h.groceryStore.MoveItem(id, householdID, target.ID)
+	if errors.Is(err, store.ErrArchivedGroceryList) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "list is archived"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("move grocery item", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to move item"})
+		return
+	}
+
+	if merged {
+		h.broadcast(householdID, websocket.NewMessage("grocery_item", "deleted", id, nil))
+		h.broadcast(householdID, websocket.NewMessage("grocery_item", "updated", item.ID, map[string]any{"list_id": target.ID}))
+	} else {
+		h.broadcast(householdID, websocket.NewMessage("grocery_item", "moved", id, map[string]any{"list_id": target.ID}))
+	}
 
 	writeJSON(w, http.StatusOK, item)
 }
 
 func (h *GroceryHandler) ClearChecked(w http.ResponseWriter, r *http.Request) {
-	listID, err := strconv.ParseInt(r.PathValue("list_id"), 10, 64)
+	householdID := auth.HouseholdID(r.Context())
+	listID, err := parseListIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid list_id"})
 		return
 	}
 
-	count, err := h.groceryStore.ClearChecked(listID)
+	count, err := h.groceryStore.ClearChecked(listID, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to clear checked"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("grocery_item", "cleared", 0, map[string]any{"list_id": listID}))
+	h.broadcast(householdID, websocket.NewMessage("grocery_item", "cleared", 0, map[string]any{"list_id": listID}))
 
 	writeJSON(w, http.StatusOK, map[string]int64{"cleared": count})
 }
@@ -0,0 +1,426 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dukerupert/gamwich/internal/auth"
+	"github.com/dukerupert/gamwich/internal/grocery"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/store"
+	"github.com/dukerupert/gamwich/internal/websocket"
+)
+
+// groceryCategorizer loads how the household categorizes items.
+func groceryCategorizer(gs *store.GroceryStore, householdID int64) (grocery.Categorizer, error) {
+	rules, err := gs.CategoryRules(householdID)
+	if err != nil {
+		return grocery.Categorizer{}, err
+	}
+	categories, err := gs.ListCategories(householdID)
+	if err != nil {
+		return grocery.Categorizer{}, err
+	}
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = c.Name
+	}
+	return grocery.Categorizer{Learned: rules, Categories: names}, nil
+}
+
+// learnCategory remembers that the household moved an item to category,
+// so items by the same name go there from now on.
+func learnCategory(gs *store.GroceryStore, householdID int64, item *model.GroceryItem, category string) error {
+	if category == item.Category {
+		return nil
+	}
+	return gs.SetCategoryRule(householdID, grocery.ItemKey(item.Name), category)
+}
+
+// categoryError maps a store error from saving a category to a client
+// message, or "" if it is not one the client caused.
+func categoryError(err error) string {
+	switch {
+	case errors.Is(err, store.ErrCategoryExists):
+		return "a category with that name already exists"
+	case errors.Is(err, store.ErrCategoryIsOther):
+		return "the Other category cannot be renamed or deleted"
+	}
+	return ""
+}
+
+func (h *GroceryHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	categories, err := h.groceryStore.ListCategories(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list categories"})
+		return
+	}
+	if categories == nil {
+		categories = []model.GroceryCategory{}
+	}
+	writeJSON(w, http.StatusOK, categories)
+}
+
+func (h *GroceryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+		return
+	}
+
+	category, err := h.groceryStore.CreateCategory(householdID, req.Name)
+	if msg := categoryError(err); msg != "" {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": msg})
+		return
+	}
+	if err != nil {
+		h.logger.Error("create grocery category", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create category"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_category", "created", category.ID, nil))
+
+	writeJSON(w, http.StatusCreated, category)
+}
+
+// UpdateCategory renames a category, and every item in it.
+func (h *GroceryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+		return
+	}
+
+	category, err := h.groceryStore.RenameCategory(id, householdID, req.Name)
+	if msg := categoryError(err); msg != "" {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": msg})
+		return
+	}
+	if err != nil {
+		h.logger.Error("rename grocery category", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update category"})
+		return
+	}
+	if category == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "category not found"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_category", "updated", id, nil))
+
+	writeJSON(w, http.StatusOK, category)
+}
+
+// DeleteCategory deletes a category. Its items move to Other.
+func (h *GroceryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	existing, err := h.groceryStore.GetCategoryByID(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get category"})
+		return
+	}
+	if existing == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "category not found"})
+		return
+	}
+
+	err = h.groceryStore.DeleteCategory(id, householdID)
+	if msg := categoryError(err); msg != "" {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": msg})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete category"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_category", "deleted", id, nil))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *GroceryHandler) UpdateCategorySortOrder(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	var req struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ids are required"})
+		return
+	}
+
+	if err := h.groceryStore.UpdateCategorySortOrder(householdID, req.IDs); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update sort order"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_category", "reordered", 0, nil))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// categoryMapping is how a household categorizes items, in the form it is
+// exported and imported in: its categories in order, and the category it
+// has chosen for each item it has recategorized.
+type categoryMapping struct {
+	Categories []string          `json:"categories"`
+	Rules      map[string]string `json:"rules"`
+}
+
+// ExportCategoryRules returns the household's categories and learned rules.
+func (h *GroceryHandler) ExportCategoryRules(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	categories, err := h.groceryStore.ListCategories(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list categories"})
+		return
+	}
+	rules, err := h.groceryStore.CategoryRules(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list category rules"})
+		return
+	}
+
+	mapping := categoryMapping{Categories: make([]string, len(categories)), Rules: rules}
+	for i, c := range categories {
+		mapping.Categories[i] = c.Name
+	}
+	writeJSON(w, http.StatusOK, mapping)
+}
+
+// ImportCategoryRules merges an exported mapping into the household's,
+// creating any categories it is missing.
+func (h *GroceryHandler) ImportCategoryRules(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	var req categoryMapping
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	categories := make([]string, 0, len(req.Categories))
+	for _, name := range req.Categories {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "category names are required"})
+			return
+		}
+		categories = append(categories, name)
+	}
+	rules := make(map[string]string, len(req.Rules))
+	for item, category := range req.Rules {
+		key, category := grocery.ItemKey(item), strings.TrimSpace(category)
+		if key == "" || category == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "rules need an item and a category"})
+			return
+		}
+		rules[key] = category
+	}
+
+	created, err := h.groceryStore.ImportCategoryRules(householdID, categories, rules)
+	if err != nil {
+		h.logger.Error("import grocery category rules", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to import category rules"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_category", "imported", 0, nil))
+
+	writeJSON(w, http.StatusOK, map[string]int{"categories_created": created, "rules_imported": len(rules)})
+}
+
+// GroceryCategoryCreate handles POST to add a category.
+func (h *TemplateHandler) GroceryCategoryCreate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.renderToast(w, "error", "Category name is required")
+		h.renderGroceryContent(w, householdID, groceryListID(r))
+		return
+	}
+
+	category, err := h.groceryStore.CreateCategory(householdID, name)
+	if msg := categoryError(err); msg != "" {
+		h.renderToast(w, "error", msg)
+		h.renderGroceryContent(w, householdID, groceryListID(r))
+		return
+	}
+	if err != nil {
+		h.logger.Error("create grocery category", "error", err)
+		http.Error(w, "failed to create category", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_category", "created", category.ID, nil))
+
+	h.renderToast(w, "success", "Category added")
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
+
+// GroceryCategoryRename handles PUT to rename a category.
+func (h *TemplateHandler) GroceryCategoryRename(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.renderToast(w, "error", "Category name is required")
+		h.renderGroceryContent(w, householdID, groceryListID(r))
+		return
+	}
+
+	category, err := h.groceryStore.RenameCategory(id, householdID, name)
+	if msg := categoryError(err); msg != "" {
+		h.renderToast(w, "error", msg)
+		h.renderGroceryContent(w, householdID, groceryListID(r))
+		return
+	}
+	if err != nil {
+		h.logger.Error("rename grocery category", "error", err)
+		http.Error(w, "failed to rename category", http.StatusInternalServerError)
+		return
+	}
+	if category == nil {
+		http.Error(w, "category not found", http.StatusNotFound)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_category", "updated", id, nil))
+
+	h.renderToast(w, "success", "Category renamed")
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
+
+// GroceryCategoryMove handles POST to move a category one place up or
+// down.
+func (h *TemplateHandler) GroceryCategoryMove(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	categories, err := h.groceryStore.ListCategories(householdID)
+	if err != nil {
+		http.Error(w, "failed to list categories", http.StatusInternalServerError)
+		return
+	}
+
+	ids := make([]int64, len(categories))
+	for i, c := range categories {
+		ids[i] = c.ID
+	}
+	for i := range ids {
+		if ids[i] != id {
+			continue
+		}
+		j := i + 1
+		if r.FormValue("dir") == "up" {
+			j = i - 1
+		}
+		if j >= 0 && j < len(ids) {
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+		break
+	}
+
+	if err := h.groceryStore.UpdateCategorySortOrder(householdID, ids); err != nil {
+		h.logger.Error("reorder grocery categories", "error", err)
+		http.Error(w, "failed to reorder categories", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_category", "reordered", 0, nil))
+
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
+
+// GroceryCategoryDelete handles DELETE for a category. Its items move to
+// Other.
+func (h *TemplateHandler) GroceryCategoryDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.groceryStore.GetCategoryByID(id, householdID)
+	if err != nil || category == nil {
+		http.Error(w, "category not found", http.StatusNotFound)
+		return
+	}
+
+	err = h.groceryStore.DeleteCategory(id, householdID)
+	if msg := categoryError(err); msg != "" {
+		h.renderToast(w, "error", msg)
+		h.renderGroceryContent(w, householdID, groceryListID(r))
+		return
+	}
+	if err != nil {
+		h.logger.Error("delete grocery category", "error", err)
+		http.Error(w, "failed to delete category", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_category", "deleted", id, nil))
+
+	h.renderToast(w, "success", "Deleted "+category.Name)
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
@@ -0,0 +1,737 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/auth"
+	"github.com/dukerupert/gamwich/internal/grocery"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/store"
+	"github.com/dukerupert/gamwich/internal/websocket"
+)
+
+// GroceryPage renders the full grocery page inside the dashboard layout.
+func (h *TemplateHandler) GroceryPage(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	data, err := h.buildDashboardData(r, "grocery")
+	if err != nil {
+		http.Error(w, "failed to load data", http.StatusInternalServerError)
+		return
+	}
+
+	groceryData, err := h.buildGroceryListData(householdID, groceryListID(r))
+	if err != nil {
+		h.logger.Error("build grocery list", "error", err)
+		http.Error(w, "template error", http.StatusInternalServerError)
+		return
+	}
+
+	content, err := h.renderSection("grocery-content", groceryData)
+	if err != nil {
+		h.logger.Error("render grocery content", "error", err)
+		http.Error(w, "template error", http.StatusInternalServerError)
+		return
+	}
+	data["Content"] = content
+
+	h.render(w, "layout.html", data)
+}
+
+// GroceryPartial renders the grocery section for HTMX swap.
+func (h *TemplateHandler) GroceryPartial(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
+
+// GroceryItemList renders the item list partial.
+func (h *TemplateHandler) GroceryItemList(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	groceryData, err := h.buildGroceryListData(householdID, groceryListID(r))
+	if err != nil {
+		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "grocery-item-list", groceryData)
+}
+
+// GroceryItemAdd handles POST quick-add form submission.
+func (h *TemplateHandler) GroceryItemAdd(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	// The quick-add bar reads a quantity and notes from what was typed,
+	// as in "2 lb chicken (boneless)".
+	parsed := grocery.ParseItem(r.FormValue("name"))
+	name := parsed.Name
+	if name == "" {
+		h.renderToast(w, "error", "Item name is required")
+		return
+	}
+
+	list, err := h.groceryList(householdID, groceryListID(r))
+	if err != nil || list == nil {
+		http.Error(w, "failed to get list", http.StatusInternalServerError)
+		return
+	}
+
+	activeUserID := h.activeUserFromCookie(r)
+	var addedBy *int64
+	if activeUserID > 0 {
+		addedBy = &activeUserID
+	}
+
+	categorizer, err := groceryCategorizer(h.groceryStore, householdID)
+	if err != nil {
+		h.logger.Error("load grocery categorizer", "error", err)
+	}
+	cat := categorizer.Categorize(name)
+
+	item, merged, err := h.groceryStore.AddItem(list.ID, householdID, name, parsed.Quantity, parsed.Unit, parsed.Notes, cat, addedBy)
+	if err != nil {
+		h.logger.Error("create grocery item", "error", err)
+		http.Error(w, "failed to create item", http.StatusInternalServerError)
+		return
+	}
+
+	if merged {
+		h.broadcast(householdID, websocket.NewMessage("grocery_item", "updated", item.ID, map[string]any{"list_id": list.ID}))
+		h.renderToast(w, "success", fmt.Sprintf("%s was already on the list", item.Name))
+	} else {
+		h.broadcast(householdID, websocket.NewMessage("grocery_item", "created", item.ID, map[string]any{"list_id": list.ID}))
+	}
+
+	// Fire push notification for grocery addition (exclude the adding user)
+	if h.pushScheduler != nil {
+		userID := auth.UserID(r.Context())
+		go h.pushScheduler.SendGroceryNotification(householdID, userID, name)
+	}
+
+	groceryData, err := h.buildGroceryListData(householdID, list.ID)
+	if err != nil {
+		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "grocery-item-list", groceryData)
+}
+
+// GroceryItemToggle handles POST to toggle checked state.
+func (h *TemplateHandler) GroceryItemToggle(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	activeUserID := h.activeUserFromCookie(r)
+	var checkedBy *int64
+	if activeUserID > 0 {
+		checkedBy = &activeUserID
+	}
+
+	item, err := h.groceryStore.ToggleChecked(id, householdID, checkedBy)
+	if err != nil {
+		h.logger.Error("toggle grocery item", "error", err)
+		http.Error(w, "failed to toggle item", http.StatusInternalServerError)
+		return
+	}
+	if item == nil {
+		http.Error(w, "item not found", http.StatusNotFound)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_item", "checked", id, nil))
+
+	groceryData, err := h.buildGroceryListData(householdID, item.ListID)
+	if err != nil {
+		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
+		return
+	}
+	if r.FormValue("view") == "shopping" {
+		h.renderPartial(w, "grocery-shopping-list", groceryData)
+		return
+	}
+	h.renderPartial(w, "grocery-item-list", groceryData)
+}
+
+// GroceryItemDelete handles DELETE for a grocery item.
+func (h *TemplateHandler) GroceryItemDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.groceryStore.GetItemByID(id, householdID)
+	if err != nil || item == nil {
+		http.Error(w, "item not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.groceryStore.DeleteItem(id, householdID); err != nil {
+		http.Error(w, "failed to delete item", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_item", "deleted", id, nil))
+
+	groceryData, err := h.buildGroceryListData(householdID, item.ListID)
+	if err != nil {
+		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "grocery-item-list", groceryData)
+}
+
+// GroceryClearChecked handles POST to clear all checked items.
+func (h *TemplateHandler) GroceryClearChecked(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	list, err := h.groceryList(householdID, groceryListID(r))
+	if err != nil || list == nil {
+		http.Error(w, "failed to get list", http.StatusInternalServerError)
+		return
+	}
+
+	count, err := h.groceryStore.ClearChecked(list.ID, householdID)
+	if err != nil {
+		http.Error(w, "failed to clear checked", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_item", "cleared", 0, map[string]any{"list_id": list.ID}))
+
+	h.renderToast(w, "success", fmt.Sprintf("Cleared %d item(s)", count))
+
+	groceryData, err := h.buildGroceryListData(householdID, list.ID)
+	if err != nil {
+		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "grocery-item-list", groceryData)
+}
+
+// GroceryItemEditForm renders the edit form in the modal.
+func (h *TemplateHandler) GroceryItemEditForm(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.groceryStore.GetItemByID(id, householdID)
+	if err != nil || item == nil {
+		http.Error(w, "item not found", http.StatusNotFound)
+		return
+	}
+
+	categories, _ := h.groceryStore.ListCategories(householdID)
+	lists, _ := h.groceryStore.ListLists(householdID)
+
+	// Keep the item's category selectable even if the household no longer
+	// has it, so saving the form does not recategorize the item.
+	if !slices.ContainsFunc(categories, func(c model.GroceryCategory) bool { return c.Name == item.Category }) {
+		categories = append(categories, model.GroceryCategory{Name: item.Category})
+	}
+
+	// The item can be moved to any active list, or left where it is.
+	var listOptions []model.GroceryList
+	for _, l := range lists {
+		if !l.Archived || l.ID == item.ListID {
+			listOptions = append(listOptions, l)
+		}
+	}
+
+	h.renderPartial(w, "grocery-edit-form", map[string]any{
+		"ID":         item.ID,
+		"Name":       item.Name,
+		"Quantity":   item.Quantity,
+		"Unit":       item.Unit,
+		"Notes":      item.Notes,
+		"Category":   item.Category,
+		"Categories": categories,
+		"ListID":     item.ListID,
+		"Lists":      listOptions,
+	})
+}
+
+// GroceryItemUpdate handles PUT form submission to update a grocery item.
+func (h *TemplateHandler) GroceryItemUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.renderToast(w, "error", "Item name is required")
+		return
+	}
+
+	quantity := r.FormValue("quantity")
+	unit := r.FormValue("unit")
+	notes := r.FormValue("notes")
+	category := r.FormValue("category")
+
+	if category == "" {
+		category = model.GroceryCategoryOther
+	}
+
+	existing, err := h.groceryStore.GetItemByID(id, householdID)
+	if err != nil || existing == nil {
+		http.Error(w, "item not found", http.StatusNotFound)
+		return
+	}
+
+	var target *model.GroceryList
+	if toListID := groceryListID(r); toListID > 0 && toListID != existing.ListID {
+		if target, err = h.groceryStore.GetListByID(toListID, householdID); err != nil {
+			http.Error(w, "failed to get list", http.StatusInternalServerError)
+			return
+		}
+		if target == nil || target.Archived {
+			h.renderToast(w, "error", "List not found")
+			return
+		}
+	}
+
+	item, err := h.groceryStore.UpdateItem(id, householdID, name, quantity, unit, notes, category)
+	if err != nil {
+		h.logger.Error("update grocery item", "error", err)
+		http.Error(w, "failed to update item", http.StatusInternalServerError)
+		return
+	}
+	if err := learnCategory(h.groceryStore, householdID, existing, category); err != nil {
+		h.logger.Error("learn grocery category", "error", err)
+	}
+
+	// The list the item was on is the one being shown, so re-render that
+	// even if the item is moved off it.
+	shownListID := item.ListID
+	message := "Item updated"
+	if target != nil {
+		if _, _, err := h.groceryStore.MoveItem(id, householdID, target.ID); err != nil {
+			h.logger.Error("move grocery item", "error", err)
+			http.Error(w, "failed to move item", http.StatusInternalServerError)
+			return
+		}
+		message = "Item moved to " + target.Name
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_item", "updated", id, nil))
+
+	w.Header().Set("HX-Trigger", "closeGroceryModal")
+	h.renderToast(w, "success", message)
+
+	groceryData, err := h.buildGroceryListData(householdID, shownListID)
+	if err != nil {
+		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "grocery-item-list", groceryData)
+}
+
+// --- Grocery helper methods ---
+
+type categoryGroup struct {
+	CategoryName string
+	Aisle        string
+	Items        []model.GroceryItem
+}
+
+// groceryListID returns the list_id form or query value, or 0 for the
+// household's default list.
+func groceryListID(r *http.Request) int64 {
+	id, _ := strconv.ParseInt(r.FormValue("list_id"), 10, 64)
+	return id
+}
+
+// groceryList returns the given list, or the default list if listID is 0.
+func (h *TemplateHandler) groceryList(householdID, listID int64) (*model.GroceryList, error) {
+	if listID > 0 {
+		return h.groceryStore.GetListByID(listID, householdID)
+	}
+	return h.groceryStore.GetDefaultList(householdID)
+}
+
+// activeGroceryLists returns the lists that are not archived.
+func activeGroceryLists(lists []model.GroceryList) []model.GroceryList {
+	var active []model.GroceryList
+	for _, l := range lists {
+		if !l.Archived {
+			active = append(active, l)
+		}
+	}
+	return active
+}
+
+// frequentGroceryItems is how many items the quick-add panel offers.
+const frequentGroceryItems = 12
+
+// buildGroceryListData builds the grocery section for the given list. If
+// listID is 0 or not one of the household's lists, it shows the default.
+func (h *TemplateHandler) buildGroceryListData(householdID, listID int64) (map[string]any, error) {
+	lists, err := h.groceryStore.ListLists(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list lists: %w", err)
+	}
+
+	shops, err := h.groceryStore.ListShops(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list stores: %w", err)
+	}
+
+	var list *model.GroceryList
+	for i := range lists {
+		if lists[i].ID == listID {
+			list = &lists[i]
+			break
+		}
+	}
+	if list == nil && len(lists) > 0 && !lists[0].Archived {
+		list = &lists[0]
+	}
+	if list == nil {
+		return map[string]any{
+			"List":           nil,
+			"Lists":          lists,
+			"ActiveLists":    nil,
+			"Shops":          shops,
+			"CategoryGroups": nil,
+			"CheckedItems":   nil,
+			"UncheckedCount": 0,
+			"Categories":     nil,
+		}, nil
+	}
+
+	var shop *model.GroceryShop
+	if list.ShopID != nil {
+		shop, err = h.groceryStore.GetShop(*list.ShopID, householdID)
+		if err != nil {
+			return nil, fmt.Errorf("get store: %w", err)
+		}
+	}
+
+	items, err := h.groceryStore.ListItemsByList(list.ID, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list items: %w", err)
+	}
+
+	categories, err := h.groceryStore.ListCategories(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list categories: %w", err)
+	}
+
+	history, err := h.groceryStore.ListPurchases(list.ID, householdID, time.Now().Add(-grocery.HistoryWindow))
+	if err != nil {
+		return nil, fmt.Errorf("list purchases: %w", err)
+	}
+	frequent := grocery.Frequent(history, uncheckedKeys(items), time.Now().In(h.location(householdID)), frequentGroceryItems)
+
+	// Build category sort order map
+	catOrder := make(map[string]int)
+	for _, c := range categories {
+		catOrder[c.Name] = c.SortOrder
+	}
+
+	// Separate unchecked and checked
+	var unchecked, checked []model.GroceryItem
+	for _, item := range items {
+		if item.Checked {
+			checked = append(checked, item)
+		} else {
+			unchecked = append(unchecked, item)
+		}
+	}
+
+	// Group unchecked by category
+	groupMap := make(map[string][]model.GroceryItem)
+	for _, item := range unchecked {
+		groupMap[item.Category] = append(groupMap[item.Category], item)
+	}
+
+	// Sort groups in the store's walking order, or by category sort order
+	// if the list has no store
+	sections := make([]model.ShopSection, len(categories))
+	for i, cat := range categories {
+		sections[i] = model.ShopSection{CategoryID: cat.ID, Category: cat.Name}
+	}
+	if shop != nil {
+		sections = shop.Sections
+	}
+	var groups []categoryGroup
+	for _, sec := range sections {
+		if items, ok := groupMap[sec.Category]; ok {
+			groups = append(groups, categoryGroup{
+				CategoryName: sec.Category,
+				Aisle:        sec.Aisle,
+				Items:        items,
+			})
+		}
+	}
+	// Add any categories not in seed data
+	for catName, items := range groupMap {
+		if _, ok := catOrder[catName]; !ok {
+			groups = append(groups, categoryGroup{
+				CategoryName: catName,
+				Items:        items,
+			})
+		}
+	}
+
+	return map[string]any{
+		"List":           list,
+		"Lists":          lists,
+		"ActiveLists":    activeGroceryLists(lists),
+		"Shops":          shops,
+		"Shop":           shop,
+		"Frequent":       frequent,
+		"CategoryGroups": groups,
+		"Aisles":         groupByAisle(groups),
+		"CheckedItems":   checked,
+		"UncheckedCount": len(unchecked),
+		"Categories":     categories,
+	}, nil
+}
+
+// buildGrocerySummaryData counts the items still to buy across the
+// household's active lists, and lists those with any.
+func (h *TemplateHandler) buildGrocerySummaryData(householdID int64) (map[string]any, error) {
+	lists, err := h.groceryStore.ListLists(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list lists: %w", err)
+	}
+
+	count := 0
+	var withItems []model.GroceryList
+	for _, l := range activeGroceryLists(lists) {
+		if l.UncheckedCount > 0 {
+			count += l.UncheckedCount
+			withItems = append(withItems, l)
+		}
+	}
+
+	return map[string]any{"UncheckedCount": count, "Lists": withItems}, nil
+}
+
+// renderGroceryContent renders the grocery section for the given list.
+func (h *TemplateHandler) renderGroceryContent(w http.ResponseWriter, householdID, listID int64) {
+	groceryData, err := h.buildGroceryListData(householdID, listID)
+	if err != nil {
+		h.logger.Error("build grocery list", "error", err)
+		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "grocery-content", groceryData)
+}
+
+// GroceryListCreate handles POST to add a list, and switches to it.
+func (h *TemplateHandler) GroceryListCreate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.renderToast(w, "error", "List name is required")
+		h.renderGroceryContent(w, householdID, groceryListID(r))
+		return
+	}
+
+	list, err := h.groceryStore.CreateList(householdID, name)
+	if err != nil {
+		h.logger.Error("create grocery list", "error", err)
+		http.Error(w, "failed to create list", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_list", "created", list.ID, nil))
+
+	h.renderToast(w, "success", "List added")
+	h.renderGroceryContent(w, householdID, list.ID)
+}
+
+// GroceryListRename handles PUT to rename a list.
+func (h *TemplateHandler) GroceryListRename(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.renderToast(w, "error", "List name is required")
+		h.renderGroceryContent(w, householdID, groceryListID(r))
+		return
+	}
+
+	list, err := h.groceryStore.RenameList(id, householdID, name)
+	if err != nil {
+		h.logger.Error("rename grocery list", "error", err)
+		http.Error(w, "failed to rename list", http.StatusInternalServerError)
+		return
+	}
+	if list == nil {
+		http.Error(w, "list not found", http.StatusNotFound)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_list", "updated", id, nil))
+
+	h.renderToast(w, "success", "List renamed")
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
+
+// GroceryListArchive handles POST to archive a list, or to restore it if
+// the archived form value is "false".
+func (h *TemplateHandler) GroceryListArchive(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	archived := r.FormValue("archived") != "false"
+	shownListID := groceryListID(r)
+
+	list, err := h.groceryStore.SetListArchived(id, householdID, archived)
+	if errors.Is(err, store.ErrLastGroceryList) {
+		h.renderToast(w, "error", "You can't archive your only list")
+		h.renderGroceryContent(w, householdID, shownListID)
+		return
+	}
+	if err != nil {
+		h.logger.Error("archive grocery list", "error", err)
+		http.Error(w, "failed to archive list", http.StatusInternalServerError)
+		return
+	}
+	if list == nil {
+		http.Error(w, "list not found", http.StatusNotFound)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_list", "updated", id, nil))
+
+	message := "List restored"
+	if archived {
+		message = "List archived"
+		if shownListID == id {
+			shownListID = 0
+		}
+	}
+	h.renderToast(w, "success", message)
+	h.renderGroceryContent(w, householdID, shownListID)
+}
+
+// GroceryListMove handles POST to move a list one place up or down among
+// the active lists.
+func (h *TemplateHandler) GroceryListMove(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	lists, err := h.groceryStore.ListLists(householdID)
+	if err != nil {
+		http.Error(w, "failed to list lists", http.StatusInternalServerError)
+		return
+	}
+
+	ids := make([]int64, len(lists))
+	for i, l := range lists {
+		ids[i] = l.ID
+	}
+	active := len(activeGroceryLists(lists))
+	for i := range ids[:active] {
+		if ids[i] != id {
+			continue
+		}
+		j := i + 1
+		if r.FormValue("dir") == "up" {
+			j = i - 1
+		}
+		if j >= 0 && j < active {
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+		break
+	}
+
+	if err := h.groceryStore.UpdateListSortOrder(householdID, ids); err != nil {
+		h.logger.Error("reorder grocery lists", "error", err)
+		http.Error(w, "failed to reorder lists", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_list", "reordered", 0, nil))
+
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
+
+// GroceryListDelete handles DELETE for a list and its items.
+func (h *TemplateHandler) GroceryListDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.groceryStore.GetListByID(id, householdID)
+	if err != nil || list == nil {
+		http.Error(w, "list not found", http.StatusNotFound)
+		return
+	}
+
+	err = h.groceryStore.DeleteList(id, householdID)
+	if errors.Is(err, store.ErrLastGroceryList) {
+		h.renderToast(w, "error", "You can't delete your only list")
+		h.renderGroceryContent(w, householdID, groceryListID(r))
+		return
+	}
+	if err != nil {
+		h.logger.Error("delete grocery list", "error", err)
+		http.Error(w, "failed to delete list", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_list", "deleted", id, nil))
+
+	h.renderToast(w, "success", "Deleted "+list.Name)
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
@@ -0,0 +1,509 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dukerupert/gamwich/internal/auth"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/websocket"
+)
+
+// shopSectionsRequest is a store layout as sent by clients: the categories
+// in walking order, each with an optional aisle.
+type shopSectionsRequest []struct {
+	CategoryID int64  `json:"category_id"`
+	Aisle      string `json:"aisle"`
+}
+
+func (req shopSectionsRequest) sections() []model.ShopSection {
+	sections := make([]model.ShopSection, len(req))
+	for i, s := range req {
+		sections[i] = model.ShopSection{CategoryID: s.CategoryID, Aisle: strings.TrimSpace(s.Aisle)}
+	}
+	return sections
+}
+
+func (h *GroceryHandler) ListShops(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	shops, err := h.groceryStore.ListShops(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list stores"})
+		return
+	}
+	if shops == nil {
+		shops = []model.GroceryShop{}
+	}
+	writeJSON(w, http.StatusOK, shops)
+}
+
+func (h *GroceryHandler) GetShop(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	shop, err := h.groceryStore.GetShop(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get store"})
+		return
+	}
+	if shop == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "store not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, shop)
+}
+
+// CreateShop creates a store, optionally with its layout.
+func (h *GroceryHandler) CreateShop(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	var req struct {
+		Name     string              `json:"name"`
+		Sections shopSectionsRequest `json:"sections"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+		return
+	}
+
+	shop, err := h.groceryStore.CreateShop(householdID, req.Name)
+	if err == nil && req.Sections != nil {
+		shop, err = h.groceryStore.SetShopSections(shop.ID, householdID, req.Sections.sections())
+	}
+	if err != nil {
+		h.logger.Error("create grocery store", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create store"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_store", "created", shop.ID, nil))
+
+	writeJSON(w, http.StatusCreated, shop)
+}
+
+// UpdateShop renames a store and/or replaces its layout. Fields left out
+// are unchanged.
+func (h *GroceryHandler) UpdateShop(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	var req struct {
+		Name     *string             `json:"name"`
+		Sections shopSectionsRequest `json:"sections"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+	if req.Name != nil {
+		*req.Name = strings.TrimSpace(*req.Name)
+		if *req.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+			return
+		}
+	}
+
+	shop, err := h.groceryStore.GetShop(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get store"})
+		return
+	}
+	if shop == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "store not found"})
+		return
+	}
+
+	if req.Sections != nil {
+		shop, err = h.groceryStore.SetShopSections(id, householdID, req.Sections.sections())
+	}
+	if err == nil && req.Name != nil {
+		shop, err = h.groceryStore.RenameShop(id, householdID, *req.Name)
+	}
+	if err != nil {
+		h.logger.Error("update grocery store", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update store"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_store", "updated", id, nil))
+
+	writeJSON(w, http.StatusOK, shop)
+}
+
+// DeleteShop deletes a store. Lists shopped there are sorted by category
+// again.
+func (h *GroceryHandler) DeleteShop(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	existing, err := h.groceryStore.GetShop(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get store"})
+		return
+	}
+	if existing == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "store not found"})
+		return
+	}
+
+	if err := h.groceryStore.DeleteShop(id, householdID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete store"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_store", "deleted", id, nil))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetListShop sets the store a list is shopped at, from {"store_id": n},
+// or clears it with {"store_id": null}.
+func (h *GroceryHandler) SetListShop(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	listID, err := parseListIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid list id"})
+		return
+	}
+
+	var req struct {
+		StoreID *int64 `json:"store_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	list, err := h.groceryStore.GetListByID(listID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get list"})
+		return
+	}
+	if list == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "list not found"})
+		return
+	}
+
+	list, err = h.groceryStore.SetListShop(listID, householdID, req.StoreID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to set store"})
+		return
+	}
+	if list == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "store not found"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_list", "updated", listID, nil))
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// aisleGroup is a stop in shopping mode: an aisle, or a category that has
+// no aisle, with what to pick up there.
+type aisleGroup struct {
+	Aisle      string
+	Categories []categoryGroup
+}
+
+// groupByAisle merges category groups that are next to each other in the
+// same aisle.
+func groupByAisle(groups []categoryGroup) []aisleGroup {
+	var aisles []aisleGroup
+	for _, g := range groups {
+		if n := len(aisles); n > 0 && g.Aisle != "" && aisles[n-1].Aisle == g.Aisle {
+			aisles[n-1].Categories = append(aisles[n-1].Categories, g)
+			continue
+		}
+		aisles = append(aisles, aisleGroup{Aisle: g.Aisle, Categories: []categoryGroup{g}})
+	}
+	return aisles
+}
+
+// renderGroceryShopping renders shopping mode for the given list.
+func (h *TemplateHandler) renderGroceryShopping(w http.ResponseWriter, householdID, listID int64) {
+	groceryData, err := h.buildGroceryListData(householdID, listID)
+	if err != nil {
+		h.logger.Error("build grocery list", "error", err)
+		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "grocery-shopping", groceryData)
+}
+
+// GroceryShoppingPage renders shopping mode as a full page.
+func (h *TemplateHandler) GroceryShoppingPage(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	data, err := h.buildDashboardData(r, "grocery")
+	if err != nil {
+		http.Error(w, "failed to load data", http.StatusInternalServerError)
+		return
+	}
+
+	groceryData, err := h.buildGroceryListData(householdID, groceryListID(r))
+	if err != nil {
+		h.logger.Error("build grocery list", "error", err)
+		http.Error(w, "template error", http.StatusInternalServerError)
+		return
+	}
+
+	content, err := h.renderSection("grocery-shopping", groceryData)
+	if err != nil {
+		h.logger.Error("render grocery shopping", "error", err)
+		http.Error(w, "template error", http.StatusInternalServerError)
+		return
+	}
+	data["Content"] = content
+
+	h.render(w, "layout.html", data)
+}
+
+// GroceryShoppingPartial renders shopping mode for HTMX swap: the
+// unchecked items grouped by aisle, in the order of the list's store.
+func (h *TemplateHandler) GroceryShoppingPartial(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	h.renderGroceryShopping(w, householdID, groceryListID(r))
+}
+
+// GroceryListSetShop handles PUT to set the store a list is shopped at.
+// An empty store_id clears it.
+func (h *TemplateHandler) GroceryListSetShop(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	var shopID *int64
+	if v := r.FormValue("store_id"); v != "" {
+		sid, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid store id", http.StatusBadRequest)
+			return
+		}
+		shopID = &sid
+	}
+
+	existing, err := h.groceryStore.GetListByID(id, householdID)
+	if err != nil || existing == nil {
+		http.Error(w, "list not found", http.StatusNotFound)
+		return
+	}
+
+	list, err := h.groceryStore.SetListShop(id, householdID, shopID)
+	if err != nil {
+		h.logger.Error("set grocery list store", "error", err)
+		http.Error(w, "failed to set store", http.StatusInternalServerError)
+		return
+	}
+	if list == nil {
+		h.renderToast(w, "error", "Store not found")
+		h.renderGroceryContent(w, householdID, id)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_list", "updated", id, nil))
+
+	h.renderGroceryContent(w, householdID, id)
+}
+
+// GroceryShopCreate handles POST to add a store.
+func (h *TemplateHandler) GroceryShopCreate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.renderToast(w, "error", "Store name is required")
+		h.renderGroceryContent(w, householdID, groceryListID(r))
+		return
+	}
+
+	shop, err := h.groceryStore.CreateShop(householdID, name)
+	if err != nil {
+		h.logger.Error("create grocery store", "error", err)
+		http.Error(w, "failed to create store", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_store", "created", shop.ID, nil))
+
+	h.renderToast(w, "success", "Store added")
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
+
+// GroceryShopRename handles PUT to rename a store.
+func (h *TemplateHandler) GroceryShopRename(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.renderToast(w, "error", "Store name is required")
+		h.renderGroceryContent(w, householdID, groceryListID(r))
+		return
+	}
+
+	shop, err := h.groceryStore.RenameShop(id, householdID, name)
+	if err != nil {
+		h.logger.Error("rename grocery store", "error", err)
+		http.Error(w, "failed to rename store", http.StatusInternalServerError)
+		return
+	}
+	if shop == nil {
+		http.Error(w, "store not found", http.StatusNotFound)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_store", "updated", id, nil))
+
+	h.renderToast(w, "success", "Store renamed")
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
+
+// GroceryShopDelete handles DELETE for a store. Lists shopped there are
+// sorted by category again.
+func (h *TemplateHandler) GroceryShopDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	shop, err := h.groceryStore.GetShop(id, householdID)
+	if err != nil || shop == nil {
+		http.Error(w, "store not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.groceryStore.DeleteShop(id, householdID); err != nil {
+		h.logger.Error("delete grocery store", "error", err)
+		http.Error(w, "failed to delete store", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_store", "deleted", id, nil))
+
+	h.renderToast(w, "success", "Deleted "+shop.Name)
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
+
+// GroceryShopLayoutForm renders a store's layout form in the modal.
+func (h *TemplateHandler) GroceryShopLayoutForm(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	shop, err := h.groceryStore.GetShop(id, householdID)
+	if err != nil || shop == nil {
+		http.Error(w, "store not found", http.StatusNotFound)
+		return
+	}
+
+	h.renderPartial(w, "grocery-store-layout-form", map[string]any{
+		"Shop":   shop,
+		"ListID": groceryListID(r),
+	})
+}
+
+// GroceryShopLayoutUpdate handles PUT of the layout form. Each category
+// comes with a position to sort by and an optional aisle.
+func (h *TemplateHandler) GroceryShopLayoutUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	categoryIDs, positions, aisles := r.Form["category_id"], r.Form["position"], r.Form["aisle"]
+	if len(positions) != len(categoryIDs) || len(aisles) != len(categoryIDs) {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	type row struct {
+		position int
+		section  model.ShopSection
+	}
+	rows := make([]row, 0, len(categoryIDs))
+	for i, v := range categoryIDs {
+		categoryID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid category id", http.StatusBadRequest)
+			return
+		}
+		// A blank or invalid position keeps the row where it was.
+		position, err := strconv.Atoi(strings.TrimSpace(positions[i]))
+		if err != nil {
+			position = i + 1
+		}
+		rows = append(rows, row{position, model.ShopSection{CategoryID: categoryID, Aisle: strings.TrimSpace(aisles[i])}})
+	}
+	slices.SortStableFunc(rows, func(a, b row) int { return a.position - b.position })
+
+	sections := make([]model.ShopSection, len(rows))
+	for i, row := range rows {
+		sections[i] = row.section
+	}
+
+	shop, err := h.groceryStore.SetShopSections(id, householdID, sections)
+	if err != nil {
+		h.logger.Error("update grocery store layout", "error", err)
+		http.Error(w, "failed to update store layout", http.StatusInternalServerError)
+		return
+	}
+	if shop == nil {
+		http.Error(w, "store not found", http.StatusNotFound)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("grocery_store", "updated", id, nil))
+
+	w.Header().Set("HX-Trigger", "closeGroceryModal")
+	h.renderToast(w, "success", "Saved layout for "+shop.Name)
+	h.renderGroceryContent(w, householdID, groceryListID(r))
+}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/auth"
+	"github.com/dukerupert/gamwich/internal/ical"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/store"
+)
+
+// feedLookback limits how far back one-off events are included in feeds.
+const feedLookback = 365 * 24 * time.Hour
+
+// ICalHandler serves read-only iCalendar feeds authorized by a feed token
+// in the URL instead of a session.
+type ICalHandler struct {
+	icalStore      *store.ICalStore
+	eventStore     *store.EventStore
+	memberStore    *store.FamilyMemberStore
+	householdStore *store.HouseholdStore
+	settingsStore  *store.SettingsStore
+	logger         *slog.Logger
+}
+
+func NewICalHandler(is *store.ICalStore, es *store.EventStore, ms *store.FamilyMemberStore, hs *store.HouseholdStore, ss *store.SettingsStore, logger *slog.Logger) *ICalHandler {
+	return &ICalHandler{icalStore: is, eventStore: es, memberStore: ms, householdStore: hs, settingsStore: ss, logger: logger}
+}
+
+// HouseholdFeed handles GET /ical/{token}/household.ics
+func (h *ICalHandler) HouseholdFeed(w http.ResponseWriter, r *http.Request) {
+	tok := h.authorize(w, r)
+	if tok == nil {
+		return
+	}
+
+	events, err := h.eventStore.ListForFeed(tok.HouseholdID, time.Now().Add(-feedLookback))
+	if err != nil {
+		h.logger.Error("list feed events", "error", err)
+		http.Error(w, "failed to load events", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeFeed(w, tok.HouseholdID, h.householdName(tok.HouseholdID), "household.ics", events)
+}
+
+// MemberFeed handles GET /ical/{token}/member/{file}, where file is "{id}.ics".
+func (h *ICalHandler) MemberFeed(w http.ResponseWriter, r *http.Request) {
+	tok := h.authorize(w, r)
+	if tok == nil {
+		return
+	}
+
+	file := r.PathValue("file")
+	idStr, ok := strings.CutSuffix(file, ".ics")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	memberID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	member, err := h.memberStore.GetByID(memberID, tok.HouseholdID)
+	if err != nil {
+		h.logger.Error("get feed member", "error", err)
+		http.Error(w, "failed to load family member", http.StatusInternalServerError)
+		return
+	}
+	if member == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	events, err := h.eventStore.ListForFeed(tok.HouseholdID, time.Now().Add(-feedLookback))
+	if err != nil {
+		h.logger.Error("list feed events", "error", err)
+		http.Error(w, "failed to load events", http.StatusInternalServerError)
+		return
+	}
+
+	name := member.Name
+	if household := h.householdName(tok.HouseholdID); household != "" {
+		name = household + " – " + member.Name
+	}
+	h.writeFeed(w, tok.HouseholdID, name, file, filterEventsByMember(events, memberID))
+}
+
+// authorize resolves the feed token from the URL, writing a 404 if it is unknown.
+func (h *ICalHandler) authorize(w http.ResponseWriter, r *http.Request) *model.ICalToken {
+	tok, err := h.icalStore.GetByToken(r.PathValue("token"))
+	if err != nil {
+		h.logger.Error("get ical token", "error", err)
+		http.Error(w, "failed to load feed", http.StatusInternalServerError)
+		return nil
+	}
+	if tok == nil {
+		http.NotFound(w, r)
+		return nil
+	}
+	if err := h.icalStore.MarkUsed(tok.ID); err != nil {
+		h.logger.Warn("mark ical token used", "error", err)
+	}
+	return tok
+}
+
+func (h *ICalHandler) householdName(householdID int64) string {
+	household, err := h.householdStore.GetByID(householdID)
+	if err != nil || household == nil {
+		return ""
+	}
+	return household.Name
+}
+
+func (h *ICalHandler) writeFeed(w http.ResponseWriter, householdID int64, name, filename string, events []model.CalendarEvent) {
+	loc := householdLocation(h.settingsStore, householdID, h.logger)
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, name, events, loc, time.Now()); err != nil {
+		h.logger.Error("encode ical feed", "error", err)
+		http.Error(w, "failed to build feed", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(buf.Bytes())
+}
+
+// filterEventsByMember keeps the events the member attends in any role.
+// Exceptions follow their recurring parent so overrides stay attached to
+// the series.
+func filterEventsByMember(events []model.CalendarEvent, memberID int64) []model.CalendarEvent {
+	parents := make(map[int64]bool)
+	var filtered []model.CalendarEvent
+	for _, e := range events {
+		if e.RecurrenceParentID == nil && e.HasAttendee(memberID) {
+			parents[e.ID] = true
+			filtered = append(filtered, e)
+		}
+	}
+	for _, e := range events {
+		if e.RecurrenceParentID != nil && parents[*e.RecurrenceParentID] {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// feedBaseURL returns the scheme and host the request was made to, honouring
+// X-Forwarded-Proto from a reverse proxy or tunnel.
+func feedBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// icalFeedLink is a subscribable feed URL shown on the settings page.
+type icalFeedLink struct {
+	Name  string
+	Emoji string
+	URL   string
+}
+
+// icalNewFeed is the household and per-member URLs of a token that was just
+// created. Only the token's hash is stored, so they are shown once.
+type icalNewFeed struct {
+	HouseholdURL string
+	MemberFeeds  []icalFeedLink
+}
+
+func (h *TemplateHandler) buildICalSettingsData(r *http.Request, householdID int64, newToken string) (map[string]any, error) {
+	data := map[string]any{
+		"IsAdmin": auth.IsAdmin(r.Context()),
+	}
+	if !auth.IsAdmin(r.Context()) {
+		return data, nil
+	}
+
+	tokens, err := h.icalStore.List(householdID)
+	if err != nil {
+		return nil, err
+	}
+	data["Tokens"] = tokens
+	if newToken == "" {
+		return data, nil
+	}
+
+	members, err := h.store.List(householdID)
+	if err != nil {
+		return nil, err
+	}
+	prefix := feedBaseURL(r) + "/ical/" + newToken
+	feed := icalNewFeed{HouseholdURL: prefix + "/household.ics"}
+	for _, m := range members {
+		feed.MemberFeeds = append(feed.MemberFeeds, icalFeedLink{
+			Name:  m.Name,
+			Emoji: m.AvatarEmoji,
+			URL:   prefix + "/member/" + strconv.FormatInt(m.ID, 10) + ".ics",
+		})
+	}
+	data["NewFeed"] = feed
+	return data, nil
+}
+
+// ICalSettingsPartial renders the calendar feed settings card content.
+func (h *TemplateHandler) ICalSettingsPartial(w http.ResponseWriter, r *http.Request) {
+	h.renderICalSettings(w, r, "")
+}
+
+func (h *TemplateHandler) renderICalSettings(w http.ResponseWriter, r *http.Request, newToken string) {
+	householdID := auth.HouseholdID(r.Context())
+	data, err := h.buildICalSettingsData(r, householdID, newToken)
+	if err != nil {
+		h.logger.Error("build ical settings", "error", err)
+		h.renderToast(w, "error", "Failed to load calendar feeds")
+		return
+	}
+	h.renderPartial(w, "ical-settings-form", data)
+}
+
+// ICalTokenCreate creates a new calendar feed token and shows its links once.
+func (h *TemplateHandler) ICalTokenCreate(w http.ResponseWriter, r *http.Request) {
+	if !auth.IsAdmin(r.Context()) {
+		h.renderToast(w, "error", "Admin access required")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		h.renderToast(w, "error", "Invalid form data")
+		return
+	}
+
+	householdID := auth.HouseholdID(r.Context())
+	userID := auth.UserID(r.Context())
+	label := strings.TrimSpace(r.FormValue("label"))
+
+	_, token, err := h.icalStore.Create(householdID, label, &userID)
+	if err != nil {
+		h.logger.Error("create ical token", "error", err)
+		h.renderToast(w, "error", "Failed to create feed link")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": "Calendar feed link created"}`)
+	h.renderICalSettings(w, r, token)
+}
+
+// ICalTokenRevoke revokes a calendar feed token.
+func (h *TemplateHandler) ICalTokenRevoke(w http.ResponseWriter, r *http.Request) {
+	if !auth.IsAdmin(r.Context()) {
+		h.renderToast(w, "error", "Admin access required")
+		return
+	}
+	id, err := parseIDParam(r)
+	if err != nil {
+		h.renderToast(w, "error", "Invalid feed ID")
+		return
+	}
+
+	householdID := auth.HouseholdID(r.Context())
+	if err := h.icalStore.Delete(id, householdID); err != nil {
+		h.logger.Error("revoke ical token", "error", err)
+		h.renderToast(w, "error", "Failed to revoke feed link")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": "Calendar feed link revoked"}`)
+	h.ICalSettingsPartial(w, r)
+}
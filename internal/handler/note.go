@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dukerupert/gamwich/internal/auth"
 	"github.com/dukerupert/gamwich/internal/model"
 	"github.com/dukerupert/gamwich/internal/store"
 	"github.com/dukerupert/gamwich/internal/websocket"
@@ -23,9 +24,9 @@ func NewNoteHandler(ns *store.NoteStore, ms *store.FamilyMemberStore, hub *webso
 	return &NoteHandler{noteStore: ns, memberStore: ms, hub: hub, logger: logger}
 }
 
-func (h *NoteHandler) broadcast(msg websocket.Message) {
+func (h *NoteHandler) broadcast(householdID int64, msg websocket.Message) {
 	if h.hub != nil {
-		h.hub.Broadcast(msg)
+		h.hub.BroadcastToHousehold(householdID, msg)
 	}
 }
 
@@ -45,6 +46,7 @@ type noteRequest struct {
 }
 
 func (h *NoteHandler) Create(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	var req noteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
@@ -64,21 +66,25 @@ func (h *NoteHandler) Create(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "priority must be urgent, normal, or fun"})
 		return
 	}
+	if !memberExists(w, h.memberStore, householdID, req.AuthorID) {
+		return
+	}
 
-	note, err := h.noteStore.Create(req.Title, req.Body, req.AuthorID, req.Pinned, req.Priority, req.ExpiresAt)
+	note, err := h.noteStore.Create(householdID, req.Title, req.Body, req.AuthorID, req.Pinned, req.Priority, req.ExpiresAt)
 	if err != nil {
 		h.logger.Error("create note", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create note"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("note", "created", note.ID, nil))
+	h.broadcast(householdID, websocket.NewMessage("note", "created", note.ID, nil))
 
 	writeJSON(w, http.StatusCreated, note)
 }
 
 func (h *NoteHandler) List(w http.ResponseWriter, r *http.Request) {
-	notes, err := h.noteStore.List()
+	householdID := auth.HouseholdID(r.Context())
+	notes, err := h.noteStore.List(householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list notes"})
 		return
@@ -90,13 +96,14 @@ func (h *NoteHandler) List(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *NoteHandler) Update(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.noteStore.GetByID(id)
+	existing, err := h.noteStore.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get note"})
 		return
@@ -125,26 +132,30 @@ func (h *NoteHandler) Update(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "priority must be urgent, normal, or fun"})
 		return
 	}
+	if !memberExists(w, h.memberStore, householdID, req.AuthorID) {
+		return
+	}
 
-	note, err := h.noteStore.Update(id, req.Title, req.Body, req.AuthorID, req.Pinned, req.Priority, req.ExpiresAt)
+	note, err := h.noteStore.Update(id, householdID, req.Title, req.Body, req.AuthorID, req.Pinned, req.Priority, req.ExpiresAt)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update note"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("note", "updated", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("note", "updated", id, nil))
 
 	writeJSON(w, http.StatusOK, note)
 }
 
 func (h *NoteHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.noteStore.GetByID(id)
+	existing, err := h.noteStore.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get note"})
 		return
@@ -154,24 +165,25 @@ func (h *NoteHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.noteStore.Delete(id); err != nil {
+	if err := h.noteStore.Delete(id, householdID); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete note"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("note", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("note", "deleted", id, nil))
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *NoteHandler) TogglePinned(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	note, err := h.noteStore.TogglePinned(id)
+	note, err := h.noteStore.TogglePinned(id, householdID)
 	if err != nil {
 		h.logger.Error("toggle note pin", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to toggle pin"})
@@ -182,7 +194,7 @@ func (h *NoteHandler) TogglePinned(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("note", "pinned", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("note", "pinned", id, nil))
 
 	writeJSON(w, http.StatusOK, note)
 }
@@ -11,13 +11,32 @@ import (
 )
 
 type PushHandler struct {
-	pushStore *store.PushStore
-	service   *push.Service
-	logger    *slog.Logger
+	pushStore   *store.PushStore
+	memberStore *store.FamilyMemberStore
+	service     *push.Service
+	logger      *slog.Logger
 }
 
-func NewPushHandler(ps *store.PushStore, svc *push.Service, logger *slog.Logger) *PushHandler {
-	return &PushHandler{pushStore: ps, service: svc, logger: logger}
+func NewPushHandler(ps *store.PushStore, ms *store.FamilyMemberStore, svc *push.Service, logger *slog.Logger) *PushHandler {
+	return &PushHandler{pushStore: ps, memberStore: ms, service: svc, logger: logger}
+}
+
+// checkMember reports whether memberID is nil or a member of the household,
+// writing an error response if not.
+func (h *PushHandler) checkMember(w http.ResponseWriter, householdID int64, memberID *int64) bool {
+	if memberID == nil {
+		return true
+	}
+	member, err := h.memberStore.GetByID(*memberID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to check family member"})
+		return false
+	}
+	if member == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "family member not found"})
+		return false
+	}
+	return true
 }
 
 type subscribeRequest struct {
@@ -25,6 +44,8 @@ type subscribeRequest struct {
 	P256dh     string `json:"p256dh"`
 	Auth       string `json:"auth"`
 	DeviceName string `json:"device_name"`
+	// FamilyMemberID is who the device belongs to, if anyone.
+	FamilyMemberID *int64 `json:"family_member_id"`
 }
 
 // Subscribe handles POST /api/push/subscribe
@@ -42,6 +63,9 @@ func (h *PushHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "endpoint, p256dh, and auth are required"})
 		return
 	}
+	if !h.checkMember(w, householdID, req.FamilyMemberID) {
+		return
+	}
 
 	sub, err := h.pushStore.CreateSubscription(userID, householdID, req.Endpoint, req.P256dh, req.Auth, req.DeviceName)
 	if err != nil {
@@ -50,9 +74,60 @@ func (h *PushHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.FamilyMemberID != nil {
+		if err := h.pushStore.SetSubscriptionMember(sub.ID, householdID, req.FamilyMemberID); err != nil {
+			h.logger.Error("set push subscription member", "error", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save subscription"})
+			return
+		}
+		if sub, err = h.pushStore.GetByID(sub.ID, householdID); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save subscription"})
+			return
+		}
+	}
+
 	writeJSON(w, http.StatusCreated, sub)
 }
 
+// SetMember handles PUT /api/push/subscriptions/{id}/member
+func (h *PushHandler) SetMember(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	var req struct {
+		FamilyMemberID *int64 `json:"family_member_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+	if !h.checkMember(w, householdID, req.FamilyMemberID) {
+		return
+	}
+
+	if err := h.pushStore.SetSubscriptionMember(id, householdID, req.FamilyMemberID); err != nil {
+		h.logger.Error("set push subscription member", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update subscription"})
+		return
+	}
+
+	sub, err := h.pushStore.GetByID(id, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get subscription"})
+		return
+	}
+	if sub == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "subscription not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, sub)
+}
+
 // Unsubscribe handles DELETE /api/push/subscriptions/{id}
 func (h *PushHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
 	householdID := auth.HouseholdID(r.Context())
@@ -2,41 +2,65 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dukerupert/gamwich/internal/allowance"
+	"github.com/dukerupert/gamwich/internal/auth"
 	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/push"
 	"github.com/dukerupert/gamwich/internal/store"
 	"github.com/dukerupert/gamwich/internal/websocket"
 )
 
 type RewardHandler struct {
-	rewardStore *store.RewardStore
-	memberStore *store.FamilyMemberStore
-	hub         *websocket.Hub
-	logger      *slog.Logger
+	rewardStore   *store.RewardStore
+	memberStore   *store.FamilyMemberStore
+	settingsStore *store.SettingsStore
+	hub           *websocket.Hub
+	pushSched     *push.Scheduler
+	logger        *slog.Logger
 }
 
-func NewRewardHandler(rs *store.RewardStore, ms *store.FamilyMemberStore, hub *websocket.Hub, logger *slog.Logger) *RewardHandler {
-	return &RewardHandler{rewardStore: rs, memberStore: ms, hub: hub, logger: logger}
+func NewRewardHandler(rs *store.RewardStore, ms *store.FamilyMemberStore, ss *store.SettingsStore, hub *websocket.Hub, pushSched *push.Scheduler, logger *slog.Logger) *RewardHandler {
+	return &RewardHandler{rewardStore: rs, memberStore: ms, settingsStore: ss, hub: hub, pushSched: pushSched, logger: logger}
 }
 
-func (h *RewardHandler) broadcast(msg websocket.Message) {
+func (h *RewardHandler) broadcast(householdID int64, msg websocket.Message) {
 	if h.hub != nil {
-		h.hub.Broadcast(msg)
+		h.hub.BroadcastToHousehold(householdID, msg)
 	}
 }
 
 type rewardRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	PointCost   int    `json:"point_cost"`
-	Active      bool   `json:"active"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	PointCost    int    `json:"point_cost"`
+	Active       bool   `json:"active"`
+	Stock        *int   `json:"stock"`
+	CooldownDays int    `json:"cooldown_days"`
+}
+
+// validateLimits checks a reward's stock and cooldown. A nil stock means
+// there is no limit. It returns a message for the client if either is
+// invalid.
+func validateLimits(stock *int, cooldownDays int) string {
+	switch {
+	case stock != nil && *stock < 0:
+		return "stock must be >= 0"
+	case cooldownDays < 0:
+		return "cooldown_days must be >= 0"
+	}
+	return ""
 }
 
 func (h *RewardHandler) Create(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	var req rewardRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
@@ -52,21 +76,35 @@ func (h *RewardHandler) Create(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "point_cost must be >= 0"})
 		return
 	}
+	if msg := validateLimits(req.Stock, req.CooldownDays); msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
 
-	reward, err := h.rewardStore.Create(req.Title, req.Description, req.PointCost, req.Active)
+	reward, err := h.rewardStore.Create(householdID, req.Title, req.Description, req.PointCost, req.Active)
 	if err != nil {
 		h.logger.Error("create reward", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create reward"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("reward", "created", reward.ID, nil))
+	if req.Stock != nil || req.CooldownDays != 0 {
+		reward, err = h.saveLimits(reward.ID, householdID, req)
+		if err != nil {
+			h.logger.Error("set reward limits", "error", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create reward"})
+			return
+		}
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("reward", "created", reward.ID, nil))
 
 	writeJSON(w, http.StatusCreated, reward)
 }
 
 func (h *RewardHandler) List(w http.ResponseWriter, r *http.Request) {
-	rewards, err := h.rewardStore.List()
+	householdID := auth.HouseholdID(r.Context())
+	rewards, err := h.rewardStore.List(householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list rewards"})
 		return
@@ -78,13 +116,14 @@ func (h *RewardHandler) List(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *RewardHandler) Update(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.rewardStore.GetByID(id)
+	existing, err := h.rewardStore.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get reward"})
 		return
@@ -109,26 +148,44 @@ func (h *RewardHandler) Update(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "point_cost must be >= 0"})
 		return
 	}
+	if msg := validateLimits(req.Stock, req.CooldownDays); msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
 
-	reward, err := h.rewardStore.Update(id, req.Title, req.Description, req.PointCost, req.Active)
+	if _, err := h.rewardStore.Update(id, householdID, req.Title, req.Description, req.PointCost, req.Active); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update reward"})
+		return
+	}
+	reward, err := h.saveLimits(id, householdID, req)
 	if err != nil {
+		h.logger.Error("set reward limits", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update reward"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("reward", "updated", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("reward", "updated", id, nil))
 
 	writeJSON(w, http.StatusOK, reward)
 }
 
+// saveLimits saves a reward's stock and cooldown.
+func (h *RewardHandler) saveLimits(id, householdID int64, req rewardRequest) (*model.Reward, error) {
+	if err := h.rewardStore.SetLimits(id, householdID, req.Stock, req.CooldownDays); err != nil {
+		return nil, err
+	}
+	return h.rewardStore.GetByID(id, householdID)
+}
+
 func (h *RewardHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	existing, err := h.rewardStore.GetByID(id)
+	existing, err := h.rewardStore.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get reward"})
 		return
@@ -138,24 +195,25 @@ func (h *RewardHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.rewardStore.Delete(id); err != nil {
+	if err := h.rewardStore.Delete(id, householdID); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete reward"})
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("reward", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("reward", "deleted", id, nil))
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *RewardHandler) Redeem(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
 		return
 	}
 
-	reward, err := h.rewardStore.GetByID(id)
+	reward, err := h.rewardStore.GetByID(id, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get reward"})
 		return
@@ -177,32 +235,149 @@ func (h *RewardHandler) Redeem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check balance if member specified
-	if req.RedeemedBy != nil {
-		balance, err := h.rewardStore.GetPointBalance(*req.RedeemedBy)
-		if err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to check balance"})
-			return
-		}
-		if balance.Balance < reward.PointCost {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "insufficient points"})
-			return
-		}
+	redemption, err := h.rewardStore.Redeem(id, householdID, req.RedeemedBy, reward.PointCost)
+	if redeemRejected(err) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
 	}
-
-	redemption, err := h.rewardStore.Redeem(id, req.RedeemedBy, reward.PointCost)
 	if err != nil {
 		h.logger.Error("redeem reward", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to redeem reward"})
 		return
 	}
+	if redemption == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "reward not found"})
+		return
+	}
 
-	h.broadcast(websocket.NewMessage("reward", "redeemed", id, nil))
+	requestReward(h.memberStore, h.pushSched, householdID, *reward, redemption)
+	h.broadcast(householdID, websocket.NewMessage("reward", "redeemed", id, nil))
 
 	writeJSON(w, http.StatusCreated, redemption)
 }
 
+// redeemRejected reports whether err is Redeem turning a request down, as
+// opposed to failing.
+func redeemRejected(err error) bool {
+	return errors.Is(err, store.ErrInsufficientPoints) || errors.Is(err, store.ErrOutOfStock) || errors.Is(err, store.ErrOnCooldown)
+}
+
+// requestReward tells parents that a member has asked for a reward.
+func requestReward(ms *store.FamilyMemberStore, pushSched *push.Scheduler, householdID int64, reward model.Reward, redemption *model.RewardRedemption) {
+	if pushSched == nil {
+		return
+	}
+	var name string
+	if redemption.RedeemedBy != nil {
+		if m, err := ms.GetByID(*redemption.RedeemedBy, householdID); err == nil && m != nil {
+			name = m.Name
+		}
+	}
+	go pushSched.SendRewardRequest(householdID, reward.Title, name)
+}
+
+// rewardError is a change to a redemption that where it stands does not
+// allow.
+type rewardError string
+
+func (e rewardError) Error() string { return string(e) }
+
+const (
+	errInvalidRedemptionStatus = rewardError(`status must be "approved", "fulfilled" or "cancelled"`)
+	errRedemptionClosed        = rewardError("redemption can no longer be changed that way")
+	errOwnRedemption           = rewardError("members cannot approve their own rewards")
+)
+
+// moveRedemption approves, fulfils or cancels a redemption on behalf of
+// actorID, whose PIN the caller has already checked. Members cannot
+// approve or fulfil their own redemptions, but can cancel them.
+func moveRedemption(rs *store.RewardStore, householdID, redemptionID int64, status model.RedemptionStatus, actorID int64, note string) (*model.RewardRedemption, error) {
+	if status != model.RedemptionApproved && status != model.RedemptionFulfilled && status != model.RedemptionCancelled {
+		return nil, errInvalidRedemptionStatus
+	}
+	existing, err := rs.GetRedemption(redemptionID, householdID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil || !existing.Status.Open() {
+		return nil, errRedemptionClosed
+	}
+	if status != model.RedemptionCancelled && existing.RedeemedBy != nil && *existing.RedeemedBy == actorID {
+		return nil, errOwnRedemption
+	}
+	redemption, err := rs.UpdateRedemptionStatus(redemptionID, householdID, status, actorID, strings.TrimSpace(note))
+	if err != nil {
+		return nil, err
+	}
+	if redemption == nil {
+		return nil, errRedemptionClosed
+	}
+	return redemption, nil
+}
+
+func (h *RewardHandler) writeRewardError(w http.ResponseWriter, action string, err error) {
+	var rewardErr rewardError
+	if errors.As(err, &rewardErr) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": rewardErr.Error()})
+		return
+	}
+	h.logger.Error(action, "error", err)
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to " + action})
+}
+
+// ListRedemptions returns the redemptions waiting for a parent to approve
+// or fulfil them.
+func (h *RewardHandler) ListRedemptions(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	redemptions, err := h.rewardStore.ListOpenRedemptions(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list redemptions"})
+		return
+	}
+	if redemptions == nil {
+		redemptions = []model.RewardRedemption{}
+	}
+	writeJSON(w, http.StatusOK, redemptions)
+}
+
+// UpdateRedemptionStatus approves, fulfils or cancels a redemption. The
+// parent doing so must give their PIN.
+func (h *RewardHandler) UpdateRedemptionStatus(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	redemptionID, err := strconv.ParseInt(r.PathValue("redemption_id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid redemption_id"})
+		return
+	}
+
+	var req struct {
+		Status  model.RedemptionStatus `json:"status"`
+		ActorID int64                  `json:"actor_id"`
+		PIN     string                 `json:"pin"`
+		Note    string                 `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	if status, msg := verifyMemberPIN(h.memberStore, req.ActorID, householdID, req.PIN); msg != "" {
+		writeJSON(w, status, map[string]string{"error": msg})
+		return
+	}
+
+	redemption, err := moveRedemption(h.rewardStore, householdID, redemptionID, req.Status, req.ActorID, req.Note)
+	if err != nil {
+		h.writeRewardError(w, "update redemption", err)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("reward", string(redemption.Status), redemption.RewardID, nil))
+	writeJSON(w, http.StatusOK, redemption)
+}
+
 func (h *RewardHandler) GetPointBalance(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	memberIDStr := r.PathValue("id")
 	memberID, err := strconv.ParseInt(memberIDStr, 10, 64)
 	if err != nil {
@@ -210,17 +385,32 @@ func (h *RewardHandler) GetPointBalance(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	balance, err := h.rewardStore.GetPointBalance(memberID)
+	member, err := h.memberStore.GetByID(memberID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get family member"})
+		return
+	}
+	if member == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "family member not found"})
+		return
+	}
+
+	balance, err := h.rewardStore.GetPointBalance(memberID, householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get balance"})
 		return
 	}
+	if balance.Goals, err = goalProgress(h.rewardStore, memberID, householdID, balance.Balance); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get balance"})
+		return
+	}
 
 	writeJSON(w, http.StatusOK, balance)
 }
 
 func (h *RewardHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
-	balances, err := h.rewardStore.GetAllPointBalances()
+	householdID := auth.HouseholdID(r.Context())
+	balances, err := h.rewardStore.GetAllPointBalances(householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get leaderboard"})
 		return
@@ -230,3 +420,357 @@ func (h *RewardHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 	}
 	writeJSON(w, http.StatusOK, balances)
 }
+
+// validateAdjustment checks a manual change to a member's points. The
+// amount is signed: bonuses add points, penalties take them away, and
+// corrections can do either. It returns a message for the client if the
+// adjustment is invalid.
+func validateAdjustment(kind model.PointTransactionKind, amount int, reason string) string {
+	switch {
+	case !kind.Adjustment():
+		return `kind must be "bonus", "penalty" or "correction"`
+	case amount == 0:
+		return "amount must not be zero"
+	case kind == model.PointsBonus && amount < 0:
+		return "a bonus must add points"
+	case kind == model.PointsPenalty && amount > 0:
+		return "a penalty must take points away"
+	case reason == "":
+		return "reason is required"
+	}
+	return ""
+}
+
+// ListTransactions returns a member's point ledger, newest first.
+func (h *RewardHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	memberID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid member id"})
+		return
+	}
+
+	member, err := h.memberStore.GetByID(memberID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get family member"})
+		return
+	}
+	if member == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "family member not found"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	txns, err := h.rewardStore.ListTransactions(memberID, householdID, limit)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list transactions"})
+		return
+	}
+	if txns == nil {
+		txns = []model.PointTransaction{}
+	}
+	writeJSON(w, http.StatusOK, txns)
+}
+
+// AdjustPoints records a bonus, penalty or correction for a member. The
+// parent making it must give their PIN and cannot adjust their own points.
+func (h *RewardHandler) AdjustPoints(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	memberID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid member id"})
+		return
+	}
+
+	var req struct {
+		Kind    model.PointTransactionKind `json:"kind"`
+		Amount  int                        `json:"amount"`
+		Reason  string                     `json:"reason"`
+		ActorID int64                      `json:"actor_id"`
+		PIN     string                     `json:"pin"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if msg := validateAdjustment(req.Kind, req.Amount, req.Reason); msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
+	if req.ActorID == memberID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "members cannot adjust their own points"})
+		return
+	}
+	if status, msg := verifyMemberPIN(h.memberStore, req.ActorID, householdID, req.PIN); msg != "" {
+		writeJSON(w, status, map[string]string{"error": msg})
+		return
+	}
+
+	txn, err := h.rewardStore.AdjustPoints(memberID, householdID, req.Kind, req.Amount, req.Reason, &req.ActorID)
+	if err != nil {
+		h.logger.Error("adjust points", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to adjust points"})
+		return
+	}
+	if txn == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "family member not found"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("reward", "points_adjusted", memberID, nil))
+
+	writeJSON(w, http.StatusCreated, txn)
+}
+
+// memberParam looks up the family member named in the path. It writes the
+// error response and reports false if there is no such member.
+func (h *RewardHandler) memberParam(w http.ResponseWriter, r *http.Request) (*model.FamilyMember, bool) {
+	memberID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid member id"})
+		return nil, false
+	}
+	member, err := h.memberStore.GetByID(memberID, auth.HouseholdID(r.Context()))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get family member"})
+		return nil, false
+	}
+	if member == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "family member not found"})
+		return nil, false
+	}
+	return member, true
+}
+
+// goalProgress measures a member's balance against each of their savings
+// goals.
+func goalProgress(rs *store.RewardStore, memberID, householdID int64, balance int) ([]model.SavingsGoalProgress, error) {
+	goals, err := rs.ListGoals(memberID, householdID)
+	if err != nil {
+		return nil, err
+	}
+	progress := make([]model.SavingsGoalProgress, 0, len(goals))
+	for _, g := range goals {
+		progress = append(progress, g.Progress(balance))
+	}
+	return progress, nil
+}
+
+// validateAllowance checks what a member's points are worth in cents and
+// their weekly allowance. It returns a message for the client if either is
+// invalid.
+func validateAllowance(centsPerPoint, weeklyPoints int, weekday time.Weekday) string {
+	switch {
+	case centsPerPoint < 0:
+		return "cents_per_point must be >= 0"
+	case weeklyPoints < 0:
+		return "weekly_points must be >= 0"
+	case weekday < time.Sunday || weekday > time.Saturday:
+		return "weekday must be between 0 (Sunday) and 6 (Saturday)"
+	}
+	return ""
+}
+
+// GetAllowance returns a member's allowance. Members without one get an
+// allowance that pays nothing.
+func (h *RewardHandler) GetAllowance(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	member, ok := h.memberParam(w, r)
+	if !ok {
+		return
+	}
+
+	a, err := h.rewardStore.GetAllowance(member.ID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get allowance"})
+		return
+	}
+	if a == nil {
+		a = &model.Allowance{FamilyMemberID: member.ID, Weekday: time.Saturday}
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+// SetAllowance sets what a member's points are worth and their weekly
+// allowance. The parent doing so must give their PIN and cannot set their
+// own allowance.
+func (h *RewardHandler) SetAllowance(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	member, ok := h.memberParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		CentsPerPoint int          `json:"cents_per_point"`
+		WeeklyPoints  int          `json:"weekly_points"`
+		Weekday       time.Weekday `json:"weekday"`
+		ActorID       int64        `json:"actor_id"`
+		PIN           string       `json:"pin"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	if msg := validateAllowance(req.CentsPerPoint, req.WeeklyPoints, req.Weekday); msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
+	if req.ActorID == member.ID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "members cannot set their own allowance"})
+		return
+	}
+	if status, msg := verifyMemberPIN(h.memberStore, req.ActorID, householdID, req.PIN); msg != "" {
+		writeJSON(w, status, map[string]string{"error": msg})
+		return
+	}
+
+	today := time.Now().In(householdLocation(h.settingsStore, householdID, h.logger)).Format("2006-01-02")
+	a, err := h.rewardStore.SetAllowance(member.ID, householdID, req.CentsPerPoint, req.WeeklyPoints, req.Weekday, today)
+	if err != nil {
+		h.logger.Error("set allowance", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to set allowance"})
+		return
+	}
+	if a == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "family member not found"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("reward", "allowance_updated", member.ID, nil))
+
+	writeJSON(w, http.StatusOK, a)
+}
+
+// ListGoals returns a member's savings goals and how far their balance
+// gets them towards each.
+func (h *RewardHandler) ListGoals(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	member, ok := h.memberParam(w, r)
+	if !ok {
+		return
+	}
+
+	balance, err := h.rewardStore.GetPointBalance(member.ID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get balance"})
+		return
+	}
+	goals, err := goalProgress(h.rewardStore, member.ID, householdID, balance.Balance)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list goals"})
+		return
+	}
+	writeJSON(w, http.StatusOK, goals)
+}
+
+// CreateGoal adds something a member is saving their points towards.
+func (h *RewardHandler) CreateGoal(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	member, ok := h.memberParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Title        string `json:"title"`
+		TargetPoints int    `json:"target_points"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "title is required"})
+		return
+	}
+	if req.TargetPoints <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "target_points must be > 0"})
+		return
+	}
+
+	goal, err := h.rewardStore.CreateGoal(member.ID, householdID, req.Title, req.TargetPoints)
+	if err != nil {
+		h.logger.Error("create savings goal", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create goal"})
+		return
+	}
+	if goal == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "family member not found"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("reward", "goal_created", member.ID, nil))
+
+	writeJSON(w, http.StatusCreated, goal)
+}
+
+// DeleteGoal removes one of a member's savings goals.
+func (h *RewardHandler) DeleteGoal(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	member, ok := h.memberParam(w, r)
+	if !ok {
+		return
+	}
+	goalID, err := strconv.ParseInt(r.PathValue("goal_id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid goal_id"})
+		return
+	}
+
+	if err := h.rewardStore.DeleteGoal(goalID, member.ID, householdID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete goal"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("reward", "goal_deleted", member.ID, nil))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Statement downloads a member's points for a month as CSV. The month is
+// given as YYYY-MM and defaults to the current one.
+func (h *RewardHandler) Statement(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	member, ok := h.memberParam(w, r)
+	if !ok {
+		return
+	}
+
+	loc := householdLocation(h.settingsStore, householdID, h.logger)
+	month := time.Now().In(loc)
+	if m := r.URL.Query().Get("month"); m != "" {
+		var err error
+		if month, err = time.ParseInLocation("2006-01", m, loc); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "month must be YYYY-MM"})
+			return
+		}
+	}
+
+	txns, err := h.rewardStore.ListTransactions(member.ID, householdID, 0)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list transactions"})
+		return
+	}
+	a, err := h.rewardStore.GetAllowance(member.ID, householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get allowance"})
+		return
+	}
+	var centsPerPoint int
+	if a != nil {
+		centsPerPoint = a.CentsPerPoint
+	}
+	st := allowance.BuildStatement(member.Name, txns, month, centsPerPoint)
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", st.Filename()))
+	if err := st.WriteCSV(w); err != nil {
+		h.logger.Error("write statement", "error", err)
+	}
+}
@@ -3,10 +3,13 @@ package handler
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"regexp"
 	"strconv"
+	"time"
 
+	"github.com/dukerupert/gamwich/internal/auth"
 	"github.com/dukerupert/gamwich/internal/backup"
 	"github.com/dukerupert/gamwich/internal/store"
 	"github.com/dukerupert/gamwich/internal/weather"
@@ -26,14 +29,15 @@ func NewSettingsHandler(ss *store.SettingsStore, ws *weather.Service, bm *backup
 	return &SettingsHandler{settingsStore: ss, weatherSvc: ws, backupManager: bm, hub: hub}
 }
 
-func (h *SettingsHandler) broadcast(msg websocket.Message) {
+func (h *SettingsHandler) broadcast(householdID int64, msg websocket.Message) {
 	if h.hub != nil {
-		h.hub.Broadcast(msg)
+		h.hub.BroadcastToHousehold(householdID, msg)
 	}
 }
 
 func (h *SettingsHandler) GetKiosk(w http.ResponseWriter, r *http.Request) {
-	settings, err := h.settingsStore.GetKioskSettings()
+	householdID := auth.HouseholdID(r.Context())
+	settings, err := h.settingsStore.GetKioskSettings(householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get settings"})
 		return
@@ -42,6 +46,7 @@ func (h *SettingsHandler) GetKiosk(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *SettingsHandler) UpdateKiosk(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	var req map[string]string
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
@@ -54,15 +59,15 @@ func (h *SettingsHandler) UpdateKiosk(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for key, value := range req {
-		if err := h.settingsStore.Set(key, value); err != nil {
+		if err := h.settingsStore.Set(householdID, key, value); err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save settings"})
 			return
 		}
 	}
 
-	h.broadcast(websocket.NewMessage("settings", "updated", 0, nil))
+	h.broadcast(householdID, websocket.NewMessage("settings", "updated", 0, nil))
 
-	settings, err := h.settingsStore.GetKioskSettings()
+	settings, err := h.settingsStore.GetKioskSettings(householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get settings"})
 		return
@@ -71,7 +76,8 @@ func (h *SettingsHandler) UpdateKiosk(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *SettingsHandler) GetWeather(w http.ResponseWriter, r *http.Request) {
-	settings, err := h.settingsStore.GetWeatherSettings()
+	householdID := auth.HouseholdID(r.Context())
+	settings, err := h.settingsStore.GetWeatherSettings(householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get settings"})
 		return
@@ -80,6 +86,7 @@ func (h *SettingsHandler) GetWeather(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *SettingsHandler) UpdateWeather(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	var req map[string]string
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
@@ -92,7 +99,7 @@ func (h *SettingsHandler) UpdateWeather(w http.ResponseWriter, r *http.Request)
 	}
 
 	for key, value := range req {
-		if err := h.settingsStore.Set(key, value); err != nil {
+		if err := h.settingsStore.Set(householdID, key, value); err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save settings"})
 			return
 		}
@@ -106,9 +113,9 @@ func (h *SettingsHandler) UpdateWeather(w http.ResponseWriter, r *http.Request)
 		})
 	}
 
-	h.broadcast(websocket.NewMessage("settings", "updated", 0, nil))
+	h.broadcast(householdID, websocket.NewMessage("settings", "updated", 0, nil))
 
-	settings, err := h.settingsStore.GetWeatherSettings()
+	settings, err := h.settingsStore.GetWeatherSettings(householdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get settings"})
 		return
@@ -177,8 +184,73 @@ func validateKioskSettings(settings map[string]string) error {
 	return nil
 }
 
+func (h *SettingsHandler) GetTimezone(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	loc, err := h.settingsStore.Location(householdID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get settings"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{store.TimezoneKey: loc.String()})
+}
+
+func (h *SettingsHandler) UpdateTimezone(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	var req map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	name := req[store.TimezoneKey]
+	if err := validateTimezone(name); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if err := h.settingsStore.Set(householdID, store.TimezoneKey, name); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save settings"})
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("settings", "updated", 0, nil))
+	writeJSON(w, http.StatusOK, map[string]string{store.TimezoneKey: name})
+}
+
+// validateTimezone checks that name is an IANA timezone name.
+func validateTimezone(name string) error {
+	if name == "" || name == "Local" {
+		return fmt.Errorf("timezone must be an IANA timezone name such as \"America/Denver\"")
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("unknown timezone: %s", name)
+	}
+	return nil
+}
+
+// householdLocation returns the household's timezone, falling back to UTC
+// if the setting cannot be read.
+func householdLocation(ss *store.SettingsStore, householdID int64, logger *slog.Logger) *time.Location {
+	loc, err := ss.Location(householdID)
+	if err != nil {
+		logger.Warn("household timezone", "household_id", householdID, "error", err)
+	}
+	return loc
+}
+
+// instanceAdmin reports whether the request is from an admin of the
+// default household, which holds the settings shared by the whole
+// instance: the license key, remote access and S3 storage.
+func instanceAdmin(r *http.Request) bool {
+	return auth.HouseholdID(r.Context()) == store.DefaultHouseholdID && auth.IsAdmin(r.Context())
+}
+
 func (h *SettingsHandler) GetS3(w http.ResponseWriter, r *http.Request) {
-	settings, err := h.settingsStore.GetS3Settings()
+	if !instanceAdmin(r) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin access required"})
+		return
+	}
+	settings, err := h.settingsStore.GetS3Settings(store.DefaultHouseholdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get settings"})
 		return
@@ -187,6 +259,10 @@ func (h *SettingsHandler) GetS3(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *SettingsHandler) UpdateS3(w http.ResponseWriter, r *http.Request) {
+	if !instanceAdmin(r) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin access required"})
+		return
+	}
 	var req map[string]string
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
@@ -199,7 +275,7 @@ func (h *SettingsHandler) UpdateS3(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for key, value := range req {
-		if err := h.settingsStore.Set(key, value); err != nil {
+		if err := h.settingsStore.Set(store.DefaultHouseholdID, key, value); err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save settings"})
 			return
 		}
@@ -215,9 +291,9 @@ func (h *SettingsHandler) UpdateS3(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	h.broadcast(websocket.NewMessage("settings", "updated", 0, nil))
+	h.broadcast(store.DefaultHouseholdID, websocket.NewMessage("settings", "updated", 0, nil))
 
-	settings, err := h.settingsStore.GetS3Settings()
+	settings, err := h.settingsStore.GetS3Settings(store.DefaultHouseholdID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get settings"})
 		return
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -12,11 +13,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dukerupert/gamwich/internal/allowance"
 	"github.com/dukerupert/gamwich/internal/auth"
 	"github.com/dukerupert/gamwich/internal/backup"
+	"github.com/dukerupert/gamwich/internal/calendar"
+	"github.com/dukerupert/gamwich/internal/calsync"
 	"github.com/dukerupert/gamwich/internal/chore"
 
-	"github.com/dukerupert/gamwich/internal/grocery"
 	"github.com/dukerupert/gamwich/internal/license"
 	"github.com/dukerupert/gamwich/internal/model"
 	"github.com/dukerupert/gamwich/internal/push"
@@ -33,6 +36,7 @@ const activeUserCookie = "gamwich_active_user"
 type TemplateHandler struct {
 	store          *store.FamilyMemberStore
 	eventStore     *store.EventStore
+	calService     *calendar.Service
 	choreStore     *store.ChoreStore
 	groceryStore   *store.GroceryStore
 	noteStore      *store.NoteStore
@@ -47,14 +51,22 @@ type TemplateHandler struct {
 	pushStore      *store.PushStore
 	pushService    *push.Service
 	pushScheduler  *push.Scheduler
+	icalStore      *store.ICalStore
+	calSubStore    *store.CalendarSubscriptionStore
+	calImporter    *calsync.Importer
+	calSync        *calsync.Scheduler
+	caldavStore    *store.CalDAVStore
+	caldavSyncer   *calsync.CalDAVSyncer
+	davStore       *store.DAVStore
 	templates      *template.Template
 	logger         *slog.Logger
 }
 
-func NewTemplateHandler(s *store.FamilyMemberStore, es *store.EventStore, cs *store.ChoreStore, gs *store.GroceryStore, ns *store.NoteStore, rs *store.RewardStore, ss *store.SettingsStore, w *weather.Service, hub *websocket.Hub, lc *license.Client, tm *tunnel.Manager, bm *backup.Manager, bs *store.BackupStore, ps *store.PushStore, pushSvc *push.Service, pushSched *push.Scheduler, logger *slog.Logger) *TemplateHandler {
+func NewTemplateHandler(s *store.FamilyMemberStore, es *store.EventStore, cal *calendar.Service, cs *store.ChoreStore, gs *store.GroceryStore, ns *store.NoteStore, rs *store.RewardStore, ss *store.SettingsStore, w *weather.Service, hub *websocket.Hub, lc *license.Client, tm *tunnel.Manager, bm *backup.Manager, bs *store.BackupStore, ps *store.PushStore, pushSvc *push.Service, pushSched *push.Scheduler, is *store.ICalStore, css *store.CalendarSubscriptionStore, ci *calsync.Importer, csched *calsync.Scheduler, cds *store.CalDAVStore, cdsync *calsync.CalDAVSyncer, ds *store.DAVStore, logger *slog.Logger) *TemplateHandler {
 	funcMap := template.FuncMap{
 		"add":         func(a, b int) int { return a + b },
 		"formatBytes": formatBytes,
+		"money":       allowance.FormatCents,
 		"seq": func(start, end int) []int {
 			s := make([]int, 0, end-start+1)
 			for i := start; i <= end; i++ {
@@ -67,6 +79,7 @@ func NewTemplateHandler(s *store.FamilyMemberStore, es *store.EventStore, cs *st
 	return &TemplateHandler{
 		store:         s,
 		eventStore:    es,
+		calService:    cal,
 		choreStore:    cs,
 		groceryStore:  gs,
 		noteStore:     ns,
@@ -81,6 +94,13 @@ func NewTemplateHandler(s *store.FamilyMemberStore, es *store.EventStore, cs *st
 		pushStore:     ps,
 		pushService:   pushSvc,
 		pushScheduler: pushSched,
+		icalStore:     is,
+		calSubStore:   css,
+		calImporter:   ci,
+		calSync:       csched,
+		caldavStore:   cds,
+		caldavSyncer:  cdsync,
+		davStore:      ds,
 		templates:     tmpl,
 		logger:        logger,
 	}
@@ -104,23 +124,24 @@ func formatBytes(b int64) string {
 	}
 }
 
-func (h *TemplateHandler) broadcast(msg websocket.Message) {
+func (h *TemplateHandler) broadcast(householdID int64, msg websocket.Message) {
 	if h.hub != nil {
-		h.hub.Broadcast(msg)
+		h.hub.BroadcastToHousehold(householdID, msg)
 	}
 }
 
 // buildDashboardData assembles the common data map for layout rendering.
 func (h *TemplateHandler) buildDashboardData(r *http.Request, section string) (map[string]any, error) {
-	members, err := h.store.List()
+	householdID := auth.HouseholdID(r.Context())
+	members, err := h.store.List(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load members: %w", err)
 	}
 
 	activeUserID := h.activeUserFromCookie(r)
 
-	kioskSettings, _ := h.settingsStore.GetKioskSettings()
-	themeSettings, _ := h.settingsStore.GetThemeSettings()
+	kioskSettings, _ := h.settingsStore.GetKioskSettings(householdID)
+	themeSettings, _ := h.settingsStore.GetThemeSettings(householdID)
 
 	data := map[string]any{
 		"Title":          "Gamwich",
@@ -132,10 +153,12 @@ func (h *TemplateHandler) buildDashboardData(r *http.Request, section string) (m
 		"ThemeSettings":  themeSettings,
 		"IsFreeTier":     h.licenseClient.IsFreeTier(),
 	}
+	data["InstanceAdmin"] = instanceAdmin(r)
 	return data, nil
 }
 
 // activeUserFromCookie reads and parses the active user ID from the cookie.
+// It returns 0 unless the user is a member of the request's household.
 func (h *TemplateHandler) activeUserFromCookie(r *http.Request) int64 {
 	c, err := r.Cookie(activeUserCookie)
 	if err != nil {
@@ -145,6 +168,10 @@ func (h *TemplateHandler) activeUserFromCookie(r *http.Request) int64 {
 	if err != nil {
 		return 0
 	}
+	member, err := h.store.GetByID(id, auth.HouseholdID(r.Context()))
+	if err != nil || member == nil {
+		return 0
+	}
 	return id
 }
 
@@ -159,6 +186,7 @@ func (h *TemplateHandler) renderSection(name string, data any) (template.HTML, e
 
 // Dashboard renders the main dashboard page with the home section.
 func (h *TemplateHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if r.URL.Path != "/" {
 		http.NotFound(w, r)
 		return
@@ -170,18 +198,21 @@ func (h *TemplateHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	choreSummary, _ := h.buildChoreSummaryData()
+	choreSummary, _ := h.buildChoreSummaryData(householdID)
 	data["ChoreSummary"] = choreSummary
 
-	grocerySummary, _ := h.buildGrocerySummaryData()
+	grocerySummary, _ := h.buildGrocerySummaryData(householdID)
 	data["GrocerySummary"] = grocerySummary
 
-	noteSummary, _ := h.buildNoteSummaryData()
+	noteSummary, _ := h.buildNoteSummaryData(householdID)
 	data["NoteSummary"] = noteSummary
 
-	leaderboard, _ := h.buildLeaderboardData()
+	leaderboard, _ := h.buildLeaderboardData(householdID)
 	data["Leaderboard"] = leaderboard
 
+	approvals, _ := h.buildApprovalQueueData(householdID)
+	data["Approvals"] = approvals
+
 	content, err := h.renderSection("dashboard-content", data)
 	if err != nil {
 		h.logger.Error("render dashboard content", "error", err)
@@ -217,29 +248,34 @@ func (h *TemplateHandler) SectionPage(section string) http.HandlerFunc {
 
 // DashboardPartial renders only the dashboard section content for HTMX swap.
 func (h *TemplateHandler) DashboardPartial(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	data, err := h.buildDashboardData(r, "dashboard")
 	if err != nil {
 		http.Error(w, "failed to load data", http.StatusInternalServerError)
 		return
 	}
 
-	choreSummary, _ := h.buildChoreSummaryData()
+	choreSummary, _ := h.buildChoreSummaryData(householdID)
 	data["ChoreSummary"] = choreSummary
 
-	grocerySummary, _ := h.buildGrocerySummaryData()
+	grocerySummary, _ := h.buildGrocerySummaryData(householdID)
 	data["GrocerySummary"] = grocerySummary
 
-	noteSummary, _ := h.buildNoteSummaryData()
+	noteSummary, _ := h.buildNoteSummaryData(householdID)
 	data["NoteSummary"] = noteSummary
 
-	leaderboard, _ := h.buildLeaderboardData()
+	leaderboard, _ := h.buildLeaderboardData(householdID)
 	data["Leaderboard"] = leaderboard
 
+	approvals, _ := h.buildApprovalQueueData(householdID)
+	data["Approvals"] = approvals
+
 	h.renderPartial(w, "dashboard-content", data)
 }
 
 // CalendarPage renders the full calendar page inside the dashboard layout.
 func (h *TemplateHandler) CalendarPage(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	data, err := h.buildDashboardData(r, "calendar")
 	if err != nil {
 		http.Error(w, "failed to load data", http.StatusInternalServerError)
@@ -252,7 +288,7 @@ func (h *TemplateHandler) CalendarPage(w http.ResponseWriter, r *http.Request) {
 	}
 	date := h.parseCalendarDate(r)
 
-	viewContent, err := h.buildCalendarViewContent(view, date)
+	viewContent, err := h.buildCalendarViewContent(householdID, view, date, calendarMemberFilter(r))
 	if err != nil {
 		h.logger.Error("build calendar view", "error", err)
 		http.Error(w, "template error", http.StatusInternalServerError)
@@ -278,13 +314,14 @@ func (h *TemplateHandler) CalendarPage(w http.ResponseWriter, r *http.Request) {
 
 // CalendarPartial renders the calendar section with the default or requested view.
 func (h *TemplateHandler) CalendarPartial(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	view := r.URL.Query().Get("view")
 	if view != "week" {
 		view = "day"
 	}
 	date := h.parseCalendarDate(r)
 
-	viewContent, err := h.buildCalendarViewContent(view, date)
+	viewContent, err := h.buildCalendarViewContent(householdID, view, date, calendarMemberFilter(r))
 	if err != nil {
 		h.logger.Error("build calendar view", "error", err)
 		http.Error(w, "template error", http.StatusInternalServerError)
@@ -300,8 +337,9 @@ func (h *TemplateHandler) CalendarPartial(w http.ResponseWriter, r *http.Request
 
 // CalendarDayPartial renders the day view for HTMX swap.
 func (h *TemplateHandler) CalendarDayPartial(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	date := h.parseCalendarDate(r)
-	data, err := h.buildDayViewData(date)
+	data, err := h.buildDayViewData(householdID, date, calendarMemberFilter(r))
 	if err != nil {
 		h.logger.Error("build day view", "error", err)
 		http.Error(w, "failed to load events", http.StatusInternalServerError)
@@ -312,8 +350,9 @@ func (h *TemplateHandler) CalendarDayPartial(w http.ResponseWriter, r *http.Requ
 
 // CalendarWeekPartial renders the week view for HTMX swap.
 func (h *TemplateHandler) CalendarWeekPartial(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	date := h.parseCalendarDate(r)
-	data, err := h.buildWeekViewData(date)
+	data, err := h.buildWeekViewData(householdID, date, calendarMemberFilter(r))
 	if err != nil {
 		h.logger.Error("build week view", "error", err)
 		http.Error(w, "failed to load events", http.StatusInternalServerError)
@@ -324,13 +363,14 @@ func (h *TemplateHandler) CalendarWeekPartial(w http.ResponseWriter, r *http.Req
 
 // CalendarEventDetail renders the event detail card in the modal.
 func (h *TemplateHandler) CalendarEventDetail(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	event, err := h.eventStore.GetByID(id)
+	event, err := h.eventStore.GetByID(id, householdID)
 	if err != nil {
 		http.Error(w, "failed to get event", http.StatusInternalServerError)
 		return
@@ -351,12 +391,13 @@ func (h *TemplateHandler) CalendarEventDetail(w http.ResponseWriter, r *http.Req
 		parentID = event.ID
 	}
 
-	data := h.buildEventDetailData(event, isRecurring, parentID, occurrenceDate)
+	data := h.buildEventDetailData(householdID, event, isRecurring, parentID, occurrenceDate)
 	h.renderPartial(w, "calendar-event-detail", data)
 }
 
 // CalendarEventNewForm renders the event creation form in the modal.
 func (h *TemplateHandler) CalendarEventNewForm(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	date := h.parseCalendarDate(r)
 	hourStr := r.URL.Query().Get("hour")
 	hour := 9 // default
@@ -364,7 +405,7 @@ func (h *TemplateHandler) CalendarEventNewForm(w http.ResponseWriter, r *http.Re
 		hour = h
 	}
 
-	members, _ := h.store.List()
+	members, _ := h.store.List(householdID)
 
 	data := map[string]any{
 		"DateYear":    date.Year(),
@@ -381,6 +422,7 @@ func (h *TemplateHandler) CalendarEventNewForm(w http.ResponseWriter, r *http.Re
 
 // CalendarEventCreate handles POST form submission to create an event.
 func (h *TemplateHandler) CalendarEventCreate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -422,17 +464,19 @@ func (h *TemplateHandler) CalendarEventCreate(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	var familyMemberID *int64
-	if memberStr := r.FormValue("family_member_id"); memberStr != "" && memberStr != "0" {
-		id, err := strconv.ParseInt(memberStr, 10, 64)
-		if err == nil {
-			familyMemberID = &id
-		}
-	}
-
+	attendees := h.formAttendees(r, householdID)
 	recurrenceRule := r.FormValue("recurrence_rule")
 
-	event, err := h.eventStore.CreateWithRecurrence(title, description, startTime, endTime, allDay, familyMemberID, location, recurrenceRule)
+	event, err := h.eventStore.CreateWithAttendees(householdID, model.CalendarEvent{
+		Title:          title,
+		Description:    description,
+		StartTime:      startTime,
+		EndTime:        endTime,
+		AllDay:         allDay,
+		Attendees:      attendees,
+		Location:       location,
+		RecurrenceRule: recurrenceRule,
+	})
 	if err != nil {
 		h.logger.Error("create event", "error", err)
 		http.Error(w, "failed to create event", http.StatusInternalServerError)
@@ -441,18 +485,19 @@ func (h *TemplateHandler) CalendarEventCreate(w http.ResponseWriter, r *http.Req
 
 	if rmStr := r.FormValue("reminder_minutes"); rmStr != "" {
 		if rm, err := strconv.Atoi(rmStr); err == nil {
-			if err := h.eventStore.SetReminderMinutes(event.ID, &rm); err != nil {
+			if err := h.eventStore.SetReminderMinutes(event.ID, householdID, &rm); err != nil {
 				h.logger.Error("set reminder", "error", err)
 			}
 		}
 	}
 
-	h.broadcast(websocket.NewMessage("calendar_event", "created", event.ID, nil))
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "created", event.ID, nil))
+	h.calSync.Notify(householdID)
 
 	w.Header().Set("HX-Trigger", "closeEventModal")
 
 	// Re-render the current day view
-	data, err := h.buildDayViewData(date)
+	data, err := h.buildDayViewData(householdID, date, 0)
 	if err != nil {
 		http.Error(w, "failed to load events", http.StatusInternalServerError)
 		return
@@ -463,13 +508,14 @@ func (h *TemplateHandler) CalendarEventCreate(w http.ResponseWriter, r *http.Req
 
 // CalendarEventEditForm renders the edit form for an event.
 func (h *TemplateHandler) CalendarEventEditForm(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	event, err := h.eventStore.GetByID(id)
+	event, err := h.eventStore.GetByID(id, householdID)
 	if err != nil {
 		http.Error(w, "failed to get event", http.StatusInternalServerError)
 		return
@@ -478,12 +524,16 @@ func (h *TemplateHandler) CalendarEventEditForm(w http.ResponseWriter, r *http.R
 		http.Error(w, "event not found", http.StatusNotFound)
 		return
 	}
+	if event.Imported() {
+		h.renderToast(w, "error", "Imported events are read-only")
+		return
+	}
 
-	members, _ := h.store.List()
+	members, _ := h.store.List(householdID)
 
-	var familyMemberID int64
-	if event.FamilyMemberID != nil {
-		familyMemberID = *event.FamilyMemberID
+	attendeeRoles := make(map[int64]model.AttendeeRole)
+	for _, a := range event.Attendees {
+		attendeeRoles[a.FamilyMemberID] = a.Role
 	}
 
 	// For recurring events, accept mode/occurrence context from query params
@@ -509,7 +559,7 @@ func (h *TemplateHandler) CalendarEventEditForm(w http.ResponseWriter, r *http.R
 		"StartMinute":     event.StartTime.Minute(),
 		"EndHour":         event.EndTime.Hour(),
 		"EndMinute":       event.EndTime.Minute(),
-		"FamilyMemberID":  familyMemberID,
+		"AttendeeRoles":   attendeeRoles,
 		"Members":         members,
 		"RecurrenceRule":  event.RecurrenceRule,
 		"ReminderMinutes": reminderMinutes,
@@ -524,12 +574,27 @@ func (h *TemplateHandler) CalendarEventEditForm(w http.ResponseWriter, r *http.R
 
 // CalendarEventUpdate handles PUT form submission to update an event.
 func (h *TemplateHandler) CalendarEventUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
+	existing, err := h.eventStore.GetByID(id, householdID)
+	if err != nil {
+		http.Error(w, "failed to get event", http.StatusInternalServerError)
+		return
+	}
+	if existing == nil {
+		http.Error(w, "event not found", http.StatusNotFound)
+		return
+	}
+	if existing.Imported() {
+		h.renderToast(w, "error", "Imported events are read-only")
+		return
+	}
+
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -571,15 +636,19 @@ func (h *TemplateHandler) CalendarEventUpdate(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	var familyMemberID *int64
-	if memberStr := r.FormValue("family_member_id"); memberStr != "" && memberStr != "0" {
-		mid, err := strconv.ParseInt(memberStr, 10, 64)
-		if err == nil {
-			familyMemberID = &mid
-		}
+	attendees := h.formAttendees(r, householdID)
+	recurrenceRule := r.FormValue("recurrence_rule")
+	edited := model.CalendarEvent{
+		Title:          title,
+		Description:    description,
+		StartTime:      startTime,
+		EndTime:        endTime,
+		AllDay:         allDay,
+		Attendees:      attendees,
+		Location:       location,
+		RecurrenceRule: recurrenceRule,
 	}
 
-	recurrenceRule := r.FormValue("recurrence_rule")
 	mode := r.FormValue("mode")
 	parentIDStr := r.FormValue("parent_id")
 	occurrenceDateStr := r.FormValue("occurrence_date")
@@ -591,7 +660,7 @@ func (h *TemplateHandler) CalendarEventUpdate(w http.ResponseWriter, r *http.Req
 		if parentID == 0 {
 			parentID = id
 		}
-		parent, err := h.eventStore.GetByID(parentID)
+		parent, err := h.eventStore.GetByID(parentID, householdID)
 		if err != nil || parent == nil {
 			http.Error(w, "parent event not found", http.StatusNotFound)
 			return
@@ -602,7 +671,12 @@ func (h *TemplateHandler) CalendarEventUpdate(w http.ResponseWriter, r *http.Req
 				origStart = time.Date(od.Year(), od.Month(), od.Day(), parent.StartTime.Hour(), parent.StartTime.Minute(), 0, 0, time.UTC)
 			}
 		}
-		if _, err := h.eventStore.CreateException(parentID, origStart, title, description, startTime, endTime, allDay, familyMemberID, location, false); err != nil {
+		exc := edited
+		exc.RecurrenceRule = ""
+		exc.RecurrenceParentID = &parentID
+		exc.OriginalStartTime = &origStart
+		_, err = h.eventStore.CreateWithAttendees(householdID, exc)
+		if err != nil {
 			h.logger.Error("create exception", "error", err)
 			http.Error(w, "failed to create exception", http.StatusInternalServerError)
 			return
@@ -614,10 +688,11 @@ func (h *TemplateHandler) CalendarEventUpdate(w http.ResponseWriter, r *http.Req
 		if parentID == 0 {
 			parentID = id
 		}
-		if err := h.eventStore.DeleteExceptions(parentID); err != nil {
+		if err := h.eventStore.DeleteExceptions(parentID, householdID); err != nil {
 			h.logger.Error("delete exceptions", "error", err)
 		}
-		if _, err := h.eventStore.UpdateWithRecurrence(parentID, title, description, startTime, endTime, allDay, familyMemberID, location, recurrenceRule); err != nil {
+		_, err := h.eventStore.UpdateWithAttendees(parentID, householdID, edited)
+		if err != nil {
 			h.logger.Error("update event", "error", err)
 			http.Error(w, "failed to update event", http.StatusInternalServerError)
 			return
@@ -625,7 +700,8 @@ func (h *TemplateHandler) CalendarEventUpdate(w http.ResponseWriter, r *http.Req
 
 	default:
 		// Non-recurring or simple update
-		if _, err := h.eventStore.UpdateWithRecurrence(id, title, description, startTime, endTime, allDay, familyMemberID, location, recurrenceRule); err != nil {
+		_, err := h.eventStore.UpdateWithAttendees(id, householdID, edited)
+		if err != nil {
 			h.logger.Error("update event", "error", err)
 			http.Error(w, "failed to update event", http.StatusInternalServerError)
 			return
@@ -635,22 +711,23 @@ func (h *TemplateHandler) CalendarEventUpdate(w http.ResponseWriter, r *http.Req
 	// Update reminder for the target event
 	if rmStr := r.FormValue("reminder_minutes"); rmStr != "" {
 		if rm, err := strconv.Atoi(rmStr); err == nil {
-			if err := h.eventStore.SetReminderMinutes(id, &rm); err != nil {
+			if err := h.eventStore.SetReminderMinutes(id, householdID, &rm); err != nil {
 				h.logger.Error("set reminder", "error", err)
 			}
 		}
 	} else {
 		// Clear reminder if empty
-		if err := h.eventStore.SetReminderMinutes(id, nil); err != nil {
+		if err := h.eventStore.SetReminderMinutes(id, householdID, nil); err != nil {
 			h.logger.Error("clear reminder", "error", err)
 		}
 	}
 
-	h.broadcast(websocket.NewMessage("calendar_event", "updated", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "updated", id, nil))
+	h.calSync.Notify(householdID)
 
 	w.Header().Set("HX-Trigger", "closeEventModal")
 
-	data, err := h.buildDayViewData(date)
+	data, err := h.buildDayViewData(householdID, date, 0)
 	if err != nil {
 		http.Error(w, "failed to load events", http.StatusInternalServerError)
 		return
@@ -661,13 +738,14 @@ func (h *TemplateHandler) CalendarEventUpdate(w http.ResponseWriter, r *http.Req
 
 // CalendarEventDeleteForm handles DELETE from the event detail modal.
 func (h *TemplateHandler) CalendarEventDeleteForm(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	event, err := h.eventStore.GetByID(id)
+	event, err := h.eventStore.GetByID(id, householdID)
 	if err != nil {
 		http.Error(w, "failed to get event", http.StatusInternalServerError)
 		return
@@ -676,6 +754,10 @@ func (h *TemplateHandler) CalendarEventDeleteForm(w http.ResponseWriter, r *http
 		http.Error(w, "event not found", http.StatusNotFound)
 		return
 	}
+	if event.Imported() {
+		h.renderToast(w, "error", "Imported events are read-only")
+		return
+	}
 
 	date := event.StartTime
 
@@ -690,7 +772,7 @@ func (h *TemplateHandler) CalendarEventDeleteForm(w http.ResponseWriter, r *http
 		if parentID == 0 {
 			parentID = id
 		}
-		parent, err := h.eventStore.GetByID(parentID)
+		parent, err := h.eventStore.GetByID(parentID, householdID)
 		if err != nil || parent == nil {
 			http.Error(w, "parent event not found", http.StatusNotFound)
 			return
@@ -702,7 +784,7 @@ func (h *TemplateHandler) CalendarEventDeleteForm(w http.ResponseWriter, r *http
 				date = od
 			}
 		}
-		if _, err := h.eventStore.CreateException(parentID, origStart, parent.Title, parent.Description, origStart, origStart.Add(parent.EndTime.Sub(parent.StartTime)), parent.AllDay, parent.FamilyMemberID, parent.Location, true); err != nil {
+		if _, err := h.eventStore.CreateException(householdID, parentID, origStart, parent.Title, parent.Description, origStart, origStart.Add(parent.EndTime.Sub(parent.StartTime)), parent.AllDay, parent.FamilyMemberID, parent.Location, true); err != nil {
 			h.logger.Error("create cancelled exception", "error", err)
 			http.Error(w, "failed to delete occurrence", http.StatusInternalServerError)
 			return
@@ -714,24 +796,25 @@ func (h *TemplateHandler) CalendarEventDeleteForm(w http.ResponseWriter, r *http
 		if parentID == 0 {
 			parentID = id
 		}
-		if err := h.eventStore.Delete(parentID); err != nil {
+		if err := h.eventStore.Delete(parentID, householdID); err != nil {
 			http.Error(w, "failed to delete event series", http.StatusInternalServerError)
 			return
 		}
 
 	default:
 		// Non-recurring delete
-		if err := h.eventStore.Delete(id); err != nil {
+		if err := h.eventStore.Delete(id, householdID); err != nil {
 			http.Error(w, "failed to delete event", http.StatusInternalServerError)
 			return
 		}
 	}
 
-	h.broadcast(websocket.NewMessage("calendar_event", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "deleted", id, nil))
+	h.calSync.Notify(householdID)
 
 	w.Header().Set("HX-Trigger", "closeEventModal")
 
-	data, err := h.buildDayViewData(date)
+	data, err := h.buildDayViewData(householdID, date, 0)
 	if err != nil {
 		http.Error(w, "failed to load events", http.StatusInternalServerError)
 		return
@@ -780,18 +863,24 @@ func (h *TemplateHandler) RecurrenceDeleteChoice(w http.ResponseWriter, r *http.
 
 // ChoresPage renders the full chores page inside the dashboard layout.
 func (h *TemplateHandler) ChoresPage(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	data, err := h.buildDashboardData(r, "chores")
 	if err != nil {
 		http.Error(w, "failed to load data", http.StatusInternalServerError)
 		return
 	}
 
-	choreData, err := h.buildChoreListData("all", 0)
+	choreData, err := h.buildChoreListData(householdID, "all", 0)
 	if err != nil {
 		h.logger.Error("build chore list", "error", err)
 		http.Error(w, "template error", http.StatusInternalServerError)
 		return
 	}
+	if choreData["MemberStats"], err = h.buildChoreStatsData(householdID); err != nil {
+		h.logger.Error("build chore stats", "error", err)
+		http.Error(w, "template error", http.StatusInternalServerError)
+		return
+	}
 
 	content, err := h.renderSection("chores-content", choreData)
 	if err != nil {
@@ -806,18 +895,25 @@ func (h *TemplateHandler) ChoresPage(w http.ResponseWriter, r *http.Request) {
 
 // ChoresPartial renders the chores section for HTMX swap.
 func (h *TemplateHandler) ChoresPartial(w http.ResponseWriter, r *http.Request) {
-	choreData, err := h.buildChoreListData("all", 0)
+	householdID := auth.HouseholdID(r.Context())
+	choreData, err := h.buildChoreListData(householdID, "all", 0)
 	if err != nil {
 		h.logger.Error("build chore list", "error", err)
 		http.Error(w, "failed to load chores", http.StatusInternalServerError)
 		return
 	}
+	if choreData["MemberStats"], err = h.buildChoreStatsData(householdID); err != nil {
+		h.logger.Error("build chore stats", "error", err)
+		http.Error(w, "failed to load chores", http.StatusInternalServerError)
+		return
+	}
 	h.renderPartial(w, "chores-content", choreData)
 }
 
 // ChoreListByPerson renders the by-person chore list partial.
 func (h *TemplateHandler) ChoreListByPerson(w http.ResponseWriter, r *http.Request) {
-	choreData, err := h.buildChoreListData("by-person", 0)
+	householdID := auth.HouseholdID(r.Context())
+	choreData, err := h.buildChoreListData(householdID, "by-person", 0)
 	if err != nil {
 		http.Error(w, "failed to load chores", http.StatusInternalServerError)
 		return
@@ -827,7 +923,8 @@ func (h *TemplateHandler) ChoreListByPerson(w http.ResponseWriter, r *http.Reque
 
 // ChoreListByArea renders the by-area chore list partial.
 func (h *TemplateHandler) ChoreListByArea(w http.ResponseWriter, r *http.Request) {
-	choreData, err := h.buildChoreListData("by-area", 0)
+	householdID := auth.HouseholdID(r.Context())
+	choreData, err := h.buildChoreListData(householdID, "by-area", 0)
 	if err != nil {
 		http.Error(w, "failed to load chores", http.StatusInternalServerError)
 		return
@@ -837,7 +934,8 @@ func (h *TemplateHandler) ChoreListByArea(w http.ResponseWriter, r *http.Request
 
 // ChoreListAll renders the all chores list partial.
 func (h *TemplateHandler) ChoreListAll(w http.ResponseWriter, r *http.Request) {
-	choreData, err := h.buildChoreListData("all", 0)
+	householdID := auth.HouseholdID(r.Context())
+	choreData, err := h.buildChoreListData(householdID, "all", 0)
 	if err != nil {
 		http.Error(w, "failed to load chores", http.StatusInternalServerError)
 		return
@@ -847,31 +945,34 @@ func (h *TemplateHandler) ChoreListAll(w http.ResponseWriter, r *http.Request) {
 
 // ChoreNewForm renders the chore creation form.
 func (h *TemplateHandler) ChoreNewForm(w http.ResponseWriter, r *http.Request) {
-	members, _ := h.store.List()
-	areas, _ := h.choreStore.ListAreas()
+	householdID := auth.HouseholdID(r.Context())
+	members, _ := h.store.List(householdID)
+	areas, _ := h.choreStore.ListAreas(householdID)
 
 	h.renderPartial(w, "chore-form", map[string]any{
-		"Members": members,
-		"Areas":   areas,
+		"Members":         members,
+		"Areas":           areas,
+		"RotationOptions": rotationOptions(members, nil),
 	})
 }
 
 // ChoreEditForm renders the chore edit form.
 func (h *TemplateHandler) ChoreEditForm(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	c, err := h.choreStore.GetByID(id)
+	c, err := h.choreStore.GetByID(id, householdID)
 	if err != nil || c == nil {
 		http.Error(w, "chore not found", http.StatusNotFound)
 		return
 	}
 
-	members, _ := h.store.List()
-	areas, _ := h.choreStore.ListAreas()
+	members, _ := h.store.List(householdID)
+	areas, _ := h.choreStore.ListAreas(householdID)
 
 	var areaID, assignedTo int64
 	if c.AreaID != nil {
@@ -880,22 +981,129 @@ func (h *TemplateHandler) ChoreEditForm(w http.ResponseWriter, r *http.Request)
 	if c.AssignedTo != nil {
 		assignedTo = *c.AssignedTo
 	}
+	var dueAt string
+	if c.DueAt != nil {
+		dueAt = c.DueAt.In(h.location(householdID)).Format(dueAtLayout)
+	}
 
 	h.renderPartial(w, "chore-edit-form", map[string]any{
-		"ID":             c.ID,
-		"Title":          c.Title,
-		"Description":    c.Description,
-		"AreaID":         areaID,
-		"Points":         c.Points,
-		"RecurrenceRule": c.RecurrenceRule,
-		"AssignedTo":     assignedTo,
-		"Members":        members,
-		"Areas":          areas,
+		"ID":               c.ID,
+		"Title":            c.Title,
+		"Description":      c.Description,
+		"AreaID":           areaID,
+		"Points":           c.Points,
+		"RecurrenceRule":   c.RecurrenceRule,
+		"AssignedTo":       assignedTo,
+		"DueAt":            dueAt,
+		"DueTime":          c.DueTime,
+		"RequiresApproval": c.RequiresApproval,
+		"MinAge":           c.MinAge,
+		"Members":          members,
+		"Areas":            areas,
+		"RotationCadence":  string(c.RotationCadence),
+		"RotationOptions":  rotationOptions(members, c.RotationMembers),
 	})
 }
 
+// rotationOption is a member checkbox in the chore form's rotation picker.
+type rotationOption struct {
+	model.FamilyMember
+	Checked bool
+}
+
+// rotationOptions lists the rotation members in turn order, followed by the
+// rest of the family.
+func rotationOptions(members []model.FamilyMember, rotation []int64) []rotationOption {
+	byID := make(map[int64]model.FamilyMember)
+	for _, m := range members {
+		byID[m.ID] = m
+	}
+	var opts []rotationOption
+	inRotation := make(map[int64]bool)
+	for _, id := range rotation {
+		if m, ok := byID[id]; ok {
+			opts = append(opts, rotationOption{FamilyMember: m, Checked: true})
+			inRotation[id] = true
+		}
+	}
+	for _, m := range members {
+		if !inRotation[m.ID] {
+			opts = append(opts, rotationOption{FamilyMember: m})
+		}
+	}
+	return opts
+}
+
+// formRotation reads the rotation cadence and members from a chore form.
+// Members are kept in the order the form lists them.
+func formRotation(r *http.Request) (model.RotationCadence, []int64) {
+	cadence := model.RotationCadence(r.FormValue("rotation_cadence"))
+	if !cadence.Valid() {
+		cadence = model.RotationNone
+	}
+	var members []int64
+	seen := make(map[int64]bool)
+	for _, v := range r.Form["rotation_members"] {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		members = append(members, id)
+	}
+	return cadence, members
+}
+
+// dueAtLayout is the format of a datetime-local input.
+const dueAtLayout = "2006-01-02T15:04"
+
+// formDeadline reads when a chore is due by from a chore form: a date and
+// time on the household's clock for one-off chores, and a time of day for
+// recurring ones. Whichever does not apply is ignored.
+func formDeadline(r *http.Request, recurrenceRule string, loc *time.Location) (*time.Time, string) {
+	if recurrenceRule != "" {
+		dueTime := r.FormValue("due_time")
+		if !timeFormatRegexp.MatchString(dueTime) {
+			dueTime = ""
+		}
+		return nil, dueTime
+	}
+	dueAt, err := time.ParseInLocation(dueAtLayout, r.FormValue("due_at"), loc)
+	if err != nil {
+		return nil, ""
+	}
+	return &dueAt, ""
+}
+
+// formRequiresApproval reads the "needs approval" toggle from a chore form.
+func formRequiresApproval(r *http.Request) bool {
+	v := r.FormValue("requires_approval")
+	return v == "on" || v == "true"
+}
+
+// formMinAge reads the minimum age from a chore form and checks the
+// assignee is old enough. It returns a message for the user if not.
+func (h *TemplateHandler) formMinAge(r *http.Request, householdID int64, assignedTo *int64) (int, string) {
+	minAge, _ := strconv.Atoi(r.FormValue("min_age"))
+	if minAge < 0 || minAge > maxMinAge {
+		return 0, fmt.Sprintf("Minimum age must be between 0 and %d", maxMinAge)
+	}
+	if assignedTo == nil || minAge == 0 {
+		return minAge, ""
+	}
+	member, err := h.store.GetByID(*assignedTo, householdID)
+	if err != nil || member == nil {
+		return minAge, ""
+	}
+	if !member.OldEnough(minAge, time.Now().In(h.location(householdID))) {
+		return minAge, member.Name + " is too young for this chore"
+	}
+	return minAge, ""
+}
+
 // ChoreCreate handles POST form submission to create a chore.
 func (h *TemplateHandler) ChoreCreate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -927,19 +1135,51 @@ func (h *TemplateHandler) ChoreCreate(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	newChore, err := h.choreStore.Create(title, description, areaID, points, recurrenceRule, assignedTo)
+	minAge, msg := h.formMinAge(r, householdID, assignedTo)
+	if msg != "" {
+		h.renderToast(w, "error", msg)
+		return
+	}
+
+	newChore, err := h.choreStore.Create(householdID, title, description, areaID, points, recurrenceRule, assignedTo)
 	if err != nil {
 		h.logger.Error("create chore", "error", err)
 		http.Error(w, "failed to create chore", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("chore", "created", newChore.ID, nil))
+	cadence, rotation := formRotation(r)
+	if err := h.choreStore.SetRotation(newChore.ID, householdID, rotation, cadence); err != nil {
+		h.logger.Error("set chore rotation", "error", err)
+		http.Error(w, "failed to create chore", http.StatusInternalServerError)
+		return
+	}
+
+	dueAt, dueTime := formDeadline(r, recurrenceRule, h.location(householdID))
+	if err := h.choreStore.SetDeadline(newChore.ID, householdID, dueAt, dueTime); err != nil {
+		h.logger.Error("set chore deadline", "error", err)
+		http.Error(w, "failed to create chore", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.choreStore.SetRequiresApproval(newChore.ID, householdID, formRequiresApproval(r)); err != nil {
+		h.logger.Error("set chore requires approval", "error", err)
+		http.Error(w, "failed to create chore", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.choreStore.SetMinAge(newChore.ID, householdID, minAge); err != nil {
+		h.logger.Error("set chore min age", "error", err)
+		http.Error(w, "failed to create chore", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "created", newChore.ID, nil))
 
 	w.Header().Set("HX-Trigger", "closeChoreModal")
 	h.renderToast(w, "success", "Chore created")
 
-	choreData, err := h.buildChoreListData("all", 0)
+	choreData, err := h.buildChoreListData(householdID, "all", 0)
 	if err != nil {
 		http.Error(w, "failed to load chores", http.StatusInternalServerError)
 		return
@@ -949,12 +1189,18 @@ func (h *TemplateHandler) ChoreCreate(w http.ResponseWriter, r *http.Request) {
 
 // ChoreUpdate handles PUT form submission to update a chore.
 func (h *TemplateHandler) ChoreUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
+	if existing, err := h.choreStore.GetByID(id, householdID); err != nil || existing == nil {
+		http.Error(w, "chore not found", http.StatusNotFound)
+		return
+	}
+
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -986,18 +1232,50 @@ func (h *TemplateHandler) ChoreUpdate(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if _, err := h.choreStore.Update(id, title, description, areaID, points, recurrenceRule, assignedTo); err != nil {
+	minAge, msg := h.formMinAge(r, householdID, assignedTo)
+	if msg != "" {
+		h.renderToast(w, "error", msg)
+		return
+	}
+
+	if _, err := h.choreStore.Update(id, householdID, title, description, areaID, points, recurrenceRule, assignedTo); err != nil {
 		h.logger.Error("update chore", "error", err)
 		http.Error(w, "failed to update chore", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("chore", "updated", id, nil))
+	cadence, rotation := formRotation(r)
+	if err := h.choreStore.SetRotation(id, householdID, rotation, cadence); err != nil {
+		h.logger.Error("set chore rotation", "error", err)
+		http.Error(w, "failed to update chore", http.StatusInternalServerError)
+		return
+	}
+
+	dueAt, dueTime := formDeadline(r, recurrenceRule, h.location(householdID))
+	if err := h.choreStore.SetDeadline(id, householdID, dueAt, dueTime); err != nil {
+		h.logger.Error("set chore deadline", "error", err)
+		http.Error(w, "failed to update chore", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.choreStore.SetRequiresApproval(id, householdID, formRequiresApproval(r)); err != nil {
+		h.logger.Error("set chore requires approval", "error", err)
+		http.Error(w, "failed to update chore", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.choreStore.SetMinAge(id, householdID, minAge); err != nil {
+		h.logger.Error("set chore min age", "error", err)
+		http.Error(w, "failed to update chore", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "updated", id, nil))
 
 	w.Header().Set("HX-Trigger", "closeChoreModal")
 	h.renderToast(w, "success", "Chore updated")
 
-	choreData, err := h.buildChoreListData("all", 0)
+	choreData, err := h.buildChoreListData(householdID, "all", 0)
 	if err != nil {
 		http.Error(w, "failed to load chores", http.StatusInternalServerError)
 		return
@@ -1007,23 +1285,29 @@ func (h *TemplateHandler) ChoreUpdate(w http.ResponseWriter, r *http.Request) {
 
 // ChoreDelete handles DELETE for a chore.
 func (h *TemplateHandler) ChoreDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.choreStore.Delete(id); err != nil {
+	if existing, err := h.choreStore.GetByID(id, householdID); err != nil || existing == nil {
+		http.Error(w, "chore not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.choreStore.Delete(id, householdID); err != nil {
 		http.Error(w, "failed to delete chore", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("chore", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("chore", "deleted", id, nil))
 
 	w.Header().Set("HX-Trigger", "closeChoreModal")
 	h.renderToast(w, "success", "Chore deleted")
 
-	choreData, err := h.buildChoreListData("all", 0)
+	choreData, err := h.buildChoreListData(householdID, "all", 0)
 	if err != nil {
 		http.Error(w, "failed to load chores", http.StatusInternalServerError)
 		return
@@ -1033,6 +1317,7 @@ func (h *TemplateHandler) ChoreDelete(w http.ResponseWriter, r *http.Request) {
 
 // ChoreComplete handles POST to complete a chore via HTMX.
 func (h *TemplateHandler) ChoreComplete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
@@ -1046,27 +1331,41 @@ func (h *TemplateHandler) ChoreComplete(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Look up chore to get points
-	choreObj, err := h.choreStore.GetByID(id)
+	choreObj, err := h.choreStore.GetByID(id, householdID)
 	if err != nil || choreObj == nil {
 		http.Error(w, "chore not found", http.StatusNotFound)
 		return
 	}
 
-	if _, err := h.choreStore.CreateCompletion(id, completedBy, choreObj.Points); err != nil {
+	points, err := earnedPoints(h.store, *choreObj, householdID, completedBy, time.Now().In(h.location(householdID)))
+	if errors.Is(err, errMemberNotFound) {
+		http.Error(w, "family member not found", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to get family member", http.StatusInternalServerError)
+		return
+	}
+
+	completion, err := h.choreStore.CreateCompletion(id, householdID, completedBy, points)
+	if err != nil {
 		h.logger.Error("complete chore", "error", err)
 		http.Error(w, "failed to complete chore", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("chore", "completed", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("chore", "completed", id, nil))
 
 	toastMsg := "Chore completed!"
-	if choreObj.Points > 0 {
-		toastMsg = fmt.Sprintf("Chore completed! +%d points", choreObj.Points)
+	if completion != nil && completion.Status == model.CompletionPending {
+		requestApproval(h.store, h.pushScheduler, householdID, *choreObj, completion)
+		toastMsg = "Sent for approval"
+	} else if completion != nil && completion.PointsEarned > 0 {
+		toastMsg = fmt.Sprintf("Chore completed! +%d points", completion.PointsEarned)
 	}
 	h.renderToast(w, "success", toastMsg)
 
-	choreData, err := h.buildChoreListData("all", 0)
+	choreData, err := h.buildChoreListData(householdID, "all", 0)
 	if err != nil {
 		http.Error(w, "failed to load chores", http.StatusInternalServerError)
 		return
@@ -1076,30 +1375,158 @@ func (h *TemplateHandler) ChoreComplete(w http.ResponseWriter, r *http.Request)
 
 // ChoreUndoComplete handles DELETE to undo a chore completion.
 func (h *TemplateHandler) ChoreUndoComplete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
+	if existing, err := h.choreStore.GetByID(id, householdID); err != nil || existing == nil {
+		http.Error(w, "chore not found", http.StatusNotFound)
+		return
+	}
+
 	// Undo by removing the last completion for this chore
-	last, err := h.choreStore.LastCompletionForChore(id)
+	last, err := h.choreStore.LastCompletionForChore(id, householdID)
 	if err != nil {
 		http.Error(w, "failed to get completion", http.StatusInternalServerError)
 		return
 	}
 	if last != nil {
-		if err := h.choreStore.DeleteCompletion(last.ID); err != nil {
+		if err := h.choreStore.DeleteCompletion(last.ID, householdID); err != nil {
 			http.Error(w, "failed to undo completion", http.StatusInternalServerError)
 			return
 		}
 	}
 
-	h.broadcast(websocket.NewMessage("chore", "completion_undone", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("chore", "completion_undone", id, nil))
 
 	h.renderToast(w, "success", "Completion undone")
 
-	choreData, err := h.buildChoreListData("all", 0)
+	choreData, err := h.buildChoreListData(householdID, "all", 0)
+	if err != nil {
+		http.Error(w, "failed to load chores", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "chore-list-all", choreData)
+}
+
+// ChoreSkipTurn passes a rotating chore's current turn to the next member.
+func (h *TemplateHandler) ChoreSkipTurn(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	c, err := h.choreStore.GetByID(id, householdID)
+	if err != nil || c == nil {
+		http.Error(w, "chore not found", http.StatusNotFound)
+		return
+	}
+
+	today := time.Now().In(h.location(householdID))
+	if _, err := skipTurn(h.choreStore, *c, householdID, today); err != nil {
+		h.renderChoreError(w, "skip turn", err)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "updated", id, nil))
+	h.renderToast(w, "success", "Turn skipped")
+
+	choreData, err := h.buildChoreListData(householdID, "all", 0)
+	if err != nil {
+		http.Error(w, "failed to load chores", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "chore-list-all", choreData)
+}
+
+// ChoreSwapTurn hands a rotating chore's current turn to another member.
+func (h *TemplateHandler) ChoreSwapTurn(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+	memberID, err := strconv.ParseInt(r.FormValue("member_id"), 10, 64)
+	if err != nil {
+		h.renderToast(w, "error", "Choose who to swap with")
+		return
+	}
+
+	c, err := h.choreStore.GetByID(id, householdID)
+	if err != nil || c == nil {
+		http.Error(w, "chore not found", http.StatusNotFound)
+		return
+	}
+
+	today := time.Now().In(h.location(householdID))
+	if _, err := swapTurn(h.choreStore, *c, householdID, today, memberID); err != nil {
+		h.renderChoreError(w, "swap turn", err)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "updated", id, nil))
+	h.renderToast(w, "success", "Turn swapped")
+
+	choreData, err := h.buildChoreListData(householdID, "all", 0)
+	if err != nil {
+		http.Error(w, "failed to load chores", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "chore-list-all", choreData)
+}
+
+// ChoreExcuse excuses a chore's current due date, for sick days and the
+// like, so it is not counted as missed.
+func (h *TemplateHandler) ChoreExcuse(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	c, err := h.choreStore.GetByID(id, householdID)
+	if err != nil || c == nil {
+		http.Error(w, "chore not found", http.StatusNotFound)
+		return
+	}
+
+	today := time.Now().In(h.location(householdID))
+	last, err := h.choreStore.LastCompletionForChore(id, householdID)
+	if err != nil {
+		http.Error(w, "failed to get completion", http.StatusInternalServerError)
+		return
+	}
+	var lastTime *time.Time
+	if last != nil {
+		lastTime = &last.CompletedAt
+	}
+	_, dueDate := chore.ComputeStatus(*c, lastTime, today)
+	if dueDate == nil {
+		h.renderToast(w, "error", "Nothing is due to excuse")
+		return
+	}
+
+	if _, err := letOffOccurrence(h.choreStore, *c, householdID, today, chore.DateKey(*dueDate), model.OccurrenceExcused, r.FormValue("note")); err != nil {
+		h.renderChoreError(w, "excuse chore", err)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("chore", "updated", id, nil))
+	h.renderToast(w, "success", "Chore excused")
+
+	choreData, err := h.buildChoreListData(householdID, "all", 0)
 	if err != nil {
 		http.Error(w, "failed to load chores", http.StatusInternalServerError)
 		return
@@ -1107,15 +1534,26 @@ func (h *TemplateHandler) ChoreUndoComplete(w http.ResponseWriter, r *http.Reque
 	h.renderPartial(w, "chore-list-all", choreData)
 }
 
+func (h *TemplateHandler) renderChoreError(w http.ResponseWriter, action string, err error) {
+	var choreErr choreError
+	if errors.As(err, &choreErr) {
+		h.renderToast(w, "error", choreErr.Error())
+		return
+	}
+	h.logger.Error(action, "error", err)
+	http.Error(w, "failed to "+action, http.StatusInternalServerError)
+}
+
 // ChoreManagePage renders the full chore management page.
 func (h *TemplateHandler) ChoreManagePage(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	data, err := h.buildDashboardData(r, "chores")
 	if err != nil {
 		http.Error(w, "failed to load data", http.StatusInternalServerError)
 		return
 	}
 
-	manageData, err := h.buildChoreManageData()
+	manageData, err := h.buildChoreManageData(householdID)
 	if err != nil {
 		h.logger.Error("build chore manage data", "error", err)
 		http.Error(w, "template error", http.StatusInternalServerError)
@@ -1135,7 +1573,8 @@ func (h *TemplateHandler) ChoreManagePage(w http.ResponseWriter, r *http.Request
 
 // ChoreManagePartial renders the manage section partial.
 func (h *TemplateHandler) ChoreManagePartial(w http.ResponseWriter, r *http.Request) {
-	manageData, err := h.buildChoreManageData()
+	householdID := auth.HouseholdID(r.Context())
+	manageData, err := h.buildChoreManageData(householdID)
 	if err != nil {
 		http.Error(w, "failed to load data", http.StatusInternalServerError)
 		return
@@ -1145,7 +1584,8 @@ func (h *TemplateHandler) ChoreManagePartial(w http.ResponseWriter, r *http.Requ
 
 // ChoreAreaList renders the area list partial.
 func (h *TemplateHandler) ChoreAreaList(w http.ResponseWriter, r *http.Request) {
-	areas, err := h.choreStore.ListAreas()
+	householdID := auth.HouseholdID(r.Context())
+	areas, err := h.choreStore.ListAreas(householdID)
 	if err != nil {
 		http.Error(w, "failed to load areas", http.StatusInternalServerError)
 		return
@@ -1155,6 +1595,7 @@ func (h *TemplateHandler) ChoreAreaList(w http.ResponseWriter, r *http.Request)
 
 // ChoreAreaCreate handles POST to create a new area.
 func (h *TemplateHandler) ChoreAreaCreate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -1166,22 +1607,23 @@ func (h *TemplateHandler) ChoreAreaCreate(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	area, err := h.choreStore.CreateArea(name, 0)
+	area, err := h.choreStore.CreateArea(householdID, name, 0)
 	if err != nil {
 		h.logger.Error("create area", "error", err)
 		http.Error(w, "failed to create area", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("chore_area", "created", area.ID, nil))
+	h.broadcast(householdID, websocket.NewMessage("chore_area", "created", area.ID, nil))
 
 	h.renderToast(w, "success", "Area created")
-	areas, _ := h.choreStore.ListAreas()
+	areas, _ := h.choreStore.ListAreas(householdID)
 	h.renderPartial(w, "chore-area-list", map[string]any{"Areas": areas})
 }
 
 // ChoreAreaUpdate handles PUT to rename an area.
 func (h *TemplateHandler) ChoreAreaUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
@@ -1199,41 +1641,47 @@ func (h *TemplateHandler) ChoreAreaUpdate(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	area, err := h.choreStore.GetAreaByID(id)
+	area, err := h.choreStore.GetAreaByID(id, householdID)
 	if err != nil || area == nil {
 		http.Error(w, "area not found", http.StatusNotFound)
 		return
 	}
 
-	if _, err := h.choreStore.UpdateArea(id, name, area.SortOrder); err != nil {
+	if _, err := h.choreStore.UpdateArea(id, householdID, name, area.SortOrder); err != nil {
 		http.Error(w, "failed to update area", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("chore_area", "updated", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("chore_area", "updated", id, nil))
 
 	h.renderToast(w, "success", "Area updated")
-	areas, _ := h.choreStore.ListAreas()
+	areas, _ := h.choreStore.ListAreas(householdID)
 	h.renderPartial(w, "chore-area-list", map[string]any{"Areas": areas})
 }
 
 // ChoreAreaDelete handles DELETE for an area.
 func (h *TemplateHandler) ChoreAreaDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.choreStore.DeleteArea(id); err != nil {
+	if area, err := h.choreStore.GetAreaByID(id, householdID); err != nil || area == nil {
+		http.Error(w, "area not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.choreStore.DeleteArea(id, householdID); err != nil {
 		http.Error(w, "failed to delete area", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("chore_area", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("chore_area", "deleted", id, nil))
 
 	h.renderToast(w, "success", "Area deleted")
-	areas, _ := h.choreStore.ListAreas()
+	areas, _ := h.choreStore.ListAreas(householdID)
 	h.renderPartial(w, "chore-area-list", map[string]any{"Areas": areas})
 }
 
@@ -1247,18 +1695,18 @@ type memberChoreSummary struct {
 	Done        int
 }
 
-func (h *TemplateHandler) buildChoreListData(filter string, filterID int64) (map[string]any, error) {
-	chores, err := h.choreStore.List()
+func (h *TemplateHandler) buildChoreListData(householdID int64, filter string, filterID int64) (map[string]any, error) {
+	chores, err := h.choreStore.List(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list chores: %w", err)
 	}
 
-	members, err := h.store.List()
+	members, err := h.store.List(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list members: %w", err)
 	}
 
-	areas, err := h.choreStore.ListAreas()
+	areas, err := h.choreStore.ListAreas(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list areas: %w", err)
 	}
@@ -1272,22 +1720,44 @@ func (h *TemplateHandler) buildChoreListData(filter string, filterID int64) (map
 		areaMap[a.ID] = a
 	}
 
-	today := time.Now()
-	var choreList []chore.ChoreWithStatus
+	today := time.Now().In(h.location(householdID))
+	_, history, err := chore.LoadHistory(h.choreStore, householdID, today)
+	if err != nil {
+		return nil, fmt.Errorf("chore history: %w", err)
+	}
+	var choreList []chore.ChoreWithStatus
 
 	for _, c := range chores {
-		last, _ := h.choreStore.LastCompletionForChore(c.ID)
+		last, _ := h.choreStore.LastCompletionForChore(c.ID, householdID)
 		var lastTime *time.Time
 		if last != nil {
 			lastTime = &last.CompletedAt
 		}
 		status, dueDate := chore.ComputeStatus(c, lastTime, today)
+		status, missed := chore.ApplyHistory(status, history[c.ID])
 
 		cws := chore.ChoreWithStatus{
 			Chore:          c,
 			Status:         status,
 			DueDate:        dueDate,
+			Deadline:       chore.Deadline(c, dueDate),
 			LastCompletion: lastTime,
+			Missed:         missed,
+		}
+		cws.AwaitingApproval = status == chore.StatusCompleted && last != nil && last.Status == model.CompletionPending
+
+		// Rotating chores are shown against whoever's turn it is.
+		if c.Rotates() {
+			rot, _, err := choreRotation(h.choreStore, c, householdID, chore.RotationDay(dueDate, today))
+			if err != nil {
+				return nil, fmt.Errorf("chore rotation: %w", err)
+			}
+			cws.AssignedTo = rot.MemberID
+			for _, mid := range c.RotationMembers {
+				if m, ok := memberMap[mid]; ok && (rot.MemberID == nil || mid != *rot.MemberID) {
+					cws.SwapOptions = append(cws.SwapOptions, m)
+				}
+			}
 		}
 
 		if c.AreaID != nil {
@@ -1410,18 +1880,18 @@ func (h *TemplateHandler) buildChoreListData(filter string, filterID int64) (map
 	}, nil
 }
 
-func (h *TemplateHandler) buildChoreManageData() (map[string]any, error) {
-	chores, err := h.choreStore.List()
+func (h *TemplateHandler) buildChoreManageData(householdID int64) (map[string]any, error) {
+	chores, err := h.choreStore.List(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list chores: %w", err)
 	}
 
-	areas, err := h.choreStore.ListAreas()
+	areas, err := h.choreStore.ListAreas(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list areas: %w", err)
 	}
 
-	members, _ := h.store.List()
+	members, _ := h.store.List(householdID)
 
 	areaMap := make(map[int64]model.ChoreArea)
 	for _, a := range areas {
@@ -1453,139 +1923,151 @@ func (h *TemplateHandler) buildChoreManageData() (map[string]any, error) {
 		choreDisplays = append(choreDisplays, cd)
 	}
 
+	templates, err := h.buildChoreTemplatesData(householdID)
+	if err != nil {
+		return nil, err
+	}
+
 	return map[string]any{
-		"Chores":  choreDisplays,
-		"Areas":   areas,
-		"Members": members,
+		"Chores":    choreDisplays,
+		"Areas":     areas,
+		"Members":   members,
+		"Packs":     templates["Packs"],
+		"Templates": templates["Templates"],
 	}, nil
 }
 
-func (h *TemplateHandler) buildChoreSummaryData() ([]memberChoreSummary, error) {
-	chores, err := h.choreStore.List()
-	if err != nil {
-		return nil, fmt.Errorf("list chores: %w", err)
+// choreTemplateView is a starter pack or saved template with its chores
+// grouped by area.
+type choreTemplateView struct {
+	ID          int64
+	Key         string
+	Name        string
+	Description string
+	AgeBand     string
+	ShareCode   string
+	Count       int
+	Areas       []chore.AreaChores
+}
+
+// buildChoreTemplatesData returns the starter packs and the household's
+// saved chore templates.
+func (h *TemplateHandler) buildChoreTemplatesData(householdID int64) (map[string]any, error) {
+	var packs []choreTemplateView
+	for _, p := range chore.StarterPacks() {
+		packs = append(packs, choreTemplateView{
+			Key: p.Key, Name: p.Name, Description: p.Description, AgeBand: p.AgeBand,
+			Count: len(p.Chores), Areas: chore.ByArea(p.Chores),
+		})
 	}
 
-	members, err := h.store.List()
+	saved, err := h.choreStore.ListTemplates(householdID)
 	if err != nil {
-		return nil, fmt.Errorf("list members: %w", err)
+		return nil, fmt.Errorf("list chore templates: %w", err)
 	}
-
-	today := time.Now()
-	memberMap := make(map[int64]model.FamilyMember)
-	for _, m := range members {
-		memberMap[m.ID] = m
-	}
-
-	// Count per member
-	type counts struct {
-		total int
-		done  int
+	var templates []choreTemplateView
+	for _, t := range saved {
+		templates = append(templates, choreTemplateView{
+			ID: t.ID, Name: t.Name, Description: t.Description, ShareCode: t.ShareCode,
+			Count: len(t.Chores), Areas: chore.ByArea(t.Chores),
+		})
 	}
-	memberCounts := make(map[int64]*counts)
-
-	for _, c := range chores {
-		if c.AssignedTo == nil {
-			continue
-		}
-		mid := *c.AssignedTo
-		if _, ok := memberCounts[mid]; !ok {
-			memberCounts[mid] = &counts{}
-		}
-		mc := memberCounts[mid]
-		mc.total++
 
-		last, _ := h.choreStore.LastCompletionForChore(c.ID)
-		var lastTime *time.Time
-		if last != nil {
-			lastTime = &last.CompletedAt
-		}
-		status, _ := chore.ComputeStatus(c, lastTime, today)
-		if status == chore.StatusCompleted {
-			mc.done++
-		}
-	}
+	return map[string]any{"Packs": packs, "Templates": templates}, nil
+}
 
-	var summaries []memberChoreSummary
-	for _, m := range members {
-		if mc, ok := memberCounts[m.ID]; ok {
-			summaries = append(summaries, memberChoreSummary{
-				MemberName:  m.Name,
-				MemberEmoji: m.AvatarEmoji,
-				MemberColor: m.Color,
-				Total:       mc.total,
-				Done:        mc.done,
-			})
-		}
+func (h *TemplateHandler) renderChoreTemplates(w http.ResponseWriter, householdID int64) {
+	data, err := h.buildChoreTemplatesData(householdID)
+	if err != nil {
+		h.logger.Error("build chore templates", "error", err)
+		http.Error(w, "failed to load templates", http.StatusInternalServerError)
+		return
 	}
-
-	return summaries, nil
+	h.renderPartial(w, "chore-templates", data)
 }
 
-func statusPriority(s chore.Status) int {
-	switch s {
-	case chore.StatusOverdue:
-		return 0
-	case chore.StatusPending:
-		return 1
-	case chore.StatusCompleted:
-		return 2
-	case chore.StatusNotDue:
-		return 3
-	default:
-		return 4
-	}
+// ChoreTemplateList renders the starter packs and saved templates.
+func (h *TemplateHandler) ChoreTemplateList(w http.ResponseWriter, r *http.Request) {
+	h.renderChoreTemplates(w, auth.HouseholdID(r.Context()))
 }
 
-// GroceryPage renders the full grocery page inside the dashboard layout.
-func (h *TemplateHandler) GroceryPage(w http.ResponseWriter, r *http.Request) {
-	data, err := h.buildDashboardData(r, "grocery")
+// importTemplateChores adds template chores to the household and renders
+// the manage page again, since both chores and areas may have changed.
+func (h *TemplateHandler) importTemplateChores(w http.ResponseWriter, householdID int64, name string, chores []model.TemplateChore) {
+	n, err := h.choreStore.ImportTemplate(householdID, chores)
 	if err != nil {
-		http.Error(w, "failed to load data", http.StatusInternalServerError)
+		h.logger.Error("import chores", "error", err)
+		http.Error(w, "failed to import chores", http.StatusInternalServerError)
 		return
 	}
 
-	groceryData, err := h.buildGroceryListData()
-	if err != nil {
-		h.logger.Error("build grocery list", "error", err)
-		http.Error(w, "template error", http.StatusInternalServerError)
-		return
+	switch n {
+	case 0:
+		h.renderToast(w, "success", "You already have every chore in "+name)
+	case 1:
+		h.renderToast(w, "success", "Added 1 chore from "+name)
+	default:
+		h.renderToast(w, "success", fmt.Sprintf("Added %d chores from %s", n, name))
 	}
+	if n > 0 {
+		h.broadcast(householdID, websocket.NewMessage("chore", "imported", 0, nil))
+	}
+	h.renderChoreManage(w, householdID)
+}
 
-	content, err := h.renderSection("grocery-content", groceryData)
+func (h *TemplateHandler) renderChoreManage(w http.ResponseWriter, householdID int64) {
+	manageData, err := h.buildChoreManageData(householdID)
 	if err != nil {
-		h.logger.Error("render grocery content", "error", err)
-		http.Error(w, "template error", http.StatusInternalServerError)
+		h.logger.Error("build chore manage data", "error", err)
+		http.Error(w, "failed to load data", http.StatusInternalServerError)
 		return
 	}
-	data["Content"] = content
-
-	h.render(w, "layout.html", data)
+	h.renderPartial(w, "chore-manage-content", manageData)
 }
 
-// GroceryPartial renders the grocery section for HTMX swap.
-func (h *TemplateHandler) GroceryPartial(w http.ResponseWriter, r *http.Request) {
-	groceryData, err := h.buildGroceryListData()
-	if err != nil {
-		h.logger.Error("build grocery list", "error", err)
-		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
+// ChoreStarterPackImport handles POST to add a starter pack's chores.
+func (h *TemplateHandler) ChoreStarterPackImport(w http.ResponseWriter, r *http.Request) {
+	pack, ok := chore.FindStarterPack(r.PathValue("key"))
+	if !ok {
+		http.Error(w, "starter pack not found", http.StatusNotFound)
 		return
 	}
-	h.renderPartial(w, "grocery-content", groceryData)
+	h.importTemplateChores(w, auth.HouseholdID(r.Context()), pack.Name, pack.Chores)
 }
 
-// GroceryItemList renders the item list partial.
-func (h *TemplateHandler) GroceryItemList(w http.ResponseWriter, r *http.Request) {
-	groceryData, err := h.buildGroceryListData()
+// ChoreTemplateImport handles POST to add a saved template's chores, by ID
+// or, for another household's template, by share code.
+func (h *TemplateHandler) ChoreTemplateImport(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	var saved *model.ChoreTemplate
+	var err error
+	if idStr := r.PathValue("id"); idStr != "" {
+		id, perr := strconv.ParseInt(idStr, 10, 64)
+		if perr != nil {
+			http.Error(w, "invalid id", http.StatusBadRequest)
+			return
+		}
+		saved, err = h.choreStore.GetTemplate(id, householdID)
+	} else {
+		saved, err = h.choreStore.GetTemplateByShareCode(r.FormValue("share_code"))
+	}
 	if err != nil {
-		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
+		h.logger.Error("get chore template", "error", err)
+		http.Error(w, "failed to get template", http.StatusInternalServerError)
+		return
+	}
+	if saved == nil {
+		h.renderToast(w, "error", "Template not found")
+		h.renderChoreManage(w, householdID)
 		return
 	}
-	h.renderPartial(w, "grocery-item-list", groceryData)
+	h.importTemplateChores(w, householdID, saved.Name, saved.Chores)
 }
 
-// GroceryItemAdd handles POST quick-add form submission.
-func (h *TemplateHandler) GroceryItemAdd(w http.ResponseWriter, r *http.Request) {
+// ChoreTemplateSave handles POST to save the household's recurring chores
+// as a template.
+func (h *TemplateHandler) ChoreTemplateSave(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -1593,312 +2075,188 @@ func (h *TemplateHandler) GroceryItemAdd(w http.ResponseWriter, r *http.Request)
 
 	name := strings.TrimSpace(r.FormValue("name"))
 	if name == "" {
-		h.renderToast(w, "error", "Item name is required")
-		return
-	}
-
-	list, err := h.groceryStore.GetDefaultList()
-	if err != nil || list == nil {
-		http.Error(w, "failed to get list", http.StatusInternalServerError)
+		h.renderToast(w, "error", "Template name is required")
+		h.renderChoreTemplates(w, householdID)
 		return
 	}
-
-	activeUserID := h.activeUserFromCookie(r)
-	var addedBy *int64
-	if activeUserID > 0 {
-		addedBy = &activeUserID
-	}
-
-	cat := grocery.Categorize(name)
-
-	item, err := h.groceryStore.CreateItem(list.ID, name, "", "", "", cat, addedBy)
+	chores, err := h.choreStore.List(householdID)
 	if err != nil {
-		h.logger.Error("create grocery item", "error", err)
-		http.Error(w, "failed to create item", http.StatusInternalServerError)
+		http.Error(w, "failed to list chores", http.StatusInternalServerError)
 		return
 	}
-
-	h.broadcast(websocket.NewMessage("grocery_item", "created", item.ID, map[string]any{"list_id": list.ID}))
-
-	// Fire push notification for grocery addition (exclude the adding user)
-	if h.pushScheduler != nil {
-		userID := auth.UserID(r.Context())
-		householdID := auth.HouseholdID(r.Context())
-		go h.pushScheduler.SendGroceryNotification(householdID, userID, name)
-	}
-
-	groceryData, err := h.buildGroceryListData()
-	if err != nil {
-		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
+	if !hasRecurringChores(chores) {
+		h.renderToast(w, "error", "Add some recurring chores first")
+		h.renderChoreTemplates(w, householdID)
 		return
 	}
-	h.renderPartial(w, "grocery-item-list", groceryData)
-}
 
-// GroceryItemToggle handles POST to toggle checked state.
-func (h *TemplateHandler) GroceryItemToggle(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	saved, err := h.choreStore.SaveTemplate(householdID, name, strings.TrimSpace(r.FormValue("description")))
 	if err != nil {
-		http.Error(w, "invalid id", http.StatusBadRequest)
-		return
-	}
-
-	activeUserID := h.activeUserFromCookie(r)
-	var checkedBy *int64
-	if activeUserID > 0 {
-		checkedBy = &activeUserID
-	}
-
-	if _, err := h.groceryStore.ToggleChecked(id, checkedBy); err != nil {
-		h.logger.Error("toggle grocery item", "error", err)
-		http.Error(w, "failed to toggle item", http.StatusInternalServerError)
+		h.logger.Error("save chore template", "error", err)
+		http.Error(w, "failed to save template", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("grocery_item", "checked", id, nil))
-
-	groceryData, err := h.buildGroceryListData()
-	if err != nil {
-		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
-		return
-	}
-	h.renderPartial(w, "grocery-item-list", groceryData)
+	h.renderToast(w, "success", "Template saved. Share code: "+saved.ShareCode)
+	h.renderChoreTemplates(w, householdID)
 }
 
-// GroceryItemDelete handles DELETE for a grocery item.
-func (h *TemplateHandler) GroceryItemDelete(w http.ResponseWriter, r *http.Request) {
+// ChoreTemplateDelete handles DELETE of a saved template.
+func (h *TemplateHandler) ChoreTemplateDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.groceryStore.DeleteItem(id); err != nil {
-		http.Error(w, "failed to delete item", http.StatusInternalServerError)
+	if err := h.choreStore.DeleteTemplate(id, householdID); err != nil {
+		h.logger.Error("delete chore template", "error", err)
+		http.Error(w, "failed to delete template", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("grocery_item", "deleted", id, nil))
-
-	groceryData, err := h.buildGroceryListData()
-	if err != nil {
-		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
-		return
-	}
-	h.renderPartial(w, "grocery-item-list", groceryData)
+	h.renderChoreTemplates(w, householdID)
 }
 
-// GroceryClearChecked handles POST to clear all checked items.
-func (h *TemplateHandler) GroceryClearChecked(w http.ResponseWriter, r *http.Request) {
-	list, err := h.groceryStore.GetDefaultList()
-	if err != nil || list == nil {
-		http.Error(w, "failed to get list", http.StatusInternalServerError)
-		return
-	}
-
-	count, err := h.groceryStore.ClearChecked(list.ID)
-	if err != nil {
-		http.Error(w, "failed to clear checked", http.StatusInternalServerError)
-		return
-	}
-
-	h.broadcast(websocket.NewMessage("grocery_item", "cleared", 0, map[string]any{"list_id": list.ID}))
-
-	h.renderToast(w, "success", fmt.Sprintf("Cleared %d item(s)", count))
-
-	groceryData, err := h.buildGroceryListData()
+func (h *TemplateHandler) buildChoreSummaryData(householdID int64) ([]memberChoreSummary, error) {
+	chores, err := h.choreStore.List(householdID)
 	if err != nil {
-		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
-		return
+		return nil, fmt.Errorf("list chores: %w", err)
 	}
-	h.renderPartial(w, "grocery-item-list", groceryData)
-}
 
-// GroceryItemEditForm renders the edit form in the modal.
-func (h *TemplateHandler) GroceryItemEditForm(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	members, err := h.store.List(householdID)
 	if err != nil {
-		http.Error(w, "invalid id", http.StatusBadRequest)
-		return
+		return nil, fmt.Errorf("list members: %w", err)
 	}
 
-	item, err := h.groceryStore.GetItemByID(id)
-	if err != nil || item == nil {
-		http.Error(w, "item not found", http.StatusNotFound)
-		return
+	today := time.Now().In(h.location(householdID))
+	memberMap := make(map[int64]model.FamilyMember)
+	for _, m := range members {
+		memberMap[m.ID] = m
 	}
 
-	categories, _ := h.groceryStore.ListCategories()
-
-	h.renderPartial(w, "grocery-edit-form", map[string]any{
-		"ID":         item.ID,
-		"Name":       item.Name,
-		"Quantity":   item.Quantity,
-		"Unit":       item.Unit,
-		"Notes":      item.Notes,
-		"Category":   item.Category,
-		"Categories": categories,
-	})
-}
-
-// GroceryItemUpdate handles PUT form submission to update a grocery item.
-func (h *TemplateHandler) GroceryItemUpdate(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
-	if err != nil {
-		http.Error(w, "invalid id", http.StatusBadRequest)
-		return
+	// Count per member
+	type counts struct {
+		total int
+		done  int
 	}
+	memberCounts := make(map[int64]*counts)
 
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "invalid form data", http.StatusBadRequest)
-		return
-	}
+	for _, c := range chores {
+		if c.AssignedTo == nil && !c.Rotates() {
+			continue
+		}
 
-	name := strings.TrimSpace(r.FormValue("name"))
-	if name == "" {
-		h.renderToast(w, "error", "Item name is required")
-		return
-	}
+		last, _ := h.choreStore.LastCompletionForChore(c.ID, householdID)
+		var lastTime *time.Time
+		if last != nil {
+			lastTime = &last.CompletedAt
+		}
+		status, dueDate := chore.ComputeStatus(c, lastTime, today)
 
-	quantity := r.FormValue("quantity")
-	unit := r.FormValue("unit")
-	notes := r.FormValue("notes")
-	category := r.FormValue("category")
+		assignedTo := c.AssignedTo
+		if c.Rotates() {
+			rot, _, err := choreRotation(h.choreStore, c, householdID, chore.RotationDay(dueDate, today))
+			if err != nil {
+				return nil, fmt.Errorf("chore rotation: %w", err)
+			}
+			assignedTo = rot.MemberID
+		}
+		if assignedTo == nil {
+			continue
+		}
 
-	if category == "" {
-		category = "Other"
+		mid := *assignedTo
+		if _, ok := memberCounts[mid]; !ok {
+			memberCounts[mid] = &counts{}
+		}
+		mc := memberCounts[mid]
+		mc.total++
+		if status == chore.StatusCompleted {
+			mc.done++
+		}
 	}
 
-	if _, err := h.groceryStore.UpdateItem(id, name, quantity, unit, notes, category); err != nil {
-		h.logger.Error("update grocery item", "error", err)
-		http.Error(w, "failed to update item", http.StatusInternalServerError)
-		return
+	var summaries []memberChoreSummary
+	for _, m := range members {
+		if mc, ok := memberCounts[m.ID]; ok {
+			summaries = append(summaries, memberChoreSummary{
+				MemberName:  m.Name,
+				MemberEmoji: m.AvatarEmoji,
+				MemberColor: m.Color,
+				Total:       mc.total,
+				Done:        mc.done,
+			})
+		}
 	}
 
-	h.broadcast(websocket.NewMessage("grocery_item", "updated", id, nil))
-
-	w.Header().Set("HX-Trigger", "closeGroceryModal")
-	h.renderToast(w, "success", "Item updated")
-
-	groceryData, err := h.buildGroceryListData()
-	if err != nil {
-		http.Error(w, "failed to load grocery data", http.StatusInternalServerError)
-		return
-	}
-	h.renderPartial(w, "grocery-item-list", groceryData)
+	return summaries, nil
 }
 
-// --- Grocery helper methods ---
-
-type categoryGroup struct {
-	CategoryName string
-	Items        []model.GroceryItem
+// memberStatsView is a member's chore stats as shown on the chores page.
+type memberStatsView struct {
+	model.FamilyMember
+	Stats         chore.Stats
+	OnTimePercent int
 }
 
-func (h *TemplateHandler) buildGroceryListData() (map[string]any, error) {
-	list, err := h.groceryStore.GetDefaultList()
-	if err != nil {
-		return nil, fmt.Errorf("get default list: %w", err)
-	}
-	if list == nil {
-		return map[string]any{
-			"List":           nil,
-			"CategoryGroups": nil,
-			"CheckedItems":   nil,
-			"UncheckedCount": 0,
-			"Categories":     nil,
-		}, nil
-	}
-
-	items, err := h.groceryStore.ListItemsByList(list.ID)
+// buildChoreStatsData returns streaks and on-time rates for members who
+// have had recurring chores fall due.
+func (h *TemplateHandler) buildChoreStatsData(householdID int64) ([]memberStatsView, error) {
+	members, err := h.store.List(householdID)
 	if err != nil {
-		return nil, fmt.Errorf("list items: %w", err)
+		return nil, fmt.Errorf("list members: %w", err)
 	}
 
-	categories, err := h.groceryStore.ListCategories()
+	today := time.Now().In(h.location(householdID))
+	chores, history, err := chore.LoadHistory(h.choreStore, householdID, today)
 	if err != nil {
-		return nil, fmt.Errorf("list categories: %w", err)
-	}
-
-	// Build category sort order map
-	catOrder := make(map[string]int)
-	for _, c := range categories {
-		catOrder[c.Name] = c.SortOrder
-	}
-
-	// Separate unchecked and checked
-	var unchecked, checked []model.GroceryItem
-	for _, item := range items {
-		if item.Checked {
-			checked = append(checked, item)
-		} else {
-			unchecked = append(unchecked, item)
-		}
-	}
-
-	// Group unchecked by category
-	groupMap := make(map[string][]model.GroceryItem)
-	for _, item := range unchecked {
-		groupMap[item.Category] = append(groupMap[item.Category], item)
+		return nil, fmt.Errorf("chore history: %w", err)
 	}
 
-	// Sort groups by category sort order
-	var groups []categoryGroup
-	for _, cat := range categories {
-		if items, ok := groupMap[cat.Name]; ok {
-			groups = append(groups, categoryGroup{
-				CategoryName: cat.Name,
-				Items:        items,
-			})
-		}
-	}
-	// Add any categories not in seed data
-	for catName, items := range groupMap {
-		if _, ok := catOrder[catName]; !ok {
-			groups = append(groups, categoryGroup{
-				CategoryName: catName,
-				Items:        items,
-			})
+	var views []memberStatsView
+	for _, m := range members {
+		ms := statsForMember(chores, history, m.ID)
+		if ms.Occurrences == 0 {
+			continue
 		}
+		views = append(views, memberStatsView{
+			FamilyMember:  m,
+			Stats:         ms.Stats,
+			OnTimePercent: int(ms.OnTimeRate*100 + 0.5),
+		})
 	}
-
-	return map[string]any{
-		"List":           list,
-		"CategoryGroups": groups,
-		"CheckedItems":   checked,
-		"UncheckedCount": len(unchecked),
-		"Categories":     categories,
-	}, nil
+	return views, nil
 }
 
-func (h *TemplateHandler) buildGrocerySummaryData() (map[string]any, error) {
-	list, err := h.groceryStore.GetDefaultList()
-	if err != nil {
-		return nil, fmt.Errorf("get default list: %w", err)
-	}
-	if list == nil {
-		return map[string]any{"UncheckedCount": 0}, nil
-	}
-
-	count, err := h.groceryStore.CountUnchecked(list.ID)
-	if err != nil {
-		return nil, fmt.Errorf("count unchecked: %w", err)
+func statusPriority(s chore.Status) int {
+	switch s {
+	case chore.StatusOverdue:
+		return 0
+	case chore.StatusPending:
+		return 1
+	case chore.StatusCompleted, chore.StatusExcused, chore.StatusSkipped:
+		return 2
+	case chore.StatusNotDue:
+		return 3
+	default:
+		return 4
 	}
-
-	return map[string]any{"UncheckedCount": count}, nil
 }
 
 // --- Notes handlers ---
 
 // NotesPage renders the full notes page inside the dashboard layout.
 func (h *TemplateHandler) NotesPage(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	data, err := h.buildDashboardData(r, "notes")
 	if err != nil {
 		http.Error(w, "failed to load data", http.StatusInternalServerError)
 		return
 	}
 
-	noteData, err := h.buildNoteListData()
+	noteData, err := h.buildNoteListData(householdID)
 	if err != nil {
 		h.logger.Error("build note list", "error", err)
 		http.Error(w, "template error", http.StatusInternalServerError)
@@ -1919,32 +2277,35 @@ func (h *TemplateHandler) NotesPage(w http.ResponseWriter, r *http.Request) {
 
 // NotesPartial renders the notes section for HTMX swap.
 func (h *TemplateHandler) NotesPartial(w http.ResponseWriter, r *http.Request) {
-	noteData, err := h.buildNoteListData()
+	householdID := auth.HouseholdID(r.Context())
+	noteData, err := h.buildNoteListData(householdID)
 	if err != nil {
 		h.logger.Error("build note list", "error", err)
 		http.Error(w, "failed to load notes data", http.StatusInternalServerError)
 		return
 	}
-	members, _ := h.store.List()
+	members, _ := h.store.List(householdID)
 	noteData["Members"] = members
 	h.renderPartial(w, "notes-content", noteData)
 }
 
 // NoteList renders the note list partial.
 func (h *TemplateHandler) NoteList(w http.ResponseWriter, r *http.Request) {
-	noteData, err := h.buildNoteListData()
+	householdID := auth.HouseholdID(r.Context())
+	noteData, err := h.buildNoteListData(householdID)
 	if err != nil {
 		http.Error(w, "failed to load notes data", http.StatusInternalServerError)
 		return
 	}
-	members, _ := h.store.List()
+	members, _ := h.store.List(householdID)
 	noteData["Members"] = members
 	h.renderPartial(w, "note-list", noteData)
 }
 
 // NoteNewForm renders the new note form in the modal.
 func (h *TemplateHandler) NoteNewForm(w http.ResponseWriter, r *http.Request) {
-	members, _ := h.store.List()
+	householdID := auth.HouseholdID(r.Context())
+	members, _ := h.store.List(householdID)
 	data := map[string]any{
 		"Members": members,
 	}
@@ -1953,19 +2314,20 @@ func (h *TemplateHandler) NoteNewForm(w http.ResponseWriter, r *http.Request) {
 
 // NoteEditForm renders the edit note form in the modal.
 func (h *TemplateHandler) NoteEditForm(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	note, err := h.noteStore.GetByID(id)
+	note, err := h.noteStore.GetByID(id, householdID)
 	if err != nil || note == nil {
 		http.Error(w, "note not found", http.StatusNotFound)
 		return
 	}
 
-	members, _ := h.store.List()
+	members, _ := h.store.List(householdID)
 	data := map[string]any{
 		"Note":    note,
 		"Members": members,
@@ -1975,6 +2337,7 @@ func (h *TemplateHandler) NoteEditForm(w http.ResponseWriter, r *http.Request) {
 
 // NoteCreate handles POST form submission to create a note.
 func (h *TemplateHandler) NoteCreate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -2010,29 +2373,30 @@ func (h *TemplateHandler) NoteCreate(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	note, err := h.noteStore.Create(title, body, authorID, pinned, priority, expiresAt)
+	note, err := h.noteStore.Create(householdID, title, body, authorID, pinned, priority, expiresAt)
 	if err != nil {
 		h.logger.Error("create note", "error", err)
 		http.Error(w, "failed to create note", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("note", "created", note.ID, nil))
+	h.broadcast(householdID, websocket.NewMessage("note", "created", note.ID, nil))
 
 	w.Header().Set("HX-Trigger", "closeNoteModal")
 
-	noteData, err := h.buildNoteListData()
+	noteData, err := h.buildNoteListData(householdID)
 	if err != nil {
 		http.Error(w, "failed to load notes data", http.StatusInternalServerError)
 		return
 	}
-	members, _ := h.store.List()
+	members, _ := h.store.List(householdID)
 	noteData["Members"] = members
 	h.renderPartial(w, "note-list", noteData)
 }
 
 // NoteUpdate handles PUT form submission to update a note.
 func (h *TemplateHandler) NoteUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
@@ -2058,12 +2422,13 @@ func (h *TemplateHandler) NoteUpdate(w http.ResponseWriter, r *http.Request) {
 
 	pinned := r.FormValue("pinned") == "on" || r.FormValue("pinned") == "true"
 
-	// Preserve existing author or use active user
-	existing, _ := h.noteStore.GetByID(id)
-	var authorID *int64
-	if existing != nil {
-		authorID = existing.AuthorID
+	// Preserve existing author
+	existing, err := h.noteStore.GetByID(id, householdID)
+	if err != nil || existing == nil {
+		http.Error(w, "note not found", http.StatusNotFound)
+		return
 	}
+	authorID := existing.AuthorID
 
 	var expiresAt *time.Time
 	if exp := r.FormValue("expires_at"); exp != "" {
@@ -2074,84 +2439,95 @@ func (h *TemplateHandler) NoteUpdate(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	note, err := h.noteStore.Update(id, title, body, authorID, pinned, priority, expiresAt)
+	note, err := h.noteStore.Update(id, householdID, title, body, authorID, pinned, priority, expiresAt)
 	if err != nil {
 		h.logger.Error("update note", "error", err)
 		http.Error(w, "failed to update note", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("note", "updated", note.ID, nil))
+	h.broadcast(householdID, websocket.NewMessage("note", "updated", note.ID, nil))
 
 	w.Header().Set("HX-Trigger", "closeNoteModal")
 
-	noteData, err := h.buildNoteListData()
+	noteData, err := h.buildNoteListData(householdID)
 	if err != nil {
 		http.Error(w, "failed to load notes data", http.StatusInternalServerError)
 		return
 	}
-	members, _ := h.store.List()
+	members, _ := h.store.List(householdID)
 	noteData["Members"] = members
 	h.renderPartial(w, "note-list", noteData)
 }
 
 // NoteDelete handles DELETE to remove a note.
 func (h *TemplateHandler) NoteDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.noteStore.Delete(id); err != nil {
+	if existing, err := h.noteStore.GetByID(id, householdID); err != nil || existing == nil {
+		http.Error(w, "note not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.noteStore.Delete(id, householdID); err != nil {
 		h.logger.Error("delete note", "error", err)
 		http.Error(w, "failed to delete note", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("note", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("note", "deleted", id, nil))
 
 	w.Header().Set("HX-Trigger", "closeNoteModal")
 
-	noteData, err := h.buildNoteListData()
+	noteData, err := h.buildNoteListData(householdID)
 	if err != nil {
 		http.Error(w, "failed to load notes data", http.StatusInternalServerError)
 		return
 	}
-	members, _ := h.store.List()
+	members, _ := h.store.List(householdID)
 	noteData["Members"] = members
 	h.renderPartial(w, "note-list", noteData)
 }
 
 // NoteTogglePin handles POST to toggle a note's pinned status.
 func (h *TemplateHandler) NoteTogglePin(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	note, err := h.noteStore.TogglePinned(id)
-	if err != nil || note == nil {
+	note, err := h.noteStore.TogglePinned(id, householdID)
+	if err != nil {
 		http.Error(w, "failed to toggle pin", http.StatusInternalServerError)
 		return
 	}
+	if note == nil {
+		http.Error(w, "note not found", http.StatusNotFound)
+		return
+	}
 
-	h.broadcast(websocket.NewMessage("note", "pinned", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("note", "pinned", id, nil))
 
-	noteData, err := h.buildNoteListData()
+	noteData, err := h.buildNoteListData(householdID)
 	if err != nil {
 		http.Error(w, "failed to load notes data", http.StatusInternalServerError)
 		return
 	}
-	members, _ := h.store.List()
+	members, _ := h.store.List(householdID)
 	noteData["Members"] = members
 	h.renderPartial(w, "note-list", noteData)
 }
 
 // buildNoteListData assembles note data for the notes section.
-func (h *TemplateHandler) buildNoteListData() (map[string]any, error) {
-	notes, err := h.noteStore.List()
+func (h *TemplateHandler) buildNoteListData(householdID int64) (map[string]any, error) {
+	notes, err := h.noteStore.List(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list notes: %w", err)
 	}
@@ -2173,13 +2549,13 @@ func (h *TemplateHandler) buildNoteListData() (map[string]any, error) {
 }
 
 // buildNoteSummaryData returns pinned notes for the dashboard widget.
-func (h *TemplateHandler) buildNoteSummaryData() (map[string]any, error) {
-	pinned, err := h.noteStore.ListPinned()
+func (h *TemplateHandler) buildNoteSummaryData(householdID int64) (map[string]any, error) {
+	pinned, err := h.noteStore.ListPinned(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list pinned notes: %w", err)
 	}
 
-	total, err := h.noteStore.List()
+	total, err := h.noteStore.List(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list notes: %w", err)
 	}
@@ -2241,13 +2617,14 @@ func (h *TemplateHandler) RewardsList(w http.ResponseWriter, r *http.Request) {
 
 // RewardRedeem handles POST to redeem a reward.
 func (h *TemplateHandler) RewardRedeem(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	reward, err := h.rewardStore.GetByID(id)
+	reward, err := h.rewardStore.GetByID(id, householdID)
 	if err != nil || reward == nil {
 		http.Error(w, "reward not found", http.StatusNotFound)
 		return
@@ -2263,42 +2640,435 @@ func (h *TemplateHandler) RewardRedeem(w http.ResponseWriter, r *http.Request) {
 		redeemedBy = &activeUserID
 	}
 
-	// Check balance
-	if redeemedBy != nil {
-		balance, err := h.rewardStore.GetPointBalance(*redeemedBy)
-		if err != nil {
-			http.Error(w, "failed to check balance", http.StatusInternalServerError)
+	redemption, err := h.rewardStore.Redeem(id, householdID, redeemedBy, reward.PointCost)
+	switch {
+	case errors.Is(err, store.ErrInsufficientPoints):
+		h.renderToast(w, "error", "Not enough points!")
+	case errors.Is(err, store.ErrOutOfStock):
+		h.renderToast(w, "error", "Sorry, that reward is out of stock")
+	case errors.Is(err, store.ErrOnCooldown):
+		h.renderToast(w, "error", "You've had that one recently. Try again later!")
+	case err != nil:
+		h.logger.Error("redeem reward", "error", err)
+		http.Error(w, "failed to redeem reward", http.StatusInternalServerError)
+		return
+	case redemption == nil:
+		http.Error(w, "reward not found", http.StatusNotFound)
+		return
+	default:
+		requestReward(h.store, h.pushScheduler, householdID, *reward, redemption)
+		h.broadcast(householdID, websocket.NewMessage("reward", "redeemed", id, nil))
+		h.renderToast(w, "success", fmt.Sprintf("Requested: %s! A parent will sort it out.", reward.Title))
+	}
+
+	rewardsData, err := h.buildRewardsData(r)
+	if err != nil {
+		http.Error(w, "failed to load rewards data", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "rewards-list", rewardsData)
+}
+
+// redemptionView is a redemption in the reward request queue.
+type redemptionView struct {
+	ID          int64
+	RewardTitle string
+	MemberName  string
+	MemberEmoji string
+	Points      int
+	Status      model.RedemptionStatus
+	RequestedAt string
+}
+
+// buildRedemptionQueueData returns the household's redemptions waiting on
+// a parent, oldest first, with the parents who can handle them.
+func (h *TemplateHandler) buildRedemptionQueueData(householdID int64) (map[string]any, error) {
+	open, err := h.rewardStore.ListOpenRedemptions(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list open redemptions: %w", err)
+	}
+
+	rewards, err := h.rewardStore.List(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list rewards: %w", err)
+	}
+	titles := make(map[int64]string)
+	for _, rw := range rewards {
+		titles[rw.ID] = rw.Title
+	}
+
+	members, err := h.store.List(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list members: %w", err)
+	}
+	memberMap := make(map[int64]model.FamilyMember)
+	var approvers []model.FamilyMember
+	for _, m := range members {
+		memberMap[m.ID] = m
+		if m.CanApprove(time.Now()) {
+			approvers = append(approvers, m)
+		}
+	}
+
+	loc := h.location(householdID)
+	views := make([]redemptionView, 0, len(open))
+	for _, rd := range open {
+		v := redemptionView{
+			ID:          rd.ID,
+			RewardTitle: titles[rd.RewardID],
+			Points:      rd.PointsSpent,
+			Status:      rd.Status,
+			RequestedAt: rd.RedeemedAt.In(loc).Format("Mon 3:04 PM"),
+		}
+		if rd.RedeemedBy != nil {
+			if m, ok := memberMap[*rd.RedeemedBy]; ok {
+				v.MemberName = m.Name
+				v.MemberEmoji = m.AvatarEmoji
+			}
+		}
+		views = append(views, v)
+	}
+
+	return map[string]any{
+		"Redemptions": views,
+		"Approvers":   approvers,
+	}, nil
+}
+
+func (h *TemplateHandler) renderRedemptionQueue(w http.ResponseWriter, householdID int64) {
+	data, err := h.buildRedemptionQueueData(householdID)
+	if err != nil {
+		h.logger.Error("build redemption queue", "error", err)
+		http.Error(w, "failed to load reward requests", http.StatusInternalServerError)
+		return
+	}
+	h.renderPartial(w, "redemption-queue", data)
+}
+
+// RedemptionStatusUpdate handles a parent approving, fulfilling or
+// cancelling a reward request from the queue. The parent picked in the
+// queue must give their PIN.
+func (h *TemplateHandler) RedemptionStatusUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	actorID, _ := strconv.ParseInt(r.FormValue("actor_id"), 10, 64)
+	if status, msg := verifyMemberPIN(h.store, actorID, householdID, r.FormValue("pin")); msg != "" {
+		if status == http.StatusInternalServerError {
+			http.Error(w, msg, status)
 			return
 		}
-		if balance.Balance < reward.PointCost {
-			h.renderToast(w, "error", "Not enough points!")
+		h.renderToast(w, "error", msg)
+		h.renderRedemptionQueue(w, householdID)
+		return
+	}
+
+	status := model.RedemptionStatus(r.FormValue("status"))
+	redemption, err := moveRedemption(h.rewardStore, householdID, id, status, actorID, r.FormValue("note"))
+	if err != nil {
+		var rewardErr rewardError
+		if !errors.As(err, &rewardErr) {
+			h.logger.Error("update redemption", "error", err)
+			http.Error(w, "failed to update redemption", http.StatusInternalServerError)
 			return
 		}
+		h.renderToast(w, "error", rewardErr.Error())
+	} else {
+		toastMsg := "Reward approved"
+		switch status {
+		case model.RedemptionFulfilled:
+			toastMsg = "Reward handed over"
+		case model.RedemptionCancelled:
+			toastMsg = "Request cancelled and points refunded"
+		}
+		h.broadcast(householdID, websocket.NewMessage("reward", string(redemption.Status), redemption.RewardID, nil))
+		h.renderToast(w, "success", toastMsg)
 	}
 
-	if _, err := h.rewardStore.Redeem(id, redeemedBy, reward.PointCost); err != nil {
-		h.logger.Error("redeem reward", "error", err)
-		http.Error(w, "failed to redeem reward", http.StatusInternalServerError)
+	h.renderRedemptionQueue(w, householdID)
+}
+
+// transactionView is a point ledger entry as shown in a member's history.
+type transactionView struct {
+	model.PointTransaction
+	ActorName string
+	When      string
+}
+
+// buildPointsHistoryData returns a member's balance and point ledger, with
+// the parents who could make an adjustment to it.
+func (h *TemplateHandler) buildPointsHistoryData(householdID, memberID int64) (map[string]any, error) {
+	member, err := h.store.GetByID(memberID, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("get member: %w", err)
+	}
+	if member == nil {
+		return nil, nil
+	}
+
+	balance, err := h.rewardStore.GetPointBalance(memberID, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+
+	txns, err := h.rewardStore.ListTransactions(memberID, householdID, 50)
+	if err != nil {
+		return nil, fmt.Errorf("list transactions: %w", err)
+	}
+
+	members, err := h.store.List(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list members: %w", err)
+	}
+	names := make(map[int64]string)
+	var approvers []model.FamilyMember
+	for _, m := range members {
+		names[m.ID] = m.Name
+		if m.CanApprove(time.Now()) && m.ID != memberID {
+			approvers = append(approvers, m)
+		}
+	}
+
+	loc := h.location(householdID)
+	views := make([]transactionView, 0, len(txns))
+	for _, t := range txns {
+		v := transactionView{PointTransaction: t, When: t.CreatedAt.In(loc).Format("Jan 2, 3:04 PM")}
+		if t.ActorID != nil {
+			v.ActorName = names[*t.ActorID]
+		}
+		views = append(views, v)
+	}
+
+	goals, err := goalProgress(h.rewardStore, memberID, householdID, balance.Balance)
+	if err != nil {
+		return nil, fmt.Errorf("list goals: %w", err)
+	}
+
+	a, err := h.rewardStore.GetAllowance(memberID, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("get allowance: %w", err)
+	}
+	if a == nil {
+		a = &model.Allowance{FamilyMemberID: memberID, Weekday: time.Saturday}
+	}
+	weekdays := make([]time.Weekday, 7)
+	for i := range weekdays {
+		weekdays[i] = time.Weekday(i)
+	}
+
+	return map[string]any{
+		"Member":         member,
+		"Balance":        balance,
+		"Transactions":   views,
+		"Approvers":      approvers,
+		"Goals":          goals,
+		"Allowance":      a,
+		"PointValue":     fmt.Sprintf("%d.%02d", a.CentsPerPoint/100, a.CentsPerPoint%100),
+		"Weekdays":       weekdays,
+		"StatementMonth": time.Now().In(loc).Format("2006-01"),
+	}, nil
+}
+
+func (h *TemplateHandler) renderPointsHistory(w http.ResponseWriter, householdID, memberID int64) {
+	data, err := h.buildPointsHistoryData(householdID, memberID)
+	if err != nil {
+		h.logger.Error("build points history", "error", err)
+		http.Error(w, "failed to load points history", http.StatusInternalServerError)
+		return
+	}
+	if data == nil {
+		http.Error(w, "family member not found", http.StatusNotFound)
 		return
 	}
+	h.renderPartial(w, "points-history", data)
+}
 
-	h.broadcast(websocket.NewMessage("reward", "redeemed", id, nil))
+// MemberPointsHistory renders a member's point ledger.
+func (h *TemplateHandler) MemberPointsHistory(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	h.renderPointsHistory(w, householdID, id)
+}
 
-	h.renderToast(w, "success", fmt.Sprintf("Redeemed: %s!", reward.Title))
+// MemberPointsAdjust handles POST of the adjust points form. Points are
+// entered as a positive number; a penalty takes them away, and a
+// correction can be negative.
+func (h *TemplateHandler) MemberPointsAdjust(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
 
-	rewardsData, err := h.buildRewardsData(r)
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	kind := model.PointTransactionKind(r.FormValue("kind"))
+	amount, _ := strconv.Atoi(r.FormValue("amount"))
+	if kind == model.PointsPenalty && amount > 0 {
+		amount = -amount
+	}
+	reason := strings.TrimSpace(r.FormValue("reason"))
+	actorID, _ := strconv.ParseInt(r.FormValue("actor_id"), 10, 64)
+
+	msg := validateAdjustment(kind, amount, reason)
+	if msg == "" && actorID == id {
+		msg = "members cannot adjust their own points"
+	}
+	if msg == "" {
+		var status int
+		if status, msg = verifyMemberPIN(h.store, actorID, householdID, r.FormValue("pin")); status == http.StatusInternalServerError {
+			http.Error(w, msg, status)
+			return
+		}
+	}
+	if msg != "" {
+		h.renderToast(w, "error", msg)
+		h.renderPointsHistory(w, householdID, id)
+		return
+	}
+
+	if _, err := h.rewardStore.AdjustPoints(id, householdID, kind, amount, reason, &actorID); err != nil {
+		h.logger.Error("adjust points", "error", err)
+		http.Error(w, "failed to adjust points", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("reward", "points_adjusted", id, nil))
+	h.renderToast(w, "success", "Points updated")
+	h.renderPointsHistory(w, householdID, id)
+}
+
+// MemberAllowanceUpdate handles POST of the allowance form. What a point is
+// worth is entered in dollars.
+func (h *TemplateHandler) MemberAllowanceUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
-		http.Error(w, "failed to load rewards data", http.StatusInternalServerError)
+		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
-	h.renderPartial(w, "rewards-list", rewardsData)
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	centsPerPoint, err := allowance.ParseCents(r.FormValue("point_value"))
+	weeklyPoints, _ := strconv.Atoi(r.FormValue("weekly_points"))
+	weekday, _ := strconv.Atoi(r.FormValue("weekday"))
+	actorID, _ := strconv.ParseInt(r.FormValue("actor_id"), 10, 64)
+
+	msg := validateAllowance(centsPerPoint, weeklyPoints, time.Weekday(weekday))
+	if err != nil {
+		msg = "Enter what a point is worth, like 0.10"
+	}
+	if msg == "" && actorID == id {
+		msg = "members cannot set their own allowance"
+	}
+	if msg == "" {
+		var status int
+		if status, msg = verifyMemberPIN(h.store, actorID, householdID, r.FormValue("pin")); status == http.StatusInternalServerError {
+			http.Error(w, msg, status)
+			return
+		}
+	}
+	if msg != "" {
+		h.renderToast(w, "error", msg)
+		h.renderPointsHistory(w, householdID, id)
+		return
+	}
+
+	today := time.Now().In(h.location(householdID)).Format("2006-01-02")
+	if _, err := h.rewardStore.SetAllowance(id, householdID, centsPerPoint, weeklyPoints, time.Weekday(weekday), today); err != nil {
+		h.logger.Error("set allowance", "error", err)
+		http.Error(w, "failed to set allowance", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("reward", "allowance_updated", id, nil))
+	h.renderToast(w, "success", "Allowance saved")
+	h.renderPointsHistory(w, householdID, id)
+}
+
+// MemberGoalCreate handles POST of the new savings goal form.
+func (h *TemplateHandler) MemberGoalCreate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	title := strings.TrimSpace(r.FormValue("title"))
+	target, _ := strconv.Atoi(r.FormValue("target_points"))
+	if title == "" || target <= 0 {
+		h.renderToast(w, "error", "Give the goal a name and the points it needs")
+		h.renderPointsHistory(w, householdID, id)
+		return
+	}
+
+	if _, err := h.rewardStore.CreateGoal(id, householdID, title, target); err != nil {
+		h.logger.Error("create savings goal", "error", err)
+		http.Error(w, "failed to create goal", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("reward", "goal_created", id, nil))
+	h.renderPointsHistory(w, householdID, id)
+}
+
+// MemberGoalDelete handles DELETE of a savings goal.
+func (h *TemplateHandler) MemberGoalDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	goalID, err := strconv.ParseInt(r.PathValue("goal_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid goal id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.rewardStore.DeleteGoal(goalID, id, householdID); err != nil {
+		h.logger.Error("delete savings goal", "error", err)
+		http.Error(w, "failed to delete goal", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("reward", "goal_deleted", id, nil))
+	h.renderPointsHistory(w, householdID, id)
 }
 
 // --- Reward management (settings) handlers ---
 
 // RewardManagePartial renders the reward management list for settings.
 func (h *TemplateHandler) RewardManagePartial(w http.ResponseWriter, r *http.Request) {
-	rewards, err := h.rewardStore.List()
+	householdID := auth.HouseholdID(r.Context())
+	rewards, err := h.rewardStore.List(householdID)
 	if err != nil {
 		http.Error(w, "failed to load rewards", http.StatusInternalServerError)
 		return
@@ -2313,13 +3083,14 @@ func (h *TemplateHandler) RewardNewForm(w http.ResponseWriter, r *http.Request)
 
 // RewardEditForm renders the edit reward form.
 func (h *TemplateHandler) RewardEditForm(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	reward, err := h.rewardStore.GetByID(id)
+	reward, err := h.rewardStore.GetByID(id, householdID)
 	if err != nil || reward == nil {
 		http.Error(w, "reward not found", http.StatusNotFound)
 		return
@@ -2328,8 +3099,21 @@ func (h *TemplateHandler) RewardEditForm(w http.ResponseWriter, r *http.Request)
 	h.renderPartial(w, "reward-edit-form", map[string]any{"Reward": reward})
 }
 
+// formRewardLimits reads a reward's stock and cooldown from a reward form.
+// A blank stock means there is no limit.
+func formRewardLimits(r *http.Request) (*int, int) {
+	var stock *int
+	if v := strings.TrimSpace(r.FormValue("stock")); v != "" {
+		n, _ := strconv.Atoi(v)
+		stock = &n
+	}
+	cooldownDays, _ := strconv.Atoi(r.FormValue("cooldown_days"))
+	return stock, cooldownDays
+}
+
 // RewardCreate handles POST to create a reward.
 func (h *TemplateHandler) RewardCreate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -2344,24 +3128,35 @@ func (h *TemplateHandler) RewardCreate(w http.ResponseWriter, r *http.Request) {
 	description := r.FormValue("description")
 	pointCost, _ := strconv.Atoi(r.FormValue("point_cost"))
 	active := r.FormValue("active") == "on" || r.FormValue("active") == "true"
+	stock, cooldownDays := formRewardLimits(r)
+	if msg := validateLimits(stock, cooldownDays); msg != "" {
+		h.renderToast(w, "error", msg)
+		return
+	}
 
-	reward, err := h.rewardStore.Create(title, description, pointCost, active)
+	reward, err := h.rewardStore.Create(householdID, title, description, pointCost, active)
 	if err != nil {
 		h.logger.Error("create reward", "error", err)
 		http.Error(w, "failed to create reward", http.StatusInternalServerError)
 		return
 	}
+	if err := h.rewardStore.SetLimits(reward.ID, householdID, stock, cooldownDays); err != nil {
+		h.logger.Error("set reward limits", "error", err)
+		http.Error(w, "failed to create reward", http.StatusInternalServerError)
+		return
+	}
 
-	h.broadcast(websocket.NewMessage("reward", "created", reward.ID, nil))
+	h.broadcast(householdID, websocket.NewMessage("reward", "created", reward.ID, nil))
 
 	w.Header().Set("HX-Trigger", "closeRewardModal")
 
-	rewards, _ := h.rewardStore.List()
+	rewards, _ := h.rewardStore.List(householdID)
 	h.renderPartial(w, "reward-manage-list", map[string]any{"Rewards": rewards})
 }
 
 // RewardUpdate handles PUT to update a reward.
 func (h *TemplateHandler) RewardUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
@@ -2382,52 +3177,81 @@ func (h *TemplateHandler) RewardUpdate(w http.ResponseWriter, r *http.Request) {
 	description := r.FormValue("description")
 	pointCost, _ := strconv.Atoi(r.FormValue("point_cost"))
 	active := r.FormValue("active") == "on" || r.FormValue("active") == "true"
+	stock, cooldownDays := formRewardLimits(r)
+	if msg := validateLimits(stock, cooldownDays); msg != "" {
+		h.renderToast(w, "error", msg)
+		return
+	}
 
-	reward, err := h.rewardStore.Update(id, title, description, pointCost, active)
+	reward, err := h.rewardStore.Update(id, householdID, title, description, pointCost, active)
 	if err != nil {
 		h.logger.Error("update reward", "error", err)
 		http.Error(w, "failed to update reward", http.StatusInternalServerError)
 		return
 	}
+	if reward == nil {
+		http.Error(w, "reward not found", http.StatusNotFound)
+		return
+	}
+	if err := h.rewardStore.SetLimits(id, householdID, stock, cooldownDays); err != nil {
+		h.logger.Error("set reward limits", "error", err)
+		http.Error(w, "failed to update reward", http.StatusInternalServerError)
+		return
+	}
 
-	h.broadcast(websocket.NewMessage("reward", "updated", reward.ID, nil))
+	h.broadcast(householdID, websocket.NewMessage("reward", "updated", reward.ID, nil))
 
 	w.Header().Set("HX-Trigger", "closeRewardModal")
 
-	rewards, _ := h.rewardStore.List()
+	rewards, _ := h.rewardStore.List(householdID)
 	h.renderPartial(w, "reward-manage-list", map[string]any{"Rewards": rewards})
 }
 
 // RewardDelete handles DELETE to remove a reward.
 func (h *TemplateHandler) RewardDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.rewardStore.Delete(id); err != nil {
+	if existing, err := h.rewardStore.GetByID(id, householdID); err != nil || existing == nil {
+		http.Error(w, "reward not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.rewardStore.Delete(id, householdID); err != nil {
 		h.logger.Error("delete reward", "error", err)
 		http.Error(w, "failed to delete reward", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("reward", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("reward", "deleted", id, nil))
 
 	w.Header().Set("HX-Trigger", "closeRewardModal")
 
-	rewards, _ := h.rewardStore.List()
+	rewards, _ := h.rewardStore.List(householdID)
 	h.renderPartial(w, "reward-manage-list", map[string]any{"Rewards": rewards})
 }
 
+// rewardView is a reward as offered to the active user. AvailableOn is
+// when they can redeem it again, if they are waiting out its cooldown.
+type rewardView struct {
+	model.Reward
+	OutOfStock  bool
+	AvailableOn string
+}
+
 // buildRewardsData assembles data for the rewards page.
 func (h *TemplateHandler) buildRewardsData(r *http.Request) (map[string]any, error) {
-	rewards, err := h.rewardStore.ListActive()
+	householdID := auth.HouseholdID(r.Context())
+	active, err := h.rewardStore.ListActive(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list active rewards: %w", err)
 	}
 
-	members, err := h.store.List()
+	members, err := h.store.List(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list members: %w", err)
 	}
@@ -2435,25 +3259,52 @@ func (h *TemplateHandler) buildRewardsData(r *http.Request) (map[string]any, err
 	activeUserID := h.activeUserFromCookie(r)
 	var userBalance *model.PointBalance
 	if activeUserID > 0 {
-		userBalance, _ = h.rewardStore.GetPointBalance(activeUserID)
+		userBalance, _ = h.rewardStore.GetPointBalance(activeUserID, householdID)
+		if userBalance != nil {
+			userBalance.Goals, _ = goalProgress(h.rewardStore, activeUserID, householdID, userBalance.Balance)
+		}
 	}
 
-	balances, _ := h.rewardStore.GetAllPointBalances()
+	balances, _ := h.rewardStore.GetAllPointBalances(householdID)
 
-	return map[string]any{
-		"Rewards":      rewards,
-		"Members":      members,
-		"UserBalance":  userBalance,
-		"Balances":     balances,
+	var next map[int64]time.Time
+	if activeUserID > 0 {
+		next, err = h.rewardStore.NextRedeemable(activeUserID, householdID)
+		if err != nil {
+			return nil, fmt.Errorf("next redeemable: %w", err)
+		}
+	}
+	loc := h.location(householdID)
+	rewards := make([]rewardView, 0, len(active))
+	for _, rw := range active {
+		v := rewardView{Reward: rw, OutOfStock: rw.Stock != nil && *rw.Stock <= 0}
+		if at, ok := next[rw.ID]; ok {
+			v.AvailableOn = at.In(loc).Format("Mon Jan 2")
+		}
+		rewards = append(rewards, v)
+	}
+
+	queue, err := h.buildRedemptionQueueData(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"Rewards":      rewards,
+		"Members":      members,
+		"UserBalance":  userBalance,
+		"Balances":     balances,
 		"ActiveUserID": activeUserID,
+		"Redemptions":  queue["Redemptions"],
+		"Approvers":    queue["Approvers"],
 	}, nil
 }
 
 // buildLeaderboardData returns data for the dashboard leaderboard widget.
-func (h *TemplateHandler) buildLeaderboardData() (map[string]any, error) {
-	enabled, _ := h.settingsStore.Get("rewards_leaderboard_enabled")
+func (h *TemplateHandler) buildLeaderboardData(householdID int64) (map[string]any, error) {
+	enabled, _ := h.settingsStore.Get(householdID, "rewards_leaderboard_enabled")
 
-	balances, err := h.rewardStore.GetAllPointBalances()
+	balances, err := h.rewardStore.GetAllPointBalances(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("get all balances: %w", err)
 	}
@@ -2466,12 +3317,13 @@ func (h *TemplateHandler) buildLeaderboardData() (map[string]any, error) {
 
 // SettingsPartial renders the settings section for HTMX swap.
 func (h *TemplateHandler) SettingsPartial(w http.ResponseWriter, r *http.Request) {
-	h.renderPartial(w, "settings-content", nil)
+	h.renderPartial(w, "settings-content", map[string]any{"InstanceAdmin": instanceAdmin(r)})
 }
 
 // KioskSettingsPartial renders the kiosk settings form for HTMX swap.
 func (h *TemplateHandler) KioskSettingsPartial(w http.ResponseWriter, r *http.Request) {
-	settings, err := h.settingsStore.GetKioskSettings()
+	householdID := auth.HouseholdID(r.Context())
+	settings, err := h.settingsStore.GetKioskSettings(householdID)
 	if err != nil {
 		h.logger.Error("get kiosk settings", "error", err)
 		http.Error(w, "failed to load settings", http.StatusInternalServerError)
@@ -2482,6 +3334,7 @@ func (h *TemplateHandler) KioskSettingsPartial(w http.ResponseWriter, r *http.Re
 
 // KioskSettingsUpdate handles PUT form submission for kiosk settings.
 func (h *TemplateHandler) KioskSettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -2512,16 +3365,16 @@ func (h *TemplateHandler) KioskSettingsUpdate(w http.ResponseWriter, r *http.Req
 	}
 
 	for key, value := range settings {
-		if err := h.settingsStore.Set(key, value); err != nil {
+		if err := h.settingsStore.Set(householdID, key, value); err != nil {
 			h.logger.Error("set setting", "key", key, "error", err)
 			h.renderToast(w, "error", "Failed to save settings")
 			return
 		}
 	}
 
-	h.broadcast(websocket.NewMessage("settings", "updated", 0, nil))
+	h.broadcast(householdID, websocket.NewMessage("settings", "updated", 0, nil))
 
-	updated, err := h.settingsStore.GetKioskSettings()
+	updated, err := h.settingsStore.GetKioskSettings(householdID)
 	if err != nil {
 		h.logger.Error("get kiosk settings", "error", err)
 		http.Error(w, "failed to load settings", http.StatusInternalServerError)
@@ -2534,7 +3387,8 @@ func (h *TemplateHandler) KioskSettingsUpdate(w http.ResponseWriter, r *http.Req
 
 // WeatherSettingsPartial renders the weather settings form for HTMX swap.
 func (h *TemplateHandler) WeatherSettingsPartial(w http.ResponseWriter, r *http.Request) {
-	settings, err := h.settingsStore.GetWeatherSettings()
+	householdID := auth.HouseholdID(r.Context())
+	settings, err := h.settingsStore.GetWeatherSettings(householdID)
 	if err != nil {
 		h.logger.Error("get weather settings", "error", err)
 		http.Error(w, "failed to load settings", http.StatusInternalServerError)
@@ -2545,6 +3399,7 @@ func (h *TemplateHandler) WeatherSettingsPartial(w http.ResponseWriter, r *http.
 
 // WeatherSettingsUpdate handles PUT form submission for weather settings.
 func (h *TemplateHandler) WeatherSettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -2573,7 +3428,7 @@ func (h *TemplateHandler) WeatherSettingsUpdate(w http.ResponseWriter, r *http.R
 	}
 
 	for key, value := range settings {
-		if err := h.settingsStore.Set(key, value); err != nil {
+		if err := h.settingsStore.Set(householdID, key, value); err != nil {
 			h.logger.Error("set setting", "key", key, "error", err)
 			h.renderToast(w, "error", "Failed to save settings")
 			return
@@ -2586,9 +3441,9 @@ func (h *TemplateHandler) WeatherSettingsUpdate(w http.ResponseWriter, r *http.R
 		TemperatureUnit: settings["weather_units"],
 	})
 
-	h.broadcast(websocket.NewMessage("settings", "updated", 0, nil))
+	h.broadcast(householdID, websocket.NewMessage("settings", "updated", 0, nil))
 
-	updated, err := h.settingsStore.GetWeatherSettings()
+	updated, err := h.settingsStore.GetWeatherSettings(householdID)
 	if err != nil {
 		h.logger.Error("get weather settings", "error", err)
 		http.Error(w, "failed to load settings", http.StatusInternalServerError)
@@ -2599,9 +3454,44 @@ func (h *TemplateHandler) WeatherSettingsUpdate(w http.ResponseWriter, r *http.R
 	h.renderPartial(w, "weather-settings-form", updated)
 }
 
+// TimezoneSettingsPartial renders the household timezone form for HTMX swap.
+func (h *TemplateHandler) TimezoneSettingsPartial(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	h.renderPartial(w, "timezone-settings-form", map[string]any{
+		"Timezone": h.location(householdID).String(),
+	})
+}
+
+// TimezoneSettingsUpdate handles PUT form submission for the household timezone.
+func (h *TemplateHandler) TimezoneSettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("timezone"))
+	if err := validateTimezone(name); err != nil {
+		h.renderToast(w, "error", "Unknown time zone")
+		return
+	}
+
+	if err := h.settingsStore.Set(householdID, store.TimezoneKey, name); err != nil {
+		h.logger.Error("set setting", "key", store.TimezoneKey, "error", err)
+		h.renderToast(w, "error", "Failed to save settings")
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("settings", "updated", 0, nil))
+
+	h.renderToast(w, "success", "Time zone saved")
+	h.renderPartial(w, "timezone-settings-form", map[string]any{"Timezone": name})
+}
+
 // ThemeSettingsPartial renders the theme settings form for HTMX swap.
 func (h *TemplateHandler) ThemeSettingsPartial(w http.ResponseWriter, r *http.Request) {
-	settings, err := h.settingsStore.GetThemeSettings()
+	householdID := auth.HouseholdID(r.Context())
+	settings, err := h.settingsStore.GetThemeSettings(householdID)
 	if err != nil {
 		h.logger.Error("get theme settings", "error", err)
 		http.Error(w, "failed to load settings", http.StatusInternalServerError)
@@ -2612,6 +3502,7 @@ func (h *TemplateHandler) ThemeSettingsPartial(w http.ResponseWriter, r *http.Re
 
 // ThemeSettingsUpdate handles PUT form submission for theme settings.
 func (h *TemplateHandler) ThemeSettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -2652,16 +3543,16 @@ func (h *TemplateHandler) ThemeSettingsUpdate(w http.ResponseWriter, r *http.Req
 	}
 
 	for key, value := range settings {
-		if err := h.settingsStore.Set(key, value); err != nil {
+		if err := h.settingsStore.Set(householdID, key, value); err != nil {
 			h.logger.Error("set setting", "key", key, "error", err)
 			h.renderToast(w, "error", "Failed to save settings")
 			return
 		}
 	}
 
-	h.broadcast(websocket.NewMessage("settings", "updated", 0, nil))
+	h.broadcast(householdID, websocket.NewMessage("settings", "updated", 0, nil))
 
-	updated, err := h.settingsStore.GetThemeSettings()
+	updated, err := h.settingsStore.GetThemeSettings(householdID)
 	if err != nil {
 		h.logger.Error("get theme settings", "error", err)
 		http.Error(w, "failed to load settings", http.StatusInternalServerError)
@@ -2674,17 +3565,18 @@ func (h *TemplateHandler) ThemeSettingsUpdate(w http.ResponseWriter, r *http.Req
 
 // NextUpcomingEventPartial renders the next upcoming event for the idle screen.
 func (h *TemplateHandler) NextUpcomingEventPartial(w http.ResponseWriter, r *http.Request) {
-	now := time.Now().UTC()
+	householdID := auth.HouseholdID(r.Context())
+	now := h.wallClockNow(householdID)
 	rangeEnd := now.Add(24 * time.Hour)
 
-	events, err := h.expandEventsForRange(now, rangeEnd)
+	events, err := h.calService.Expand(householdID, now, rangeEnd)
 	if err != nil {
 		h.logger.Error("expand events for idle", "error", err)
 		h.renderPartial(w, "idle-next-event", nil)
 		return
 	}
 
-	var nextEvent *expandedEvent
+	var nextEvent *calendar.Occurrence
 	for i := range events {
 		if events[i].StartTime.After(now) && !events[i].AllDay {
 			nextEvent = &events[i]
@@ -2702,13 +3594,14 @@ func (h *TemplateHandler) WeatherPartial(w http.ResponseWriter, r *http.Request)
 
 // SetActiveUser sets the active user cookie for non-PIN members.
 func (h *TemplateHandler) SetActiveUser(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	member, err := h.store.GetByID(id)
+	member, err := h.store.GetByID(id, householdID)
 	if err != nil || member == nil {
 		http.Error(w, "member not found", http.StatusNotFound)
 		return
@@ -2723,7 +3616,7 @@ func (h *TemplateHandler) SetActiveUser(w http.ResponseWriter, r *http.Request)
 		SameSite: http.SameSiteLaxMode,
 	})
 
-	members, err := h.store.List()
+	members, err := h.store.List(householdID)
 	if err != nil {
 		http.Error(w, "failed to load members", http.StatusInternalServerError)
 		return
@@ -2737,13 +3630,14 @@ func (h *TemplateHandler) SetActiveUser(w http.ResponseWriter, r *http.Request)
 
 // UserPINChallenge renders the PIN challenge template into the modal body.
 func (h *TemplateHandler) UserPINChallenge(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	member, err := h.store.GetByID(id)
+	member, err := h.store.GetByID(id, householdID)
 	if err != nil || member == nil {
 		http.Error(w, "member not found", http.StatusNotFound)
 		return
@@ -2754,12 +3648,18 @@ func (h *TemplateHandler) UserPINChallenge(w http.ResponseWriter, r *http.Reques
 
 // VerifyPINAndSetUser verifies the PIN and sets the active user cookie on success.
 func (h *TemplateHandler) VerifyPINAndSetUser(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
+	if existing, err := h.store.GetByID(id, householdID); err != nil || existing == nil {
+		http.Error(w, "member not found", http.StatusNotFound)
+		return
+	}
+
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -2767,14 +3667,14 @@ func (h *TemplateHandler) VerifyPINAndSetUser(w http.ResponseWriter, r *http.Req
 
 	pin := r.FormValue("pin")
 
-	hash, err := h.store.GetPINHash(id)
+	hash, err := h.store.GetPINHash(id, householdID)
 	if err != nil {
 		http.Error(w, "server error", http.StatusInternalServerError)
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(pin)); err != nil {
-		member, _ := h.store.GetByID(id)
+		member, _ := h.store.GetByID(id, householdID)
 		h.renderToast(w, "error", "Incorrect PIN")
 		h.renderPartial(w, "pin-challenge", member)
 		return
@@ -2793,7 +3693,7 @@ func (h *TemplateHandler) VerifyPINAndSetUser(w http.ResponseWriter, r *http.Req
 	// Close the modal via HX-Trigger header.
 	w.Header().Set("HX-Trigger", "closeModal")
 
-	members, err := h.store.List()
+	members, err := h.store.List(householdID)
 	if err != nil {
 		http.Error(w, "failed to load members", http.StatusInternalServerError)
 		return
@@ -2826,7 +3726,8 @@ func (h *TemplateHandler) FamilyMembers(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *TemplateHandler) FamilyMemberList(w http.ResponseWriter, r *http.Request) {
-	members, err := h.store.List()
+	householdID := auth.HouseholdID(r.Context())
+	members, err := h.store.List(householdID)
 	if err != nil {
 		http.Error(w, "failed to load family members", http.StatusInternalServerError)
 		return
@@ -2836,13 +3737,14 @@ func (h *TemplateHandler) FamilyMemberList(w http.ResponseWriter, r *http.Reques
 }
 
 func (h *TemplateHandler) FamilyMemberEditForm(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	member, err := h.store.GetByID(id)
+	member, err := h.store.GetByID(id, householdID)
 	if err != nil || member == nil {
 		http.Error(w, "family member not found", http.StatusNotFound)
 		return
@@ -2852,6 +3754,7 @@ func (h *TemplateHandler) FamilyMemberEditForm(w http.ResponseWriter, r *http.Re
 }
 
 func (h *TemplateHandler) FamilyMemberCreate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -2878,7 +3781,13 @@ func (h *TemplateHandler) FamilyMemberCreate(w http.ResponseWriter, r *http.Requ
 		avatarEmoji = "\xf0\x9f\x98\x80"
 	}
 
-	exists, err := h.store.NameExists(name, 0)
+	profile, msg := memberProfileFromForm(r)
+	if msg != "" {
+		h.renderToast(w, "error", msg)
+		return
+	}
+
+	exists, err := h.store.NameExists(householdID, name, 0)
 	if err != nil {
 		http.Error(w, "server error", http.StatusInternalServerError)
 		return
@@ -2888,15 +3797,19 @@ func (h *TemplateHandler) FamilyMemberCreate(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	member, err := h.store.Create(name, color, avatarEmoji)
+	member, err := h.store.Create(householdID, name, color, avatarEmoji)
 	if err != nil {
 		http.Error(w, "failed to create family member", http.StatusInternalServerError)
 		return
 	}
+	if err := h.store.SetProfile(member.ID, householdID, profile.Birthdate, profile.Role, profile.PointsPercent); err != nil {
+		http.Error(w, "failed to create family member", http.StatusInternalServerError)
+		return
+	}
 
-	h.broadcast(websocket.NewMessage("family_member", "created", member.ID, nil))
+	h.broadcast(householdID, websocket.NewMessage("family_member", "created", member.ID, nil))
 
-	members, err := h.store.List()
+	members, err := h.store.List(householdID)
 	if err != nil {
 		http.Error(w, "failed to load family members", http.StatusInternalServerError)
 		return
@@ -2906,12 +3819,18 @@ func (h *TemplateHandler) FamilyMemberCreate(w http.ResponseWriter, r *http.Requ
 }
 
 func (h *TemplateHandler) FamilyMemberUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
+	if existing, err := h.store.GetByID(id, householdID); err != nil || existing == nil {
+		http.Error(w, "family member not found", http.StatusNotFound)
+		return
+	}
+
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -2931,7 +3850,13 @@ func (h *TemplateHandler) FamilyMemberUpdate(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	exists, err := h.store.NameExists(name, id)
+	profile, msg := memberProfileFromForm(r)
+	if msg != "" {
+		h.renderToast(w, "error", msg)
+		return
+	}
+
+	exists, err := h.store.NameExists(householdID, name, id)
 	if err != nil {
 		http.Error(w, "server error", http.StatusInternalServerError)
 		return
@@ -2941,14 +3866,18 @@ func (h *TemplateHandler) FamilyMemberUpdate(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if _, err := h.store.Update(id, name, color, avatarEmoji); err != nil {
+	if _, err := h.store.Update(id, householdID, name, color, avatarEmoji); err != nil {
+		http.Error(w, "failed to update family member", http.StatusInternalServerError)
+		return
+	}
+	if err := h.store.SetProfile(id, householdID, profile.Birthdate, profile.Role, profile.PointsPercent); err != nil {
 		http.Error(w, "failed to update family member", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("family_member", "updated", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("family_member", "updated", id, nil))
 
-	members, err := h.store.List()
+	members, err := h.store.List(householdID)
 	if err != nil {
 		http.Error(w, "failed to load family members", http.StatusInternalServerError)
 		return
@@ -2957,21 +3886,45 @@ func (h *TemplateHandler) FamilyMemberUpdate(w http.ResponseWriter, r *http.Requ
 	h.renderPartial(w, "member-list", map[string]any{"Members": members})
 }
 
+// memberProfileFromForm reads a member's birthdate, role and point
+// multiplier from the add and edit forms. A blank multiplier means the
+// role's default. It returns a message for the user if any is invalid.
+func memberProfileFromForm(r *http.Request) (model.FamilyMember, string) {
+	m := model.FamilyMember{MemberProfile: model.MemberProfile{
+		Birthdate: strings.TrimSpace(r.FormValue("birthdate")),
+		Role:      model.MemberRole(r.FormValue("role")),
+	}}
+	if v := strings.TrimSpace(r.FormValue("points_percent")); v != "" {
+		pct, err := strconv.Atoi(v)
+		if err != nil {
+			return m, "points_percent must be a whole number"
+		}
+		m.PointsPercent = &pct
+	}
+	return m, validateProfile(m)
+}
+
 func (h *TemplateHandler) FamilyMemberDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.store.Delete(id); err != nil {
+	if existing, err := h.store.GetByID(id, householdID); err != nil || existing == nil {
+		http.Error(w, "family member not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.store.Delete(id, householdID); err != nil {
 		http.Error(w, "failed to delete family member", http.StatusInternalServerError)
 		return
 	}
 
-	h.broadcast(websocket.NewMessage("family_member", "deleted", id, nil))
+	h.broadcast(householdID, websocket.NewMessage("family_member", "deleted", id, nil))
 
-	members, err := h.store.List()
+	members, err := h.store.List(householdID)
 	if err != nil {
 		http.Error(w, "failed to load family members", http.StatusInternalServerError)
 		return
@@ -2981,13 +3934,14 @@ func (h *TemplateHandler) FamilyMemberDelete(w http.ResponseWriter, r *http.Requ
 }
 
 func (h *TemplateHandler) PINSetupForm(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	member, err := h.store.GetByID(id)
+	member, err := h.store.GetByID(id, householdID)
 	if err != nil || member == nil {
 		http.Error(w, "family member not found", http.StatusNotFound)
 		return
@@ -3001,13 +3955,14 @@ func (h *TemplateHandler) PINSetupForm(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *TemplateHandler) PINGate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	member, err := h.store.GetByID(id)
+	member, err := h.store.GetByID(id, householdID)
 	if err != nil || member == nil {
 		http.Error(w, "family member not found", http.StatusNotFound)
 		return
@@ -3028,12 +3983,18 @@ func (h *TemplateHandler) PINGate(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *TemplateHandler) PINVerifyThenAct(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
+	if existing, err := h.store.GetByID(id, householdID); err != nil || existing == nil {
+		http.Error(w, "family member not found", http.StatusNotFound)
+		return
+	}
+
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -3042,16 +4003,30 @@ func (h *TemplateHandler) PINVerifyThenAct(w http.ResponseWriter, r *http.Reques
 	pin := r.FormValue("pin")
 	nextAction := r.FormValue("next_action")
 
-	hash, err := h.store.GetPINHash(id)
+	hash, err := h.store.GetPINHash(id, householdID)
 	if err != nil {
 		http.Error(w, "server error", http.StatusInternalServerError)
 		return
 	}
 
+	isReview := nextAction == "approve" || nextAction == "reject"
+
 	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(pin)); err != nil {
 		h.renderToast(w, "error", "Incorrect PIN")
 
-		member, _ := h.store.GetByID(id)
+		member, _ := h.store.GetByID(id, householdID)
+		if isReview {
+			completionID, _ := strconv.ParseInt(r.FormValue("completion_id"), 10, 64)
+			approvals, _ := h.buildApprovalQueueData(householdID)
+			for _, item := range approvals {
+				if item.CompletionID == completionID && member != nil {
+					h.renderPartial(w, "approval-pin", approvalPINData(item, *member, nextAction))
+					return
+				}
+			}
+			h.renderPartial(w, "approval-queue", map[string]any{"Approvals": approvals})
+			return
+		}
 		data := struct {
 			ID         int64
 			Name       string
@@ -3061,15 +4036,20 @@ func (h *TemplateHandler) PINVerifyThenAct(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	member, _ := h.store.GetByID(id)
+	if isReview {
+		h.reviewAfterPIN(w, r, householdID, id, nextAction)
+		return
+	}
+
+	member, _ := h.store.GetByID(id, householdID)
 
 	if nextAction == "clear" {
-		if err := h.store.ClearPIN(id); err != nil {
+		if err := h.store.ClearPIN(id, householdID); err != nil {
 			http.Error(w, "failed to clear PIN", http.StatusInternalServerError)
 			return
 		}
 		h.renderToast(w, "success", "PIN removed")
-		members, _ := h.store.List()
+		members, _ := h.store.List(householdID)
 		h.renderPartial(w, "member-list", map[string]any{"Members": members})
 		return
 	}
@@ -3079,12 +4059,18 @@ func (h *TemplateHandler) PINVerifyThenAct(w http.ResponseWriter, r *http.Reques
 }
 
 func (h *TemplateHandler) PINSetup(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
+	if existing, err := h.store.GetByID(id, householdID); err != nil || existing == nil {
+		http.Error(w, "family member not found", http.StatusNotFound)
+		return
+	}
+
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form data", http.StatusBadRequest)
 		return
@@ -3094,7 +4080,7 @@ func (h *TemplateHandler) PINSetup(w http.ResponseWriter, r *http.Request) {
 
 	if len(pin) != 4 || !isDigits(pin) {
 		h.renderToast(w, "error", "PIN must be exactly 4 digits")
-		member, _ := h.store.GetByID(id)
+		member, _ := h.store.GetByID(id, householdID)
 		h.renderPartial(w, "pin-setup", member)
 		return
 	}
@@ -3105,14 +4091,14 @@ func (h *TemplateHandler) PINSetup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.SetPIN(id, string(hash)); err != nil {
+	if err := h.store.SetPIN(id, householdID, string(hash)); err != nil {
 		http.Error(w, "failed to set PIN", http.StatusInternalServerError)
 		return
 	}
 
 	h.renderToast(w, "success", "PIN set successfully")
 
-	members, err := h.store.List()
+	members, err := h.store.List(householdID)
 	if err != nil {
 		http.Error(w, "failed to load family members", http.StatusInternalServerError)
 		return
@@ -3133,21 +4119,32 @@ type dayViewEvent struct {
 	IsRecurring    bool
 	ParentID       int64
 	OccurrenceDate string
+	Conflict       bool
 }
 
 type weekDayEvent struct {
 	Title       string
 	Color       string
+	OtherColors []string
 	IsRecurring bool
+	Conflict    bool
 }
 
 type weekDay struct {
-	DayName   string
-	DayNum    int
-	DateStr   string
-	IsToday   bool
-	Events    []weekDayEvent
-	MoreCount int
+	DayName       string
+	DayNum        int
+	DateStr       string
+	IsToday       bool
+	Events        []weekDayEvent
+	MoreCount     int
+	ConflictCount int
+}
+
+type eventAttendeeView struct {
+	Name  string
+	Color string
+	Emoji string
+	Role  model.AttendeeRole
 }
 
 type hourSlot struct {
@@ -3163,20 +4160,70 @@ func (h *TemplateHandler) parseCalendarDate(r *http.Request) time.Time {
 			return t
 		}
 	}
-	return time.Now()
+	return h.wallClockNow(auth.HouseholdID(r.Context()))
+}
+
+// location returns the household's timezone.
+func (h *TemplateHandler) location(householdID int64) *time.Location {
+	return householdLocation(h.settingsStore, householdID, h.logger)
+}
+
+// wallClockNow returns the household's current wall-clock time, in the
+// UTC-labelled form calendar event times are stored in.
+func (h *TemplateHandler) wallClockNow(householdID int64) time.Time {
+	return recurrence.WallClock(time.Now(), h.location(householdID))
 }
 
-func (h *TemplateHandler) buildCalendarViewContent(view string, date time.Time) (template.HTML, error) {
+// calendarMemberFilter reads the family member the day and week views are
+// filtered to, or 0 for everyone.
+func calendarMemberFilter(r *http.Request) int64 {
+	id, _ := strconv.ParseInt(r.URL.Query().Get("member"), 10, 64)
+	return id
+}
+
+// attendeeStyle returns the color an event is drawn in, its attendees'
+// avatars, and the colors of attendees other than the one it is drawn for.
+// Events take the filtered member's color, or else their primary attendee's.
+func attendeeStyle(e model.CalendarEvent, memberMap map[int64]model.FamilyMember, filter int64) (color, emojis string, others []string) {
+	color = "#6B7280" // default gray
+	lead := filter
+	if lead == 0 && e.FamilyMemberID != nil {
+		lead = *e.FamilyMemberID
+	}
+	for _, a := range e.Attendees {
+		m, ok := memberMap[a.FamilyMemberID]
+		if !ok {
+			continue
+		}
+		emojis += m.AvatarEmoji
+		if a.FamilyMemberID == lead {
+			color = m.Color
+		} else {
+			others = append(others, m.Color)
+		}
+	}
+	return color, emojis, others
+}
+
+// addMemberFilter adds what the member filter bar in the day and week views
+// needs: the members, who is selected, and the URL of the view to reload.
+func addMemberFilter(data map[string]any, members []model.FamilyMember, filter int64, viewURL string) {
+	data["Members"] = members
+	data["MemberFilter"] = filter
+	data["ViewURL"] = viewURL
+}
+
+func (h *TemplateHandler) buildCalendarViewContent(householdID int64, view string, date time.Time, memberFilter int64) (template.HTML, error) {
 	var templateName string
 	var data any
 	var err error
 
 	if view == "week" {
 		templateName = "calendar-week-view"
-		data, err = h.buildWeekViewData(date)
+		data, err = h.buildWeekViewData(householdID, date, memberFilter)
 	} else {
 		templateName = "calendar-day-view"
-		data, err = h.buildDayViewData(date)
+		data, err = h.buildDayViewData(householdID, date, memberFilter)
 	}
 	if err != nil {
 		return "", err
@@ -3185,110 +4232,17 @@ func (h *TemplateHandler) buildCalendarViewContent(view string, date time.Time)
 	return h.renderSection(templateName, data)
 }
 
-// expandedEvent is a flattened event including virtual occurrences of recurring events.
-type expandedEvent struct {
-	model.CalendarEvent
-	IsRecurring    bool
-	ParentID       int64
-	OccurrenceDate string // "2006-01-02" for identifying specific occurrences
-}
-
-// expandEventsForRange returns both non-recurring and expanded recurring events for a date range.
-func (h *TemplateHandler) expandEventsForRange(rangeStart, rangeEnd time.Time) ([]expandedEvent, error) {
-	// 1. Get non-recurring events
-	nonRecurring, err := h.eventStore.ListByDateRange(rangeStart, rangeEnd)
-	if err != nil {
-		return nil, fmt.Errorf("list events: %w", err)
-	}
-
-	var results []expandedEvent
-	for _, e := range nonRecurring {
-		results = append(results, expandedEvent{
-			CalendarEvent:  e,
-			OccurrenceDate: e.StartTime.Format("2006-01-02"),
-		})
-	}
-
-	// 2. Get recurring parent events
-	recurring, err := h.eventStore.ListRecurring(rangeEnd)
-	if err != nil {
-		return nil, fmt.Errorf("list recurring: %w", err)
-	}
-
-	// 3. Expand each recurring event
-	for _, parent := range recurring {
-		rule, err := recurrence.Parse(parent.RecurrenceRule)
-		if err != nil {
-			h.logger.Error("skip recurring event", "event_id", parent.ID, "rule", parent.RecurrenceRule, "error", err)
-			continue
-		}
-
-		occurrences := recurrence.Expand(rule, parent.StartTime, parent.EndTime, rangeStart, rangeEnd)
-
-		// Get exceptions for this parent
-		exceptions, err := h.eventStore.ListExceptions(parent.ID)
-		if err != nil {
-			return nil, fmt.Errorf("list exceptions for %d: %w", parent.ID, err)
-		}
-
-		// Build exception lookup by original start time (date-only key)
-		excMap := make(map[string]model.CalendarEvent)
-		for _, exc := range exceptions {
-			if exc.OriginalStartTime != nil {
-				key := exc.OriginalStartTime.Format("2006-01-02T15:04:05Z")
-				excMap[key] = exc
-			}
-		}
-
-		for _, occ := range occurrences {
-			key := occ.Start.Format("2006-01-02T15:04:05Z")
-			if exc, found := excMap[key]; found {
-				if exc.Cancelled {
-					continue // skip cancelled occurrence
-				}
-				// Use exception's data
-				results = append(results, expandedEvent{
-					CalendarEvent:  exc,
-					IsRecurring:    true,
-					ParentID:       parent.ID,
-					OccurrenceDate: occ.Start.Format("2006-01-02"),
-				})
-			} else {
-				// Virtual occurrence from parent data
-				virtual := parent
-				virtual.StartTime = occ.Start
-				virtual.EndTime = occ.End
-				results = append(results, expandedEvent{
-					CalendarEvent:  virtual,
-					IsRecurring:    true,
-					ParentID:       parent.ID,
-					OccurrenceDate: occ.Start.Format("2006-01-02"),
-				})
-			}
-		}
-	}
-
-	// Sort: all-day first, then by start time
-	sort.Slice(results, func(i, j int) bool {
-		if results[i].AllDay != results[j].AllDay {
-			return results[i].AllDay
-		}
-		return results[i].StartTime.Before(results[j].StartTime)
-	})
-
-	return results, nil
-}
-
-func (h *TemplateHandler) buildDayViewData(date time.Time) (map[string]any, error) {
+func (h *TemplateHandler) buildDayViewData(householdID int64, date time.Time, memberFilter int64) (map[string]any, error) {
 	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
 	dayEnd := dayStart.Add(24 * time.Hour)
 
-	events, err := h.expandEventsForRange(dayStart, dayEnd)
+	events, err := h.calService.Expand(householdID, dayStart, dayEnd)
 	if err != nil {
 		return nil, fmt.Errorf("expand events: %w", err)
 	}
+	conflicted := calendar.ConflictingKeys(calendar.FindConflicts(events, false))
 
-	members, err := h.store.List()
+	members, err := h.store.List(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list members: %w", err)
 	}
@@ -3301,14 +4255,10 @@ func (h *TemplateHandler) buildDayViewData(date time.Time) (map[string]any, erro
 	var timedEvents []dayViewEvent
 
 	for _, e := range events {
-		color := "#6B7280" // default gray
-		emoji := ""
-		if e.FamilyMemberID != nil {
-			if m, ok := memberMap[*e.FamilyMemberID]; ok {
-				color = m.Color
-				emoji = m.AvatarEmoji
-			}
+		if memberFilter != 0 && !e.HasAttendee(memberFilter) {
+			continue
 		}
+		color, emoji, _ := attendeeStyle(e.CalendarEvent, memberMap, memberFilter)
 
 		if e.AllDay {
 			allDayEvents = append(allDayEvents, dayViewEvent{
@@ -3358,6 +4308,7 @@ func (h *TemplateHandler) buildDayViewData(date time.Time) (map[string]any, erro
 			IsRecurring:    e.IsRecurring,
 			ParentID:       e.ParentID,
 			OccurrenceDate: e.OccurrenceDate,
+			Conflict:       conflicted[e.Key()],
 		})
 	}
 
@@ -3378,10 +4329,10 @@ func (h *TemplateHandler) buildDayViewData(date time.Time) (map[string]any, erro
 		}
 	}
 
-	today := time.Now()
+	today := h.wallClockNow(householdID)
 	isToday := date.Year() == today.Year() && date.YearDay() == today.YearDay()
 
-	return map[string]any{
+	data := map[string]any{
 		"DateStr":      dayStart.Format("2006-01-02"),
 		"DateLabel":    dayStart.Format("Monday, January 2, 2006"),
 		"PrevDate":     dayStart.AddDate(0, 0, -1).Format("2006-01-02"),
@@ -3390,10 +4341,12 @@ func (h *TemplateHandler) buildDayViewData(date time.Time) (map[string]any, erro
 		"AllDayEvents": allDayEvents,
 		"TimedEvents":  timedEvents,
 		"Hours":        hours,
-	}, nil
+	}
+	addMemberFilter(data, members, memberFilter, "/partials/calendar/day?date="+dayStart.Format("2006-01-02"))
+	return data, nil
 }
 
-func (h *TemplateHandler) buildWeekViewData(date time.Time) (map[string]any, error) {
+func (h *TemplateHandler) buildWeekViewData(householdID int64, date time.Time, memberFilter int64) (map[string]any, error) {
 	// Find Monday of the week (ISO 8601)
 	weekday := date.Weekday()
 	if weekday == time.Sunday {
@@ -3403,12 +4356,13 @@ func (h *TemplateHandler) buildWeekViewData(date time.Time) (map[string]any, err
 	monday = time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, time.UTC)
 	sunday := monday.AddDate(0, 0, 7)
 
-	events, err := h.expandEventsForRange(monday, sunday)
+	events, err := h.calService.Expand(householdID, monday, sunday)
 	if err != nil {
 		return nil, fmt.Errorf("expand events: %w", err)
 	}
+	conflicted := calendar.ConflictingKeys(calendar.FindConflicts(events, false))
 
-	members, err := h.store.List()
+	members, err := h.store.List(householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list members: %w", err)
 	}
@@ -3417,7 +4371,7 @@ func (h *TemplateHandler) buildWeekViewData(date time.Time) (map[string]any, err
 		memberMap[m.ID] = m
 	}
 
-	today := time.Now()
+	today := h.wallClockNow(householdID)
 	days := make([]weekDay, 7)
 
 	for i := 0; i < 7; i++ {
@@ -3428,20 +4382,24 @@ func (h *TemplateHandler) buildWeekViewData(date time.Time) (map[string]any, err
 		isToday := d.Year() == today.Year() && d.YearDay() == today.YearDay()
 
 		var dayEvents []weekDayEvent
+		conflictCount := 0
 		for _, e := range events {
 			// Check overlap with this day
 			if e.StartTime.Before(dayEnd) && e.EndTime.After(dayStart) {
-				color := "#6B7280"
-				if e.FamilyMemberID != nil {
-					if m, ok := memberMap[*e.FamilyMemberID]; ok {
-						color = m.Color
-					}
+				if memberFilter != 0 && !e.HasAttendee(memberFilter) {
+					continue
 				}
+				color, _, others := attendeeStyle(e.CalendarEvent, memberMap, memberFilter)
 				dayEvents = append(dayEvents, weekDayEvent{
 					Title:       e.Title,
 					Color:       color,
+					OtherColors: others,
 					IsRecurring: e.IsRecurring,
+					Conflict:    conflicted[e.Key()],
 				})
+				if conflicted[e.Key()] {
+					conflictCount++
+				}
 			}
 		}
 
@@ -3453,27 +4411,30 @@ func (h *TemplateHandler) buildWeekViewData(date time.Time) (map[string]any, err
 		}
 
 		days[i] = weekDay{
-			DayName:   d.Format("Mon"),
-			DayNum:    d.Day(),
-			DateStr:   d.Format("2006-01-02"),
-			IsToday:   isToday,
-			Events:    displayEvents,
-			MoreCount: moreCount,
+			DayName:       d.Format("Mon"),
+			DayNum:        d.Day(),
+			DateStr:       d.Format("2006-01-02"),
+			IsToday:       isToday,
+			Events:        displayEvents,
+			MoreCount:     moreCount,
+			ConflictCount: conflictCount,
 		}
 	}
 
 	weekLabel := fmt.Sprintf("%s - %s", monday.Format("Jan 2"), monday.AddDate(0, 0, 6).Format("Jan 2, 2006"))
 
-	return map[string]any{
+	data := map[string]any{
 		"Days":      days,
 		"WeekLabel": weekLabel,
 		"PrevWeek":  monday.AddDate(0, 0, -7).Format("2006-01-02"),
 		"NextWeek":  monday.AddDate(0, 0, 7).Format("2006-01-02"),
 		"TodayStr":  today.Format("2006-01-02"),
-	}, nil
+	}
+	addMemberFilter(data, members, memberFilter, "/partials/calendar/week?date="+monday.Format("2006-01-02"))
+	return data, nil
 }
 
-func (h *TemplateHandler) buildEventDetailData(event *model.CalendarEvent, isRecurring bool, parentID int64, occurrenceDate string) map[string]any {
+func (h *TemplateHandler) buildEventDetailData(householdID int64, event *model.CalendarEvent, isRecurring bool, parentID int64, occurrenceDate string) map[string]any {
 	data := map[string]any{
 		"ID":             event.ID,
 		"Title":          event.Title,
@@ -3520,13 +4481,29 @@ func (h *TemplateHandler) buildEventDetailData(event *model.CalendarEvent, isRec
 		}
 	}
 
-	if event.FamilyMemberID != nil {
-		member, err := h.store.GetByID(*event.FamilyMemberID)
+	var attendees []eventAttendeeView
+	for _, a := range event.Attendees {
+		member, err := h.store.GetByID(a.FamilyMemberID, householdID)
 		if err == nil && member != nil {
-			data["MemberName"] = member.Name
-			data["MemberColor"] = member.Color
-			data["MemberEmoji"] = member.AvatarEmoji
+			attendees = append(attendees, eventAttendeeView{Name: member.Name, Color: member.Color, Emoji: member.AvatarEmoji, Role: a.Role})
+		}
+	}
+	data["Attendees"] = attendees
+
+	if event.Imported() {
+		data["Imported"] = true
+		data["SourceName"] = "an .ics file"
+		if event.SubscriptionID != nil {
+			if sub, err := h.calSubStore.GetByID(*event.SubscriptionID, householdID); err == nil && sub != nil {
+				data["SourceName"] = sub.Name
+			}
 		}
+		var memberID int64
+		if event.FamilyMemberID != nil {
+			memberID = *event.FamilyMemberID
+		}
+		data["FamilyMemberID"] = memberID
+		data["Members"], _ = h.store.List(householdID)
 	}
 
 	return data
@@ -3535,9 +4512,13 @@ func (h *TemplateHandler) buildEventDetailData(event *model.CalendarEvent, isRec
 // LicenseSettingsPartial renders the license/subscription settings card content.
 // TunnelSettingsPartial renders the tunnel settings card content.
 func (h *TemplateHandler) TunnelSettingsPartial(w http.ResponseWriter, r *http.Request) {
+	if !instanceAdmin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
 	hasTunnel := h.licenseClient.HasFeature("tunnel")
 	status := h.tunnelManager.Status()
-	tunnelSettings, _ := h.settingsStore.GetTunnelSettings()
+	tunnelSettings, _ := h.settingsStore.GetTunnelSettings(store.DefaultHouseholdID)
 	data := map[string]any{
 		"HasTunnel":     hasTunnel,
 		"TunnelState":   string(status.State),
@@ -3551,6 +4532,10 @@ func (h *TemplateHandler) TunnelSettingsPartial(w http.ResponseWriter, r *http.R
 
 // TunnelSettingsUpdate handles saving tunnel settings.
 func (h *TemplateHandler) TunnelSettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	if !instanceAdmin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
 	if err := r.ParseForm(); err != nil {
 		h.renderToast(w, "error", "Invalid form data")
 		return
@@ -3559,8 +4544,8 @@ func (h *TemplateHandler) TunnelSettingsUpdate(w http.ResponseWriter, r *http.Re
 	token := strings.TrimSpace(r.FormValue("tunnel_token"))
 	enabled := r.FormValue("tunnel_enabled") == "true"
 
-	h.settingsStore.Set("tunnel_token", token)
-	h.settingsStore.Set("tunnel_enabled", fmt.Sprintf("%t", enabled))
+	h.settingsStore.Set(store.DefaultHouseholdID, "tunnel_token", token)
+	h.settingsStore.Set(store.DefaultHouseholdID, "tunnel_enabled", fmt.Sprintf("%t", enabled))
 
 	h.tunnelManager.UpdateConfig(tunnel.Config{
 		Token:   token,
@@ -3583,6 +4568,10 @@ func (h *TemplateHandler) TunnelSettingsUpdate(w http.ResponseWriter, r *http.Re
 
 // TunnelStatusPartial returns just the tunnel status badge for polling.
 func (h *TemplateHandler) TunnelStatusPartial(w http.ResponseWriter, r *http.Request) {
+	if !instanceAdmin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
 	status := h.tunnelManager.Status()
 	data := map[string]any{
 		"TunnelState": string(status.State),
@@ -3593,6 +4582,10 @@ func (h *TemplateHandler) TunnelStatusPartial(w http.ResponseWriter, r *http.Req
 }
 
 func (h *TemplateHandler) LicenseSettingsPartial(w http.ResponseWriter, r *http.Request) {
+	if !instanceAdmin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
 	status := h.licenseClient.Status()
 	data := map[string]any{
 		"IsFreeTier": h.licenseClient.IsFreeTier(),
@@ -3608,6 +4601,10 @@ func (h *TemplateHandler) LicenseSettingsPartial(w http.ResponseWriter, r *http.
 
 // LicenseKeyUpdate handles updating the license key from settings.
 func (h *TemplateHandler) LicenseKeyUpdate(w http.ResponseWriter, r *http.Request) {
+	if !instanceAdmin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
 	if err := r.ParseForm(); err != nil {
 		h.renderToast(w, "error", "Invalid form data")
 		return
@@ -3615,7 +4612,7 @@ func (h *TemplateHandler) LicenseKeyUpdate(w http.ResponseWriter, r *http.Reques
 
 	key := strings.TrimSpace(r.FormValue("license_key"))
 	h.licenseClient.SetKey(key)
-	h.settingsStore.Set("license_key", key)
+	h.settingsStore.Set(store.DefaultHouseholdID, "license_key", key)
 
 	status := h.licenseClient.Status()
 	data := map[string]any{
@@ -3634,7 +4631,11 @@ func (h *TemplateHandler) LicenseKeyUpdate(w http.ResponseWriter, r *http.Reques
 
 // S3SettingsPartial renders the S3 storage settings card content.
 func (h *TemplateHandler) S3SettingsPartial(w http.ResponseWriter, r *http.Request) {
-	s3Settings, _ := h.settingsStore.GetS3Settings()
+	if !instanceAdmin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	s3Settings, _ := h.settingsStore.GetS3Settings(store.DefaultHouseholdID)
 	configured := s3Settings["backup_s3_bucket"] != "" && s3Settings["backup_s3_access_key"] != "" && s3Settings["backup_s3_secret_key"] != ""
 	data := map[string]any{
 		"Endpoint":   s3Settings["backup_s3_endpoint"],
@@ -3649,6 +4650,10 @@ func (h *TemplateHandler) S3SettingsPartial(w http.ResponseWriter, r *http.Reque
 
 // S3SettingsUpdate handles saving S3 storage settings.
 func (h *TemplateHandler) S3SettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	if !instanceAdmin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
 	if err := r.ParseForm(); err != nil {
 		h.renderToast(w, "error", "Invalid form data")
 		return
@@ -3660,11 +4665,11 @@ func (h *TemplateHandler) S3SettingsUpdate(w http.ResponseWriter, r *http.Reques
 	accessKey := strings.TrimSpace(r.FormValue("backup_s3_access_key"))
 	secretKey := strings.TrimSpace(r.FormValue("backup_s3_secret_key"))
 
-	h.settingsStore.Set("backup_s3_endpoint", endpoint)
-	h.settingsStore.Set("backup_s3_bucket", bucket)
-	h.settingsStore.Set("backup_s3_region", region)
-	h.settingsStore.Set("backup_s3_access_key", accessKey)
-	h.settingsStore.Set("backup_s3_secret_key", secretKey)
+	h.settingsStore.Set(store.DefaultHouseholdID, "backup_s3_endpoint", endpoint)
+	h.settingsStore.Set(store.DefaultHouseholdID, "backup_s3_bucket", bucket)
+	h.settingsStore.Set(store.DefaultHouseholdID, "backup_s3_region", region)
+	h.settingsStore.Set(store.DefaultHouseholdID, "backup_s3_access_key", accessKey)
+	h.settingsStore.Set(store.DefaultHouseholdID, "backup_s3_secret_key", secretKey)
 
 	if h.backupManager != nil {
 		h.backupManager.UpdateS3Config(backup.S3Config{
@@ -3676,7 +4681,7 @@ func (h *TemplateHandler) S3SettingsUpdate(w http.ResponseWriter, r *http.Reques
 		})
 	}
 
-	h.broadcast(websocket.NewMessage("settings", "updated", 0, nil))
+	h.broadcast(store.DefaultHouseholdID, websocket.NewMessage("settings", "updated", 0, nil))
 
 	w.Header().Set("HX-Trigger", `{"showToast": "S3 storage settings updated"}`)
 	h.S3SettingsPartial(w, r)
@@ -3710,9 +4715,9 @@ func (h *TemplateHandler) renderToast(w http.ResponseWriter, level, message stri
 func (h *TemplateHandler) BackupSettingsPartial(w http.ResponseWriter, r *http.Request) {
 	hasBackup := h.licenseClient.HasFeature("backup")
 	status := h.backupManager.Status()
-	backupSettings, _ := h.settingsStore.GetBackupSettings()
-
 	householdID := auth.HouseholdID(r.Context())
+	backupSettings, _ := h.settingsStore.GetBackupSettings(householdID)
+
 	history, _ := h.backupStore.List(householdID, 10)
 	totalSize, _ := h.backupStore.TotalSizeByHousehold(householdID)
 
@@ -3736,6 +4741,7 @@ func (h *TemplateHandler) BackupSettingsPartial(w http.ResponseWriter, r *http.R
 
 // BackupSettingsUpdate handles saving backup schedule settings.
 func (h *TemplateHandler) BackupSettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
 	if err := r.ParseForm(); err != nil {
 		h.renderToast(w, "error", "Invalid form data")
 		return
@@ -3745,12 +4751,12 @@ func (h *TemplateHandler) BackupSettingsUpdate(w http.ResponseWriter, r *http.Re
 	scheduleHour := r.FormValue("backup_schedule_hour")
 	retentionDays := r.FormValue("backup_retention_days")
 
-	h.settingsStore.Set("backup_enabled", fmt.Sprintf("%t", enabled))
+	h.settingsStore.Set(householdID, "backup_enabled", fmt.Sprintf("%t", enabled))
 	if scheduleHour != "" {
-		h.settingsStore.Set("backup_schedule_hour", scheduleHour)
+		h.settingsStore.Set(householdID, "backup_schedule_hour", scheduleHour)
 	}
 	if retentionDays != "" {
-		h.settingsStore.Set("backup_retention_days", retentionDays)
+		h.settingsStore.Set(householdID, "backup_retention_days", retentionDays)
 	}
 
 	w.Header().Set("HX-Trigger", `{"showToast": "Backup settings updated"}`)
@@ -3785,12 +4791,12 @@ func (h *TemplateHandler) BackupPassphraseUpdate(w http.ResponseWriter, r *http.
 	}
 
 	// Store salt as hex and hash
+	householdID := auth.HouseholdID(r.Context())
 	saltHex := fmt.Sprintf("%x", salt)
-	h.settingsStore.Set("backup_passphrase_salt", saltHex)
-	h.settingsStore.Set("backup_passphrase_hash", string(hash))
+	h.settingsStore.Set(householdID, "backup_passphrase_salt", saltHex)
+	h.settingsStore.Set(householdID, "backup_passphrase_hash", string(hash))
 
 	// Cache key for scheduled backups
-	householdID := auth.HouseholdID(r.Context())
 	h.backupManager.CacheKey(householdID, passphrase, salt)
 
 	w.Header().Set("HX-Trigger", `{"showToast": "Backup passphrase saved"}`)
@@ -3811,15 +4817,14 @@ func (h *TemplateHandler) BackupNow(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Verify passphrase against stored hash
-	backupSettings, _ := h.settingsStore.GetBackupSettings()
+	householdID := auth.HouseholdID(r.Context())
+	backupSettings, _ := h.settingsStore.GetBackupSettings(householdID)
 	storedHash := backupSettings["backup_passphrase_hash"]
 	if err := bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(passphrase)); err != nil {
 		h.renderToast(w, "error", "Incorrect passphrase")
 		return
 	}
 
-	householdID := auth.HouseholdID(r.Context())
-
 	go func() {
 		if _, err := h.backupManager.RunNow(r.Context(), householdID, passphrase); err != nil {
 			h.logger.Error("backup now failed", "error", err)
@@ -3876,15 +4881,14 @@ func (h *TemplateHandler) BackupRestore(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Verify passphrase
-	backupSettings, _ := h.settingsStore.GetBackupSettings()
+	householdID := auth.HouseholdID(r.Context())
+	backupSettings, _ := h.settingsStore.GetBackupSettings(householdID)
 	storedHash := backupSettings["backup_passphrase_hash"]
 	if err := bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(passphrase)); err != nil {
 		h.renderToast(w, "error", "Incorrect passphrase")
 		return
 	}
 
-	householdID := auth.HouseholdID(r.Context())
-
 	if err := h.backupManager.Restore(r.Context(), backupID, householdID, passphrase); err != nil {
 		h.renderToast(w, "error", fmt.Sprintf("Restore failed: %v", err))
 		return
@@ -3941,6 +4945,8 @@ func (h *TemplateHandler) PushSettingsPartial(w http.ResponseWriter, r *http.Req
 		prefMap := map[string]bool{
 			model.NotifTypeCalendarReminder: true,
 			model.NotifTypeChoreDue:         true,
+			model.NotifTypeChoreApproval:    true,
+			model.NotifTypeRewardRequest:    true,
 			model.NotifTypeGroceryAdded:     true,
 		}
 		for _, p := range prefs {
@@ -3952,16 +4958,20 @@ func (h *TemplateHandler) PushSettingsPartial(w http.ResponseWriter, r *http.Req
 			vapidKey = h.pushService.VAPIDPublicKey()
 		}
 
-		data["Subscriptions"] = subs
+		members, _ := h.store.List(householdID)
+		data["Subscriptions"] = pushDevices(subs)
 		data["SubscriptionCount"] = len(subs)
+		data["Members"] = members
 		data["CalendarEnabled"] = prefMap[model.NotifTypeCalendarReminder]
 		data["ChoreEnabled"] = prefMap[model.NotifTypeChoreDue]
+		data["ApprovalEnabled"] = prefMap[model.NotifTypeChoreApproval]
+		data["RewardRequestEnabled"] = prefMap[model.NotifTypeRewardRequest]
 		data["GroceryEnabled"] = prefMap[model.NotifTypeGroceryAdded]
 		data["VAPIDKey"] = vapidKey
 	}
 
 	// Add VAPID key info from DB for display
-	if vapidSettings, err := h.settingsStore.GetVAPIDSettings(); err == nil {
+	if vapidSettings, err := h.settingsStore.GetVAPIDSettings(store.DefaultHouseholdID); err == nil {
 		data["VAPIDPublicKeyFull"] = vapidSettings["vapid_public_key"]
 		data["VAPIDKeysConfigured"] = vapidSettings["vapid_public_key"] != "" && vapidSettings["vapid_private_key"] != ""
 	}
@@ -3981,10 +4991,14 @@ func (h *TemplateHandler) PushPreferencesUpdate(w http.ResponseWriter, r *http.R
 
 	calendarEnabled := r.FormValue("calendar_enabled") == "true"
 	choreEnabled := r.FormValue("chore_enabled") == "true"
+	approvalEnabled := r.FormValue("approval_enabled") == "true"
+	rewardRequestEnabled := r.FormValue("reward_request_enabled") == "true"
 	groceryEnabled := r.FormValue("grocery_enabled") == "true"
 
 	h.pushStore.SetPreference(userID, householdID, model.NotifTypeCalendarReminder, calendarEnabled)
 	h.pushStore.SetPreference(userID, householdID, model.NotifTypeChoreDue, choreEnabled)
+	h.pushStore.SetPreference(userID, householdID, model.NotifTypeChoreApproval, approvalEnabled)
+	h.pushStore.SetPreference(userID, householdID, model.NotifTypeRewardRequest, rewardRequestEnabled)
 	h.pushStore.SetPreference(userID, householdID, model.NotifTypeGroceryAdded, groceryEnabled)
 
 	w.Header().Set("HX-Trigger", `{"showToast": "Notification preferences updated"}`)
@@ -3997,11 +5011,58 @@ func (h *TemplateHandler) PushDevicesList(w http.ResponseWriter, r *http.Request
 	householdID := auth.HouseholdID(r.Context())
 
 	subs, _ := h.pushStore.ListByUser(userID, householdID)
+	members, _ := h.store.List(householdID)
 	h.renderPartial(w, "push-devices-list", map[string]any{
-		"Subscriptions": subs,
+		"Subscriptions": pushDevices(subs),
+		"Members":       members,
 	})
 }
 
+// pushDevice is a push subscription in the device list, with the member
+// it belongs to as a plain ID for the member picker.
+type pushDevice struct {
+	model.PushSubscription
+	MemberID int64
+}
+
+func pushDevices(subs []model.PushSubscription) []pushDevice {
+	devices := make([]pushDevice, 0, len(subs))
+	for _, sub := range subs {
+		d := pushDevice{PushSubscription: sub}
+		if sub.FamilyMemberID != nil {
+			d.MemberID = *sub.FamilyMemberID
+		}
+		devices = append(devices, d)
+	}
+	return devices
+}
+
+// PushDeviceMember sets which family member a push subscription belongs to.
+func (h *TemplateHandler) PushDeviceMember(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var memberID *int64
+	if mStr := r.FormValue("family_member_id"); mStr != "" && mStr != "0" {
+		mid, err := strconv.ParseInt(mStr, 10, 64)
+		if err == nil {
+			memberID = &mid
+		}
+	}
+
+	if err := h.pushStore.SetSubscriptionMember(id, householdID, memberID); err != nil {
+		h.logger.Error("set push subscription member", "error", err)
+		h.renderToast(w, "error", "Failed to update device")
+		return
+	}
+	w.Header().Set("HX-Trigger", `{"showToast": "Device updated"}`)
+	h.PushSettingsPartial(w, r)
+}
+
 // PushDeviceDelete removes a push subscription.
 func (h *TemplateHandler) PushDeviceDelete(w http.ResponseWriter, r *http.Request) {
 	householdID := auth.HouseholdID(r.Context())
@@ -4015,3 +5076,474 @@ func (h *TemplateHandler) PushDeviceDelete(w http.ResponseWriter, r *http.Reques
 	w.Header().Set("HX-Trigger", `{"showToast": "Device removed"}`)
 	h.PushSettingsPartial(w, r)
 }
+
+// calendarSubscriptionView is a subscription with its assignee flattened for
+// the member select.
+type calendarSubscriptionView struct {
+	model.CalendarSubscription
+	MemberID int64
+}
+
+// formMemberID reads an optional family_member_id form value, ignoring IDs
+// outside the household.
+func (h *TemplateHandler) formMemberID(r *http.Request, householdID int64) *int64 {
+	id, err := strconv.ParseInt(r.FormValue("family_member_id"), 10, 64)
+	if err != nil || id == 0 {
+		return nil
+	}
+	member, err := h.store.GetByID(id, householdID)
+	if err != nil || member == nil {
+		return nil
+	}
+	return &id
+}
+
+// formAttendees reads the attendee_role_<memberID> fields of the event
+// forms, in family member order, ignoring members outside the household. A
+// lone family_member_id is taken as a single attendee.
+func (h *TemplateHandler) formAttendees(r *http.Request, householdID int64) []model.Attendee {
+	members, err := h.store.List(householdID)
+	if err != nil {
+		h.logger.Error("list members", "error", err)
+		return nil
+	}
+
+	var attendees []model.Attendee
+	for _, m := range members {
+		role := model.AttendeeRole(r.FormValue(fmt.Sprintf("attendee_role_%d", m.ID)))
+		if role.Valid() {
+			attendees = append(attendees, model.Attendee{FamilyMemberID: m.ID, Role: role})
+		}
+	}
+	if len(attendees) == 0 {
+		if id := h.formMemberID(r, householdID); id != nil {
+			attendees = append(attendees, model.Attendee{FamilyMemberID: *id, Role: model.RoleAttendee})
+		}
+	}
+	return attendees
+}
+
+// CalendarSubscriptionsPartial renders the calendar subscriptions card content.
+func (h *TemplateHandler) CalendarSubscriptionsPartial(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	subs, err := h.calSubStore.List(householdID)
+	if err != nil {
+		h.logger.Error("list calendar subscriptions", "error", err)
+		h.renderToast(w, "error", "Failed to load calendar subscriptions")
+		return
+	}
+	members, _ := h.store.List(householdID)
+
+	views := make([]calendarSubscriptionView, 0, len(subs))
+	for _, sub := range subs {
+		view := calendarSubscriptionView{CalendarSubscription: sub}
+		if sub.FamilyMemberID != nil {
+			view.MemberID = *sub.FamilyMemberID
+		}
+		views = append(views, view)
+	}
+
+	h.renderPartial(w, "calendar-subscriptions-form", map[string]any{
+		"Subscriptions": views,
+		"Members":       members,
+	})
+}
+
+// CalendarSubscriptionCreate adds a subscription and fetches it.
+func (h *TemplateHandler) CalendarSubscriptionCreate(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderToast(w, "error", "Invalid form data")
+		return
+	}
+
+	householdID := auth.HouseholdID(r.Context())
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.renderToast(w, "error", "Name is required")
+		return
+	}
+	url, err := calsync.NormalizeURL(r.FormValue("url"))
+	if err != nil {
+		h.renderToast(w, "error", "Enter an http, https, or webcal link")
+		return
+	}
+
+	sub, err := h.calSubStore.Create(householdID, name, url, h.formMemberID(r, householdID))
+	if err != nil {
+		h.logger.Error("create calendar subscription", "error", err)
+		h.renderToast(w, "error", "Failed to add subscription")
+		return
+	}
+
+	if _, err := h.calImporter.SyncSubscription(r.Context(), *sub); err != nil {
+		w.Header().Set("HX-Trigger", `{"showToast": "Subscription added, but the first sync failed"}`)
+	} else {
+		w.Header().Set("HX-Trigger", `{"showToast": "Subscription added"}`)
+	}
+	h.CalendarSubscriptionsPartial(w, r)
+}
+
+// CalendarSubscriptionSync re-fetches a subscription immediately.
+func (h *TemplateHandler) CalendarSubscriptionSync(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		h.renderToast(w, "error", "Invalid subscription ID")
+		return
+	}
+
+	sub, err := h.calSubStore.GetByID(id, householdID)
+	if err != nil || sub == nil {
+		h.renderToast(w, "error", "Subscription not found")
+		return
+	}
+
+	if _, err := h.calImporter.SyncSubscription(r.Context(), *sub); err != nil {
+		w.Header().Set("HX-Trigger", `{"showToast": "Sync failed"}`)
+	} else {
+		w.Header().Set("HX-Trigger", `{"showToast": "Subscription synced"}`)
+	}
+	h.CalendarSubscriptionsPartial(w, r)
+}
+
+// CalendarSubscriptionUpdate changes who a subscription's events belong to.
+func (h *TemplateHandler) CalendarSubscriptionUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		h.renderToast(w, "error", "Invalid subscription ID")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		h.renderToast(w, "error", "Invalid form data")
+		return
+	}
+
+	sub, err := h.calSubStore.GetByID(id, householdID)
+	if err != nil || sub == nil {
+		h.renderToast(w, "error", "Subscription not found")
+		return
+	}
+
+	memberID := h.formMemberID(r, householdID)
+	if _, err := h.calSubStore.Update(id, householdID, sub.Name, sub.URL, memberID); err != nil {
+		h.logger.Error("update calendar subscription", "error", err)
+		h.renderToast(w, "error", "Failed to update subscription")
+		return
+	}
+	if err := h.eventStore.SetSubscriptionFamilyMember(id, householdID, memberID); err != nil {
+		h.logger.Error("reassign subscription events", "error", err)
+		h.renderToast(w, "error", "Failed to update subscription")
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "updated", 0, nil))
+
+	w.Header().Set("HX-Trigger", `{"showToast": "Subscription updated"}`)
+	h.CalendarSubscriptionsPartial(w, r)
+}
+
+// CalendarSubscriptionDelete removes a subscription and its events.
+func (h *TemplateHandler) CalendarSubscriptionDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		h.renderToast(w, "error", "Invalid subscription ID")
+		return
+	}
+
+	if sub, err := h.calSubStore.GetByID(id, householdID); err != nil || sub == nil {
+		h.renderToast(w, "error", "Subscription not found")
+		return
+	}
+
+	if err := h.calSubStore.Delete(id, householdID); err != nil {
+		h.logger.Error("delete calendar subscription", "error", err)
+		h.renderToast(w, "error", "Failed to remove subscription")
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "deleted", 0, nil))
+
+	w.Header().Set("HX-Trigger", `{"showToast": "Subscription removed"}`)
+	h.CalendarSubscriptionsPartial(w, r)
+}
+
+// CalendarImportUpload imports an uploaded .ics file.
+func (h *TemplateHandler) CalendarImportUpload(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	if err := r.ParseMultipartForm(calsync.MaxCalendarSize); err != nil {
+		h.renderToast(w, "error", "Choose an .ics file to import")
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		h.renderToast(w, "error", "Choose an .ics file to import")
+		return
+	}
+	defer file.Close()
+
+	res, err := h.calImporter.ImportFile(householdID, h.formMemberID(r, householdID), file)
+	if err != nil {
+		h.logger.Warn("import calendar file", "error", err)
+		h.renderToast(w, "error", "That file could not be imported")
+		return
+	}
+
+	if res.Changed() {
+		h.broadcast(householdID, websocket.NewMessage("calendar_event", "imported", 0, nil))
+	}
+
+	w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": "Imported %d new, %d updated events"}`, res.Created, res.Updated))
+	h.CalendarSubscriptionsPartial(w, r)
+}
+
+// CalendarEventAssignMember changes who an event belongs to. It is the one
+// edit allowed on imported events and applies to the whole series.
+func (h *TemplateHandler) CalendarEventAssignMember(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.eventStore.GetByID(id, householdID)
+	if err != nil {
+		http.Error(w, "failed to get event", http.StatusInternalServerError)
+		return
+	}
+	if event == nil {
+		http.Error(w, "event not found", http.StatusNotFound)
+		return
+	}
+
+	seriesID := id
+	if event.RecurrenceParentID != nil {
+		seriesID = *event.RecurrenceParentID
+	}
+	if err := h.eventStore.SetFamilyMember(seriesID, householdID, h.formMemberID(r, householdID)); err != nil {
+		h.logger.Error("assign event member", "error", err)
+		h.renderToast(w, "error", "Failed to update event")
+		return
+	}
+
+	event, err = h.eventStore.GetByID(id, householdID)
+	if err != nil || event == nil {
+		http.Error(w, "failed to get event", http.StatusInternalServerError)
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "updated", seriesID, nil))
+	h.calSync.Notify(householdID)
+
+	isRecurring := event.RecurrenceRule != "" || event.RecurrenceParentID != nil
+	parentID := seriesID
+	data := h.buildEventDetailData(householdID, event, isRecurring, parentID, r.FormValue("occurrence_date"))
+	w.Header().Set("HX-Trigger", `{"showToast": "Event updated"}`)
+	h.renderPartial(w, "calendar-event-detail", data)
+}
+
+// caldavCollectionView is a CalDAV collection with its member flattened for
+// the member select.
+type caldavCollectionView struct {
+	model.CalDAVCollection
+	MemberID int64
+}
+
+// CalDAVCollectionsPartial renders the CalDAV sync card content.
+func (h *TemplateHandler) CalDAVCollectionsPartial(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	collections, err := h.caldavStore.ListCollections(householdID)
+	if err != nil {
+		h.logger.Error("list caldav collections", "error", err)
+		h.renderToast(w, "error", "Failed to load CalDAV calendars")
+		return
+	}
+	members, _ := h.store.List(householdID)
+
+	views := make([]caldavCollectionView, 0, len(collections))
+	for _, coll := range collections {
+		view := caldavCollectionView{CalDAVCollection: coll}
+		if coll.FamilyMemberID != nil {
+			view.MemberID = *coll.FamilyMemberID
+		}
+		views = append(views, view)
+	}
+
+	h.renderPartial(w, "caldav-collections-form", map[string]any{
+		"Collections": views,
+		"Members":     members,
+	})
+}
+
+// CalDAVCollectionCreate connects a CalDAV collection and syncs it.
+func (h *TemplateHandler) CalDAVCollectionCreate(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderToast(w, "error", "Invalid form data")
+		return
+	}
+
+	householdID := auth.HouseholdID(r.Context())
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.renderToast(w, "error", "Name is required")
+		return
+	}
+	url, err := calsync.NormalizeCalDAVURL(r.FormValue("url"))
+	if err != nil {
+		h.renderToast(w, "error", "Enter an http or https collection URL")
+		return
+	}
+
+	coll, err := h.caldavStore.CreateCollection(householdID, name, url, strings.TrimSpace(r.FormValue("username")), r.FormValue("password"), h.formMemberID(r, householdID))
+	if err != nil {
+		h.logger.Error("create caldav collection", "error", err)
+		h.renderToast(w, "error", "Failed to connect calendar")
+		return
+	}
+
+	if _, err := h.caldavSyncer.SyncCollection(r.Context(), *coll); err != nil {
+		w.Header().Set("HX-Trigger", `{"showToast": "Calendar connected, but the first sync failed"}`)
+	} else {
+		w.Header().Set("HX-Trigger", `{"showToast": "Calendar connected"}`)
+	}
+	h.CalDAVCollectionsPartial(w, r)
+}
+
+// CalDAVCollectionSync syncs a collection immediately.
+func (h *TemplateHandler) CalDAVCollectionSync(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		h.renderToast(w, "error", "Invalid calendar ID")
+		return
+	}
+
+	coll, err := h.caldavStore.GetCollection(id, householdID)
+	if err != nil || coll == nil {
+		h.renderToast(w, "error", "Calendar not found")
+		return
+	}
+
+	if _, err := h.caldavSyncer.SyncCollection(r.Context(), *coll); err != nil {
+		w.Header().Set("HX-Trigger", `{"showToast": "Sync failed"}`)
+	} else {
+		w.Header().Set("HX-Trigger", `{"showToast": "Calendar synced"}`)
+	}
+	h.CalDAVCollectionsPartial(w, r)
+}
+
+// CalDAVCollectionUpdate changes which member a collection syncs.
+func (h *TemplateHandler) CalDAVCollectionUpdate(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		h.renderToast(w, "error", "Invalid calendar ID")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		h.renderToast(w, "error", "Invalid form data")
+		return
+	}
+
+	coll, err := h.caldavStore.GetCollection(id, householdID)
+	if err != nil || coll == nil {
+		h.renderToast(w, "error", "Calendar not found")
+		return
+	}
+
+	if _, err := h.caldavStore.UpdateCollection(id, householdID, coll.Name, coll.URL, coll.Username, "", h.formMemberID(r, householdID)); err != nil {
+		h.logger.Error("update caldav collection", "error", err)
+		h.renderToast(w, "error", "Failed to update calendar")
+		return
+	}
+
+	h.broadcast(householdID, websocket.NewMessage("calendar_event", "updated", 0, nil))
+
+	w.Header().Set("HX-Trigger", `{"showToast": "Calendar updated"}`)
+	h.CalDAVCollectionsPartial(w, r)
+}
+
+// CalDAVCollectionDelete stops syncing a collection.
+func (h *TemplateHandler) CalDAVCollectionDelete(w http.ResponseWriter, r *http.Request) {
+	householdID := auth.HouseholdID(r.Context())
+	id, err := parseIDParam(r)
+	if err != nil {
+		h.renderToast(w, "error", "Invalid calendar ID")
+		return
+	}
+
+	if err := h.caldavStore.DeleteCollection(id, householdID); err != nil {
+		h.logger.Error("delete caldav collection", "error", err)
+		h.renderToast(w, "error", "Failed to remove calendar")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": "Calendar removed"}`)
+	h.CalDAVCollectionsPartial(w, r)
+}
+
+// AppPasswordsPartial renders the signed-in user's app passwords for
+// calendar apps.
+func (h *TemplateHandler) AppPasswordsPartial(w http.ResponseWriter, r *http.Request) {
+	h.renderAppPasswords(w, r, "")
+}
+
+func (h *TemplateHandler) renderAppPasswords(w http.ResponseWriter, r *http.Request, newPassword string) {
+	householdID := auth.HouseholdID(r.Context())
+	passwords, err := h.davStore.ListAppPasswords(auth.UserID(r.Context()), householdID)
+	if err != nil {
+		h.logger.Error("list app passwords", "error", err)
+		h.renderToast(w, "error", "Failed to load app passwords")
+		return
+	}
+	h.renderPartial(w, "app-passwords-form", map[string]any{
+		"Passwords":   passwords,
+		"NewPassword": newPassword,
+		"ServerURL":   feedBaseURL(r) + davRoot,
+	})
+}
+
+// AppPasswordCreate generates an app password and shows it once.
+func (h *TemplateHandler) AppPasswordCreate(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderToast(w, "error", "Invalid form data")
+		return
+	}
+
+	householdID := auth.HouseholdID(r.Context())
+	label := strings.TrimSpace(r.FormValue("label"))
+	_, password, err := h.davStore.CreateAppPassword(auth.UserID(r.Context()), householdID, label)
+	if err != nil {
+		h.logger.Error("create app password", "error", err)
+		h.renderToast(w, "error", "Failed to create app password")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": "App password created"}`)
+	h.renderAppPasswords(w, r, password)
+}
+
+// AppPasswordRevoke deletes one of the signed-in user's app passwords.
+func (h *TemplateHandler) AppPasswordRevoke(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		h.renderToast(w, "error", "Invalid app password ID")
+		return
+	}
+
+	householdID := auth.HouseholdID(r.Context())
+	if err := h.davStore.DeleteAppPassword(id, auth.UserID(r.Context()), householdID); err != nil {
+		h.logger.Error("revoke app password", "error", err)
+		h.renderToast(w, "error", "Failed to revoke app password")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": "App password revoked"}`)
+	h.renderAppPasswords(w, r, "")
+}
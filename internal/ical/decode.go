@@ -0,0 +1,384 @@
+package ical
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotCalendar is returned by Parse when the input has no VCALENDAR.
+var ErrNotCalendar = errors.New("not an iCalendar file")
+
+// Event is a VEVENT read from an iCalendar source. Start, End, ExDates, and
+// RecurrenceID are wall-clock times in the UTC location, matching how
+// calendar events are stored. LastModified is an absolute UTC time, taken
+// from LAST-MODIFIED or else DTSTAMP, and is zero if neither is present.
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	RRule        string
+	ExDates      []time.Time
+	RecurrenceID *time.Time
+	Cancelled    bool
+	LastModified time.Time
+}
+
+// Parse reads the VEVENTs in an iCalendar stream. UTC and TZID times are
+// converted to wall-clock times in loc; floating times are kept as written.
+// Events without a DTSTART are skipped.
+func Parse(r io.Reader, loc *time.Location) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var stack []string
+	var props []property
+	seenCalendar := false
+
+	for _, raw := range lines {
+		p, ok := parseLine(raw)
+		if !ok {
+			continue
+		}
+		switch p.name {
+		case "BEGIN":
+			comp := strings.ToUpper(p.value)
+			if comp == "VCALENDAR" {
+				seenCalendar = true
+			}
+			if comp == "VEVENT" {
+				props = nil
+			}
+			stack = append(stack, comp)
+			continue
+		case "END":
+			if len(stack) == 0 {
+				continue
+			}
+			comp := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if comp == "VEVENT" {
+				if e, ok := buildEvent(props, loc); ok {
+					events = append(events, e)
+				}
+			}
+			continue
+		}
+		// Only direct VEVENT properties count; nested VALARMs are ignored.
+		if len(stack) > 0 && stack[len(stack)-1] == "VEVENT" {
+			props = append(props, p)
+		}
+	}
+
+	if !seenCalendar {
+		return nil, ErrNotCalendar
+	}
+	return events, nil
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfold joins continuation lines, which start with a space or tab.
+func unfold(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	var lines []string
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseLine splits a content line into name, parameters, and value.
+// Colons and semicolons inside quoted parameter values are not separators.
+func parseLine(line string) (property, bool) {
+	inQuote := false
+	colon := -1
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuote = !inQuote
+		case ':':
+			if !inQuote {
+				colon = i
+			}
+		}
+		if colon >= 0 {
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, false
+	}
+
+	head, value := line[:colon], line[colon+1:]
+	parts := splitUnquoted(head, ';')
+	p := property{name: strings.ToUpper(parts[0]), value: value, params: map[string]string{}}
+	for _, param := range parts[1:] {
+		k, v, ok := strings.Cut(param, "=")
+		if !ok {
+			continue
+		}
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return p, true
+}
+
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	inQuote := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			inQuote = !inQuote
+		case s[i] == sep && !inQuote:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func buildEvent(props []property, loc *time.Location) (Event, bool) {
+	var e Event
+	var hasStart, hasEnd bool
+	var duration time.Duration
+	var hasDuration bool
+	var stamp time.Time
+
+	for _, p := range props {
+		switch p.name {
+		case "UID":
+			e.UID = strings.TrimSpace(p.value)
+		case "SUMMARY":
+			e.Summary = unescapeText(p.value)
+		case "DESCRIPTION":
+			e.Description = unescapeText(p.value)
+		case "LOCATION":
+			e.Location = unescapeText(p.value)
+		case "STATUS":
+			e.Cancelled = strings.EqualFold(strings.TrimSpace(p.value), "CANCELLED")
+		case "DTSTART":
+			t, allDay, err := parseTime(p.value, p.params, loc)
+			if err != nil {
+				return Event{}, false
+			}
+			e.Start, e.AllDay, hasStart = t, allDay, true
+		case "DTEND":
+			t, _, err := parseTime(p.value, p.params, loc)
+			if err == nil {
+				e.End, hasEnd = t, true
+			}
+		case "DURATION":
+			d, err := parseDuration(p.value)
+			if err == nil {
+				duration, hasDuration = d, true
+			}
+		case "RRULE":
+			e.RRule = p.value
+		case "EXDATE":
+			for _, v := range strings.Split(p.value, ",") {
+				if t, _, err := parseTime(v, p.params, loc); err == nil {
+					e.ExDates = append(e.ExDates, t)
+				}
+			}
+		case "LAST-MODIFIED":
+			if t, err := time.Parse(utcFormat, strings.TrimSpace(p.value)); err == nil {
+				e.LastModified = t
+			}
+		case "DTSTAMP":
+			if t, err := time.Parse(utcFormat, strings.TrimSpace(p.value)); err == nil {
+				stamp = t
+			}
+		case "RECURRENCE-ID":
+			if t, _, err := parseTime(p.value, p.params, loc); err == nil {
+				e.RecurrenceID = &t
+			}
+		}
+	}
+
+	if !hasStart {
+		return Event{}, false
+	}
+	if e.LastModified.IsZero() {
+		e.LastModified = stamp
+	}
+
+	switch {
+	case hasEnd && e.End.After(e.Start):
+	case hasDuration && duration > 0:
+		e.End = e.Start.Add(duration)
+	case e.AllDay:
+		e.End = e.Start.AddDate(0, 0, 1)
+	default:
+		e.End = e.Start
+	}
+
+	if e.RRule != "" {
+		e.RRule = normalizeRRule(e.RRule, e.AllDay, loc)
+	}
+	if e.UID == "" {
+		e.UID = syntheticUID(e)
+	}
+	return e, true
+}
+
+// parseTime reads a DATE or DATE-TIME value and returns it as a wall-clock
+// time in loc, reporting whether it was a DATE.
+func parseTime(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(params["VALUE"], "DATE") || len(value) == len(dateFormat) {
+		t, err := time.Parse(dateFormat, value)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcFormat, value)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		return wallClock(t.In(loc)), false, nil
+	}
+
+	if tzid := params["TZID"]; tzid != "" {
+		if tz, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			t, err := time.ParseInLocation(localFormat, value, tz)
+			if err != nil {
+				return time.Time{}, false, err
+			}
+			return wallClock(t.In(loc)), false, nil
+		}
+		// Unknown zone names (e.g. Windows names) fall back to floating time.
+	}
+
+	t, err := time.Parse(localFormat, value)
+	return t, false, err
+}
+
+// wallClock returns t's local date and time relabelled as UTC.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// parseDuration reads an RFC 5545 duration such as "PT1H30M" or "P1W".
+func parseDuration(value string) (time.Duration, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(value), "+")
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration: %q", value)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %q", value)
+		}
+		num = ""
+		switch {
+		case c == 'W':
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case c == 'D':
+			d += time.Duration(n) * 24 * time.Hour
+		case c == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case c == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case c == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration: %q", value)
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration: %q", value)
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// normalizeRRule rewrites UNTIL as a wall-clock value in the form the
+// recurrence package reads. Other rule parts are passed through.
+func normalizeRRule(rule string, allDay bool, loc *time.Location) string {
+	parts := strings.Split(rule, ";")
+	for i, part := range parts {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || !strings.EqualFold(key, "UNTIL") {
+			continue
+		}
+		t, isDate, err := parseTime(val, nil, loc)
+		if err != nil {
+			continue
+		}
+		switch {
+		case allDay:
+			parts[i] = "UNTIL=" + t.Format(dateFormat)
+		case isDate:
+			// UNTIL is inclusive, so a DATE bound on a timed series covers the whole day.
+			parts[i] = "UNTIL=" + t.Add(24*time.Hour-time.Second).Format(utcFormat)
+		default:
+			parts[i] = "UNTIL=" + t.Format(utcFormat)
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// syntheticUID derives a stable UID for events that lack one so re-imports
+// of the same source still match.
+func syntheticUID(e Event) string {
+	sum := sha1.Sum([]byte(e.Summary + "\x00" + e.Start.Format(utcFormat)))
+	return fmt.Sprintf("%x@import", sum[:10])
+}
+
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+func unescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
@@ -0,0 +1,249 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+func parse(t *testing.T, lines ...string) []Event {
+	t.Helper()
+	src := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+	events, err := Parse(strings.NewReader(src), time.UTC)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return events
+}
+
+func TestParseTimedEvent(t *testing.T) {
+	events := parse(t,
+		"BEGIN:VEVENT",
+		"UID:abc@example.com",
+		"SUMMARY:Dentist\\, 2nd floor",
+		"DESCRIPTION:Bring forms\\nArrive early",
+		"LOCATION:Main St",
+		"DTSTART:20260205T100000",
+		"DTEND:20260205T113000",
+		"END:VEVENT",
+	)
+	if len(events) != 1 {
+		t.Fatalf("len = %d, want 1", len(events))
+	}
+	e := events[0]
+	if e.UID != "abc@example.com" || e.Summary != "Dentist, 2nd floor" || e.Location != "Main St" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if e.Description != "Bring forms\nArrive early" {
+		t.Errorf("description = %q", e.Description)
+	}
+	if !e.Start.Equal(time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC)) || !e.End.Equal(time.Date(2026, 2, 5, 11, 30, 0, 0, time.UTC)) {
+		t.Errorf("times = %v - %v", e.Start, e.End)
+	}
+	if e.AllDay {
+		t.Error("expected timed event")
+	}
+}
+
+func TestParseAllDayAndDuration(t *testing.T) {
+	events := parse(t,
+		"BEGIN:VEVENT",
+		"UID:a",
+		"SUMMARY:Holiday",
+		"DTSTART;VALUE=DATE:20260214",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:b",
+		"SUMMARY:Call",
+		"DTSTART:20260214T090000",
+		"DURATION:PT1H30M",
+		"END:VEVENT",
+	)
+	if len(events) != 2 {
+		t.Fatalf("len = %d, want 2", len(events))
+	}
+	if !events[0].AllDay || !events[0].End.Equal(time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("all-day event = %+v", events[0])
+	}
+	if !events[1].End.Equal(time.Date(2026, 2, 14, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("duration end = %v", events[1].End)
+	}
+}
+
+func TestParseConvertsZones(t *testing.T) {
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	src := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:utc",
+		"DTSTART:20260710T160000Z",
+		"DTEND:20260710T170000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:ny",
+		`DTSTART;TZID="America/New_York":20260110T090000`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	events, err := Parse(strings.NewReader(src), denver)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	// 16:00Z is 10:00 MDT in July.
+	if want := time.Date(2026, 7, 10, 10, 0, 0, 0, time.UTC); !events[0].Start.Equal(want) {
+		t.Errorf("utc start = %v, want %v", events[0].Start, want)
+	}
+	// 09:00 EST is 07:00 MST in January.
+	if want := time.Date(2026, 1, 10, 7, 0, 0, 0, time.UTC); !events[1].Start.Equal(want) {
+		t.Errorf("tzid start = %v, want %v", events[1].Start, want)
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	events := parse(t,
+		"BEGIN:VEVENT",
+		"UID:series",
+		"SUMMARY:Practice",
+		"DTSTART:20260302T170000",
+		"DTEND:20260302T180000",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20260330",
+		"EXDATE:20260309T170000,20260316T170000",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"DESCRIPTION:Reminder",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:series",
+		"SUMMARY:Practice (late)",
+		"RECURRENCE-ID:20260323T170000",
+		"DTSTART:20260323T180000",
+		"DTEND:20260323T190000",
+		"END:VEVENT",
+	)
+	if len(events) != 2 {
+		t.Fatalf("len = %d, want 2", len(events))
+	}
+	master := events[0]
+	if master.RRule != "FREQ=WEEKLY;BYDAY=MO;UNTIL=20260330T235959Z" {
+		t.Errorf("rrule = %q", master.RRule)
+	}
+	if len(master.ExDates) != 2 || !master.ExDates[1].Equal(time.Date(2026, 3, 16, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("exdates = %v", master.ExDates)
+	}
+	if master.Description != "" {
+		t.Errorf("alarm description leaked into event: %q", master.Description)
+	}
+	override := events[1]
+	if override.RecurrenceID == nil || !override.RecurrenceID.Equal(time.Date(2026, 3, 23, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("recurrence-id = %v", override.RecurrenceID)
+	}
+}
+
+func TestParseUnfoldsLines(t *testing.T) {
+	events := parse(t,
+		"BEGIN:VEVENT",
+		"UID:fold",
+		"SUMMARY:A very long",
+		"  summary",
+		"DTSTART:20260205T100000",
+		"END:VEVENT",
+	)
+	if events[0].Summary != "A very long summary" {
+		t.Errorf("summary = %q", events[0].Summary)
+	}
+}
+
+func TestParseCancelledAndMissingUID(t *testing.T) {
+	events := parse(t,
+		"BEGIN:VEVENT",
+		"SUMMARY:No UID",
+		"STATUS:CANCELLED",
+		"DTSTART:20260205T100000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:No start",
+		"END:VEVENT",
+	)
+	if len(events) != 1 {
+		t.Fatalf("len = %d, want 1 (events without DTSTART are skipped)", len(events))
+	}
+	if !events[0].Cancelled {
+		t.Error("expected cancelled")
+	}
+	again := parse(t, "BEGIN:VEVENT", "SUMMARY:No UID", "DTSTART:20260205T100000", "END:VEVENT")
+	if events[0].UID == "" || events[0].UID != again[0].UID {
+		t.Errorf("synthetic UID not stable: %q vs %q", events[0].UID, again[0].UID)
+	}
+}
+
+func TestParseLastModified(t *testing.T) {
+	events := parse(t,
+		"BEGIN:VEVENT", "UID:a", "DTSTART:20260301T100000",
+		"DTSTAMP:20260101T000000Z", "LAST-MODIFIED:20260215T083000Z", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:b", "DTSTART:20260301T100000", "DTSTAMP:20260101T000000Z", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:c", "DTSTART:20260301T100000", "END:VEVENT",
+	)
+	want := []time.Time{
+		time.Date(2026, 2, 15, 8, 30, 0, 0, time.UTC),
+		time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		{},
+	}
+	for i, e := range events {
+		if !e.LastModified.Equal(want[i]) {
+			t.Errorf("%s: LastModified = %v, want %v", e.UID, e.LastModified, want[i])
+		}
+	}
+}
+
+func TestParseRejectsNonCalendar(t *testing.T) {
+	_, err := Parse(strings.NewReader("<html></html>"), time.UTC)
+	if !errors.Is(err, ErrNotCalendar) {
+		t.Errorf("err = %v, want ErrNotCalendar", err)
+	}
+}
+
+func TestParseRoundTripsEncode(t *testing.T) {
+	var buf bytes.Buffer
+	err := Encode(&buf, "Family", []model.CalendarEvent{
+		{ID: 1, Title: "Soccer", StartTime: time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC), EndTime: time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC), RecurrenceRule: "FREQ=WEEKLY;BYDAY=MO"},
+		{ID: 2, RecurrenceParentID: ptr(int64(1)), OriginalStartTime: ptr(time.Date(2026, 3, 9, 17, 0, 0, 0, time.UTC)), Cancelled: true},
+	}, time.UTC, time.Now())
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	events, err := Parse(&buf, time.UTC)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(events) != 1 || events[0].UID != UID(1) || events[0].RRule != "FREQ=WEEKLY;BYDAY=MO" || len(events[0].ExDates) != 1 {
+		t.Errorf("round trip = %+v", events)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT15M":    15 * time.Minute,
+		"P1D":      24 * time.Hour,
+		"P1W":      7 * 24 * time.Hour,
+		"P1DT2H":   26 * time.Hour,
+		"-PT10M":   -10 * time.Minute,
+		"PT1H0M5S": time.Hour + 5*time.Second,
+	}
+	for in, want := range tests {
+		got, err := parseDuration(in)
+		if err != nil || got != want {
+			t.Errorf("parseDuration(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseDuration("1H"); err == nil {
+		t.Error("expected error for missing P")
+	}
+}
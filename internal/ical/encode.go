@@ -0,0 +1,236 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/recurrence"
+)
+
+// ContentType is the MIME type for iCalendar responses.
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	prodID        = "-//Gamwich//Gamwich//EN"
+	maxLineOctets = 75
+	dateFormat    = "20060102"
+	localFormat   = "20060102T150405"
+	utcFormat     = "20060102T150405Z"
+)
+
+// UID returns the stable iCalendar UID for a calendar event.
+func UID(eventID int64) string {
+	return fmt.Sprintf("event-%d@gamwich", eventID)
+}
+
+// Encode writes events as a VCALENDAR named name. events may mix one-off
+// events, recurring parents, and their exception rows; exceptions are emitted
+// as EXDATEs (cancelled) or RECURRENCE-ID overrides of their parent, and
+// exceptions whose parent is not in events are dropped.
+//
+// Event times are stored as wall-clock values in loc, the household's
+// timezone, so they are written with loc's TZID and a VTIMEZONE for it, or
+// in UTC if loc is UTC; all-day events use DATE values.
+func Encode(w io.Writer, name string, events []model.CalendarEvent, loc *time.Location, now time.Time) error {
+	exceptions := make(map[int64][]model.CalendarEvent)
+	for _, e := range events {
+		if e.RecurrenceParentID != nil {
+			exceptions[*e.RecurrenceParentID] = append(exceptions[*e.RecurrenceParentID], e)
+		}
+	}
+
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN", "VCALENDAR")
+	lw.line("VERSION", "2.0")
+	lw.line("PRODID", prodID)
+	lw.line("CALSCALE", "GREGORIAN")
+	lw.line("METHOD", "PUBLISH")
+	if name != "" {
+		lw.line("X-WR-CALNAME", escapeText(name))
+	}
+
+	writeTimezoneFor(lw, events, loc, now)
+	stamp := now.UTC().Format(utcFormat)
+	for _, e := range events {
+		if e.RecurrenceParentID != nil {
+			continue
+		}
+		writeSeries(lw, e, exceptions[e.ID], UID(e.ID), stamp, loc)
+	}
+
+	lw.line("END", "VCALENDAR")
+	return lw.err
+}
+
+// EncodeObject writes a single event series as a calendar object resource,
+// as stored on a CalDAV server, using the given UID. Times are written as
+// Encode writes them.
+func EncodeObject(w io.Writer, uid string, event model.CalendarEvent, exceptions []model.CalendarEvent, loc *time.Location, now time.Time) error {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN", "VCALENDAR")
+	lw.line("VERSION", "2.0")
+	lw.line("PRODID", prodID)
+	lw.line("CALSCALE", "GREGORIAN")
+	writeTimezoneFor(lw, append([]model.CalendarEvent{event}, exceptions...), loc, now)
+	writeSeries(lw, event, exceptions, uid, now.UTC().Format(utcFormat), loc)
+	lw.line("END", "VCALENDAR")
+	return lw.err
+}
+
+// writeSeries writes e and, if it recurs, its exceptions as EXDATEs and
+// RECURRENCE-ID overrides.
+func writeSeries(lw *lineWriter, e model.CalendarEvent, exceptions []model.CalendarEvent, uid, stamp string, loc *time.Location) {
+	var exdates []string
+	var overrides []model.CalendarEvent
+	if e.RecurrenceRule != "" {
+		for _, exc := range exceptions {
+			if exc.OriginalStartTime == nil {
+				continue
+			}
+			if exc.Cancelled {
+				exdates = append(exdates, formatTime(*exc.OriginalStartTime, e.AllDay, loc))
+			} else {
+				overrides = append(overrides, exc)
+			}
+		}
+	}
+
+	lw.line("BEGIN", "VEVENT")
+	writeEventProps(lw, e, uid, stamp, loc)
+	if e.RecurrenceRule != "" {
+		lw.line("RRULE", rruleValue(e, loc))
+	}
+	if len(exdates) > 0 {
+		lw.line("EXDATE"+valueParam(e.AllDay, loc), strings.Join(exdates, ","))
+	}
+	lw.line("END", "VEVENT")
+
+	for _, exc := range overrides {
+		lw.line("BEGIN", "VEVENT")
+		writeEventProps(lw, exc, uid, stamp, loc)
+		lw.line("RECURRENCE-ID"+valueParam(e.AllDay, loc), formatTime(*exc.OriginalStartTime, e.AllDay, loc))
+		lw.line("END", "VEVENT")
+	}
+}
+
+func writeEventProps(lw *lineWriter, e model.CalendarEvent, uid, stamp string, loc *time.Location) {
+	lw.line("UID", uid)
+	lw.line("DTSTAMP", stamp)
+	lw.line("DTSTART"+valueParam(e.AllDay, loc), formatTime(e.StartTime, e.AllDay, loc))
+	lw.line("DTEND"+valueParam(e.AllDay, loc), formatTime(e.EndTime, e.AllDay, loc))
+	lw.line("SUMMARY", escapeText(e.Title))
+	if e.Description != "" {
+		lw.line("DESCRIPTION", escapeText(e.Description))
+	}
+	if e.Location != "" {
+		lw.line("LOCATION", escapeText(e.Location))
+	}
+	if !e.UpdatedAt.IsZero() {
+		lw.line("LAST-MODIFIED", e.UpdatedAt.UTC().Format(utcFormat))
+	}
+}
+
+// rruleValue returns the event's recurrence rule with UNTIL rewritten to
+// match the DTSTART value type, as RFC 5545 requires: a DATE for all-day
+// events and otherwise a UTC time.
+func rruleValue(e model.CalendarEvent, loc *time.Location) string {
+	rule, err := recurrence.Parse(e.RecurrenceRule)
+	if err != nil || rule.Until == nil {
+		return e.RecurrenceRule
+	}
+	until := *rule.Until
+	rule.Until = nil
+	if e.AllDay {
+		return rule.String() + ";UNTIL=" + until.UTC().Format(dateFormat)
+	}
+	return rule.String() + ";UNTIL=" + instant(until, loc).Format(utcFormat)
+}
+
+func valueParam(allDay bool, loc *time.Location) string {
+	switch {
+	case allDay:
+		return ";VALUE=DATE"
+	case needsTZID(loc):
+		return ";TZID=" + loc.String()
+	}
+	return ""
+}
+
+// formatTime writes a wall-clock time in loc, in the form valueParam
+// announces.
+func formatTime(t time.Time, allDay bool, loc *time.Location) string {
+	switch {
+	case allDay:
+		return t.UTC().Format(dateFormat)
+	case needsTZID(loc):
+		return t.UTC().Format(localFormat)
+	}
+	return t.UTC().Format(utcFormat)
+}
+
+// instant returns the moment a wall-clock time shows in loc, in UTC.
+func instant(t time.Time, loc *time.Location) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc).UTC()
+}
+
+// writeTimezoneFor writes a VTIMEZONE for loc if events have times of day
+// written with its TZID.
+func writeTimezoneFor(lw *lineWriter, events []model.CalendarEvent, loc *time.Location, now time.Time) {
+	if !needsTZID(loc) {
+		return
+	}
+	if from, to, ok := timezoneYears(events, now); ok {
+		writeTimezone(lw, loc, from, to)
+	}
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// lineWriter writes CRLF-terminated content lines folded at 75 octets.
+// The first write error is kept and later writes are skipped.
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(name, value string) {
+	if lw.err != nil {
+		return
+	}
+	_, lw.err = io.WriteString(lw.w, fold(name+":"+value))
+}
+
+func fold(line string) string {
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit.
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
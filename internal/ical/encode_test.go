@@ -0,0 +1,307 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+func encode(t *testing.T, events []model.CalendarEvent) string {
+	t.Helper()
+	var buf bytes.Buffer
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	if err := Encode(&buf, "Family", events, time.UTC, now); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.String()
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestEncodeCalendarEnvelope(t *testing.T) {
+	out := encode(t, nil)
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"VERSION:2.0\r\n",
+		"PRODID:-//Gamwich//Gamwich//EN\r\n",
+		"X-WR-CALNAME:Family\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestEncodeTimedEvent(t *testing.T) {
+	out := encode(t, []model.CalendarEvent{{
+		ID:          7,
+		Title:       "Dentist",
+		Description: "Bring forms; arrive early, please",
+		Location:    "Main St",
+		StartTime:   time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC),
+		EndTime:     time.Date(2026, 2, 5, 11, 30, 0, 0, time.UTC),
+	}})
+
+	for _, want := range []string{
+		"UID:event-7@gamwich\r\n",
+		"DTSTAMP:20260201T120000Z\r\n",
+		"DTSTART:20260205T100000Z\r\n",
+		"DTEND:20260205T113000Z\r\n",
+		"SUMMARY:Dentist\r\n",
+		`DESCRIPTION:Bring forms\; arrive early\, please` + "\r\n",
+		"LOCATION:Main St\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "RRULE") {
+		t.Error("one-off event should not have an RRULE")
+	}
+}
+
+func TestEncodeAllDayEvent(t *testing.T) {
+	out := encode(t, []model.CalendarEvent{{
+		ID:        1,
+		Title:     "Field trip",
+		AllDay:    true,
+		StartTime: time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 2, 6, 0, 0, 0, 0, time.UTC),
+	}})
+
+	if !strings.Contains(out, "DTSTART;VALUE=DATE:20260205\r\n") {
+		t.Errorf("missing DATE start:\n%s", out)
+	}
+	if !strings.Contains(out, "DTEND;VALUE=DATE:20260206\r\n") {
+		t.Errorf("missing DATE end:\n%s", out)
+	}
+}
+
+func TestEncodeRecurringWithExceptions(t *testing.T) {
+	start := time.Date(2026, 2, 2, 16, 0, 0, 0, time.UTC)
+	parent := model.CalendarEvent{
+		ID:             10,
+		Title:          "Soccer",
+		StartTime:      start,
+		EndTime:        start.Add(time.Hour),
+		RecurrenceRule: "FREQ=WEEKLY;BYDAY=MO;UNTIL=20260330T000000Z",
+	}
+	cancelled := model.CalendarEvent{
+		ID:                 11,
+		Title:              "Soccer",
+		StartTime:          start.AddDate(0, 0, 7),
+		EndTime:            start.AddDate(0, 0, 7).Add(time.Hour),
+		RecurrenceParentID: ptr(int64(10)),
+		OriginalStartTime:  ptr(start.AddDate(0, 0, 7)),
+		Cancelled:          true,
+	}
+	moved := model.CalendarEvent{
+		ID:                 12,
+		Title:              "Soccer (moved)",
+		StartTime:          start.AddDate(0, 0, 15),
+		EndTime:            start.AddDate(0, 0, 15).Add(time.Hour),
+		RecurrenceParentID: ptr(int64(10)),
+		OriginalStartTime:  ptr(start.AddDate(0, 0, 14)),
+	}
+
+	out := encode(t, []model.CalendarEvent{parent, cancelled, moved})
+
+	for _, want := range []string{
+		"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20260330T000000Z\r\n",
+		"EXDATE:20260209T160000Z\r\n",
+		"RECURRENCE-ID:20260216T160000Z\r\n",
+		"SUMMARY:Soccer (moved)\r\n",
+		"DTSTART:20260217T160000Z\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if got := strings.Count(out, "UID:event-10@gamwich"); got != 2 {
+		t.Errorf("parent UID count = %d, want 2 (parent + override)", got)
+	}
+	if strings.Contains(out, "event-11@") || strings.Contains(out, "event-12@") {
+		t.Error("exception rows should reuse the parent UID")
+	}
+	if got := strings.Count(out, "BEGIN:VEVENT"); got != 2 {
+		t.Errorf("VEVENT count = %d, want 2", got)
+	}
+}
+
+func TestEncodeAllDayRecurrence(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	parent := model.CalendarEvent{
+		ID:             20,
+		Title:          "Trash day",
+		AllDay:         true,
+		StartTime:      start,
+		EndTime:        start.AddDate(0, 0, 1),
+		RecurrenceRule: "FREQ=WEEKLY;UNTIL=20260501",
+	}
+	skipped := model.CalendarEvent{
+		ID:                 21,
+		AllDay:             true,
+		RecurrenceParentID: ptr(int64(20)),
+		OriginalStartTime:  ptr(start.AddDate(0, 0, 7)),
+		Cancelled:          true,
+	}
+
+	out := encode(t, []model.CalendarEvent{parent, skipped})
+
+	if !strings.Contains(out, "RRULE:FREQ=WEEKLY;UNTIL=20260501\r\n") {
+		t.Errorf("all-day UNTIL should be a DATE:\n%s", out)
+	}
+	if !strings.Contains(out, "EXDATE;VALUE=DATE:20260308\r\n") {
+		t.Errorf("missing DATE EXDATE:\n%s", out)
+	}
+}
+
+func TestEncodeDropsOrphanExceptions(t *testing.T) {
+	orphan := model.CalendarEvent{
+		ID:                 30,
+		Title:              "Orphan",
+		RecurrenceParentID: ptr(int64(99)),
+		OriginalStartTime:  ptr(time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC)),
+	}
+	out := encode(t, []model.CalendarEvent{orphan})
+	if strings.Contains(out, "BEGIN:VEVENT") {
+		t.Errorf("orphan exception should be dropped:\n%s", out)
+	}
+}
+
+func TestEncodeObject(t *testing.T) {
+	parent := model.CalendarEvent{
+		ID:             40,
+		Title:          "Swim",
+		StartTime:      time.Date(2026, 2, 2, 17, 0, 0, 0, time.UTC),
+		EndTime:        time.Date(2026, 2, 2, 18, 0, 0, 0, time.UTC),
+		RecurrenceRule: "FREQ=WEEKLY",
+	}
+	cancelled := model.CalendarEvent{
+		ID:                 41,
+		RecurrenceParentID: ptr(int64(40)),
+		OriginalStartTime:  ptr(time.Date(2026, 2, 9, 17, 0, 0, 0, time.UTC)),
+		Cancelled:          true,
+	}
+
+	var buf bytes.Buffer
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	if err := EncodeObject(&buf, "swim@example.com", parent, []model.CalendarEvent{cancelled}, time.UTC, now); err != nil {
+		t.Fatalf("encode object: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"UID:swim@example.com\r\n", "RRULE:FREQ=WEEKLY\r\n", "EXDATE:20260209T170000Z\r\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	// Calendar object resources must not carry a METHOD.
+	if strings.Contains(out, "METHOD:") {
+		t.Errorf("object should not have a METHOD:\n%s", out)
+	}
+}
+
+func TestEncodeInTimezone(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	parent := model.CalendarEvent{
+		ID:             50,
+		Title:          "Practice",
+		StartTime:      time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC),
+		EndTime:        time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC),
+		RecurrenceRule: "FREQ=WEEKLY;BYDAY=MO;UNTIL=20260330T170000Z",
+	}
+	cancelled := model.CalendarEvent{
+		ID:                 51,
+		RecurrenceParentID: ptr(int64(50)),
+		OriginalStartTime:  ptr(time.Date(2026, 3, 9, 17, 0, 0, 0, time.UTC)),
+		Cancelled:          true,
+	}
+
+	var buf bytes.Buffer
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	if err := Encode(&buf, "Family", []model.CalendarEvent{parent, cancelled}, chicago, now); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VTIMEZONE\r\nTZID:America/Chicago\r\n",
+		"DTSTART;TZID=America/Chicago:20260302T170000\r\n",
+		"DTEND;TZID=America/Chicago:20260302T180000\r\n",
+		"EXDATE;TZID=America/Chicago:20260309T170000\r\n",
+		// 17:00 CDT on March 30 is 22:00 UTC.
+		"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20260330T220000Z\r\n",
+		// DST starts at 02:00 CST on March 8, 2026.
+		"BEGIN:DAYLIGHT\r\nDTSTART:20260308T020000\r\nTZOFFSETFROM:-0600\r\nTZOFFSETTO:-0500\r\nTZNAME:CDT\r\nEND:DAYLIGHT\r\n",
+		"BEGIN:STANDARD\r\nDTSTART:20261101T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0600\r\nTZNAME:CST\r\nEND:STANDARD\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "END:VTIMEZONE") > strings.Index(out, "BEGIN:VEVENT") {
+		t.Errorf("VTIMEZONE should come before the events:\n%s", out)
+	}
+
+	// A client in New York sees the practice an hour later on its clock.
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	events, err := Parse(strings.NewReader(out), ny)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if want := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC); len(events) != 1 || !events[0].Start.Equal(want) {
+		t.Errorf("parsed = %+v, want start %v", events, want)
+	}
+}
+
+func TestEncodeAllDayWithoutTimezone(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	var buf bytes.Buffer
+	err = Encode(&buf, "Family", []model.CalendarEvent{{
+		ID:        60,
+		Title:     "Holiday",
+		AllDay:    true,
+		StartTime: time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 7, 4, 0, 0, 0, 0, time.UTC),
+	}}, chicago, time.Now())
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "VTIMEZONE") || !strings.Contains(out, "DTSTART;VALUE=DATE:20260703\r\n") {
+		t.Errorf("all-day events need no VTIMEZONE:\n%s", out)
+	}
+}
+
+func TestFold(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("é", 60)
+	folded := fold(line)
+
+	for i, l := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(l) > maxLineOctets {
+			t.Errorf("line %d is %d octets, want <= %d", i, len(l), maxLineOctets)
+		}
+		if i > 0 && !strings.HasPrefix(l, " ") {
+			t.Errorf("continuation line %d does not start with a space", i)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", "")
+	if unfolded != line {
+		t.Errorf("unfolded = %q, want %q", unfolded, line)
+	}
+}
@@ -0,0 +1,108 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+// timezoneYearsAhead is how many years past the events and now a VTIMEZONE
+// lists offset changes for, so open-ended series keep their local times in
+// clients that do not know the zone by name.
+const timezoneYearsAhead = 5
+
+// needsTZID reports whether event times in loc are written with its TZID,
+// rather than in UTC.
+func needsTZID(loc *time.Location) bool {
+	return loc != time.UTC
+}
+
+// writeTimezone writes a VTIMEZONE for loc with an observance for each of
+// its offset changes from the start of year from to the end of year to.
+func writeTimezone(lw *lineWriter, loc *time.Location, from, to int) {
+	lw.line("BEGIN", "VTIMEZONE")
+	lw.line("TZID", loc.String())
+
+	t := time.Date(from, 1, 1, 0, 0, 0, 0, loc)
+	end := time.Date(to+1, 1, 1, 0, 0, 0, 0, loc)
+	_, offset := t.Zone()
+	writeObservance(lw, t, offset)
+	for t.Before(end) {
+		next := t.Add(24 * time.Hour)
+		if _, o := next.Zone(); o == offset {
+			t = next
+			continue
+		}
+		// Find the first second of the new offset.
+		lo, hi := t, next
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+			if _, o := mid.Zone(); o == offset {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		writeObservance(lw, hi, offset)
+		_, offset = hi.Zone()
+		t = hi
+	}
+
+	lw.line("END", "VTIMEZONE")
+}
+
+// writeObservance writes the offset that takes effect at t, changing from
+// the offset before it. Its DTSTART is the local time just before the change.
+func writeObservance(lw *lineWriter, t time.Time, before int) {
+	name, offset := t.Zone()
+	kind := "STANDARD"
+	if t.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	lw.line("BEGIN", kind)
+	lw.line("DTSTART", t.In(time.FixedZone("", before)).Format(localFormat))
+	lw.line("TZOFFSETFROM", formatOffset(before))
+	lw.line("TZOFFSETTO", formatOffset(offset))
+	if name != "" {
+		lw.line("TZNAME", escapeText(name))
+	}
+	lw.line("END", kind)
+}
+
+// formatOffset writes a UTC offset in seconds as ±HHMM, or ±HHMMSS if it
+// has seconds.
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	s := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset/60%60)
+	if offset%60 != 0 {
+		s += fmt.Sprintf("%02d", offset%60)
+	}
+	return s
+}
+
+// timezoneYears returns the years a VTIMEZONE for events must cover, and
+// false if none of them has a time of day.
+func timezoneYears(events []model.CalendarEvent, now time.Time) (from, to int, ok bool) {
+	for _, e := range events {
+		if e.AllDay {
+			continue
+		}
+		for _, t := range []time.Time{e.StartTime, e.EndTime} {
+			if !ok || t.Year() < from {
+				from = t.Year()
+			}
+			if !ok || t.Year() > to {
+				to = t.Year()
+			}
+			ok = true
+		}
+	}
+	if ok {
+		to = max(to, now.Year()) + timezoneYearsAhead
+	}
+	return from, to, ok
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// CalDAVCollection is an external CalDAV calendar kept in two-way sync.
+// Events sync to the collection's family member, or to events without a
+// member when FamilyMemberID is nil.
+type CalDAVCollection struct {
+	ID             int64      `json:"id"`
+	HouseholdID    int64      `json:"household_id"`
+	Name           string     `json:"name"`
+	URL            string     `json:"url"`
+	Username       string     `json:"username"`
+	Password       string     `json:"-"`
+	FamilyMemberID *int64     `json:"family_member_id"`
+	CTag           string     `json:"-"`
+	LastSyncedAt   *time.Time `json:"last_synced_at"`
+	LastError      string     `json:"last_error"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// CalDAVObject is the sync state of one event series in a collection.
+// EventID is nil once the local event has been deleted.
+type CalDAVObject struct {
+	ID           int64     `json:"id"`
+	CollectionID int64     `json:"collection_id"`
+	EventID      *int64    `json:"event_id"`
+	Href         string    `json:"href"`
+	UID          string    `json:"uid"`
+	ETag         string    `json:"etag"`
+	Dirty        bool      `json:"dirty"`
+	SyncedAt     time.Time `json:"synced_at"`
+}
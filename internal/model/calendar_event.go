@@ -10,12 +10,80 @@ type CalendarEvent struct {
 	EndTime            time.Time  `json:"end_time"`
 	AllDay             bool       `json:"all_day"`
 	FamilyMemberID     *int64     `json:"family_member_id"`
+	Attendees          []Attendee `json:"attendees"`
 	Location           string     `json:"location"`
 	RecurrenceRule     string     `json:"recurrence_rule"`
 	RecurrenceParentID *int64     `json:"recurrence_parent_id"`
 	OriginalStartTime  *time.Time `json:"original_start_time"`
 	Cancelled          bool       `json:"cancelled"`
 	ReminderMinutes    *int       `json:"reminder_minutes,omitempty"`
+	SubscriptionID     *int64     `json:"subscription_id,omitempty"`
+	SourceUID          string     `json:"source_uid,omitempty"`
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
+
+// AttendeeRole is how a family member takes part in an event.
+type AttendeeRole string
+
+const (
+	RoleAttendee AttendeeRole = "attendee"
+	RoleDriver   AttendeeRole = "driver"
+	RoleOptional AttendeeRole = "optional"
+)
+
+// Valid reports whether r is a known role.
+func (r AttendeeRole) Valid() bool {
+	return r == RoleAttendee || r == RoleDriver || r == RoleOptional
+}
+
+type Attendee struct {
+	FamilyMemberID int64        `json:"family_member_id"`
+	Role           AttendeeRole `json:"role"`
+}
+
+// PrimaryAttendee returns the member an event belongs to for coloring,
+// per-member feeds and CalDAV collections: the first attendee, or the first
+// member in any role if nobody is a plain attendee.
+func PrimaryAttendee(attendees []Attendee) *int64 {
+	for _, a := range attendees {
+		if a.Role == RoleAttendee {
+			id := a.FamilyMemberID
+			return &id
+		}
+	}
+	if len(attendees) > 0 {
+		id := attendees[0].FamilyMemberID
+		return &id
+	}
+	return nil
+}
+
+// HasAttendee reports whether the family member takes part in the event in
+// any role.
+func (e CalendarEvent) HasAttendee(familyMemberID int64) bool {
+	for _, a := range e.Attendees {
+		if a.FamilyMemberID == familyMemberID {
+			return true
+		}
+	}
+	return false
+}
+
+// Imported reports whether the event came from an .ics import or
+// subscription. Imported events are read-only apart from their assignee.
+func (e CalendarEvent) Imported() bool {
+	return e.SourceUID != ""
+}
+
+type CalendarSubscription struct {
+	ID             int64      `json:"id"`
+	HouseholdID    int64      `json:"household_id"`
+	Name           string     `json:"name"`
+	URL            string     `json:"url"`
+	FamilyMemberID *int64     `json:"family_member_id"`
+	LastSyncedAt   *time.Time `json:"last_synced_at"`
+	LastError      string     `json:"last_error"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -10,23 +10,157 @@ type ChoreArea struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Chore is a one-off or recurring household task. A one-off chore may be
+// due by DueAt; each occurrence of a recurring chore may be due by DueTime,
+// a "15:04" time of day on the household's clock. Completions of a chore
+// that RequiresApproval earn no points until a parent approves them.
+// Members younger than MinAge cannot be assigned the chore and are passed
+// over in its rotation; RotationProfiles holds the rotation members' ages
+// and roles for this.
 type Chore struct {
-	ID             int64     `json:"id"`
-	Title          string    `json:"title"`
-	Description    string    `json:"description"`
-	AreaID         *int64    `json:"area_id"`
-	Points         int       `json:"points"`
-	RecurrenceRule string    `json:"recurrence_rule"`
-	AssignedTo     *int64    `json:"assigned_to"`
-	SortOrder      int       `json:"sort_order"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID               int64           `json:"id"`
+	Title            string          `json:"title"`
+	Description      string          `json:"description"`
+	AreaID           *int64          `json:"area_id"`
+	Points           int             `json:"points"`
+	RecurrenceRule   string          `json:"recurrence_rule"`
+	AssignedTo       *int64          `json:"assigned_to"`
+	RotationMembers  []int64         `json:"rotation_members"`
+	RotationCadence  RotationCadence `json:"rotation_cadence"`
+	DueAt            *time.Time      `json:"due_at"`
+	DueTime          string          `json:"due_time"`
+	RequiresApproval bool            `json:"requires_approval"`
+	MinAge           int             `json:"min_age"`
+	SortOrder        int             `json:"sort_order"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+
+	RotationProfiles map[int64]MemberProfile `json:"-"`
+}
+
+// Rotates reports whether the chore passes between its rotation members
+// rather than staying with AssignedTo.
+func (c Chore) Rotates() bool {
+	return c.RotationCadence != "" && len(c.RotationMembers) > 0
+}
+
+// RotationCadence is when a rotating chore's turn moves to the next member.
+type RotationCadence string
+
+const (
+	RotationNone         RotationCadence = ""
+	RotationOccurrence   RotationCadence = "occurrence"
+	RotationWeekly       RotationCadence = "weekly"
+	RotationOnCompletion RotationCadence = "completion"
+)
+
+// Valid reports whether c is a known cadence. The empty cadence is valid
+// and means the chore does not rotate.
+func (c RotationCadence) Valid() bool {
+	switch c {
+	case RotationNone, RotationOccurrence, RotationWeekly, RotationOnCompletion:
+		return true
+	}
+	return false
+}
+
+type RotationEventKind string
+
+const (
+	RotationSkip RotationEventKind = "skip"
+	RotationSwap RotationEventKind = "swap"
+)
+
+// ChoreRotationEvent is a manual change to a chore's rotation. A skip passes
+// the turn to the next member and shifts every later turn along; a swap
+// gives the turn to ToMemberID, whose own next turn goes to FromMemberID.
+type ChoreRotationEvent struct {
+	ID           int64             `json:"id"`
+	ChoreID      int64             `json:"chore_id"`
+	Kind         RotationEventKind `json:"kind"`
+	Turn         int               `json:"turn"`
+	DueDate      string            `json:"due_date"`
+	FromMemberID *int64            `json:"from_member_id"`
+	ToMemberID   *int64            `json:"to_member_id"`
+	CreatedAt    time.Time         `json:"created_at"`
 }
 
+// OccurrenceStatus is the recorded outcome of one occurrence of a
+// recurring chore.
+type OccurrenceStatus string
+
+const (
+	OccurrenceDone    OccurrenceStatus = "done"
+	OccurrenceMissed  OccurrenceStatus = "missed"
+	OccurrenceExcused OccurrenceStatus = "excused"
+	OccurrenceSkipped OccurrenceStatus = "skipped"
+)
+
+// Valid reports whether s is a known status.
+func (s OccurrenceStatus) Valid() bool {
+	switch s {
+	case OccurrenceDone, OccurrenceMissed, OccurrenceExcused, OccurrenceSkipped:
+		return true
+	}
+	return false
+}
+
+// ChoreOccurrence records how one due date of a recurring chore turned out.
+// MemberID is who was responsible; CompletedBy is who did it.
+type ChoreOccurrence struct {
+	ID          int64            `json:"id"`
+	ChoreID     int64            `json:"chore_id"`
+	DueDate     string           `json:"due_date"`
+	Status      OccurrenceStatus `json:"status"`
+	MemberID    *int64           `json:"member_id"`
+	CompletedBy *int64           `json:"completed_by"`
+	Note        string           `json:"note"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// CompletionStatus is where a completion stands in the approval workflow.
+// Completions of chores that do not require approval are approved at once.
+type CompletionStatus string
+
+const (
+	CompletionPending  CompletionStatus = "pending"
+	CompletionApproved CompletionStatus = "approved"
+	CompletionRejected CompletionStatus = "rejected"
+)
+
 type ChoreCompletion struct {
-	ID           int64     `json:"id"`
-	ChoreID      int64     `json:"chore_id"`
-	CompletedBy  *int64    `json:"completed_by"`
-	PointsEarned int       `json:"points_earned"`
-	CompletedAt  time.Time `json:"completed_at"`
+	ID            int64            `json:"id"`
+	ChoreID       int64            `json:"chore_id"`
+	CompletedBy   *int64           `json:"completed_by"`
+	PointsEarned  int              `json:"points_earned"`
+	CompletedAt   time.Time        `json:"completed_at"`
+	Status        CompletionStatus `json:"status"`
+	ReviewedBy    *int64           `json:"reviewed_by"`
+	ReviewedAt    *time.Time       `json:"reviewed_at"`
+	ReviewComment string           `json:"review_comment"`
+}
+
+// TemplateChore is a chore as kept in a template, free of anything tied to
+// one household: its area is kept by name and nobody is assigned.
+type TemplateChore struct {
+	Title            string `json:"title"`
+	Description      string `json:"description"`
+	Area             string `json:"area"`
+	Points           int    `json:"points"`
+	RecurrenceRule   string `json:"recurrence_rule"`
+	DueTime          string `json:"due_time"`
+	RequiresApproval bool   `json:"requires_approval"`
+	MinAge           int    `json:"min_age"`
+}
+
+// ChoreTemplate is a set of chores a household saved to reuse. A new
+// household can start out with them by giving the ShareCode.
+type ChoreTemplate struct {
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	ShareCode   string          `json:"share_code"`
+	Chores      []TemplateChore `json:"chores"`
+	CreatedAt   time.Time       `json:"created_at"`
 }
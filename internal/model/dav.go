@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// AppPassword lets a CalDAV client sign in as a user of a household. The
+// password itself is only shown once, when it is created.
+type AppPassword struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	HouseholdID int64      `json:"household_id"`
+	Label       string     `json:"label"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// DAVResource is the name and UID a CalDAV client gave an event series.
+type DAVResource struct {
+	EventID     int64  `json:"event_id"`
+	HouseholdID int64  `json:"household_id"`
+	Name        string `json:"name"`
+	UID         string `json:"uid"`
+}
@@ -2,13 +2,146 @@ package model
 
 import "time"
 
+// FamilyMember is someone in the household. PointsPercent, if set,
+// overrides the point multiplier their role gives them.
 type FamilyMember struct {
-	ID          int64     `json:"id"`
-	Name        string    `json:"name"`
-	Color       string    `json:"color"`
-	AvatarEmoji string    `json:"avatar_emoji"`
-	HasPIN      bool      `json:"has_pin"`
-	SortOrder   int       `json:"sort_order"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	AvatarEmoji string `json:"avatar_emoji"`
+	HasPIN      bool   `json:"has_pin"`
+	MemberProfile
+	PointsPercent *int      `json:"points_percent"`
+	SortOrder     int       `json:"sort_order"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// MemberRole is whether a member is an adult, teen or child.
+type MemberRole string
+
+const (
+	RoleUnset MemberRole = ""
+	RoleAdult MemberRole = "adult"
+	RoleTeen  MemberRole = "teen"
+	RoleChild MemberRole = "child"
+)
+
+// Valid reports whether r is a known role. The empty role is valid and
+// means the member's role follows from their birthdate, if any.
+func (r MemberRole) Valid() bool {
+	switch r {
+	case RoleUnset, RoleAdult, RoleTeen, RoleChild:
+		return true
+	}
+	return false
+}
+
+// Ages at which a member without a role counts as a teen and an adult.
+const (
+	TeenAge  = 13
+	AdultAge = 18
+)
+
+// MemberProfile is a member's birthdate, "2006-01-02", and role. Either
+// may be empty.
+type MemberProfile struct {
+	Birthdate string     `json:"birthdate"`
+	Role      MemberRole `json:"role"`
+}
+
+// Age returns the member's age in whole years on today, and false if
+// their birthdate is not known.
+func (p MemberProfile) Age(today time.Time) (int, bool) {
+	born, err := time.ParseInLocation("2006-01-02", p.Birthdate, today.Location())
+	if err != nil {
+		return 0, false
+	}
+	age := today.Year() - born.Year()
+	if today.Month() < born.Month() || (today.Month() == born.Month() && today.Day() < born.Day()) {
+		age--
+	}
+	return age, true
+}
+
+// EffectiveRole returns the member's role or, if they have none, the one
+// their age puts them in. It is RoleUnset if neither is known.
+func (p MemberProfile) EffectiveRole(today time.Time) MemberRole {
+	if p.Role != RoleUnset {
+		return p.Role
+	}
+	age, ok := p.Age(today)
+	switch {
+	case !ok:
+		return RoleUnset
+	case age < TeenAge:
+		return RoleChild
+	case age < AdultAge:
+		return RoleTeen
+	}
+	return RoleAdult
+}
+
+// OldEnough reports whether the member is at least minAge on today. A
+// member with no birthdate is taken to be the youngest their role allows,
+// so a child without one is too young for any chore with a minimum age.
+// Members with neither a birthdate nor a role are old enough for anything.
+func (p MemberProfile) OldEnough(minAge int, today time.Time) bool {
+	if minAge <= 0 {
+		return true
+	}
+	if age, ok := p.Age(today); ok {
+		return age >= minAge
+	}
+	switch p.Role {
+	case RoleChild:
+		return false
+	case RoleTeen:
+		return minAge <= TeenAge
+	case RoleAdult:
+		return minAge <= AdultAge
+	}
+	return true
+}
+
+// DefaultPointsPercent is the point multiplier, as a percentage, for
+// members in a role. Younger members earn more for the same chore, so the
+// simpler chores they can do still add up.
+func DefaultPointsPercent(role MemberRole) int {
+	switch role {
+	case RoleChild:
+		return 150
+	case RoleTeen:
+		return 125
+	}
+	return 100
+}
+
+// PointMultiplier returns the percentage of a chore's points the member
+// earns on today.
+func (m FamilyMember) PointMultiplier(today time.Time) int {
+	if m.PointsPercent != nil {
+		return *m.PointsPercent
+	}
+	return DefaultPointsPercent(m.EffectiveRole(today))
+}
+
+// ScalePoints returns what the member earns for a chore worth points,
+// rounded to the nearest point.
+func (m FamilyMember) ScalePoints(points int, today time.Time) int {
+	pct := m.PointMultiplier(today)
+	if pct == 100 || points <= 0 {
+		return points
+	}
+	return (points*pct + 50) / 100
+}
+
+// CanApprove reports whether the member may approve on the kiosk what
+// only a parent should: they need a PIN and must not be a teen or child.
+func (m FamilyMember) CanApprove(today time.Time) bool {
+	if !m.HasPIN {
+		return false
+	}
+	role := m.EffectiveRole(today)
+	return role != RoleTeen && role != RoleChild
 }
@@ -2,6 +2,10 @@ package model
 
 import "time"
 
+// GroceryCategoryOther is the category items go in when no other fits.
+// Every household has it, and it cannot be renamed or deleted.
+const GroceryCategoryOther = "Other"
+
 type GroceryCategory struct {
 	ID        int64     `json:"id"`
 	Name      string    `json:"name"`
@@ -9,11 +13,35 @@ type GroceryCategory struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// GroceryList is a named shopping list, such as one per store.
+// UncheckedCount is the number of items still to buy on it. If ShopID is
+// set, its items are sorted in that store's walking order.
 type GroceryList struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	SortOrder int       `json:"sort_order"`
-	CreatedAt time.Time `json:"created_at"`
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	SortOrder      int       `json:"sort_order"`
+	Archived       bool      `json:"archived"`
+	ShopID         *int64    `json:"store_id"`
+	UncheckedCount int       `json:"unchecked_count"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// GroceryShop is a store the household shops at, with its layout.
+// Sections holds every one of the household's categories in the order
+// they are walked past in the store.
+type GroceryShop struct {
+	ID        int64         `json:"id"`
+	Name      string        `json:"name"`
+	Sections  []ShopSection `json:"sections"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// ShopSection is where a grocery category is in a store. Aisle is
+// optional and free-form, such as "12" or "Back wall".
+type ShopSection struct {
+	CategoryID int64  `json:"category_id"`
+	Category   string `json:"category"`
+	Aisle      string `json:"aisle"`
 }
 
 type GroceryItem struct {
@@ -31,3 +59,16 @@ type GroceryItem struct {
 	SortOrder int        `json:"sort_order"`
 	CreatedAt time.Time  `json:"created_at"`
 }
+
+// GroceryPurchase records an item being bought, which is when it is
+// checked off its list.
+type GroceryPurchase struct {
+	ID          int64     `json:"id"`
+	ListID      *int64    `json:"list_id"`
+	Name        string    `json:"name"`
+	Category    string    `json:"category"`
+	Quantity    string    `json:"quantity"`
+	Unit        string    `json:"unit"`
+	PurchasedBy *int64    `json:"purchased_by"`
+	PurchasedAt time.Time `json:"purchased_at"`
+}
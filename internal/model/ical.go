@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// ICalToken grants read-only access to a household's iCalendar feeds.
+type ICalToken struct {
+	ID          int64      `json:"id"`
+	HouseholdID int64      `json:"household_id"`
+	Label       string     `json:"label"`
+	CreatedBy   *int64     `json:"created_by"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
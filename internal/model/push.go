@@ -6,18 +6,24 @@ import "time"
 const (
 	NotifTypeCalendarReminder = "calendar_reminder"
 	NotifTypeChoreDue         = "chore_due"
+	NotifTypeChoreApproval    = "chore_approval"
+	NotifTypeRewardRequest    = "reward_request"
 	NotifTypeGroceryAdded     = "grocery_added"
 )
 
+// PushSubscription is a device registered for notifications.
+// FamilyMemberID is who the device belongs to, so reminders about a
+// chore reach whoever it is for.
 type PushSubscription struct {
-	ID          int64     `json:"id"`
-	UserID      int64     `json:"user_id"`
-	HouseholdID int64     `json:"household_id"`
-	Endpoint    string    `json:"endpoint"`
-	P256dhKey   string    `json:"p256dh_key"`
-	AuthKey     string    `json:"auth_key"`
-	DeviceName  string    `json:"device_name"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID             int64     `json:"id"`
+	UserID         int64     `json:"user_id"`
+	HouseholdID    int64     `json:"household_id"`
+	Endpoint       string    `json:"endpoint"`
+	P256dhKey      string    `json:"p256dh_key"`
+	AuthKey        string    `json:"auth_key"`
+	DeviceName     string    `json:"device_name"`
+	FamilyMemberID *int64    `json:"family_member_id"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 type NotificationPreference struct {
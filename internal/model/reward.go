@@ -2,27 +2,136 @@ package model
 
 import "time"
 
+// Reward is something members can spend points on. Stock, if set, is how
+// many are left; CooldownDays, if set, is how long a member must wait
+// before redeeming it again.
 type Reward struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	PointCost   int       `json:"point_cost"`
-	Active      bool      `json:"active"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID           int64     `json:"id"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	PointCost    int       `json:"point_cost"`
+	Active       bool      `json:"active"`
+	Stock        *int      `json:"stock"`
+	CooldownDays int       `json:"cooldown_days"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
+// RedemptionStatus is where a redemption stands between being requested
+// and the reward being handed over.
+type RedemptionStatus string
+
+const (
+	RedemptionRequested RedemptionStatus = "requested"
+	RedemptionApproved  RedemptionStatus = "approved"
+	RedemptionFulfilled RedemptionStatus = "fulfilled"
+	RedemptionCancelled RedemptionStatus = "cancelled"
+)
+
+// Open reports whether the redemption is still waiting on a parent.
+func (s RedemptionStatus) Open() bool {
+	return s == RedemptionRequested || s == RedemptionApproved
+}
+
+// RewardRedemption is a member's request for a reward. Its points are
+// spent when it is requested and refunded if it is cancelled. ReviewedBy
+// is the parent who approved or cancelled it, and FulfilledBy the one who
+// handed the reward over.
 type RewardRedemption struct {
-	ID          int64     `json:"id"`
-	RewardID    int64     `json:"reward_id"`
-	RedeemedBy  *int64    `json:"redeemed_by"`
-	PointsSpent int       `json:"points_spent"`
-	RedeemedAt  time.Time `json:"redeemed_at"`
+	ID          int64            `json:"id"`
+	RewardID    int64            `json:"reward_id"`
+	RedeemedBy  *int64           `json:"redeemed_by"`
+	PointsSpent int              `json:"points_spent"`
+	RedeemedAt  time.Time        `json:"redeemed_at"`
+	Status      RedemptionStatus `json:"status"`
+	ReviewedBy  *int64           `json:"reviewed_by"`
+	ReviewedAt  *time.Time       `json:"reviewed_at"`
+	FulfilledBy *int64           `json:"fulfilled_by"`
+	FulfilledAt *time.Time       `json:"fulfilled_at"`
+	Note        string           `json:"note"`
 }
 
+// PointBalance is a member's points. CentsPerPoint is what a point is
+// worth to them in money, if anything, and ValueCents what their balance
+// is worth. Goals are filled in only where the caller asks for them.
 type PointBalance struct {
-	MemberID   int64  `json:"member_id"`
-	MemberName string `json:"member_name"`
-	TotalEarned int   `json:"total_earned"`
-	TotalSpent  int   `json:"total_spent"`
-	Balance     int   `json:"balance"`
+	MemberID      int64                 `json:"member_id"`
+	MemberName    string                `json:"member_name"`
+	TotalEarned   int                   `json:"total_earned"`
+	TotalSpent    int                   `json:"total_spent"`
+	Balance       int                   `json:"balance"`
+	CentsPerPoint int                   `json:"cents_per_point"`
+	ValueCents    int                   `json:"value_cents"`
+	Goals         []SavingsGoalProgress `json:"goals,omitempty"`
+}
+
+// PointTransactionKind is what a change to a member's points was for.
+type PointTransactionKind string
+
+const (
+	PointsEarned     PointTransactionKind = "earn"
+	PointsSpent      PointTransactionKind = "spend"
+	PointsBonus      PointTransactionKind = "bonus"
+	PointsPenalty    PointTransactionKind = "penalty"
+	PointsCorrection PointTransactionKind = "correction"
+	PointsAllowance  PointTransactionKind = "allowance"
+)
+
+// Adjustment reports whether the kind is one a parent records by hand.
+func (k PointTransactionKind) Adjustment() bool {
+	return k == PointsBonus || k == PointsPenalty || k == PointsCorrection
+}
+
+// PointTransaction is one entry in a member's point ledger. Amount is
+// signed. ActorID is the member who made the change, if known, and
+// CompletionID or RedemptionID link earned and spent points to where they
+// came from.
+type PointTransaction struct {
+	ID             int64                `json:"id"`
+	FamilyMemberID int64                `json:"family_member_id"`
+	Kind           PointTransactionKind `json:"kind"`
+	Amount         int                  `json:"amount"`
+	Reason         string               `json:"reason"`
+	ActorID        *int64               `json:"actor_id"`
+	CompletionID   *int64               `json:"completion_id"`
+	RedemptionID   *int64               `json:"redemption_id"`
+	CreatedAt      time.Time            `json:"created_at"`
+}
+
+// Allowance is what a member's points are worth and the points paid into
+// their ledger each week. Weekday is the day deposits are made, StartsOn
+// the date the allowance was set up and LastDeposit the latest deposit day
+// paid, both as YYYY-MM-DD.
+type Allowance struct {
+	FamilyMemberID int64        `json:"family_member_id"`
+	CentsPerPoint  int          `json:"cents_per_point"`
+	WeeklyPoints   int          `json:"weekly_points"`
+	Weekday        time.Weekday `json:"weekday"`
+	StartsOn       string       `json:"starts_on"`
+	LastDeposit    string       `json:"last_deposit"`
+}
+
+// SavingsGoal is something a member is saving their points towards.
+type SavingsGoal struct {
+	ID             int64     `json:"id"`
+	FamilyMemberID int64     `json:"family_member_id"`
+	Title          string    `json:"title"`
+	TargetPoints   int       `json:"target_points"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SavingsGoalProgress is how far a member's balance gets them towards a
+// goal. Saved is capped at the target.
+type SavingsGoalProgress struct {
+	SavingsGoal
+	Saved   int  `json:"saved"`
+	Percent int  `json:"percent"`
+	Reached bool `json:"reached"`
+}
+
+// Progress measures a balance against the goal.
+func (g SavingsGoal) Progress(balance int) SavingsGoalProgress {
+	p := SavingsGoalProgress{SavingsGoal: g, Saved: max(0, min(balance, g.TargetPoints))}
+	p.Percent = p.Saved * 100 / g.TargetPoints
+	p.Reached = p.Saved == g.TargetPoints
+	return p
 }
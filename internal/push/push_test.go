@@ -3,6 +3,8 @@ package push
 import (
 	"encoding/base64"
 	"testing"
+
+	"github.com/dukerupert/gamwich/internal/model"
 )
 
 func TestGenerateVAPIDKeys(t *testing.T) {
@@ -58,3 +60,49 @@ func TestPayloadJSON(t *testing.T) {
 		t.Errorf("tag = %q, want %q", p.Tag, "test-tag")
 	}
 }
+
+func TestMemberRecipients(t *testing.T) {
+	kid, parent := int64(1), int64(2)
+	subs := []model.PushSubscription{
+		{ID: 1, FamilyMemberID: &kid},
+		{ID: 2, FamilyMemberID: &parent},
+		{ID: 3},
+	}
+	ids := func(subs []model.PushSubscription) []int64 {
+		var out []int64
+		for _, s := range subs {
+			out = append(out, s.ID)
+		}
+		return out
+	}
+
+	if got := ids(memberRecipients(subs, kid)); len(got) != 1 || got[0] != 1 {
+		t.Errorf("kid's chore went to %v, want [1]", got)
+	}
+	if got := ids(memberRecipients(subs, kid, parent)); len(got) != 2 {
+		t.Errorf("kid and parent went to %v, want [1 2]", got)
+	}
+	if got := ids(memberRecipients(subs, 3)); len(got) != 1 || got[0] != 3 {
+		t.Errorf("member without a device went to %v, want [3]", got)
+	}
+	if got := ids(memberRecipients(subs)); len(got) != 1 || got[0] != 3 {
+		t.Errorf("nobody in particular went to %v, want [3]", got)
+	}
+}
+
+func TestChoreSummary(t *testing.T) {
+	tests := []struct {
+		titles  []string
+		overdue int
+		want    string
+	}{
+		{[]string{"Make bed"}, 0, "Chore due today: Make bed"},
+		{[]string{"Make bed", "Feed cat"}, 1, "2 chores to do today: Make bed, Feed cat (1 overdue)"},
+		{[]string{"A", "B", "C", "D", "E"}, 0, "5 chores to do today: A, B and 3 more"},
+	}
+	for _, tt := range tests {
+		if got := choreSummary(tt.titles, tt.overdue); got != tt.want {
+			t.Errorf("choreSummary(%v, %d) = %q, want %q", tt.titles, tt.overdue, got, tt.want)
+		}
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/dukerupert/gamwich/internal/chore"
 	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/recurrence"
 	"github.com/dukerupert/gamwich/internal/store"
 )
 
@@ -20,6 +23,7 @@ type Scheduler struct {
 	events   *store.EventStore
 	chores   *store.ChoreStore
 	members  *store.FamilyMemberStore
+	settings *store.SettingsStore
 	interval time.Duration
 	logger   *slog.Logger
 	cancel   context.CancelFunc
@@ -27,13 +31,14 @@ type Scheduler struct {
 }
 
 // NewScheduler creates a notification scheduler.
-func NewScheduler(svc *Service, pushStore *store.PushStore, eventStore *store.EventStore, choreStore *store.ChoreStore, memberStore *store.FamilyMemberStore, logger *slog.Logger) *Scheduler {
+func NewScheduler(svc *Service, pushStore *store.PushStore, eventStore *store.EventStore, choreStore *store.ChoreStore, memberStore *store.FamilyMemberStore, settingsStore *store.SettingsStore, logger *slog.Logger) *Scheduler {
 	return &Scheduler{
 		service:  svc,
 		push:     pushStore,
 		events:   eventStore,
 		chores:   choreStore,
 		members:  memberStore,
+		settings: settingsStore,
 		interval: 60 * time.Second,
 		logger:   logger,
 	}
@@ -85,16 +90,24 @@ func (s *Scheduler) tick() {
 	}
 
 	for _, hid := range householdIDs {
-		s.checkCalendarReminders(hid)
-		s.checkChoreDue(hid)
+		loc, err := s.settings.Location(hid)
+		if err != nil {
+			s.logger.Error("household timezone", "household_id", hid, "error", err)
+		}
+		s.checkCalendarReminders(hid, loc)
+		s.checkChoreDue(hid, loc)
+		s.checkChoreDeadlines(hid, loc)
 	}
 }
 
-func (s *Scheduler) checkCalendarReminders(householdID int64) {
-	now := time.Now().UTC()
+// checkCalendarReminders sends reminders that fall due in the next minute.
+// Event times are wall-clock times, so the window is read off the
+// household's clock.
+func (s *Scheduler) checkCalendarReminders(householdID int64, loc *time.Location) {
+	now := recurrence.WallClock(time.Now(), loc)
 	windowEnd := now.Add(60 * time.Second)
 
-	events, err := s.events.ListUpcomingWithReminders(now, windowEnd)
+	events, err := s.events.ListUpcomingWithReminders(householdID, now, windowEnd)
 	if err != nil {
 		s.logger.Error("calendar reminders query", "error", err)
 		return
@@ -151,8 +164,196 @@ func (s *Scheduler) checkCalendarReminders(householdID int64) {
 	}
 }
 
-func (s *Scheduler) checkChoreDue(householdID int64) {
-	now := time.Now().UTC()
+// choreReminderLead is how long before a chore's deadline its assignee is
+// reminded.
+const choreReminderLead = 30 * time.Minute
+
+// dueChore is a chore that still needs doing on its current due date.
+type dueChore struct {
+	chore    model.Chore
+	status   chore.Status
+	dueDate  *time.Time
+	memberID *int64
+}
+
+// dueChores returns the household's chores that are pending or overdue as
+// of now, with who each falls to. Occurrences that were excused or skipped
+// are left out. If want is not nil, only the chores it accepts are kept;
+// it is asked before who a chore falls to is looked up, which takes more
+// queries.
+func (s *Scheduler) dueChores(householdID int64, now time.Time, want func(dueChore) bool) ([]dueChore, error) {
+	chores, err := s.chores.List(householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list chores: %w", err)
+	}
+	lastCompleted, err := s.chores.LastCompletionTimes(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []dueChore
+	for _, c := range chores {
+		var lastTime *time.Time
+		if t, ok := lastCompleted[c.ID]; ok {
+			lastTime = &t
+		}
+		status, dueDate := chore.ComputeStatus(c, lastTime, now)
+		if status != chore.StatusPending && status != chore.StatusOverdue {
+			continue
+		}
+
+		day := chore.RotationDay(dueDate, now)
+		d := dueChore{chore: c, status: status, dueDate: dueDate, memberID: c.AssignedTo}
+		if want != nil && !want(d) {
+			continue
+		}
+		if c.Rotates() {
+			completions, err := s.chores.CountCompletionsBefore(c.ID, householdID, day)
+			if err != nil {
+				return nil, fmt.Errorf("count completions: %w", err)
+			}
+			events, err := s.chores.ListRotationEvents(c.ID, householdID)
+			if err != nil {
+				return nil, fmt.Errorf("rotation events: %w", err)
+			}
+			d.memberID = chore.Responsible(c, day, completions, events).MemberID
+		}
+
+		if c.RecurrenceRule != "" {
+			recorded, err := s.chores.ListOccurrences(c.ID, householdID)
+			if err != nil {
+				return nil, fmt.Errorf("list occurrences: %w", err)
+			}
+			letOff := false
+			for _, o := range recorded {
+				if o.DueDate != chore.DateKey(day) {
+					continue
+				}
+				letOff = o.Status == model.OccurrenceExcused || o.Status == model.OccurrenceSkipped
+				if o.MemberID != nil {
+					d.memberID = o.MemberID
+				}
+			}
+			if letOff {
+				continue
+			}
+		}
+		due = append(due, d)
+	}
+	return due, nil
+}
+
+// memberRecipients picks the devices to tell about something for the
+// given members: the devices that belong to any of them or, if there are
+// no members or none of their devices are registered, the devices that
+// belong to nobody.
+func memberRecipients(subs []model.PushSubscription, memberIDs ...int64) []model.PushSubscription {
+	wanted := make(map[int64]bool, len(memberIDs))
+	for _, id := range memberIDs {
+		wanted[id] = true
+	}
+	var mine, shared []model.PushSubscription
+	for _, sub := range subs {
+		switch {
+		case sub.FamilyMemberID == nil:
+			shared = append(shared, sub)
+		case wanted[*sub.FamilyMemberID]:
+			mine = append(mine, sub)
+		}
+	}
+	if len(mine) > 0 {
+		return mine
+	}
+	return shared
+}
+
+// checkChoreDeadlines reminds whoever a chore falls to when its deadline is
+// less than choreReminderLead away.
+func (s *Scheduler) checkChoreDeadlines(householdID int64, loc *time.Location) {
+	now := time.Now().In(loc)
+
+	// Only chores whose deadline is close need to know who they fall to.
+	due, err := s.dueChores(householdID, now, func(d dueChore) bool {
+		deadline := chore.Deadline(d.chore, d.dueDate)
+		return d.status == chore.StatusPending && deadline != nil && deadline.Sub(now) <= choreReminderLead
+	})
+	if err != nil {
+		s.logger.Error("chore deadlines", "error", err)
+		return
+	}
+
+	var subs []model.PushSubscription
+	for _, d := range due {
+		deadline := chore.Deadline(d.chore, d.dueDate)
+
+		leadTime := int(choreReminderLead / time.Minute)
+		refID := fmt.Sprintf("chore-%d-%s", d.chore.ID, deadline.UTC().Format(time.RFC3339))
+		sent, err := s.push.WasSent(householdID, model.NotifTypeChoreDue, refID, leadTime)
+		if err != nil {
+			s.logger.Error("check sent status", "error", err)
+			continue
+		}
+		if sent {
+			continue
+		}
+
+		if subs == nil {
+			if subs, err = s.push.ListByHousehold(householdID); err != nil {
+				s.logger.Error("list subscriptions for chore deadlines", "error", err)
+				return
+			}
+		}
+
+		minutes := int(deadline.Sub(now).Round(time.Minute) / time.Minute)
+		payload := Payload{
+			Title: "Chore Reminder",
+			Body:  fmt.Sprintf("%s is due in %d minutes", d.chore.Title, minutes),
+			URL:   "/chores",
+			Tag:   fmt.Sprintf("chore-%d", d.chore.ID),
+		}
+
+		var memberIDs []int64
+		if d.memberID != nil {
+			memberIDs = append(memberIDs, *d.memberID)
+		}
+		for _, sub := range memberRecipients(subs, memberIDs...) {
+			enabled, _ := s.push.IsPreferenceEnabled(sub.UserID, householdID, model.NotifTypeChoreDue)
+			if !enabled {
+				continue
+			}
+
+			if err := s.service.Send(&sub, payload); err != nil {
+				if errors.Is(err, ErrExpired) {
+					s.push.DeleteByEndpoint(sub.Endpoint)
+				} else {
+					s.logger.Error("send chore deadline reminder", "error", err)
+				}
+			}
+		}
+
+		s.push.RecordSent(householdID, model.NotifTypeChoreDue, refID, leadTime)
+	}
+}
+
+// choreSummary describes the chores left to do today.
+func choreSummary(titles []string, overdue int) string {
+	var body string
+	switch {
+	case len(titles) == 1:
+		body = fmt.Sprintf("Chore due today: %s", titles[0])
+	case len(titles) <= 3:
+		body = fmt.Sprintf("%d chores to do today: %s", len(titles), strings.Join(titles, ", "))
+	default:
+		body = fmt.Sprintf("%d chores to do today: %s and %d more", len(titles), strings.Join(titles[:2], ", "), len(titles)-2)
+	}
+	if overdue > 0 {
+		body += fmt.Sprintf(" (%d overdue)", overdue)
+	}
+	return body
+}
+
+func (s *Scheduler) checkChoreDue(householdID int64, loc *time.Location) {
+	now := time.Now().In(loc)
 
 	// Only run once per day at the start of each hour (minute 0)
 	if now.Minute() != 0 {
@@ -165,46 +366,52 @@ func (s *Scheduler) checkChoreDue(householdID int64) {
 		return
 	}
 
-	chores, err := s.chores.List()
+	due, err := s.dueChores(householdID, now, nil)
 	if err != nil {
-		s.logger.Error("list chores", "error", err)
+		s.logger.Error("chores due today", "error", err)
 		return
 	}
 
-	if len(chores) == 0 {
+	if len(due) == 0 {
 		return
 	}
 
-	// Build a summary of chores with assignments
-	var summaryItems []string
-	for _, c := range chores {
-		summaryItems = append(summaryItems, c.Title)
-	}
-
 	subs, err := s.push.ListByHousehold(householdID)
 	if err != nil {
 		s.logger.Error("list subscriptions for chores", "error", err)
 		return
 	}
 
-	body := fmt.Sprintf("You have %d chores to do today", len(summaryItems))
-	if len(summaryItems) == 1 {
-		body = fmt.Sprintf("Chore due today: %s", summaryItems[0])
-	}
-
-	payload := Payload{
-		Title: "Chore Reminders",
-		Body:  body,
-		URL:   "/chores",
-		Tag:   "chore-daily",
-	}
-
 	for _, sub := range subs {
 		enabled, _ := s.push.IsPreferenceEnabled(sub.UserID, householdID, model.NotifTypeChoreDue)
 		if !enabled {
 			continue
 		}
 
+		// Devices that belong to a member hear about that member's chores;
+		// the rest hear about everything.
+		var titles []string
+		overdue := 0
+		for _, d := range due {
+			if sub.FamilyMemberID != nil && (d.memberID == nil || *d.memberID != *sub.FamilyMemberID) {
+				continue
+			}
+			titles = append(titles, d.chore.Title)
+			if d.status == chore.StatusOverdue {
+				overdue++
+			}
+		}
+		if len(titles) == 0 {
+			continue
+		}
+
+		payload := Payload{
+			Title: "Chore Reminders",
+			Body:  choreSummary(titles, overdue),
+			URL:   "/chores",
+			Tag:   "chore-daily",
+		}
+
 		if err := s.service.Send(&sub, payload); err != nil {
 			if errors.Is(err, ErrExpired) {
 				s.push.DeleteByEndpoint(sub.Endpoint)
@@ -217,6 +424,74 @@ func (s *Scheduler) checkChoreDue(householdID int64) {
 	s.push.RecordSent(householdID, model.NotifTypeChoreDue, refID, 0)
 }
 
+// SendApprovalRequest tells parents that a chore completion is waiting for
+// their approval. Called from the chore handlers, not from the scheduler.
+func (s *Scheduler) SendApprovalRequest(householdID int64, choreTitle, memberName string) {
+	body := fmt.Sprintf("%s is waiting for approval", choreTitle)
+	if memberName != "" {
+		body = fmt.Sprintf("%s finished %s and is waiting for approval", memberName, choreTitle)
+	}
+	s.sendToParents(householdID, model.NotifTypeChoreApproval, Payload{
+		Title: "Chore Approval",
+		Body:  body,
+		URL:   "/",
+		Tag:   "chore-approval",
+	})
+}
+
+// SendRewardRequest tells parents that a member has asked for a reward.
+// Called from the reward handlers, not from the scheduler.
+func (s *Scheduler) SendRewardRequest(householdID int64, rewardTitle, memberName string) {
+	body := fmt.Sprintf("%s has been requested", rewardTitle)
+	if memberName != "" {
+		body = fmt.Sprintf("%s would like %s", memberName, rewardTitle)
+	}
+	s.sendToParents(householdID, model.NotifTypeRewardRequest, Payload{
+		Title: "Reward Request",
+		Body:  body,
+		URL:   "/chores/rewards",
+		Tag:   "reward-request",
+	})
+}
+
+// sendToParents sends a payload to the parents' devices, or to the shared
+// ones if no parent has a device. Parents are the family members who can
+// approve: adults with a PIN.
+func (s *Scheduler) sendToParents(householdID int64, notifType string, payload Payload) {
+	members, err := s.members.List(householdID)
+	if err != nil {
+		s.logger.Error("parent notification list members", "type", notifType, "error", err)
+		return
+	}
+	var parents []int64
+	for _, m := range members {
+		if m.CanApprove(time.Now()) {
+			parents = append(parents, m.ID)
+		}
+	}
+
+	subs, err := s.push.ListByHousehold(householdID)
+	if err != nil {
+		s.logger.Error("parent notification list subscriptions", "type", notifType, "error", err)
+		return
+	}
+
+	for _, sub := range memberRecipients(subs, parents...) {
+		enabled, _ := s.push.IsPreferenceEnabled(sub.UserID, householdID, notifType)
+		if !enabled {
+			continue
+		}
+
+		if err := s.service.Send(&sub, payload); err != nil {
+			if errors.Is(err, ErrExpired) {
+				s.push.DeleteByEndpoint(sub.Endpoint)
+			} else {
+				s.logger.Error("send parent notification", "type", notifType, "error", err)
+			}
+		}
+	}
+}
+
 // SendGroceryNotification sends a push notification for a grocery item addition.
 // Called from the grocery handler, not from the scheduler.
 func (s *Scheduler) SendGroceryNotification(householdID, excludeUserID int64, itemName string) {
@@ -1,6 +1,9 @@
 package recurrence
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // Occurrence represents a single generated occurrence of a recurring event.
 type Occurrence struct {
@@ -8,14 +11,52 @@ type Occurrence struct {
 	End   time.Time
 }
 
+// Set is a recurrence set as defined by RFC 5545: the occurrences of Rule,
+// plus the RDates, minus the ExDates. RDates and ExDates are occurrence
+// start times; RDates do not count towards the rule's COUNT.
+//
+// IncludeStart makes the series start its first occurrence even if it does
+// not match the rule, as RFC 5545 requires of imported series. Series made
+// in the app leave it unset, so a Monday chore created on a Wednesday is
+// first due the next Monday.
+type Set struct {
+	Rule         Rule
+	RDates       []time.Time
+	ExDates      []time.Time
+	IncludeStart bool
+}
+
 // Expand generates all occurrences of a recurring event within [rangeStart, rangeEnd).
 // eventStart and eventEnd define the first occurrence's time span (used for duration).
+// Occurrences keep eventStart's time of day in its location, so a rule expanded
+// in a location with daylight saving time stays at the same local time.
 func Expand(rule Rule, eventStart, eventEnd time.Time, rangeStart, rangeEnd time.Time) []Occurrence {
+	return Set{Rule: rule}.Expand(eventStart, eventEnd, rangeStart, rangeEnd)
+}
+
+// ExpandIn is Expand for wall-clock times stored in UTC, as calendar events
+// are. The times are read as local times in loc, so occurrences are computed
+// as loc's calendar sees them, and the results are returned as wall-clock
+// times again. Each occurrence lasts as long on the wall clock as the first.
+func ExpandIn(rule Rule, eventStart, eventEnd time.Time, rangeStart, rangeEnd time.Time, loc *time.Location) []Occurrence {
+	return Set{Rule: rule}.ExpandIn(eventStart, eventEnd, rangeStart, rangeEnd, loc)
+}
+
+// Expand generates the set's occurrences within [rangeStart, rangeEnd).
+// eventStart is the first occurrence if it matches the rule or the set has
+// IncludeStart.
+func (s Set) Expand(eventStart, eventEnd time.Time, rangeStart, rangeEnd time.Time) []Occurrence {
+	rule := s.Rule
 	duration := eventEnd.Sub(eventStart)
-	var results []Occurrence
-	count := 0
 
-	iter := newIterator(rule, eventStart)
+	horizon := rangeEnd
+	if rule.Until != nil && rule.Until.Before(horizon) {
+		horizon = *rule.Until
+	}
+
+	var starts []time.Time
+	count := 0
+	iter := newIterator(rule, eventStart, horizon, s.IncludeStart)
 	for {
 		occStart := iter.next()
 		if occStart.IsZero() {
@@ -26,7 +67,7 @@ func Expand(rule Rule, eventStart, eventEnd time.Time, rangeStart, rangeEnd time
 		if rule.Until != nil && occStart.After(*rule.Until) {
 			break
 		}
-		if occStart.After(rangeEnd) || occStart.Equal(rangeEnd) {
+		if !occStart.Before(rangeEnd) {
 			break
 		}
 
@@ -35,199 +76,321 @@ func Expand(rule Rule, eventStart, eventEnd time.Time, rangeStart, rangeEnd time
 			break
 		}
 
-		occEnd := occStart.Add(duration)
-
 		// Include if occurrence overlaps with range: occStart < rangeEnd && occEnd > rangeStart
-		if occStart.Before(rangeEnd) && occEnd.After(rangeStart) {
-			results = append(results, Occurrence{Start: occStart, End: occEnd})
+		if occStart.Add(duration).After(rangeStart) {
+			starts = append(starts, occStart)
+		}
+	}
+
+	for _, rdate := range s.RDates {
+		if rdate.Before(rangeEnd) && rdate.Add(duration).After(rangeStart) {
+			starts = append(starts, rdate)
 		}
 	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
 
+	var results []Occurrence
+	for i, start := range starts {
+		if i > 0 && start.Equal(starts[i-1]) {
+			continue
+		}
+		if containsTime(s.ExDates, start) {
+			continue
+		}
+		results = append(results, Occurrence{Start: start, End: start.Add(duration)})
+	}
 	return results
 }
 
-type iterator struct {
-	rule       Rule
-	baseStart  time.Time
-	current    time.Time
-	weekDayIdx int
-	started    bool
-	count      int
-}
+// ExpandIn is Expand for wall-clock times stored in UTC; see ExpandIn.
+func (s Set) ExpandIn(eventStart, eventEnd time.Time, rangeStart, rangeEnd time.Time, loc *time.Location) []Occurrence {
+	local := func(t time.Time) time.Time {
+		return localTime(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), loc)
+	}
 
-func newIterator(rule Rule, start time.Time) *iterator {
-	return &iterator{
-		rule:      rule,
-		baseStart: start,
-		current:   start,
+	localSet := Set{Rule: s.Rule, IncludeStart: s.IncludeStart}
+	if s.Rule.Until != nil {
+		until := local(*s.Rule.Until)
+		localSet.Rule.Until = &until
 	}
+	for _, t := range s.RDates {
+		localSet.RDates = append(localSet.RDates, local(t))
+	}
+	for _, t := range s.ExDates {
+		localSet.ExDates = append(localSet.ExDates, local(t))
+	}
+
+	duration := eventEnd.Sub(eventStart)
+	occs := localSet.Expand(local(eventStart), local(eventEnd), local(rangeStart), local(rangeEnd))
+	for i := range occs {
+		occs[i].Start = WallClock(occs[i].Start, loc)
+		occs[i].End = occs[i].Start.Add(duration)
+	}
+	return occs
 }
 
-func (it *iterator) next() time.Time {
-	// Safety limit to prevent infinite loops
-	const maxIterations = 10000
+// WallClock returns the wall-clock time t shows in loc, stored in UTC the
+// way calendar event times are.
+func WallClock(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
 
-	for it.count < maxIterations {
-		it.count++
-		t := it.advance()
-		if t.IsZero() {
-			return time.Time{}
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, x := range times {
+		if x.Equal(t) {
+			return true
 		}
-		return t
 	}
-	return time.Time{}
+	return false
 }
 
-func (it *iterator) advance() time.Time {
-	switch it.rule.Freq {
-	case Daily:
-		return it.advanceDaily()
-	case Weekly:
-		if len(it.rule.ByDay) > 0 {
-			return it.advanceWeeklyByDay()
+// localTime returns the given wall-clock time in loc. A time that falls in
+// a daylight saving gap is moved forward by the length of the gap, and an
+// ambiguous time resolves to its first occurrence, as RFC 5545 specifies.
+func localTime(year int, month time.Month, day, hour, min, sec int, loc *time.Location) time.Time {
+	wall := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	_, offBefore := wall.Add(-12 * time.Hour).In(loc).Zone()
+	_, offAfter := wall.Add(12 * time.Hour).In(loc).Zone()
+
+	var best time.Time
+	for _, off := range []int{offBefore, offAfter} {
+		t := wall.Add(-time.Duration(off) * time.Second).In(loc)
+		if t.Hour() != hour || t.Minute() != min || t.Day() != day {
+			continue
 		}
-		return it.advanceWeeklySimple()
-	case Monthly:
-		return it.advanceMonthly()
-	case Yearly:
-		return it.advanceYearly()
+		if best.IsZero() || t.Before(best) {
+			best = t
+		}
+	}
+	if best.IsZero() {
+		// Skipped by a gap: read it with the offset in effect before the gap.
+		best = wall.Add(-time.Duration(offBefore) * time.Second).In(loc)
 	}
-	return time.Time{}
+	return best
 }
 
-func (it *iterator) advanceDaily() time.Time {
-	if !it.started {
-		it.started = true
-		return it.current
-	}
-	it.current = it.current.AddDate(0, 0, it.rule.Interval)
-	return it.current
+// iterator generates a rule's occurrences in order, one period (day, week,
+// month, or year, per FREQ) at a time. Dates are computed as civil dates in
+// UTC and only combined with the start's time of day and location at the end.
+type iterator struct {
+	rule      Rule
+	baseStart time.Time
+	baseDate  time.Time
+	horizon   time.Time
+	weekStart time.Weekday
+
+	byMonth    []time.Month
+	byDay      []WeekdayNum
+	byMonthDay []int
+
+	includeStart bool
+	period       int
+	pending      []time.Time
+	done         bool
 }
 
-func (it *iterator) advanceWeeklySimple() time.Time {
-	if !it.started {
-		it.started = true
-		return it.current
+// newIterator prepares an iterator that stops once a period begins after
+// horizon. Rules without BYDAY or BYMONTHDAY repeat on the start's weekday,
+// day of month, or date, depending on FREQ. If includeStart is set, start
+// comes first whether or not it matches the rule.
+func newIterator(rule Rule, start, horizon time.Time, includeStart bool) *iterator {
+	if rule.Interval < 1 {
+		rule.Interval = 1
 	}
-	it.current = it.current.AddDate(0, 0, 7*it.rule.Interval)
-	return it.current
-}
+	it := &iterator{
+		rule:       rule,
+		baseStart:  start,
+		baseDate:   time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+		horizon:    horizon,
+		weekStart:  time.Monday,
+		byMonth:    rule.ByMonth,
+		byDay:      rule.ByDay,
+		byMonthDay: rule.ByMonthDay,
 
-func (it *iterator) advanceWeeklyByDay() time.Time {
-	if !it.started {
-		it.started = true
-		// Find the week start (Monday at midnight, same time as baseStart)
-		it.current = weekStart(it.baseStart)
-		it.weekDayIdx = 0
-		return it.findNextByDay()
+		includeStart: includeStart,
+	}
+	if includeStart {
+		it.pending = []time.Time{start}
+	}
+	if rule.WeekStart != nil {
+		it.weekStart = *rule.WeekStart
 	}
 
-	it.weekDayIdx++
-	if it.weekDayIdx >= len(it.rule.ByDay) {
-		// Move to next week period
-		it.current = it.current.AddDate(0, 0, 7*it.rule.Interval)
-		it.current = weekStart(it.current)
-		it.weekDayIdx = 0
+	if len(rule.ByDay) == 0 && len(rule.ByMonthDay) == 0 {
+		switch rule.Freq {
+		case Weekly:
+			it.byDay = []WeekdayNum{{Weekday: start.Weekday()}}
+		case Monthly:
+			it.byMonthDay = []int{start.Day()}
+		case Yearly:
+			if len(rule.ByMonth) == 0 {
+				it.byMonth = []time.Month{start.Month()}
+			}
+			it.byMonthDay = []int{start.Day()}
+		}
 	}
-	return it.findNextByDay()
+	return it
 }
 
-func (it *iterator) findNextByDay() time.Time {
-	for it.weekDayIdx < len(it.rule.ByDay) {
-		day := it.rule.ByDay[it.weekDayIdx]
-		// Calculate the date for this weekday in the current week
-		mondayOfWeek := it.current
-		offset := int(day) - int(time.Monday)
-		if offset < 0 {
-			offset += 7 // Sunday
+func (it *iterator) next() time.Time {
+	for len(it.pending) == 0 {
+		if it.done {
+			return time.Time{}
 		}
-		candidate := time.Date(
-			mondayOfWeek.Year(), mondayOfWeek.Month(), mondayOfWeek.Day()+offset,
-			it.baseStart.Hour(), it.baseStart.Minute(), it.baseStart.Second(), 0,
-			it.baseStart.Location(),
-		)
+		it.fill()
+	}
+	t := it.pending[0]
+	it.pending = it.pending[1:]
+	return t
+}
 
-		// Skip dates before the event start
-		if !candidate.Before(it.baseStart) {
-			return candidate
-		}
-		it.weekDayIdx++
+// fill queues the occurrences of the next period from the start on.
+func (it *iterator) fill() {
+	first, days := it.periodDays(it.period)
+	it.period++
+	if it.at(first).After(it.horizon) || first.Year() > 9999 {
+		it.done = true
+		return
 	}
 
-	// All days in this week are before start; advance to next week
-	it.current = it.current.AddDate(0, 0, 7*it.rule.Interval)
-	it.current = weekStart(it.current)
-	it.weekDayIdx = 0
-	return it.findNextByDay()
-}
+	var matches []time.Time
+	for _, d := range days {
+		if it.matches(d) {
+			matches = append(matches, d)
+		}
+	}
+	if len(it.rule.BySetPos) > 0 {
+		matches = selectPositions(matches, it.rule.BySetPos)
+	}
 
-func weekStart(t time.Time) time.Time {
-	wd := t.Weekday()
-	offset := int(wd) - int(time.Monday)
-	if offset < 0 {
-		offset += 7
+	for _, d := range matches {
+		if d.Equal(it.baseDate) {
+			// The start is an occurrence since it matches the rule, unless
+			// it was queued already.
+			if !it.includeStart {
+				it.pending = append(it.pending, it.baseStart)
+			}
+			continue
+		}
+		if t := it.at(d); t.After(it.baseStart) {
+			it.pending = append(it.pending, t)
+		}
 	}
-	monday := t.AddDate(0, 0, -offset)
-	return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, t.Location())
 }
 
-func (it *iterator) advanceMonthly() time.Time {
-	if !it.started {
-		it.started = true
-		return it.current
+// periodDays returns the first day of period n and the days in it.
+func (it *iterator) periodDays(n int) (time.Time, []time.Time) {
+	step := n * it.rule.Interval
+	var first time.Time
+	var length int
+	switch it.rule.Freq {
+	case Daily:
+		first, length = it.baseDate.AddDate(0, 0, step), 1
+	case Weekly:
+		offset := (int(it.baseDate.Weekday()) - int(it.weekStart) + 7) % 7
+		first, length = it.baseDate.AddDate(0, 0, 7*step-offset), 7
+	case Monthly:
+		first = time.Date(it.baseDate.Year(), it.baseDate.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		length = daysInMonth(first.Year(), first.Month())
+	case Yearly:
+		first = time.Date(it.baseDate.Year()+step, time.January, 1, 0, 0, 0, 0, time.UTC)
+		length = daysInYear(first.Year())
 	}
 
-	day := it.rule.ByMonthDay
-	if day == 0 {
-		day = it.baseStart.Day()
+	days := make([]time.Time, length)
+	for i := range days {
+		days[i] = first.AddDate(0, 0, i)
 	}
+	return first, days
+}
 
-	// Advance by interval months
-	next := it.current.AddDate(0, it.rule.Interval, 0)
+func (it *iterator) matches(d time.Time) bool {
+	if len(it.byMonth) > 0 && !containsMonth(it.byMonth, d.Month()) {
+		return false
+	}
+	if len(it.byMonthDay) > 0 && !matchesMonthDay(it.byMonthDay, d) {
+		return false
+	}
+	if len(it.byDay) > 0 && !it.matchesDay(d) {
+		return false
+	}
+	return true
+}
 
-	// Set to desired day of month, clamping to last day of month
-	year, month, _ := next.Date()
-	lastDay := daysInMonth(year, month)
-	actualDay := day
-	if actualDay > lastDay {
-		// Skip months that don't have this day
-		for {
-			next = next.AddDate(0, it.rule.Interval, 0)
-			year, month, _ = next.Date()
-			lastDay = daysInMonth(year, month)
-			if day <= lastDay {
-				actualDay = day
-				break
-			}
+func containsMonth(months []time.Month, m time.Month) bool {
+	for _, x := range months {
+		if x == m {
+			return true
 		}
 	}
+	return false
+}
 
-	it.current = time.Date(
-		year, month, actualDay,
-		it.baseStart.Hour(), it.baseStart.Minute(), it.baseStart.Second(), 0,
-		it.baseStart.Location(),
-	)
-	return it.current
+func matchesMonthDay(days []int, d time.Time) bool {
+	last := daysInMonth(d.Year(), d.Month())
+	for _, md := range days {
+		if md == d.Day() || (md < 0 && last+md+1 == d.Day()) {
+			return true
+		}
+	}
+	return false
 }
 
-func (it *iterator) advanceYearly() time.Time {
-	if !it.started {
-		it.started = true
-		return it.current
+// matchesDay checks d against BYDAY. Numbered entries count within the
+// month for monthly rules and yearly rules with BYMONTH, and within the
+// year for other yearly rules.
+func (it *iterator) matchesDay(d time.Time) bool {
+	for _, wd := range it.byDay {
+		if wd.Weekday != d.Weekday() {
+			continue
+		}
+		if wd.N == 0 {
+			return true
+		}
+
+		var index, total int
+		switch {
+		case it.rule.Freq == Yearly && len(it.rule.ByMonth) == 0:
+			index, total = d.YearDay(), daysInYear(d.Year())
+		case it.rule.Freq == Monthly || it.rule.Freq == Yearly:
+			index, total = d.Day(), daysInMonth(d.Year(), d.Month())
+		default:
+			return true
+		}
+		nth := (index-1)/7 + 1
+		nthFromEnd := -((total-index)/7 + 1)
+		if wd.N == nth || wd.N == nthFromEnd {
+			return true
+		}
 	}
+	return false
+}
 
-	next := it.current.AddDate(it.rule.Interval, 0, 0)
-	// Handle Feb 29 → non-leap years: skip to next occurrence
-	if it.baseStart.Month() == time.February && it.baseStart.Day() == 29 {
-		for next.Day() != 29 {
-			next = next.AddDate(it.rule.Interval, 0, 0)
+// selectPositions applies BYSETPOS to a period's dates, keeping them in order.
+func selectPositions(dates []time.Time, positions []int) []time.Time {
+	var selected []time.Time
+	for i, d := range dates {
+		for _, pos := range positions {
+			if pos == i+1 || pos == i-len(dates) {
+				selected = append(selected, d)
+				break
+			}
 		}
 	}
+	return selected
+}
 
-	it.current = next
-	return it.current
+// at combines a civil date with the start's time of day and location.
+func (it *iterator) at(d time.Time) time.Time {
+	s := it.baseStart
+	return localTime(d.Year(), d.Month(), d.Day(), s.Hour(), s.Minute(), s.Second(), s.Location())
 }
 
 func daysInMonth(year int, month time.Month) int {
 	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
 }
+
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
@@ -3,6 +3,7 @@ package recurrence
 import (
 	"testing"
 	"time"
+	_ "time/tzdata"
 )
 
 func TestParseFreqOnly(t *testing.T) {
@@ -49,7 +50,7 @@ func TestParseWithByDay(t *testing.T) {
 	if len(r.ByDay) != 3 {
 		t.Fatalf("ByDay len = %d, want 3", len(r.ByDay))
 	}
-	want := []time.Weekday{time.Monday, time.Wednesday, time.Friday}
+	want := []WeekdayNum{{Weekday: time.Monday}, {Weekday: time.Wednesday}, {Weekday: time.Friday}}
 	for i, d := range r.ByDay {
 		if d != want[i] {
 			t.Errorf("ByDay[%d] = %v, want %v", i, d, want[i])
@@ -101,7 +102,7 @@ func TestParseErrors(t *testing.T) {
 }
 
 func TestRuleString(t *testing.T) {
-	r := Rule{Freq: Weekly, Interval: 2, ByDay: []time.Weekday{time.Monday, time.Wednesday}}
+	r := Rule{Freq: Weekly, Interval: 2, ByDay: []WeekdayNum{{Weekday: time.Monday}, {Weekday: time.Wednesday}}}
 	got := r.String()
 	want := "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"
 	if got != want {
@@ -394,3 +395,443 @@ func TestExpandPreservesDuration(t *testing.T) {
 		}
 	}
 }
+
+func TestParseByParts(t *testing.T) {
+	r, err := Parse("FREQ=MONTHLY;BYMONTH=1,6;BYDAY=2TU,-1FR;BYMONTHDAY=-3,15;BYSETPOS=1,-1;WKST=SU")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if len(r.ByMonth) != 2 || r.ByMonth[0] != time.January || r.ByMonth[1] != time.June {
+		t.Errorf("ByMonth = %v", r.ByMonth)
+	}
+	wantDays := []WeekdayNum{{Weekday: time.Tuesday, N: 2}, {Weekday: time.Friday, N: -1}}
+	if len(r.ByDay) != 2 || r.ByDay[0] != wantDays[0] || r.ByDay[1] != wantDays[1] {
+		t.Errorf("ByDay = %v, want %v", r.ByDay, wantDays)
+	}
+	if len(r.ByMonthDay) != 2 || r.ByMonthDay[0] != -3 || r.ByMonthDay[1] != 15 {
+		t.Errorf("ByMonthDay = %v", r.ByMonthDay)
+	}
+	if len(r.BySetPos) != 2 || r.BySetPos[0] != 1 || r.BySetPos[1] != -1 {
+		t.Errorf("BySetPos = %v", r.BySetPos)
+	}
+	if r.WeekStart == nil || *r.WeekStart != time.Sunday {
+		t.Errorf("WeekStart = %v, want Sunday", r.WeekStart)
+	}
+}
+
+func TestParseByPartErrors(t *testing.T) {
+	tests := []string{
+		"FREQ=DAILY;COUNT=5;UNTIL=20260301T000000Z",
+		"FREQ=MONTHLY;BYSETPOS=1",
+		"FREQ=WEEKLY;BYDAY=2TU",
+		"FREQ=MONTHLY;BYDAY=6TU",
+		"FREQ=MONTHLY;BYDAY=0TU",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=YEARLY;BYMONTH=13",
+		"FREQ=YEARLY;BYMONTH=-1",
+		"FREQ=MONTHLY;BYDAY=MO;BYSETPOS=0",
+		"FREQ=WEEKLY;WKST=XX",
+	}
+
+	for _, input := range tests {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) should error", input)
+		}
+	}
+}
+
+func TestRuleStringRoundTripByParts(t *testing.T) {
+	inputs := []string{
+		"FREQ=MONTHLY;BYDAY=2TU",
+		"FREQ=MONTHLY;BYDAY=-1FR",
+		"FREQ=MONTHLY;INTERVAL=2;BYDAY=1SU,-1SU;COUNT=10",
+		"FREQ=MONTHLY;BYMONTHDAY=1,-1",
+		"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"FREQ=YEARLY;BYMONTH=6,7;BYDAY=TH",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,SU;WKST=SU",
+		"FREQ=DAILY;UNTIL=19971224T000000Z",
+	}
+
+	for _, input := range inputs {
+		r, err := Parse(input)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", input, err)
+			continue
+		}
+		if got := r.String(); got != input {
+			t.Errorf("roundtrip %q -> %q", input, got)
+		}
+	}
+
+	// Defaults are dropped.
+	r, _ := Parse("freq=weekly;interval=1;wkst=mo;byday=mo")
+	if got := r.String(); got != "FREQ=WEEKLY;BYDAY=MO" {
+		t.Errorf("String() = %q, want FREQ=WEEKLY;BYDAY=MO", got)
+	}
+}
+
+func TestDescribeByParts(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"FREQ=DAILY;INTERVAL=3", "Repeats every 3 days"},
+		{"FREQ=WEEKLY;INTERVAL=3", "Repeats every 3 weeks"},
+		{"FREQ=MONTHLY;INTERVAL=6", "Repeats every 6 months"},
+		{"FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", "Repeats every weekday"},
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=10", "Repeats every weekday, 10 times"},
+		{"FREQ=DAILY;BYMONTH=1,3", "Repeats daily in January and March"},
+		{"FREQ=MONTHLY;BYDAY=2TU", "Repeats monthly on the 2nd Tuesday"},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "Repeats monthly on the last Friday"},
+		{"FREQ=MONTHLY;INTERVAL=2;BYDAY=1SU,-1SU", "Repeats every 2 months on the 1st and last Sunday"},
+		{"FREQ=MONTHLY;BYDAY=-2MO", "Repeats monthly on the 2nd to last Monday"},
+		{"FREQ=MONTHLY;BYDAY=TU", "Repeats monthly on Tuesdays"},
+		{"FREQ=MONTHLY;BYMONTHDAY=2,15", "Repeats monthly on the 2nd and 15th"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1", "Repeats monthly on the 1st and the last day"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-3", "Repeats monthly on the 3rd to last day"},
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", "Repeats monthly on the last weekday"},
+		{"FREQ=MONTHLY;BYDAY=TU,WE,TH;BYSETPOS=3", "Repeats monthly on the 3rd Tue, Wed, or Thu"},
+		{"FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13", "Repeats monthly on Fridays falling on the 13th"},
+		{"FREQ=YEARLY;BYMONTH=9;BYDAY=1MO", "Repeats yearly on the 1st Monday of September"},
+		{"FREQ=YEARLY;BYMONTH=3;BYDAY=TH", "Repeats yearly on Thursdays in March"},
+		{"FREQ=YEARLY;BYMONTH=6,7", "Repeats yearly in June and July"},
+		{"FREQ=YEARLY;BYMONTH=1,2,3;INTERVAL=2", "Repeats every 2 years in January, February, and March"},
+		{"FREQ=YEARLY;BYDAY=20MO", "Repeats yearly on the 20th Monday of the year"},
+		{"FREQ=WEEKLY;COUNT=1", "Repeats weekly, once"},
+		{"FREQ=DAILY;UNTIL=20060102T000000Z", "Repeats daily, until Jan 2, 2006"},
+	}
+
+	for _, tt := range tests {
+		r, err := Parse(tt.rule)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.rule, err)
+			continue
+		}
+		if got := r.Describe(); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
+
+// TestExpandRFC5545 checks the examples from RFC 5545 section 3.8.5.3 that
+// use supported rule parts. Every example starts at 09:00 America/New_York
+// and stays at 09:00 local time across daylight saving changes. Rules
+// without an end list only their first occurrences.
+func TestExpandRFC5545(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	date := func(s string) time.Time {
+		tm, err := time.ParseInLocation("20060102T150405", s+"T090000", ny)
+		if err != nil {
+			t.Fatalf("parse %q: %v", s, err)
+		}
+		return tm
+	}
+
+	tests := []struct {
+		name    string
+		start   string
+		rule    string
+		exdates []string
+		want    []string
+		total   int // number of occurrences, if the rule ends
+	}{
+		{
+			name: "daily for 10 occurrences", start: "19970902", rule: "FREQ=DAILY;COUNT=10",
+			want:  []string{"19970902", "19970903", "19970904", "19970905", "19970906", "19970907", "19970908", "19970909", "19970910", "19970911"},
+			total: 10,
+		},
+		{
+			name: "daily until December 24, 1997", start: "19970902", rule: "FREQ=DAILY;UNTIL=19971224T000000Z",
+			want:  []string{"19970902", "19970903", "19970904"},
+			total: 113,
+		},
+		{
+			name: "every 10 days, 5 occurrences", start: "19970902", rule: "FREQ=DAILY;INTERVAL=10;COUNT=5",
+			want:  []string{"19970902", "19970912", "19970922", "19971002", "19971012"},
+			total: 5,
+		},
+		{
+			name: "every day in January, for 3 years", start: "19980101",
+			rule:  "FREQ=YEARLY;UNTIL=20000131T140000Z;BYMONTH=1;BYDAY=SU,MO,TU,WE,TH,FR,SA",
+			want:  []string{"19980101", "19980102", "19980103"},
+			total: 93,
+		},
+		{
+			name: "every day in January, for 3 years (daily)", start: "19980101",
+			rule:  "FREQ=DAILY;UNTIL=20000131T140000Z;BYMONTH=1",
+			want:  []string{"19980101", "19980102", "19980103"},
+			total: 93,
+		},
+		{
+			name: "weekly for 10 occurrences", start: "19970902", rule: "FREQ=WEEKLY;COUNT=10",
+			want:  []string{"19970902", "19970909", "19970916", "19970923", "19970930", "19971007", "19971014", "19971021", "19971028", "19971104"},
+			total: 10,
+		},
+		{
+			name: "weekly on Tuesday and Thursday for five weeks", start: "19970902",
+			rule:  "FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH",
+			want:  []string{"19970902", "19970904", "19970909", "19970911", "19970916", "19970918", "19970923", "19970925", "19970930", "19971002"},
+			total: 10,
+		},
+		{
+			name: "every other week on Monday, Wednesday, and Friday", start: "19970901",
+			rule: "FREQ=WEEKLY;INTERVAL=2;UNTIL=19971224T000000Z;WKST=SU;BYDAY=MO,WE,FR",
+			want: []string{
+				"19970901", "19970903", "19970905", "19970915", "19970917", "19970919", "19970929",
+				"19971001", "19971003", "19971013", "19971015", "19971017", "19971027", "19971029", "19971031",
+				"19971110", "19971112", "19971114", "19971124", "19971126", "19971128",
+				"19971208", "19971210", "19971212", "19971222",
+			},
+			total: 25,
+		},
+		{
+			name: "every other week on Tuesday and Thursday, for 8 occurrences", start: "19970902",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;COUNT=8;WKST=SU;BYDAY=TU,TH",
+			want:  []string{"19970902", "19970904", "19970916", "19970918", "19970930", "19971002", "19971014", "19971016"},
+			total: 8,
+		},
+		{
+			name: "monthly on the first Friday for 10 occurrences", start: "19970905", rule: "FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			want:  []string{"19970905", "19971003", "19971107", "19971205", "19980102", "19980206", "19980306", "19980403", "19980501", "19980605"},
+			total: 10,
+		},
+		{
+			name: "every other month on the first and last Sunday", start: "19970907",
+			rule:  "FREQ=MONTHLY;INTERVAL=2;COUNT=10;BYDAY=1SU,-1SU",
+			want:  []string{"19970907", "19970928", "19971102", "19971130", "19980104", "19980125", "19980301", "19980329", "19980503", "19980531"},
+			total: 10,
+		},
+		{
+			name: "monthly on the second-to-last Monday for 6 months", start: "19970922", rule: "FREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+			want:  []string{"19970922", "19971020", "19971117", "19971222", "19980119", "19980216"},
+			total: 6,
+		},
+		{
+			name: "monthly on the third-to-last day of the month", start: "19970928", rule: "FREQ=MONTHLY;BYMONTHDAY=-3",
+			want: []string{"19970928", "19971029", "19971128", "19971229", "19980129", "19980226"},
+		},
+		{
+			name: "monthly on the 2nd and 15th for 10 occurrences", start: "19970902", rule: "FREQ=MONTHLY;COUNT=10;BYMONTHDAY=2,15",
+			want:  []string{"19970902", "19970915", "19971002", "19971015", "19971102", "19971115", "19971202", "19971215", "19980102", "19980115"},
+			total: 10,
+		},
+		{
+			name: "monthly on the first and last day for 10 occurrences", start: "19970930", rule: "FREQ=MONTHLY;COUNT=10;BYMONTHDAY=1,-1",
+			want:  []string{"19970930", "19971001", "19971031", "19971101", "19971130", "19971201", "19971231", "19980101", "19980131", "19980201"},
+			total: 10,
+		},
+		{
+			name: "every 18 months on the 10th through 15th", start: "19970910",
+			rule:  "FREQ=MONTHLY;INTERVAL=18;COUNT=10;BYMONTHDAY=10,11,12,13,14,15",
+			want:  []string{"19970910", "19970911", "19970912", "19970913", "19970914", "19970915", "19990310", "19990311", "19990312", "19990313"},
+			total: 10,
+		},
+		{
+			name: "every Tuesday, every other month", start: "19970902", rule: "FREQ=MONTHLY;INTERVAL=2;BYDAY=TU",
+			want: []string{"19970902", "19970909", "19970916", "19970923", "19970930", "19971104", "19971111", "19971118", "19971125", "19980106"},
+		},
+		{
+			name: "yearly in June and July for 10 occurrences", start: "19970610", rule: "FREQ=YEARLY;COUNT=10;BYMONTH=6,7",
+			want:  []string{"19970610", "19970710", "19980610", "19980710", "19990610", "19990710", "20000610", "20000710", "20010610", "20010710"},
+			total: 10,
+		},
+		{
+			name: "every other year in January, February, and March", start: "19970310",
+			rule:  "FREQ=YEARLY;INTERVAL=2;COUNT=10;BYMONTH=1,2,3",
+			want:  []string{"19970310", "19990110", "19990210", "19990310", "20010110", "20010210", "20010310", "20030110", "20030210", "20030310"},
+			total: 10,
+		},
+		{
+			name: "every 20th Monday of the year", start: "19970519", rule: "FREQ=YEARLY;BYDAY=20MO",
+			want: []string{"19970519", "19980518", "19990517"},
+		},
+		{
+			name: "every Thursday in March", start: "19970313", rule: "FREQ=YEARLY;BYMONTH=3;BYDAY=TH",
+			want: []string{"19970313", "19970320", "19970327", "19980305", "19980312", "19980319", "19980326", "19990304", "19990311", "19990318", "19990325"},
+		},
+		{
+			name: "every Thursday, but only during June, July, and August", start: "19970605", rule: "FREQ=YEARLY;BYDAY=TH;BYMONTH=6,7,8",
+			want: []string{
+				"19970605", "19970612", "19970619", "19970626", "19970703", "19970710", "19970717", "19970724", "19970731",
+				"19970807", "19970814", "19970821", "19970828", "19980604",
+			},
+		},
+		{
+			name: "every Friday the 13th", start: "19970902", rule: "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			exdates: []string{"19970902"},
+			want:    []string{"19980213", "19980313", "19981113", "19990813", "20001013"},
+		},
+		{
+			name: "the first Saturday that follows the first Sunday of the month", start: "19970913",
+			rule: "FREQ=MONTHLY;BYDAY=SA;BYMONTHDAY=7,8,9,10,11,12,13",
+			want: []string{"19970913", "19971011", "19971108", "19971213", "19980110", "19980207", "19980307", "19980411", "19980509", "19980613"},
+		},
+		{
+			name: "U.S. Presidential Election day", start: "19961105",
+			rule: "FREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8",
+			want: []string{"19961105", "20001107", "20041102"},
+		},
+		{
+			name: "the third instance of Tuesday, Wednesday, or Thursday", start: "19970904",
+			rule:  "FREQ=MONTHLY;COUNT=3;BYDAY=TU,WE,TH;BYSETPOS=3",
+			want:  []string{"19970904", "19971007", "19971106"},
+			total: 3,
+		},
+		{
+			name: "the second-to-last weekday of the month", start: "19970929",
+			rule: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-2",
+			want: []string{"19970929", "19971030", "19971127", "19971230", "19980129", "19980226", "19980330"},
+		},
+		{
+			name: "week starting on Monday", start: "19970805", rule: "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			want:  []string{"19970805", "19970810", "19970819", "19970824"},
+			total: 4,
+		},
+		{
+			name: "week starting on Sunday", start: "19970805", rule: "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			want:  []string{"19970805", "19970817", "19970819", "19970831"},
+			total: 4,
+		},
+		{
+			name: "invalid dates are skipped", start: "20070115", rule: "FREQ=MONTHLY;BYMONTHDAY=15,30;COUNT=5",
+			want:  []string{"20070115", "20070130", "20070215", "20070315", "20070330"},
+			total: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.rule, err)
+			}
+			set := Set{Rule: rule}
+			for _, ex := range tt.exdates {
+				set.ExDates = append(set.ExDates, date(ex))
+			}
+			start := date(tt.start)
+			occs := set.Expand(start, start.Add(time.Hour), start, date("20100101"))
+
+			if tt.total > 0 && len(occs) != tt.total {
+				t.Errorf("got %d occurrences, want %d", len(occs), tt.total)
+			}
+			if len(occs) < len(tt.want) {
+				t.Fatalf("got %d occurrences, want at least %d", len(occs), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !occs[i].Start.Equal(date(want)) {
+					t.Errorf("occ[%d] = %v, want %s 09:00", i, occs[i].Start, want)
+				}
+			}
+			for i, occ := range occs {
+				if occ.Start.Hour() != 9 || occ.End.Sub(occ.Start) != time.Hour {
+					t.Errorf("occ[%d] = %v-%v, want 09:00 for an hour", i, occ.Start, occ.End)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandDaylightSavingGap(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	// 2:30am does not exist on March 8, 2026; RFC 5545 moves it to 3:30 EDT.
+	rule, _ := Parse("FREQ=DAILY;COUNT=3")
+	start := time.Date(2026, 3, 7, 2, 30, 0, 0, ny)
+	occs := Expand(rule, start, start.Add(30*time.Minute), start, start.AddDate(0, 0, 7))
+	if len(occs) != 3 {
+		t.Fatalf("got %d occurrences, want 3", len(occs))
+	}
+	want := []time.Time{
+		time.Date(2026, 3, 7, 7, 30, 0, 0, time.UTC), // 2:30 EST
+		time.Date(2026, 3, 8, 7, 30, 0, 0, time.UTC), // 3:30 EDT
+		time.Date(2026, 3, 9, 6, 30, 0, 0, time.UTC), // 2:30 EDT
+	}
+	for i, occ := range occs {
+		if !occ.Start.Equal(want[i]) {
+			t.Errorf("occ[%d] = %v, want %v", i, occ.Start, want[i].In(ny))
+		}
+	}
+}
+
+func TestExpandInKeepsWallClock(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	// Wall-clock times stored as UTC, across the March 8, 2026 change.
+	rule, _ := Parse("FREQ=DAILY")
+	start := d(2026, 3, 6, 9)
+	occs := ExpandIn(rule, start, d(2026, 3, 6, 10), d(2026, 3, 6, 0), d(2026, 3, 11, 0), ny)
+	if len(occs) != 5 {
+		t.Fatalf("got %d occurrences, want 5", len(occs))
+	}
+	for i, occ := range occs {
+		if !occ.Start.Equal(d(2026, 3, 6+i, 9)) || !occ.End.Equal(d(2026, 3, 6+i, 10)) {
+			t.Errorf("occ[%d] = %v-%v, want 09:00-10:00 on Mar %d", i, occ.Start, occ.End, 6+i)
+		}
+	}
+
+	// An overnight event keeps its wall-clock end, not its elapsed duration.
+	occs = ExpandIn(rule, d(2026, 3, 7, 22), d(2026, 3, 8, 6), d(2026, 3, 7, 0), d(2026, 3, 9, 0), ny)
+	if len(occs) != 2 || !occs[0].End.Equal(d(2026, 3, 8, 6)) || !occs[1].End.Equal(d(2026, 3, 9, 6)) {
+		t.Errorf("overnight occurrences = %v", occs)
+	}
+}
+
+func TestSetExpandInWithDates(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	rule, _ := Parse("FREQ=WEEKLY;COUNT=4")
+	set := Set{
+		Rule:    rule,
+		ExDates: []time.Time{d(2026, 3, 10, 18)},
+		RDates:  []time.Time{d(2026, 3, 12, 18), d(2026, 3, 17, 18)},
+	}
+	occs := set.ExpandIn(d(2026, 3, 3, 18), d(2026, 3, 3, 19), d(2026, 3, 1, 0), d(2026, 4, 1, 0), ny)
+
+	// COUNT applies before EXDATE, and an RDATE that repeats an occurrence
+	// is listed once.
+	want := []time.Time{d(2026, 3, 3, 18), d(2026, 3, 12, 18), d(2026, 3, 17, 18), d(2026, 3, 24, 18)}
+	if len(occs) != len(want) {
+		t.Fatalf("got %d occurrences, want %d: %v", len(occs), len(want), occs)
+	}
+	for i, occ := range occs {
+		if !occ.Start.Equal(want[i]) {
+			t.Errorf("occ[%d] = %v, want %v", i, occ.Start, want[i])
+		}
+	}
+}
+
+func TestSetExpandIncludeStart(t *testing.T) {
+	rule, _ := Parse("FREQ=WEEKLY;BYDAY=MO")
+	// Wednesday, with sub-second precision like a created_at timestamp.
+	start := time.Date(2026, 3, 4, 9, 0, 0, 500, time.UTC)
+	end := d(2026, 3, 31, 0)
+
+	occs := Set{Rule: rule}.Expand(start, start.Add(time.Hour), start, end)
+	if len(occs) == 0 || !occs[0].Start.Equal(time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("without IncludeStart, first occurrence = %v, want Monday Mar 9", occs)
+	}
+
+	occs = Set{Rule: rule, IncludeStart: true}.Expand(start, start.Add(time.Hour), start, end)
+	if len(occs) < 2 || !occs[0].Start.Equal(start) || occs[1].Start.Weekday() != time.Monday {
+		t.Errorf("with IncludeStart, occurrences = %v, want the start then Mondays", occs)
+	}
+
+	// A start that matches the rule is its first occurrence either way.
+	monday := time.Date(2026, 3, 2, 9, 0, 0, 500, time.UTC)
+	occs = Set{Rule: rule}.Expand(monday, monday.Add(time.Hour), monday, end)
+	if len(occs) == 0 || !occs[0].Start.Equal(monday) {
+		t.Errorf("first occurrence = %v, want the start %v", occs, monday)
+	}
+}
@@ -50,16 +50,38 @@ var dayAbbrev = map[time.Weekday]string{
 	time.Saturday:  "SA",
 }
 
+// WeekdayNum is a BYDAY entry. N numbers the weekday within the month, or
+// within the year for yearly rules without BYMONTH: 2 is the second Tuesday
+// for "2TU" and -1 the last Friday for "-1FR". N is 0 for every matching
+// weekday.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return dayAbbrev[w.Weekday]
+	}
+	return strconv.Itoa(w.N) + dayAbbrev[w.Weekday]
+}
+
 type Rule struct {
 	Freq       Freq
-	Interval   int            // default 1; 2 = biweekly when Freq=Weekly
-	ByDay      []time.Weekday // for WEEKLY: which days (empty = same weekday as start)
-	ByMonthDay int            // for MONTHLY: day of month (0 = same as start)
-	Count      int            // max occurrences (0 = unlimited)
-	Until      *time.Time     // stop after this date (nil = no limit)
+	Interval   int           // default 1; 2 = biweekly when Freq=Weekly
+	ByMonth    []time.Month  // limit to these months
+	ByDay      []WeekdayNum  // which weekdays (empty = same weekday as start for WEEKLY)
+	ByMonthDay []int         // days of the month; negative counts back from the last day (empty = same as start for MONTHLY)
+	BySetPos   []int         // keep only these positions among each period's occurrences; negative counts from the end
+	WeekStart  *time.Weekday // WKST (nil = Monday)
+	Count      int           // max occurrences (0 = unlimited)
+	Until      *time.Time    // stop after this date (nil = no limit)
 }
 
 // Parse parses an RRULE string like "FREQ=WEEKLY;BYDAY=MO,WE;INTERVAL=2".
+// It supports the DAILY, WEEKLY, MONTHLY, and YEARLY frequencies with the
+// INTERVAL, COUNT, UNTIL, BYMONTH, BYDAY, BYMONTHDAY, BYSETPOS, and WKST
+// parts of RFC 5545.
 func Parse(rule string) (Rule, error) {
 	if rule == "" {
 		return Rule{}, fmt.Errorf("empty rule")
@@ -68,7 +90,7 @@ func Parse(rule string) (Rule, error) {
 	r := Rule{Interval: 1}
 	var hasFreq bool
 
-	parts := strings.Split(rule, ";")
+	parts := strings.Split(strings.ToUpper(rule), ";")
 	for _, part := range parts {
 		kv := strings.SplitN(part, "=", 2)
 		if len(kv) != 2 {
@@ -92,22 +114,45 @@ func Parse(rule string) (Rule, error) {
 			}
 			r.Interval = n
 
+		case "BYMONTH":
+			months, err := parseInts(val, 1, 12, false)
+			if err != nil {
+				return Rule{}, fmt.Errorf("invalid BYMONTH: %q", val)
+			}
+			for _, m := range months {
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+
 		case "BYDAY":
 			days := strings.Split(val, ",")
 			for _, d := range days {
-				wd, ok := dayNames[strings.TrimSpace(d)]
-				if !ok {
-					return Rule{}, fmt.Errorf("unknown day: %q", d)
+				wd, err := parseWeekdayNum(strings.TrimSpace(d))
+				if err != nil {
+					return Rule{}, err
 				}
 				r.ByDay = append(r.ByDay, wd)
 			}
 
 		case "BYMONTHDAY":
-			n, err := strconv.Atoi(val)
-			if err != nil || n < 1 || n > 31 {
+			days, err := parseInts(val, 1, 31, true)
+			if err != nil {
 				return Rule{}, fmt.Errorf("invalid BYMONTHDAY: %q", val)
 			}
-			r.ByMonthDay = n
+			r.ByMonthDay = days
+
+		case "BYSETPOS":
+			pos, err := parseInts(val, 1, 366, true)
+			if err != nil {
+				return Rule{}, fmt.Errorf("invalid BYSETPOS: %q", val)
+			}
+			r.BySetPos = pos
+
+		case "WKST":
+			wd, ok := dayNames[val]
+			if !ok {
+				return Rule{}, fmt.Errorf("unknown WKST day: %q", val)
+			}
+			r.WeekStart = &wd
 
 		case "COUNT":
 			n, err := strconv.Atoi(val)
@@ -134,10 +179,71 @@ func Parse(rule string) (Rule, error) {
 	if !hasFreq {
 		return Rule{}, fmt.Errorf("FREQ is required")
 	}
+	if r.Count > 0 && r.Until != nil {
+		return Rule{}, fmt.Errorf("COUNT and UNTIL cannot both be set")
+	}
+	if len(r.BySetPos) > 0 && len(r.ByMonth) == 0 && len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		return Rule{}, fmt.Errorf("BYSETPOS requires another BY rule part")
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return Rule{}, fmt.Errorf("numbered BYDAY %q requires FREQ=MONTHLY or FREQ=YEARLY", wd)
+		}
+		if wd.N != 0 && r.Freq == Monthly && (wd.N > 5 || wd.N < -5) {
+			return Rule{}, fmt.Errorf("invalid BYDAY for a monthly rule: %q", wd)
+		}
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return Rule{}, fmt.Errorf("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
 
 	return r, nil
 }
 
+// parseInts parses a comma-separated list of integers whose absolute value
+// is between lo and hi. Negative values are only accepted if signed is set.
+func parseInts(val string, lo, hi int, signed bool) ([]int, error) {
+	var result []int
+	for _, s := range strings.Split(val, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		abs := n
+		if n < 0 {
+			if !signed {
+				return nil, fmt.Errorf("negative value %d", n)
+			}
+			abs = -n
+		}
+		if abs < lo || abs > hi {
+			return nil, fmt.Errorf("value %d out of range", n)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+// parseWeekdayNum parses a BYDAY entry such as "TU", "2TU", or "-1FR".
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("unknown day: %q", s)
+	}
+	wd, ok := dayNames[s[len(s)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("unknown day: %q", s)
+	}
+	result := WeekdayNum{Weekday: wd}
+	if prefix := s[:len(s)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n > 53 || n < -53 {
+			return WeekdayNum{}, fmt.Errorf("invalid day number: %q", s)
+		}
+		result.N = n
+	}
+	return result, nil
+}
+
 // String serializes the rule back to an RRULE string.
 func (r Rule) String() string {
 	var parts []string
@@ -147,16 +253,32 @@ func (r Rule) String() string {
 		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
 	}
 
+	if len(r.ByMonth) > 0 {
+		var months []string
+		for _, m := range r.ByMonth {
+			months = append(months, strconv.Itoa(int(m)))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+
 	if len(r.ByDay) > 0 {
 		var days []string
 		for _, d := range r.ByDay {
-			days = append(days, dayAbbrev[d])
+			days = append(days, d.String())
 		}
 		parts = append(parts, "BYDAY="+strings.Join(days, ","))
 	}
 
-	if r.ByMonthDay > 0 {
-		parts = append(parts, fmt.Sprintf("BYMONTHDAY=%d", r.ByMonthDay))
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(r.BySetPos))
+	}
+
+	if r.WeekStart != nil && *r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+dayAbbrev[*r.WeekStart])
 	}
 
 	if r.Count > 0 {
@@ -170,39 +292,223 @@ func (r Rule) String() string {
 	return strings.Join(parts, ";")
 }
 
-// Describe returns a human-readable description of the rule.
+func joinInts(values []int) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, ",")
+}
+
+// Describe returns a human-readable description of the rule, such as
+// "Repeats monthly on the 2nd Tuesday" or "Repeats every weekday, 10 times".
 func (r Rule) Describe() string {
-	switch r.Freq {
-	case Daily:
-		if r.Interval > 1 {
-			return fmt.Sprintf("Repeats every %d days", r.Interval)
+	var b strings.Builder
+	if r.isWeekdays() {
+		b.WriteString("Repeats every weekday")
+	} else {
+		b.WriteString(r.describeFreq())
+		if on := r.describeDays(); on != "" {
+			b.WriteString(" on " + on)
+		}
+	}
+
+	if len(r.ByMonth) > 0 {
+		var months []string
+		for _, m := range r.ByMonth {
+			months = append(months, m.String())
+		}
+		if r.Freq == Yearly && r.hasNumberedDay() {
+			b.WriteString(" of " + joinList(months, "and"))
+		} else {
+			b.WriteString(" in " + joinList(months, "and"))
 		}
+	} else if r.Freq == Yearly && r.hasNumberedDay() {
+		b.WriteString(" of the year")
+	}
+
+	switch {
+	case r.Count == 1:
+		b.WriteString(", once")
+	case r.Count > 1:
+		fmt.Fprintf(&b, ", %d times", r.Count)
+	case r.Until != nil:
+		b.WriteString(", until " + r.Until.Format("Jan 2, 2006"))
+	}
+	return b.String()
+}
+
+func (r Rule) describeFreq() string {
+	unit := map[Freq]string{Daily: "day", Weekly: "week", Monthly: "month", Yearly: "year"}[r.Freq]
+	switch {
+	case r.Interval > 1:
+		return fmt.Sprintf("Repeats every %d %ss", r.Interval, unit)
+	case r.Freq == Daily:
 		return "Repeats daily"
-	case Weekly:
-		prefix := "Repeats weekly"
-		if r.Interval == 2 {
-			prefix = "Repeats every 2 weeks"
-		} else if r.Interval > 2 {
-			prefix = fmt.Sprintf("Repeats every %d weeks", r.Interval)
-		}
-		if len(r.ByDay) > 0 {
-			var names []string
-			for _, d := range r.ByDay {
-				names = append(names, d.String()[:3])
+	default:
+		return "Repeats " + unit + "ly"
+	}
+}
+
+// describeDays describes the BYDAY, BYMONTHDAY, and BYSETPOS parts, without
+// the leading "on".
+func (r Rule) describeDays() string {
+	if len(r.BySetPos) > 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) > 0 && !r.hasNumberedDay() {
+		var target string
+		switch {
+		case len(r.ByDay) == 7:
+			target = "day"
+		case r.isWeekdayList():
+			target = "weekday"
+		default:
+			target = joinList(r.shortDayNames(), "or")
+		}
+		return "the " + joinList(ordinals(r.BySetPos), "and") + " " + target
+	}
+
+	var days string
+	switch {
+	case len(r.ByDay) == 0:
+	case r.Freq == Daily || r.Freq == Weekly:
+		days = strings.Join(r.shortDayNames(), ", ")
+	case r.sameNumberedDay():
+		var nums []int
+		for _, d := range r.ByDay {
+			nums = append(nums, d.N)
+		}
+		days = "the " + joinList(ordinals(nums), "and") + " " + r.ByDay[0].Weekday.String()
+	default:
+		var names []string
+		for _, d := range r.ByDay {
+			if d.N == 0 {
+				names = append(names, d.Weekday.String()+"s")
+			} else {
+				names = append(names, "the "+ordinal(d.N)+" "+d.Weekday.String())
 			}
-			return prefix + " on " + strings.Join(names, ", ")
 		}
-		return prefix
-	case Monthly:
-		if r.Interval > 1 {
-			return fmt.Sprintf("Repeats every %d months", r.Interval)
+		days = joinList(names, "and")
+	}
+
+	var monthDays string
+	if len(r.ByMonthDay) > 0 {
+		var names []string
+		for _, n := range r.ByMonthDay {
+			if n < 0 {
+				names = append(names, "the "+ordinal(n)+" day")
+			} else {
+				names = append(names, ordinal(n))
+			}
 		}
-		return "Repeats monthly"
-	case Yearly:
-		if r.Interval > 1 {
-			return fmt.Sprintf("Repeats every %d years", r.Interval)
+		if r.ByMonthDay[0] > 0 {
+			names[0] = "the " + names[0]
 		}
-		return "Repeats yearly"
+		monthDays = joinList(names, "and")
+	}
+
+	var desc string
+	switch {
+	case days != "" && monthDays != "":
+		desc = days + " falling on " + monthDays
+	case days != "":
+		desc = days
+	default:
+		desc = monthDays
+	}
+	if desc != "" && len(r.BySetPos) > 0 {
+		desc += " (the " + joinList(ordinals(r.BySetPos), "and") + " of these)"
+	}
+	return desc
+}
+
+// isWeekdays reports whether the rule is a plain "every weekday" rule.
+func (r Rule) isWeekdays() bool {
+	return (r.Freq == Daily || r.Freq == Weekly) && r.Interval <= 1 &&
+		r.isWeekdayList() && len(r.ByMonthDay) == 0 && len(r.BySetPos) == 0
+}
+
+// isWeekdayList reports whether BYDAY is exactly Monday through Friday.
+func (r Rule) isWeekdayList() bool {
+	if len(r.ByDay) != 5 {
+		return false
+	}
+	seen := make(map[time.Weekday]bool)
+	for _, d := range r.ByDay {
+		if d.N != 0 || d.Weekday == time.Saturday || d.Weekday == time.Sunday {
+			return false
+		}
+		seen[d.Weekday] = true
+	}
+	return len(seen) == 5
+}
+
+func (r Rule) hasNumberedDay() bool {
+	for _, d := range r.ByDay {
+		if d.N != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// sameNumberedDay reports whether BYDAY numbers a single weekday, as in
+// "1SU,-1SU".
+func (r Rule) sameNumberedDay() bool {
+	for _, d := range r.ByDay {
+		if d.N == 0 || d.Weekday != r.ByDay[0].Weekday {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Rule) shortDayNames() []string {
+	var names []string
+	for _, d := range r.ByDay {
+		names = append(names, d.Weekday.String()[:3])
+	}
+	return names
+}
+
+// ordinal returns "1st", "2nd", "23rd", and so on, with negative numbers
+// counting back as "last" and "2nd to last".
+func ordinal(n int) string {
+	if n == -1 {
+		return "last"
+	}
+	if n < 0 {
+		return ordinal(-n) + " to last"
+	}
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+func ordinals(nums []int) []string {
+	s := make([]string, len(nums))
+	for i, n := range nums {
+		s[i] = ordinal(n)
+	}
+	return s
+}
+
+// joinList joins items as an English list: "a", "a and b", "a, b, and c".
+func joinList(items []string, conj string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + " " + conj + " " + items[1]
 	}
-	return ""
+	return strings.Join(items[:len(items)-1], ", ") + ", " + conj + " " + items[len(items)-1]
 }
@@ -7,7 +7,11 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/dukerupert/gamwich/internal/allowance"
 	"github.com/dukerupert/gamwich/internal/backup"
+	"github.com/dukerupert/gamwich/internal/calendar"
+	"github.com/dukerupert/gamwich/internal/calsync"
+	"github.com/dukerupert/gamwich/internal/chore"
 	"github.com/dukerupert/gamwich/internal/email"
 	"github.com/dukerupert/gamwich/internal/handler"
 	"github.com/dukerupert/gamwich/internal/license"
@@ -32,6 +36,10 @@ type Server struct {
 	templateHandler *handler.TemplateHandler
 	authH           *handler.AuthHandler
 	pushH           *handler.PushHandler
+	icalH           *handler.ICalHandler
+	calSubH         *handler.CalendarSubscriptionHandler
+	caldavH         *handler.CalDAVHandler
+	davH            *handler.DAVHandler
 	sessionStore    *store.SessionStore
 	householdStore  *store.HouseholdStore
 	pushStore       *store.PushStore
@@ -41,6 +49,9 @@ type Server struct {
 	backupManager   *backup.Manager
 	pushService     *push.Service
 	pushScheduler   *push.Scheduler
+	calScheduler    *calsync.Scheduler
+	choreCloser     *chore.Closer
+	depositor       *allowance.Depositor
 	logger          *slog.Logger
 }
 
@@ -60,6 +71,23 @@ func New(db *sql.DB, weatherSvc *weather.Service, emailClient *email.Client, bas
 	householdStore := store.NewHouseholdStore(db)
 	sessionStore := store.NewSessionStore(db)
 	magicLinkStore := store.NewMagicLinkStore(db)
+	icalStore := store.NewICalStore(db)
+	davStore := store.NewDAVStore(db)
+
+	// Calendar subscriptions and .ics imports
+	calSubStore := store.NewCalendarSubscriptionStore(db)
+	calLogger := logger.With("component", "calsync")
+	calService := calendar.NewService(eventStore, settingsStore, logger.With("component", "calendar"))
+	calImporter := calsync.NewImporter(eventStore, calSubStore, settingsStore, func(householdID int64) {
+		hub.BroadcastToHousehold(householdID, ws.NewMessage("calendar_event", "synced", 0, nil))
+	}, calLogger)
+
+	// Two-way CalDAV sync
+	caldavStore := store.NewCalDAVStore(db)
+	caldavSyncer := calsync.NewCalDAVSyncer(caldavStore, eventStore, settingsStore, func(householdID int64) {
+		hub.BroadcastToHousehold(householdID, ws.NewMessage("calendar_event", "synced", 0, nil))
+	}, calLogger)
+	calSched := calsync.NewScheduler(calImporter, calSubStore, caldavSyncer, caldavStore, calLogger)
 
 	backupLogger := logger.With("component", "backup")
 	tunnelLogger := logger.With("component", "tunnel")
@@ -67,8 +95,10 @@ func New(db *sql.DB, weatherSvc *weather.Service, emailClient *email.Client, bas
 
 	// Backup store + manager
 	backupStore := store.NewBackupStore(db)
+	// Backup and tunnel are configured on the install's default household, so
+	// their status updates only go to that household's screens.
 	backupMgr := backup.NewManager(backupCfg, db, backupStore, settingsStore, func(s backup.Status) {
-		hub.Broadcast(ws.Message{
+		hub.BroadcastToHousehold(store.DefaultHouseholdID, ws.Message{
 			Type:   "backup_status",
 			Entity: "backup",
 			Action: string(s.State),
@@ -83,12 +113,12 @@ func New(db *sql.DB, weatherSvc *weather.Service, emailClient *email.Client, bas
 	tunnelCfg := tunnel.Config{
 		LocalURL: "http://localhost:" + port,
 	}
-	if ts, err := settingsStore.GetTunnelSettings(); err == nil {
+	if ts, err := settingsStore.GetTunnelSettings(store.DefaultHouseholdID); err == nil {
 		tunnelCfg.Token = ts["tunnel_token"]
 		tunnelCfg.Enabled = ts["tunnel_enabled"] == "true"
 	}
 	tunnelMgr := tunnel.NewManager(tunnelCfg, func(s tunnel.Status) {
-		hub.Broadcast(ws.Message{
+		hub.BroadcastToHousehold(store.DefaultHouseholdID, ws.Message{
 			Type:   "tunnel_status",
 			Entity: "tunnel",
 			Action: string(s.State),
@@ -106,23 +136,27 @@ func New(db *sql.DB, weatherSvc *weather.Service, emailClient *email.Client, bas
 	var pushH *handler.PushHandler
 	if pushCfg.VAPIDPublicKey != "" && pushCfg.VAPIDPrivateKey != "" {
 		pushSvc = push.NewService(pushCfg.VAPIDPublicKey, pushCfg.VAPIDPrivateKey)
-		pushSched = push.NewScheduler(pushSvc, pushSt, eventStore, choreStore, familyMemberStore, pushLogger)
-		pushH = handler.NewPushHandler(pushSt, pushSvc, logger.With("component", "push_handler"))
+		pushSched = push.NewScheduler(pushSvc, pushSt, eventStore, choreStore, familyMemberStore, settingsStore, pushLogger)
+		pushH = handler.NewPushHandler(pushSt, familyMemberStore, pushSvc, logger.With("component", "push_handler"))
 	}
 
 	return &Server{
 		db:              db,
 		hub:             hub,
 		familyMemberH:   handler.NewFamilyMemberHandler(familyMemberStore, hub, logger.With("component", "family_member")),
-		calendarEventH:  handler.NewCalendarEventHandler(eventStore, familyMemberStore, hub, logger.With("component", "calendar")),
-		choreH:          handler.NewChoreHandler(choreStore, familyMemberStore, hub, logger.With("component", "chore")),
+		calendarEventH:  handler.NewCalendarEventHandler(eventStore, familyMemberStore, settingsStore, calService, hub, calSched, logger.With("component", "calendar")),
+		choreH:          handler.NewChoreHandler(choreStore, familyMemberStore, settingsStore, hub, pushSched, logger.With("component", "chore")),
 		groceryH:        handler.NewGroceryHandler(groceryStore, familyMemberStore, hub, logger.With("component", "grocery")),
 		noteH:           handler.NewNoteHandler(noteStore, familyMemberStore, hub, logger.With("component", "note")),
-		rewardH:         handler.NewRewardHandler(rewardStore, familyMemberStore, hub, logger.With("component", "reward")),
+		rewardH:         handler.NewRewardHandler(rewardStore, familyMemberStore, settingsStore, hub, pushSched, logger.With("component", "reward")),
 		settingsH:       handler.NewSettingsHandler(settingsStore, weatherSvc, backupMgr, hub),
-		templateHandler: handler.NewTemplateHandler(familyMemberStore, eventStore, choreStore, groceryStore, noteStore, rewardStore, settingsStore, weatherSvc, hub, licenseClient, tunnelMgr, backupMgr, backupStore, pushSt, pushSvc, pushSched, logger.With("component", "template")),
-		authH:           handler.NewAuthHandler(userStore, householdStore, sessionStore, magicLinkStore, emailClient, baseURL, logger.With("component", "auth")),
+		templateHandler: handler.NewTemplateHandler(familyMemberStore, eventStore, calService, choreStore, groceryStore, noteStore, rewardStore, settingsStore, weatherSvc, hub, licenseClient, tunnelMgr, backupMgr, backupStore, pushSt, pushSvc, pushSched, icalStore, calSubStore, calImporter, calSched, caldavStore, caldavSyncer, davStore, logger.With("component", "template")),
+		authH:           handler.NewAuthHandler(userStore, householdStore, sessionStore, magicLinkStore, choreStore, emailClient, baseURL, logger.With("component", "auth")),
 		pushH:           pushH,
+		icalH:           handler.NewICalHandler(icalStore, eventStore, familyMemberStore, householdStore, settingsStore, logger.With("component", "ical")),
+		calSubH:         handler.NewCalendarSubscriptionHandler(calSubStore, eventStore, familyMemberStore, calImporter, hub, logger.With("component", "calendar_subscription")),
+		caldavH:         handler.NewCalDAVHandler(caldavStore, familyMemberStore, caldavSyncer, hub, logger.With("component", "caldav")),
+		davH:            handler.NewDAVHandler(davStore, eventStore, familyMemberStore, householdStore, settingsStore, calSched, hub, logger.With("component", "dav")),
 		sessionStore:    sessionStore,
 		householdStore:  householdStore,
 		pushStore:       pushSt,
@@ -132,6 +166,9 @@ func New(db *sql.DB, weatherSvc *weather.Service, emailClient *email.Client, bas
 		backupManager:   backupMgr,
 		pushService:     pushSvc,
 		pushScheduler:   pushSched,
+		calScheduler:    calSched,
+		choreCloser:     chore.NewCloser(choreStore, settingsStore, logger.With("component", "chore-closer")),
+		depositor:       allowance.NewDepositor(rewardStore, settingsStore, logger.With("component", "allowance")),
 		logger:          logger,
 	}
 }
@@ -171,6 +208,21 @@ func (s *Server) PushScheduler() *push.Scheduler {
 	return s.pushScheduler
 }
 
+// CalendarSyncScheduler returns the scheduler for calendar subscriptions and CalDAV sync.
+func (s *Server) CalendarSyncScheduler() *calsync.Scheduler {
+	return s.calScheduler
+}
+
+// ChoreCloser returns the job that closes out missed chore occurrences.
+func (s *Server) ChoreCloser() *chore.Closer {
+	return s.choreCloser
+}
+
+// AllowanceDepositor returns the job that pays weekly allowances.
+func (s *Server) AllowanceDepositor() *allowance.Depositor {
+	return s.depositor
+}
+
 // PushStore returns the push store for cleanup tasks.
 func (s *Server) PushStore() *store.PushStore {
 	return s.pushStore
@@ -190,6 +242,14 @@ func (s *Server) Router() http.Handler {
 	outerMux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServer(http.Dir("web/static"))))
 	outerMux.HandleFunc("GET /health", s.healthHandler)
 
+	// Calendar feeds (authorized by the token in the URL)
+	outerMux.HandleFunc("GET /ical/{token}/household.ics", s.icalH.HouseholdFeed)
+	outerMux.HandleFunc("GET /ical/{token}/member/{file}", s.icalH.MemberFeed)
+
+	// CalDAV server (authorized by app passwords)
+	outerMux.Handle("/dav/", s.davH)
+	outerMux.HandleFunc("/.well-known/caldav", s.davH.WellKnown)
+
 	// Protected routes — wrapped with RequireAuth middleware
 	protectedMux := http.NewServeMux()
 	s.registerProtectedRoutes(protectedMux)
@@ -238,9 +298,31 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	// Calendar event API routes
 	mux.HandleFunc("POST /api/events", s.calendarEventH.Create)
 	mux.HandleFunc("GET /api/events", s.calendarEventH.List)
+	mux.HandleFunc("GET /api/events/conflicts", s.calendarEventH.Conflicts)
 	mux.HandleFunc("GET /api/events/{id}", s.calendarEventH.Get)
 	mux.HandleFunc("PUT /api/events/{id}", s.calendarEventH.Update)
 	mux.HandleFunc("DELETE /api/events/{id}", s.calendarEventH.Delete)
+	mux.HandleFunc("PUT /api/events/{id}/member", s.calendarEventH.AssignMember)
+	mux.HandleFunc("POST /api/events/import", s.calSubH.Import)
+
+	// Calendar subscription routes
+	mux.HandleFunc("GET /api/calendar-subscriptions", s.calSubH.List)
+	mux.HandleFunc("POST /api/calendar-subscriptions", s.calSubH.Create)
+	mux.HandleFunc("PUT /api/calendar-subscriptions/{id}", s.calSubH.Update)
+	mux.HandleFunc("DELETE /api/calendar-subscriptions/{id}", s.calSubH.Delete)
+	mux.HandleFunc("POST /api/calendar-subscriptions/{id}/sync", s.calSubH.Sync)
+
+	// CalDAV collections
+	mux.HandleFunc("GET /api/caldav-collections", s.caldavH.List)
+	mux.HandleFunc("POST /api/caldav-collections", s.caldavH.Create)
+	mux.HandleFunc("PUT /api/caldav-collections/{id}", s.caldavH.Update)
+	mux.HandleFunc("DELETE /api/caldav-collections/{id}", s.caldavH.Delete)
+	mux.HandleFunc("POST /api/caldav-collections/{id}/sync", s.caldavH.Sync)
+
+	// App passwords for the CalDAV server
+	mux.HandleFunc("GET /api/app-passwords", s.davH.ListAppPasswords)
+	mux.HandleFunc("POST /api/app-passwords", s.davH.CreateAppPassword)
+	mux.HandleFunc("DELETE /api/app-passwords/{id}", s.davH.DeleteAppPassword)
 
 	// Chore API routes
 	mux.HandleFunc("POST /api/chores", s.choreH.Create)
@@ -249,13 +331,50 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("DELETE /api/chores/{id}", s.choreH.Delete)
 	mux.HandleFunc("POST /api/chores/{id}/complete", s.choreH.Complete)
 	mux.HandleFunc("DELETE /api/chores/{id}/completions/{completion_id}", s.choreH.UndoComplete)
+	mux.HandleFunc("GET /api/chores/{id}/rotation", s.choreH.GetRotation)
+	mux.HandleFunc("POST /api/chores/{id}/rotation/skip", s.choreH.SkipTurn)
+	mux.HandleFunc("POST /api/chores/{id}/rotation/swap", s.choreH.SwapTurn)
+	mux.HandleFunc("GET /api/chores/{id}/occurrences", s.choreH.ListOccurrences)
+	mux.HandleFunc("PUT /api/chores/{id}/occurrences/{date}", s.choreH.SetOccurrence)
+	mux.HandleFunc("DELETE /api/chores/{id}/occurrences/{date}", s.choreH.ClearOccurrence)
+	mux.HandleFunc("GET /api/chores/approvals", s.choreH.ListApprovals)
+	mux.HandleFunc("POST /api/chores/completions/{completion_id}/review", s.choreH.ReviewCompletion)
+	mux.HandleFunc("GET /api/chores/packs", s.choreH.ListStarterPacks)
+	mux.HandleFunc("POST /api/chores/packs/{key}/import", s.choreH.ImportStarterPack)
+	mux.HandleFunc("GET /api/chores/templates", s.choreH.ListTemplates)
+	mux.HandleFunc("POST /api/chores/templates", s.choreH.SaveTemplate)
+	mux.HandleFunc("POST /api/chores/templates/import", s.choreH.ImportSharedTemplate)
+	mux.HandleFunc("POST /api/chores/templates/{template_id}/import", s.choreH.ImportTemplate)
+	mux.HandleFunc("DELETE /api/chores/templates/{template_id}", s.choreH.DeleteTemplate)
 
 	// Grocery API routes
+	mux.HandleFunc("GET /api/grocery-categories", s.groceryH.ListCategories)
+	mux.HandleFunc("POST /api/grocery-categories", s.groceryH.CreateCategory)
+	mux.HandleFunc("PUT /api/grocery-categories/sort", s.groceryH.UpdateCategorySortOrder)
+	mux.HandleFunc("GET /api/grocery-categories/rules", s.groceryH.ExportCategoryRules)
+	mux.HandleFunc("POST /api/grocery-categories/rules", s.groceryH.ImportCategoryRules)
+	mux.HandleFunc("PUT /api/grocery-categories/{id}", s.groceryH.UpdateCategory)
+	mux.HandleFunc("DELETE /api/grocery-categories/{id}", s.groceryH.DeleteCategory)
+	mux.HandleFunc("GET /api/grocery-stores", s.groceryH.ListShops)
+	mux.HandleFunc("POST /api/grocery-stores", s.groceryH.CreateShop)
+	mux.HandleFunc("GET /api/grocery-stores/{id}", s.groceryH.GetShop)
+	mux.HandleFunc("PUT /api/grocery-stores/{id}", s.groceryH.UpdateShop)
+	mux.HandleFunc("DELETE /api/grocery-stores/{id}", s.groceryH.DeleteShop)
+	mux.HandleFunc("GET /api/grocery-lists", s.groceryH.ListLists)
+	mux.HandleFunc("POST /api/grocery-lists", s.groceryH.CreateList)
+	mux.HandleFunc("PUT /api/grocery-lists/sort", s.groceryH.UpdateListSortOrder)
+	mux.HandleFunc("GET /api/grocery-lists/{list_id}", s.groceryH.GetList)
+	mux.HandleFunc("PUT /api/grocery-lists/{list_id}", s.groceryH.UpdateList)
+	mux.HandleFunc("DELETE /api/grocery-lists/{list_id}", s.groceryH.DeleteList)
+	mux.HandleFunc("PUT /api/grocery-lists/{list_id}/store", s.groceryH.SetListShop)
+	mux.HandleFunc("GET /api/grocery-lists/{list_id}/suggestions", s.groceryH.Suggestions)
 	mux.HandleFunc("POST /api/grocery-lists/{list_id}/items", s.groceryH.CreateItem)
+	mux.HandleFunc("POST /api/grocery-lists/{list_id}/quick-add", s.groceryH.QuickAdd)
 	mux.HandleFunc("GET /api/grocery-lists/{list_id}/items", s.groceryH.ListItems)
 	mux.HandleFunc("PUT /api/grocery-lists/{list_id}/items/{id}", s.groceryH.UpdateItem)
 	mux.HandleFunc("DELETE /api/grocery-lists/{list_id}/items/{id}", s.groceryH.DeleteItem)
 	mux.HandleFunc("POST /api/grocery-lists/{list_id}/items/{id}/check", s.groceryH.ToggleChecked)
+	mux.HandleFunc("POST /api/grocery-lists/{list_id}/items/{id}/move", s.groceryH.MoveItem)
 	mux.HandleFunc("POST /api/grocery-lists/{list_id}/clear-checked", s.groceryH.ClearChecked)
 
 	// Notes API routes
@@ -271,7 +390,18 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("PUT /api/rewards/{id}", s.rewardH.Update)
 	mux.HandleFunc("DELETE /api/rewards/{id}", s.rewardH.Delete)
 	mux.HandleFunc("POST /api/rewards/{id}/redeem", s.rewardH.Redeem)
+	mux.HandleFunc("GET /api/rewards/redemptions", s.rewardH.ListRedemptions)
+	mux.HandleFunc("POST /api/rewards/redemptions/{redemption_id}/status", s.rewardH.UpdateRedemptionStatus)
 	mux.HandleFunc("GET /api/family-members/{id}/points", s.rewardH.GetPointBalance)
+	mux.HandleFunc("GET /api/family-members/{id}/points/transactions", s.rewardH.ListTransactions)
+	mux.HandleFunc("POST /api/family-members/{id}/points/adjustments", s.rewardH.AdjustPoints)
+	mux.HandleFunc("GET /api/family-members/{id}/points/statement", s.rewardH.Statement)
+	mux.HandleFunc("GET /api/family-members/{id}/allowance", s.rewardH.GetAllowance)
+	mux.HandleFunc("PUT /api/family-members/{id}/allowance", s.rewardH.SetAllowance)
+	mux.HandleFunc("GET /api/family-members/{id}/goals", s.rewardH.ListGoals)
+	mux.HandleFunc("POST /api/family-members/{id}/goals", s.rewardH.CreateGoal)
+	mux.HandleFunc("DELETE /api/family-members/{id}/goals/{goal_id}", s.rewardH.DeleteGoal)
+	mux.HandleFunc("GET /api/family-members/{id}/stats", s.choreH.MemberStats)
 	mux.HandleFunc("GET /api/leaderboard", s.rewardH.GetLeaderboard)
 
 	// Settings API routes
@@ -279,6 +409,8 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("PUT /api/settings/kiosk", s.settingsH.UpdateKiosk)
 	mux.HandleFunc("GET /api/settings/weather", s.settingsH.GetWeather)
 	mux.HandleFunc("PUT /api/settings/weather", s.settingsH.UpdateWeather)
+	mux.HandleFunc("GET /api/settings/timezone", s.settingsH.GetTimezone)
+	mux.HandleFunc("PUT /api/settings/timezone", s.settingsH.UpdateTimezone)
 	mux.HandleFunc("GET /api/settings/s3", s.settingsH.GetS3)
 	mux.HandleFunc("PUT /api/settings/s3", s.settingsH.UpdateS3)
 
@@ -286,6 +418,7 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	if s.pushH != nil {
 		mux.HandleFunc("POST /api/push/subscribe", s.pushH.Subscribe)
 		mux.HandleFunc("DELETE /api/push/subscriptions/{id}", s.pushH.Unsubscribe)
+		mux.HandleFunc("PUT /api/push/subscriptions/{id}/member", s.pushH.SetMember)
 		mux.HandleFunc("GET /api/push/subscriptions", s.pushH.ListSubscriptions)
 		mux.HandleFunc("GET /api/push/vapid-key", s.pushH.GetVAPIDKey)
 		mux.HandleFunc("GET /api/push/preferences", s.pushH.GetPreferences)
@@ -299,6 +432,7 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /chores", s.templateHandler.ChoresPage)
 	mux.HandleFunc("GET /chores/manage", s.templateHandler.ChoreManagePage)
 	mux.HandleFunc("GET /grocery", s.templateHandler.GroceryPage)
+	mux.HandleFunc("GET /grocery/shop", s.templateHandler.GroceryShoppingPage)
 	mux.HandleFunc("GET /notes", s.templateHandler.NotesPage)
 	mux.HandleFunc("GET /chores/rewards", s.templateHandler.RewardsPage)
 	mux.HandleFunc("GET /settings", s.templateHandler.SectionPage("settings"))
@@ -316,6 +450,22 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /partials/grocery/clear-checked", s.templateHandler.GroceryClearChecked)
 	mux.HandleFunc("GET /partials/grocery/items/{id}/edit", s.templateHandler.GroceryItemEditForm)
 	mux.HandleFunc("PUT /partials/grocery/items/{id}", s.templateHandler.GroceryItemUpdate)
+	mux.HandleFunc("POST /partials/grocery/lists", s.templateHandler.GroceryListCreate)
+	mux.HandleFunc("PUT /partials/grocery/lists/{id}", s.templateHandler.GroceryListRename)
+	mux.HandleFunc("DELETE /partials/grocery/lists/{id}", s.templateHandler.GroceryListDelete)
+	mux.HandleFunc("POST /partials/grocery/lists/{id}/archive", s.templateHandler.GroceryListArchive)
+	mux.HandleFunc("POST /partials/grocery/lists/{id}/move", s.templateHandler.GroceryListMove)
+	mux.HandleFunc("PUT /partials/grocery/lists/{id}/store", s.templateHandler.GroceryListSetShop)
+	mux.HandleFunc("GET /partials/grocery/shop", s.templateHandler.GroceryShoppingPartial)
+	mux.HandleFunc("POST /partials/grocery/stores", s.templateHandler.GroceryShopCreate)
+	mux.HandleFunc("PUT /partials/grocery/stores/{id}", s.templateHandler.GroceryShopRename)
+	mux.HandleFunc("DELETE /partials/grocery/stores/{id}", s.templateHandler.GroceryShopDelete)
+	mux.HandleFunc("GET /partials/grocery/stores/{id}/layout", s.templateHandler.GroceryShopLayoutForm)
+	mux.HandleFunc("PUT /partials/grocery/stores/{id}/layout", s.templateHandler.GroceryShopLayoutUpdate)
+	mux.HandleFunc("POST /partials/grocery/categories", s.templateHandler.GroceryCategoryCreate)
+	mux.HandleFunc("PUT /partials/grocery/categories/{id}", s.templateHandler.GroceryCategoryRename)
+	mux.HandleFunc("DELETE /partials/grocery/categories/{id}", s.templateHandler.GroceryCategoryDelete)
+	mux.HandleFunc("POST /partials/grocery/categories/{id}/move", s.templateHandler.GroceryCategoryMove)
 	// Notes partials (HTMX)
 	mux.HandleFunc("GET /partials/notes", s.templateHandler.NotesPartial)
 	mux.HandleFunc("GET /partials/notes/list", s.templateHandler.NoteList)
@@ -330,6 +480,12 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /partials/rewards", s.templateHandler.RewardsPartial)
 	mux.HandleFunc("GET /partials/rewards/list", s.templateHandler.RewardsList)
 	mux.HandleFunc("POST /partials/rewards/{id}/redeem", s.templateHandler.RewardRedeem)
+	mux.HandleFunc("POST /partials/rewards/redemptions/{id}/status", s.templateHandler.RedemptionStatusUpdate)
+	mux.HandleFunc("GET /partials/rewards/members/{id}/points", s.templateHandler.MemberPointsHistory)
+	mux.HandleFunc("POST /partials/rewards/members/{id}/points", s.templateHandler.MemberPointsAdjust)
+	mux.HandleFunc("POST /partials/rewards/members/{id}/allowance", s.templateHandler.MemberAllowanceUpdate)
+	mux.HandleFunc("POST /partials/rewards/members/{id}/goals", s.templateHandler.MemberGoalCreate)
+	mux.HandleFunc("DELETE /partials/rewards/members/{id}/goals/{goal_id}", s.templateHandler.MemberGoalDelete)
 
 	// Reward management partials (settings)
 	mux.HandleFunc("GET /partials/settings/rewards", s.templateHandler.RewardManagePartial)
@@ -344,6 +500,8 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("PUT /partials/settings/kiosk", s.templateHandler.KioskSettingsUpdate)
 	mux.HandleFunc("GET /partials/settings/weather", s.templateHandler.WeatherSettingsPartial)
 	mux.HandleFunc("PUT /partials/settings/weather", s.templateHandler.WeatherSettingsUpdate)
+	mux.HandleFunc("GET /partials/settings/timezone", s.templateHandler.TimezoneSettingsPartial)
+	mux.HandleFunc("PUT /partials/settings/timezone", s.templateHandler.TimezoneSettingsUpdate)
 	mux.HandleFunc("GET /partials/settings/theme", s.templateHandler.ThemeSettingsPartial)
 	mux.HandleFunc("PUT /partials/settings/theme", s.templateHandler.ThemeSettingsUpdate)
 	mux.HandleFunc("GET /partials/settings/license", s.templateHandler.LicenseSettingsPartial)
@@ -364,6 +522,7 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /partials/calendar/events", s.templateHandler.CalendarEventCreate)
 	mux.HandleFunc("PUT /partials/calendar/events/{id}", s.templateHandler.CalendarEventUpdate)
 	mux.HandleFunc("DELETE /partials/calendar/events/{id}", s.templateHandler.CalendarEventDeleteForm)
+	mux.HandleFunc("PUT /partials/calendar/events/{id}/member", s.templateHandler.CalendarEventAssignMember)
 	mux.HandleFunc("GET /partials/calendar/events/{id}/recurrence-edit", s.templateHandler.RecurrenceEditChoice)
 	mux.HandleFunc("GET /partials/calendar/events/{id}/recurrence-delete", s.templateHandler.RecurrenceDeleteChoice)
 
@@ -378,11 +537,22 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("DELETE /partials/chores/{id}", s.templateHandler.ChoreDelete)
 	mux.HandleFunc("POST /partials/chores/{id}/complete", s.templateHandler.ChoreComplete)
 	mux.HandleFunc("POST /partials/chores/{id}/undo-complete", s.templateHandler.ChoreUndoComplete)
+	mux.HandleFunc("POST /partials/chores/{id}/skip-turn", s.templateHandler.ChoreSkipTurn)
+	mux.HandleFunc("POST /partials/chores/{id}/swap-turn", s.templateHandler.ChoreSwapTurn)
+	mux.HandleFunc("POST /partials/chores/{id}/excuse", s.templateHandler.ChoreExcuse)
+	mux.HandleFunc("GET /partials/chores/approvals", s.templateHandler.ChoreApprovalQueue)
+	mux.HandleFunc("GET /partials/chores/approvals/{id}/review", s.templateHandler.ChoreApprovalReview)
 	mux.HandleFunc("GET /partials/chores/manage", s.templateHandler.ChoreManagePartial)
 	mux.HandleFunc("GET /partials/chores/areas", s.templateHandler.ChoreAreaList)
 	mux.HandleFunc("POST /partials/chores/areas", s.templateHandler.ChoreAreaCreate)
 	mux.HandleFunc("PUT /partials/chores/areas/{id}", s.templateHandler.ChoreAreaUpdate)
 	mux.HandleFunc("DELETE /partials/chores/areas/{id}", s.templateHandler.ChoreAreaDelete)
+	mux.HandleFunc("GET /partials/chores/templates", s.templateHandler.ChoreTemplateList)
+	mux.HandleFunc("POST /partials/chores/templates", s.templateHandler.ChoreTemplateSave)
+	mux.HandleFunc("POST /partials/chores/templates/import", s.templateHandler.ChoreTemplateImport)
+	mux.HandleFunc("POST /partials/chores/templates/{id}/import", s.templateHandler.ChoreTemplateImport)
+	mux.HandleFunc("DELETE /partials/chores/templates/{id}", s.templateHandler.ChoreTemplateDelete)
+	mux.HandleFunc("POST /partials/chores/packs/{key}/import", s.templateHandler.ChoreStarterPackImport)
 
 	// Weather partial (HTMX polling)
 	mux.HandleFunc("GET /partials/weather", s.templateHandler.WeatherPartial)
@@ -420,6 +590,26 @@ func (s *Server) registerProtectedRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("PUT /partials/settings/push/preferences", s.templateHandler.PushPreferencesUpdate)
 	mux.HandleFunc("GET /partials/settings/push/devices", s.templateHandler.PushDevicesList)
 	mux.HandleFunc("DELETE /partials/settings/push/devices/{id}", s.templateHandler.PushDeviceDelete)
+	mux.HandleFunc("PUT /partials/settings/push/devices/{id}/member", s.templateHandler.PushDeviceMember)
+
+	// Calendar feed partials (HTMX)
+	mux.HandleFunc("GET /partials/settings/ical", s.templateHandler.ICalSettingsPartial)
+	mux.HandleFunc("POST /partials/settings/ical", s.templateHandler.ICalTokenCreate)
+	mux.HandleFunc("DELETE /partials/settings/ical/{id}", s.templateHandler.ICalTokenRevoke)
+	mux.HandleFunc("GET /partials/settings/calendar-subscriptions", s.templateHandler.CalendarSubscriptionsPartial)
+	mux.HandleFunc("POST /partials/settings/calendar-subscriptions", s.templateHandler.CalendarSubscriptionCreate)
+	mux.HandleFunc("PUT /partials/settings/calendar-subscriptions/{id}", s.templateHandler.CalendarSubscriptionUpdate)
+	mux.HandleFunc("DELETE /partials/settings/calendar-subscriptions/{id}", s.templateHandler.CalendarSubscriptionDelete)
+	mux.HandleFunc("POST /partials/settings/calendar-subscriptions/{id}/sync", s.templateHandler.CalendarSubscriptionSync)
+	mux.HandleFunc("GET /partials/settings/caldav", s.templateHandler.CalDAVCollectionsPartial)
+	mux.HandleFunc("POST /partials/settings/caldav", s.templateHandler.CalDAVCollectionCreate)
+	mux.HandleFunc("PUT /partials/settings/caldav/{id}", s.templateHandler.CalDAVCollectionUpdate)
+	mux.HandleFunc("DELETE /partials/settings/caldav/{id}", s.templateHandler.CalDAVCollectionDelete)
+	mux.HandleFunc("POST /partials/settings/caldav/{id}/sync", s.templateHandler.CalDAVCollectionSync)
+	mux.HandleFunc("GET /partials/settings/app-passwords", s.templateHandler.AppPasswordsPartial)
+	mux.HandleFunc("POST /partials/settings/app-passwords", s.templateHandler.AppPasswordCreate)
+	mux.HandleFunc("DELETE /partials/settings/app-passwords/{id}", s.templateHandler.AppPasswordRevoke)
+	mux.HandleFunc("POST /partials/settings/calendar-import", s.templateHandler.CalendarImportUpload)
 
 	// WebSocket
 	mux.HandleFunc("GET /ws", ws.HandleWebSocket(s.hub))
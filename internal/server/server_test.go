@@ -0,0 +1,1943 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/backup"
+	"github.com/dukerupert/gamwich/internal/database"
+	"github.com/dukerupert/gamwich/internal/email"
+	"github.com/dukerupert/gamwich/internal/license"
+	"github.com/dukerupert/gamwich/internal/model"
+	"github.com/dukerupert/gamwich/internal/push"
+	"github.com/dukerupert/gamwich/internal/store"
+	"github.com/dukerupert/gamwich/internal/weather"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type testHousehold struct {
+	id    int64
+	token string
+}
+
+func setupTestServer(t *testing.T) (*Server, http.Handler) {
+	t.Helper()
+	// Templates are parsed relative to the repository root.
+	t.Chdir("../..")
+
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := New(db, weather.NewService(weather.Config{}), email.NewClient("", "", "http://localhost"), "http://localhost",
+		license.NewClient(license.Config{}), "8080", backup.Config{}, push.Config{}, logger)
+	return srv, srv.Router()
+}
+
+// loginHousehold creates an admin user in the household and returns a session for it.
+func loginHousehold(t *testing.T, srv *Server, householdID int64, email string) testHousehold {
+	t.Helper()
+	us := store.NewUserStore(srv.db)
+	u, err := us.Create(email, "Test User")
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if _, err := srv.householdStore.AddMember(householdID, u.ID, "admin"); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	sess, err := srv.sessionStore.Create(u.ID, householdID)
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	return testHousehold{id: householdID, token: sess.Token}
+}
+
+func doRequest(t *testing.T, h http.Handler, hh testHousehold, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, r)
+	req.AddCookie(&http.Cookie{Name: "gamwich_session", Value: hh.token})
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeList(t *testing.T, rec *httptest.ResponseRecorder) []map[string]any {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var out []map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return out
+}
+
+func TestHouseholdIsolation(t *testing.T) {
+	srv, h := setupTestServer(t)
+
+	other, err := srv.householdStore.Create("Other Household")
+	if err != nil {
+		t.Fatalf("create household: %v", err)
+	}
+	if err := srv.householdStore.SeedDefaults(other.ID); err != nil {
+		t.Fatalf("seed household: %v", err)
+	}
+
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+	b := loginHousehold(t, srv, other.ID, "b@example.com")
+
+	// Seed household A's data.
+	members := store.NewFamilyMemberStore(srv.db)
+	member, err := members.Create(a.id, "Alice", "#FF0000", "😀")
+	if err != nil {
+		t.Fatalf("create member: %v", err)
+	}
+	start := time.Now().Truncate(time.Hour)
+	event, err := store.NewEventStore(srv.db).Create(a.id, "Dentist", "", start, start.Add(time.Hour), false, &member.ID, "")
+	if err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	chores := store.NewChoreStore(srv.db)
+	chore, err := chores.Create(a.id, "Secret dishes", "", nil, 5, "", &member.ID)
+	if err != nil {
+		t.Fatalf("create chore: %v", err)
+	}
+	if _, err := chores.CreateCompletion(chore.ID, a.id, &member.ID, 5); err != nil {
+		t.Fatalf("create completion: %v", err)
+	}
+	groceries := store.NewGroceryStore(srv.db)
+	list, err := groceries.GetDefaultList(a.id)
+	if err != nil {
+		t.Fatalf("get default list: %v", err)
+	}
+	item, err := groceries.CreateItem(list.ID, a.id, "Secret milk", "", "", "", "Dairy", nil)
+	if err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	note, err := store.NewNoteStore(srv.db).Create(a.id, "Secret note", "", nil, false, "normal", nil)
+	if err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+	reward, err := store.NewRewardStore(srv.db).Create(a.id, "Secret reward", "", 1, true)
+	if err != nil {
+		t.Fatalf("create reward: %v", err)
+	}
+
+	id := func(n int64) string { return strconv.FormatInt(n, 10) }
+	listPath := "/api/grocery-lists/" + id(list.ID) + "/items"
+
+	t.Run("lists are empty for other household", func(t *testing.T) {
+		window := "?start=" + start.Add(-24*time.Hour).Format("2006-01-02") + "&end=" + start.Add(48*time.Hour).Format("2006-01-02")
+		for _, path := range []string{"/api/family-members", "/api/events" + window, "/api/chores", "/api/notes", "/api/rewards", "/api/leaderboard"} {
+			if got := decodeList(t, doRequest(t, h, b, "GET", path, "")); len(got) != 0 {
+				t.Errorf("GET %s returned %d rows to other household", path, len(got))
+			}
+		}
+		if rec := doRequest(t, h, b, "GET", listPath, ""); rec.Code == http.StatusOK && strings.Contains(rec.Body.String(), "Secret milk") {
+			t.Errorf("GET %s leaked items to other household", listPath)
+		}
+	})
+
+	t.Run("by-id access is not found", func(t *testing.T) {
+		cases := []struct {
+			method, path, body string
+		}{
+			{"GET", "/api/events/" + id(event.ID), ""},
+			{"PUT", "/api/events/" + id(event.ID), `{"title":"x","start_time":"` + start.Format(time.RFC3339) + `","end_time":"` + start.Add(time.Hour).Format(time.RFC3339) + `"}`},
+			{"DELETE", "/api/events/" + id(event.ID), ""},
+			{"PUT", "/api/family-members/" + id(member.ID), `{"name":"x","color":"#000000","avatar_emoji":"😀"}`},
+			{"DELETE", "/api/family-members/" + id(member.ID), ""},
+			{"GET", "/api/family-members/" + id(member.ID) + "/points", ""},
+			{"PUT", "/api/chores/" + id(chore.ID), `{"title":"x","points":1}`},
+			{"DELETE", "/api/chores/" + id(chore.ID), ""},
+			{"POST", "/api/chores/" + id(chore.ID) + "/complete", `{}`},
+			{"POST", listPath, `{"name":"Eggs"}`},
+			{"PUT", listPath + "/" + id(item.ID), `{"name":"x"}`},
+			{"DELETE", listPath + "/" + id(item.ID), ""},
+			{"POST", listPath + "/" + id(item.ID) + "/check", `{}`},
+			{"PUT", "/api/notes/" + id(note.ID), `{"title":"x","priority":"normal"}`},
+			{"DELETE", "/api/notes/" + id(note.ID), ""},
+			{"POST", "/api/notes/" + id(note.ID) + "/pin", ""},
+			{"PUT", "/api/rewards/" + id(reward.ID), `{"title":"x","point_cost":1,"active":true}`},
+			{"DELETE", "/api/rewards/" + id(reward.ID), ""},
+			{"POST", "/api/rewards/" + id(reward.ID) + "/redeem", `{"redeemed_by":` + id(member.ID) + `}`},
+		}
+		for _, tc := range cases {
+			rec := doRequest(t, h, b, tc.method, tc.path, tc.body)
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("%s %s status = %d, want %d", tc.method, tc.path, rec.Code, http.StatusNotFound)
+			}
+		}
+	})
+
+	t.Run("by-id partials are not found", func(t *testing.T) {
+		cases := []struct {
+			method, path string
+		}{
+			{"GET", "/partials/calendar/events/" + id(event.ID)},
+			{"GET", "/partials/calendar/events/" + id(event.ID) + "/edit"},
+			{"PUT", "/partials/calendar/events/" + id(event.ID) + "?title=x"},
+			{"DELETE", "/partials/calendar/events/" + id(event.ID)},
+			{"PUT", "/partials/calendar/events/" + id(event.ID) + "/member?family_member_id=" + id(member.ID)},
+			{"GET", "/partials/chores/" + id(chore.ID) + "/edit"},
+			{"PUT", "/partials/chores/" + id(chore.ID) + "?title=x&points=1"},
+			{"DELETE", "/partials/chores/" + id(chore.ID)},
+			{"POST", "/partials/chores/" + id(chore.ID) + "/complete"},
+			{"POST", "/partials/chores/" + id(chore.ID) + "/undo-complete"},
+			{"POST", "/partials/chores/" + id(chore.ID) + "/skip-turn"},
+			{"POST", "/partials/chores/" + id(chore.ID) + "/excuse"},
+			{"GET", "/partials/grocery/items/" + id(item.ID) + "/edit"},
+			{"PUT", "/partials/grocery/items/" + id(item.ID) + "?name=x"},
+			{"DELETE", "/partials/grocery/items/" + id(item.ID)},
+			{"POST", "/partials/grocery/items/" + id(item.ID) + "/check"},
+			{"PUT", "/partials/grocery/lists/" + id(list.ID) + "?name=x"},
+			{"DELETE", "/partials/grocery/lists/" + id(list.ID)},
+			{"POST", "/partials/grocery/lists/" + id(list.ID) + "/archive"},
+			{"GET", "/partials/notes/" + id(note.ID) + "/edit"},
+			{"PUT", "/partials/notes/" + id(note.ID) + "?title=x"},
+			{"DELETE", "/partials/notes/" + id(note.ID)},
+			{"POST", "/partials/notes/" + id(note.ID) + "/pin"},
+			{"GET", "/partials/settings/rewards/" + id(reward.ID) + "/edit"},
+			{"PUT", "/partials/settings/rewards/" + id(reward.ID) + "?title=x&point_cost=1"},
+			{"DELETE", "/partials/settings/rewards/" + id(reward.ID)},
+			{"POST", "/partials/rewards/" + id(reward.ID) + "/redeem"},
+			{"GET", "/partials/rewards/members/" + id(member.ID) + "/points"},
+			{"GET", "/partials/family-members/" + id(member.ID) + "/edit"},
+			{"PUT", "/partials/family-members/" + id(member.ID) + "?name=x&color=%23000000"},
+			{"DELETE", "/partials/family-members/" + id(member.ID)},
+			{"GET", "/partials/family-members/" + id(member.ID) + "/pin"},
+			{"POST", "/partials/family-members/" + id(member.ID) + "/pin?pin=1234"},
+			{"POST", "/partials/family-members/" + id(member.ID) + "/pin/verify?pin=1234"},
+			{"POST", "/partials/user/select/" + id(member.ID)},
+			{"POST", "/partials/user/verify-pin/" + id(member.ID) + "?pin=1234"},
+		}
+		for _, tc := range cases {
+			rec := doRequest(t, h, b, tc.method, tc.path, "")
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("%s %s status = %d, want %d", tc.method, tc.path, rec.Code, http.StatusNotFound)
+			}
+		}
+	})
+
+	t.Run("members of another household are rejected", func(t *testing.T) {
+		own, err := chores.Create(b.id, "Own dishes", "", nil, 5, "", nil)
+		if err != nil {
+			t.Fatalf("create chore: %v", err)
+		}
+		ownNote, err := store.NewNoteStore(srv.db).Create(b.id, "Own note", "", nil, false, "normal", nil)
+		if err != nil {
+			t.Fatalf("create note: %v", err)
+		}
+		ownList, err := groceries.GetDefaultList(b.id)
+		if err != nil {
+			t.Fatalf("get default list: %v", err)
+		}
+		ownItem, err := groceries.CreateItem(ownList.ID, b.id, "Own milk", "", "", "", "Dairy", nil)
+		if err != nil {
+			t.Fatalf("create item: %v", err)
+		}
+		ownListPath := "/api/grocery-lists/" + id(ownList.ID)
+		foreign := id(member.ID)
+		cases := []struct {
+			method, path, body string
+		}{
+			{"POST", "/api/chores/" + id(own.ID) + "/complete", `{"completed_by":` + foreign + `}`},
+			{"POST", "/api/notes", `{"title":"x","author_id":` + foreign + `}`},
+			{"PUT", "/api/notes/" + id(ownNote.ID), `{"title":"x","author_id":` + foreign + `}`},
+			{"POST", ownListPath + "/items", `{"name":"Eggs","added_by":` + foreign + `}`},
+			{"POST", ownListPath + "/quick-add", `{"text":"2 eggs","added_by":` + foreign + `}`},
+			{"POST", ownListPath + "/items/" + id(ownItem.ID) + "/check", `{"checked_by":` + foreign + `}`},
+		}
+		for _, tc := range cases {
+			rec := doRequest(t, h, b, tc.method, tc.path, tc.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s %s status = %d, want %d", tc.method, tc.path, rec.Code, http.StatusBadRequest)
+			}
+		}
+		if completions, _ := chores.ListCompletionsByChore(own.ID, b.id); len(completions) != 0 {
+			t.Errorf("completions = %d, want 0", len(completions))
+		}
+		if items := decodeList(t, doRequest(t, h, b, "GET", ownListPath+"/items", "")); len(items) != 1 || items[0]["checked"] == true {
+			t.Errorf("items = %v, want only the unchecked milk", items)
+		}
+
+		// Nor does an active user cookie naming them count.
+		req := httptest.NewRequest("POST", "/partials/chores/"+id(own.ID)+"/complete", nil)
+		req.AddCookie(&http.Cookie{Name: "gamwich_session", Value: b.token})
+		req.AddCookie(&http.Cookie{Name: "gamwich_active_user", Value: foreign})
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		completions, _ := chores.ListCompletionsByChore(own.ID, b.id)
+		if len(completions) != 1 || completions[0].CompletedBy != nil {
+			t.Errorf("completions = %+v, want one by nobody", completions)
+		}
+	})
+
+	t.Run("instance settings belong to the default household", func(t *testing.T) {
+		settings := store.NewSettingsStore(srv.db)
+		if err := settings.Set(a.id, "backup_s3_secret_key", "s3-secret"); err != nil {
+			t.Fatalf("set S3 secret: %v", err)
+		}
+		cases := []struct {
+			method, path, body string
+		}{
+			{"GET", "/api/settings/s3", ""},
+			{"PUT", "/api/settings/s3", `{"backup_s3_secret_key":"stolen"}`},
+			{"GET", "/partials/settings/s3", ""},
+			{"PUT", "/partials/settings/s3?backup_s3_secret_key=stolen", ""},
+			{"GET", "/partials/settings/tunnel", ""},
+			{"PUT", "/partials/settings/tunnel?tunnel_token=stolen&tunnel_enabled=true", ""},
+			{"GET", "/partials/settings/tunnel/status", ""},
+			{"GET", "/partials/settings/license", ""},
+			{"PUT", "/partials/settings/license?license_key=stolen", ""},
+		}
+		for _, tc := range cases {
+			rec := doRequest(t, h, b, tc.method, tc.path, tc.body)
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("%s %s status = %d, want %d", tc.method, tc.path, rec.Code, http.StatusForbidden)
+			}
+			if strings.Contains(rec.Body.String(), "s3-secret") {
+				t.Errorf("%s %s leaked the S3 secret", tc.method, tc.path)
+			}
+		}
+		for _, key := range []string{"backup_s3_secret_key", "tunnel_token", "license_key"} {
+			if v, _ := settings.Get(a.id, key); v == "stolen" {
+				t.Errorf("other household overwrote %s", key)
+			}
+		}
+		if rec := doRequest(t, h, a, "GET", "/partials/settings/s3", ""); rec.Code != http.StatusOK {
+			t.Errorf("GET S3 settings as the default household = %d, want %d", rec.Code, http.StatusOK)
+		}
+		if rec := doRequest(t, h, b, "GET", "/partials/settings", ""); strings.Contains(rec.Body.String(), "/partials/settings/s3") {
+			t.Error("settings page offers S3 storage to another household")
+		}
+	})
+
+	t.Run("partials do not render other household data", func(t *testing.T) {
+		for path, secret := range map[string]string{
+			"/partials/chores":  "Secret dishes",
+			"/partials/grocery": "Secret milk",
+			"/partials/notes":   "Secret note",
+		} {
+			rec := doRequest(t, h, b, "GET", path, "")
+			if rec.Code != http.StatusOK {
+				t.Errorf("GET %s status = %d, want %d", path, rec.Code, http.StatusOK)
+			}
+			if strings.Contains(rec.Body.String(), secret) {
+				t.Errorf("GET %s leaked %q to other household", path, secret)
+			}
+			rec = doRequest(t, h, a, "GET", path, "")
+			if !strings.Contains(rec.Body.String(), secret) {
+				t.Errorf("GET %s did not render %q for owning household", path, secret)
+			}
+		}
+	})
+
+	t.Run("settings are per household", func(t *testing.T) {
+		rec := doRequest(t, h, b, "PUT", "/api/settings/kiosk", `{"idle_timeout_minutes":"30"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("PUT kiosk status = %d: %s", rec.Code, rec.Body.String())
+		}
+		rec = doRequest(t, h, a, "GET", "/api/settings/kiosk", "")
+		var settings map[string]string
+		json.Unmarshal(rec.Body.Bytes(), &settings)
+		if settings["idle_timeout_minutes"] == "30" {
+			t.Error("other household's kiosk settings leaked into household A")
+		}
+	})
+
+	t.Run("owning household data is intact", func(t *testing.T) {
+		if got := decodeList(t, doRequest(t, h, a, "GET", "/api/family-members", "")); len(got) != 1 {
+			t.Errorf("members = %d, want 1", len(got))
+		}
+		if got := decodeList(t, doRequest(t, h, a, "GET", "/api/chores", "")); len(got) != 1 {
+			t.Errorf("chores = %d, want 1", len(got))
+		}
+		if got := decodeList(t, doRequest(t, h, a, "GET", listPath, "")); len(got) != 1 {
+			t.Errorf("grocery items = %d, want 1", len(got))
+		}
+		if got := decodeList(t, doRequest(t, h, a, "GET", "/api/notes", "")); len(got) != 1 {
+			t.Errorf("notes = %d, want 1", len(got))
+		}
+		if got := decodeList(t, doRequest(t, h, a, "GET", "/api/rewards", "")); len(got) != 1 {
+			t.Errorf("rewards = %d, want 1", len(got))
+		}
+		if rec := doRequest(t, h, a, "GET", "/api/events/"+id(event.ID), ""); rec.Code != http.StatusOK {
+			t.Errorf("GET event status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+}
+
+func TestHouseholdTimezone(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	if rec := doRequest(t, h, a, "PUT", "/api/settings/timezone", `{"timezone":"Mars/Olympus_Mons"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("PUT unknown timezone = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := doRequest(t, h, a, "PUT", "/api/settings/timezone", `{"timezone":"America/New_York"}`); rec.Code != http.StatusOK {
+		t.Fatalf("PUT timezone = %d: %s", rec.Code, rec.Body.String())
+	}
+	rec := doRequest(t, h, a, "GET", "/api/settings/timezone", "")
+	if !strings.Contains(rec.Body.String(), "America/New_York") {
+		t.Errorf("GET timezone = %s", rec.Body.String())
+	}
+
+	// A weekly 2:30am event meets the Mar 8, 2026 DST gap, when 2:30am
+	// New York time does not exist, and moves to 3:30am that day only.
+	start := time.Date(2026, 3, 1, 2, 30, 0, 0, time.UTC)
+	if _, err := store.NewEventStore(srv.db).CreateWithRecurrence(a.id, "Night feed", "", start, start.Add(30*time.Minute), false, nil, "", "FREQ=WEEKLY"); err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	events := decodeList(t, doRequest(t, h, a, "GET", "/api/events?start=2026-03-01&end=2026-03-16", ""))
+	var got []string
+	for _, e := range events {
+		got = append(got, e["start_time"].(string))
+	}
+	want := []string{"2026-03-01T02:30:00Z", "2026-03-08T03:30:00Z", "2026-03-15T02:30:00Z"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("occurrences = %v, want %v", got, want)
+	}
+}
+
+func TestEventConflicts(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	alice, _ := store.NewFamilyMemberStore(srv.db).Create(a.id, "Alice", "#FF0000", "😀")
+	rec := doRequest(t, h, a, "POST", "/api/events", fmt.Sprintf(`{"title":"Piano","start_time":"2026-05-04T16:00:00Z","end_time":"2026-05-04T17:00:00Z","family_member_id":%d,"location":"Studio"}`, alice.ID))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "conflicts") {
+		t.Errorf("first event reported conflicts: %s", rec.Body.String())
+	}
+
+	rec = doRequest(t, h, a, "POST", "/api/events", fmt.Sprintf(`{"title":"Soccer","start_time":"2026-05-04T16:30:00Z","end_time":"2026-05-04T18:00:00Z","family_member_id":%d,"location":"Park"}`, alice.ID))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body.String())
+	}
+	var created struct {
+		Title     string `json:"title"`
+		Conflicts []struct {
+			Kind string `json:"kind"`
+		} `json:"conflicts"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	if created.Title != "Soccer" || len(created.Conflicts) != 1 || created.Conflicts[0].Kind != "member" {
+		t.Errorf("create response = %s", rec.Body.String())
+	}
+
+	// Driven events elsewhere only conflict when drivers are asked for, and
+	// only with each other: Soccer has a location but nobody driving.
+	mom, _ := store.NewFamilyMemberStore(srv.db).Create(a.id, "Mom", "#00FF00", "😀")
+	dad, _ := store.NewFamilyMemberStore(srv.db).Create(a.id, "Dad", "#0000FF", "😀")
+	doRequest(t, h, a, "POST", "/api/events", fmt.Sprintf(`{"title":"Dinner","start_time":"2026-05-04T17:30:00Z","end_time":"2026-05-04T19:00:00Z","location":"Grandma's","attendees":[{"family_member_id":%d,"role":"driver"}]}`, mom.ID))
+	doRequest(t, h, a, "POST", "/api/events", fmt.Sprintf(`{"title":"Swim","start_time":"2026-05-04T17:45:00Z","end_time":"2026-05-04T18:30:00Z","location":"Pool","attendees":[{"family_member_id":%d,"role":"driver"}]}`, dad.ID))
+	if got := decodeList(t, doRequest(t, h, a, "GET", "/api/events/conflicts?start=2026-05-04&end=2026-05-05", "")); len(got) != 1 {
+		t.Errorf("conflicts = %d, want 1", len(got))
+	}
+	if got := decodeList(t, doRequest(t, h, a, "GET", "/api/events/conflicts?start=2026-05-04&end=2026-05-05&drivers=true", "")); len(got) != 2 {
+		t.Errorf("conflicts with drivers = %d, want 2", len(got))
+	}
+	for _, query := range []string{"", "?start=2026-05-04&end=2026-05-04", "?start=2026-05-05&end=2026-05-04", "?start=1900-01-01&end=2100-01-01"} {
+		if rec := doRequest(t, h, a, "GET", "/api/events/conflicts"+query, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("conflicts%s = %d, want %d", query, rec.Code, http.StatusBadRequest)
+		}
+	}
+	if rec := doRequest(t, h, a, "GET", "/api/events/conflicts?start=2026-05-01&end=2026-06-01", ""); rec.Code != http.StatusOK {
+		t.Errorf("conflicts for a month = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestEventAttendees(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	members := store.NewFamilyMemberStore(srv.db)
+	kid, _ := members.Create(a.id, "Kid", "#FF0000", "🧒")
+	dad, _ := members.Create(a.id, "Dad", "#0000FF", "🧔")
+	mom, _ := members.Create(a.id, "Mom", "#00FF00", "👩")
+
+	body := fmt.Sprintf(`{"title":"Soccer","start_time":"2026-05-04T16:00:00Z","end_time":"2026-05-04T17:00:00Z","attendees":[{"family_member_id":%d},{"family_member_id":%d,"role":"driver"}]}`, kid.ID, dad.ID)
+	rec := doRequest(t, h, a, "POST", "/api/events", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body.String())
+	}
+	var event struct {
+		FamilyMemberID int64 `json:"family_member_id"`
+		Attendees      []struct {
+			FamilyMemberID int64  `json:"family_member_id"`
+			Role           string `json:"role"`
+		} `json:"attendees"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &event)
+	if event.FamilyMemberID != kid.ID || len(event.Attendees) != 2 || event.Attendees[1].Role != "driver" {
+		t.Errorf("created event = %s", rec.Body.String())
+	}
+
+	bad := fmt.Sprintf(`{"title":"Nap","start_time":"2026-05-04T13:00:00Z","end_time":"2026-05-04T14:00:00Z","attendees":[{"family_member_id":%d,"role":"chaperone"}]}`, kid.ID)
+	if rec := doRequest(t, h, a, "POST", "/api/events", bad); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown role = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	// Filtering the day view to the driver shows the event; filtering to
+	// someone not going hides it.
+	if rec := doRequest(t, h, a, "GET", fmt.Sprintf("/partials/calendar/day?date=2026-05-04&member=%d", dad.ID), ""); !strings.Contains(rec.Body.String(), "Soccer") {
+		t.Errorf("driver's day view is missing the event")
+	}
+	if rec := doRequest(t, h, a, "GET", fmt.Sprintf("/partials/calendar/day?date=2026-05-04&member=%d", mom.ID), ""); strings.Contains(rec.Body.String(), "Soccer") {
+		t.Errorf("day view for someone not going shows the event")
+	}
+}
+
+func TestChoreRotation(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	members := store.NewFamilyMemberStore(srv.db)
+	kid, _ := members.Create(a.id, "Kid", "#FF0000", "🧒")
+	teen, _ := members.Create(a.id, "Teen", "#0000FF", "🧑")
+	outsider, _ := members.Create(a.id, "Grandma", "#00FF00", "👵")
+
+	body := fmt.Sprintf(`{"title":"Dishes","recurrence_rule":"FREQ=DAILY","rotation_cadence":"completion","rotation_members":[%d,%d]}`, kid.ID, teen.ID)
+	rec := doRequest(t, h, a, "POST", "/api/chores", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body.String())
+	}
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	path := fmt.Sprintf("/api/chores/%d/rotation", created.ID)
+
+	type rotation struct {
+		Current struct {
+			MemberID int64 `json:"member_id"`
+		} `json:"current"`
+		History []struct {
+			Kind string `json:"kind"`
+		} `json:"history"`
+	}
+	current := func(rec *httptest.ResponseRecorder) rotation {
+		t.Helper()
+		var r rotation
+		if err := json.Unmarshal(rec.Body.Bytes(), &r); err != nil {
+			t.Fatalf("decode rotation: %v: %s", err, rec.Body.String())
+		}
+		return r
+	}
+
+	if r := current(doRequest(t, h, a, "GET", path, "")); r.Current.MemberID != kid.ID {
+		t.Errorf("first turn = %d, want kid %d", r.Current.MemberID, kid.ID)
+	}
+
+	rec = doRequest(t, h, a, "POST", path+"/skip", "")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("skip = %d: %s", rec.Code, rec.Body.String())
+	}
+	if r := current(rec); r.Current.MemberID != teen.ID {
+		t.Errorf("after skip = %d, want teen %d", r.Current.MemberID, teen.ID)
+	}
+
+	if rec := doRequest(t, h, a, "POST", path+"/swap", fmt.Sprintf(`{"member_id":%d}`, outsider.ID)); rec.Code != http.StatusBadRequest {
+		t.Errorf("swap outside rotation = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	rec = doRequest(t, h, a, "POST", path+"/swap", fmt.Sprintf(`{"member_id":%d}`, kid.ID))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("swap = %d: %s", rec.Code, rec.Body.String())
+	}
+	r := current(rec)
+	if r.Current.MemberID != kid.ID {
+		t.Errorf("after swap = %d, want kid %d", r.Current.MemberID, kid.ID)
+	}
+	if len(r.History) != 2 || r.History[0].Kind != "skip" || r.History[1].Kind != "swap" {
+		t.Errorf("history = %+v, want skip then swap", r.History)
+	}
+
+	// The by-person view lists the chore under whoever's turn it is.
+	rec = doRequest(t, h, a, "GET", "/partials/chores/by-person", "")
+	page := rec.Body.String()
+	if i := strings.Index(page, "Kid"); i < 0 || !strings.Contains(page[i:], "Dishes") {
+		t.Errorf("by-person view does not list the chore under the kid")
+	}
+	if !strings.Contains(page, "Swap with 🧑 Teen") {
+		t.Errorf("chore card does not offer a swap with the teen")
+	}
+
+	plain := doRequest(t, h, a, "POST", "/api/chores", `{"title":"Trash"}`)
+	json.Unmarshal(plain.Body.Bytes(), &created)
+	if rec := doRequest(t, h, a, "POST", fmt.Sprintf("/api/chores/%d/rotation/skip", created.ID), ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("skip on a chore that does not rotate = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestMemberChoreStats(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	kid, _ := store.NewFamilyMemberStore(srv.db).Create(a.id, "Kid", "#FF0000", "🧒")
+	chores := store.NewChoreStore(srv.db)
+	c, _ := chores.Create(a.id, "Feed cat", "", nil, 1, "FREQ=DAILY", &kid.ID)
+
+	// Due three days ago, two days ago, yesterday and today: done on time,
+	// missed, done on time, still open.
+	loc, _ := store.NewSettingsStore(srv.db).Location(a.id)
+	now := time.Now().In(loc)
+	day := func(offset int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+offset, 12, 0, 0, 0, loc).UTC()
+	}
+	srv.db.Exec(`UPDATE chores SET created_at = ? WHERE id = ?`, day(-3), c.ID)
+	for _, offset := range []int{-3, -1} {
+		comp, _ := chores.CreateCompletion(c.ID, a.id, &kid.ID, 1)
+		srv.db.Exec(`UPDATE chore_completions SET completed_at = ? WHERE id = ?`, day(offset), comp.ID)
+	}
+
+	rec := doRequest(t, h, a, "GET", fmt.Sprintf("/api/family-members/%d/stats", kid.ID), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("stats = %d: %s", rec.Code, rec.Body.String())
+	}
+	var stats struct {
+		Occurrences   int     `json:"occurrences"`
+		Missed        int     `json:"missed"`
+		OnTimeRate    float64 `json:"on_time_rate"`
+		CurrentStreak int     `json:"current_streak"`
+		BestStreak    int     `json:"best_streak"`
+		Chores        []struct {
+			ChoreID int64 `json:"chore_id"`
+			Missed  int   `json:"missed"`
+		} `json:"chores"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &stats)
+	if stats.Occurrences != 3 || stats.Missed != 1 || stats.CurrentStreak != 1 || stats.BestStreak != 1 {
+		t.Errorf("stats = %s", rec.Body.String())
+	}
+	if len(stats.Chores) != 1 || stats.Chores[0].ChoreID != c.ID || stats.Chores[0].Missed != 1 {
+		t.Errorf("per-chore stats = %+v", stats.Chores)
+	}
+
+	if rec := doRequest(t, h, a, "GET", "/api/family-members/9999/stats", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown member = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	if rec := doRequest(t, h, a, "GET", "/partials/chores", ""); !strings.Contains(rec.Body.String(), "67% on time") {
+		t.Errorf("chores page does not show the kid's on-time rate")
+	}
+}
+
+func TestChoreOccurrences(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	kid, _ := store.NewFamilyMemberStore(srv.db).Create(a.id, "Kid", "#FF0000", "🧒")
+	chores := store.NewChoreStore(srv.db)
+	c, _ := chores.Create(a.id, "Feed cat", "", nil, 1, "FREQ=DAILY", &kid.ID)
+
+	loc, _ := store.NewSettingsStore(srv.db).Location(a.id)
+	now := time.Now().In(loc)
+	day := func(offset int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+offset, 12, 0, 0, 0, loc)
+	}
+	srv.db.Exec(`UPDATE chores SET created_at = ? WHERE id = ?`, day(-2).UTC(), c.ID)
+	path := fmt.Sprintf("/api/chores/%d/occurrences", c.ID)
+
+	// Excusing yesterday takes it out of the missed count.
+	yesterday := day(-1).Format("2006-01-02")
+	rec := doRequest(t, h, a, "PUT", path+"/"+yesterday, `{"status":"excused","note":"sick"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("excuse = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doRequest(t, h, a, "PUT", path+"/"+day(1).Format("2006-01-02"), `{"status":"excused"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("excuse a future date = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := doRequest(t, h, a, "PUT", path+"/"+yesterday, `{"status":"missed"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("set status missed = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	var history []struct {
+		DueDate string `json:"due_date"`
+		Status  string `json:"status"`
+		Note    string `json:"note"`
+	}
+	rec = doRequest(t, h, a, "GET", path, "")
+	json.Unmarshal(rec.Body.Bytes(), &history)
+	want := []string{"missed", "excused", "open"}
+	if len(history) != len(want) {
+		t.Fatalf("history = %s", rec.Body.String())
+	}
+	for i, o := range history {
+		if o.Status != want[i] {
+			t.Errorf("%s: status = %q, want %q", o.DueDate, o.Status, want[i])
+		}
+	}
+	if history[1].Note != "sick" {
+		t.Errorf("note = %q, want %q", history[1].Note, "sick")
+	}
+
+	// Excusing today from the chores page.
+	rec = doRequest(t, h, a, "POST", fmt.Sprintf("/partials/chores/%d/excuse", c.ID), "")
+	if !strings.Contains(rec.Body.String(), "Excused") {
+		t.Errorf("chore card is not marked excused after excusing today")
+	}
+	if !strings.Contains(rec.Body.String(), "1 missed") {
+		t.Errorf("chore card does not count the missed occurrence")
+	}
+
+	if rec := doRequest(t, h, a, "DELETE", path+"/"+yesterday, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("clear = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	rec = doRequest(t, h, a, "GET", path, "")
+	json.Unmarshal(rec.Body.Bytes(), &history)
+	if history[1].Status != "missed" {
+		t.Errorf("cleared occurrence status = %q, want missed", history[1].Status)
+	}
+}
+
+func TestChoreDeadlines(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	rec := doRequest(t, h, a, "POST", "/api/chores", `{"title":"Return library books","due_at":"`+past+`"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body.String())
+	}
+	var created struct {
+		ID    int64   `json:"id"`
+		DueAt *string `json:"due_at"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	if created.DueAt == nil {
+		t.Fatalf("created chore has no due_at: %s", rec.Body.String())
+	}
+	if rec := doRequest(t, h, a, "GET", "/partials/chores", ""); !strings.Contains(rec.Body.String(), "Overdue") {
+		t.Error("chore past its due_at is not shown as overdue")
+	}
+
+	// Updating without a deadline clears it.
+	path := fmt.Sprintf("/api/chores/%d", created.ID)
+	rec = doRequest(t, h, a, "PUT", path, `{"title":"Return library books"}`)
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	if rec.Code != http.StatusOK || created.DueAt != nil {
+		t.Errorf("update = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = doRequest(t, h, a, "POST", "/api/chores", `{"title":"Make bed","recurrence_rule":"FREQ=DAILY","due_time":"08:00"}`)
+	if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), `"due_time":"08:00"`) {
+		t.Errorf("create with due_time = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	for _, body := range []string{
+		`{"title":"Bad","recurrence_rule":"FREQ=DAILY","due_time":"8am"}`,
+		`{"title":"Bad","due_time":"08:00"}`,
+		`{"title":"Bad","recurrence_rule":"FREQ=DAILY","due_at":"` + past + `"}`,
+	} {
+		if rec := doRequest(t, h, a, "POST", "/api/chores", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("create %s = %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestChoreApproval(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	members := store.NewFamilyMemberStore(srv.db)
+	kid, _ := members.Create(store.DefaultHouseholdID, "Kid", "#00FF00", "🧒")
+	parent, _ := members.Create(store.DefaultHouseholdID, "Parent", "#0000FF", "🧑")
+	hash, _ := bcrypt.GenerateFromPassword([]byte("1234"), bcrypt.MinCost)
+	members.SetPIN(parent.ID, store.DefaultHouseholdID, string(hash))
+	members.SetPIN(kid.ID, store.DefaultHouseholdID, string(hash))
+	sibling, _ := members.Create(store.DefaultHouseholdID, "Sibling", "#FFFF00", "👧")
+	members.SetPIN(sibling.ID, store.DefaultHouseholdID, string(hash))
+	members.SetProfile(sibling.ID, store.DefaultHouseholdID, "", model.RoleChild, nil)
+
+	rec := doRequest(t, h, a, "POST", "/api/chores", `{"title":"Clean room","points":10,"requires_approval":true}`)
+	if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), `"requires_approval":true`) {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body.String())
+	}
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+
+	rec = doRequest(t, h, a, "POST", fmt.Sprintf("/api/chores/%d/complete", created.ID), fmt.Sprintf(`{"completed_by":%d}`, kid.ID))
+	var completion struct {
+		ID           int64  `json:"id"`
+		Status       string `json:"status"`
+		PointsEarned int    `json:"points_earned"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &completion)
+	if rec.Code != http.StatusCreated || completion.Status != "pending" || completion.PointsEarned != 0 {
+		t.Fatalf("complete = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = doRequest(t, h, a, "GET", "/api/chores/approvals", "")
+	if list := decodeList(t, rec); len(list) != 1 {
+		t.Fatalf("approvals = %d, want 1", len(list))
+	}
+	if rec := doRequest(t, h, a, "GET", "/partials/dashboard", ""); !strings.Contains(rec.Body.String(), "Clean room") {
+		t.Error("dashboard does not show the completion waiting for approval")
+	}
+
+	review := fmt.Sprintf("/api/chores/completions/%d/review", completion.ID)
+	for _, tc := range []struct {
+		body string
+		want int
+	}{
+		{fmt.Sprintf(`{"status":"approved","reviewed_by":%d,"pin":"0000"}`, parent.ID), http.StatusUnauthorized},
+		{fmt.Sprintf(`{"status":"approved","reviewed_by":%d,"pin":"1234"}`, kid.ID), http.StatusBadRequest},
+		{fmt.Sprintf(`{"status":"approved","reviewed_by":%d,"pin":"1234"}`, sibling.ID), http.StatusForbidden},
+		{fmt.Sprintf(`{"status":"maybe","reviewed_by":%d,"pin":"1234"}`, parent.ID), http.StatusBadRequest},
+	} {
+		if rec := doRequest(t, h, a, "POST", review, tc.body); rec.Code != tc.want {
+			t.Errorf("review %s = %d, want %d", tc.body, rec.Code, tc.want)
+		}
+	}
+
+	// A child's PIN does not approve on the kiosk either.
+	rec = doRequest(t, h, a, "POST", fmt.Sprintf("/partials/family-members/%d/pin/verify?pin=1234&next_action=approve&completion_id=%d", sibling.ID, completion.ID), "")
+	if !strings.Contains(rec.Body.String(), "only adults with a PIN can approve chores") {
+		t.Errorf("kiosk approval by a child = %d: %s", rec.Code, rec.Body.String())
+	}
+	if list := decodeList(t, doRequest(t, h, a, "GET", "/api/chores/approvals", "")); len(list) != 1 {
+		t.Errorf("approvals after a child's approval = %d, want 1", len(list))
+	}
+
+	rec = doRequest(t, h, a, "GET", fmt.Sprintf("/api/family-members/%d/points", kid.ID), "")
+	if !strings.Contains(rec.Body.String(), `"total_earned":0`) {
+		t.Errorf("balance before approval = %s", rec.Body.String())
+	}
+
+	rec = doRequest(t, h, a, "POST", review, fmt.Sprintf(`{"status":"approved","reviewed_by":%d,"pin":"1234","comment":"Nice"}`, parent.ID))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"approved"`) {
+		t.Fatalf("approve = %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doRequest(t, h, a, "GET", fmt.Sprintf("/api/family-members/%d/points", kid.ID), "")
+	if !strings.Contains(rec.Body.String(), `"total_earned":10`) {
+		t.Errorf("balance after approval = %s", rec.Body.String())
+	}
+
+	if rec := doRequest(t, h, a, "POST", review, fmt.Sprintf(`{"status":"rejected","reviewed_by":%d,"pin":"1234"}`, parent.ID)); rec.Code != http.StatusBadRequest {
+		t.Errorf("second review = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestChoreTemplates(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	packs := decodeList(t, doRequest(t, h, a, "GET", "/api/chores/packs", ""))
+	if len(packs) == 0 {
+		t.Fatal("no starter packs")
+	}
+
+	rec := doRequest(t, h, a, "POST", "/api/chores/packs/teens/import", "")
+	var imported struct {
+		Created int `json:"created"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &imported)
+	if rec.Code != http.StatusOK || imported.Created == 0 {
+		t.Fatalf("import pack = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doRequest(t, h, a, "POST", "/api/chores/packs/teens/import", ""); !strings.Contains(rec.Body.String(), `"created":0`) {
+		t.Errorf("import pack again = %s, want nothing created", rec.Body.String())
+	}
+	if rec := doRequest(t, h, a, "POST", "/api/chores/packs/nope/import", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("import unknown pack = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	rec = doRequest(t, h, a, "POST", "/api/chores/templates", `{"name":"Teen chores"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("save template = %d: %s", rec.Code, rec.Body.String())
+	}
+	var saved struct {
+		ID        int64  `json:"id"`
+		ShareCode string `json:"share_code"`
+		Chores    []any  `json:"chores"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &saved)
+	if len(saved.Chores) != imported.Created {
+		t.Errorf("template chores = %d, want %d", len(saved.Chores), imported.Created)
+	}
+	if list := decodeList(t, doRequest(t, h, a, "GET", "/api/chores/templates", "")); len(list) != 1 {
+		t.Errorf("templates = %d, want 1", len(list))
+	}
+
+	// Another household imports it by share code, or when registering.
+	other, _ := srv.householdStore.Create("Other")
+	srv.householdStore.SeedDefaults(other.ID)
+	b := loginHousehold(t, srv, other.ID, "b@example.com")
+	if rec := doRequest(t, h, b, "POST", fmt.Sprintf("/api/chores/templates/%d/import", saved.ID), ""); rec.Code != http.StatusNotFound {
+		t.Errorf("import other household's template by id = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	rec = doRequest(t, h, b, "POST", "/api/chores/templates/import", `{"share_code":"`+strings.ToLower(saved.ShareCode)+`"}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), fmt.Sprintf(`"created":%d`, imported.Created)) {
+		t.Errorf("import by share code = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	form := url.Values{"email": {"c@example.com"}, "household_name": {"Starter"}, "template_code": {saved.ShareCode}}
+	req := httptest.NewRequest("POST", "/register", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("register = %d: %s", rec.Code, rec.Body.String())
+	}
+	var registered int
+	srv.db.QueryRow(`SELECT COUNT(*) FROM chores c JOIN households hh ON hh.id = c.household_id WHERE hh.name = 'Starter'`).Scan(&registered)
+	if registered != imported.Created {
+		t.Errorf("registered household chores = %d, want %d", registered, imported.Created)
+	}
+
+	form.Set("email", "d@example.com")
+	form.Set("template_code", "NOPE")
+	req = httptest.NewRequest("POST", "/register", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("register with unknown code = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	if rec := doRequest(t, h, a, "DELETE", fmt.Sprintf("/api/chores/templates/%d", saved.ID), ""); rec.Code != http.StatusNoContent {
+		t.Errorf("delete template = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	if rec := doRequest(t, h, a, "GET", "/partials/chores/manage", ""); !strings.Contains(rec.Body.String(), "Starter Packs") {
+		t.Error("manage page does not list starter packs")
+	}
+}
+
+func TestMemberProfiles(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	rec := doRequest(t, h, a, "POST", "/api/family-members", `{"name":"Kid","role":"child","birthdate":"2018-04-02"}`)
+	var kid struct {
+		ID            int64  `json:"id"`
+		Role          string `json:"role"`
+		Birthdate     string `json:"birthdate"`
+		PointsPercent *int   `json:"points_percent"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &kid)
+	if rec.Code != http.StatusCreated || kid.Role != "child" || kid.Birthdate != "2018-04-02" {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	path := fmt.Sprintf("/api/family-members/%d", kid.ID)
+	for _, body := range []string{
+		`{"name":"Kid","role":"toddler"}`,
+		`{"name":"Kid","birthdate":"02/04/2018"}`,
+		`{"name":"Kid","birthdate":"2999-01-01"}`,
+		`{"name":"Kid","points_percent":-5}`,
+	} {
+		if rec := doRequest(t, h, a, "PUT", path, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("update %s = %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+
+	// Fields left out are kept.
+	rec = doRequest(t, h, a, "PUT", path, `{"name":"Kiddo","points_percent":200}`)
+	json.Unmarshal(rec.Body.Bytes(), &kid)
+	if rec.Code != http.StatusOK || kid.Role != "child" || kid.PointsPercent == nil || *kid.PointsPercent != 200 {
+		t.Fatalf("update = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Children cannot be given chores above their age, and earn points at
+	// their multiplier.
+	body := fmt.Sprintf(`{"title":"Mow the lawn","points":5,"min_age":14,"assigned_to":%d}`, kid.ID)
+	if rec := doRequest(t, h, a, "POST", "/api/chores", body); rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "too young") {
+		t.Errorf("assign too-young member = %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doRequest(t, h, a, "POST", "/api/chores", `{"title":"Feed the cat","points":3}`)
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	rec = doRequest(t, h, a, "POST", fmt.Sprintf("/api/chores/%d/complete", created.ID), fmt.Sprintf(`{"completed_by":%d}`, kid.ID))
+	if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), `"points_earned":6`) {
+		t.Errorf("complete = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// A child's PIN does not let them make adjustments on the kiosk.
+	members := store.NewFamilyMemberStore(srv.db)
+	hash, _ := bcrypt.GenerateFromPassword([]byte("1234"), bcrypt.MinCost)
+	members.SetPIN(kid.ID, store.DefaultHouseholdID, string(hash))
+	sibling, _ := members.Create(store.DefaultHouseholdID, "Sibling", "#FF0000", "S")
+	adjust := fmt.Sprintf(`{"amount":50,"kind":"bonus","reason":"Helped","actor_id":%d,"pin":"1234"}`, kid.ID)
+	if rec := doRequest(t, h, a, "POST", fmt.Sprintf("/api/family-members/%d/points/adjustments", sibling.ID), adjust); rec.Code != http.StatusForbidden {
+		t.Errorf("adjust by child = %d, want %d: %s", rec.Code, http.StatusForbidden, rec.Body.String())
+	}
+
+	if rec := doRequest(t, h, a, "GET", "/partials/family-members", ""); !strings.Contains(rec.Body.String(), "Born 2018-04-02") {
+		t.Error("family settings do not show the birthdate")
+	}
+}
+
+func TestPointAdjustments(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	members := store.NewFamilyMemberStore(srv.db)
+	kid, _ := members.Create(store.DefaultHouseholdID, "Kid", "#00FF00", "🧒")
+	parent, _ := members.Create(store.DefaultHouseholdID, "Parent", "#0000FF", "🧑")
+	hash, _ := bcrypt.GenerateFromPassword([]byte("1234"), bcrypt.MinCost)
+	members.SetPIN(parent.ID, store.DefaultHouseholdID, string(hash))
+
+	adjust := fmt.Sprintf("/api/family-members/%d/points/adjustments", kid.ID)
+	for _, tc := range []struct {
+		body string
+		want int
+	}{
+		{fmt.Sprintf(`{"kind":"bonus","amount":5,"reason":"Helped","actor_id":%d,"pin":"0000"}`, parent.ID), http.StatusUnauthorized},
+		{fmt.Sprintf(`{"kind":"bonus","amount":-5,"reason":"Helped","actor_id":%d,"pin":"1234"}`, parent.ID), http.StatusBadRequest},
+		{fmt.Sprintf(`{"kind":"earn","amount":5,"reason":"Helped","actor_id":%d,"pin":"1234"}`, parent.ID), http.StatusBadRequest},
+		{fmt.Sprintf(`{"kind":"bonus","amount":5,"reason":"","actor_id":%d,"pin":"1234"}`, parent.ID), http.StatusBadRequest},
+		{fmt.Sprintf(`{"kind":"bonus","amount":5,"reason":"Helped","actor_id":%d,"pin":"1234"}`, kid.ID), http.StatusBadRequest},
+	} {
+		if rec := doRequest(t, h, a, "POST", adjust, tc.body); rec.Code != tc.want {
+			t.Errorf("adjust %s = %d, want %d: %s", tc.body, rec.Code, tc.want, rec.Body.String())
+		}
+	}
+
+	rec := doRequest(t, h, a, "POST", adjust, fmt.Sprintf(`{"kind":"bonus","amount":5,"reason":"Helped","actor_id":%d,"pin":"1234"}`, parent.ID))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("bonus = %d: %s", rec.Code, rec.Body.String())
+	}
+	doRequest(t, h, a, "POST", adjust, fmt.Sprintf(`{"kind":"penalty","amount":-2,"reason":"Late","actor_id":%d,"pin":"1234"}`, parent.ID))
+
+	rec = doRequest(t, h, a, "GET", fmt.Sprintf("/api/family-members/%d/points", kid.ID), "")
+	if !strings.Contains(rec.Body.String(), `"balance":3`) {
+		t.Errorf("balance = %s, want 3", rec.Body.String())
+	}
+
+	rec = doRequest(t, h, a, "GET", fmt.Sprintf("/api/family-members/%d/points/transactions", kid.ID), "")
+	if list := decodeList(t, rec); len(list) != 2 || list[0]["kind"] != "penalty" {
+		t.Errorf("transactions = %v", list)
+	}
+
+	rec = doRequest(t, h, a, "GET", fmt.Sprintf("/partials/rewards/members/%d/points", kid.ID), "")
+	if body := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(body, "Helped") || !strings.Contains(body, "Parent") {
+		t.Errorf("points history = %d: %s", rec.Code, body)
+	}
+}
+
+func TestRewardRedemptions(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	members := store.NewFamilyMemberStore(srv.db)
+	kid, _ := members.Create(store.DefaultHouseholdID, "Kid", "#00FF00", "🧒")
+	parent, _ := members.Create(store.DefaultHouseholdID, "Parent", "#0000FF", "🧑")
+	hash, _ := bcrypt.GenerateFromPassword([]byte("1234"), bcrypt.MinCost)
+	members.SetPIN(parent.ID, store.DefaultHouseholdID, string(hash))
+	store.NewRewardStore(srv.db).AdjustPoints(kid.ID, store.DefaultHouseholdID, model.PointsBonus, 20, "Allowance", nil)
+
+	rec := doRequest(t, h, a, "POST", "/api/rewards", `{"title":"Movie night","point_cost":5,"active":true,"stock":-1}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("negative stock = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	rec = doRequest(t, h, a, "POST", "/api/rewards", `{"title":"Movie night","point_cost":5,"active":true,"stock":2,"cooldown_days":0}`)
+	var reward model.Reward
+	json.Unmarshal(rec.Body.Bytes(), &reward)
+	if rec.Code != http.StatusCreated || reward.Stock == nil || *reward.Stock != 2 {
+		t.Fatalf("create reward = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	redeem := fmt.Sprintf("/api/rewards/%d/redeem", reward.ID)
+	body := fmt.Sprintf(`{"redeemed_by":%d}`, kid.ID)
+	var first, second model.RewardRedemption
+	rec = doRequest(t, h, a, "POST", redeem, body)
+	json.Unmarshal(rec.Body.Bytes(), &first)
+	if rec.Code != http.StatusCreated || first.Status != model.RedemptionRequested {
+		t.Fatalf("redeem = %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doRequest(t, h, a, "POST", redeem, body)
+	json.Unmarshal(rec.Body.Bytes(), &second)
+	if rec = doRequest(t, h, a, "POST", redeem, body); rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "out of stock") {
+		t.Errorf("redeem out of stock = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if list := decodeList(t, doRequest(t, h, a, "GET", "/api/rewards/redemptions", "")); len(list) != 2 {
+		t.Errorf("open redemptions = %d, want 2", len(list))
+	}
+	rec = doRequest(t, h, a, "GET", "/partials/rewards/list", "")
+	if !strings.Contains(rec.Body.String(), "Reward Requests") {
+		t.Errorf("rewards list does not show the request queue: %s", rec.Body.String())
+	}
+
+	status := func(id int64) string { return fmt.Sprintf("/api/rewards/redemptions/%d/status", id) }
+	for _, tc := range []struct {
+		body string
+		want int
+	}{
+		{fmt.Sprintf(`{"status":"approved","actor_id":%d,"pin":"0000"}`, parent.ID), http.StatusUnauthorized},
+		{fmt.Sprintf(`{"status":"requested","actor_id":%d,"pin":"1234"}`, parent.ID), http.StatusBadRequest},
+		{fmt.Sprintf(`{"status":"approved","actor_id":%d,"pin":"1234"}`, kid.ID), http.StatusBadRequest},
+		{fmt.Sprintf(`{"status":"approved","actor_id":%d,"pin":"1234"}`, parent.ID), http.StatusOK},
+		{fmt.Sprintf(`{"status":"approved","actor_id":%d,"pin":"1234"}`, parent.ID), http.StatusBadRequest},
+		{fmt.Sprintf(`{"status":"fulfilled","actor_id":%d,"pin":"1234"}`, parent.ID), http.StatusOK},
+		{fmt.Sprintf(`{"status":"cancelled","actor_id":%d,"pin":"1234"}`, parent.ID), http.StatusBadRequest},
+	} {
+		if rec := doRequest(t, h, a, "POST", status(first.ID), tc.body); rec.Code != tc.want {
+			t.Errorf("update %s = %d, want %d: %s", tc.body, rec.Code, tc.want, rec.Body.String())
+		}
+	}
+
+	rec = doRequest(t, h, a, "POST", status(second.ID), fmt.Sprintf(`{"status":"cancelled","actor_id":%d,"pin":"1234","note":"Not tonight"}`, parent.ID))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Not tonight") {
+		t.Errorf("cancel = %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doRequest(t, h, a, "GET", fmt.Sprintf("/api/family-members/%d/points", kid.ID), "")
+	if !strings.Contains(rec.Body.String(), `"balance":15`) {
+		t.Errorf("balance after refund = %s, want 15", rec.Body.String())
+	}
+	if list := decodeList(t, doRequest(t, h, a, "GET", "/api/rewards/redemptions", "")); len(list) != 0 {
+		t.Errorf("open redemptions = %d, want 0", len(list))
+	}
+}
+
+func TestAllowances(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	members := store.NewFamilyMemberStore(srv.db)
+	kid, _ := members.Create(store.DefaultHouseholdID, "Kid", "#00FF00", "🧒")
+	parent, _ := members.Create(store.DefaultHouseholdID, "Parent", "#0000FF", "🧑")
+	hash, _ := bcrypt.GenerateFromPassword([]byte("1234"), bcrypt.MinCost)
+	members.SetPIN(parent.ID, store.DefaultHouseholdID, string(hash))
+	store.NewRewardStore(srv.db).AdjustPoints(kid.ID, store.DefaultHouseholdID, model.PointsBonus, 30, "Birthday", nil)
+
+	allowance := fmt.Sprintf("/api/family-members/%d/allowance", kid.ID)
+	rec := doRequest(t, h, a, "GET", allowance, "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"weekly_points":0`) {
+		t.Errorf("get allowance = %d: %s", rec.Code, rec.Body.String())
+	}
+	for _, tc := range []struct {
+		body string
+		want int
+	}{
+		{fmt.Sprintf(`{"cents_per_point":10,"weekly_points":20,"weekday":6,"actor_id":%d,"pin":"0000"}`, parent.ID), http.StatusUnauthorized},
+		{fmt.Sprintf(`{"cents_per_point":-1,"weekly_points":20,"weekday":6,"actor_id":%d,"pin":"1234"}`, parent.ID), http.StatusBadRequest},
+		{fmt.Sprintf(`{"cents_per_point":10,"weekly_points":20,"weekday":7,"actor_id":%d,"pin":"1234"}`, parent.ID), http.StatusBadRequest},
+		{fmt.Sprintf(`{"cents_per_point":10,"weekly_points":20,"weekday":6,"actor_id":%d,"pin":"1234"}`, kid.ID), http.StatusBadRequest},
+		{fmt.Sprintf(`{"cents_per_point":10,"weekly_points":20,"weekday":6,"actor_id":%d,"pin":"1234"}`, parent.ID), http.StatusOK},
+	} {
+		if rec := doRequest(t, h, a, "PUT", allowance, tc.body); rec.Code != tc.want {
+			t.Errorf("set allowance %s = %d, want %d: %s", tc.body, rec.Code, tc.want, rec.Body.String())
+		}
+	}
+
+	goals := fmt.Sprintf("/api/family-members/%d/goals", kid.ID)
+	if rec := doRequest(t, h, a, "POST", goals, `{"title":"Bike","target_points":0}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("goal without target = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	rec = doRequest(t, h, a, "POST", goals, `{"title":"Bike","target_points":120}`)
+	var goal model.SavingsGoal
+	json.Unmarshal(rec.Body.Bytes(), &goal)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create goal = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = doRequest(t, h, a, "GET", fmt.Sprintf("/api/family-members/%d/points", kid.ID), "")
+	var balance model.PointBalance
+	json.Unmarshal(rec.Body.Bytes(), &balance)
+	if balance.ValueCents != 300 || len(balance.Goals) != 1 || balance.Goals[0].Saved != 30 || balance.Goals[0].Percent != 25 {
+		t.Errorf("balance = %s", rec.Body.String())
+	}
+
+	rec = doRequest(t, h, a, "GET", fmt.Sprintf("/api/family-members/%d/points/statement", kid.ID), "")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") ||
+		!strings.Contains(rec.Header().Get("Content-Disposition"), "Kid-") || !strings.Contains(rec.Body.String(), "Birthday,30,30,$3.00") {
+		t.Errorf("statement = %d %v: %s", rec.Code, rec.Header(), rec.Body.String())
+	}
+	if rec := doRequest(t, h, a, "GET", fmt.Sprintf("/api/family-members/%d/points/statement?month=June", kid.ID), ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("statement for bad month = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec = doRequest(t, h, a, "GET", fmt.Sprintf("/partials/rewards/members/%d/points", kid.ID), "")
+	if !strings.Contains(rec.Body.String(), "Bike") || !strings.Contains(rec.Body.String(), "$3.00") {
+		t.Errorf("points history does not show goals and value: %s", rec.Body.String())
+	}
+
+	if rec := doRequest(t, h, a, "DELETE", fmt.Sprintf("%s/%d", goals, goal.ID), ""); rec.Code != http.StatusNoContent {
+		t.Errorf("delete goal = %d", rec.Code)
+	}
+	if list := decodeList(t, doRequest(t, h, a, "GET", goals, "")); len(list) != 0 {
+		t.Errorf("goals after delete = %d, want 0", len(list))
+	}
+}
+
+func TestICalFeeds(t *testing.T) {
+	srv, h := setupTestServer(t)
+
+	other, _ := srv.householdStore.Create("Other Household")
+	srv.householdStore.SeedDefaults(other.ID)
+
+	members := store.NewFamilyMemberStore(srv.db)
+	alice, _ := members.Create(store.DefaultHouseholdID, "Alice", "#FF0000", "😀")
+	bob, _ := members.Create(store.DefaultHouseholdID, "Bob", "#00FF00", "😎")
+	outsider, _ := members.Create(other.ID, "Outsider", "#0000FF", "👻")
+
+	events := store.NewEventStore(srv.db)
+	start := time.Now().Truncate(time.Hour)
+	events.Create(store.DefaultHouseholdID, "Alice dentist", "", start, start.Add(time.Hour), false, &alice.ID, "")
+	events.CreateWithRecurrence(store.DefaultHouseholdID, "Bob soccer", "", start, start.Add(time.Hour), false, &bob.ID, "", "FREQ=WEEKLY")
+	events.Create(other.ID, "Other secret", "", start, start.Add(time.Hour), false, &outsider.ID, "")
+
+	tok, token, err := store.NewICalStore(srv.db).Create(store.DefaultHouseholdID, "Phone", nil)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec
+	}
+
+	rec := get("/ical/" + token + "/household.ics")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("household feed status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("content type = %q, want text/calendar", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"SUMMARY:Alice dentist", "SUMMARY:Bob soccer", "RRULE:FREQ=WEEKLY"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("household feed missing %q", want)
+		}
+	}
+	if strings.Contains(body, "Other secret") {
+		t.Error("household feed leaked another household's event")
+	}
+
+	body = get("/ical/" + token + "/member/" + strconv.FormatInt(alice.ID, 10) + ".ics").Body.String()
+	if !strings.Contains(body, "SUMMARY:Alice dentist") || strings.Contains(body, "Bob soccer") {
+		t.Errorf("member feed not filtered to Alice:\n%s", body)
+	}
+
+	if rec := get("/ical/" + token + "/member/" + strconv.FormatInt(outsider.ID, 10) + ".ics"); rec.Code != http.StatusNotFound {
+		t.Errorf("other household member feed status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := get("/ical/not-a-token/household.ics"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown token status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := get("/ical/" + token + "/member/" + strconv.FormatInt(alice.ID, 10)); rec.Code != http.StatusNotFound {
+		t.Errorf("member feed without .ics status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	// Times are written in the household timezone.
+	if err := store.NewSettingsStore(srv.db).Set(store.DefaultHouseholdID, store.TimezoneKey, "America/New_York"); err != nil {
+		t.Fatalf("set timezone: %v", err)
+	}
+	body = get("/ical/" + token + "/household.ics").Body.String()
+	for _, want := range []string{"BEGIN:VTIMEZONE\r\nTZID:America/New_York\r\n", "DTSTART;TZID=America/New_York:"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("zoned feed missing %q:\n%s", want, body)
+		}
+	}
+
+	// Settings partial lists tokens for admins, but feed URLs are only
+	// shown when a token is created.
+	admin := loginHousehold(t, srv, store.DefaultHouseholdID, "admin@example.com")
+	rec = doRequest(t, h, admin, "GET", "/partials/settings/ical", "")
+	if body := rec.Body.String(); !strings.Contains(body, "Phone") || strings.Contains(body, "/household.ics") {
+		t.Errorf("settings partial should list the token without its URL:\n%s", body)
+	}
+	rec = doRequest(t, h, admin, "POST", "/partials/settings/ical", "")
+	if !strings.Contains(rec.Body.String(), "/household.ics") {
+		t.Errorf("create response missing the new feed URL:\n%s", rec.Body.String())
+	}
+
+	// Revoking the token disables the feed.
+	rec = doRequest(t, h, admin, "DELETE", "/partials/settings/ical/"+strconv.FormatInt(tok.ID, 10), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("revoke status = %d", rec.Code)
+	}
+	if rec := get("/ical/" + token + "/household.ics"); rec.Code != http.StatusNotFound {
+		t.Errorf("revoked token status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestImportedEventsAreReadOnly(t *testing.T) {
+	srv, h := setupTestServer(t)
+
+	other, _ := srv.householdStore.Create("Other Household")
+	srv.householdStore.SeedDefaults(other.ID)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	members := store.NewFamilyMemberStore(srv.db)
+	alice, _ := members.Create(a.id, "Alice", "#FF0000", "😀")
+	bob, _ := members.Create(a.id, "Bob", "#00FF00", "😎")
+	outsider, _ := members.Create(other.ID, "Outsider", "#0000FF", "👻")
+
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:recital@school.example",
+		"SUMMARY:Recital",
+		"DTSTART:20260410T180000",
+		"DTEND:20260410T190000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	rec := doRequest(t, h, a, "POST", "/api/events/import?family_member_id="+strconv.FormatInt(outsider.ID, 10), ics)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("import for other household's member = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	rec = doRequest(t, h, a, "POST", "/api/events/import", "not a calendar")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("import of non-calendar = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	path := "/api/events/import?family_member_id=" + strconv.FormatInt(alice.ID, 10)
+	for i := 0; i < 2; i++ {
+		rec = doRequest(t, h, a, "POST", path, ics)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("import status = %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	imported, err := store.NewEventStore(srv.db).ListImported(a.id, nil)
+	if err != nil {
+		t.Fatalf("list imported: %v", err)
+	}
+	if len(imported) != 1 {
+		t.Fatalf("imported = %d, want 1 after importing twice", len(imported))
+	}
+	event := imported[0]
+	if event.FamilyMemberID == nil || *event.FamilyMemberID != alice.ID {
+		t.Errorf("member = %v, want %d", event.FamilyMemberID, alice.ID)
+	}
+
+	eventPath := "/api/events/" + strconv.FormatInt(event.ID, 10)
+	update := `{"title":"Changed","start_time":"2026-04-10T18:00:00Z","end_time":"2026-04-10T19:00:00Z"}`
+	if rec := doRequest(t, h, a, "PUT", eventPath, update); rec.Code != http.StatusForbidden {
+		t.Errorf("update imported event = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := doRequest(t, h, a, "DELETE", eventPath, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("delete imported event = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	rec = doRequest(t, h, a, "PUT", eventPath+"/member", `{"family_member_id":`+strconv.FormatInt(bob.ID, 10)+`}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("assign member = %d: %s", rec.Code, rec.Body.String())
+	}
+	var assigned map[string]any
+	json.Unmarshal(rec.Body.Bytes(), &assigned)
+	if got, _ := assigned["family_member_id"].(float64); int64(got) != bob.ID {
+		t.Errorf("family_member_id = %v, want %d", assigned["family_member_id"], bob.ID)
+	}
+}
+
+func TestCalDAVCollectionsAPI(t *testing.T) {
+	srv, h := setupTestServer(t)
+
+	other, _ := srv.householdStore.Create("Other Household")
+	srv.householdStore.SeedDefaults(other.ID)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+	b := loginHousehold(t, srv, other.ID, "b@example.com")
+
+	dav := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer dav.Close()
+
+	rec := doRequest(t, h, a, "POST", "/api/caldav-collections", `{"name":"Family","url":"ftp://example.com/cal"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("create with ftp URL = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	body := `{"name":"Family","url":"` + dav.URL + `/cal/","username":"me","password":"secret"}`
+	rec = doRequest(t, h, a, "POST", "/api/caldav-collections", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "secret") {
+		t.Errorf("response leaks the password: %s", rec.Body.String())
+	}
+	var created struct {
+		Collection struct {
+			ID        int64  `json:"id"`
+			LastError string `json:"last_error"`
+		} `json:"collection"`
+		Error string `json:"error"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	if created.Error == "" || created.Collection.LastError == "" {
+		t.Errorf("failed first sync should be reported: %s", rec.Body.String())
+	}
+
+	if got := decodeList(t, doRequest(t, h, b, "GET", "/api/caldav-collections", "")); len(got) != 0 {
+		t.Errorf("other household sees %d collections, want 0", len(got))
+	}
+	path := "/api/caldav-collections/" + strconv.FormatInt(created.Collection.ID, 10)
+	if rec := doRequest(t, h, b, "DELETE", path, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("delete from other household = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := doRequest(t, h, a, "DELETE", path, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("delete = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
+
+func TestCalDAVServer(t *testing.T) {
+	srv, h := setupTestServer(t)
+
+	other, _ := srv.householdStore.Create("Other Household")
+	srv.householdStore.SeedDefaults(other.ID)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+	b := loginHousehold(t, srv, other.ID, "b@example.com")
+
+	appPassword := func(hh testHousehold) string {
+		t.Helper()
+		rec := doRequest(t, h, hh, "POST", "/api/app-passwords", `{"label":"Phone"}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("create app password = %d: %s", rec.Code, rec.Body.String())
+		}
+		var created struct {
+			Password string `json:"password"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &created)
+		return created.Password
+	}
+	passA, passB := appPassword(a), appPassword(b)
+
+	dav := func(email, password, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth(email, password)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	davA := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		return dav("a@example.com", passA, method, path, body, headers)
+	}
+
+	if rec := dav("a@example.com", "wrong", "PROPFIND", "/dav/", "", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong password = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := dav("A@Example.com", strings.ToUpper(passA), "PROPFIND", "/dav/", "", map[string]string{"Depth": "0"}); rec.Code != http.StatusMultiStatus {
+		t.Errorf("case-insensitive sign-in = %d, want %d", rec.Code, http.StatusMultiStatus)
+	}
+
+	home := "/dav/calendars/" + strconv.FormatInt(a.id, 10) + "/"
+	rec := davA("PROPFIND", "/dav/", `<d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:prop><d:current-user-principal/><c:calendar-home-set/></d:prop></d:propfind>`, map[string]string{"Depth": "0"})
+	if rec.Code != http.StatusMultiStatus || !strings.Contains(rec.Body.String(), home) {
+		t.Fatalf("root propfind = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = davA("PROPFIND", home, "", map[string]string{"Depth": "1"})
+	if !strings.Contains(rec.Body.String(), home+"household/") || !strings.Contains(rec.Body.String(), "<c:calendar/>") {
+		t.Errorf("home propfind missing the household calendar: %s", rec.Body.String())
+	}
+
+	coll := home + "household/"
+	object := coll + "swim.ics"
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:swim-uid\r\n" +
+		"DTSTART:20260302T090000\r\nDTEND:20260302T100000\r\nSUMMARY:Swim\r\n" +
+		"RRULE:FREQ=WEEKLY;INTERVAL=1;BYDAY=MO;WKST=SU\r\nEXDATE:20260309T090000\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:swim-uid\r\nRECURRENCE-ID:20260316T090000\r\n" +
+		"DTSTART:20260316T100000\r\nDTEND:20260316T110000\r\nSUMMARY:Swim (late)\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	calHeaders := map[string]string{"Content-Type": "text/calendar", "If-None-Match": "*"}
+
+	if rec := davA("PUT", object, ics, calHeaders); rec.Code != http.StatusCreated {
+		t.Fatalf("put = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := davA("PUT", object, ics, calHeaders); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("put over existing with If-None-Match = %d, want %d", rec.Code, http.StatusPreconditionFailed)
+	}
+	if rec := davA("PUT", coll+"copy.ics", ics, calHeaders); rec.Code != http.StatusForbidden {
+		t.Errorf("put with a duplicate UID = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	byhour := strings.Replace(ics, "BYDAY=MO;WKST=SU", "BYDAY=MO;BYHOUR=9,17", 1)
+	if rec := davA("PUT", coll+"byhour.ics", strings.ReplaceAll(byhour, "swim-uid", "byhour-uid"), calHeaders); rec.Code != http.StatusForbidden {
+		t.Errorf("put with an unsupported rule = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	rec = davA("GET", object, "", nil)
+	body := rec.Body.String()
+	for _, want := range []string{"UID:swim-uid", "RRULE:FREQ=WEEKLY;BYDAY=MO;WKST=SU\r\n", "EXDATE:20260309T090000", "RECURRENCE-ID:20260316T090000", "SUMMARY:Swim (late)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("object missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "INTERVAL") {
+		t.Errorf("rule was not normalized through recurrence.Rule:\n%s", body)
+	}
+	etag := rec.Header().Get("ETag")
+
+	rec = davA("REPORT", coll, `<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:prop><d:getetag/><c:calendar-data/></d:prop><d:href>`+object+`</d:href><d:href>`+coll+`missing.ics</d:href></c:calendar-multiget>`, map[string]string{"Depth": "1"})
+	if rec.Code != http.StatusMultiStatus || !strings.Contains(rec.Body.String(), "UID:swim-uid") || !strings.Contains(rec.Body.String(), "404 Not Found") {
+		t.Errorf("multiget = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	query := `<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:prop><d:getetag/></d:prop><c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT"><c:time-range start="%s" end="%s"/></c:comp-filter></c:comp-filter></c:filter></c:calendar-query>`
+	rec = davA("REPORT", coll, fmt.Sprintf(query, "20260301T000000Z", "20260401T000000Z"), nil)
+	if !strings.Contains(rec.Body.String(), object) {
+		t.Errorf("calendar-query missing the series: %s", rec.Body.String())
+	}
+	rec = davA("REPORT", coll, fmt.Sprintf(query, "20250101T000000Z", "20250201T000000Z"), nil)
+	if strings.Contains(rec.Body.String(), object) {
+		t.Errorf("calendar-query before the series should be empty: %s", rec.Body.String())
+	}
+
+	// Another household's users cannot see this household's calendars.
+	if rec := dav("b@example.com", passB, "PROPFIND", home, "", map[string]string{"Depth": "1"}); rec.Code != http.StatusNotFound {
+		t.Errorf("other household propfind = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	if rec := davA("DELETE", object, "", map[string]string{"If-Match": `"stale"`}); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("delete with stale etag = %d, want %d", rec.Code, http.StatusPreconditionFailed)
+	}
+	if rec := davA("DELETE", object, "", map[string]string{"If-Match": etag}); rec.Code != http.StatusNoContent {
+		t.Errorf("delete = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := davA("GET", object, "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("get after delete = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if events := decodeList(t, doRequest(t, h, a, "GET", "/api/events?start=2026-03-01&end=2026-04-01", "")); len(events) != 0 {
+		t.Errorf("events after delete = %d, want 0", len(events))
+	}
+}
+
+func TestGroceryLists(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	createList := func(name string) int64 {
+		t.Helper()
+		rec := doRequest(t, h, a, "POST", "/api/grocery-lists", `{"name":"`+name+`"}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("create list %q = %d: %s", name, rec.Code, rec.Body.String())
+		}
+		var list struct {
+			ID int64 `json:"id"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &list)
+		return list.ID
+	}
+	lists := decodeList(t, doRequest(t, h, a, "GET", "/api/grocery-lists", ""))
+	if len(lists) != 1 {
+		t.Fatalf("lists = %d, want the default list", len(lists))
+	}
+	groceryID := int64(lists[0]["id"].(float64))
+	costcoID := createList("Costco")
+	pharmacyID := createList("Pharmacy")
+	if rec := doRequest(t, h, a, "POST", "/api/grocery-lists", `{"name":" "}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("create unnamed list = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	listPath := func(id int64) string { return fmt.Sprintf("/api/grocery-lists/%d", id) }
+	rec := doRequest(t, h, a, "POST", listPath(costcoID)+"/items", `{"name":"Paper towels"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("add item = %d: %s", rec.Code, rec.Body.String())
+	}
+	var item struct {
+		ID     int64 `json:"id"`
+		ListID int64 `json:"list_id"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &item)
+	doRequest(t, h, a, "POST", listPath(groceryID)+"/items", `{"name":"Milk"}`)
+
+	// Moving an item takes it off one list and onto another.
+	rec = doRequest(t, h, a, "POST", fmt.Sprintf("%s/items/%d/move", listPath(costcoID), item.ID), fmt.Sprintf(`{"list_id":%d}`, pharmacyID))
+	json.Unmarshal(rec.Body.Bytes(), &item)
+	if rec.Code != http.StatusOK || item.ListID != pharmacyID {
+		t.Fatalf("move item = %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := decodeList(t, doRequest(t, h, a, "GET", listPath(costcoID)+"/items", "")); len(got) != 0 {
+		t.Errorf("Costco items = %d, want 0", len(got))
+	}
+
+	rec = doRequest(t, h, a, "PUT", listPath(pharmacyID), `{"name":"Drugstore"}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"name":"Drugstore"`) {
+		t.Errorf("rename list = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doRequest(t, h, a, "PUT", "/api/grocery-lists/sort", fmt.Sprintf(`{"ids":[%d,%d,%d]}`, pharmacyID, groceryID, costcoID)); rec.Code != http.StatusNoContent {
+		t.Errorf("sort lists = %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := decodeList(t, doRequest(t, h, a, "GET", "/api/grocery-lists", "")); got[0]["name"] != "Drugstore" || got[0]["unchecked_count"] != float64(1) {
+		t.Errorf("first list = %v, want Drugstore with 1 item", got[0])
+	}
+
+	// The dashboard counts each list, and the switcher shows them all.
+	rec = doRequest(t, h, a, "GET", "/partials/dashboard", "")
+	if body := rec.Body.String(); !strings.Contains(body, "Drugstore") || !strings.Contains(body, "Grocery") || strings.Contains(body, "Costco") {
+		t.Errorf("dashboard does not list the lists with items: %s", body)
+	}
+	rec = doRequest(t, h, a, "GET", fmt.Sprintf("/partials/grocery?list_id=%d", groceryID), "")
+	if body := rec.Body.String(); !strings.Contains(body, "Milk") || strings.Contains(body, "Paper towels") || !strings.Contains(body, "Costco") {
+		t.Errorf("grocery partial does not show the Grocery list: %s", body)
+	}
+
+	// Archived lists can't be moved to and drop out of the switcher.
+	if rec := doRequest(t, h, a, "PUT", listPath(costcoID), `{"archived":true}`); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"archived":true`) {
+		t.Errorf("archive list = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doRequest(t, h, a, "POST", fmt.Sprintf("%s/items/%d/move", listPath(pharmacyID), item.ID), fmt.Sprintf(`{"list_id":%d}`, costcoID)); rec.Code != http.StatusBadRequest {
+		t.Errorf("move to archived list = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := doRequest(t, h, a, "GET", "/partials/grocery", ""); strings.Contains(rec.Body.String(), "/grocery?list_id="+strconv.FormatInt(costcoID, 10)) {
+		t.Error("switcher shows an archived list")
+	}
+
+	// Another household can't see or change them.
+	other, _ := srv.householdStore.Create("Other")
+	srv.householdStore.SeedDefaults(other.ID)
+	b := loginHousehold(t, srv, other.ID, "b@example.com")
+	if got := decodeList(t, doRequest(t, h, b, "GET", "/api/grocery-lists", "")); len(got) != 1 {
+		t.Errorf("other household lists = %d, want 1", len(got))
+	}
+	for _, tc := range []struct{ method, path, body string }{
+		{"GET", listPath(pharmacyID), ""},
+		{"PUT", listPath(pharmacyID), `{"name":"Mine"}`},
+		{"DELETE", listPath(pharmacyID), ""},
+		{"DELETE", fmt.Sprintf("/partials/grocery/lists/%d", pharmacyID), ""},
+	} {
+		if rec := doRequest(t, h, b, tc.method, tc.path, tc.body); rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s = %d, want %d", tc.method, tc.path, rec.Code, http.StatusNotFound)
+		}
+	}
+
+	// The last active list can't be archived or deleted.
+	if rec := doRequest(t, h, a, "DELETE", listPath(pharmacyID), ""); rec.Code != http.StatusNoContent {
+		t.Errorf("delete list = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doRequest(t, h, a, "DELETE", listPath(groceryID), ""); rec.Code != http.StatusConflict {
+		t.Errorf("delete last list = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if rec := doRequest(t, h, a, "PUT", listPath(groceryID), `{"archived":true}`); rec.Code != http.StatusConflict {
+		t.Errorf("archive last list = %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	// Lists are managed from the grocery section too.
+	rec = doRequest(t, h, a, "POST", "/partials/grocery/lists?name=Hardware", "")
+	if body := rec.Body.String(); !strings.Contains(body, "List added") || !strings.Contains(body, "Hardware") {
+		t.Errorf("add list partial does not switch to the new list: %s", body)
+	}
+	rec = doRequest(t, h, a, "POST", fmt.Sprintf("/partials/grocery/lists/%d/archive?archived=true&list_id=%d", groceryID, groceryID), "")
+	if body := rec.Body.String(); !strings.Contains(body, "List archived") || !strings.Contains(body, `data-list-id="`) || strings.Contains(body, fmt.Sprintf(`data-list-id="%d"`, groceryID)) {
+		t.Errorf("archive list partial = %s", body)
+	}
+}
+
+func TestGrocerySuggestions(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	list, err := store.NewGroceryStore(srv.db).GetDefaultList(store.DefaultHouseholdID)
+	if err != nil {
+		t.Fatalf("get default list: %v", err)
+	}
+	// Eggs every two weeks, last bought 13 days ago; bread once.
+	for _, days := range []int{41, 27, 13} {
+		srv.db.Exec(`INSERT INTO grocery_purchases (household_id, list_id, name, category, purchased_at) VALUES (?, ?, 'Eggs', 'Dairy', ?)`,
+			store.DefaultHouseholdID, list.ID, time.Now().AddDate(0, 0, -days).UTC())
+	}
+	srv.db.Exec(`INSERT INTO grocery_purchases (household_id, list_id, name, category, purchased_at) VALUES (?, ?, 'Bread', 'Bakery', ?)`,
+		store.DefaultHouseholdID, list.ID, time.Now().AddDate(0, 0, -3).UTC())
+
+	path := fmt.Sprintf("/api/grocery-lists/%d/suggestions", list.ID)
+	got := decodeList(t, doRequest(t, h, a, "GET", path, ""))
+	if len(got) != 1 || got[0]["name"] != "Eggs" || got[0]["interval_days"] != float64(14) {
+		t.Fatalf("suggestions = %v, want eggs every 14 days", got)
+	}
+
+	rec := doRequest(t, h, a, "GET", "/partials/grocery", "")
+	if body := rec.Body.String(); !strings.Contains(body, "Frequent items") || !strings.Contains(body, "You usually buy this every 2 weeks") || !strings.Contains(body, `value="Bread"`) {
+		t.Errorf("grocery partial has no frequent items panel: %s", body)
+	}
+
+	// Once eggs are on the list they are no longer suggested, and checking
+	// them off adds to the history.
+	rec = doRequest(t, h, a, "POST", fmt.Sprintf("/api/grocery-lists/%d/items", list.ID), `{"name":"eggs"}`)
+	var item struct {
+		ID int64 `json:"id"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &item)
+	if got := decodeList(t, doRequest(t, h, a, "GET", path, "")); len(got) != 0 {
+		t.Errorf("suggestions = %v, want none while eggs are on the list", got)
+	}
+	doRequest(t, h, a, "POST", fmt.Sprintf("/api/grocery-lists/%d/items/%d/check", list.ID, item.ID), `{}`)
+	doRequest(t, h, a, "POST", fmt.Sprintf("/api/grocery-lists/%d/clear-checked", list.ID), "")
+	var purchases int
+	srv.db.QueryRow(`SELECT COUNT(*) FROM grocery_purchases WHERE name = 'eggs'`).Scan(&purchases)
+	if purchases != 1 {
+		t.Errorf("purchases after checking eggs off = %d, want 1", purchases)
+	}
+	if got := decodeList(t, doRequest(t, h, a, "GET", path, "")); len(got) != 0 {
+		t.Errorf("suggestions = %v, want none right after buying eggs", got)
+	}
+
+	other, _ := srv.householdStore.Create("Other")
+	srv.householdStore.SeedDefaults(other.ID)
+	b := loginHousehold(t, srv, other.ID, "b@example.com")
+	if rec := doRequest(t, h, b, "GET", path, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("other household suggestions = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestGroceryCategoryLearning(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	list, err := store.NewGroceryStore(srv.db).GetDefaultList(store.DefaultHouseholdID)
+	if err != nil {
+		t.Fatalf("get default list: %v", err)
+	}
+	itemsPath := fmt.Sprintf("/api/grocery-lists/%d/items", list.ID)
+	// Items are checked off once added, so adding the same one again makes
+	// a new item instead of merging into it.
+	addItem := func(hh testHousehold, path, name string) map[string]any {
+		t.Helper()
+		rec := doRequest(t, h, hh, "POST", path, `{"name":"`+name+`"}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("add %q = %d: %s", name, rec.Code, rec.Body.String())
+		}
+		var item map[string]any
+		json.Unmarshal(rec.Body.Bytes(), &item)
+		doRequest(t, h, hh, "POST", fmt.Sprintf("%s/%d/check", path, int64(item["id"].(float64))), "")
+		return item
+	}
+
+	rec := doRequest(t, h, a, "POST", "/api/grocery-categories", `{"name":"Baby"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create category = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doRequest(t, h, a, "POST", "/api/grocery-categories", `{"name":"baby"}`); rec.Code != http.StatusConflict {
+		t.Errorf("create duplicate category = %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	// Moving an item to another category teaches it.
+	wipes := addItem(a, itemsPath, "Wipes")
+	if wipes["category"] != "Other" {
+		t.Fatalf("wipes category = %v, want Other", wipes["category"])
+	}
+	rec = doRequest(t, h, a, "PUT", fmt.Sprintf("%s/%d", itemsPath, int64(wipes["id"].(float64))), `{"name":"Wipes","category":"Baby"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("recategorize = %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := addItem(a, itemsPath, " wipes "); got["category"] != "Baby" {
+		t.Errorf("learned category = %v, want Baby", got["category"])
+	}
+
+	rec = doRequest(t, h, a, "GET", "/api/grocery-categories/rules", "")
+	var mapping struct {
+		Categories []string          `json:"categories"`
+		Rules      map[string]string `json:"rules"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &mapping)
+	if rec.Code != http.StatusOK || mapping.Rules["wipes"] != "Baby" || mapping.Categories[len(mapping.Categories)-1] != "Baby" {
+		t.Fatalf("export rules = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Another household starts without them, and can import them.
+	other, _ := srv.householdStore.Create("Other")
+	srv.householdStore.SeedDefaults(other.ID)
+	b := loginHousehold(t, srv, other.ID, "b@example.com")
+	otherList, _ := store.NewGroceryStore(srv.db).GetDefaultList(other.ID)
+	otherItems := fmt.Sprintf("/api/grocery-lists/%d/items", otherList.ID)
+	if got := addItem(b, otherItems, "Wipes"); got["category"] != "Other" {
+		t.Errorf("other household category = %v, want Other", got["category"])
+	}
+	rec = doRequest(t, h, b, "POST", "/api/grocery-categories/rules", rec.Body.String())
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"categories_created":1`) {
+		t.Fatalf("import rules = %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := addItem(b, otherItems, "Wipes"); got["category"] != "Baby" {
+		t.Errorf("imported category = %v, want Baby", got["category"])
+	}
+
+	// Renaming a category carries its items and rules along; Other stays put.
+	categories := decodeList(t, doRequest(t, h, a, "GET", "/api/grocery-categories", ""))
+	ids := map[string]int64{}
+	for _, c := range categories {
+		ids[c["name"].(string)] = int64(c["id"].(float64))
+	}
+	if rec := doRequest(t, h, a, "PUT", fmt.Sprintf("/api/grocery-categories/%d", ids["Baby"]), `{"name":"Nursery"}`); rec.Code != http.StatusOK {
+		t.Errorf("rename category = %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := addItem(a, itemsPath, "Wipes"); got["category"] != "Nursery" {
+		t.Errorf("category after rename = %v, want Nursery", got["category"])
+	}
+	if rec := doRequest(t, h, a, "DELETE", fmt.Sprintf("/api/grocery-categories/%d", ids["Other"]), ""); rec.Code != http.StatusConflict {
+		t.Errorf("delete Other = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if rec := doRequest(t, h, b, "DELETE", fmt.Sprintf("/api/grocery-categories/%d", ids["Baby"]), ""); rec.Code != http.StatusNotFound {
+		t.Errorf("delete other household's category = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	rec = doRequest(t, h, a, "DELETE", fmt.Sprintf("/partials/grocery/categories/%d", ids["Baby"]), "")
+	if body := rec.Body.String(); !strings.Contains(body, "Deleted Nursery") || strings.Contains(body, ">Nursery<") {
+		t.Errorf("delete category partial = %s", body)
+	}
+}
+
+func TestGroceryStores(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	gs := store.NewGroceryStore(srv.db)
+	list, err := gs.GetDefaultList(store.DefaultHouseholdID)
+	if err != nil {
+		t.Fatalf("get default list: %v", err)
+	}
+	for _, name := range []string{"Apples", "Milk", "Bread"} {
+		rec := doRequest(t, h, a, "POST", fmt.Sprintf("/api/grocery-lists/%d/items", list.ID), `{"name":"`+name+`"}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("add %q = %d: %s", name, rec.Code, rec.Body.String())
+		}
+	}
+	categories := decodeList(t, doRequest(t, h, a, "GET", "/api/grocery-categories", ""))
+	ids := map[string]int64{}
+	for _, c := range categories {
+		ids[c["name"].(string)] = int64(c["id"].(float64))
+	}
+
+	// Bread and milk share an aisle at the back; produce is by the exit.
+	body := fmt.Sprintf(`{"name":"Corner Market","sections":[{"category_id":%d,"aisle":"4"},{"category_id":%d,"aisle":"4"},{"category_id":%d}]}`,
+		ids["Bakery"], ids["Dairy"], ids["Produce"])
+	rec := doRequest(t, h, a, "POST", "/api/grocery-stores", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create store = %d: %s", rec.Code, rec.Body.String())
+	}
+	var shop model.GroceryShop
+	json.Unmarshal(rec.Body.Bytes(), &shop)
+	if shop.Sections[0].Category != "Bakery" || shop.Sections[1].Aisle != "4" {
+		t.Fatalf("store sections = %+v", shop.Sections)
+	}
+
+	rec = doRequest(t, h, a, "PUT", fmt.Sprintf("/api/grocery-lists/%d/store", list.ID), fmt.Sprintf(`{"store_id":%d}`, shop.ID))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), fmt.Sprintf(`"store_id":%d`, shop.ID)) {
+		t.Fatalf("set list store = %d: %s", rec.Code, rec.Body.String())
+	}
+	items := decodeList(t, doRequest(t, h, a, "GET", fmt.Sprintf("/api/grocery-lists/%d/items", list.ID), ""))
+	if len(items) != 3 || items[0]["name"] != "Bread" || items[1]["name"] != "Milk" || items[2]["name"] != "Apples" {
+		t.Errorf("items in walking order = %v", items)
+	}
+
+	// Shopping mode groups by aisle and hides what is in the cart.
+	checkPath := fmt.Sprintf("/partials/grocery/items/%d/check?view=shopping", int64(items[2]["id"].(float64)))
+	body = doRequest(t, h, a, "POST", checkPath, "").Body.String()
+	if !strings.Contains(body, "Aisle 4") || !strings.Contains(body, "Bakery, Dairy") || strings.Contains(body, "Apples") {
+		t.Errorf("shopping list after check = %s", body)
+	}
+	rec = doRequest(t, h, a, "GET", fmt.Sprintf("/partials/grocery/shop?list_id=%d", list.ID), "")
+	if body := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(body, `data-view="shopping"`) || strings.Contains(body, "Apples") {
+		t.Errorf("shopping mode = %d: %s", rec.Code, body)
+	}
+
+	// The layout form reorders by position.
+	form := url.Values{"category_id": {fmt.Sprint(ids["Produce"]), fmt.Sprint(ids["Bakery"])}, "position": {"1", "2"}, "aisle": {"", "9"}}
+	rec = doRequest(t, h, a, "PUT", fmt.Sprintf("/partials/grocery/stores/%d/layout?%s", shop.ID, form.Encode()), "")
+	if rec.Code != http.StatusOK || rec.Header().Get("HX-Trigger") != "closeGroceryModal" {
+		t.Fatalf("save layout = %d: %s", rec.Code, rec.Body.String())
+	}
+	if got, _ := gs.GetShop(shop.ID, store.DefaultHouseholdID); got.Sections[0].Category != "Produce" || got.Sections[1].Aisle != "9" {
+		t.Errorf("sections after layout = %+v", got.Sections)
+	}
+
+	rec = doRequest(t, h, a, "PUT", fmt.Sprintf("/partials/grocery/lists/%d/store?store_id=", list.ID), "")
+	if body := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(body, "Corner Market") || strings.Contains(body, "Aisle 9") {
+		t.Errorf("clear list store partial = %d: %s", rec.Code, body)
+	}
+	rec = doRequest(t, h, a, "PUT", fmt.Sprintf("/partials/grocery/lists/%d/store?store_id=%d", list.ID, shop.ID), "")
+	if body := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(body, "Aisle 9") {
+		t.Errorf("set list store partial = %d: %s", rec.Code, body)
+	}
+
+	// Another household cannot see or use the store.
+	other, _ := srv.householdStore.Create("Other")
+	srv.householdStore.SeedDefaults(other.ID)
+	b := loginHousehold(t, srv, other.ID, "b@example.com")
+	otherList, _ := gs.GetDefaultList(other.ID)
+	if rec := doRequest(t, h, b, "GET", fmt.Sprintf("/api/grocery-stores/%d", shop.ID), ""); rec.Code != http.StatusNotFound {
+		t.Errorf("get other household's store = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := doRequest(t, h, b, "PUT", fmt.Sprintf("/api/grocery-lists/%d/store", otherList.ID), fmt.Sprintf(`{"store_id":%d}`, shop.ID)); rec.Code != http.StatusBadRequest {
+		t.Errorf("shop at other household's store = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	if rec := doRequest(t, h, a, "DELETE", fmt.Sprintf("/api/grocery-stores/%d", shop.ID), ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete store = %d: %s", rec.Code, rec.Body.String())
+	}
+	if got, _ := gs.GetListByID(list.ID, store.DefaultHouseholdID); got.ShopID != nil {
+		t.Errorf("list store after delete = %d, want none", *got.ShopID)
+	}
+}
+
+func TestGroceryQuickAdd(t *testing.T) {
+	srv, h := setupTestServer(t)
+	a := loginHousehold(t, srv, store.DefaultHouseholdID, "a@example.com")
+
+	list, err := store.NewGroceryStore(srv.db).GetDefaultList(store.DefaultHouseholdID)
+	if err != nil {
+		t.Fatalf("get default list: %v", err)
+	}
+	quickAdd := fmt.Sprintf("/api/grocery-lists/%d/quick-add", list.ID)
+
+	rec := doRequest(t, h, a, "POST", quickAdd, `{"text":"3 cans black beans (low sodium)"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("quick add = %d: %s", rec.Code, rec.Body.String())
+	}
+	var item model.GroceryItem
+	json.Unmarshal(rec.Body.Bytes(), &item)
+	if item.Name != "black beans" || item.Quantity != "3" || item.Unit != "can" || item.Notes != "low sodium" {
+		t.Errorf("quick added item = %+v", item)
+	}
+
+	// The same item again is merged into it.
+	rec = doRequest(t, h, a, "POST", quickAdd, `{"text":"Black Beans x2"}`)
+	var merged model.GroceryItem
+	json.Unmarshal(rec.Body.Bytes(), &merged)
+	if rec.Code != http.StatusOK || merged.ID != item.ID || merged.Quantity != "5" {
+		t.Errorf("quick add again = %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doRequest(t, h, a, "POST", fmt.Sprintf("/api/grocery-lists/%d/items", list.ID), `{"name":"black beans","quantity":"1","unit":"cans"}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"quantity":"6"`) {
+		t.Errorf("create merged item = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doRequest(t, h, a, "POST", quickAdd, `{"text":"  "}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("quick add blank = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	// The quick-add bar reads quantities too.
+	rec = doRequest(t, h, a, "POST", fmt.Sprintf("/partials/grocery/items?list_id=%d&name=%s", list.ID, url.QueryEscape("a dozen eggs")), "")
+	if body := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(body, ">eggs<") || !strings.Contains(body, "12") {
+		t.Errorf("quick add partial = %d: %s", rec.Code, body)
+	}
+	rec = doRequest(t, h, a, "POST", fmt.Sprintf("/partials/grocery/items?list_id=%d&name=eggs", list.ID), "")
+	if body := rec.Body.String(); !strings.Contains(body, "eggs was already on the list") || !strings.Contains(body, "13") {
+		t.Errorf("quick add partial again = %s", body)
+	}
+}
@@ -0,0 +1,318 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+type CalDAVStore struct {
+	db *sql.DB
+}
+
+func NewCalDAVStore(db *sql.DB) *CalDAVStore {
+	return &CalDAVStore{db: db}
+}
+
+func scanCalDAVCollection(scanner interface{ Scan(...any) error }) (*model.CalDAVCollection, error) {
+	var c model.CalDAVCollection
+	var memberID sql.NullInt64
+	var lastSynced sql.NullTime
+	err := scanner.Scan(&c.ID, &c.HouseholdID, &c.Name, &c.URL, &c.Username, &c.Password, &memberID, &c.CTag, &lastSynced, &c.LastError, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if memberID.Valid {
+		c.FamilyMemberID = &memberID.Int64
+	}
+	if lastSynced.Valid {
+		c.LastSyncedAt = &lastSynced.Time
+	}
+	return &c, nil
+}
+
+const caldavCollectionCols = `id, household_id, name, url, username, password, family_member_id, ctag, last_synced_at, last_error, created_at, updated_at`
+
+func (s *CalDAVStore) CreateCollection(householdID int64, name, url, username, password string, familyMemberID *int64) (*model.CalDAVCollection, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO caldav_collections (household_id, name, url, username, password, family_member_id) VALUES (?, ?, ?, ?, ?, ?)`,
+		householdID, name, url, username, password, nullableID(familyMemberID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert caldav collection: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("last insert id: %w", err)
+	}
+	return s.GetCollection(id, householdID)
+}
+
+func (s *CalDAVStore) GetCollection(id, householdID int64) (*model.CalDAVCollection, error) {
+	row := s.db.QueryRow(
+		`SELECT `+caldavCollectionCols+` FROM caldav_collections WHERE id = ? AND household_id = ?`, id, householdID,
+	)
+	c, err := scanCalDAVCollection(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get caldav collection: %w", err)
+	}
+	return c, nil
+}
+
+func (s *CalDAVStore) ListCollections(householdID int64) ([]model.CalDAVCollection, error) {
+	rows, err := s.db.Query(
+		`SELECT `+caldavCollectionCols+` FROM caldav_collections WHERE household_id = ? ORDER BY name COLLATE NOCASE, id`,
+		householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list caldav collections: %w", err)
+	}
+	defer rows.Close()
+	return scanCalDAVCollections(rows)
+}
+
+// ListCollectionsDue returns collections across all households that have
+// never synced or last synced before the given time.
+func (s *CalDAVStore) ListCollectionsDue(before time.Time) ([]model.CalDAVCollection, error) {
+	rows, err := s.db.Query(
+		`SELECT `+caldavCollectionCols+` FROM caldav_collections
+		 WHERE last_synced_at IS NULL OR last_synced_at < ?
+		 ORDER BY last_synced_at IS NOT NULL, last_synced_at, id`,
+		before.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list due caldav collections: %w", err)
+	}
+	defer rows.Close()
+	return scanCalDAVCollections(rows)
+}
+
+func scanCalDAVCollections(rows *sql.Rows) ([]model.CalDAVCollection, error) {
+	var collections []model.CalDAVCollection
+	for rows.Next() {
+		c, err := scanCalDAVCollection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan caldav collection: %w", err)
+		}
+		collections = append(collections, *c)
+	}
+	return collections, rows.Err()
+}
+
+// UpdateCollection changes a collection's settings. An empty password keeps
+// the stored one. Changing the URL resets the sync state so the new
+// collection is treated as unsynced; changing the member reassigns the
+// events already synced with it.
+func (s *CalDAVStore) UpdateCollection(id, householdID int64, name, url, username, password string, familyMemberID *int64) (*model.CalDAVCollection, error) {
+	existing, err := s.GetCollection(id, householdID)
+	if err != nil || existing == nil {
+		return existing, err
+	}
+	if password == "" {
+		password = existing.Password
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	ctag := existing.CTag
+	switch {
+	case url != existing.URL:
+		ctag = ""
+		if _, err := tx.Exec(`DELETE FROM caldav_objects WHERE collection_id = ?`, id); err != nil {
+			return nil, fmt.Errorf("reset caldav objects: %w", err)
+		}
+	case !sameMemberID(familyMemberID, existing.FamilyMemberID):
+		// Synced events follow the collection to its new member. Only the
+		// local assignment changed, so objects that were clean stay clean.
+		clean, err := cleanObjectIDs(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(
+			`UPDATE calendar_events SET family_member_id = ?
+			 WHERE household_id = ? AND COALESCE(recurrence_parent_id, id) IN (
+			     SELECT event_id FROM caldav_objects WHERE collection_id = ? AND event_id IS NOT NULL)`,
+			nullableID(familyMemberID), householdID, id,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("reassign caldav events: %w", err)
+		}
+		for _, objectID := range clean {
+			if _, err := tx.Exec(`UPDATE caldav_objects SET dirty = 0 WHERE id = ?`, objectID); err != nil {
+				return nil, fmt.Errorf("reset caldav object: %w", err)
+			}
+		}
+	}
+	_, err = tx.Exec(
+		`UPDATE caldav_collections SET name = ?, url = ?, username = ?, password = ?, family_member_id = ?, ctag = ?
+		 WHERE id = ? AND household_id = ?`,
+		name, url, username, password, nullableID(familyMemberID), ctag, id, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update caldav collection: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetCollection(id, householdID)
+}
+
+func cleanObjectIDs(tx *sql.Tx, collectionID int64) ([]int64, error) {
+	rows, err := tx.Query(`SELECT id FROM caldav_objects WHERE collection_id = ? AND dirty = 0`, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("query clean caldav objects: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan caldav object id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func sameMemberID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// RecordSync stores the outcome of a sync. An empty errMsg clears the last error.
+func (s *CalDAVStore) RecordSync(id int64, ctag string, syncedAt time.Time, errMsg string) error {
+	_, err := s.db.Exec(
+		`UPDATE caldav_collections SET ctag = ?, last_synced_at = ?, last_error = ? WHERE id = ?`,
+		ctag, syncedAt.UTC(), errMsg, id,
+	)
+	if err != nil {
+		return fmt.Errorf("record caldav sync: %w", err)
+	}
+	return nil
+}
+
+// DeleteCollection stops syncing a collection. Local copies of its events
+// are kept as ordinary events.
+func (s *CalDAVStore) DeleteCollection(id, householdID int64) error {
+	_, err := s.db.Exec(`DELETE FROM caldav_collections WHERE id = ? AND household_id = ?`, id, householdID)
+	if err != nil {
+		return fmt.Errorf("delete caldav collection: %w", err)
+	}
+	return nil
+}
+
+func scanCalDAVObject(scanner interface{ Scan(...any) error }) (*model.CalDAVObject, error) {
+	var o model.CalDAVObject
+	var eventID sql.NullInt64
+	var dirty int
+	err := scanner.Scan(&o.ID, &o.CollectionID, &eventID, &o.Href, &o.UID, &o.ETag, &dirty, &o.SyncedAt)
+	if err != nil {
+		return nil, err
+	}
+	if eventID.Valid {
+		o.EventID = &eventID.Int64
+	}
+	o.Dirty = dirty == 1
+	return &o, nil
+}
+
+const caldavObjectCols = `id, collection_id, event_id, href, uid, etag, dirty, synced_at`
+
+func (s *CalDAVStore) ListObjects(collectionID int64) ([]model.CalDAVObject, error) {
+	rows, err := s.db.Query(
+		`SELECT `+caldavObjectCols+` FROM caldav_objects WHERE collection_id = ? ORDER BY id`, collectionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list caldav objects: %w", err)
+	}
+	defer rows.Close()
+
+	var objects []model.CalDAVObject
+	for rows.Next() {
+		o, err := scanCalDAVObject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan caldav object: %w", err)
+		}
+		objects = append(objects, *o)
+	}
+	return objects, rows.Err()
+}
+
+// GetObjectByEvent returns the sync state of an event series, if it is linked
+// to a collection.
+func (s *CalDAVStore) GetObjectByEvent(eventID int64) (*model.CalDAVObject, error) {
+	row := s.db.QueryRow(`SELECT `+caldavObjectCols+` FROM caldav_objects WHERE event_id = ?`, eventID)
+	o, err := scanCalDAVObject(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get caldav object: %w", err)
+	}
+	return o, nil
+}
+
+// SaveObject records that an event series and a remote object are in sync,
+// creating the link if it does not exist yet.
+func (s *CalDAVStore) SaveObject(collectionID int64, eventID int64, href, uid, etag string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO caldav_objects (collection_id, event_id, href, uid, etag, dirty, synced_at)
+		 VALUES (?, ?, ?, ?, ?, 0, ?)
+		 ON CONFLICT (collection_id, href) DO UPDATE SET
+		     event_id = excluded.event_id, uid = excluded.uid, etag = excluded.etag, dirty = 0, synced_at = excluded.synced_at`,
+		collectionID, eventID, href, uid, etag, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("save caldav object: %w", err)
+	}
+	return nil
+}
+
+func (s *CalDAVStore) DeleteObject(id int64) error {
+	_, err := s.db.Exec(`DELETE FROM caldav_objects WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete caldav object: %w", err)
+	}
+	return nil
+}
+
+// ListUnsyncedEvents returns the household's event series that belong in a
+// collection for familyMemberID but are not linked to any collection yet.
+// Imported events and exception rows are never pushed.
+func (s *CalDAVStore) ListUnsyncedEvents(householdID int64, familyMemberID *int64) ([]model.CalendarEvent, error) {
+	rows, err := s.db.Query(
+		`SELECT `+selectCols+`
+		 FROM calendar_events
+		 WHERE household_id = ? AND recurrence_parent_id IS NULL AND source_uid = ''
+		   AND family_member_id IS ?
+		   AND id NOT IN (SELECT event_id FROM caldav_objects WHERE event_id IS NOT NULL)
+		 ORDER BY id`,
+		householdID, nullableID(familyMemberID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query unsynced events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []model.CalendarEvent
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan unsynced event: %w", err)
+		}
+		events = append(events, *e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/database"
+)
+
+func setupCalDAVTestDB(t *testing.T) (*CalDAVStore, *EventStore, *FamilyMemberStore) {
+	t.Helper()
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewCalDAVStore(db), NewEventStore(db), NewFamilyMemberStore(db)
+}
+
+func TestCalDAVCollectionCRUD(t *testing.T) {
+	s, _, _ := setupCalDAVTestDB(t)
+
+	coll, err := s.CreateCollection(testHouseholdID, "Family", "https://dav.example.com/cal/", "me", "secret", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if coll.Password != "secret" || coll.LastSyncedAt != nil {
+		t.Errorf("unexpected collection: %+v", coll)
+	}
+
+	updated, err := s.UpdateCollection(coll.ID, testHouseholdID, "Home", coll.URL, "me", "", nil)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Name != "Home" || updated.Password != "secret" {
+		t.Errorf("update should keep the password when empty: %+v", updated)
+	}
+
+	if err := s.RecordSync(coll.ID, "ctag-1", time.Now(), ""); err != nil {
+		t.Fatalf("record sync: %v", err)
+	}
+	due, err := s.ListCollectionsDue(time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("list due: %v", err)
+	}
+	if len(due) != 0 {
+		t.Errorf("due = %d, want 0 right after a sync", len(due))
+	}
+
+	if got, _ := s.GetCollection(coll.ID, testHouseholdID+1); got != nil {
+		t.Error("collection should not be visible to another household")
+	}
+
+	if err := s.DeleteCollection(coll.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if got, _ := s.GetCollection(coll.ID, testHouseholdID); got != nil {
+		t.Error("collection still exists after delete")
+	}
+}
+
+func TestCalDAVObjectsTrackLocalChanges(t *testing.T) {
+	s, es, _ := setupCalDAVTestDB(t)
+
+	coll, _ := s.CreateCollection(testHouseholdID, "Family", "https://dav.example.com/cal/", "", "", nil)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	e, _ := es.CreateWithRecurrence(testHouseholdID, "Swim", "", start, start.Add(time.Hour), false, nil, "", "FREQ=WEEKLY")
+
+	unsynced, err := s.ListUnsyncedEvents(testHouseholdID, nil)
+	if err != nil {
+		t.Fatalf("list unsynced: %v", err)
+	}
+	if len(unsynced) != 1 {
+		t.Fatalf("unsynced = %d, want 1", len(unsynced))
+	}
+
+	if err := s.SaveObject(coll.ID, e.ID, coll.URL+"swim.ics", "swim", `"1"`); err != nil {
+		t.Fatalf("save object: %v", err)
+	}
+	if unsynced, _ := s.ListUnsyncedEvents(testHouseholdID, nil); len(unsynced) != 0 {
+		t.Errorf("linked event still listed as unsynced")
+	}
+
+	dirty := func() bool {
+		t.Helper()
+		o, err := s.GetObjectByEvent(e.ID)
+		if err != nil || o == nil {
+			t.Fatalf("get object: %v", err)
+		}
+		return o.Dirty
+	}
+	if dirty() {
+		t.Fatal("saved object should be clean")
+	}
+
+	// Adding an exception marks the series dirty.
+	if _, err := es.CreateException(testHouseholdID, e.ID, start.AddDate(0, 0, 7), "Swim", "", start.AddDate(0, 0, 7), start.AddDate(0, 0, 7).Add(time.Hour), false, nil, "", true); err != nil {
+		t.Fatalf("create exception: %v", err)
+	}
+	if !dirty() {
+		t.Error("adding an exception should mark the object dirty")
+	}
+
+	s.SaveObject(coll.ID, e.ID, coll.URL+"swim.ics", "swim", `"2"`)
+	if _, err := es.UpdateWithRecurrence(e.ID, testHouseholdID, "Swim lesson", "", start, start.Add(time.Hour), false, nil, "", "FREQ=WEEKLY"); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if !dirty() {
+		t.Error("editing the event should mark the object dirty")
+	}
+
+	// Deleting the event keeps the row so the remote copy can be removed.
+	if err := es.Delete(e.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	objects, _ := s.ListObjects(coll.ID)
+	if len(objects) != 1 || objects[0].EventID != nil {
+		t.Errorf("objects after delete = %+v, want one unlinked row", objects)
+	}
+}
+
+func TestCalDAVUpdateCollectionReassignsEvents(t *testing.T) {
+	s, es, ms := setupCalDAVTestDB(t)
+
+	kid, _ := ms.Create(testHouseholdID, "Sam", "#00FF00", "S")
+	coll, _ := s.CreateCollection(testHouseholdID, "Family", "https://dav.example.com/cal/", "", "", nil)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	e, _ := es.Create(testHouseholdID, "Swim", "", start, start.Add(time.Hour), false, nil, "")
+	s.SaveObject(coll.ID, e.ID, coll.URL+"swim.ics", "swim", `"1"`)
+
+	if _, err := s.UpdateCollection(coll.ID, testHouseholdID, coll.Name, coll.URL, "", "", &kid.ID); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, _ := es.GetByID(e.ID, testHouseholdID)
+	if got.FamilyMemberID == nil || *got.FamilyMemberID != kid.ID {
+		t.Errorf("family member = %v, want %d", got.FamilyMemberID, kid.ID)
+	}
+	if o, _ := s.GetObjectByEvent(e.ID); o == nil || o.Dirty {
+		t.Errorf("reassignment should not mark the object dirty: %+v", o)
+	}
+
+	if _, err := s.UpdateCollection(coll.ID, testHouseholdID, coll.Name, "https://other.example.com/cal/", "", "", &kid.ID); err != nil {
+		t.Fatalf("update url: %v", err)
+	}
+	if objects, _ := s.ListObjects(coll.ID); len(objects) != 0 {
+		t.Errorf("objects after URL change = %d, want 0", len(objects))
+	}
+}
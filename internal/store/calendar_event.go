@@ -3,6 +3,9 @@ package store
 import (
 	"database/sql"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dukerupert/gamwich/internal/model"
@@ -16,11 +19,11 @@ func NewEventStore(db *sql.DB) *EventStore {
 	return &EventStore{db: db}
 }
 
-func (s *EventStore) Create(title, description string, startTime, endTime time.Time, allDay bool, familyMemberID *int64, location string) (*model.CalendarEvent, error) {
-	return s.CreateWithRecurrence(title, description, startTime, endTime, allDay, familyMemberID, location, "")
+func (s *EventStore) Create(householdID int64, title, description string, startTime, endTime time.Time, allDay bool, familyMemberID *int64, location string) (*model.CalendarEvent, error) {
+	return s.CreateWithRecurrence(householdID, title, description, startTime, endTime, allDay, familyMemberID, location, "")
 }
 
-func (s *EventStore) CreateWithRecurrence(title, description string, startTime, endTime time.Time, allDay bool, familyMemberID *int64, location, recurrenceRule string) (*model.CalendarEvent, error) {
+func (s *EventStore) CreateWithRecurrence(householdID int64, title, description string, startTime, endTime time.Time, allDay bool, familyMemberID *int64, location, recurrenceRule string) (*model.CalendarEvent, error) {
 	var allDayInt int
 	if allDay {
 		allDayInt = 1
@@ -31,10 +34,16 @@ func (s *EventStore) CreateWithRecurrence(title, description string, startTime,
 		memberID = sql.NullInt64{Int64: *familyMemberID, Valid: true}
 	}
 
-	result, err := s.db.Exec(
-		`INSERT INTO calendar_events (title, description, start_time, end_time, all_day, family_member_id, location, recurrence_rule)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-		title, description, startTime.UTC(), endTime.UTC(), allDayInt, memberID, location, recurrenceRule,
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO calendar_events (household_id, title, description, start_time, end_time, all_day, family_member_id, location, recurrence_rule)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		householdID, title, description, startTime.UTC(), endTime.UTC(), allDayInt, memberID, location, recurrenceRule,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert calendar event: %w", err)
@@ -44,11 +53,17 @@ func (s *EventStore) CreateWithRecurrence(title, description string, startTime,
 	if err != nil {
 		return nil, fmt.Errorf("last insert id: %w", err)
 	}
+	if err := addPrimaryAttendee(tx, id, familyMemberID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
 }
 
-func (s *EventStore) CreateException(parentID int64, originalStart time.Time, title, description string, startTime, endTime time.Time, allDay bool, familyMemberID *int64, location string, cancelled bool) (*model.CalendarEvent, error) {
+func (s *EventStore) CreateException(householdID, parentID int64, originalStart time.Time, title, description string, startTime, endTime time.Time, allDay bool, familyMemberID *int64, location string, cancelled bool) (*model.CalendarEvent, error) {
 	var allDayInt, cancelledInt int
 	if allDay {
 		allDayInt = 1
@@ -62,10 +77,16 @@ func (s *EventStore) CreateException(parentID int64, originalStart time.Time, ti
 		memberID = sql.NullInt64{Int64: *familyMemberID, Valid: true}
 	}
 
-	result, err := s.db.Exec(
-		`INSERT INTO calendar_events (title, description, start_time, end_time, all_day, family_member_id, location, recurrence_parent_id, original_start_time, cancelled)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		title, description, startTime.UTC(), endTime.UTC(), allDayInt, memberID, location, parentID, originalStart.UTC(), cancelledInt,
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO calendar_events (household_id, title, description, start_time, end_time, all_day, family_member_id, location, recurrence_parent_id, original_start_time, cancelled)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		householdID, title, description, startTime.UTC(), endTime.UTC(), allDayInt, memberID, location, parentID, originalStart.UTC(), cancelledInt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert exception event: %w", err)
@@ -75,8 +96,48 @@ func (s *EventStore) CreateException(parentID int64, originalStart time.Time, ti
 	if err != nil {
 		return nil, fmt.Errorf("last insert id: %w", err)
 	}
+	if err := addPrimaryAttendee(tx, id, familyMemberID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(id, householdID)
+}
+
+// CreateWithAttendees inserts an event, or an exception when
+// e.RecurrenceParentID is set, together with its attendees. The primary
+// attendee becomes the event's family_member_id.
+func (s *EventStore) CreateWithAttendees(householdID int64, e model.CalendarEvent) (*model.CalendarEvent, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO calendar_events (household_id, title, description, start_time, end_time, all_day, family_member_id, location, recurrence_rule, recurrence_parent_id, original_start_time, cancelled)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		householdID, e.Title, e.Description, e.StartTime.UTC(), e.EndTime.UTC(), e.AllDay, nullableID(model.PrimaryAttendee(e.Attendees)), e.Location, e.RecurrenceRule,
+		nullableID(e.RecurrenceParentID), nullableTime(e.OriginalStartTime), e.Cancelled,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert calendar event: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("last insert id: %w", err)
+	}
+	if err := setAttendees(tx, id, e.Attendees); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
 }
 
 func scanEvent(scanner interface{ Scan(...any) error }) (*model.CalendarEvent, error) {
@@ -86,12 +147,15 @@ func scanEvent(scanner interface{ Scan(...any) error }) (*model.CalendarEvent, e
 	var parentID sql.NullInt64
 	var originalStart sql.NullTime
 	var reminderMinutes sql.NullInt64
+	var subscriptionID sql.NullInt64
+	var attendees sql.NullString
 
 	err := scanner.Scan(
 		&e.ID, &e.Title, &e.Description, &e.StartTime, &e.EndTime,
 		&allDayInt, &memberID, &e.Location, &e.RecurrenceRule,
 		&parentID, &originalStart, &cancelledInt, &reminderMinutes,
-		&e.CreatedAt, &e.UpdatedAt,
+		&subscriptionID, &e.SourceUID,
+		&e.CreatedAt, &e.UpdatedAt, &attendees,
 	)
 	if err != nil {
 		return nil, err
@@ -112,15 +176,54 @@ func scanEvent(scanner interface{ Scan(...any) error }) (*model.CalendarEvent, e
 		v := int(reminderMinutes.Int64)
 		e.ReminderMinutes = &v
 	}
+	if subscriptionID.Valid {
+		e.SubscriptionID = &subscriptionID.Int64
+	}
+	e.Attendees, err = parseAttendees(attendees.String, e.FamilyMemberID)
+	if err != nil {
+		return nil, err
+	}
 
 	return &e, nil
 }
 
-const selectCols = `id, title, description, start_time, end_time, all_day, family_member_id, location, recurrence_rule, recurrence_parent_id, original_start_time, cancelled, reminder_minutes, created_at, updated_at`
+// selectCols ends with the event's attendees packed as "memberID:role,...".
+const selectCols = `id, title, description, start_time, end_time, all_day, family_member_id, location, recurrence_rule, recurrence_parent_id, original_start_time, cancelled, reminder_minutes, subscription_id, source_uid, created_at, updated_at,
+	(SELECT group_concat(family_member_id || ':' || role) FROM event_attendees WHERE event_id = calendar_events.id)`
+
+var roleOrder = map[model.AttendeeRole]int{model.RoleAttendee: 0, model.RoleDriver: 1, model.RoleOptional: 2}
 
-func (s *EventStore) GetByID(id int64) (*model.CalendarEvent, error) {
+// parseAttendees unpacks the attendees column, putting the primary attendee
+// first and the rest in role order.
+func parseAttendees(packed string, primary *int64) ([]model.Attendee, error) {
+	attendees := []model.Attendee{}
+	if packed == "" {
+		return attendees, nil
+	}
+	for _, part := range strings.Split(packed, ",") {
+		idStr, role, _ := strings.Cut(part, ":")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse attendee %q: %w", part, err)
+		}
+		attendees = append(attendees, model.Attendee{FamilyMemberID: id, Role: model.AttendeeRole(role)})
+	}
+	sort.SliceStable(attendees, func(i, j int) bool {
+		a, b := attendees[i], attendees[j]
+		if primary != nil && (a.FamilyMemberID == *primary) != (b.FamilyMemberID == *primary) {
+			return a.FamilyMemberID == *primary
+		}
+		if roleOrder[a.Role] != roleOrder[b.Role] {
+			return roleOrder[a.Role] < roleOrder[b.Role]
+		}
+		return a.FamilyMemberID < b.FamilyMemberID
+	})
+	return attendees, nil
+}
+
+func (s *EventStore) GetByID(id, householdID int64) (*model.CalendarEvent, error) {
 	row := s.db.QueryRow(
-		`SELECT `+selectCols+` FROM calendar_events WHERE id = ?`, id,
+		`SELECT `+selectCols+` FROM calendar_events WHERE id = ? AND household_id = ?`, id, householdID,
 	)
 	e, err := scanEvent(row)
 	if err == sql.ErrNoRows {
@@ -132,15 +235,16 @@ func (s *EventStore) GetByID(id int64) (*model.CalendarEvent, error) {
 	return e, nil
 }
 
-func (s *EventStore) ListByDateRange(start, end time.Time) ([]model.CalendarEvent, error) {
+func (s *EventStore) ListByDateRange(householdID int64, start, end time.Time) ([]model.CalendarEvent, error) {
 	rows, err := s.db.Query(
 		`SELECT `+selectCols+`
 		 FROM calendar_events
-		 WHERE start_time < ? AND end_time > ?
+		 WHERE household_id = ?
+		   AND start_time < ? AND end_time > ?
 		   AND recurrence_rule = ''
 		   AND recurrence_parent_id IS NULL
 		 ORDER BY all_day DESC, start_time ASC`,
-		end.UTC(), start.UTC(),
+		householdID, end.UTC(), start.UTC(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("query calendar events: %w", err)
@@ -158,16 +262,44 @@ func (s *EventStore) ListByDateRange(start, end time.Time) ([]model.CalendarEven
 	return events, rows.Err()
 }
 
+// ListForFeed returns every recurring parent and exception, plus the one-off
+// events that end after since, for building calendar feeds.
+func (s *EventStore) ListForFeed(householdID int64, since time.Time) ([]model.CalendarEvent, error) {
+	rows, err := s.db.Query(
+		`SELECT `+selectCols+`
+		 FROM calendar_events
+		 WHERE household_id = ?
+		   AND (recurrence_rule != '' OR recurrence_parent_id IS NOT NULL OR end_time > ?)
+		 ORDER BY start_time ASC, id ASC`,
+		householdID, since.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query feed events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []model.CalendarEvent
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan feed event: %w", err)
+		}
+		events = append(events, *e)
+	}
+	return events, rows.Err()
+}
+
 // ListRecurring returns all recurring parent events whose start_time is before the given date.
-func (s *EventStore) ListRecurring(before time.Time) ([]model.CalendarEvent, error) {
+func (s *EventStore) ListRecurring(householdID int64, before time.Time) ([]model.CalendarEvent, error) {
 	rows, err := s.db.Query(
 		`SELECT `+selectCols+`
 		 FROM calendar_events
-		 WHERE recurrence_rule != ''
+		 WHERE household_id = ?
+		   AND recurrence_rule != ''
 		   AND recurrence_parent_id IS NULL
 		   AND start_time < ?
 		 ORDER BY start_time ASC`,
-		before.UTC(),
+		householdID, before.UTC(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("query recurring events: %w", err)
@@ -186,13 +318,13 @@ func (s *EventStore) ListRecurring(before time.Time) ([]model.CalendarEvent, err
 }
 
 // ListExceptions returns all exception events for a given parent recurring event.
-func (s *EventStore) ListExceptions(parentID int64) ([]model.CalendarEvent, error) {
+func (s *EventStore) ListExceptions(parentID, householdID int64) ([]model.CalendarEvent, error) {
 	rows, err := s.db.Query(
 		`SELECT `+selectCols+`
 		 FROM calendar_events
-		 WHERE recurrence_parent_id = ?
+		 WHERE recurrence_parent_id = ? AND household_id = ?
 		 ORDER BY original_start_time ASC`,
-		parentID,
+		parentID, householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("query exceptions: %w", err)
@@ -211,19 +343,19 @@ func (s *EventStore) ListExceptions(parentID int64) ([]model.CalendarEvent, erro
 }
 
 // DeleteExceptions removes all exception events for a given parent.
-func (s *EventStore) DeleteExceptions(parentID int64) error {
-	_, err := s.db.Exec("DELETE FROM calendar_events WHERE recurrence_parent_id = ?", parentID)
+func (s *EventStore) DeleteExceptions(parentID, householdID int64) error {
+	_, err := s.db.Exec("DELETE FROM calendar_events WHERE recurrence_parent_id = ? AND household_id = ?", parentID, householdID)
 	if err != nil {
 		return fmt.Errorf("delete exceptions: %w", err)
 	}
 	return nil
 }
 
-func (s *EventStore) Update(id int64, title, description string, startTime, endTime time.Time, allDay bool, familyMemberID *int64, location string) (*model.CalendarEvent, error) {
-	return s.UpdateWithRecurrence(id, title, description, startTime, endTime, allDay, familyMemberID, location, "")
+func (s *EventStore) Update(id, householdID int64, title, description string, startTime, endTime time.Time, allDay bool, familyMemberID *int64, location string) (*model.CalendarEvent, error) {
+	return s.UpdateWithRecurrence(id, householdID, title, description, startTime, endTime, allDay, familyMemberID, location, "")
 }
 
-func (s *EventStore) UpdateWithRecurrence(id int64, title, description string, startTime, endTime time.Time, allDay bool, familyMemberID *int64, location, recurrenceRule string) (*model.CalendarEvent, error) {
+func (s *EventStore) UpdateWithRecurrence(id, householdID int64, title, description string, startTime, endTime time.Time, allDay bool, familyMemberID *int64, location, recurrenceRule string) (*model.CalendarEvent, error) {
 	var allDayInt int
 	if allDay {
 		allDayInt = 1
@@ -234,25 +366,81 @@ func (s *EventStore) UpdateWithRecurrence(id int64, title, description string, s
 		memberID = sql.NullInt64{Int64: *familyMemberID, Valid: true}
 	}
 
-	_, err := s.db.Exec(
+	existing, err := s.GetByID(id, householdID)
+	if err != nil || existing == nil {
+		return existing, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`UPDATE calendar_events
+		 SET title = ?, description = ?, start_time = ?, end_time = ?, all_day = ?, family_member_id = ?, location = ?, recurrence_rule = ?
+		 WHERE id = ? AND household_id = ?`,
+		title, description, startTime.UTC(), endTime.UTC(), allDayInt, memberID, location, recurrenceRule, id, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update calendar event: %w", err)
+	}
+	if !sameMemberID(existing.FamilyMemberID, familyMemberID) {
+		if existing.FamilyMemberID != nil {
+			if _, err := tx.Exec(`DELETE FROM event_attendees WHERE event_id = ? AND family_member_id = ?`, id, *existing.FamilyMemberID); err != nil {
+				return nil, fmt.Errorf("remove primary attendee: %w", err)
+			}
+		}
+		if err := addPrimaryAttendee(tx, id, familyMemberID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(id, householdID)
+}
+
+// UpdateWithAttendees updates an event and replaces its attendees. It
+// returns nil if the event is not the household's.
+func (s *EventStore) UpdateWithAttendees(id, householdID int64, e model.CalendarEvent) (*model.CalendarEvent, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
 		`UPDATE calendar_events
 		 SET title = ?, description = ?, start_time = ?, end_time = ?, all_day = ?, family_member_id = ?, location = ?, recurrence_rule = ?
-		 WHERE id = ?`,
-		title, description, startTime.UTC(), endTime.UTC(), allDayInt, memberID, location, recurrenceRule, id,
+		 WHERE id = ? AND household_id = ?`,
+		e.Title, e.Description, e.StartTime.UTC(), e.EndTime.UTC(), e.AllDay, nullableID(model.PrimaryAttendee(e.Attendees)), e.Location, e.RecurrenceRule,
+		id, householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("update calendar event: %w", err)
 	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, nil
+	}
+	if err := setAttendees(tx, id, e.Attendees); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
 }
 
-func (s *EventStore) SetReminderMinutes(id int64, minutes *int) error {
+func (s *EventStore) SetReminderMinutes(id, householdID int64, minutes *int) error {
 	var val any
 	if minutes != nil {
 		val = *minutes
 	}
-	_, err := s.db.Exec(`UPDATE calendar_events SET reminder_minutes = ? WHERE id = ?`, val, id)
+	_, err := s.db.Exec(`UPDATE calendar_events SET reminder_minutes = ? WHERE id = ? AND household_id = ?`, val, id, householdID)
 	if err != nil {
 		return fmt.Errorf("set reminder minutes: %w", err)
 	}
@@ -261,15 +449,17 @@ func (s *EventStore) SetReminderMinutes(id int64, minutes *int) error {
 
 // ListUpcomingWithReminders returns non-cancelled events that have a reminder set
 // and whose (start_time - reminder_minutes) falls within the given window.
-func (s *EventStore) ListUpcomingWithReminders(windowStart, windowEnd time.Time) ([]model.CalendarEvent, error) {
+func (s *EventStore) ListUpcomingWithReminders(householdID int64, windowStart, windowEnd time.Time) ([]model.CalendarEvent, error) {
 	rows, err := s.db.Query(
 		`SELECT `+selectCols+`
 		 FROM calendar_events
-		 WHERE reminder_minutes IS NOT NULL
+		 WHERE household_id = ?
+		   AND reminder_minutes IS NOT NULL
 		   AND cancelled = 0
 		   AND datetime(start_time, '-' || reminder_minutes || ' minutes') >= ?
 		   AND datetime(start_time, '-' || reminder_minutes || ' minutes') < ?
 		 ORDER BY start_time ASC`,
+		householdID,
 		windowStart.UTC().Format("2006-01-02 15:04:05"),
 		windowEnd.UTC().Format("2006-01-02 15:04:05"),
 	)
@@ -289,10 +479,244 @@ func (s *EventStore) ListUpcomingWithReminders(windowStart, windowEnd time.Time)
 	return events, rows.Err()
 }
 
-func (s *EventStore) Delete(id int64) error {
-	_, err := s.db.Exec("DELETE FROM calendar_events WHERE id = ?", id)
+func (s *EventStore) Delete(id, householdID int64) error {
+	_, err := s.db.Exec("DELETE FROM calendar_events WHERE id = ? AND household_id = ?", id, householdID)
 	if err != nil {
 		return fmt.Errorf("delete calendar event: %w", err)
 	}
 	return nil
 }
+
+// ListImported returns the events imported from a subscription, or from
+// .ics files when subscriptionID is nil.
+func (s *EventStore) ListImported(householdID int64, subscriptionID *int64) ([]model.CalendarEvent, error) {
+	rows, err := s.db.Query(
+		`SELECT `+selectCols+`
+		 FROM calendar_events
+		 WHERE household_id = ? AND source_uid != '' AND subscription_id IS ?
+		 ORDER BY recurrence_parent_id IS NOT NULL, id`,
+		householdID, nullableID(subscriptionID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query imported events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []model.CalendarEvent
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan imported event: %w", err)
+		}
+		events = append(events, *e)
+	}
+	return events, rows.Err()
+}
+
+// CreateImported inserts an event read from an iCalendar source, including
+// its recurrence parent, source UID, and subscription.
+func (s *EventStore) CreateImported(householdID int64, e model.CalendarEvent) (*model.CalendarEvent, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO calendar_events (household_id, title, description, start_time, end_time, all_day, family_member_id, location, recurrence_rule, recurrence_parent_id, original_start_time, cancelled, subscription_id, source_uid)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		householdID, e.Title, e.Description, e.StartTime.UTC(), e.EndTime.UTC(), e.AllDay, nullableID(e.FamilyMemberID), e.Location, e.RecurrenceRule,
+		nullableID(e.RecurrenceParentID), nullableTime(e.OriginalStartTime), e.Cancelled, nullableID(e.SubscriptionID), e.SourceUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert imported event: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("last insert id: %w", err)
+	}
+	if err := addPrimaryAttendee(tx, id, e.FamilyMemberID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(id, householdID)
+}
+
+// UpdateImported refreshes an imported event from its source. The assigned
+// family member is left alone so local assignments survive re-syncs.
+func (s *EventStore) UpdateImported(id, householdID int64, e model.CalendarEvent) (*model.CalendarEvent, error) {
+	_, err := s.db.Exec(
+		`UPDATE calendar_events
+		 SET title = ?, description = ?, start_time = ?, end_time = ?, all_day = ?, location = ?, recurrence_rule = ?, original_start_time = ?, cancelled = ?
+		 WHERE id = ? AND household_id = ?`,
+		e.Title, e.Description, e.StartTime.UTC(), e.EndTime.UTC(), e.AllDay, e.Location, e.RecurrenceRule, nullableTime(e.OriginalStartTime), e.Cancelled,
+		id, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update imported event: %w", err)
+	}
+
+	return s.GetByID(id, householdID)
+}
+
+// SetFamilyMember assigns an event, and any exceptions of it, to a family member.
+func (s *EventStore) SetFamilyMember(id, householdID int64, familyMemberID *int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`UPDATE calendar_events SET family_member_id = ?
+		 WHERE household_id = ? AND (id = ? OR recurrence_parent_id = ?)`,
+		nullableID(familyMemberID), householdID, id, id,
+	)
+	if err != nil {
+		return fmt.Errorf("set event family member: %w", err)
+	}
+	err = resetAttendees(tx,
+		`SELECT id FROM calendar_events WHERE household_id = ? AND (id = ? OR recurrence_parent_id = ?)`,
+		[]any{householdID, id, id}, familyMemberID,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetSubscriptionFamilyMember assigns every event from a subscription to a family member.
+func (s *EventStore) SetSubscriptionFamilyMember(subscriptionID, householdID int64, familyMemberID *int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`UPDATE calendar_events SET family_member_id = ? WHERE subscription_id = ? AND household_id = ?`,
+		nullableID(familyMemberID), subscriptionID, householdID,
+	)
+	if err != nil {
+		return fmt.Errorf("set subscription family member: %w", err)
+	}
+	err = resetAttendees(tx,
+		`SELECT id FROM calendar_events WHERE subscription_id = ? AND household_id = ?`,
+		[]any{subscriptionID, householdID}, familyMemberID,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetAttendees replaces an event's attendees. The primary attendee becomes
+// the event's family_member_id.
+func (s *EventStore) SetAttendees(id, householdID int64, attendees []model.Attendee) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`UPDATE calendar_events SET family_member_id = ? WHERE id = ? AND household_id = ?`,
+		nullableID(model.PrimaryAttendee(attendees)), id, householdID,
+	)
+	if err != nil {
+		return fmt.Errorf("set event family member: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil
+	}
+	if err := setAttendees(tx, id, attendees); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setAttendees replaces the attendees of an event.
+func setAttendees(tx *sql.Tx, id int64, attendees []model.Attendee) error {
+	if _, err := tx.Exec(`DELETE FROM event_attendees WHERE event_id = ?`, id); err != nil {
+		return fmt.Errorf("delete attendees: %w", err)
+	}
+	for _, a := range attendees {
+		if _, err := tx.Exec(
+			`INSERT INTO event_attendees (event_id, family_member_id, role) VALUES (?, ?, ?)`,
+			id, a.FamilyMemberID, a.Role,
+		); err != nil {
+			return fmt.Errorf("insert attendee: %w", err)
+		}
+	}
+	return nil
+}
+
+// addPrimaryAttendee records familyMemberID, if set, as an attendee of an
+// event. A member already attending keeps their role.
+func addPrimaryAttendee(tx *sql.Tx, id int64, familyMemberID *int64) error {
+	if familyMemberID == nil {
+		return nil
+	}
+	_, err := tx.Exec(
+		`INSERT INTO event_attendees (event_id, family_member_id, role) VALUES (?, ?, ?)
+		 ON CONFLICT (event_id, family_member_id) DO NOTHING`,
+		id, *familyMemberID, model.RoleAttendee,
+	)
+	if err != nil {
+		return fmt.Errorf("insert attendee: %w", err)
+	}
+	return nil
+}
+
+// resetAttendees makes familyMemberID the only attendee of the events
+// selected by query.
+func resetAttendees(tx *sql.Tx, query string, args []any, familyMemberID *int64) error {
+	if _, err := tx.Exec(`DELETE FROM event_attendees WHERE event_id IN (`+query+`)`, args...); err != nil {
+		return fmt.Errorf("delete attendees: %w", err)
+	}
+	if familyMemberID == nil {
+		return nil
+	}
+	_, err := tx.Exec(
+		`INSERT INTO event_attendees (event_id, family_member_id, role)
+		 SELECT id, ?, ? FROM (`+query+`)`,
+		append([]any{*familyMemberID, model.RoleAttendee}, args...)...,
+	)
+	if err != nil {
+		return fmt.Errorf("insert attendees: %w", err)
+	}
+	return nil
+}
+
+// SeriesUpdatedAt returns the latest updated_at of an event and its exceptions.
+func (s *EventStore) SeriesUpdatedAt(id, householdID int64) (time.Time, error) {
+	var updatedAt time.Time
+	err := s.db.QueryRow(
+		`SELECT updated_at FROM calendar_events
+		 WHERE household_id = ? AND (id = ? OR recurrence_parent_id = ?)
+		 ORDER BY updated_at DESC LIMIT 1`,
+		householdID, id, id,
+	).Scan(&updatedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return time.Time{}, fmt.Errorf("query series updated_at: %w", err)
+	}
+	return updatedAt, nil
+}
+
+func nullableID(id *int64) sql.NullInt64 {
+	if id == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *id, Valid: true}
+}
+
+func nullableTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
@@ -5,8 +5,12 @@ import (
 	"time"
 
 	"github.com/dukerupert/gamwich/internal/database"
+	"github.com/dukerupert/gamwich/internal/model"
 )
 
+// testHouseholdID is the default household created by the migrations.
+const testHouseholdID = DefaultHouseholdID
+
 func setupTestDB(t *testing.T) *EventStore {
 	t.Helper()
 	db, err := database.Open(":memory:")
@@ -27,7 +31,7 @@ func TestCreateAndGetByID(t *testing.T) {
 	start := time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC)
 	end := time.Date(2026, 2, 5, 11, 0, 0, 0, time.UTC)
 
-	event, err := s.Create("Team Meeting", "Weekly sync", start, end, false, nil, "Conference Room")
+	event, err := s.Create(testHouseholdID, "Team Meeting", "Weekly sync", start, end, false, nil, "Conference Room")
 	if err != nil {
 		t.Fatalf("create event: %v", err)
 	}
@@ -57,7 +61,7 @@ func TestCreateAndGetByID(t *testing.T) {
 	}
 
 	// GetByID
-	got, err := s.GetByID(event.ID)
+	got, err := s.GetByID(event.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get by id: %v", err)
 	}
@@ -69,7 +73,7 @@ func TestCreateAndGetByID(t *testing.T) {
 func TestGetByIDNotFound(t *testing.T) {
 	s := setupTestDB(t)
 
-	got, err := s.GetByID(999)
+	got, err := s.GetByID(999, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get by id: %v", err)
 	}
@@ -91,7 +95,7 @@ func TestCreateWithFamilyMember(t *testing.T) {
 	start := time.Date(2026, 2, 5, 14, 0, 0, 0, time.UTC)
 	end := time.Date(2026, 2, 5, 15, 0, 0, 0, time.UTC)
 
-	event, err := s.Create("Alice's Meeting", "", start, end, false, &memberID, "")
+	event, err := s.Create(testHouseholdID, "Alice's Meeting", "", start, end, false, &memberID, "")
 	if err != nil {
 		t.Fatalf("create event: %v", err)
 	}
@@ -106,7 +110,7 @@ func TestCreateAllDayEvent(t *testing.T) {
 	start := time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2026, 2, 6, 0, 0, 0, 0, time.UTC)
 
-	event, err := s.Create("Holiday", "", start, end, true, nil, "")
+	event, err := s.Create(testHouseholdID, "Holiday", "", start, end, true, nil, "")
 	if err != nil {
 		t.Fatalf("create event: %v", err)
 	}
@@ -121,21 +125,21 @@ func TestListByDateRange(t *testing.T) {
 	// Create events across several days
 	day1Start := time.Date(2026, 2, 5, 9, 0, 0, 0, time.UTC)
 	day1End := time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC)
-	s.Create("Day 1 Event", "", day1Start, day1End, false, nil, "")
+	s.Create(testHouseholdID, "Day 1 Event", "", day1Start, day1End, false, nil, "")
 
 	day2Start := time.Date(2026, 2, 6, 9, 0, 0, 0, time.UTC)
 	day2End := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
-	s.Create("Day 2 Event", "", day2Start, day2End, false, nil, "")
+	s.Create(testHouseholdID, "Day 2 Event", "", day2Start, day2End, false, nil, "")
 
 	day3Start := time.Date(2026, 2, 7, 9, 0, 0, 0, time.UTC)
 	day3End := time.Date(2026, 2, 7, 10, 0, 0, 0, time.UTC)
-	s.Create("Day 3 Event", "", day3Start, day3End, false, nil, "")
+	s.Create(testHouseholdID, "Day 3 Event", "", day3Start, day3End, false, nil, "")
 
 	// Query only day 1-2 range
 	rangeStart := time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC)
 	rangeEnd := time.Date(2026, 2, 7, 0, 0, 0, 0, time.UTC)
 
-	events, err := s.ListByDateRange(rangeStart, rangeEnd)
+	events, err := s.ListByDateRange(testHouseholdID, rangeStart, rangeEnd)
 	if err != nil {
 		t.Fatalf("list by range: %v", err)
 	}
@@ -157,15 +161,15 @@ func TestListByDateRangeExcludesRecurring(t *testing.T) {
 	end := time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC)
 
 	// Create a normal event
-	s.Create("Normal Event", "", start, end, false, nil, "")
+	s.Create(testHouseholdID, "Normal Event", "", start, end, false, nil, "")
 
 	// Create a recurring event
-	s.CreateWithRecurrence("Weekly Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY")
+	s.CreateWithRecurrence(testHouseholdID, "Weekly Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY")
 
 	// ListByDateRange should only return the normal event
 	rangeStart := time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC)
 	rangeEnd := time.Date(2026, 2, 6, 0, 0, 0, 0, time.UTC)
-	events, err := s.ListByDateRange(rangeStart, rangeEnd)
+	events, err := s.ListByDateRange(testHouseholdID, rangeStart, rangeEnd)
 	if err != nil {
 		t.Fatalf("list by range: %v", err)
 	}
@@ -184,11 +188,11 @@ func TestListByDateRangeAllDayFirst(t *testing.T) {
 	end := time.Date(2026, 2, 6, 0, 0, 0, 0, time.UTC)
 
 	// Create timed event first
-	s.Create("Morning Meeting", "", time.Date(2026, 2, 5, 9, 0, 0, 0, time.UTC), time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC), false, nil, "")
+	s.Create(testHouseholdID, "Morning Meeting", "", time.Date(2026, 2, 5, 9, 0, 0, 0, time.UTC), time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC), false, nil, "")
 	// Create all-day event second
-	s.Create("Holiday", "", start, end, true, nil, "")
+	s.Create(testHouseholdID, "Holiday", "", start, end, true, nil, "")
 
-	events, err := s.ListByDateRange(start, end)
+	events, err := s.ListByDateRange(testHouseholdID, start, end)
 	if err != nil {
 		t.Fatalf("list by range: %v", err)
 	}
@@ -207,13 +211,13 @@ func TestListByDateRangeSpanningEvent(t *testing.T) {
 	// Event spans across query range
 	eventStart := time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC)
 	eventEnd := time.Date(2026, 2, 8, 0, 0, 0, 0, time.UTC)
-	s.Create("Multi-day Event", "", eventStart, eventEnd, false, nil, "")
+	s.Create(testHouseholdID, "Multi-day Event", "", eventStart, eventEnd, false, nil, "")
 
 	// Query for a single day within the span
 	rangeStart := time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC)
 	rangeEnd := time.Date(2026, 2, 6, 0, 0, 0, 0, time.UTC)
 
-	events, err := s.ListByDateRange(rangeStart, rangeEnd)
+	events, err := s.ListByDateRange(testHouseholdID, rangeStart, rangeEnd)
 	if err != nil {
 		t.Fatalf("list by range: %v", err)
 	}
@@ -228,14 +232,14 @@ func TestUpdate(t *testing.T) {
 	start := time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC)
 	end := time.Date(2026, 2, 5, 11, 0, 0, 0, time.UTC)
 
-	event, err := s.Create("Original Title", "", start, end, false, nil, "")
+	event, err := s.Create(testHouseholdID, "Original Title", "", start, end, false, nil, "")
 	if err != nil {
 		t.Fatalf("create event: %v", err)
 	}
 
 	newStart := time.Date(2026, 2, 5, 14, 0, 0, 0, time.UTC)
 	newEnd := time.Date(2026, 2, 5, 15, 30, 0, 0, time.UTC)
-	updated, err := s.Update(event.ID, "Updated Title", "Added desc", newStart, newEnd, true, nil, "New Location")
+	updated, err := s.Update(event.ID, testHouseholdID, "Updated Title", "Added desc", newStart, newEnd, true, nil, "New Location")
 	if err != nil {
 		t.Fatalf("update event: %v", err)
 	}
@@ -259,16 +263,16 @@ func TestDelete(t *testing.T) {
 	start := time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC)
 	end := time.Date(2026, 2, 5, 11, 0, 0, 0, time.UTC)
 
-	event, err := s.Create("To Delete", "", start, end, false, nil, "")
+	event, err := s.Create(testHouseholdID, "To Delete", "", start, end, false, nil, "")
 	if err != nil {
 		t.Fatalf("create event: %v", err)
 	}
 
-	if err := s.Delete(event.ID); err != nil {
+	if err := s.Delete(event.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete event: %v", err)
 	}
 
-	got, err := s.GetByID(event.ID)
+	got, err := s.GetByID(event.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get by id after delete: %v", err)
 	}
@@ -290,7 +294,7 @@ func TestFamilyMemberDeleteSetsNull(t *testing.T) {
 	start := time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC)
 	end := time.Date(2026, 2, 5, 11, 0, 0, 0, time.UTC)
 
-	event, err := s.Create("Bob's Event", "", start, end, false, &memberID, "")
+	event, err := s.Create(testHouseholdID, "Bob's Event", "", start, end, false, &memberID, "")
 	if err != nil {
 		t.Fatalf("create event: %v", err)
 	}
@@ -302,7 +306,7 @@ func TestFamilyMemberDeleteSetsNull(t *testing.T) {
 	}
 
 	// Event should still exist with null family_member_id
-	got, err := s.GetByID(event.ID)
+	got, err := s.GetByID(event.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get by id: %v", err)
 	}
@@ -314,6 +318,126 @@ func TestFamilyMemberDeleteSetsNull(t *testing.T) {
 	}
 }
 
+func TestSetAttendees(t *testing.T) {
+	s := setupTestDB(t)
+
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		if _, err := s.db.Exec("INSERT INTO family_members (name, color, avatar_emoji, sort_order) VALUES (?, ?, ?, ?)", name, "#FF0000", "A", 0); err != nil {
+			t.Fatalf("insert family member: %v", err)
+		}
+	}
+	alice, bob, carol := int64(1), int64(2), int64(3)
+
+	start := time.Date(2026, 2, 5, 16, 0, 0, 0, time.UTC)
+	event, err := s.Create(testHouseholdID, "Soccer", "", start, start.Add(time.Hour), false, &alice, "Park")
+	if err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	if len(event.Attendees) != 1 || event.Attendees[0] != (model.Attendee{FamilyMemberID: alice, Role: model.RoleAttendee}) {
+		t.Fatalf("attendees = %v, want Alice only", event.Attendees)
+	}
+
+	err = s.SetAttendees(event.ID, testHouseholdID, []model.Attendee{
+		{FamilyMemberID: bob, Role: model.RoleDriver},
+		{FamilyMemberID: carol, Role: model.RoleAttendee},
+	})
+	if err != nil {
+		t.Fatalf("set attendees: %v", err)
+	}
+	got, _ := s.GetByID(event.ID, testHouseholdID)
+	if got.FamilyMemberID == nil || *got.FamilyMemberID != carol {
+		t.Errorf("family_member_id = %v, want %d", got.FamilyMemberID, carol)
+	}
+	want := []model.Attendee{{FamilyMemberID: carol, Role: model.RoleAttendee}, {FamilyMemberID: bob, Role: model.RoleDriver}}
+	if len(got.Attendees) != 2 || got.Attendees[0] != want[0] || got.Attendees[1] != want[1] {
+		t.Errorf("attendees = %v, want %v", got.Attendees, want)
+	}
+
+	// Changing the primary member keeps the other attendees.
+	got, err = s.Update(event.ID, testHouseholdID, "Soccer", "", start, start.Add(time.Hour), false, &alice, "Park")
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	want = []model.Attendee{{FamilyMemberID: alice, Role: model.RoleAttendee}, {FamilyMemberID: bob, Role: model.RoleDriver}}
+	if len(got.Attendees) != 2 || got.Attendees[0] != want[0] || got.Attendees[1] != want[1] {
+		t.Errorf("attendees after update = %v, want %v", got.Attendees, want)
+	}
+
+	if err := s.SetAttendees(event.ID, testHouseholdID, nil); err != nil {
+		t.Fatalf("clear attendees: %v", err)
+	}
+	got, _ = s.GetByID(event.ID, testHouseholdID)
+	if got.FamilyMemberID != nil || len(got.Attendees) != 0 {
+		t.Errorf("after clearing: family_member_id = %v, attendees = %v", got.FamilyMemberID, got.Attendees)
+	}
+}
+
+func TestSaveWithAttendees(t *testing.T) {
+	s := setupTestDB(t)
+
+	for _, name := range []string{"Alice", "Bob"} {
+		if _, err := s.db.Exec("INSERT INTO family_members (name, color, avatar_emoji, sort_order) VALUES (?, ?, ?, ?)", name, "#FF0000", "A", 0); err != nil {
+			t.Fatalf("insert family member: %v", err)
+		}
+	}
+	alice, bob, missing := int64(1), int64(2), int64(99)
+
+	start := time.Date(2026, 2, 5, 16, 0, 0, 0, time.UTC)
+	e := model.CalendarEvent{
+		Title:     "Soccer",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		Location:  "Park",
+		Attendees: []model.Attendee{{FamilyMemberID: bob, Role: model.RoleDriver}, {FamilyMemberID: alice, Role: model.RoleAttendee}},
+	}
+	event, err := s.CreateWithAttendees(testHouseholdID, e)
+	if err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	if event.FamilyMemberID == nil || *event.FamilyMemberID != alice {
+		t.Errorf("family_member_id = %v, want %d", event.FamilyMemberID, alice)
+	}
+	want := []model.Attendee{{FamilyMemberID: alice, Role: model.RoleAttendee}, {FamilyMemberID: bob, Role: model.RoleDriver}}
+	if len(event.Attendees) != 2 || event.Attendees[0] != want[0] || event.Attendees[1] != want[1] {
+		t.Errorf("attendees = %v, want %v", event.Attendees, want)
+	}
+
+	// A failed attendee write leaves no event behind.
+	bad := e
+	bad.Title = "Swim"
+	bad.Attendees = []model.Attendee{{FamilyMemberID: missing, Role: model.RoleAttendee}}
+	if _, err := s.CreateWithAttendees(testHouseholdID, bad); err == nil {
+		t.Fatal("expected error creating event with unknown attendee")
+	}
+	var n int
+	s.db.QueryRow("SELECT COUNT(*) FROM calendar_events").Scan(&n)
+	if n != 1 {
+		t.Errorf("events = %d, want 1", n)
+	}
+
+	// Nor does it change the event being updated.
+	if _, err := s.UpdateWithAttendees(event.ID, testHouseholdID, bad); err == nil {
+		t.Fatal("expected error updating event with unknown attendee")
+	}
+	got, _ := s.GetByID(event.ID, testHouseholdID)
+	if got.Title != "Soccer" || len(got.Attendees) != 2 {
+		t.Errorf("after failed update: title = %q, attendees = %v", got.Title, got.Attendees)
+	}
+
+	e.Attendees = []model.Attendee{{FamilyMemberID: bob, Role: model.RoleAttendee}}
+	got, err = s.UpdateWithAttendees(event.ID, testHouseholdID, e)
+	if err != nil {
+		t.Fatalf("update event: %v", err)
+	}
+	if got.FamilyMemberID == nil || *got.FamilyMemberID != bob || len(got.Attendees) != 1 || got.Attendees[0] != e.Attendees[0] {
+		t.Errorf("after update: family_member_id = %v, attendees = %v", got.FamilyMemberID, got.Attendees)
+	}
+
+	if got, err := s.UpdateWithAttendees(event.ID, testHouseholdID+1, e); err != nil || got != nil {
+		t.Errorf("update in other household = %v, %v, want nil", got, err)
+	}
+}
+
 // --- Recurrence-specific tests ---
 
 func TestCreateWithRecurrence(t *testing.T) {
@@ -322,7 +446,7 @@ func TestCreateWithRecurrence(t *testing.T) {
 	start := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC) // Tuesday
 	end := time.Date(2026, 2, 3, 11, 0, 0, 0, time.UTC)
 
-	event, err := s.CreateWithRecurrence("Soccer Practice", "", start, end, false, nil, "Field", "FREQ=WEEKLY;BYDAY=TU,TH")
+	event, err := s.CreateWithRecurrence(testHouseholdID, "Soccer Practice", "", start, end, false, nil, "Field", "FREQ=WEEKLY;BYDAY=TU,TH")
 	if err != nil {
 		t.Fatalf("create recurring event: %v", err)
 	}
@@ -341,13 +465,13 @@ func TestListRecurring(t *testing.T) {
 	end := time.Date(2026, 2, 3, 11, 0, 0, 0, time.UTC)
 
 	// Create a recurring event
-	s.CreateWithRecurrence("Weekly Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY")
+	s.CreateWithRecurrence(testHouseholdID, "Weekly Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY")
 
 	// Create a normal event
-	s.Create("Normal Event", "", start, end, false, nil, "")
+	s.Create(testHouseholdID, "Normal Event", "", start, end, false, nil, "")
 
 	// ListRecurring should only return the recurring one
-	events, err := s.ListRecurring(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	events, err := s.ListRecurring(testHouseholdID, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
 	if err != nil {
 		t.Fatalf("list recurring: %v", err)
 	}
@@ -365,10 +489,10 @@ func TestListRecurringBeforeFilter(t *testing.T) {
 	// Create a recurring event starting Feb 10
 	start := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
 	end := time.Date(2026, 2, 10, 11, 0, 0, 0, time.UTC)
-	s.CreateWithRecurrence("Future Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY")
+	s.CreateWithRecurrence(testHouseholdID, "Future Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY")
 
 	// ListRecurring with before=Feb 5 should return nothing
-	events, err := s.ListRecurring(time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC))
+	events, err := s.ListRecurring(testHouseholdID, time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC))
 	if err != nil {
 		t.Fatalf("list recurring: %v", err)
 	}
@@ -383,7 +507,7 @@ func TestCreateAndListExceptions(t *testing.T) {
 	start := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC) // Tuesday
 	end := time.Date(2026, 2, 3, 11, 0, 0, 0, time.UTC)
 
-	parent, err := s.CreateWithRecurrence("Weekly Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY")
+	parent, err := s.CreateWithRecurrence(testHouseholdID, "Weekly Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY")
 	if err != nil {
 		t.Fatalf("create parent: %v", err)
 	}
@@ -393,7 +517,7 @@ func TestCreateAndListExceptions(t *testing.T) {
 	newStart := time.Date(2026, 2, 10, 14, 0, 0, 0, time.UTC)
 	newEnd := time.Date(2026, 2, 10, 15, 0, 0, 0, time.UTC)
 
-	exc, err := s.CreateException(parent.ID, origStart, "Weekly Meeting (moved)", "", newStart, newEnd, false, nil, "", false)
+	exc, err := s.CreateException(testHouseholdID, parent.ID, origStart, "Weekly Meeting (moved)", "", newStart, newEnd, false, nil, "", false)
 	if err != nil {
 		t.Fatalf("create exception: %v", err)
 	}
@@ -412,13 +536,13 @@ func TestCreateAndListExceptions(t *testing.T) {
 
 	// Create a cancelled exception for Feb 17
 	origStart2 := time.Date(2026, 2, 17, 10, 0, 0, 0, time.UTC)
-	_, err = s.CreateException(parent.ID, origStart2, "Weekly Meeting", "", origStart2, origStart2.Add(time.Hour), false, nil, "", true)
+	_, err = s.CreateException(testHouseholdID, parent.ID, origStart2, "Weekly Meeting", "", origStart2, origStart2.Add(time.Hour), false, nil, "", true)
 	if err != nil {
 		t.Fatalf("create cancelled exception: %v", err)
 	}
 
 	// List exceptions
-	exceptions, err := s.ListExceptions(parent.ID)
+	exceptions, err := s.ListExceptions(parent.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("list exceptions: %v", err)
 	}
@@ -436,17 +560,17 @@ func TestDeleteExceptions(t *testing.T) {
 	start := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
 	end := time.Date(2026, 2, 3, 11, 0, 0, 0, time.UTC)
 
-	parent, _ := s.CreateWithRecurrence("Weekly", "", start, end, false, nil, "", "FREQ=WEEKLY")
+	parent, _ := s.CreateWithRecurrence(testHouseholdID, "Weekly", "", start, end, false, nil, "", "FREQ=WEEKLY")
 
 	origStart := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
-	s.CreateException(parent.ID, origStart, "Modified", "", origStart, origStart.Add(time.Hour), false, nil, "", false)
+	s.CreateException(testHouseholdID, parent.ID, origStart, "Modified", "", origStart, origStart.Add(time.Hour), false, nil, "", false)
 
-	err := s.DeleteExceptions(parent.ID)
+	err := s.DeleteExceptions(parent.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("delete exceptions: %v", err)
 	}
 
-	exceptions, _ := s.ListExceptions(parent.ID)
+	exceptions, _ := s.ListExceptions(parent.ID, testHouseholdID)
 	if len(exceptions) != 0 {
 		t.Errorf("got %d exceptions after delete, want 0", len(exceptions))
 	}
@@ -458,17 +582,17 @@ func TestDeleteParentCascadesExceptions(t *testing.T) {
 	start := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
 	end := time.Date(2026, 2, 3, 11, 0, 0, 0, time.UTC)
 
-	parent, _ := s.CreateWithRecurrence("Weekly", "", start, end, false, nil, "", "FREQ=WEEKLY")
+	parent, _ := s.CreateWithRecurrence(testHouseholdID, "Weekly", "", start, end, false, nil, "", "FREQ=WEEKLY")
 
 	origStart := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
-	exc, _ := s.CreateException(parent.ID, origStart, "Modified", "", origStart, origStart.Add(time.Hour), false, nil, "", false)
+	exc, _ := s.CreateException(testHouseholdID, parent.ID, origStart, "Modified", "", origStart, origStart.Add(time.Hour), false, nil, "", false)
 
 	// Delete parent — exceptions should cascade
-	if err := s.Delete(parent.ID); err != nil {
+	if err := s.Delete(parent.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete parent: %v", err)
 	}
 
-	got, _ := s.GetByID(exc.ID)
+	got, _ := s.GetByID(exc.ID, testHouseholdID)
 	if got != nil {
 		t.Error("exception should be deleted via CASCADE")
 	}
@@ -480,9 +604,9 @@ func TestUpdateWithRecurrence(t *testing.T) {
 	start := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
 	end := time.Date(2026, 2, 3, 11, 0, 0, 0, time.UTC)
 
-	event, _ := s.CreateWithRecurrence("Weekly Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY")
+	event, _ := s.CreateWithRecurrence(testHouseholdID, "Weekly Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY")
 
-	updated, err := s.UpdateWithRecurrence(event.ID, "Biweekly Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY;INTERVAL=2")
+	updated, err := s.UpdateWithRecurrence(event.ID, testHouseholdID, "Biweekly Meeting", "", start, end, false, nil, "", "FREQ=WEEKLY;INTERVAL=2")
 	if err != nil {
 		t.Fatalf("update with recurrence: %v", err)
 	}
@@ -490,3 +614,92 @@ func TestUpdateWithRecurrence(t *testing.T) {
 		t.Errorf("recurrence_rule = %q, want %q", updated.RecurrenceRule, "FREQ=WEEKLY;INTERVAL=2")
 	}
 }
+
+func TestEventHouseholdIsolation(t *testing.T) {
+	s := setupTestDB(t)
+	otherID := createTestHousehold(t, s.db, "Other")
+
+	start := time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	event, err := s.Create(testHouseholdID, "Ours", "", start, end, false, nil, "")
+	if err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	parent, err := s.CreateWithRecurrence(testHouseholdID, "Weekly", "", start, end, false, nil, "", "FREQ=WEEKLY")
+	if err != nil {
+		t.Fatalf("create recurring: %v", err)
+	}
+	if _, err := s.CreateException(testHouseholdID, parent.ID, start.AddDate(0, 0, 7), "Weekly", "", start, end, false, nil, "", true); err != nil {
+		t.Fatalf("create exception: %v", err)
+	}
+
+	got, err := s.GetByID(event.ID, otherID)
+	if err != nil {
+		t.Fatalf("get by id: %v", err)
+	}
+	if got != nil {
+		t.Error("expected nil when reading another household's event")
+	}
+
+	events, _ := s.ListByDateRange(otherID, start.Add(-time.Hour), end.Add(time.Hour))
+	if len(events) != 0 {
+		t.Errorf("other household sees %d events, want 0", len(events))
+	}
+	recurring, _ := s.ListRecurring(otherID, end.AddDate(0, 1, 0))
+	if len(recurring) != 0 {
+		t.Errorf("other household sees %d recurring events, want 0", len(recurring))
+	}
+	exceptions, _ := s.ListExceptions(parent.ID, otherID)
+	if len(exceptions) != 0 {
+		t.Errorf("other household sees %d exceptions, want 0", len(exceptions))
+	}
+
+	updated, err := s.Update(event.ID, otherID, "Hijacked", "", start, end, false, nil, "")
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated != nil {
+		t.Error("expected nil when updating another household's event")
+	}
+	if err := s.Delete(event.ID, otherID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := s.DeleteExceptions(parent.ID, otherID); err != nil {
+		t.Fatalf("delete exceptions: %v", err)
+	}
+
+	got, _ = s.GetByID(event.ID, testHouseholdID)
+	if got == nil || got.Title != "Ours" {
+		t.Errorf("event was modified by another household: %+v", got)
+	}
+	exceptions, _ = s.ListExceptions(parent.ID, testHouseholdID)
+	if len(exceptions) != 1 {
+		t.Errorf("exceptions = %d, want 1", len(exceptions))
+	}
+}
+
+func TestListForFeed(t *testing.T) {
+	s := setupTestDB(t)
+
+	old := time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC)
+	recent := time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC)
+
+	s.Create(testHouseholdID, "Old one-off", "", old, old.Add(time.Hour), false, nil, "")
+	s.Create(testHouseholdID, "Recent one-off", "", recent, recent.Add(time.Hour), false, nil, "")
+	parent, _ := s.CreateWithRecurrence(testHouseholdID, "Old weekly", "", old, old.Add(time.Hour), false, nil, "", "FREQ=WEEKLY")
+	s.CreateException(testHouseholdID, parent.ID, old.AddDate(0, 0, 7), "Old weekly", "", old, old.Add(time.Hour), false, nil, "", true)
+
+	events, err := s.ListForFeed(testHouseholdID, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("list for feed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events (parent, exception, recent), got %d", len(events))
+	}
+	for _, e := range events {
+		if e.Title == "Old one-off" {
+			t.Error("one-off events ending before since should be excluded")
+		}
+	}
+}
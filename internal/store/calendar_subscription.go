@@ -0,0 +1,145 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+type CalendarSubscriptionStore struct {
+	db *sql.DB
+}
+
+func NewCalendarSubscriptionStore(db *sql.DB) *CalendarSubscriptionStore {
+	return &CalendarSubscriptionStore{db: db}
+}
+
+func scanCalendarSubscription(scanner interface{ Scan(...any) error }) (*model.CalendarSubscription, error) {
+	var sub model.CalendarSubscription
+	var memberID sql.NullInt64
+	var lastSynced sql.NullTime
+	err := scanner.Scan(&sub.ID, &sub.HouseholdID, &sub.Name, &sub.URL, &memberID, &lastSynced, &sub.LastError, &sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if memberID.Valid {
+		sub.FamilyMemberID = &memberID.Int64
+	}
+	if lastSynced.Valid {
+		sub.LastSyncedAt = &lastSynced.Time
+	}
+	return &sub, nil
+}
+
+const subscriptionCols = `id, household_id, name, url, family_member_id, last_synced_at, last_error, created_at, updated_at`
+
+func (s *CalendarSubscriptionStore) Create(householdID int64, name, url string, familyMemberID *int64) (*model.CalendarSubscription, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO calendar_subscriptions (household_id, name, url, family_member_id) VALUES (?, ?, ?, ?)`,
+		householdID, name, url, nullableID(familyMemberID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert calendar subscription: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("last insert id: %w", err)
+	}
+	return s.GetByID(id, householdID)
+}
+
+func (s *CalendarSubscriptionStore) GetByID(id, householdID int64) (*model.CalendarSubscription, error) {
+	row := s.db.QueryRow(
+		`SELECT `+subscriptionCols+` FROM calendar_subscriptions WHERE id = ? AND household_id = ?`, id, householdID,
+	)
+	sub, err := scanCalendarSubscription(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get calendar subscription: %w", err)
+	}
+	return sub, nil
+}
+
+func (s *CalendarSubscriptionStore) List(householdID int64) ([]model.CalendarSubscription, error) {
+	rows, err := s.db.Query(
+		`SELECT `+subscriptionCols+` FROM calendar_subscriptions WHERE household_id = ? ORDER BY name COLLATE NOCASE, id`,
+		householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list calendar subscriptions: %w", err)
+	}
+	defer rows.Close()
+	return scanCalendarSubscriptions(rows)
+}
+
+// ListDue returns subscriptions across all households that have never synced
+// or last synced before the given time.
+func (s *CalendarSubscriptionStore) ListDue(before time.Time) ([]model.CalendarSubscription, error) {
+	rows, err := s.db.Query(
+		`SELECT `+subscriptionCols+` FROM calendar_subscriptions
+		 WHERE last_synced_at IS NULL OR last_synced_at < ?
+		 ORDER BY last_synced_at IS NOT NULL, last_synced_at, id`,
+		before.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list due calendar subscriptions: %w", err)
+	}
+	defer rows.Close()
+	return scanCalendarSubscriptions(rows)
+}
+
+func scanCalendarSubscriptions(rows *sql.Rows) ([]model.CalendarSubscription, error) {
+	var subs []model.CalendarSubscription
+	for rows.Next() {
+		sub, err := scanCalendarSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan calendar subscription: %w", err)
+		}
+		subs = append(subs, *sub)
+	}
+	return subs, rows.Err()
+}
+
+func (s *CalendarSubscriptionStore) Update(id, householdID int64, name, url string, familyMemberID *int64) (*model.CalendarSubscription, error) {
+	_, err := s.db.Exec(
+		`UPDATE calendar_subscriptions SET name = ?, url = ?, family_member_id = ? WHERE id = ? AND household_id = ?`,
+		name, url, nullableID(familyMemberID), id, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update calendar subscription: %w", err)
+	}
+	return s.GetByID(id, householdID)
+}
+
+// RecordSync stores the outcome of a fetch. An empty errMsg clears the last error.
+func (s *CalendarSubscriptionStore) RecordSync(id int64, syncedAt time.Time, errMsg string) error {
+	_, err := s.db.Exec(
+		`UPDATE calendar_subscriptions SET last_synced_at = ?, last_error = ? WHERE id = ?`,
+		syncedAt.UTC(), errMsg, id,
+	)
+	if err != nil {
+		return fmt.Errorf("record calendar subscription sync: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a subscription and the events imported from it.
+func (s *CalendarSubscriptionStore) Delete(id, householdID int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM calendar_events WHERE subscription_id = ? AND household_id = ?`, id, householdID); err != nil {
+		return fmt.Errorf("delete subscription events: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM calendar_subscriptions WHERE id = ? AND household_id = ?`, id, householdID); err != nil {
+		return fmt.Errorf("delete calendar subscription: %w", err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/database"
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+func setupSubscriptionTestDB(t *testing.T) (*CalendarSubscriptionStore, *EventStore) {
+	t.Helper()
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewCalendarSubscriptionStore(db), NewEventStore(db)
+}
+
+func TestCalendarSubscriptionCRUD(t *testing.T) {
+	s, _ := setupSubscriptionTestDB(t)
+
+	sub, err := s.Create(testHouseholdID, "School", "webcal://example.com/school.ics", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if sub.Name != "School" || sub.LastSyncedAt != nil || sub.FamilyMemberID != nil {
+		t.Errorf("unexpected subscription: %+v", sub)
+	}
+
+	updated, err := s.Update(sub.ID, testHouseholdID, "School events", sub.URL, nil)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Name != "School events" {
+		t.Errorf("name = %q, want %q", updated.Name, "School events")
+	}
+
+	subs, err := s.List(testHouseholdID)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(subs) != 1 {
+		t.Fatalf("len = %d, want 1", len(subs))
+	}
+
+	other, _ := s.GetByID(sub.ID, testHouseholdID+1)
+	if other != nil {
+		t.Error("expected subscription to be hidden from other households")
+	}
+}
+
+func TestCalendarSubscriptionListDue(t *testing.T) {
+	s, _ := setupSubscriptionTestDB(t)
+
+	fresh, _ := s.Create(testHouseholdID, "Fresh", "https://example.com/a.ics", nil)
+	stale, _ := s.Create(testHouseholdID, "Stale", "https://example.com/b.ics", nil)
+	never, _ := s.Create(testHouseholdID, "Never", "https://example.com/c.ics", nil)
+
+	now := time.Now()
+	s.RecordSync(fresh.ID, now, "")
+	s.RecordSync(stale.ID, now.Add(-2*time.Hour), "fetch failed")
+
+	due, err := s.ListDue(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("list due: %v", err)
+	}
+	if len(due) != 2 || due[0].ID != never.ID || due[1].ID != stale.ID {
+		t.Fatalf("due = %+v, want never-synced then stale", due)
+	}
+	if due[1].LastError != "fetch failed" {
+		t.Errorf("last error = %q", due[1].LastError)
+	}
+}
+
+func TestCalendarSubscriptionDeleteRemovesEvents(t *testing.T) {
+	s, es := setupSubscriptionTestDB(t)
+
+	sub, _ := s.Create(testHouseholdID, "Team", "https://example.com/team.ics", nil)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	_, err := es.CreateImported(testHouseholdID, model.CalendarEvent{
+		Title: "Game", StartTime: start, EndTime: start.Add(time.Hour),
+		SubscriptionID: &sub.ID, SourceUID: "game@example.com",
+	})
+	if err != nil {
+		t.Fatalf("create imported: %v", err)
+	}
+	fileEvent, _ := es.CreateImported(testHouseholdID, model.CalendarEvent{
+		Title: "Recital", StartTime: start, EndTime: start.Add(time.Hour), SourceUID: "recital@example.com",
+	})
+
+	if err := s.Delete(sub.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	imported, _ := es.ListImported(testHouseholdID, &sub.ID)
+	if len(imported) != 0 {
+		t.Errorf("expected subscription events removed, got %d", len(imported))
+	}
+	files, _ := es.ListImported(testHouseholdID, nil)
+	if len(files) != 1 || files[0].ID != fileEvent.ID {
+		t.Errorf("expected file import to survive, got %+v", files)
+	}
+}
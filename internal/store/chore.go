@@ -1,8 +1,12 @@
 package store
 
 import (
+	"crypto/rand"
 	"database/sql"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dukerupert/gamwich/internal/model"
@@ -29,8 +33,8 @@ func scanArea(scanner interface{ Scan(...any) error }) (*model.ChoreArea, error)
 
 const areaCols = `id, name, sort_order, created_at, updated_at`
 
-func (s *ChoreStore) ListAreas() ([]model.ChoreArea, error) {
-	rows, err := s.db.Query(`SELECT ` + areaCols + ` FROM chore_areas ORDER BY sort_order ASC, name ASC`)
+func (s *ChoreStore) ListAreas(householdID int64) ([]model.ChoreArea, error) {
+	rows, err := s.db.Query(`SELECT `+areaCols+` FROM chore_areas WHERE household_id = ? ORDER BY sort_order ASC, name ASC`, householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list areas: %w", err)
 	}
@@ -47,8 +51,8 @@ func (s *ChoreStore) ListAreas() ([]model.ChoreArea, error) {
 	return areas, rows.Err()
 }
 
-func (s *ChoreStore) GetAreaByID(id int64) (*model.ChoreArea, error) {
-	row := s.db.QueryRow(`SELECT `+areaCols+` FROM chore_areas WHERE id = ?`, id)
+func (s *ChoreStore) GetAreaByID(id, householdID int64) (*model.ChoreArea, error) {
+	row := s.db.QueryRow(`SELECT `+areaCols+` FROM chore_areas WHERE id = ? AND household_id = ?`, id, householdID)
 	a, err := scanArea(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -59,10 +63,10 @@ func (s *ChoreStore) GetAreaByID(id int64) (*model.ChoreArea, error) {
 	return a, nil
 }
 
-func (s *ChoreStore) CreateArea(name string, sortOrder int) (*model.ChoreArea, error) {
+func (s *ChoreStore) CreateArea(householdID int64, name string, sortOrder int) (*model.ChoreArea, error) {
 	result, err := s.db.Exec(
-		`INSERT INTO chore_areas (name, sort_order) VALUES (?, ?)`,
-		name, sortOrder,
+		`INSERT INTO chore_areas (household_id, name, sort_order) VALUES (?, ?, ?)`,
+		householdID, name, sortOrder,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert area: %w", err)
@@ -71,29 +75,29 @@ func (s *ChoreStore) CreateArea(name string, sortOrder int) (*model.ChoreArea, e
 	if err != nil {
 		return nil, fmt.Errorf("last insert id: %w", err)
 	}
-	return s.GetAreaByID(id)
+	return s.GetAreaByID(id, householdID)
 }
 
-func (s *ChoreStore) UpdateArea(id int64, name string, sortOrder int) (*model.ChoreArea, error) {
+func (s *ChoreStore) UpdateArea(id, householdID int64, name string, sortOrder int) (*model.ChoreArea, error) {
 	_, err := s.db.Exec(
-		`UPDATE chore_areas SET name = ?, sort_order = ? WHERE id = ?`,
-		name, sortOrder, id,
+		`UPDATE chore_areas SET name = ?, sort_order = ? WHERE id = ? AND household_id = ?`,
+		name, sortOrder, id, householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("update area: %w", err)
 	}
-	return s.GetAreaByID(id)
+	return s.GetAreaByID(id, householdID)
 }
 
-func (s *ChoreStore) DeleteArea(id int64) error {
-	_, err := s.db.Exec(`DELETE FROM chore_areas WHERE id = ?`, id)
+func (s *ChoreStore) DeleteArea(id, householdID int64) error {
+	_, err := s.db.Exec(`DELETE FROM chore_areas WHERE id = ? AND household_id = ?`, id, householdID)
 	if err != nil {
 		return fmt.Errorf("delete area: %w", err)
 	}
 	return nil
 }
 
-func (s *ChoreStore) UpdateAreaSortOrder(ids []int64) error {
+func (s *ChoreStore) UpdateAreaSortOrder(householdID int64, ids []int64) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
@@ -101,7 +105,7 @@ func (s *ChoreStore) UpdateAreaSortOrder(ids []int64) error {
 	defer tx.Rollback()
 
 	for i, id := range ids {
-		if _, err := tx.Exec(`UPDATE chore_areas SET sort_order = ? WHERE id = ?`, i, id); err != nil {
+		if _, err := tx.Exec(`UPDATE chore_areas SET sort_order = ? WHERE id = ? AND household_id = ?`, i, id, householdID); err != nil {
 			return fmt.Errorf("update sort order: %w", err)
 		}
 	}
@@ -114,15 +118,22 @@ func scanChore(scanner interface{ Scan(...any) error }) (*model.Chore, error) {
 	var c model.Chore
 	var areaID sql.NullInt64
 	var assignedTo sql.NullInt64
+	var rotation sql.NullString
+	var dueAt sql.NullTime
+	var requiresApproval int
 
 	err := scanner.Scan(
 		&c.ID, &c.Title, &c.Description, &areaID, &c.Points,
 		&c.RecurrenceRule, &assignedTo, &c.SortOrder,
-		&c.CreatedAt, &c.UpdatedAt,
+		&c.CreatedAt, &c.UpdatedAt, &c.RotationCadence, &dueAt, &c.DueTime, &requiresApproval, &c.MinAge, &rotation,
 	)
 	if err != nil {
 		return nil, err
 	}
+	c.RotationMembers, c.RotationProfiles, err = parseRotation(rotation.String)
+	if err != nil {
+		return nil, err
+	}
 
 	if areaID.Valid {
 		c.AreaID = &areaID.Int64
@@ -130,12 +141,53 @@ func scanChore(scanner interface{ Scan(...any) error }) (*model.Chore, error) {
 	if assignedTo.Valid {
 		c.AssignedTo = &assignedTo.Int64
 	}
+	if dueAt.Valid {
+		c.DueAt = &dueAt.Time
+	}
+	c.RequiresApproval = requiresApproval != 0
 	return &c, nil
 }
 
-const choreCols = `id, title, description, area_id, points, recurrence_rule, assigned_to, sort_order, created_at, updated_at`
+// choreCols ends with the rotation members packed as
+// "position:memberID:birthdate:role,...".
+const choreCols = `id, title, description, area_id, points, recurrence_rule, assigned_to, sort_order, created_at, updated_at, rotation_cadence, due_at, due_time, requires_approval, min_age,
+	(SELECT group_concat(rm.position || ':' || rm.family_member_id || ':' || COALESCE(fm.birthdate, '') || ':' || fm.role)
+	 FROM chore_rotation_members rm JOIN family_members fm ON fm.id = rm.family_member_id WHERE rm.chore_id = chores.id)`
+
+// parseRotation unpacks the rotation members column in rotation order,
+// along with each member's birthdate and role.
+func parseRotation(packed string) ([]int64, map[int64]model.MemberProfile, error) {
+	members := []int64{}
+	profiles := make(map[int64]model.MemberProfile)
+	if packed == "" {
+		return members, profiles, nil
+	}
+	type slot struct{ position, memberID int64 }
+	var slots []slot
+	for _, part := range strings.Split(packed, ",") {
+		fields := strings.SplitN(part, ":", 4)
+		if len(fields) != 4 {
+			return nil, nil, fmt.Errorf("parse rotation %q: want 4 fields", part)
+		}
+		pos, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse rotation %q: %w", part, err)
+		}
+		id, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse rotation %q: %w", part, err)
+		}
+		slots = append(slots, slot{pos, id})
+		profiles[id] = model.MemberProfile{Birthdate: fields[2], Role: model.MemberRole(fields[3])}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].position < slots[j].position })
+	for _, sl := range slots {
+		members = append(members, sl.memberID)
+	}
+	return members, profiles, nil
+}
 
-func (s *ChoreStore) Create(title, description string, areaID *int64, points int, recurrenceRule string, assignedTo *int64) (*model.Chore, error) {
+func (s *ChoreStore) Create(householdID int64, title, description string, areaID *int64, points int, recurrenceRule string, assignedTo *int64) (*model.Chore, error) {
 	var aID sql.NullInt64
 	if areaID != nil {
 		aID = sql.NullInt64{Int64: *areaID, Valid: true}
@@ -146,8 +198,8 @@ func (s *ChoreStore) Create(title, description string, areaID *int64, points int
 	}
 
 	result, err := s.db.Exec(
-		`INSERT INTO chores (title, description, area_id, points, recurrence_rule, assigned_to) VALUES (?, ?, ?, ?, ?, ?)`,
-		title, description, aID, points, recurrenceRule, aTo,
+		`INSERT INTO chores (household_id, title, description, area_id, points, recurrence_rule, assigned_to) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		householdID, title, description, aID, points, recurrenceRule, aTo,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert chore: %w", err)
@@ -156,11 +208,11 @@ func (s *ChoreStore) Create(title, description string, areaID *int64, points int
 	if err != nil {
 		return nil, fmt.Errorf("last insert id: %w", err)
 	}
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
 }
 
-func (s *ChoreStore) GetByID(id int64) (*model.Chore, error) {
-	row := s.db.QueryRow(`SELECT `+choreCols+` FROM chores WHERE id = ?`, id)
+func (s *ChoreStore) GetByID(id, householdID int64) (*model.Chore, error) {
+	row := s.db.QueryRow(`SELECT `+choreCols+` FROM chores WHERE id = ? AND household_id = ?`, id, householdID)
 	c, err := scanChore(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -171,8 +223,8 @@ func (s *ChoreStore) GetByID(id int64) (*model.Chore, error) {
 	return c, nil
 }
 
-func (s *ChoreStore) List() ([]model.Chore, error) {
-	rows, err := s.db.Query(`SELECT ` + choreCols + ` FROM chores ORDER BY sort_order ASC, title ASC`)
+func (s *ChoreStore) List(householdID int64) ([]model.Chore, error) {
+	rows, err := s.db.Query(`SELECT `+choreCols+` FROM chores WHERE household_id = ? ORDER BY sort_order ASC, title ASC`, householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list chores: %w", err)
 	}
@@ -189,10 +241,10 @@ func (s *ChoreStore) List() ([]model.Chore, error) {
 	return chores, rows.Err()
 }
 
-func (s *ChoreStore) ListByAssignee(memberID int64) ([]model.Chore, error) {
+func (s *ChoreStore) ListByAssignee(householdID, memberID int64) ([]model.Chore, error) {
 	rows, err := s.db.Query(
-		`SELECT `+choreCols+` FROM chores WHERE assigned_to = ? ORDER BY sort_order ASC, title ASC`,
-		memberID,
+		`SELECT `+choreCols+` FROM chores WHERE household_id = ? AND assigned_to = ? ORDER BY sort_order ASC, title ASC`,
+		householdID, memberID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("list chores by assignee: %w", err)
@@ -210,10 +262,10 @@ func (s *ChoreStore) ListByAssignee(memberID int64) ([]model.Chore, error) {
 	return chores, rows.Err()
 }
 
-func (s *ChoreStore) ListByArea(areaID int64) ([]model.Chore, error) {
+func (s *ChoreStore) ListByArea(householdID, areaID int64) ([]model.Chore, error) {
 	rows, err := s.db.Query(
-		`SELECT `+choreCols+` FROM chores WHERE area_id = ? ORDER BY sort_order ASC, title ASC`,
-		areaID,
+		`SELECT `+choreCols+` FROM chores WHERE household_id = ? AND area_id = ? ORDER BY sort_order ASC, title ASC`,
+		householdID, areaID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("list chores by area: %w", err)
@@ -231,7 +283,7 @@ func (s *ChoreStore) ListByArea(areaID int64) ([]model.Chore, error) {
 	return chores, rows.Err()
 }
 
-func (s *ChoreStore) Update(id int64, title, description string, areaID *int64, points int, recurrenceRule string, assignedTo *int64) (*model.Chore, error) {
+func (s *ChoreStore) Update(id, householdID int64, title, description string, areaID *int64, points int, recurrenceRule string, assignedTo *int64) (*model.Chore, error) {
 	var aID sql.NullInt64
 	if areaID != nil {
 		aID = sql.NullInt64{Int64: *areaID, Valid: true}
@@ -242,17 +294,89 @@ func (s *ChoreStore) Update(id int64, title, description string, areaID *int64,
 	}
 
 	_, err := s.db.Exec(
-		`UPDATE chores SET title = ?, description = ?, area_id = ?, points = ?, recurrence_rule = ?, assigned_to = ? WHERE id = ?`,
-		title, description, aID, points, recurrenceRule, aTo, id,
+		`UPDATE chores SET title = ?, description = ?, area_id = ?, points = ?, recurrence_rule = ?, assigned_to = ? WHERE id = ? AND household_id = ?`,
+		title, description, aID, points, recurrenceRule, aTo, id, householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("update chore: %w", err)
 	}
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
+}
+
+// SetRotation replaces a chore's rotation. The chore stops rotating if
+// cadence is empty or there are no members.
+func (s *ChoreStore) SetRotation(id, householdID int64, memberIDs []int64, cadence model.RotationCadence) error {
+	if len(memberIDs) == 0 {
+		cadence = model.RotationNone
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE chores SET rotation_cadence = ? WHERE id = ? AND household_id = ?`, cadence, id, householdID)
+	if err != nil {
+		return fmt.Errorf("set rotation cadence: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(`DELETE FROM chore_rotation_members WHERE chore_id = ?`, id); err != nil {
+		return fmt.Errorf("delete rotation members: %w", err)
+	}
+	for i, memberID := range memberIDs {
+		if _, err := tx.Exec(
+			`INSERT INTO chore_rotation_members (chore_id, family_member_id, position) VALUES (?, ?, ?)`,
+			id, memberID, i,
+		); err != nil {
+			return fmt.Errorf("insert rotation member: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// SetDeadline sets when a chore is due by: dueAt for a one-off chore, and
+// dueTime, a "15:04" time of day, for each occurrence of a recurring one.
+func (s *ChoreStore) SetDeadline(id, householdID int64, dueAt *time.Time, dueTime string) error {
+	_, err := s.db.Exec(
+		`UPDATE chores SET due_at = ?, due_time = ? WHERE id = ? AND household_id = ?`,
+		nullableTime(dueAt), dueTime, id, householdID,
+	)
+	if err != nil {
+		return fmt.Errorf("set chore deadline: %w", err)
+	}
+	return nil
 }
 
-func (s *ChoreStore) Delete(id int64) error {
-	_, err := s.db.Exec(`DELETE FROM chores WHERE id = ?`, id)
+// SetRequiresApproval sets whether a chore's completions wait for a parent
+// to approve them before earning points.
+func (s *ChoreStore) SetRequiresApproval(id, householdID int64, requiresApproval bool) error {
+	var v int
+	if requiresApproval {
+		v = 1
+	}
+	_, err := s.db.Exec(`UPDATE chores SET requires_approval = ? WHERE id = ? AND household_id = ?`, v, id, householdID)
+	if err != nil {
+		return fmt.Errorf("set chore requires approval: %w", err)
+	}
+	return nil
+}
+
+// SetMinAge sets the youngest a member can be to take on a chore, or 0 for
+// any age.
+func (s *ChoreStore) SetMinAge(id, householdID int64, minAge int) error {
+	_, err := s.db.Exec(`UPDATE chores SET min_age = ? WHERE id = ? AND household_id = ?`, minAge, id, householdID)
+	if err != nil {
+		return fmt.Errorf("set chore min age: %w", err)
+	}
+	return nil
+}
+
+func (s *ChoreStore) Delete(id, householdID int64) error {
+	_, err := s.db.Exec(`DELETE FROM chores WHERE id = ? AND household_id = ?`, id, householdID)
 	if err != nil {
 		return fmt.Errorf("delete chore: %w", err)
 	}
@@ -264,8 +388,11 @@ func (s *ChoreStore) Delete(id int64) error {
 func scanCompletion(scanner interface{ Scan(...any) error }) (*model.ChoreCompletion, error) {
 	var c model.ChoreCompletion
 	var completedBy sql.NullInt64
+	var reviewedBy sql.NullInt64
+	var reviewedAt sql.NullTime
 
-	err := scanner.Scan(&c.ID, &c.ChoreID, &completedBy, &c.PointsEarned, &c.CompletedAt)
+	err := scanner.Scan(&c.ID, &c.ChoreID, &completedBy, &c.PointsEarned, &c.CompletedAt,
+		&c.Status, &reviewedBy, &reviewedAt, &c.ReviewComment)
 	if err != nil {
 		return nil, err
 	}
@@ -273,45 +400,94 @@ func scanCompletion(scanner interface{ Scan(...any) error }) (*model.ChoreComple
 	if completedBy.Valid {
 		c.CompletedBy = &completedBy.Int64
 	}
+	if reviewedBy.Valid {
+		c.ReviewedBy = &reviewedBy.Int64
+	}
+	if reviewedAt.Valid {
+		c.ReviewedAt = &reviewedAt.Time
+	}
 	return &c, nil
 }
 
-const completionCols = `id, chore_id, completed_by, points_earned, completed_at`
+const completionCols = `id, chore_id, completed_by, points_earned, completed_at, status, reviewed_by, reviewed_at, review_comment`
+
+// householdCompletions restricts a chore_completions query to completions whose
+// parent chore belongs to the given household.
+const householdCompletions = `chore_id IN (SELECT id FROM chores WHERE household_id = ?)`
 
-func (s *ChoreStore) CreateCompletion(choreID int64, completedBy *int64, pointsEarned int) (*model.ChoreCompletion, error) {
+// countedCompletions leaves out rejected completions, which do not count as
+// the chore having been done.
+const countedCompletions = `status != 'rejected'`
+
+// CreateCompletion records a completion for a chore in the given household
+// and credits the points to whoever completed it. It returns nil if the
+// chore does not belong to the household. If the chore requires approval,
+// the completion is pending and earns no points yet.
+func (s *ChoreStore) CreateCompletion(choreID, householdID int64, completedBy *int64, pointsEarned int) (*model.ChoreCompletion, error) {
 	var cBy sql.NullInt64
 	if completedBy != nil {
 		cBy = sql.NullInt64{Int64: *completedBy, Valid: true}
 	}
 
-	result, err := s.db.Exec(
-		`INSERT INTO chore_completions (chore_id, completed_by, points_earned) VALUES (?, ?, ?)`,
-		choreID, cBy, pointsEarned,
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO chore_completions (chore_id, completed_by, points_earned, status)
+		 SELECT id, ?,
+		        CASE WHEN requires_approval THEN 0 ELSE ? END,
+		        CASE WHEN requires_approval THEN 'pending' ELSE 'approved' END
+		 FROM chores WHERE id = ? AND household_id = ?`,
+		cBy, pointsEarned, choreID, householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert completion: %w", err)
 	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return nil, nil
+	}
 	id, err := result.LastInsertId()
 	if err != nil {
 		return nil, fmt.Errorf("last insert id: %w", err)
 	}
+	if err := recordCompletionPoints(tx, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
 
 	row := s.db.QueryRow(`SELECT `+completionCols+` FROM chore_completions WHERE id = ?`, id)
 	return scanCompletion(row)
 }
 
-func (s *ChoreStore) DeleteCompletion(id int64) error {
-	_, err := s.db.Exec(`DELETE FROM chore_completions WHERE id = ?`, id)
+// DeleteCompletion removes a completion, taking back any points it earned
+// with a correction in the ledger.
+func (s *ChoreStore) DeleteCompletion(id, householdID int64) error {
+	tx, err := s.db.Begin()
 	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := reverseCompletionPoints(tx, id, householdID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM chore_completions WHERE id = ? AND `+householdCompletions, id, householdID); err != nil {
 		return fmt.Errorf("delete completion: %w", err)
 	}
-	return nil
+	return tx.Commit()
 }
 
-func (s *ChoreStore) ListCompletionsByChore(choreID int64) ([]model.ChoreCompletion, error) {
+func (s *ChoreStore) ListCompletionsByChore(choreID, householdID int64) ([]model.ChoreCompletion, error) {
 	rows, err := s.db.Query(
-		`SELECT `+completionCols+` FROM chore_completions WHERE chore_id = ? ORDER BY completed_at DESC`,
-		choreID,
+		`SELECT `+completionCols+` FROM chore_completions WHERE chore_id = ? AND `+householdCompletions+` AND `+countedCompletions+` ORDER BY completed_at DESC`,
+		choreID, householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("list completions: %w", err)
@@ -329,10 +505,10 @@ func (s *ChoreStore) ListCompletionsByChore(choreID int64) ([]model.ChoreComplet
 	return completions, rows.Err()
 }
 
-func (s *ChoreStore) ListCompletionsByDateRange(start, end time.Time) ([]model.ChoreCompletion, error) {
+func (s *ChoreStore) ListCompletionsByDateRange(householdID int64, start, end time.Time) ([]model.ChoreCompletion, error) {
 	rows, err := s.db.Query(
-		`SELECT `+completionCols+` FROM chore_completions WHERE completed_at >= ? AND completed_at < ? ORDER BY completed_at DESC`,
-		start.UTC(), end.UTC(),
+		`SELECT `+completionCols+` FROM chore_completions WHERE `+householdCompletions+` AND `+countedCompletions+` AND completed_at >= ? AND completed_at < ? ORDER BY completed_at DESC`,
+		householdID, start.UTC(), end.UTC(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("list completions by range: %w", err)
@@ -350,10 +526,96 @@ func (s *ChoreStore) ListCompletionsByDateRange(start, end time.Time) ([]model.C
 	return completions, rows.Err()
 }
 
-func (s *ChoreStore) LastCompletionForChore(choreID int64) (*model.ChoreCompletion, error) {
+// CountCompletionsBefore returns how many times a chore was completed before
+// t, leaving out rejected completions.
+func (s *ChoreStore) CountCompletionsBefore(choreID, householdID int64, t time.Time) (int, error) {
+	var n int
+	err := s.db.QueryRow(
+		`SELECT COUNT(*) FROM chore_completions WHERE chore_id = ? AND `+householdCompletions+` AND `+countedCompletions+` AND completed_at < ?`,
+		choreID, householdID, t.UTC(),
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count completions: %w", err)
+	}
+	return n, nil
+}
+
+// GetCompletion returns a completion of one of the household's chores, or
+// nil if there is none.
+func (s *ChoreStore) GetCompletion(id, householdID int64) (*model.ChoreCompletion, error) {
+	row := s.db.QueryRow(`SELECT `+completionCols+` FROM chore_completions WHERE id = ? AND `+householdCompletions, id, householdID)
+	c, err := scanCompletion(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get completion: %w", err)
+	}
+	return c, nil
+}
+
+// ListPendingCompletions returns the household's completions that are
+// waiting for approval, oldest first.
+func (s *ChoreStore) ListPendingCompletions(householdID int64) ([]model.ChoreCompletion, error) {
+	rows, err := s.db.Query(
+		`SELECT `+completionCols+` FROM chore_completions WHERE `+householdCompletions+` AND status = 'pending' ORDER BY completed_at ASC`,
+		householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list pending completions: %w", err)
+	}
+	defer rows.Close()
+
+	var completions []model.ChoreCompletion
+	for rows.Next() {
+		c, err := scanCompletion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan completion: %w", err)
+		}
+		completions = append(completions, *c)
+	}
+	return completions, rows.Err()
+}
+
+// ReviewCompletion approves or rejects a pending completion. An approved
+// completion earns pointsEarned; a rejected one earns nothing. It returns
+// nil if the completion is not pending, so a completion can only be
+// reviewed once.
+func (s *ChoreStore) ReviewCompletion(id, householdID int64, status model.CompletionStatus, reviewedBy int64, pointsEarned int, comment string) (*model.ChoreCompletion, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`UPDATE chore_completions SET
+		     status = ?, reviewed_by = ?, reviewed_at = ?, review_comment = ?,
+		     points_earned = CASE WHEN ? = 'approved' THEN ? ELSE 0 END
+		 WHERE id = ? AND status = 'pending' AND `+householdCompletions,
+		status, reviewedBy, time.Now().UTC(), comment, status, pointsEarned, id, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("review completion: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return nil, nil
+	}
+	if err := recordCompletionPoints(tx, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetCompletion(id, householdID)
+}
+
+func (s *ChoreStore) LastCompletionForChore(choreID, householdID int64) (*model.ChoreCompletion, error) {
 	row := s.db.QueryRow(
-		`SELECT `+completionCols+` FROM chore_completions WHERE chore_id = ? ORDER BY completed_at DESC LIMIT 1`,
-		choreID,
+		`SELECT `+completionCols+` FROM chore_completions WHERE chore_id = ? AND `+householdCompletions+` AND `+countedCompletions+` ORDER BY completed_at DESC LIMIT 1`,
+		choreID, householdID,
 	)
 	c, err := scanCompletion(row)
 	if err == sql.ErrNoRows {
@@ -364,3 +626,457 @@ func (s *ChoreStore) LastCompletionForChore(choreID int64) (*model.ChoreCompleti
 	}
 	return c, nil
 }
+
+// LastCompletionTimes returns when each of the household's chores was
+// last completed, by chore ID. Chores never completed are left out.
+func (s *ChoreStore) LastCompletionTimes(householdID int64) (map[int64]time.Time, error) {
+	rows, err := s.db.Query(
+		`SELECT cc.chore_id, cc.completed_at FROM chore_completions cc
+		 WHERE cc.`+householdCompletions+` AND cc.`+countedCompletions+`
+		 AND cc.id = (SELECT id FROM chore_completions WHERE chore_id = cc.chore_id AND `+countedCompletions+` ORDER BY completed_at DESC LIMIT 1)`,
+		householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("last completions: %w", err)
+	}
+	defer rows.Close()
+
+	last := make(map[int64]time.Time)
+	for rows.Next() {
+		var choreID int64
+		var at time.Time
+		if err := rows.Scan(&choreID, &at); err != nil {
+			return nil, fmt.Errorf("scan last completion: %w", err)
+		}
+		last[choreID] = at
+	}
+	return last, rows.Err()
+}
+
+// --- Rotation event methods ---
+
+const rotationEventCols = `id, chore_id, kind, turn, due_date, from_member_id, to_member_id, created_at`
+
+func scanRotationEvent(scanner interface{ Scan(...any) error }) (*model.ChoreRotationEvent, error) {
+	var e model.ChoreRotationEvent
+	var from, to sql.NullInt64
+	err := scanner.Scan(&e.ID, &e.ChoreID, &e.Kind, &e.Turn, &e.DueDate, &from, &to, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if from.Valid {
+		e.FromMemberID = &from.Int64
+	}
+	if to.Valid {
+		e.ToMemberID = &to.Int64
+	}
+	return &e, nil
+}
+
+// CreateRotationEvent records a skipped or swapped turn. It returns nil if
+// the chore does not belong to the household.
+func (s *ChoreStore) CreateRotationEvent(choreID, householdID int64, kind model.RotationEventKind, turn int, dueDate string, from, to *int64) (*model.ChoreRotationEvent, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO chore_rotation_events (chore_id, kind, turn, due_date, from_member_id, to_member_id)
+		 SELECT id, ?, ?, ?, ?, ? FROM chores WHERE id = ? AND household_id = ?`,
+		kind, turn, dueDate, nullableID(from), nullableID(to), choreID, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert rotation event: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return nil, nil
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("last insert id: %w", err)
+	}
+
+	row := s.db.QueryRow(`SELECT `+rotationEventCols+` FROM chore_rotation_events WHERE id = ?`, id)
+	return scanRotationEvent(row)
+}
+
+// ListRotationEvents returns a chore's skipped and swapped turns, oldest first.
+func (s *ChoreStore) ListRotationEvents(choreID, householdID int64) ([]model.ChoreRotationEvent, error) {
+	rows, err := s.db.Query(
+		`SELECT `+rotationEventCols+` FROM chore_rotation_events WHERE chore_id = ? AND `+householdCompletions+` ORDER BY id ASC`,
+		choreID, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list rotation events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []model.ChoreRotationEvent
+	for rows.Next() {
+		e, err := scanRotationEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan rotation event: %w", err)
+		}
+		events = append(events, *e)
+	}
+	return events, rows.Err()
+}
+
+// --- Occurrence methods ---
+
+const occurrenceCols = `id, chore_id, due_date, status, member_id, completed_by, note, created_at, updated_at`
+
+func scanOccurrence(scanner interface{ Scan(...any) error }) (*model.ChoreOccurrence, error) {
+	var o model.ChoreOccurrence
+	var memberID, completedBy sql.NullInt64
+	err := scanner.Scan(&o.ID, &o.ChoreID, &o.DueDate, &o.Status, &memberID, &completedBy, &o.Note, &o.CreatedAt, &o.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if memberID.Valid {
+		o.MemberID = &memberID.Int64
+	}
+	if completedBy.Valid {
+		o.CompletedBy = &completedBy.Int64
+	}
+	return &o, nil
+}
+
+// ListOccurrences returns a chore's recorded occurrences by due date.
+func (s *ChoreStore) ListOccurrences(choreID, householdID int64) ([]model.ChoreOccurrence, error) {
+	rows, err := s.db.Query(
+		`SELECT `+occurrenceCols+` FROM chore_occurrences WHERE chore_id = ? AND `+householdCompletions+` ORDER BY due_date ASC`,
+		choreID, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list occurrences: %w", err)
+	}
+	defer rows.Close()
+
+	var occurrences []model.ChoreOccurrence
+	for rows.Next() {
+		o, err := scanOccurrence(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan occurrence: %w", err)
+		}
+		occurrences = append(occurrences, *o)
+	}
+	return occurrences, rows.Err()
+}
+
+// SetOccurrence records the outcome of a chore's occurrence on dueDate,
+// replacing any earlier record. It returns nil if the chore does not belong
+// to the household.
+func (s *ChoreStore) SetOccurrence(choreID, householdID int64, dueDate string, status model.OccurrenceStatus, memberID, completedBy *int64, note string) (*model.ChoreOccurrence, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO chore_occurrences (chore_id, due_date, status, member_id, completed_by, note)
+		 SELECT id, ?, ?, ?, ?, ? FROM chores WHERE id = ? AND household_id = ?
+		 ON CONFLICT (chore_id, due_date) DO UPDATE SET
+		     status = excluded.status, member_id = excluded.member_id, completed_by = excluded.completed_by,
+		     note = excluded.note, updated_at = datetime('now')`,
+		dueDate, status, nullableID(memberID), nullableID(completedBy), note, choreID, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("set occurrence: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return nil, nil
+	}
+
+	row := s.db.QueryRow(`SELECT `+occurrenceCols+` FROM chore_occurrences WHERE chore_id = ? AND due_date = ?`, choreID, dueDate)
+	return scanOccurrence(row)
+}
+
+// CloseOccurrence records the outcome of an occurrence unless one is
+// already recorded. It reports whether a record was added.
+func (s *ChoreStore) CloseOccurrence(choreID, householdID int64, dueDate string, status model.OccurrenceStatus, memberID, completedBy *int64) (bool, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO chore_occurrences (chore_id, due_date, status, member_id, completed_by)
+		 SELECT id, ?, ?, ?, ? FROM chores WHERE id = ? AND household_id = ?
+		 ON CONFLICT (chore_id, due_date) DO NOTHING`,
+		dueDate, status, nullableID(memberID), nullableID(completedBy), choreID, householdID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("close occurrence: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// DeleteOccurrence clears the recorded outcome of a chore's occurrence.
+func (s *ChoreStore) DeleteOccurrence(choreID, householdID int64, dueDate string) error {
+	_, err := s.db.Exec(
+		`DELETE FROM chore_occurrences WHERE chore_id = ? AND due_date = ? AND `+householdCompletions,
+		choreID, dueDate, householdID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete occurrence: %w", err)
+	}
+	return nil
+}
+
+// ListHouseholdIDs returns the households that have recurring chores.
+func (s *ChoreStore) ListHouseholdIDs() ([]int64, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT household_id FROM chores WHERE recurrence_rule != ''`)
+	if err != nil {
+		return nil, fmt.Errorf("list chore household ids: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan household id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// --- Template methods ---
+
+// shareCodeAlphabet leaves out letters and digits easily mistaken for one
+// another, since share codes are read out and typed in by hand.
+const shareCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// normalizeShareCode uppercases a share code and drops the spaces and
+// dashes people type into it.
+func normalizeShareCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
+
+const choreTemplateCols = `id, name, description, share_code, created_at`
+
+// getTemplate loads a template and its chores. It returns nil if no
+// template matches.
+func (s *ChoreStore) getTemplate(where string, args ...any) (*model.ChoreTemplate, error) {
+	var t model.ChoreTemplate
+	err := s.db.QueryRow(`SELECT `+choreTemplateCols+` FROM chore_templates WHERE `+where, args...).
+		Scan(&t.ID, &t.Name, &t.Description, &t.ShareCode, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get chore template: %w", err)
+	}
+	if t.Chores, err = s.listTemplateChores(t.ID); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *ChoreStore) listTemplateChores(templateID int64) ([]model.TemplateChore, error) {
+	rows, err := s.db.Query(
+		`SELECT title, description, area, points, recurrence_rule, due_time, requires_approval, min_age
+		 FROM chore_template_chores WHERE template_id = ? ORDER BY sort_order, id`,
+		templateID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list template chores: %w", err)
+	}
+	defer rows.Close()
+
+	chores := []model.TemplateChore{}
+	for rows.Next() {
+		var c model.TemplateChore
+		var requiresApproval int
+		if err := rows.Scan(&c.Title, &c.Description, &c.Area, &c.Points, &c.RecurrenceRule, &c.DueTime, &requiresApproval, &c.MinAge); err != nil {
+			return nil, fmt.Errorf("scan template chore: %w", err)
+		}
+		c.RequiresApproval = requiresApproval != 0
+		chores = append(chores, c)
+	}
+	return chores, rows.Err()
+}
+
+// SaveTemplate saves the household's recurring chores as a template, with
+// a new share code. One-off chores are left out.
+func (s *ChoreStore) SaveTemplate(householdID int64, name, description string) (*model.ChoreTemplate, error) {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("generate share code: %w", err)
+	}
+	code := make([]byte, len(raw))
+	for i, c := range raw {
+		code[i] = shareCodeAlphabet[int(c)%len(shareCodeAlphabet)]
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO chore_templates (household_id, name, description, share_code) VALUES (?, ?, ?, ?)`,
+		householdID, name, description, string(code),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert chore template: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("last insert id: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO chore_template_chores (template_id, title, description, area, points, recurrence_rule, due_time, requires_approval, min_age, sort_order)
+		 SELECT ?, c.title, c.description, COALESCE(a.name, ''), c.points, c.recurrence_rule, c.due_time, c.requires_approval, c.min_age, c.sort_order
+		 FROM chores c LEFT JOIN chore_areas a ON a.id = c.area_id
+		 WHERE c.household_id = ? AND c.recurrence_rule != ''
+		 ORDER BY c.sort_order, c.title`,
+		id, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("copy chores to template: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetTemplate(id, householdID)
+}
+
+// GetTemplate returns one of the household's saved templates, or nil if
+// it does not exist.
+func (s *ChoreStore) GetTemplate(id, householdID int64) (*model.ChoreTemplate, error) {
+	return s.getTemplate(`id = ? AND household_id = ?`, id, householdID)
+}
+
+// GetTemplateByShareCode returns the template with the given share code,
+// whichever household saved it, or nil if there is none.
+func (s *ChoreStore) GetTemplateByShareCode(code string) (*model.ChoreTemplate, error) {
+	code = normalizeShareCode(code)
+	if code == "" {
+		return nil, nil
+	}
+	return s.getTemplate(`share_code = ?`, code)
+}
+
+// ListTemplates returns the household's saved templates and their chores,
+// newest first.
+func (s *ChoreStore) ListTemplates(householdID int64) ([]model.ChoreTemplate, error) {
+	rows, err := s.db.Query(
+		`SELECT `+choreTemplateCols+` FROM chore_templates WHERE household_id = ? ORDER BY created_at DESC, id DESC`,
+		householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list chore templates: %w", err)
+	}
+	var templates []model.ChoreTemplate
+	for rows.Next() {
+		var t model.ChoreTemplate
+		if err := rows.Scan(&t.ID, &t.Name, &t.Description, &t.ShareCode, &t.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan chore template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range templates {
+		if templates[i].Chores, err = s.listTemplateChores(templates[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+// DeleteTemplate deletes one of the household's saved templates.
+func (s *ChoreStore) DeleteTemplate(id, householdID int64) error {
+	_, err := s.db.Exec(`DELETE FROM chore_templates WHERE id = ? AND household_id = ?`, id, householdID)
+	if err != nil {
+		return fmt.Errorf("delete chore template: %w", err)
+	}
+	return nil
+}
+
+// ImportTemplate creates the household's own copy of each template chore.
+// It returns how many chores it created.
+func (s *ChoreStore) ImportTemplate(householdID int64, chores []model.TemplateChore) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	n, err := importTemplateChores(tx, householdID, chores)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return n, nil
+}
+
+// importTemplateChores creates template chores in a household. Chores go
+// in the household's area of the same name, which is added if it has
+// none. A chore the household already has one of by that title is left
+// out, so importing a template twice does not double up.
+func importTemplateChores(tx *sql.Tx, householdID int64, chores []model.TemplateChore) (int, error) {
+	created := 0
+	for _, c := range chores {
+		var exists int
+		err := tx.QueryRow(
+			`SELECT COUNT(*) FROM chores WHERE household_id = ? AND title = ? COLLATE NOCASE`,
+			householdID, c.Title,
+		).Scan(&exists)
+		if err != nil {
+			return created, fmt.Errorf("check chore %q: %w", c.Title, err)
+		}
+		if exists > 0 {
+			continue
+		}
+
+		var areaID sql.NullInt64
+		if c.Area != "" {
+			err := tx.QueryRow(
+				`SELECT id FROM chore_areas WHERE household_id = ? AND name = ? COLLATE NOCASE`,
+				householdID, c.Area,
+			).Scan(&areaID)
+			if err == sql.ErrNoRows {
+				var result sql.Result
+				result, err = tx.Exec(
+					`INSERT INTO chore_areas (household_id, name, sort_order)
+					 SELECT ?, ?, COALESCE(MAX(sort_order), 0) + 1 FROM chore_areas WHERE household_id = ?`,
+					householdID, c.Area, householdID,
+				)
+				if err == nil {
+					areaID.Int64, err = result.LastInsertId()
+					areaID.Valid = true
+				}
+			}
+			if err != nil {
+				return created, fmt.Errorf("find area %q: %w", c.Area, err)
+			}
+		}
+
+		var requiresApproval int
+		if c.RequiresApproval {
+			requiresApproval = 1
+		}
+		_, err = tx.Exec(
+			`INSERT INTO chores (household_id, title, description, area_id, points, recurrence_rule, due_time, requires_approval, min_age, sort_order)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			householdID, c.Title, c.Description, areaID, c.Points, c.RecurrenceRule, c.DueTime, requiresApproval, c.MinAge, created,
+		)
+		if err != nil {
+			return created, fmt.Errorf("insert chore %q: %w", c.Title, err)
+		}
+		created++
+	}
+	return created, nil
+}
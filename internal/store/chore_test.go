@@ -1,10 +1,12 @@
 package store
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/dukerupert/gamwich/internal/database"
+	"github.com/dukerupert/gamwich/internal/model"
 )
 
 func setupChoreTestDB(t *testing.T) (*ChoreStore, *FamilyMemberStore) {
@@ -23,7 +25,7 @@ func setupChoreTestDB(t *testing.T) (*ChoreStore, *FamilyMemberStore) {
 func TestAreaSeedData(t *testing.T) {
 	cs, _ := setupChoreTestDB(t)
 
-	areas, err := cs.ListAreas()
+	areas, err := cs.ListAreas(testHouseholdID)
 	if err != nil {
 		t.Fatalf("list areas: %v", err)
 	}
@@ -43,7 +45,7 @@ func TestAreaCRUD(t *testing.T) {
 	cs, _ := setupChoreTestDB(t)
 
 	// Create
-	area, err := cs.CreateArea("Garage", 6)
+	area, err := cs.CreateArea(testHouseholdID, "Garage", 6)
 	if err != nil {
 		t.Fatalf("create area: %v", err)
 	}
@@ -55,7 +57,7 @@ func TestAreaCRUD(t *testing.T) {
 	}
 
 	// Get
-	got, err := cs.GetAreaByID(area.ID)
+	got, err := cs.GetAreaByID(area.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get area: %v", err)
 	}
@@ -64,7 +66,7 @@ func TestAreaCRUD(t *testing.T) {
 	}
 
 	// Update
-	updated, err := cs.UpdateArea(area.ID, "Garage/Workshop", 7)
+	updated, err := cs.UpdateArea(area.ID, testHouseholdID, "Garage/Workshop", 7)
 	if err != nil {
 		t.Fatalf("update area: %v", err)
 	}
@@ -73,10 +75,10 @@ func TestAreaCRUD(t *testing.T) {
 	}
 
 	// Delete
-	if err := cs.DeleteArea(area.ID); err != nil {
+	if err := cs.DeleteArea(area.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete area: %v", err)
 	}
-	got, err = cs.GetAreaByID(area.ID)
+	got, err = cs.GetAreaByID(area.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get deleted area: %v", err)
 	}
@@ -88,7 +90,7 @@ func TestAreaCRUD(t *testing.T) {
 func TestAreaGetByIDNotFound(t *testing.T) {
 	cs, _ := setupChoreTestDB(t)
 
-	got, err := cs.GetAreaByID(9999)
+	got, err := cs.GetAreaByID(9999, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get area: %v", err)
 	}
@@ -100,11 +102,11 @@ func TestAreaGetByIDNotFound(t *testing.T) {
 func TestChoreCRUD(t *testing.T) {
 	cs, _ := setupChoreTestDB(t)
 
-	areas, _ := cs.ListAreas()
+	areas, _ := cs.ListAreas(testHouseholdID)
 	kitchenID := areas[0].ID
 
 	// Create
-	chore, err := cs.Create("Wash dishes", "Clean all dishes", &kitchenID, 5, "FREQ=DAILY", nil)
+	chore, err := cs.Create(testHouseholdID, "Wash dishes", "Clean all dishes", &kitchenID, 5, "FREQ=DAILY", nil)
 	if err != nil {
 		t.Fatalf("create chore: %v", err)
 	}
@@ -125,7 +127,7 @@ func TestChoreCRUD(t *testing.T) {
 	}
 
 	// GetByID
-	got, err := cs.GetByID(chore.ID)
+	got, err := cs.GetByID(chore.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get chore: %v", err)
 	}
@@ -134,7 +136,7 @@ func TestChoreCRUD(t *testing.T) {
 	}
 
 	// Update
-	updated, err := cs.Update(chore.ID, "Wash all dishes", "Pots and pans too", &kitchenID, 10, "FREQ=DAILY", nil)
+	updated, err := cs.Update(chore.ID, testHouseholdID, "Wash all dishes", "Pots and pans too", &kitchenID, 10, "FREQ=DAILY", nil)
 	if err != nil {
 		t.Fatalf("update chore: %v", err)
 	}
@@ -146,7 +148,7 @@ func TestChoreCRUD(t *testing.T) {
 	}
 
 	// List
-	chores, err := cs.List()
+	chores, err := cs.List(testHouseholdID)
 	if err != nil {
 		t.Fatalf("list chores: %v", err)
 	}
@@ -155,10 +157,10 @@ func TestChoreCRUD(t *testing.T) {
 	}
 
 	// Delete
-	if err := cs.Delete(chore.ID); err != nil {
+	if err := cs.Delete(chore.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete chore: %v", err)
 	}
-	got, err = cs.GetByID(chore.ID)
+	got, err = cs.GetByID(chore.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get deleted chore: %v", err)
 	}
@@ -170,7 +172,7 @@ func TestChoreCRUD(t *testing.T) {
 func TestChoreGetByIDNotFound(t *testing.T) {
 	cs, _ := setupChoreTestDB(t)
 
-	got, err := cs.GetByID(9999)
+	got, err := cs.GetByID(9999, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get chore: %v", err)
 	}
@@ -182,15 +184,15 @@ func TestChoreGetByIDNotFound(t *testing.T) {
 func TestChoreListByAssignee(t *testing.T) {
 	cs, ms := setupChoreTestDB(t)
 
-	member, err := ms.Create("Alice", "#FF0000", "A")
+	member, err := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
 	if err != nil {
 		t.Fatalf("create member: %v", err)
 	}
 
-	cs.Create("Chore A", "", nil, 0, "", &member.ID)
-	cs.Create("Chore B", "", nil, 0, "", nil)
+	cs.Create(testHouseholdID, "Chore A", "", nil, 0, "", &member.ID)
+	cs.Create(testHouseholdID, "Chore B", "", nil, 0, "", nil)
 
-	chores, err := cs.ListByAssignee(member.ID)
+	chores, err := cs.ListByAssignee(testHouseholdID, member.ID)
 	if err != nil {
 		t.Fatalf("list by assignee: %v", err)
 	}
@@ -205,14 +207,14 @@ func TestChoreListByAssignee(t *testing.T) {
 func TestChoreListByArea(t *testing.T) {
 	cs, _ := setupChoreTestDB(t)
 
-	areas, _ := cs.ListAreas()
+	areas, _ := cs.ListAreas(testHouseholdID)
 	kitchenID := areas[0].ID
 	bathroomID := areas[1].ID
 
-	cs.Create("Kitchen chore", "", &kitchenID, 0, "", nil)
-	cs.Create("Bathroom chore", "", &bathroomID, 0, "", nil)
+	cs.Create(testHouseholdID, "Kitchen chore", "", &kitchenID, 0, "", nil)
+	cs.Create(testHouseholdID, "Bathroom chore", "", &bathroomID, 0, "", nil)
 
-	chores, err := cs.ListByArea(kitchenID)
+	chores, err := cs.ListByArea(testHouseholdID, kitchenID)
 	if err != nil {
 		t.Fatalf("list by area: %v", err)
 	}
@@ -227,25 +229,25 @@ func TestChoreListByArea(t *testing.T) {
 func TestDeleteChoreCascadesCompletions(t *testing.T) {
 	cs, ms := setupChoreTestDB(t)
 
-	member, _ := ms.Create("Bob", "#0000FF", "B")
-	chore, _ := cs.Create("Sweep floor", "", nil, 5, "", nil)
+	member, _ := ms.Create(testHouseholdID, "Bob", "#0000FF", "B")
+	chore, _ := cs.Create(testHouseholdID, "Sweep floor", "", nil, 5, "", nil)
 
-	_, err := cs.CreateCompletion(chore.ID, &member.ID, 0)
+	_, err := cs.CreateCompletion(chore.ID, testHouseholdID, &member.ID, 0)
 	if err != nil {
 		t.Fatalf("create completion: %v", err)
 	}
 
-	completions, _ := cs.ListCompletionsByChore(chore.ID)
+	completions, _ := cs.ListCompletionsByChore(chore.ID, testHouseholdID)
 	if len(completions) != 1 {
 		t.Fatalf("expected 1 completion, got %d", len(completions))
 	}
 
 	// Delete chore should cascade
-	if err := cs.Delete(chore.ID); err != nil {
+	if err := cs.Delete(chore.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete chore: %v", err)
 	}
 
-	completions, _ = cs.ListCompletionsByChore(chore.ID)
+	completions, _ = cs.ListCompletionsByChore(chore.ID, testHouseholdID)
 	if len(completions) != 0 {
 		t.Errorf("expected 0 completions after cascade, got %d", len(completions))
 	}
@@ -254,18 +256,18 @@ func TestDeleteChoreCascadesCompletions(t *testing.T) {
 func TestDeleteMemberSetsNullOnChore(t *testing.T) {
 	cs, ms := setupChoreTestDB(t)
 
-	member, _ := ms.Create("Charlie", "#00FF00", "C")
-	chore, _ := cs.Create("Mow lawn", "", nil, 10, "", &member.ID)
+	member, _ := ms.Create(testHouseholdID, "Charlie", "#00FF00", "C")
+	chore, _ := cs.Create(testHouseholdID, "Mow lawn", "", nil, 10, "", &member.ID)
 
 	if chore.AssignedTo == nil || *chore.AssignedTo != member.ID {
 		t.Fatalf("assigned_to = %v, want %d", chore.AssignedTo, member.ID)
 	}
 
-	if err := ms.Delete(member.ID); err != nil {
+	if err := ms.Delete(member.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete member: %v", err)
 	}
 
-	got, err := cs.GetByID(chore.ID)
+	got, err := cs.GetByID(chore.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get chore: %v", err)
 	}
@@ -277,19 +279,19 @@ func TestDeleteMemberSetsNullOnChore(t *testing.T) {
 func TestDeleteMemberSetsNullOnCompletion(t *testing.T) {
 	cs, ms := setupChoreTestDB(t)
 
-	member, _ := ms.Create("Diana", "#FFFF00", "D")
-	chore, _ := cs.Create("Vacuum", "", nil, 5, "", nil)
-	comp, _ := cs.CreateCompletion(chore.ID, &member.ID, 0)
+	member, _ := ms.Create(testHouseholdID, "Diana", "#FFFF00", "D")
+	chore, _ := cs.Create(testHouseholdID, "Vacuum", "", nil, 5, "", nil)
+	comp, _ := cs.CreateCompletion(chore.ID, testHouseholdID, &member.ID, 0)
 
 	if comp.CompletedBy == nil || *comp.CompletedBy != member.ID {
 		t.Fatalf("completed_by = %v, want %d", comp.CompletedBy, member.ID)
 	}
 
-	if err := ms.Delete(member.ID); err != nil {
+	if err := ms.Delete(member.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete member: %v", err)
 	}
 
-	completions, _ := cs.ListCompletionsByChore(chore.ID)
+	completions, _ := cs.ListCompletionsByChore(chore.ID, testHouseholdID)
 	if len(completions) != 1 {
 		t.Fatalf("expected 1 completion, got %d", len(completions))
 	}
@@ -301,18 +303,18 @@ func TestDeleteMemberSetsNullOnCompletion(t *testing.T) {
 func TestDeleteAreaSetsNullOnChore(t *testing.T) {
 	cs, _ := setupChoreTestDB(t)
 
-	area, _ := cs.CreateArea("Test Area", 10)
-	chore, _ := cs.Create("Test chore", "", &area.ID, 0, "", nil)
+	area, _ := cs.CreateArea(testHouseholdID, "Test Area", 10)
+	chore, _ := cs.Create(testHouseholdID, "Test chore", "", &area.ID, 0, "", nil)
 
 	if chore.AreaID == nil || *chore.AreaID != area.ID {
 		t.Fatalf("area_id = %v, want %d", chore.AreaID, area.ID)
 	}
 
-	if err := cs.DeleteArea(area.ID); err != nil {
+	if err := cs.DeleteArea(area.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete area: %v", err)
 	}
 
-	got, err := cs.GetByID(chore.ID)
+	got, err := cs.GetByID(chore.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get chore: %v", err)
 	}
@@ -324,11 +326,11 @@ func TestDeleteAreaSetsNullOnChore(t *testing.T) {
 func TestCompletionCRUD(t *testing.T) {
 	cs, ms := setupChoreTestDB(t)
 
-	member, _ := ms.Create("Eve", "#FF00FF", "E")
-	chore, _ := cs.Create("Take out trash", "", nil, 3, "", nil)
+	member, _ := ms.Create(testHouseholdID, "Eve", "#FF00FF", "E")
+	chore, _ := cs.Create(testHouseholdID, "Take out trash", "", nil, 3, "", nil)
 
 	// Create completion
-	comp, err := cs.CreateCompletion(chore.ID, &member.ID, 0)
+	comp, err := cs.CreateCompletion(chore.ID, testHouseholdID, &member.ID, 0)
 	if err != nil {
 		t.Fatalf("create completion: %v", err)
 	}
@@ -340,7 +342,7 @@ func TestCompletionCRUD(t *testing.T) {
 	}
 
 	// List by chore
-	completions, err := cs.ListCompletionsByChore(chore.ID)
+	completions, err := cs.ListCompletionsByChore(chore.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("list completions: %v", err)
 	}
@@ -349,7 +351,7 @@ func TestCompletionCRUD(t *testing.T) {
 	}
 
 	// Last completion
-	last, err := cs.LastCompletionForChore(chore.ID)
+	last, err := cs.LastCompletionForChore(chore.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("last completion: %v", err)
 	}
@@ -358,10 +360,10 @@ func TestCompletionCRUD(t *testing.T) {
 	}
 
 	// Delete completion
-	if err := cs.DeleteCompletion(comp.ID); err != nil {
+	if err := cs.DeleteCompletion(comp.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete completion: %v", err)
 	}
-	completions, _ = cs.ListCompletionsByChore(chore.ID)
+	completions, _ = cs.ListCompletionsByChore(chore.ID, testHouseholdID)
 	if len(completions) != 0 {
 		t.Errorf("expected 0 completions after delete, got %d", len(completions))
 	}
@@ -370,9 +372,9 @@ func TestCompletionCRUD(t *testing.T) {
 func TestLastCompletionNotFound(t *testing.T) {
 	cs, _ := setupChoreTestDB(t)
 
-	chore, _ := cs.Create("No completions", "", nil, 0, "", nil)
+	chore, _ := cs.Create(testHouseholdID, "No completions", "", nil, 0, "", nil)
 
-	last, err := cs.LastCompletionForChore(chore.ID)
+	last, err := cs.LastCompletionForChore(chore.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("last completion: %v", err)
 	}
@@ -381,12 +383,61 @@ func TestLastCompletionNotFound(t *testing.T) {
 	}
 }
 
+func TestLastCompletionTimes(t *testing.T) {
+	cs, ms := setupChoreTestDB(t)
+	otherID := createTestHousehold(t, cs.db, "Other")
+
+	member, _ := ms.Create(testHouseholdID, "Eve", "#FF00FF", "E")
+	dishes, _ := cs.Create(testHouseholdID, "Dishes", "", nil, 1, "FREQ=DAILY", nil)
+	trash, _ := cs.Create(testHouseholdID, "Trash", "", nil, 1, "FREQ=DAILY", nil)
+	cs.Create(testHouseholdID, "Never done", "", nil, 1, "FREQ=DAILY", nil)
+
+	complete := func(choreID int64, at time.Time) *model.ChoreCompletion {
+		t.Helper()
+		comp, err := cs.CreateCompletion(choreID, testHouseholdID, &member.ID, 1)
+		if err != nil {
+			t.Fatalf("create completion: %v", err)
+		}
+		if _, err := cs.db.Exec(`UPDATE chore_completions SET completed_at = ? WHERE id = ?`, at, comp.ID); err != nil {
+			t.Fatalf("set completed_at: %v", err)
+		}
+		return comp
+	}
+	complete(dishes.ID, time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC))
+	complete(dishes.ID, time.Date(2026, 2, 3, 8, 0, 0, 0, time.UTC))
+	complete(trash.ID, time.Date(2026, 2, 2, 8, 0, 0, 0, time.UTC))
+	// A rejected completion does not count.
+	rejected := complete(trash.ID, time.Date(2026, 2, 4, 8, 0, 0, 0, time.UTC))
+	cs.db.Exec(`UPDATE chore_completions SET status = 'rejected' WHERE id = ?`, rejected.ID)
+
+	last, err := cs.LastCompletionTimes(testHouseholdID)
+	if err != nil {
+		t.Fatalf("last completion times: %v", err)
+	}
+	want := map[int64]time.Time{
+		dishes.ID: time.Date(2026, 2, 3, 8, 0, 0, 0, time.UTC),
+		trash.ID:  time.Date(2026, 2, 2, 8, 0, 0, 0, time.UTC),
+	}
+	if len(last) != len(want) {
+		t.Fatalf("got %d chores, want %d: %v", len(last), len(want), last)
+	}
+	for id, at := range want {
+		if !last[id].Equal(at) {
+			t.Errorf("chore %d last completed %v, want %v", id, last[id], at)
+		}
+	}
+
+	if other, _ := cs.LastCompletionTimes(otherID); len(other) != 0 {
+		t.Errorf("other household sees %d completions", len(other))
+	}
+}
+
 func TestCompletionNilCompletedBy(t *testing.T) {
 	cs, _ := setupChoreTestDB(t)
 
-	chore, _ := cs.Create("Anonymous chore", "", nil, 0, "", nil)
+	chore, _ := cs.Create(testHouseholdID, "Anonymous chore", "", nil, 0, "", nil)
 
-	comp, err := cs.CreateCompletion(chore.ID, nil, 0)
+	comp, err := cs.CreateCompletion(chore.ID, testHouseholdID, nil, 0)
 	if err != nil {
 		t.Fatalf("create completion: %v", err)
 	}
@@ -398,10 +449,10 @@ func TestCompletionNilCompletedBy(t *testing.T) {
 func TestListCompletionsByDateRange(t *testing.T) {
 	cs, _ := setupChoreTestDB(t)
 
-	chore, _ := cs.Create("Date range chore", "", nil, 0, "", nil)
+	chore, _ := cs.Create(testHouseholdID, "Date range chore", "", nil, 0, "", nil)
 
 	// Create a completion (uses default datetime('now'))
-	_, err := cs.CreateCompletion(chore.ID, nil, 0)
+	_, err := cs.CreateCompletion(chore.ID, testHouseholdID, nil, 0)
 	if err != nil {
 		t.Fatalf("create completion: %v", err)
 	}
@@ -411,7 +462,7 @@ func TestListCompletionsByDateRange(t *testing.T) {
 	start := now.Add(-1 * time.Hour)
 	end := now.Add(1 * time.Hour)
 
-	completions, err := cs.ListCompletionsByDateRange(start, end)
+	completions, err := cs.ListCompletionsByDateRange(testHouseholdID, start, end)
 	if err != nil {
 		t.Fatalf("list by range: %v", err)
 	}
@@ -421,7 +472,7 @@ func TestListCompletionsByDateRange(t *testing.T) {
 
 	// Query a range that excludes now
 	farFuture := now.Add(24 * time.Hour)
-	completions, err = cs.ListCompletionsByDateRange(farFuture, farFuture.Add(time.Hour))
+	completions, err = cs.ListCompletionsByDateRange(testHouseholdID, farFuture, farFuture.Add(time.Hour))
 	if err != nil {
 		t.Fatalf("list by range: %v", err)
 	}
@@ -433,18 +484,18 @@ func TestListCompletionsByDateRange(t *testing.T) {
 func TestAreaSortOrder(t *testing.T) {
 	cs, _ := setupChoreTestDB(t)
 
-	areas, _ := cs.ListAreas()
+	areas, _ := cs.ListAreas(testHouseholdID)
 	// Reverse the order
 	ids := make([]int64, len(areas))
 	for i, a := range areas {
 		ids[len(areas)-1-i] = a.ID
 	}
 
-	if err := cs.UpdateAreaSortOrder(ids); err != nil {
+	if err := cs.UpdateAreaSortOrder(testHouseholdID, ids); err != nil {
 		t.Fatalf("update sort order: %v", err)
 	}
 
-	areas, _ = cs.ListAreas()
+	areas, _ = cs.ListAreas(testHouseholdID)
 	// First area should now be what was last
 	if areas[0].Name != "General" {
 		t.Errorf("first area after reorder = %q, want %q", areas[0].Name, "General")
@@ -454,10 +505,10 @@ func TestAreaSortOrder(t *testing.T) {
 func TestCompletionPointsEarned(t *testing.T) {
 	cs, ms := setupChoreTestDB(t)
 
-	member, _ := ms.Create("Frank", "#00FFFF", "F")
-	chore, _ := cs.Create("Big task", "", nil, 25, "", nil)
+	member, _ := ms.Create(testHouseholdID, "Frank", "#00FFFF", "F")
+	chore, _ := cs.Create(testHouseholdID, "Big task", "", nil, 25, "", nil)
 
-	comp, err := cs.CreateCompletion(chore.ID, &member.ID, 25)
+	comp, err := cs.CreateCompletion(chore.ID, testHouseholdID, &member.ID, 25)
 	if err != nil {
 		t.Fatalf("create completion with points: %v", err)
 	}
@@ -466,7 +517,7 @@ func TestCompletionPointsEarned(t *testing.T) {
 	}
 
 	// Verify via list
-	completions, _ := cs.ListCompletionsByChore(chore.ID)
+	completions, _ := cs.ListCompletionsByChore(chore.ID, testHouseholdID)
 	if len(completions) != 1 {
 		t.Fatalf("expected 1 completion, got %d", len(completions))
 	}
@@ -474,3 +525,405 @@ func TestCompletionPointsEarned(t *testing.T) {
 		t.Errorf("listed points_earned = %d, want 25", completions[0].PointsEarned)
 	}
 }
+
+func TestChoreHouseholdIsolation(t *testing.T) {
+	cs, ms := setupChoreTestDB(t)
+	otherID := createTestHousehold(t, cs.db, "Other")
+
+	member, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "😀")
+	c, err := cs.Create(testHouseholdID, "Dishes", "", nil, 2, "", nil)
+	if err != nil {
+		t.Fatalf("create chore: %v", err)
+	}
+	completion, err := cs.CreateCompletion(c.ID, testHouseholdID, &member.ID, 2)
+	if err != nil {
+		t.Fatalf("create completion: %v", err)
+	}
+
+	areas, _ := cs.ListAreas(otherID)
+	if len(areas) != 5 {
+		t.Errorf("other household areas = %d, want 5 seeded", len(areas))
+	}
+	chores, _ := cs.List(otherID)
+	if len(chores) != 0 {
+		t.Errorf("other household sees %d chores, want 0", len(chores))
+	}
+	if got, _ := cs.GetByID(c.ID, otherID); got != nil {
+		t.Error("expected nil when reading another household's chore")
+	}
+	if got, _ := cs.LastCompletionForChore(c.ID, otherID); got != nil {
+		t.Error("expected nil completion for another household")
+	}
+	if got, _ := cs.CreateCompletion(c.ID, otherID, nil, 2); got != nil {
+		t.Error("expected nil when completing another household's chore")
+	}
+
+	if err := cs.DeleteCompletion(completion.ID, otherID); err != nil {
+		t.Fatalf("delete completion: %v", err)
+	}
+	if err := cs.Delete(c.ID, otherID); err != nil {
+		t.Fatalf("delete chore: %v", err)
+	}
+
+	if got, _ := cs.GetByID(c.ID, testHouseholdID); got == nil {
+		t.Error("chore was deleted by another household")
+	}
+	completions, _ := cs.ListCompletionsByChore(c.ID, testHouseholdID)
+	if len(completions) != 1 {
+		t.Errorf("completions = %d, want 1", len(completions))
+	}
+}
+
+func TestChoreRotation(t *testing.T) {
+	cs, ms := setupChoreTestDB(t)
+	otherID := createTestHousehold(t, cs.db, "Other")
+
+	alice, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
+	bob, _ := ms.Create(testHouseholdID, "Bob", "#0000FF", "B")
+	c, _ := cs.Create(testHouseholdID, "Dishes", "", nil, 2, "FREQ=DAILY", nil)
+
+	if c.Rotates() || len(c.RotationMembers) != 0 {
+		t.Fatalf("new chore rotation = %v %q, want none", c.RotationMembers, c.RotationCadence)
+	}
+
+	if err := cs.SetRotation(c.ID, testHouseholdID, []int64{bob.ID, alice.ID}, model.RotationWeekly); err != nil {
+		t.Fatalf("set rotation: %v", err)
+	}
+	got, _ := cs.GetByID(c.ID, testHouseholdID)
+	if got.RotationCadence != model.RotationWeekly {
+		t.Errorf("cadence = %q, want weekly", got.RotationCadence)
+	}
+	if len(got.RotationMembers) != 2 || got.RotationMembers[0] != bob.ID || got.RotationMembers[1] != alice.ID {
+		t.Errorf("members = %v, want [%d %d]", got.RotationMembers, bob.ID, alice.ID)
+	}
+
+	// Another household cannot change the rotation.
+	if err := cs.SetRotation(c.ID, otherID, nil, model.RotationNone); err != nil {
+		t.Fatalf("set rotation: %v", err)
+	}
+	if got, _ := cs.GetByID(c.ID, testHouseholdID); !got.Rotates() {
+		t.Error("rotation was cleared by another household")
+	}
+
+	// Deleting a member drops them from the rotation.
+	if err := ms.Delete(bob.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete member: %v", err)
+	}
+	got, _ = cs.GetByID(c.ID, testHouseholdID)
+	if len(got.RotationMembers) != 1 || got.RotationMembers[0] != alice.ID {
+		t.Errorf("members after delete = %v, want [%d]", got.RotationMembers, alice.ID)
+	}
+
+	// No members means no rotation.
+	if err := cs.SetRotation(c.ID, testHouseholdID, nil, model.RotationWeekly); err != nil {
+		t.Fatalf("clear rotation: %v", err)
+	}
+	got, _ = cs.GetByID(c.ID, testHouseholdID)
+	if got.Rotates() || got.RotationCadence != model.RotationNone {
+		t.Errorf("rotation = %v %q, want none", got.RotationMembers, got.RotationCadence)
+	}
+}
+
+func TestChoreDeadline(t *testing.T) {
+	cs, _ := setupChoreTestDB(t)
+	otherID := createTestHousehold(t, cs.db, "Other")
+
+	c, _ := cs.Create(testHouseholdID, "Return library books", "", nil, 0, "", nil)
+	if c.DueAt != nil || c.DueTime != "" {
+		t.Fatalf("new chore deadline = %v %q, want none", c.DueAt, c.DueTime)
+	}
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	dueAt := time.Date(2026, 2, 5, 15, 30, 0, 0, ny)
+	if err := cs.SetDeadline(c.ID, testHouseholdID, &dueAt, ""); err != nil {
+		t.Fatalf("set deadline: %v", err)
+	}
+	got, _ := cs.GetByID(c.ID, testHouseholdID)
+	if got.DueAt == nil || !got.DueAt.Equal(dueAt) {
+		t.Errorf("due_at = %v, want %v", got.DueAt, dueAt)
+	}
+
+	// Another household cannot change the deadline.
+	cs.SetDeadline(c.ID, otherID, nil, "")
+	if got, _ := cs.GetByID(c.ID, testHouseholdID); got.DueAt == nil {
+		t.Error("deadline was cleared by another household")
+	}
+
+	if err := cs.SetDeadline(c.ID, testHouseholdID, nil, "08:00"); err != nil {
+		t.Fatalf("set due time: %v", err)
+	}
+	got, _ = cs.GetByID(c.ID, testHouseholdID)
+	if got.DueAt != nil || got.DueTime != "08:00" {
+		t.Errorf("deadline = %v %q, want none and 08:00", got.DueAt, got.DueTime)
+	}
+}
+
+func TestCompletionApproval(t *testing.T) {
+	cs, ms := setupChoreTestDB(t)
+	otherID := createTestHousehold(t, cs.db, "Other")
+	kid, _ := ms.Create(testHouseholdID, "Kid", "#00FF00", "K")
+	parent, _ := ms.Create(testHouseholdID, "Parent", "#0000FF", "P")
+
+	c, _ := cs.Create(testHouseholdID, "Clean room", "", nil, 10, "", nil)
+	if err := cs.SetRequiresApproval(c.ID, testHouseholdID, true); err != nil {
+		t.Fatalf("set requires approval: %v", err)
+	}
+	if got, _ := cs.GetByID(c.ID, testHouseholdID); !got.RequiresApproval {
+		t.Fatal("requires_approval was not saved")
+	}
+
+	comp, err := cs.CreateCompletion(c.ID, testHouseholdID, &kid.ID, c.Points)
+	if err != nil {
+		t.Fatalf("create completion: %v", err)
+	}
+	if comp.Status != model.CompletionPending || comp.PointsEarned != 0 {
+		t.Fatalf("completion = %s with %d points, want pending with 0", comp.Status, comp.PointsEarned)
+	}
+	pending, _ := cs.ListPendingCompletions(testHouseholdID)
+	if len(pending) != 1 || pending[0].ID != comp.ID {
+		t.Fatalf("pending = %+v, want the completion", pending)
+	}
+	if other, _ := cs.ListPendingCompletions(otherID); len(other) != 0 {
+		t.Errorf("other household sees %d pending completions", len(other))
+	}
+
+	// Another household cannot review it.
+	if got, _ := cs.ReviewCompletion(comp.ID, otherID, model.CompletionApproved, parent.ID, 10, ""); got != nil {
+		t.Error("another household reviewed the completion")
+	}
+
+	// Points are those given on approval.
+	got, err := cs.ReviewCompletion(comp.ID, testHouseholdID, model.CompletionApproved, parent.ID, 15, "Looks great")
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if got.Status != model.CompletionApproved || got.PointsEarned != 15 || got.ReviewedBy == nil || *got.ReviewedBy != parent.ID || got.ReviewComment != "Looks great" {
+		t.Errorf("approved completion = %+v", got)
+	}
+	if again, _ := cs.ReviewCompletion(comp.ID, testHouseholdID, model.CompletionRejected, parent.ID, 15, ""); again != nil {
+		t.Error("completion was reviewed twice")
+	}
+
+	// A rejected completion earns nothing and does not count as done.
+	comp2, _ := cs.CreateCompletion(c.ID, testHouseholdID, &kid.ID, c.Points)
+	cs.db.Exec(`UPDATE chore_completions SET completed_at = ? WHERE id = ?`, time.Now().Add(time.Hour).UTC(), comp2.ID)
+	got, _ = cs.ReviewCompletion(comp2.ID, testHouseholdID, model.CompletionRejected, parent.ID, 15, "")
+	if got == nil || got.Status != model.CompletionRejected || got.PointsEarned != 0 {
+		t.Errorf("rejected completion = %+v", got)
+	}
+	if last, _ := cs.LastCompletionForChore(c.ID, testHouseholdID); last == nil || last.ID != comp.ID {
+		t.Errorf("last completion = %+v, want the approved one", last)
+	}
+}
+
+func TestChoreRotationEvents(t *testing.T) {
+	cs, ms := setupChoreTestDB(t)
+	otherID := createTestHousehold(t, cs.db, "Other")
+
+	alice, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
+	bob, _ := ms.Create(testHouseholdID, "Bob", "#0000FF", "B")
+	c, _ := cs.Create(testHouseholdID, "Dishes", "", nil, 2, "FREQ=DAILY", nil)
+
+	e, err := cs.CreateRotationEvent(c.ID, testHouseholdID, model.RotationSwap, 3, "2026-02-04", &alice.ID, &bob.ID)
+	if err != nil {
+		t.Fatalf("create rotation event: %v", err)
+	}
+	if e.Kind != model.RotationSwap || e.Turn != 3 || e.DueDate != "2026-02-04" {
+		t.Errorf("event = %+v", e)
+	}
+	if e.FromMemberID == nil || *e.FromMemberID != alice.ID || e.ToMemberID == nil || *e.ToMemberID != bob.ID {
+		t.Errorf("event members = %v -> %v", e.FromMemberID, e.ToMemberID)
+	}
+
+	if got, _ := cs.CreateRotationEvent(c.ID, otherID, model.RotationSkip, 4, "2026-02-05", nil, nil); got != nil {
+		t.Error("expected nil when recording an event on another household's chore")
+	}
+	if events, _ := cs.ListRotationEvents(c.ID, otherID); len(events) != 0 {
+		t.Errorf("other household sees %d events, want 0", len(events))
+	}
+
+	events, err := cs.ListRotationEvents(c.ID, testHouseholdID)
+	if err != nil {
+		t.Fatalf("list rotation events: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != e.ID {
+		t.Fatalf("events = %+v, want the swap", events)
+	}
+
+	if err := cs.Delete(c.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete chore: %v", err)
+	}
+	if events, _ := cs.ListRotationEvents(c.ID, testHouseholdID); len(events) != 0 {
+		t.Errorf("events after chore delete = %d, want 0", len(events))
+	}
+}
+
+func TestCountCompletionsBefore(t *testing.T) {
+	cs, _ := setupChoreTestDB(t)
+	c, _ := cs.Create(testHouseholdID, "Dishes", "", nil, 2, "", nil)
+
+	for i := 0; i < 3; i++ {
+		if _, err := cs.CreateCompletion(c.ID, testHouseholdID, nil, 0); err != nil {
+			t.Fatalf("create completion: %v", err)
+		}
+	}
+
+	n, err := cs.CountCompletionsBefore(c.ID, testHouseholdID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("count completions: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("count = %d, want 3", n)
+	}
+	if n, _ := cs.CountCompletionsBefore(c.ID, testHouseholdID, time.Now().Add(-time.Hour)); n != 0 {
+		t.Errorf("count before an hour ago = %d, want 0", n)
+	}
+}
+
+func TestChoreOccurrences(t *testing.T) {
+	cs, ms := setupChoreTestDB(t)
+	otherID := createTestHousehold(t, cs.db, "Other")
+
+	kid, _ := ms.Create(testHouseholdID, "Kid", "#FF0000", "K")
+	c, _ := cs.Create(testHouseholdID, "Feed cat", "", nil, 1, "FREQ=DAILY", &kid.ID)
+
+	added, err := cs.CloseOccurrence(c.ID, testHouseholdID, "2026-02-01", model.OccurrenceMissed, &kid.ID, nil)
+	if err != nil || !added {
+		t.Fatalf("close occurrence = %v, %v", added, err)
+	}
+	if added, _ := cs.CloseOccurrence(c.ID, testHouseholdID, "2026-02-01", model.OccurrenceDone, &kid.ID, &kid.ID); added {
+		t.Error("closing an occurrence twice replaced it")
+	}
+
+	// Excusing replaces the recorded outcome.
+	o, err := cs.SetOccurrence(c.ID, testHouseholdID, "2026-02-01", model.OccurrenceExcused, &kid.ID, nil, "flu")
+	if err != nil {
+		t.Fatalf("set occurrence: %v", err)
+	}
+	if o.Status != model.OccurrenceExcused || o.Note != "flu" {
+		t.Errorf("occurrence = %+v", o)
+	}
+
+	if got, _ := cs.SetOccurrence(c.ID, otherID, "2026-02-02", model.OccurrenceExcused, nil, nil, ""); got != nil {
+		t.Error("expected nil when excusing another household's chore")
+	}
+	if added, _ := cs.CloseOccurrence(c.ID, otherID, "2026-02-02", model.OccurrenceMissed, nil, nil); added {
+		t.Error("closed an occurrence of another household's chore")
+	}
+	cs.DeleteOccurrence(c.ID, otherID, "2026-02-01")
+
+	occurrences, err := cs.ListOccurrences(c.ID, testHouseholdID)
+	if err != nil {
+		t.Fatalf("list occurrences: %v", err)
+	}
+	if len(occurrences) != 1 || occurrences[0].Status != model.OccurrenceExcused {
+		t.Fatalf("occurrences = %+v, want the excused one", occurrences)
+	}
+
+	if err := cs.DeleteOccurrence(c.ID, testHouseholdID, "2026-02-01"); err != nil {
+		t.Fatalf("delete occurrence: %v", err)
+	}
+	if occurrences, _ := cs.ListOccurrences(c.ID, testHouseholdID); len(occurrences) != 0 {
+		t.Errorf("occurrences after delete = %d, want 0", len(occurrences))
+	}
+
+	ids, err := cs.ListHouseholdIDs()
+	if err != nil {
+		t.Fatalf("list household ids: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != testHouseholdID {
+		t.Errorf("household ids = %v, want [%d]", ids, testHouseholdID)
+	}
+}
+
+func TestChoreTemplates(t *testing.T) {
+	cs, _ := setupChoreTestDB(t)
+	otherID := createTestHousehold(t, cs.db, "Other")
+
+	areas, _ := cs.ListAreas(testHouseholdID)
+	kitchen := areas[0].ID
+	dishes, _ := cs.Create(testHouseholdID, "Dishes", "After dinner", &kitchen, 2, "FREQ=DAILY", nil)
+	if err := cs.SetDeadline(dishes.ID, testHouseholdID, nil, "20:00"); err != nil {
+		t.Fatalf("set deadline: %v", err)
+	}
+	if err := cs.SetRequiresApproval(dishes.ID, testHouseholdID, true); err != nil {
+		t.Fatalf("set requires approval: %v", err)
+	}
+	cs.Create(testHouseholdID, "Fix the fence", "", nil, 5, "", nil)
+
+	saved, err := cs.SaveTemplate(testHouseholdID, "Our chores", "")
+	if err != nil {
+		t.Fatalf("save template: %v", err)
+	}
+	if len(saved.ShareCode) != 8 {
+		t.Errorf("share code = %q, want 8 characters", saved.ShareCode)
+	}
+	if len(saved.Chores) != 1 {
+		t.Fatalf("template chores = %d, want 1 (one-off chores left out)", len(saved.Chores))
+	}
+	want := model.TemplateChore{Title: "Dishes", Description: "After dinner", Area: "Kitchen", Points: 2, RecurrenceRule: "FREQ=DAILY", DueTime: "20:00", RequiresApproval: true}
+	if saved.Chores[0] != want {
+		t.Errorf("template chore = %+v, want %+v", saved.Chores[0], want)
+	}
+
+	// Other households can only find it by share code.
+	if got, _ := cs.GetTemplate(saved.ID, otherID); got != nil {
+		t.Error("expected nil when reading another household's template")
+	}
+	if list, _ := cs.ListTemplates(otherID); len(list) != 0 {
+		t.Errorf("other household templates = %d, want 0", len(list))
+	}
+	code := strings.ToLower(saved.ShareCode[:4]) + "-" + saved.ShareCode[4:]
+	shared, err := cs.GetTemplateByShareCode(code)
+	if err != nil || shared == nil || shared.ID != saved.ID {
+		t.Fatalf("get by share code %q = %v, %v", code, shared, err)
+	}
+
+	// Importing creates missing areas and skips chores already there.
+	chores := append(shared.Chores, model.TemplateChore{Title: "Clean gutters", Area: "Garage", Points: 3, RecurrenceRule: "FREQ=MONTHLY"})
+	n, err := cs.ImportTemplate(otherID, chores)
+	if err != nil {
+		t.Fatalf("import template: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("imported = %d, want 2", n)
+	}
+	if n, _ := cs.ImportTemplate(otherID, chores); n != 0 {
+		t.Errorf("imported again = %d, want 0", n)
+	}
+
+	imported, _ := cs.List(otherID)
+	if len(imported) != 2 {
+		t.Fatalf("other household chores = %d, want 2", len(imported))
+	}
+	otherAreas, _ := cs.ListAreas(otherID)
+	if len(otherAreas) != 6 || otherAreas[5].Name != "Garage" {
+		t.Errorf("other household areas = %+v, want Garage added last", otherAreas)
+	}
+	for _, c := range imported {
+		if c.Title == "Dishes" {
+			if c.AreaID == nil || *c.AreaID != otherAreas[0].ID {
+				t.Errorf("Dishes area = %v, want other household's Kitchen", c.AreaID)
+			}
+			if c.DueTime != "20:00" || !c.RequiresApproval {
+				t.Errorf("Dishes due time %q approval %v, want 20:00 true", c.DueTime, c.RequiresApproval)
+			}
+		}
+	}
+
+	if err := cs.DeleteTemplate(saved.ID, otherID); err != nil {
+		t.Fatalf("delete template: %v", err)
+	}
+	if got, _ := cs.GetTemplate(saved.ID, testHouseholdID); got == nil {
+		t.Error("template was deleted by another household")
+	}
+	if err := cs.DeleteTemplate(saved.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete template: %v", err)
+	}
+	if got, _ := cs.GetTemplateByShareCode(saved.ShareCode); got != nil {
+		t.Error("expected nil after delete")
+	}
+}
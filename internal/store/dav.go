@@ -0,0 +1,228 @@
+package store
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+// DAVStore holds the state of the built-in CalDAV server: app passwords and
+// the names clients gave to the event series they created.
+type DAVStore struct {
+	db *sql.DB
+}
+
+func NewDAVStore(db *sql.DB) *DAVStore {
+	return &DAVStore{db: db}
+}
+
+func scanAppPassword(scanner interface{ Scan(...any) error }) (*model.AppPassword, error) {
+	var p model.AppPassword
+	var lastUsed sql.NullTime
+	err := scanner.Scan(&p.ID, &p.UserID, &p.HouseholdID, &p.Label, &lastUsed, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if lastUsed.Valid {
+		p.LastUsedAt = &lastUsed.Time
+	}
+	return &p, nil
+}
+
+const appPasswordCols = `id, user_id, household_id, label, last_used_at, created_at`
+
+// appPasswordAlphabet leaves out letters that are easy to confuse when
+// typing a password into a phone.
+const appPasswordAlphabet = "abcdefghjkmnpqrstuvwxyz"
+
+// CreateAppPassword generates a password for a user in a household. The
+// plaintext is returned once, formatted as four dash-separated groups, and
+// only its hash is stored.
+func (s *DAVStore) CreateAppPassword(userID, householdID int64, label string) (*model.AppPassword, string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("generate app password: %w", err)
+	}
+	var b strings.Builder
+	for i, c := range raw {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteByte(appPasswordAlphabet[int(c)%len(appPasswordAlphabet)])
+	}
+	password := b.String()
+
+	result, err := s.db.Exec(
+		`INSERT INTO app_passwords (user_id, household_id, label, password_hash) VALUES (?, ?, ?, ?)`,
+		userID, householdID, label, hashAppPassword(password),
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("insert app password: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, "", fmt.Errorf("last insert id: %w", err)
+	}
+	row := s.db.QueryRow(`SELECT `+appPasswordCols+` FROM app_passwords WHERE id = ?`, id)
+	p, err := scanAppPassword(row)
+	if err != nil {
+		return nil, "", fmt.Errorf("get app password: %w", err)
+	}
+	return p, password, nil
+}
+
+// hashAppPassword ignores case, spaces, and dashes so a password can be
+// typed the way it was displayed or run together.
+func hashAppPassword(password string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(password))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// ListAppPasswords returns a user's app passwords in a household, newest first.
+func (s *DAVStore) ListAppPasswords(userID, householdID int64) ([]model.AppPassword, error) {
+	rows, err := s.db.Query(
+		`SELECT `+appPasswordCols+` FROM app_passwords
+		 WHERE user_id = ? AND household_id = ?
+		 ORDER BY created_at DESC, id DESC`,
+		userID, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list app passwords: %w", err)
+	}
+	defer rows.Close()
+
+	var passwords []model.AppPassword
+	for rows.Next() {
+		p, err := scanAppPassword(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan app password: %w", err)
+		}
+		passwords = append(passwords, *p)
+	}
+	return passwords, rows.Err()
+}
+
+// Authenticate returns the app password matching the user's email and the
+// given password, or nil if there is none.
+func (s *DAVStore) Authenticate(email, password string) (*model.AppPassword, error) {
+	row := s.db.QueryRow(
+		`SELECT p.id, p.user_id, p.household_id, p.label, p.last_used_at, p.created_at
+		 FROM app_passwords p JOIN users u ON u.id = p.user_id
+		 WHERE u.email = ? COLLATE NOCASE AND p.password_hash = ?`,
+		strings.TrimSpace(email), hashAppPassword(password),
+	)
+	p, err := scanAppPassword(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("authenticate app password: %w", err)
+	}
+	return p, nil
+}
+
+// MarkAppPasswordUsed records that a client signed in with the password.
+func (s *DAVStore) MarkAppPasswordUsed(id int64) error {
+	_, err := s.db.Exec(`UPDATE app_passwords SET last_used_at = datetime('now') WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("mark app password used: %w", err)
+	}
+	return nil
+}
+
+// DeleteAppPassword revokes one of a user's app passwords.
+func (s *DAVStore) DeleteAppPassword(id, userID, householdID int64) error {
+	_, err := s.db.Exec(
+		`DELETE FROM app_passwords WHERE id = ? AND user_id = ? AND household_id = ?`,
+		id, userID, householdID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete app password: %w", err)
+	}
+	return nil
+}
+
+const davResourceCols = `event_id, household_id, name, uid`
+
+func scanDAVResource(scanner interface{ Scan(...any) error }) (*model.DAVResource, error) {
+	var r model.DAVResource
+	if err := scanner.Scan(&r.EventID, &r.HouseholdID, &r.Name, &r.UID); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetResource returns the series a client stored under name, or nil.
+func (s *DAVStore) GetResource(householdID int64, name string) (*model.DAVResource, error) {
+	row := s.db.QueryRow(
+		`SELECT `+davResourceCols+` FROM dav_resources WHERE household_id = ? AND name = ?`,
+		householdID, name,
+	)
+	r, err := scanDAVResource(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get dav resource: %w", err)
+	}
+	return r, nil
+}
+
+// ListResources returns the household's client-named series keyed by event ID.
+func (s *DAVStore) ListResources(householdID int64) (map[int64]model.DAVResource, error) {
+	rows, err := s.db.Query(`SELECT `+davResourceCols+` FROM dav_resources WHERE household_id = ?`, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list dav resources: %w", err)
+	}
+	defer rows.Close()
+
+	resources := make(map[int64]model.DAVResource)
+	for rows.Next() {
+		r, err := scanDAVResource(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan dav resource: %w", err)
+		}
+		resources[r.EventID] = *r
+	}
+	return resources, rows.Err()
+}
+
+// SaveResource records the name and UID a client gave an event series.
+func (s *DAVStore) SaveResource(householdID, eventID int64, name, uid string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO dav_resources (event_id, household_id, name, uid) VALUES (?, ?, ?, ?)
+		 ON CONFLICT (event_id) DO UPDATE SET name = excluded.name, uid = excluded.uid`,
+		eventID, householdID, name, uid,
+	)
+	if err != nil {
+		return fmt.Errorf("save dav resource: %w", err)
+	}
+	return nil
+}
+
+// GetResourceByUID returns the client-named series with the given UID, or nil.
+func (s *DAVStore) GetResourceByUID(householdID int64, uid string) (*model.DAVResource, error) {
+	row := s.db.QueryRow(
+		`SELECT `+davResourceCols+` FROM dav_resources WHERE household_id = ? AND uid = ?`,
+		householdID, uid,
+	)
+	r, err := scanDAVResource(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get dav resource by uid: %w", err)
+	}
+	return r, nil
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/database"
+)
+
+func setupDAVTestDB(t *testing.T) (*DAVStore, *UserStore, *EventStore) {
+	t.Helper()
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewDAVStore(db), NewUserStore(db), NewEventStore(db)
+}
+
+func TestAppPasswords(t *testing.T) {
+	s, us, _ := setupDAVTestDB(t)
+
+	u, err := us.Create("parent@example.com", "Parent")
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	ap, password, err := s.CreateAppPassword(u.ID, testHouseholdID, "iPhone")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(password) != 19 || strings.Count(password, "-") != 3 {
+		t.Errorf("password = %q, want four groups of four", password)
+	}
+
+	for _, tc := range []struct {
+		email, password string
+		ok              bool
+	}{
+		{"parent@example.com", password, true},
+		{"Parent@Example.com", strings.ToUpper(strings.ReplaceAll(password, "-", "")), true},
+		{"other@example.com", password, false},
+		{"parent@example.com", "wrong", false},
+	} {
+		got, err := s.Authenticate(tc.email, tc.password)
+		if err != nil {
+			t.Fatalf("authenticate: %v", err)
+		}
+		if (got != nil) != tc.ok {
+			t.Errorf("Authenticate(%q, %q) = %v, want ok=%v", tc.email, tc.password, got, tc.ok)
+		}
+		if got != nil && got.ID != ap.ID {
+			t.Errorf("authenticated as %d, want %d", got.ID, ap.ID)
+		}
+	}
+
+	if err := s.MarkAppPasswordUsed(ap.ID); err != nil {
+		t.Fatalf("mark used: %v", err)
+	}
+	list, _ := s.ListAppPasswords(u.ID, testHouseholdID)
+	if len(list) != 1 || list[0].LastUsedAt == nil {
+		t.Errorf("list = %+v, want one used password", list)
+	}
+
+	// Deleting is scoped to the owner.
+	s.DeleteAppPassword(ap.ID, u.ID+1, testHouseholdID)
+	if got, _ := s.Authenticate(u.Email, password); got == nil {
+		t.Error("another user should not be able to revoke the password")
+	}
+	if err := s.DeleteAppPassword(ap.ID, u.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if got, _ := s.Authenticate(u.Email, password); got != nil {
+		t.Error("revoked password still authenticates")
+	}
+}
+
+func TestDAVResources(t *testing.T) {
+	s, _, es := setupDAVTestDB(t)
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	e, _ := es.Create(testHouseholdID, "Swim", "", start, start.Add(time.Hour), false, nil, "")
+
+	if err := s.SaveResource(testHouseholdID, e.ID, "swim.ics", "swim-uid"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := s.SaveResource(testHouseholdID, e.ID, "swim-2.ics", "swim-uid"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if got, _ := s.GetResource(testHouseholdID, "swim.ics"); got != nil {
+		t.Errorf("old name still resolves: %+v", got)
+	}
+	if got, _ := s.GetResourceByUID(testHouseholdID, "swim-uid"); got == nil || got.Name != "swim-2.ics" {
+		t.Errorf("by uid = %+v, want swim-2.ics", got)
+	}
+
+	es.Delete(e.ID, testHouseholdID)
+	if resources, _ := s.ListResources(testHouseholdID); len(resources) != 0 {
+		t.Errorf("resources after event delete = %d, want 0", len(resources))
+	}
+}
@@ -15,16 +15,16 @@ func NewFamilyMemberStore(db *sql.DB) *FamilyMemberStore {
 	return &FamilyMemberStore{db: db}
 }
 
-func (s *FamilyMemberStore) Create(name, color, avatarEmoji string) (*model.FamilyMember, error) {
+func (s *FamilyMemberStore) Create(householdID int64, name, color, avatarEmoji string) (*model.FamilyMember, error) {
 	var maxOrder int
-	err := s.db.QueryRow("SELECT COALESCE(MAX(sort_order), -1) FROM family_members").Scan(&maxOrder)
+	err := s.db.QueryRow("SELECT COALESCE(MAX(sort_order), -1) FROM family_members WHERE household_id = ?", householdID).Scan(&maxOrder)
 	if err != nil {
 		return nil, fmt.Errorf("query max sort_order: %w", err)
 	}
 
 	result, err := s.db.Exec(
-		"INSERT INTO family_members (name, color, avatar_emoji, sort_order) VALUES (?, ?, ?, ?)",
-		name, color, avatarEmoji, maxOrder+1,
+		"INSERT INTO family_members (household_id, name, color, avatar_emoji, sort_order) VALUES (?, ?, ?, ?, ?)",
+		householdID, name, color, avatarEmoji, maxOrder+1,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert family member: %w", err)
@@ -35,12 +35,26 @@ func (s *FamilyMemberStore) Create(name, color, avatarEmoji string) (*model.Fami
 		return nil, fmt.Errorf("last insert id: %w", err)
 	}
 
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
 }
 
-func (s *FamilyMemberStore) List() ([]model.FamilyMember, error) {
+const familyMemberCols = "id, name, color, avatar_emoji, pin IS NOT NULL, COALESCE(birthdate, ''), role, points_percent, sort_order, created_at, updated_at"
+
+func scanFamilyMember(scanner interface{ Scan(...any) error }) (model.FamilyMember, error) {
+	var m model.FamilyMember
+	var pointsPercent sql.NullInt64
+	err := scanner.Scan(&m.ID, &m.Name, &m.Color, &m.AvatarEmoji, &m.HasPIN, &m.Birthdate, &m.Role, &pointsPercent, &m.SortOrder, &m.CreatedAt, &m.UpdatedAt)
+	if pointsPercent.Valid {
+		pct := int(pointsPercent.Int64)
+		m.PointsPercent = &pct
+	}
+	return m, err
+}
+
+func (s *FamilyMemberStore) List(householdID int64) ([]model.FamilyMember, error) {
 	rows, err := s.db.Query(
-		"SELECT id, name, color, avatar_emoji, pin IS NOT NULL, sort_order, created_at, updated_at FROM family_members ORDER BY sort_order",
+		"SELECT "+familyMemberCols+" FROM family_members WHERE household_id = ? ORDER BY sort_order",
+		householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("query family members: %w", err)
@@ -49,8 +63,8 @@ func (s *FamilyMemberStore) List() ([]model.FamilyMember, error) {
 
 	var members []model.FamilyMember
 	for rows.Next() {
-		var m model.FamilyMember
-		if err := rows.Scan(&m.ID, &m.Name, &m.Color, &m.AvatarEmoji, &m.HasPIN, &m.SortOrder, &m.CreatedAt, &m.UpdatedAt); err != nil {
+		m, err := scanFamilyMember(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan family member: %w", err)
 		}
 		members = append(members, m)
@@ -58,12 +72,11 @@ func (s *FamilyMemberStore) List() ([]model.FamilyMember, error) {
 	return members, rows.Err()
 }
 
-func (s *FamilyMemberStore) GetByID(id int64) (*model.FamilyMember, error) {
-	var m model.FamilyMember
-	err := s.db.QueryRow(
-		"SELECT id, name, color, avatar_emoji, pin IS NOT NULL, sort_order, created_at, updated_at FROM family_members WHERE id = ?",
-		id,
-	).Scan(&m.ID, &m.Name, &m.Color, &m.AvatarEmoji, &m.HasPIN, &m.SortOrder, &m.CreatedAt, &m.UpdatedAt)
+func (s *FamilyMemberStore) GetByID(id, householdID int64) (*model.FamilyMember, error) {
+	m, err := scanFamilyMember(s.db.QueryRow(
+		"SELECT "+familyMemberCols+" FROM family_members WHERE id = ? AND household_id = ?",
+		id, householdID,
+	))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -73,40 +86,61 @@ func (s *FamilyMemberStore) GetByID(id int64) (*model.FamilyMember, error) {
 	return &m, nil
 }
 
-func (s *FamilyMemberStore) Update(id int64, name, color, avatarEmoji string) (*model.FamilyMember, error) {
+func (s *FamilyMemberStore) Update(id, householdID int64, name, color, avatarEmoji string) (*model.FamilyMember, error) {
 	_, err := s.db.Exec(
-		"UPDATE family_members SET name = ?, color = ?, avatar_emoji = ? WHERE id = ?",
-		name, color, avatarEmoji, id,
+		"UPDATE family_members SET name = ?, color = ?, avatar_emoji = ? WHERE id = ? AND household_id = ?",
+		name, color, avatarEmoji, id, householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("update family member: %w", err)
 	}
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
+}
+
+// SetProfile sets a member's birthdate, role and point multiplier
+// override. An empty birthdate or nil pointsPercent clears it.
+func (s *FamilyMemberStore) SetProfile(id, householdID int64, birthdate string, role model.MemberRole, pointsPercent *int) error {
+	var born sql.NullString
+	if birthdate != "" {
+		born = sql.NullString{String: birthdate, Valid: true}
+	}
+	var pct sql.NullInt64
+	if pointsPercent != nil {
+		pct = sql.NullInt64{Int64: int64(*pointsPercent), Valid: true}
+	}
+	_, err := s.db.Exec(
+		"UPDATE family_members SET birthdate = ?, role = ?, points_percent = ? WHERE id = ? AND household_id = ?",
+		born, role, pct, id, householdID,
+	)
+	if err != nil {
+		return fmt.Errorf("set family member profile: %w", err)
+	}
+	return nil
 }
 
-func (s *FamilyMemberStore) Delete(id int64) error {
-	_, err := s.db.Exec("DELETE FROM family_members WHERE id = ?", id)
+func (s *FamilyMemberStore) Delete(id, householdID int64) error {
+	_, err := s.db.Exec("DELETE FROM family_members WHERE id = ? AND household_id = ?", id, householdID)
 	if err != nil {
 		return fmt.Errorf("delete family member: %w", err)
 	}
 	return nil
 }
 
-func (s *FamilyMemberStore) UpdateSortOrder(ids []int64) error {
+func (s *FamilyMemberStore) UpdateSortOrder(householdID int64, ids []int64) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare("UPDATE family_members SET sort_order = ? WHERE id = ?")
+	stmt, err := tx.Prepare("UPDATE family_members SET sort_order = ? WHERE id = ? AND household_id = ?")
 	if err != nil {
 		return fmt.Errorf("prepare stmt: %w", err)
 	}
 	defer stmt.Close()
 
 	for i, id := range ids {
-		if _, err := stmt.Exec(i, id); err != nil {
+		if _, err := stmt.Exec(i, id, householdID); err != nil {
 			return fmt.Errorf("update sort order for id %d: %w", id, err)
 		}
 	}
@@ -114,25 +148,25 @@ func (s *FamilyMemberStore) UpdateSortOrder(ids []int64) error {
 	return tx.Commit()
 }
 
-func (s *FamilyMemberStore) SetPIN(id int64, hashedPIN string) error {
-	_, err := s.db.Exec("UPDATE family_members SET pin = ? WHERE id = ?", hashedPIN, id)
+func (s *FamilyMemberStore) SetPIN(id, householdID int64, hashedPIN string) error {
+	_, err := s.db.Exec("UPDATE family_members SET pin = ? WHERE id = ? AND household_id = ?", hashedPIN, id, householdID)
 	if err != nil {
 		return fmt.Errorf("set pin: %w", err)
 	}
 	return nil
 }
 
-func (s *FamilyMemberStore) ClearPIN(id int64) error {
-	_, err := s.db.Exec("UPDATE family_members SET pin = NULL WHERE id = ?", id)
+func (s *FamilyMemberStore) ClearPIN(id, householdID int64) error {
+	_, err := s.db.Exec("UPDATE family_members SET pin = NULL WHERE id = ? AND household_id = ?", id, householdID)
 	if err != nil {
 		return fmt.Errorf("clear pin: %w", err)
 	}
 	return nil
 }
 
-func (s *FamilyMemberStore) GetPINHash(id int64) (string, error) {
+func (s *FamilyMemberStore) GetPINHash(id, householdID int64) (string, error) {
 	var pin sql.NullString
-	err := s.db.QueryRow("SELECT pin FROM family_members WHERE id = ?", id).Scan(&pin)
+	err := s.db.QueryRow("SELECT pin FROM family_members WHERE id = ? AND household_id = ?", id, householdID).Scan(&pin)
 	if err == sql.ErrNoRows {
 		return "", fmt.Errorf("family member not found")
 	}
@@ -145,11 +179,11 @@ func (s *FamilyMemberStore) GetPINHash(id int64) (string, error) {
 	return pin.String, nil
 }
 
-func (s *FamilyMemberStore) NameExists(name string, excludeID int64) (bool, error) {
+func (s *FamilyMemberStore) NameExists(householdID int64, name string, excludeID int64) (bool, error) {
 	var count int
 	err := s.db.QueryRow(
-		"SELECT COUNT(*) FROM family_members WHERE name = ? AND id != ?",
-		name, excludeID,
+		"SELECT COUNT(*) FROM family_members WHERE household_id = ? AND name = ? AND id != ?",
+		householdID, name, excludeID,
 	).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("check name exists: %w", err)
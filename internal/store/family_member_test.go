@@ -0,0 +1,83 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+func TestFamilyMemberProfile(t *testing.T) {
+	cs, ms := setupChoreTestDB(t)
+	otherID := createTestHousehold(t, cs.db, "Other")
+
+	kid, _ := ms.Create(testHouseholdID, "Sam", "#00FF00", "S")
+	if kid.Birthdate != "" || kid.Role != model.RoleUnset || kid.PointsPercent != nil {
+		t.Fatalf("new member profile = %+v %v, want empty", kid.MemberProfile, kid.PointsPercent)
+	}
+
+	pct := 200
+	if err := ms.SetProfile(kid.ID, testHouseholdID, "2016-09-30", model.RoleChild, &pct); err != nil {
+		t.Fatalf("set profile: %v", err)
+	}
+	got, _ := ms.GetByID(kid.ID, testHouseholdID)
+	if got.Birthdate != "2016-09-30" || got.Role != model.RoleChild || got.PointsPercent == nil || *got.PointsPercent != 200 {
+		t.Errorf("profile = %+v %v", got.MemberProfile, got.PointsPercent)
+	}
+
+	today := time.Date(2026, 9, 29, 12, 0, 0, 0, time.UTC)
+	if age, ok := got.Age(today); !ok || age != 9 {
+		t.Errorf("age the day before the birthday = %d %v, want 9", age, ok)
+	}
+	if age, _ := got.Age(today.AddDate(0, 0, 1)); age != 10 {
+		t.Errorf("age on the birthday = %d, want 10", age)
+	}
+	if got.ScalePoints(3, today) != 6 {
+		t.Errorf("scaled points = %d, want 6", got.ScalePoints(3, today))
+	}
+
+	// Another household cannot change it.
+	if err := ms.SetProfile(kid.ID, otherID, "", model.RoleAdult, nil); err != nil {
+		t.Fatalf("set profile: %v", err)
+	}
+	if got, _ := ms.GetByID(kid.ID, testHouseholdID); got.Role != model.RoleChild {
+		t.Error("profile was changed by another household")
+	}
+
+	// Clearing the override falls back to the role's multiplier.
+	if err := ms.SetProfile(kid.ID, testHouseholdID, "2016-09-30", model.RoleUnset, nil); err != nil {
+		t.Fatalf("clear profile: %v", err)
+	}
+	got, _ = ms.GetByID(kid.ID, testHouseholdID)
+	if got.PointsPercent != nil || got.EffectiveRole(today) != model.RoleChild {
+		t.Errorf("profile = %+v %v, want child by age", got.MemberProfile, got.PointsPercent)
+	}
+	if got.ScalePoints(3, today) != 5 {
+		t.Errorf("scaled points = %d, want 5", got.ScalePoints(3, today))
+	}
+}
+
+func TestChoreRotationProfiles(t *testing.T) {
+	cs, ms := setupChoreTestDB(t)
+
+	alice, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
+	bob, _ := ms.Create(testHouseholdID, "Bob", "#0000FF", "B")
+	ms.SetProfile(bob.ID, testHouseholdID, "2020-01-15", model.RoleUnset, nil)
+
+	c, _ := cs.Create(testHouseholdID, "Mow the lawn", "", nil, 5, "FREQ=WEEKLY", nil)
+	cs.SetRotation(c.ID, testHouseholdID, []int64{alice.ID, bob.ID}, model.RotationWeekly)
+	if err := cs.SetMinAge(c.ID, testHouseholdID, 12); err != nil {
+		t.Fatalf("set min age: %v", err)
+	}
+
+	got, _ := cs.GetByID(c.ID, testHouseholdID)
+	if got.MinAge != 12 {
+		t.Errorf("min age = %d, want 12", got.MinAge)
+	}
+	if p := got.RotationProfiles[bob.ID]; p.Birthdate != "2020-01-15" {
+		t.Errorf("bob's rotation profile = %+v", p)
+	}
+	if p := got.RotationProfiles[alice.ID]; p != (model.MemberProfile{}) {
+		t.Errorf("alice's rotation profile = %+v, want empty", p)
+	}
+}
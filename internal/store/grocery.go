@@ -2,12 +2,29 @@ package store
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/dukerupert/gamwich/internal/grocery"
 	"github.com/dukerupert/gamwich/internal/model"
 )
 
+// Reasons a grocery category cannot be saved.
+var (
+	ErrCategoryExists  = errors.New("category already exists")
+	ErrCategoryIsOther = errors.New("the Other category cannot be changed")
+)
+
+// ErrLastGroceryList is returned when archiving or deleting a list would
+// leave the household without any list to add items to.
+var ErrLastGroceryList = errors.New("household must keep at least one grocery list")
+
+// ErrArchivedGroceryList is returned when moving an item to an archived
+// list, where it would not be seen.
+var ErrArchivedGroceryList = errors.New("grocery list is archived")
+
 type GroceryStore struct {
 	db *sql.DB
 }
@@ -29,8 +46,8 @@ func scanCategory(scanner interface{ Scan(...any) error }) (*model.GroceryCatego
 
 const categoryCols = `id, name, sort_order, created_at`
 
-func (s *GroceryStore) ListCategories() ([]model.GroceryCategory, error) {
-	rows, err := s.db.Query(`SELECT ` + categoryCols + ` FROM grocery_categories ORDER BY sort_order ASC, name ASC`)
+func (s *GroceryStore) ListCategories(householdID int64) ([]model.GroceryCategory, error) {
+	rows, err := s.db.Query(`SELECT `+categoryCols+` FROM grocery_categories WHERE household_id = ? ORDER BY sort_order ASC, name ASC`, householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list categories: %w", err)
 	}
@@ -47,21 +64,279 @@ func (s *GroceryStore) ListCategories() ([]model.GroceryCategory, error) {
 	return categories, rows.Err()
 }
 
+func (s *GroceryStore) GetCategoryByID(id, householdID int64) (*model.GroceryCategory, error) {
+	row := s.db.QueryRow(`SELECT `+categoryCols+` FROM grocery_categories WHERE id = ? AND household_id = ?`, id, householdID)
+	c, err := scanCategory(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get category: %w", err)
+	}
+	return c, nil
+}
+
+// CreateCategory adds a category after the household's existing ones. It
+// returns ErrCategoryExists if the household already has one by that name.
+func (s *GroceryStore) CreateCategory(householdID int64, name string) (*model.GroceryCategory, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := createCategory(tx, householdID, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetCategoryByID(id, householdID)
+}
+
+func createCategory(tx *sql.Tx, householdID int64, name string) (int64, error) {
+	if err := checkCategoryName(tx, householdID, 0, name); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec(
+		`INSERT INTO grocery_categories (household_id, name, sort_order)
+		 SELECT ?, ?, COALESCE(MAX(sort_order) + 1, 0) FROM grocery_categories WHERE household_id = ?`,
+		householdID, name, householdID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert category: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("last insert id: %w", err)
+	}
+	return id, nil
+}
+
+// checkCategoryName returns ErrCategoryExists if a category other than id
+// in the household already has the name, ignoring case.
+func checkCategoryName(tx *sql.Tx, householdID, id int64, name string) error {
+	var n int
+	err := tx.QueryRow(
+		`SELECT COUNT(*) FROM grocery_categories WHERE household_id = ? AND id != ? AND LOWER(name) = LOWER(?)`,
+		householdID, id, name,
+	).Scan(&n)
+	if err != nil {
+		return fmt.Errorf("check category name: %w", err)
+	}
+	if n > 0 {
+		return ErrCategoryExists
+	}
+	return nil
+}
+
+// RenameCategory renames a category, along with the items and learned
+// rules in it. It returns nil if the category is not in the household.
+func (s *GroceryStore) RenameCategory(id, householdID int64, name string) (*model.GroceryCategory, error) {
+	existing, err := s.GetCategoryByID(id, householdID)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+	if existing.Name == model.GroceryCategoryOther {
+		return nil, ErrCategoryIsOther
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkCategoryName(tx, householdID, id, name); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE grocery_categories SET name = ? WHERE id = ? AND household_id = ?`, name, id, householdID); err != nil {
+		return nil, fmt.Errorf("rename category: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE grocery_items SET category = ? WHERE category = ? AND `+householdItems, name, existing.Name, householdID); err != nil {
+		return nil, fmt.Errorf("rename item categories: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE grocery_category_rules SET category = ? WHERE category = ? AND household_id = ?`, name, existing.Name, householdID); err != nil {
+		return nil, fmt.Errorf("rename category rules: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetCategoryByID(id, householdID)
+}
+
+// DeleteCategory deletes a category. Its items move to Other and the
+// rules learned for it are forgotten.
+func (s *GroceryStore) DeleteCategory(id, householdID int64) error {
+	existing, err := s.GetCategoryByID(id, householdID)
+	if err != nil || existing == nil {
+		return err
+	}
+	if existing.Name == model.GroceryCategoryOther {
+		return ErrCategoryIsOther
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE grocery_items SET category = ? WHERE category = ? AND `+householdItems, model.GroceryCategoryOther, existing.Name, householdID); err != nil {
+		return fmt.Errorf("move items to other: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM grocery_category_rules WHERE category = ? AND household_id = ?`, existing.Name, householdID); err != nil {
+		return fmt.Errorf("delete category rules: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM grocery_categories WHERE id = ? AND household_id = ?`, id, householdID); err != nil {
+		return fmt.Errorf("delete category: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (s *GroceryStore) UpdateCategorySortOrder(householdID int64, ids []int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, id := range ids {
+		if _, err := tx.Exec(`UPDATE grocery_categories SET sort_order = ? WHERE id = ? AND household_id = ?`, i, id, householdID); err != nil {
+			return fmt.Errorf("update sort order: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// --- Category rule methods ---
+
+// CategoryRules returns the categories the household has chosen for items,
+// by item key.
+func (s *GroceryStore) CategoryRules(householdID int64) (map[string]string, error) {
+	rows, err := s.db.Query(`SELECT item_key, category FROM grocery_category_rules WHERE household_id = ?`, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list category rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make(map[string]string)
+	for rows.Next() {
+		var key, category string
+		if err := rows.Scan(&key, &category); err != nil {
+			return nil, fmt.Errorf("scan category rule: %w", err)
+		}
+		rules[key] = category
+	}
+	return rules, rows.Err()
+}
+
+// SetCategoryRule remembers the category the household wants for an item.
+func (s *GroceryStore) SetCategoryRule(householdID int64, itemKey, category string) error {
+	return setCategoryRule(s.db, householdID, itemKey, category)
+}
+
+func setCategoryRule(db interface {
+	Exec(string, ...any) (sql.Result, error)
+}, householdID int64, itemKey, category string) error {
+	_, err := db.Exec(
+		`INSERT INTO grocery_category_rules (household_id, item_key, category) VALUES (?, ?, ?)
+		 ON CONFLICT (household_id, item_key) DO UPDATE SET category = excluded.category, updated_at = datetime('now')`,
+		householdID, itemKey, category,
+	)
+	if err != nil {
+		return fmt.Errorf("set category rule: %w", err)
+	}
+	return nil
+}
+
+// ImportCategoryRules adds the given categories and rules to the
+// household's, replacing any rules for the same items. Categories the
+// rules use that the household does not have are created. It returns how
+// many categories were created.
+func (s *GroceryStore) ImportCategoryRules(householdID int64, categories []string, rules map[string]string) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Match names ignoring case, so an imported "dairy" is the household's
+	// "Dairy".
+	existing := make(map[string]string)
+	rows, err := tx.Query(`SELECT name FROM grocery_categories WHERE household_id = ?`, householdID)
+	if err != nil {
+		return 0, fmt.Errorf("list categories: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan category: %w", err)
+		}
+		existing[strings.ToLower(name)] = name
+	}
+	rows.Close()
+
+	created := 0
+	resolve := func(name string) (string, error) {
+		if have, ok := existing[strings.ToLower(name)]; ok {
+			return have, nil
+		}
+		if _, err := createCategory(tx, householdID, name); err != nil {
+			return "", err
+		}
+		existing[strings.ToLower(name)] = name
+		created++
+		return name, nil
+	}
+
+	for _, name := range categories {
+		if _, err := resolve(name); err != nil {
+			return 0, err
+		}
+	}
+	for key, category := range rules {
+		name, err := resolve(category)
+		if err != nil {
+			return 0, err
+		}
+		if err := setCategoryRule(tx, householdID, key, name); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return created, nil
+}
+
 // --- List methods ---
 
 func scanList(scanner interface{ Scan(...any) error }) (*model.GroceryList, error) {
 	var l model.GroceryList
-	err := scanner.Scan(&l.ID, &l.Name, &l.SortOrder, &l.CreatedAt)
+	var archived int
+	var shopID sql.NullInt64
+	err := scanner.Scan(&l.ID, &l.Name, &l.SortOrder, &archived, &shopID, &l.UncheckedCount, &l.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
+	l.Archived = archived != 0
+	if shopID.Valid {
+		l.ShopID = &shopID.Int64
+	}
 	return &l, nil
 }
 
-const listCols = `id, name, sort_order, created_at`
+const listCols = `id, name, sort_order, archived, store_id,
+	(SELECT COUNT(*) FROM grocery_items WHERE grocery_items.list_id = grocery_lists.id AND grocery_items.checked = 0),
+	created_at`
 
-func (s *GroceryStore) GetDefaultList() (*model.GroceryList, error) {
-	row := s.db.QueryRow(`SELECT ` + listCols + ` FROM grocery_lists ORDER BY sort_order ASC LIMIT 1`)
+// GetDefaultList returns the first list in the household that is not
+// archived, or nil if there is none.
+func (s *GroceryStore) GetDefaultList(householdID int64) (*model.GroceryList, error) {
+	row := s.db.QueryRow(`SELECT `+listCols+` FROM grocery_lists WHERE household_id = ? AND archived = 0 ORDER BY sort_order ASC, id ASC LIMIT 1`, householdID)
 	l, err := scanList(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -72,6 +347,139 @@ func (s *GroceryStore) GetDefaultList() (*model.GroceryList, error) {
 	return l, nil
 }
 
+func (s *GroceryStore) GetListByID(id, householdID int64) (*model.GroceryList, error) {
+	row := s.db.QueryRow(`SELECT `+listCols+` FROM grocery_lists WHERE id = ? AND household_id = ?`, id, householdID)
+	l, err := scanList(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get list: %w", err)
+	}
+	return l, nil
+}
+
+// ListLists returns the household's lists in order, archived lists last.
+func (s *GroceryStore) ListLists(householdID int64) ([]model.GroceryList, error) {
+	rows, err := s.db.Query(`SELECT `+listCols+` FROM grocery_lists WHERE household_id = ? ORDER BY archived ASC, sort_order ASC, id ASC`, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list lists: %w", err)
+	}
+	defer rows.Close()
+
+	var lists []model.GroceryList
+	for rows.Next() {
+		l, err := scanList(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan list: %w", err)
+		}
+		lists = append(lists, *l)
+	}
+	return lists, rows.Err()
+}
+
+// CreateList adds a list after the household's existing ones.
+func (s *GroceryStore) CreateList(householdID int64, name string) (*model.GroceryList, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO grocery_lists (household_id, name, sort_order)
+		 SELECT ?, ?, COALESCE(MAX(sort_order) + 1, 0) FROM grocery_lists WHERE household_id = ?`,
+		householdID, name, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert list: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("last insert id: %w", err)
+	}
+	return s.GetListByID(id, householdID)
+}
+
+func (s *GroceryStore) RenameList(id, householdID int64, name string) (*model.GroceryList, error) {
+	_, err := s.db.Exec(`UPDATE grocery_lists SET name = ? WHERE id = ? AND household_id = ?`, name, id, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("rename list: %w", err)
+	}
+	return s.GetListByID(id, householdID)
+}
+
+// SetListArchived archives or restores a list. It returns
+// ErrLastGroceryList rather than archive the household's only active list.
+func (s *GroceryStore) SetListArchived(id, householdID int64, archived bool) (*model.GroceryList, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if archived {
+		if err := checkOtherActiveList(tx, id, householdID); err != nil {
+			return nil, err
+		}
+	}
+	a := 0
+	if archived {
+		a = 1
+	}
+	if _, err := tx.Exec(`UPDATE grocery_lists SET archived = ? WHERE id = ? AND household_id = ?`, a, id, householdID); err != nil {
+		return nil, fmt.Errorf("archive list: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetListByID(id, householdID)
+}
+
+// DeleteList deletes a list and its items. It returns ErrLastGroceryList
+// rather than delete the household's only active list.
+func (s *GroceryStore) DeleteList(id, householdID int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkOtherActiveList(tx, id, householdID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM grocery_lists WHERE id = ? AND household_id = ?`, id, householdID); err != nil {
+		return fmt.Errorf("delete list: %w", err)
+	}
+	return tx.Commit()
+}
+
+// checkOtherActiveList returns ErrLastGroceryList if list id is the only
+// list in the household that is not archived.
+func checkOtherActiveList(tx *sql.Tx, id, householdID int64) error {
+	var others int
+	err := tx.QueryRow(
+		`SELECT COUNT(*) FROM grocery_lists WHERE household_id = ? AND archived = 0 AND id != ?`,
+		householdID, id,
+	).Scan(&others)
+	if err != nil {
+		return fmt.Errorf("count lists: %w", err)
+	}
+	if others == 0 {
+		return ErrLastGroceryList
+	}
+	return nil
+}
+
+func (s *GroceryStore) UpdateListSortOrder(householdID int64, ids []int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, id := range ids {
+		if _, err := tx.Exec(`UPDATE grocery_lists SET sort_order = ? WHERE id = ? AND household_id = ?`, i, id, householdID); err != nil {
+			return fmt.Errorf("update sort order: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
 // --- Item methods ---
 
 func scanItem(scanner interface{ Scan(...any) error }) (*model.GroceryItem, error) {
@@ -104,8 +512,12 @@ func scanItem(scanner interface{ Scan(...any) error }) (*model.GroceryItem, erro
 
 const itemCols = `id, list_id, name, quantity, unit, notes, category, checked, checked_by, checked_at, added_by, sort_order, created_at`
 
-func (s *GroceryStore) GetItemByID(id int64) (*model.GroceryItem, error) {
-	row := s.db.QueryRow(`SELECT `+itemCols+` FROM grocery_items WHERE id = ?`, id)
+// householdItems restricts a grocery_items query to items whose parent list
+// belongs to the given household.
+const householdItems = `list_id IN (SELECT id FROM grocery_lists WHERE household_id = ?)`
+
+func (s *GroceryStore) GetItemByID(id, householdID int64) (*model.GroceryItem, error) {
+	row := s.db.QueryRow(`SELECT `+itemCols+` FROM grocery_items WHERE id = ? AND `+householdItems, id, householdID)
 	item, err := scanItem(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -116,30 +528,146 @@ func (s *GroceryStore) GetItemByID(id int64) (*model.GroceryItem, error) {
 	return item, nil
 }
 
-func (s *GroceryStore) CreateItem(listID int64, name, quantity, unit, notes, category string, addedBy *int64) (*model.GroceryItem, error) {
+// CreateItem adds an item to a list in the given household, or merges it
+// into one by the same name that is still to buy; see AddItem.
+// It returns nil if the list does not belong to the household.
+func (s *GroceryStore) CreateItem(listID, householdID int64, name, quantity, unit, notes, category string, addedBy *int64) (*model.GroceryItem, error) {
+	item, _, err := s.AddItem(listID, householdID, name, quantity, unit, notes, category, addedBy)
+	return item, err
+}
+
+// AddItem adds an item to a list in the given household. If an unchecked
+// item with the same name is already on the list, the new quantity is
+// added to it instead, when the two can be added up, and merged reports
+// that it was. The existing item keeps its category.
+// It returns nil if the list does not belong to the household.
+func (s *GroceryStore) AddItem(listID, householdID int64, name, quantity, unit, notes, category string, addedBy *int64) (item *model.GroceryItem, merged bool, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := mergeItem(tx, listID, householdID, name, quantity, unit, notes)
+	if err != nil {
+		return nil, false, err
+	}
+	merged = id != 0
+	if !merged {
+		if id, err = insertItem(tx, listID, householdID, name, quantity, unit, notes, category, addedBy); err != nil || id == 0 {
+			return nil, false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+
+	item, err = s.GetItemByID(id, householdID)
+	return item, merged, err
+}
+
+// mergeItem adds quantity to an unchecked item on the list with the same
+// name, and returns its id, or 0 if there is none it can be added to.
+func mergeItem(tx *sql.Tx, listID, householdID int64, name, quantity, unit, notes string) (int64, error) {
+	rows, err := tx.Query(
+		`SELECT id, name, quantity, unit, notes FROM grocery_items
+		 WHERE list_id = ? AND checked = 0 AND `+householdItems+` ORDER BY created_at ASC, id ASC`,
+		listID, householdID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("list unchecked items: %w", err)
+	}
+	type existing struct {
+		id                          int64
+		name, quantity, unit, notes string
+	}
+	var matches []existing
+	key := grocery.ItemKey(name)
+	for rows.Next() {
+		var e existing
+		if err := rows.Scan(&e.id, &e.name, &e.quantity, &e.unit, &e.notes); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan item: %w", err)
+		}
+		if grocery.ItemKey(e.name) == key {
+			matches = append(matches, e)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, e := range matches {
+		q, u, ok := grocery.MergeQuantities(e.quantity, e.unit, quantity, unit)
+		if !ok {
+			continue
+		}
+		_, err := tx.Exec(`UPDATE grocery_items SET quantity = ?, unit = ?, notes = ? WHERE id = ?`, q, u, mergeNotes(e.notes, notes), e.id)
+		if err != nil {
+			return 0, fmt.Errorf("merge item: %w", err)
+		}
+		return e.id, nil
+	}
+	return 0, nil
+}
+
+// mergeNotes adds notes to an item's existing notes, unless it already
+// has them.
+func mergeNotes(existing, notes string) string {
+	existing, notes = strings.TrimSpace(existing), strings.TrimSpace(notes)
+	switch {
+	case notes == "" || strings.Contains(strings.ToLower(existing), strings.ToLower(notes)):
+		return existing
+	case existing == "":
+		return notes
+	}
+	return existing + "; " + notes
+}
+
+// insertItem adds a new item to the list and returns its id, or 0 if the
+// list does not belong to the household.
+func insertItem(tx *sql.Tx, listID, householdID int64, name, quantity, unit, notes, category string, addedBy *int64) (int64, error) {
 	var aBy sql.NullInt64
 	if addedBy != nil {
 		aBy = sql.NullInt64{Int64: *addedBy, Valid: true}
 	}
 
-	result, err := s.db.Exec(
-		`INSERT INTO grocery_items (list_id, name, quantity, unit, notes, category, added_by) VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		listID, name, quantity, unit, notes, category, aBy,
+	result, err := tx.Exec(
+		`INSERT INTO grocery_items (list_id, name, quantity, unit, notes, category, added_by)
+		 SELECT id, ?, ?, ?, ?, ?, ? FROM grocery_lists WHERE id = ? AND household_id = ?`,
+		name, quantity, unit, notes, category, aBy, listID, householdID,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("insert item: %w", err)
+		return 0, fmt.Errorf("insert item: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return 0, nil
 	}
 	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("last insert id: %w", err)
+		return 0, fmt.Errorf("last insert id: %w", err)
 	}
-	return s.GetItemByID(id)
+	return id, nil
 }
 
-func (s *GroceryStore) ListItemsByList(listID int64) ([]model.GroceryItem, error) {
+// walkingOrder is an item's category's place in the layout of its list's
+// store, or NULL if the list has no store or the store has no place for it.
+const walkingOrder = `(SELECT ss.sort_order FROM grocery_store_sections ss
+	JOIN grocery_lists gl ON gl.store_id = ss.store_id
+	JOIN grocery_categories gc ON gc.id = ss.category_id AND gc.household_id = gl.household_id
+	WHERE gl.id = grocery_items.list_id AND gc.name = grocery_items.category)`
+
+// ListItemsByList returns a list's items, unchecked first. They are sorted
+// in the walking order of the list's store, if it has one, and otherwise
+// by category.
+func (s *GroceryStore) ListItemsByList(listID, householdID int64) ([]model.GroceryItem, error) {
 	rows, err := s.db.Query(
-		`SELECT `+itemCols+` FROM grocery_items WHERE list_id = ? ORDER BY checked ASC, category ASC, sort_order ASC, created_at ASC`,
-		listID,
+		`SELECT `+itemCols+` FROM grocery_items WHERE list_id = ? AND `+householdItems+`
+		 ORDER BY checked ASC, `+walkingOrder+` IS NULL, `+walkingOrder+` ASC, category ASC, sort_order ASC, created_at ASC`,
+		listID, householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("list items: %w", err)
@@ -157,27 +685,85 @@ func (s *GroceryStore) ListItemsByList(listID int64) ([]model.GroceryItem, error
 	return items, rows.Err()
 }
 
-func (s *GroceryStore) UpdateItem(id int64, name, quantity, unit, notes, category string) (*model.GroceryItem, error) {
+func (s *GroceryStore) UpdateItem(id, householdID int64, name, quantity, unit, notes, category string) (*model.GroceryItem, error) {
 	_, err := s.db.Exec(
-		`UPDATE grocery_items SET name = ?, quantity = ?, unit = ?, notes = ?, category = ? WHERE id = ?`,
-		name, quantity, unit, notes, category, id,
+		`UPDATE grocery_items SET name = ?, quantity = ?, unit = ?, notes = ?, category = ? WHERE id = ? AND `+householdItems,
+		name, quantity, unit, notes, category, id, householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("update item: %w", err)
 	}
-	return s.GetItemByID(id)
+	return s.GetItemByID(id, householdID)
+}
+
+// MoveItem moves an item to another list in the same household. Like
+// AddItem, an item still to buy is merged into the same one already on the
+// list, and merged reports that it was: the moved item is then deleted and
+// the one it was merged into is returned. It returns nil if either the item
+// or the list is not in the household, and ErrArchivedGroceryList if the
+// list is archived.
+func (s *GroceryStore) MoveItem(id, householdID, listID int64) (item *model.GroceryItem, merged bool, err error) {
+	existing, err := s.GetItemByID(id, householdID)
+	if err != nil || existing == nil {
+		return nil, false, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var archived bool
+	err = tx.QueryRow(`SELECT archived FROM grocery_lists WHERE id = ? AND household_id = ?`, listID, householdID).Scan(&archived)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("get list: %w", err)
+	}
+	if archived {
+		return nil, false, ErrArchivedGroceryList
+	}
+	if existing.ListID == listID {
+		return existing, false, nil
+	}
+
+	var mergedID int64
+	if !existing.Checked {
+		mergedID, err = mergeItem(tx, listID, householdID, existing.Name, existing.Quantity, existing.Unit, existing.Notes)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if mergedID != 0 {
+		if _, err := tx.Exec(`DELETE FROM grocery_items WHERE id = ?`, id); err != nil {
+			return nil, false, fmt.Errorf("delete merged item: %w", err)
+		}
+		id = mergedID
+	} else if _, err := tx.Exec(`UPDATE grocery_items SET list_id = ? WHERE id = ?`, listID, id); err != nil {
+		return nil, false, fmt.Errorf("move item: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+
+	item, err = s.GetItemByID(id, householdID)
+	return item, mergedID != 0, err
 }
 
-func (s *GroceryStore) DeleteItem(id int64) error {
-	_, err := s.db.Exec(`DELETE FROM grocery_items WHERE id = ?`, id)
+func (s *GroceryStore) DeleteItem(id, householdID int64) error {
+	_, err := s.db.Exec(`DELETE FROM grocery_items WHERE id = ? AND `+householdItems, id, householdID)
 	if err != nil {
 		return fmt.Errorf("delete item: %w", err)
 	}
 	return nil
 }
 
-func (s *GroceryStore) ToggleChecked(id int64, checkedBy *int64) (*model.GroceryItem, error) {
-	item, err := s.GetItemByID(id)
+// ToggleChecked checks an item off or puts it back on the list. Checking
+// an item off records it as purchased; unchecking it takes that back.
+func (s *GroceryStore) ToggleChecked(id, householdID int64, checkedBy *int64) (*model.GroceryItem, error) {
+	item, err := s.GetItemByID(id, householdID)
 	if err != nil {
 		return nil, err
 	}
@@ -185,33 +771,61 @@ func (s *GroceryStore) ToggleChecked(id int64, checkedBy *int64) (*model.Grocery
 		return nil, nil
 	}
 
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
 	if item.Checked {
 		// Uncheck
-		_, err = s.db.Exec(
+		if _, err := tx.Exec(
 			`UPDATE grocery_items SET checked = 0, checked_by = NULL, checked_at = NULL WHERE id = ?`,
 			id,
-		)
+		); err != nil {
+			return nil, fmt.Errorf("toggle checked: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM grocery_purchases WHERE item_id = ?`, id); err != nil {
+			return nil, fmt.Errorf("delete purchase: %w", err)
+		}
 	} else {
 		// Check
 		var cBy sql.NullInt64
 		if checkedBy != nil {
 			cBy = sql.NullInt64{Int64: *checkedBy, Valid: true}
 		}
-		_, err = s.db.Exec(
+		if _, err := tx.Exec(
 			`UPDATE grocery_items SET checked = 1, checked_by = ?, checked_at = ? WHERE id = ?`,
 			cBy, time.Now().UTC(), id,
-		)
+		); err != nil {
+			return nil, fmt.Errorf("toggle checked: %w", err)
+		}
+		if err := recordPurchases(tx, householdID, `id = ?`, id); err != nil {
+			return nil, err
+		}
 	}
-	if err != nil {
-		return nil, fmt.Errorf("toggle checked: %w", err)
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
 	}
-	return s.GetItemByID(id)
+	return s.GetItemByID(id, householdID)
 }
 
-func (s *GroceryStore) ClearChecked(listID int64) (int64, error) {
-	result, err := s.db.Exec(
-		`DELETE FROM grocery_items WHERE list_id = ? AND checked = 1`,
-		listID,
+// ClearChecked deletes a list's checked items. They stay in the purchase
+// history.
+func (s *GroceryStore) ClearChecked(listID, householdID int64) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Items checked before purchases were recorded have none yet.
+	if err := recordPurchases(tx, householdID, `list_id = ? AND checked = 1`, listID); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec(
+		`DELETE FROM grocery_items WHERE list_id = ? AND `+householdItems+` AND checked = 1`,
+		listID, householdID,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("clear checked: %w", err)
@@ -220,14 +834,66 @@ func (s *GroceryStore) ClearChecked(listID int64) (int64, error) {
 	if err != nil {
 		return 0, fmt.Errorf("rows affected: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
 	return count, nil
 }
 
-func (s *GroceryStore) CountUnchecked(listID int64) (int, error) {
+// recordPurchases adds a purchase for each checked item in the household
+// matching where that does not have one yet.
+func recordPurchases(tx *sql.Tx, householdID int64, where string, args ...any) error {
+	_, err := tx.Exec(
+		`INSERT INTO grocery_purchases (household_id, list_id, item_id, name, category, quantity, unit, purchased_by, purchased_at)
+		 SELECT ?, list_id, id, name, category, quantity, unit, checked_by, COALESCE(checked_at, datetime('now'))
+		 FROM grocery_items
+		 WHERE `+where+` AND checked = 1 AND `+householdItems+`
+		 AND NOT EXISTS (SELECT 1 FROM grocery_purchases p WHERE p.item_id = grocery_items.id)`,
+		append(append([]any{householdID}, args...), householdID)...,
+	)
+	if err != nil {
+		return fmt.Errorf("record purchases: %w", err)
+	}
+	return nil
+}
+
+// ListPurchases returns what was bought from a list since the given time,
+// oldest first.
+func (s *GroceryStore) ListPurchases(listID, householdID int64, since time.Time) ([]model.GroceryPurchase, error) {
+	rows, err := s.db.Query(
+		`SELECT id, list_id, name, category, quantity, unit, purchased_by, purchased_at
+		 FROM grocery_purchases WHERE list_id = ? AND household_id = ? AND purchased_at >= ?
+		 ORDER BY purchased_at ASC, id ASC`,
+		listID, householdID, since.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list purchases: %w", err)
+	}
+	defer rows.Close()
+
+	var purchases []model.GroceryPurchase
+	for rows.Next() {
+		var p model.GroceryPurchase
+		var listID, purchasedBy sql.NullInt64
+		if err := rows.Scan(&p.ID, &listID, &p.Name, &p.Category, &p.Quantity, &p.Unit, &purchasedBy, &p.PurchasedAt); err != nil {
+			return nil, fmt.Errorf("scan purchase: %w", err)
+		}
+		if listID.Valid {
+			p.ListID = &listID.Int64
+		}
+		if purchasedBy.Valid {
+			p.PurchasedBy = &purchasedBy.Int64
+		}
+		purchases = append(purchases, p)
+	}
+	return purchases, rows.Err()
+}
+
+func (s *GroceryStore) CountUnchecked(listID, householdID int64) (int, error) {
 	var count int
 	err := s.db.QueryRow(
-		`SELECT COUNT(*) FROM grocery_items WHERE list_id = ? AND checked = 0`,
-		listID,
+		`SELECT COUNT(*) FROM grocery_items WHERE list_id = ? AND `+householdItems+` AND checked = 0`,
+		listID, householdID,
 	).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("count unchecked: %w", err)
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+const shopCols = `id, name, created_at`
+
+func scanShop(scanner interface{ Scan(...any) error }) (*model.GroceryShop, error) {
+	var shop model.GroceryShop
+	if err := scanner.Scan(&shop.ID, &shop.Name, &shop.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &shop, nil
+}
+
+// ListShops returns the household's stores by name, without their layouts.
+func (s *GroceryStore) ListShops(householdID int64) ([]model.GroceryShop, error) {
+	rows, err := s.db.Query(`SELECT `+shopCols+` FROM grocery_stores WHERE household_id = ? ORDER BY name COLLATE NOCASE ASC, id ASC`, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list stores: %w", err)
+	}
+	defer rows.Close()
+
+	var shops []model.GroceryShop
+	for rows.Next() {
+		shop, err := scanShop(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan store: %w", err)
+		}
+		shops = append(shops, *shop)
+	}
+	return shops, rows.Err()
+}
+
+// GetShop returns a store with its layout: the categories it has placed in
+// walking order, then the rest in the household's category order.
+func (s *GroceryStore) GetShop(id, householdID int64) (*model.GroceryShop, error) {
+	row := s.db.QueryRow(`SELECT `+shopCols+` FROM grocery_stores WHERE id = ? AND household_id = ?`, id, householdID)
+	shop, err := scanShop(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get store: %w", err)
+	}
+
+	rows, err := s.db.Query(
+		`SELECT c.id, c.name, COALESCE(ss.aisle, '')
+		 FROM grocery_categories c
+		 LEFT JOIN grocery_store_sections ss ON ss.category_id = c.id AND ss.store_id = ?
+		 WHERE c.household_id = ?
+		 ORDER BY ss.sort_order IS NULL, ss.sort_order ASC, c.sort_order ASC, c.name ASC`,
+		id, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list store sections: %w", err)
+	}
+	defer rows.Close()
+
+	shop.Sections = []model.ShopSection{}
+	for rows.Next() {
+		var section model.ShopSection
+		if err := rows.Scan(&section.CategoryID, &section.Category, &section.Aisle); err != nil {
+			return nil, fmt.Errorf("scan store section: %w", err)
+		}
+		shop.Sections = append(shop.Sections, section)
+	}
+	return shop, rows.Err()
+}
+
+func (s *GroceryStore) CreateShop(householdID int64, name string) (*model.GroceryShop, error) {
+	result, err := s.db.Exec(`INSERT INTO grocery_stores (household_id, name) VALUES (?, ?)`, householdID, name)
+	if err != nil {
+		return nil, fmt.Errorf("insert store: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("last insert id: %w", err)
+	}
+	return s.GetShop(id, householdID)
+}
+
+func (s *GroceryStore) RenameShop(id, householdID int64, name string) (*model.GroceryShop, error) {
+	_, err := s.db.Exec(`UPDATE grocery_stores SET name = ? WHERE id = ? AND household_id = ?`, name, id, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("rename store: %w", err)
+	}
+	return s.GetShop(id, householdID)
+}
+
+// DeleteShop deletes a store. Lists shopped there go back to being sorted
+// by category.
+func (s *GroceryStore) DeleteShop(id, householdID int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE grocery_lists SET store_id = NULL WHERE store_id = ? AND household_id = ?`, id, householdID); err != nil {
+		return fmt.Errorf("unlink lists: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM grocery_store_sections WHERE store_id IN (SELECT id FROM grocery_stores WHERE id = ? AND household_id = ?)`, id, householdID); err != nil {
+		return fmt.Errorf("delete store sections: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM grocery_stores WHERE id = ? AND household_id = ?`, id, householdID); err != nil {
+		return fmt.Errorf("delete store: %w", err)
+	}
+	return tx.Commit()
+}
+
+// SetShopSections replaces a store's layout with sections, in walking
+// order. Categories that are not the household's are ignored, and those
+// left out are walked past last.
+func (s *GroceryStore) SetShopSections(id, householdID int64, sections []model.ShopSection) (*model.GroceryShop, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM grocery_stores WHERE id = ? AND household_id = ?`, id, householdID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("check store: %w", err)
+	}
+	if exists == 0 {
+		return nil, nil
+	}
+
+	if _, err := tx.Exec(`DELETE FROM grocery_store_sections WHERE store_id = ?`, id); err != nil {
+		return nil, fmt.Errorf("clear store sections: %w", err)
+	}
+	for i, section := range sections {
+		_, err := tx.Exec(
+			`INSERT OR REPLACE INTO grocery_store_sections (store_id, category_id, sort_order, aisle)
+			 SELECT ?, id, ?, ? FROM grocery_categories WHERE id = ? AND household_id = ?`,
+			id, i, section.Aisle, section.CategoryID, householdID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("insert store section: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetShop(id, householdID)
+}
+
+// SetListShop sets the store a list is shopped at, or clears it if shopID
+// is nil. It returns nil if the list or store is not the household's.
+func (s *GroceryStore) SetListShop(listID, householdID int64, shopID *int64) (*model.GroceryList, error) {
+	result, err := s.db.Exec(
+		`UPDATE grocery_lists SET store_id = ? WHERE id = ? AND household_id = ?
+		 AND (? IS NULL OR EXISTS (SELECT 1 FROM grocery_stores WHERE id = ? AND household_id = ?))`,
+		shopID, listID, householdID, shopID, shopID, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("set list store: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return nil, nil
+	}
+	return s.GetListByID(listID, householdID)
+}
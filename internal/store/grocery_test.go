@@ -1,9 +1,13 @@
 package store
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/dukerupert/gamwich/internal/database"
+	"github.com/dukerupert/gamwich/internal/model"
 )
 
 func setupGroceryTestDB(t *testing.T) (*GroceryStore, *FamilyMemberStore) {
@@ -22,7 +26,7 @@ func setupGroceryTestDB(t *testing.T) (*GroceryStore, *FamilyMemberStore) {
 func TestCategorySeedData(t *testing.T) {
 	gs, _ := setupGroceryTestDB(t)
 
-	categories, err := gs.ListCategories()
+	categories, err := gs.ListCategories(testHouseholdID)
 	if err != nil {
 		t.Fatalf("list categories: %v", err)
 	}
@@ -41,7 +45,7 @@ func TestCategorySeedData(t *testing.T) {
 func TestDefaultListSeedData(t *testing.T) {
 	gs, _ := setupGroceryTestDB(t)
 
-	list, err := gs.GetDefaultList()
+	list, err := gs.GetDefaultList(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get default list: %v", err)
 	}
@@ -56,11 +60,11 @@ func TestDefaultListSeedData(t *testing.T) {
 func TestItemCRUD(t *testing.T) {
 	gs, ms := setupGroceryTestDB(t)
 
-	list, _ := gs.GetDefaultList()
-	member, _ := ms.Create("Alice", "#FF0000", "A")
+	list, _ := gs.GetDefaultList(testHouseholdID)
+	member, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
 
 	// Create
-	item, err := gs.CreateItem(list.ID, "Milk", "1", "gallon", "whole milk", "Dairy", &member.ID)
+	item, err := gs.CreateItem(list.ID, testHouseholdID, "Milk", "1", "gallon", "whole milk", "Dairy", &member.ID)
 	if err != nil {
 		t.Fatalf("create item: %v", err)
 	}
@@ -84,7 +88,7 @@ func TestItemCRUD(t *testing.T) {
 	}
 
 	// GetByID
-	got, err := gs.GetItemByID(item.ID)
+	got, err := gs.GetItemByID(item.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get item: %v", err)
 	}
@@ -93,7 +97,7 @@ func TestItemCRUD(t *testing.T) {
 	}
 
 	// Update
-	updated, err := gs.UpdateItem(item.ID, "Whole Milk", "2", "gallons", "organic", "Dairy")
+	updated, err := gs.UpdateItem(item.ID, testHouseholdID, "Whole Milk", "2", "gallons", "organic", "Dairy")
 	if err != nil {
 		t.Fatalf("update item: %v", err)
 	}
@@ -105,7 +109,7 @@ func TestItemCRUD(t *testing.T) {
 	}
 
 	// List
-	items, err := gs.ListItemsByList(list.ID)
+	items, err := gs.ListItemsByList(list.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("list items: %v", err)
 	}
@@ -114,10 +118,10 @@ func TestItemCRUD(t *testing.T) {
 	}
 
 	// Delete
-	if err := gs.DeleteItem(item.ID); err != nil {
+	if err := gs.DeleteItem(item.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete item: %v", err)
 	}
-	got, err = gs.GetItemByID(item.ID)
+	got, err = gs.GetItemByID(item.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get deleted item: %v", err)
 	}
@@ -129,7 +133,7 @@ func TestItemCRUD(t *testing.T) {
 func TestItemGetByIDNotFound(t *testing.T) {
 	gs, _ := setupGroceryTestDB(t)
 
-	got, err := gs.GetItemByID(9999)
+	got, err := gs.GetItemByID(9999, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get item: %v", err)
 	}
@@ -141,14 +145,14 @@ func TestItemGetByIDNotFound(t *testing.T) {
 func TestItemListOrdering(t *testing.T) {
 	gs, _ := setupGroceryTestDB(t)
 
-	list, _ := gs.GetDefaultList()
+	list, _ := gs.GetDefaultList(testHouseholdID)
 
 	// Create items in different categories
-	gs.CreateItem(list.ID, "Chicken", "", "", "", "Meat & Seafood", nil)
-	gs.CreateItem(list.ID, "Apples", "", "", "", "Produce", nil) // earlier sort
-	gs.CreateItem(list.ID, "Bread", "", "", "", "Bakery", nil)
+	gs.CreateItem(list.ID, testHouseholdID, "Chicken", "", "", "", "Meat & Seafood", nil)
+	gs.CreateItem(list.ID, testHouseholdID, "Apples", "", "", "", "Produce", nil) // earlier sort
+	gs.CreateItem(list.ID, testHouseholdID, "Bread", "", "", "", "Bakery", nil)
 
-	items, err := gs.ListItemsByList(list.ID)
+	items, err := gs.ListItemsByList(list.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("list items: %v", err)
 	}
@@ -171,15 +175,15 @@ func TestItemListOrdering(t *testing.T) {
 func TestItemCheckedAfterUnchecked(t *testing.T) {
 	gs, _ := setupGroceryTestDB(t)
 
-	list, _ := gs.GetDefaultList()
+	list, _ := gs.GetDefaultList(testHouseholdID)
 
-	item1, _ := gs.CreateItem(list.ID, "Milk", "", "", "", "Dairy", nil)
-	gs.CreateItem(list.ID, "Bread", "", "", "", "Bakery", nil)
+	item1, _ := gs.CreateItem(list.ID, testHouseholdID, "Milk", "", "", "", "Dairy", nil)
+	gs.CreateItem(list.ID, testHouseholdID, "Bread", "", "", "", "Bakery", nil)
 
 	// Check item1
-	gs.ToggleChecked(item1.ID, nil)
+	gs.ToggleChecked(item1.ID, testHouseholdID, nil)
 
-	items, err := gs.ListItemsByList(list.ID)
+	items, err := gs.ListItemsByList(list.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("list items: %v", err)
 	}
@@ -199,13 +203,13 @@ func TestItemCheckedAfterUnchecked(t *testing.T) {
 func TestToggleChecked(t *testing.T) {
 	gs, ms := setupGroceryTestDB(t)
 
-	list, _ := gs.GetDefaultList()
-	member, _ := ms.Create("Bob", "#0000FF", "B")
+	list, _ := gs.GetDefaultList(testHouseholdID)
+	member, _ := ms.Create(testHouseholdID, "Bob", "#0000FF", "B")
 
-	item, _ := gs.CreateItem(list.ID, "Eggs", "", "", "", "Dairy", nil)
+	item, _ := gs.CreateItem(list.ID, testHouseholdID, "Eggs", "", "", "", "Dairy", nil)
 
 	// Check
-	checked, err := gs.ToggleChecked(item.ID, &member.ID)
+	checked, err := gs.ToggleChecked(item.ID, testHouseholdID, &member.ID)
 	if err != nil {
 		t.Fatalf("toggle check: %v", err)
 	}
@@ -220,7 +224,7 @@ func TestToggleChecked(t *testing.T) {
 	}
 
 	// Uncheck
-	unchecked, err := gs.ToggleChecked(item.ID, &member.ID)
+	unchecked, err := gs.ToggleChecked(item.ID, testHouseholdID, &member.ID)
 	if err != nil {
 		t.Fatalf("toggle uncheck: %v", err)
 	}
@@ -238,7 +242,7 @@ func TestToggleChecked(t *testing.T) {
 func TestToggleCheckedNotFound(t *testing.T) {
 	gs, _ := setupGroceryTestDB(t)
 
-	got, err := gs.ToggleChecked(9999, nil)
+	got, err := gs.ToggleChecked(9999, testHouseholdID, nil)
 	if err != nil {
 		t.Fatalf("toggle checked: %v", err)
 	}
@@ -250,16 +254,16 @@ func TestToggleCheckedNotFound(t *testing.T) {
 func TestClearChecked(t *testing.T) {
 	gs, _ := setupGroceryTestDB(t)
 
-	list, _ := gs.GetDefaultList()
+	list, _ := gs.GetDefaultList(testHouseholdID)
 
-	item1, _ := gs.CreateItem(list.ID, "Milk", "", "", "", "Dairy", nil)
-	item2, _ := gs.CreateItem(list.ID, "Bread", "", "", "", "Bakery", nil)
-	gs.CreateItem(list.ID, "Eggs", "", "", "", "Dairy", nil) // unchecked
+	item1, _ := gs.CreateItem(list.ID, testHouseholdID, "Milk", "", "", "", "Dairy", nil)
+	item2, _ := gs.CreateItem(list.ID, testHouseholdID, "Bread", "", "", "", "Bakery", nil)
+	gs.CreateItem(list.ID, testHouseholdID, "Eggs", "", "", "", "Dairy", nil) // unchecked
 
-	gs.ToggleChecked(item1.ID, nil)
-	gs.ToggleChecked(item2.ID, nil)
+	gs.ToggleChecked(item1.ID, testHouseholdID, nil)
+	gs.ToggleChecked(item2.ID, testHouseholdID, nil)
 
-	count, err := gs.ClearChecked(list.ID)
+	count, err := gs.ClearChecked(list.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("clear checked: %v", err)
 	}
@@ -267,7 +271,7 @@ func TestClearChecked(t *testing.T) {
 		t.Errorf("cleared count = %d, want 2", count)
 	}
 
-	items, _ := gs.ListItemsByList(list.ID)
+	items, _ := gs.ListItemsByList(list.ID, testHouseholdID)
 	if len(items) != 1 {
 		t.Fatalf("expected 1 remaining item, got %d", len(items))
 	}
@@ -279,13 +283,13 @@ func TestClearChecked(t *testing.T) {
 func TestCountUnchecked(t *testing.T) {
 	gs, _ := setupGroceryTestDB(t)
 
-	list, _ := gs.GetDefaultList()
+	list, _ := gs.GetDefaultList(testHouseholdID)
 
-	item1, _ := gs.CreateItem(list.ID, "Milk", "", "", "", "Dairy", nil)
-	gs.CreateItem(list.ID, "Bread", "", "", "", "Bakery", nil)
-	gs.CreateItem(list.ID, "Eggs", "", "", "", "Dairy", nil)
+	item1, _ := gs.CreateItem(list.ID, testHouseholdID, "Milk", "", "", "", "Dairy", nil)
+	gs.CreateItem(list.ID, testHouseholdID, "Bread", "", "", "", "Bakery", nil)
+	gs.CreateItem(list.ID, testHouseholdID, "Eggs", "", "", "", "Dairy", nil)
 
-	count, err := gs.CountUnchecked(list.ID)
+	count, err := gs.CountUnchecked(list.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("count unchecked: %v", err)
 	}
@@ -293,9 +297,9 @@ func TestCountUnchecked(t *testing.T) {
 		t.Errorf("count = %d, want 3", count)
 	}
 
-	gs.ToggleChecked(item1.ID, nil)
+	gs.ToggleChecked(item1.ID, testHouseholdID, nil)
 
-	count, err = gs.CountUnchecked(list.ID)
+	count, err = gs.CountUnchecked(list.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("count unchecked: %v", err)
 	}
@@ -307,9 +311,9 @@ func TestCountUnchecked(t *testing.T) {
 func TestDeleteListCascadesItems(t *testing.T) {
 	gs, _ := setupGroceryTestDB(t)
 
-	list, _ := gs.GetDefaultList()
-	gs.CreateItem(list.ID, "Milk", "", "", "", "Dairy", nil)
-	gs.CreateItem(list.ID, "Bread", "", "", "", "Bakery", nil)
+	list, _ := gs.GetDefaultList(testHouseholdID)
+	gs.CreateItem(list.ID, testHouseholdID, "Milk", "", "", "", "Dairy", nil)
+	gs.CreateItem(list.ID, testHouseholdID, "Bread", "", "", "", "Bakery", nil)
 
 	// Delete list directly via SQL
 	_, err := gs.db.Exec(`DELETE FROM grocery_lists WHERE id = ?`, list.ID)
@@ -317,7 +321,7 @@ func TestDeleteListCascadesItems(t *testing.T) {
 		t.Fatalf("delete list: %v", err)
 	}
 
-	items, _ := gs.ListItemsByList(list.ID)
+	items, _ := gs.ListItemsByList(list.ID, testHouseholdID)
 	if len(items) != 0 {
 		t.Errorf("expected 0 items after cascade, got %d", len(items))
 	}
@@ -326,16 +330,16 @@ func TestDeleteListCascadesItems(t *testing.T) {
 func TestDeleteMemberSetsNullOnItems(t *testing.T) {
 	gs, ms := setupGroceryTestDB(t)
 
-	list, _ := gs.GetDefaultList()
-	member, _ := ms.Create("Charlie", "#00FF00", "C")
+	list, _ := gs.GetDefaultList(testHouseholdID)
+	member, _ := ms.Create(testHouseholdID, "Charlie", "#00FF00", "C")
 
-	item, _ := gs.CreateItem(list.ID, "Milk", "", "", "", "Dairy", &member.ID)
+	item, _ := gs.CreateItem(list.ID, testHouseholdID, "Milk", "", "", "", "Dairy", &member.ID)
 
 	// Check the item with the member
-	gs.ToggleChecked(item.ID, &member.ID)
+	gs.ToggleChecked(item.ID, testHouseholdID, &member.ID)
 
 	// Verify both FKs are set
-	got, _ := gs.GetItemByID(item.ID)
+	got, _ := gs.GetItemByID(item.ID, testHouseholdID)
 	if got.AddedBy == nil || *got.AddedBy != member.ID {
 		t.Fatalf("added_by = %v, want %d", got.AddedBy, member.ID)
 	}
@@ -344,11 +348,11 @@ func TestDeleteMemberSetsNullOnItems(t *testing.T) {
 	}
 
 	// Delete member
-	if err := ms.Delete(member.ID); err != nil {
+	if err := ms.Delete(member.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete member: %v", err)
 	}
 
-	got, err := gs.GetItemByID(item.ID)
+	got, err := gs.GetItemByID(item.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get item: %v", err)
 	}
@@ -363,9 +367,9 @@ func TestDeleteMemberSetsNullOnItems(t *testing.T) {
 func TestCreateItemNilAddedBy(t *testing.T) {
 	gs, _ := setupGroceryTestDB(t)
 
-	list, _ := gs.GetDefaultList()
+	list, _ := gs.GetDefaultList(testHouseholdID)
 
-	item, err := gs.CreateItem(list.ID, "Bread", "", "", "", "Bakery", nil)
+	item, err := gs.CreateItem(list.ID, testHouseholdID, "Bread", "", "", "", "Bakery", nil)
 	if err != nil {
 		t.Fatalf("create item: %v", err)
 	}
@@ -373,3 +377,510 @@ func TestCreateItemNilAddedBy(t *testing.T) {
 		t.Errorf("added_by should be nil, got %v", *item.AddedBy)
 	}
 }
+
+func TestGroceryHouseholdIsolation(t *testing.T) {
+	gs, _ := setupGroceryTestDB(t)
+	otherID := createTestHousehold(t, gs.db, "Other")
+
+	list, _ := gs.GetDefaultList(testHouseholdID)
+	otherList, _ := gs.GetDefaultList(otherID)
+	if otherList == nil || otherList.ID == list.ID {
+		t.Fatalf("expected a separate default list for the other household, got %+v", otherList)
+	}
+
+	item, err := gs.CreateItem(list.ID, testHouseholdID, "Milk", "", "", "", "Dairy", nil)
+	if err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+
+	if got, _ := gs.CreateItem(list.ID, otherID, "Eggs", "", "", "", "Dairy", nil); got != nil {
+		t.Error("expected nil when adding to another household's list")
+	}
+	if got, _ := gs.GetListByID(list.ID, otherID); got != nil {
+		t.Error("expected nil when reading another household's list")
+	}
+	if got, _ := gs.GetItemByID(item.ID, otherID); got != nil {
+		t.Error("expected nil when reading another household's item")
+	}
+	items, _ := gs.ListItemsByList(list.ID, otherID)
+	if len(items) != 0 {
+		t.Errorf("other household sees %d items, want 0", len(items))
+	}
+	if got, _ := gs.ToggleChecked(item.ID, otherID, nil); got != nil {
+		t.Error("expected nil when checking another household's item")
+	}
+	if err := gs.DeleteItem(item.ID, otherID); err != nil {
+		t.Fatalf("delete item: %v", err)
+	}
+
+	got, _ := gs.GetItemByID(item.ID, testHouseholdID)
+	if got == nil || got.Checked {
+		t.Errorf("item was modified by another household: %+v", got)
+	}
+	count, _ := gs.CountUnchecked(list.ID, testHouseholdID)
+	if count != 1 {
+		t.Errorf("unchecked = %d, want 1", count)
+	}
+}
+
+func TestMoveItem(t *testing.T) {
+	gs, _ := setupGroceryTestDB(t)
+
+	grocery, _ := gs.GetDefaultList(testHouseholdID)
+	costco, _ := gs.CreateList(testHouseholdID, "Costco")
+	old, _ := gs.CreateList(testHouseholdID, "Old")
+	if _, err := gs.SetListArchived(old.ID, testHouseholdID, true); err != nil {
+		t.Fatalf("archive list: %v", err)
+	}
+
+	milk, _ := gs.CreateItem(grocery.ID, testHouseholdID, "Milk", "1", "gal", "", "Dairy", nil)
+	if _, _, err := gs.MoveItem(milk.ID, testHouseholdID, old.ID); !errors.Is(err, ErrArchivedGroceryList) {
+		t.Errorf("move to archived list err = %v, want ErrArchivedGroceryList", err)
+	}
+	if got, _ := gs.GetItemByID(milk.ID, testHouseholdID); got.ListID != grocery.ID {
+		t.Errorf("item moved to archived list")
+	}
+
+	// Moving onto the same item still to buy merges the two.
+	costcoMilk, _ := gs.CreateItem(costco.ID, testHouseholdID, "milk", "2", "gal", "", "Dairy", nil)
+	moved, merged, err := gs.MoveItem(milk.ID, testHouseholdID, costco.ID)
+	if err != nil {
+		t.Fatalf("move item: %v", err)
+	}
+	if !merged || moved.ID != costcoMilk.ID || moved.Quantity != "3" || moved.Unit != "gal" {
+		t.Errorf("moved item = %+v, merged %v, want 3 gal merged into %d", moved, merged, costcoMilk.ID)
+	}
+	if got, _ := gs.GetItemByID(milk.ID, testHouseholdID); got != nil {
+		t.Errorf("merged item still exists: %+v", got)
+	}
+
+	// A checked item is moved as it is.
+	bought, _ := gs.CreateItem(grocery.ID, testHouseholdID, "Milk", "1", "gal", "", "Dairy", nil)
+	gs.ToggleChecked(bought.ID, testHouseholdID, nil)
+	moved, merged, err = gs.MoveItem(bought.ID, testHouseholdID, costco.ID)
+	if err != nil {
+		t.Fatalf("move checked item: %v", err)
+	}
+	if merged || moved.ID != bought.ID || moved.ListID != costco.ID {
+		t.Errorf("moved checked item = %+v, merged %v", moved, merged)
+	}
+}
+
+func TestGroceryLists(t *testing.T) {
+	gs, _ := setupGroceryTestDB(t)
+
+	grocery, _ := gs.GetDefaultList(testHouseholdID)
+	costco, err := gs.CreateList(testHouseholdID, "Costco")
+	if err != nil {
+		t.Fatalf("create list: %v", err)
+	}
+	if costco.SortOrder <= grocery.SortOrder || costco.Archived {
+		t.Errorf("new list = %+v, want active and after %+v", costco, grocery)
+	}
+	hardware, _ := gs.CreateList(testHouseholdID, "Hardware")
+
+	if got, _ := gs.RenameList(hardware.ID, testHouseholdID, "Hardware store"); got == nil || got.Name != "Hardware store" {
+		t.Errorf("renamed list = %+v", got)
+	}
+
+	// Unchecked counts are per list.
+	milk, _ := gs.CreateItem(grocery.ID, testHouseholdID, "Milk", "", "", "", "Dairy", nil)
+	gs.CreateItem(costco.ID, testHouseholdID, "Paper towels", "", "", "", "Household", nil)
+	checked, _ := gs.CreateItem(costco.ID, testHouseholdID, "Coffee", "", "", "", "Pantry", nil)
+	gs.ToggleChecked(checked.ID, testHouseholdID, nil)
+
+	moved, merged, err := gs.MoveItem(milk.ID, testHouseholdID, costco.ID)
+	if err != nil {
+		t.Fatalf("move item: %v", err)
+	}
+	if moved == nil || moved.ID != milk.ID || moved.ListID != costco.ID || merged {
+		t.Errorf("moved item = %+v, merged %v, want on list %d", moved, merged, costco.ID)
+	}
+
+	lists, err := gs.ListLists(testHouseholdID)
+	if err != nil {
+		t.Fatalf("list lists: %v", err)
+	}
+	counts := map[string]int{}
+	for _, l := range lists {
+		counts[l.Name] = l.UncheckedCount
+	}
+	if counts["Grocery"] != 0 || counts["Costco"] != 2 || counts["Hardware store"] != 0 {
+		t.Errorf("unchecked counts = %v", counts)
+	}
+
+	if err := gs.UpdateListSortOrder(testHouseholdID, []int64{costco.ID, grocery.ID, hardware.ID}); err != nil {
+		t.Fatalf("update sort order: %v", err)
+	}
+	if got, _ := gs.GetDefaultList(testHouseholdID); got.ID != costco.ID {
+		t.Errorf("default list = %q, want Costco", got.Name)
+	}
+
+	// Archived lists sort last and are never the default.
+	if got, err := gs.SetListArchived(costco.ID, testHouseholdID, true); err != nil || !got.Archived {
+		t.Fatalf("archive list = %+v, %v", got, err)
+	}
+	if got, _ := gs.GetDefaultList(testHouseholdID); got.ID != grocery.ID {
+		t.Errorf("default list = %q, want Grocery", got.Name)
+	}
+	lists, _ = gs.ListLists(testHouseholdID)
+	if last := lists[len(lists)-1]; last.ID != costco.ID {
+		t.Errorf("last list = %q, want archived Costco", last.Name)
+	}
+	items, _ := gs.ListItemsByList(costco.ID, testHouseholdID)
+	if len(items) != 3 {
+		t.Errorf("archived list items = %d, want 3", len(items))
+	}
+
+	// The household always keeps an active list.
+	if err := gs.DeleteList(hardware.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete list: %v", err)
+	}
+	if _, err := gs.SetListArchived(grocery.ID, testHouseholdID, true); !errors.Is(err, ErrLastGroceryList) {
+		t.Errorf("archive last list err = %v, want ErrLastGroceryList", err)
+	}
+	if err := gs.DeleteList(grocery.ID, testHouseholdID); !errors.Is(err, ErrLastGroceryList) {
+		t.Errorf("delete last list err = %v, want ErrLastGroceryList", err)
+	}
+	if _, err := gs.SetListArchived(costco.ID, testHouseholdID, false); err != nil {
+		t.Fatalf("restore list: %v", err)
+	}
+	if err := gs.DeleteList(costco.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete list: %v", err)
+	}
+	if got, _ := gs.GetItemByID(milk.ID, testHouseholdID); got != nil {
+		t.Error("items should be deleted with their list")
+	}
+}
+
+func TestGroceryListHouseholdIsolation(t *testing.T) {
+	gs, _ := setupGroceryTestDB(t)
+	otherID := createTestHousehold(t, gs.db, "Other")
+
+	list, _ := gs.GetDefaultList(testHouseholdID)
+	extra, _ := gs.CreateList(testHouseholdID, "Pharmacy")
+	otherList, _ := gs.GetDefaultList(otherID)
+	item, _ := gs.CreateItem(list.ID, testHouseholdID, "Milk", "", "", "", "Dairy", nil)
+
+	if got, _ := gs.RenameList(extra.ID, otherID, "Mine"); got != nil {
+		t.Error("expected nil when renaming another household's list")
+	}
+	if got, _, _ := gs.MoveItem(item.ID, testHouseholdID, otherList.ID); got != nil {
+		t.Error("expected nil when moving an item to another household's list")
+	}
+	if got, _, _ := gs.MoveItem(item.ID, otherID, otherList.ID); got != nil {
+		t.Error("expected nil when moving another household's item")
+	}
+	gs.DeleteList(extra.ID, otherID)
+	gs.UpdateListSortOrder(otherID, []int64{extra.ID, list.ID})
+
+	if got, _ := gs.GetListByID(extra.ID, testHouseholdID); got == nil || got.Name != "Pharmacy" || got.SortOrder != extra.SortOrder {
+		t.Errorf("list was modified by another household: %+v", got)
+	}
+	if got, _ := gs.GetItemByID(item.ID, testHouseholdID); got == nil || got.ListID != list.ID {
+		t.Errorf("item was moved by another household: %+v", got)
+	}
+	if lists, _ := gs.ListLists(otherID); len(lists) != 1 {
+		t.Errorf("other household lists = %d, want 1", len(lists))
+	}
+}
+
+func TestGroceryPurchases(t *testing.T) {
+	gs, ms := setupGroceryTestDB(t)
+
+	list, _ := gs.GetDefaultList(testHouseholdID)
+	costco, _ := gs.CreateList(testHouseholdID, "Costco")
+	member, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
+	since := time.Now().Add(-time.Hour)
+
+	eggs, _ := gs.CreateItem(list.ID, testHouseholdID, "Eggs", "12", "", "", "Dairy", nil)
+	mistake, _ := gs.CreateItem(list.ID, testHouseholdID, "Bread", "", "", "", "Bakery", nil)
+	gs.CreateItem(costco.ID, testHouseholdID, "Paper towels", "", "", "", "Household", nil)
+
+	// Checking off records a purchase; unchecking takes it back.
+	gs.ToggleChecked(eggs.ID, testHouseholdID, &member.ID)
+	gs.ToggleChecked(mistake.ID, testHouseholdID, nil)
+	gs.ToggleChecked(mistake.ID, testHouseholdID, nil)
+
+	purchases, err := gs.ListPurchases(list.ID, testHouseholdID, since)
+	if err != nil {
+		t.Fatalf("list purchases: %v", err)
+	}
+	if len(purchases) != 1 {
+		t.Fatalf("purchases = %+v, want only eggs", purchases)
+	}
+	p := purchases[0]
+	if p.Name != "Eggs" || p.Quantity != "12" || p.Category != "Dairy" || p.PurchasedBy == nil || *p.PurchasedBy != member.ID {
+		t.Errorf("purchase = %+v", p)
+	}
+
+	// Clearing keeps the history, and records items checked before it was kept.
+	old, _ := gs.CreateItem(list.ID, testHouseholdID, "Butter", "", "", "", "Dairy", nil)
+	gs.db.Exec(`UPDATE grocery_items SET checked = 1, checked_at = ? WHERE id = ?`, time.Now().UTC(), old.ID)
+	if n, err := gs.ClearChecked(list.ID, testHouseholdID); err != nil || n != 2 {
+		t.Fatalf("clear checked = %d, %v; want 2", n, err)
+	}
+	purchases, _ = gs.ListPurchases(list.ID, testHouseholdID, since)
+	if len(purchases) != 2 || purchases[1].Name != "Butter" {
+		t.Errorf("purchases after clear = %+v, want eggs and butter", purchases)
+	}
+
+	if got, _ := gs.ListPurchases(costco.ID, testHouseholdID, since); len(got) != 0 {
+		t.Errorf("Costco purchases = %d, want 0", len(got))
+	}
+	if got, _ := gs.ListPurchases(list.ID, testHouseholdID, time.Now().Add(time.Hour)); len(got) != 0 {
+		t.Errorf("purchases in the future = %d, want 0", len(got))
+	}
+	otherID := createTestHousehold(t, gs.db, "Other")
+	if got, _ := gs.ListPurchases(list.ID, otherID, since); len(got) != 0 {
+		t.Errorf("other household sees %d purchases, want 0", len(got))
+	}
+}
+
+func TestGroceryCategories(t *testing.T) {
+	gs, _ := setupGroceryTestDB(t)
+
+	list, _ := gs.GetDefaultList(testHouseholdID)
+	cheese, _ := gs.CreateItem(list.ID, testHouseholdID, "Cheese", "", "", "", "Dairy", nil)
+	gs.SetCategoryRule(testHouseholdID, "oat milk", "Dairy")
+
+	baby, err := gs.CreateCategory(testHouseholdID, "Baby")
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	if _, err := gs.CreateCategory(testHouseholdID, "baby"); !errors.Is(err, ErrCategoryExists) {
+		t.Errorf("create duplicate category err = %v, want ErrCategoryExists", err)
+	}
+
+	// Renaming a category renames it on items and rules too.
+	categories, _ := gs.ListCategories(testHouseholdID)
+	var dairy, other model.GroceryCategory
+	for _, c := range categories {
+		switch c.Name {
+		case "Dairy":
+			dairy = c
+		case model.GroceryCategoryOther:
+			other = c
+		}
+	}
+	if _, err := gs.RenameCategory(dairy.ID, testHouseholdID, "Baby"); !errors.Is(err, ErrCategoryExists) {
+		t.Errorf("rename to existing name err = %v, want ErrCategoryExists", err)
+	}
+	if got, err := gs.RenameCategory(dairy.ID, testHouseholdID, "Dairy & Eggs"); err != nil || got.Name != "Dairy & Eggs" {
+		t.Fatalf("rename category = %+v, %v", got, err)
+	}
+	if got, _ := gs.GetItemByID(cheese.ID, testHouseholdID); got.Category != "Dairy & Eggs" {
+		t.Errorf("item category = %q, want %q", got.Category, "Dairy & Eggs")
+	}
+	if rules, _ := gs.CategoryRules(testHouseholdID); rules["oat milk"] != "Dairy & Eggs" {
+		t.Errorf("rules = %v, want oat milk in Dairy & Eggs", rules)
+	}
+
+	if err := gs.UpdateCategorySortOrder(testHouseholdID, []int64{baby.ID, dairy.ID}); err != nil {
+		t.Fatalf("update sort order: %v", err)
+	}
+	if categories, _ := gs.ListCategories(testHouseholdID); categories[0].ID != baby.ID {
+		t.Errorf("first category = %q, want Baby", categories[0].Name)
+	}
+
+	// Deleting one moves its items to Other and forgets its rules.
+	if err := gs.DeleteCategory(dairy.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete category: %v", err)
+	}
+	if got, _ := gs.GetItemByID(cheese.ID, testHouseholdID); got.Category != model.GroceryCategoryOther {
+		t.Errorf("item category = %q, want Other", got.Category)
+	}
+	if rules, _ := gs.CategoryRules(testHouseholdID); len(rules) != 0 {
+		t.Errorf("rules = %v, want none", rules)
+	}
+	if err := gs.DeleteCategory(other.ID, testHouseholdID); !errors.Is(err, ErrCategoryIsOther) {
+		t.Errorf("delete Other err = %v, want ErrCategoryIsOther", err)
+	}
+	if _, err := gs.RenameCategory(other.ID, testHouseholdID, "Misc"); !errors.Is(err, ErrCategoryIsOther) {
+		t.Errorf("rename Other err = %v, want ErrCategoryIsOther", err)
+	}
+
+	otherID := createTestHousehold(t, gs.db, "Other")
+	if got, _ := gs.RenameCategory(baby.ID, otherID, "Mine"); got != nil {
+		t.Error("expected nil when renaming another household's category")
+	}
+	gs.DeleteCategory(baby.ID, otherID)
+	if got, _ := gs.GetCategoryByID(baby.ID, testHouseholdID); got == nil || got.Name != "Baby" {
+		t.Errorf("category was modified by another household: %+v", got)
+	}
+}
+
+func TestImportCategoryRules(t *testing.T) {
+	gs, _ := setupGroceryTestDB(t)
+
+	gs.SetCategoryRule(testHouseholdID, "oat milk", "Dairy")
+	created, err := gs.ImportCategoryRules(testHouseholdID, []string{"produce", "Baby"}, map[string]string{
+		"oat milk": "Pantry",
+		"diapers":  "Baby",
+		"kombucha": "Drinks",
+	})
+	if err != nil {
+		t.Fatalf("import rules: %v", err)
+	}
+	if created != 2 {
+		t.Errorf("created = %d, want Baby and Drinks", created)
+	}
+	rules, _ := gs.CategoryRules(testHouseholdID)
+	if len(rules) != 3 || rules["oat milk"] != "Pantry" || rules["diapers"] != "Baby" || rules["kombucha"] != "Drinks" {
+		t.Errorf("rules = %v", rules)
+	}
+	categories, _ := gs.ListCategories(testHouseholdID)
+	if len(categories) != 13 {
+		t.Errorf("categories = %d, want 13", len(categories))
+	}
+
+	otherID := createTestHousehold(t, gs.db, "Other")
+	if rules, _ := gs.CategoryRules(otherID); len(rules) != 0 {
+		t.Errorf("other household rules = %v, want none", rules)
+	}
+}
+
+func TestGroceryShops(t *testing.T) {
+	gs, _ := setupGroceryTestDB(t)
+
+	list, _ := gs.GetDefaultList(testHouseholdID)
+	gs.CreateItem(list.ID, testHouseholdID, "Chicken", "", "", "", "Meat & Seafood", nil)
+	gs.CreateItem(list.ID, testHouseholdID, "Apples", "", "", "", "Produce", nil)
+	gs.CreateItem(list.ID, testHouseholdID, "Bread", "", "", "", "Bakery", nil)
+	gs.CreateItem(list.ID, testHouseholdID, "Nails", "", "", "", "Hardware", nil)
+
+	categories, _ := gs.ListCategories(testHouseholdID)
+	ids := map[string]int64{}
+	for _, c := range categories {
+		ids[c.Name] = c.ID
+	}
+
+	shop, err := gs.CreateShop(testHouseholdID, "Corner Market")
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	if len(shop.Sections) != len(categories) || shop.Sections[0].Category != "Produce" {
+		t.Errorf("new store sections = %+v, want household category order", shop.Sections)
+	}
+
+	shop, err = gs.SetShopSections(shop.ID, testHouseholdID, []model.ShopSection{
+		{CategoryID: ids["Bakery"], Aisle: "1"},
+		{CategoryID: ids["Meat & Seafood"], Aisle: "7"},
+	})
+	if err != nil {
+		t.Fatalf("set sections: %v", err)
+	}
+	if got := shop.Sections; got[0].Category != "Bakery" || got[0].Aisle != "1" || got[1].Category != "Meat & Seafood" || got[2].Category != "Produce" || got[2].Aisle != "" {
+		t.Errorf("sections = %+v", got)
+	}
+
+	// Items follow the store's walking order once the list is shopped
+	// there; categories it has no place for come after, by name.
+	if got, err := gs.SetListShop(list.ID, testHouseholdID, &shop.ID); err != nil || got.ShopID == nil || *got.ShopID != shop.ID {
+		t.Fatalf("set list store = %+v, %v", got, err)
+	}
+	items, _ := gs.ListItemsByList(list.ID, testHouseholdID)
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	if want := "Bread Chicken Nails Apples"; strings.Join(names, " ") != want {
+		t.Errorf("walking order = %v, want %s", names, want)
+	}
+
+	// Deleting the store puts the list back in category order.
+	if err := gs.DeleteShop(shop.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete store: %v", err)
+	}
+	if got, _ := gs.GetListByID(list.ID, testHouseholdID); got.ShopID != nil {
+		t.Errorf("list store = %d, want none", *got.ShopID)
+	}
+	if items, _ := gs.ListItemsByList(list.ID, testHouseholdID); items[0].Name != "Bread" || items[3].Name != "Apples" {
+		t.Errorf("items after delete = %+v", items)
+	}
+}
+
+func TestGroceryShopHouseholdIsolation(t *testing.T) {
+	gs, _ := setupGroceryTestDB(t)
+	otherID := createTestHousehold(t, gs.db, "Other")
+
+	list, _ := gs.GetDefaultList(testHouseholdID)
+	otherList, _ := gs.GetDefaultList(otherID)
+	shop, _ := gs.CreateShop(testHouseholdID, "Corner Market")
+	otherCategories, _ := gs.ListCategories(otherID)
+
+	if got, _ := gs.GetShop(shop.ID, otherID); got != nil {
+		t.Error("expected nil when getting another household's store")
+	}
+	if got, _ := gs.RenameShop(shop.ID, otherID, "Mine"); got != nil {
+		t.Error("expected nil when renaming another household's store")
+	}
+	if got, _ := gs.SetShopSections(shop.ID, otherID, nil); got != nil {
+		t.Error("expected nil when laying out another household's store")
+	}
+	if got, _ := gs.SetListShop(otherList.ID, otherID, &shop.ID); got != nil {
+		t.Error("expected nil when shopping at another household's store")
+	}
+	gs.DeleteShop(shop.ID, otherID)
+
+	// Another household's categories are not placed in the store.
+	got, _ := gs.SetShopSections(shop.ID, testHouseholdID, []model.ShopSection{{CategoryID: otherCategories[0].ID, Aisle: "9"}})
+	if got == nil || got.Name != "Corner Market" {
+		t.Fatalf("store was modified by another household: %+v", got)
+	}
+	for _, s := range got.Sections {
+		if s.CategoryID == otherCategories[0].ID || s.Aisle != "" {
+			t.Errorf("unexpected section %+v", s)
+		}
+	}
+	if shops, _ := gs.ListShops(otherID); len(shops) != 0 {
+		t.Errorf("other household stores = %d, want 0", len(shops))
+	}
+	if got, _ := gs.SetListShop(list.ID, testHouseholdID, &shop.ID); got == nil {
+		t.Error("expected list to be set to the household's own store")
+	}
+}
+
+func TestAddItemMerges(t *testing.T) {
+	gs, _ := setupGroceryTestDB(t)
+
+	list, _ := gs.GetDefaultList(testHouseholdID)
+	milk, merged, err := gs.AddItem(list.ID, testHouseholdID, "Milk", "", "", "", "Dairy", nil)
+	if err != nil || merged {
+		t.Fatalf("add milk = %v, %v", merged, err)
+	}
+
+	// Adding it again adds to the one on the list.
+	got, merged, err := gs.AddItem(list.ID, testHouseholdID, " milk ", "", "", "2%", "Other", nil)
+	if err != nil || !merged || got.ID != milk.ID {
+		t.Fatalf("add milk again = %+v, %v, %v", got, merged, err)
+	}
+	if got.Quantity != "2" || got.Notes != "2%" || got.Category != "Dairy" {
+		t.Errorf("merged milk = %+v, want 2 in Dairy with notes", got)
+	}
+
+	// Weights are added in the unit already on the list.
+	beef, _ := gs.CreateItem(list.ID, testHouseholdID, "Ground beef", "1", "lb", "", "Meat & Seafood", nil)
+	if got, _ := gs.CreateItem(list.ID, testHouseholdID, "Ground Beef", "8", "oz", "", "Meat & Seafood", nil); got.ID != beef.ID || got.Quantity != "1.5" || got.Unit != "lb" {
+		t.Errorf("merged beef = %+v, want 1.5 lb", got)
+	}
+
+	// Quantities that cannot be added up, and items already bought, get an
+	// item of their own.
+	if got, merged, _ := gs.AddItem(list.ID, testHouseholdID, "Ground beef", "2", "cans", "", "Meat & Seafood", nil); merged || got.ID == beef.ID {
+		t.Errorf("beef in cans merged into pounds: %+v", got)
+	}
+	gs.ToggleChecked(milk.ID, testHouseholdID, nil)
+	if _, merged, _ := gs.AddItem(list.ID, testHouseholdID, "Milk", "", "", "", "Dairy", nil); merged {
+		t.Error("expected a new item for milk that was checked off")
+	}
+
+	// Items on other lists are left alone.
+	other, _ := gs.CreateList(testHouseholdID, "Costco")
+	if _, merged, _ := gs.AddItem(other.ID, testHouseholdID, "Ground beef", "1", "lb", "", "Meat & Seafood", nil); merged {
+		t.Error("expected a new item on another list")
+	}
+	if got, _ := gs.GetItemByID(beef.ID, testHouseholdID); got.Quantity != "1.5" {
+		t.Errorf("beef quantity = %q, want 1.5", got.Quantity)
+	}
+}
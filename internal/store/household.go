@@ -176,8 +176,10 @@ func (s *HouseholdStore) UpdateMemberRole(householdID, userID int64, role string
 }
 
 // SeedDefaults inserts default chore areas, grocery categories, a grocery list,
-// and settings for a new household in a single transaction.
-func (s *HouseholdStore) SeedDefaults(householdID int64) error {
+// and settings for a new household in a single transaction. The household
+// starts out with the given chores, such as a starter pack or another
+// household's saved template.
+func (s *HouseholdStore) SeedDefaults(householdID int64, chores ...model.TemplateChore) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
@@ -200,6 +202,10 @@ func (s *HouseholdStore) SeedDefaults(householdID int64) error {
 		}
 	}
 
+	if _, err := importTemplateChores(tx, householdID, chores); err != nil {
+		return fmt.Errorf("seed chores: %w", err)
+	}
+
 	// Grocery categories
 	categories := []struct {
 		name      string
@@ -244,6 +250,7 @@ func (s *HouseholdStore) SeedDefaults(householdID int64) error {
 		{"theme_light", "garden"},
 		{"theme_dark", "forest"},
 		{"rewards_leaderboard_enabled", "true"},
+		{TimezoneKey, "UTC"},
 	}
 	for _, s := range settings {
 		if _, err := tx.Exec(
@@ -1,9 +1,11 @@
 package store
 
 import (
+	"database/sql"
 	"testing"
 
 	"github.com/dukerupert/gamwich/internal/database"
+	"github.com/dukerupert/gamwich/internal/model"
 )
 
 func setupHouseholdTestDB(t *testing.T) (*HouseholdStore, *UserStore) {
@@ -19,6 +21,20 @@ func setupHouseholdTestDB(t *testing.T) (*HouseholdStore, *UserStore) {
 	return NewHouseholdStore(db), NewUserStore(db)
 }
 
+// createTestHousehold creates and seeds a second household for isolation tests.
+func createTestHousehold(t *testing.T, db *sql.DB, name string) int64 {
+	t.Helper()
+	hs := NewHouseholdStore(db)
+	h, err := hs.Create(name)
+	if err != nil {
+		t.Fatalf("create household: %v", err)
+	}
+	if err := hs.SeedDefaults(h.ID); err != nil {
+		t.Fatalf("seed household: %v", err)
+	}
+	return h.ID
+}
+
 func TestHouseholdCreate(t *testing.T) {
 	hs, _ := setupHouseholdTestDB(t)
 
@@ -250,8 +266,8 @@ func TestHouseholdSeedDefaults(t *testing.T) {
 	// Verify settings were created
 	var settingsCount int
 	hs.db.QueryRow(`SELECT COUNT(*) FROM settings WHERE household_id = ?`, h.ID).Scan(&settingsCount)
-	if settingsCount != 13 {
-		t.Errorf("settings = %d, want 13", settingsCount)
+	if settingsCount != 14 {
+		t.Errorf("settings = %d, want 14", settingsCount)
 	}
 }
 
@@ -270,3 +286,29 @@ func TestHouseholdDefaultSeed(t *testing.T) {
 		t.Errorf("name = %q, want %q", h.Name, "My Household")
 	}
 }
+
+func TestHouseholdSeedDefaultsWithChores(t *testing.T) {
+	hs, _ := setupHouseholdTestDB(t)
+
+	h, err := hs.Create("Starter")
+	if err != nil {
+		t.Fatalf("create household: %v", err)
+	}
+	err = hs.SeedDefaults(h.ID,
+		model.TemplateChore{Title: "Make bed", Area: "Bedroom", Points: 1, RecurrenceRule: "FREQ=DAILY"},
+		model.TemplateChore{Title: "Mow lawn", Area: "Yard", Points: 4, RecurrenceRule: "FREQ=WEEKLY;BYDAY=SA"},
+	)
+	if err != nil {
+		t.Fatalf("seed defaults: %v", err)
+	}
+
+	cs := NewChoreStore(hs.db)
+	chores, _ := cs.List(h.ID)
+	if len(chores) != 2 {
+		t.Fatalf("chores = %d, want 2", len(chores))
+	}
+	areas, _ := cs.ListAreas(h.ID)
+	if len(areas) != 5 {
+		t.Errorf("areas = %d, want the 5 seeded", len(areas))
+	}
+}
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dukerupert/gamwich/internal/model"
+)
+
+type ICalStore struct {
+	db *sql.DB
+}
+
+func NewICalStore(db *sql.DB) *ICalStore {
+	return &ICalStore{db: db}
+}
+
+func scanICalToken(scanner interface{ Scan(...any) error }) (*model.ICalToken, error) {
+	var t model.ICalToken
+	var createdBy sql.NullInt64
+	var lastUsed sql.NullTime
+	err := scanner.Scan(&t.ID, &t.HouseholdID, &t.Label, &createdBy, &lastUsed, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if createdBy.Valid {
+		t.CreatedBy = &createdBy.Int64
+	}
+	if lastUsed.Valid {
+		t.LastUsedAt = &lastUsed.Time
+	}
+	return &t, nil
+}
+
+const icalTokenCols = `id, household_id, label, created_by, last_used_at, created_at`
+
+// Create generates a new feed token with a crypto-random value. The token
+// is returned once and only its hash is stored.
+func (s *ICalStore) Create(householdID int64, label string, createdBy *int64) (*model.ICalToken, string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, "", fmt.Errorf("generate token: %w", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	var creator sql.NullInt64
+	if createdBy != nil {
+		creator = sql.NullInt64{Int64: *createdBy, Valid: true}
+	}
+
+	result, err := s.db.Exec(
+		`INSERT INTO ical_tokens (household_id, token_hash, label, created_by) VALUES (?, ?, ?, ?)`,
+		householdID, hashICalToken(token), label, creator,
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("insert ical token: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, "", fmt.Errorf("last insert id: %w", err)
+	}
+	row := s.db.QueryRow(`SELECT `+icalTokenCols+` FROM ical_tokens WHERE id = ?`, id)
+	t, err := scanICalToken(row)
+	if err != nil {
+		return nil, "", fmt.Errorf("get ical token: %w", err)
+	}
+	return t, token, nil
+}
+
+func hashICalToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetByToken returns the feed token with the given value, or nil if it does not exist.
+func (s *ICalStore) GetByToken(token string) (*model.ICalToken, error) {
+	row := s.db.QueryRow(`SELECT `+icalTokenCols+` FROM ical_tokens WHERE token_hash = ?`, hashICalToken(token))
+	t, err := scanICalToken(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get ical token: %w", err)
+	}
+	return t, nil
+}
+
+func (s *ICalStore) List(householdID int64) ([]model.ICalToken, error) {
+	rows, err := s.db.Query(
+		`SELECT `+icalTokenCols+` FROM ical_tokens WHERE household_id = ? ORDER BY created_at DESC, id DESC`,
+		householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list ical tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []model.ICalToken
+	for rows.Next() {
+		t, err := scanICalToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan ical token: %w", err)
+		}
+		tokens = append(tokens, *t)
+	}
+	return tokens, rows.Err()
+}
+
+// MarkUsed records that a feed was fetched with the token.
+func (s *ICalStore) MarkUsed(id int64) error {
+	_, err := s.db.Exec(`UPDATE ical_tokens SET last_used_at = datetime('now') WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("mark ical token used: %w", err)
+	}
+	return nil
+}
+
+// Delete revokes a feed token.
+func (s *ICalStore) Delete(id, householdID int64) error {
+	_, err := s.db.Exec(`DELETE FROM ical_tokens WHERE id = ? AND household_id = ?`, id, householdID)
+	if err != nil {
+		return fmt.Errorf("delete ical token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/dukerupert/gamwich/internal/database"
+)
+
+func setupICalTestDB(t *testing.T) *ICalStore {
+	t.Helper()
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewICalStore(db)
+}
+
+func TestICalTokenCreate(t *testing.T) {
+	s := setupICalTestDB(t)
+
+	tok, token, err := s.Create(testHouseholdID, "Work calendar", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(token) != 64 {
+		t.Errorf("token length = %d, want 64", len(token))
+	}
+	if tok.Label != "Work calendar" {
+		t.Errorf("label = %q, want %q", tok.Label, "Work calendar")
+	}
+	if tok.LastUsedAt != nil {
+		t.Error("expected nil last_used_at for new token")
+	}
+
+	_, otherToken, _ := s.Create(testHouseholdID, "Phone", nil)
+	if otherToken == token {
+		t.Error("expected unique tokens")
+	}
+
+	// Only the hash is stored.
+	var stored string
+	if err := s.db.QueryRow(`SELECT token_hash FROM ical_tokens WHERE id = ?`, tok.ID).Scan(&stored); err != nil {
+		t.Fatalf("read token hash: %v", err)
+	}
+	if stored != hashICalToken(token) {
+		t.Errorf("stored %q, want the hash of the token", stored)
+	}
+}
+
+func TestICalTokenGetByToken(t *testing.T) {
+	s := setupICalTestDB(t)
+
+	created, token, _ := s.Create(testHouseholdID, "", nil)
+
+	got, err := s.GetByToken(token)
+	if err != nil {
+		t.Fatalf("get by token: %v", err)
+	}
+	if got == nil || got.ID != created.ID || got.HouseholdID != testHouseholdID {
+		t.Fatalf("got %+v, want token %d", got, created.ID)
+	}
+
+	missing, err := s.GetByToken("nope")
+	if err != nil {
+		t.Fatalf("get missing: %v", err)
+	}
+	if missing != nil {
+		t.Error("expected nil for unknown token")
+	}
+}
+
+func TestICalTokenMarkUsed(t *testing.T) {
+	s := setupICalTestDB(t)
+
+	created, token, _ := s.Create(testHouseholdID, "", nil)
+	if err := s.MarkUsed(created.ID); err != nil {
+		t.Fatalf("mark used: %v", err)
+	}
+
+	got, _ := s.GetByToken(token)
+	if got.LastUsedAt == nil {
+		t.Error("expected last_used_at to be set")
+	}
+}
+
+func TestICalTokenRevoke(t *testing.T) {
+	s := setupICalTestDB(t)
+	otherID := createTestHousehold(t, s.db, "Other")
+
+	created, token, _ := s.Create(testHouseholdID, "", nil)
+
+	// Another household cannot revoke it.
+	if err := s.Delete(created.ID, otherID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if got, _ := s.GetByToken(token); got == nil {
+		t.Fatal("token was revoked by another household")
+	}
+	if tokens, _ := s.List(otherID); len(tokens) != 0 {
+		t.Errorf("other household sees %d tokens, want 0", len(tokens))
+	}
+
+	if err := s.Delete(created.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if got, _ := s.GetByToken(token); got != nil {
+		t.Error("expected token to be revoked")
+	}
+}
@@ -42,7 +42,7 @@ func scanNote(scanner interface{ Scan(...any) error }) (*model.Note, error) {
 
 const noteCols = `id, title, body, author_id, pinned, priority, expires_at, created_at, updated_at`
 
-func (s *NoteStore) Create(title, body string, authorID *int64, pinned bool, priority string, expiresAt *time.Time) (*model.Note, error) {
+func (s *NoteStore) Create(householdID int64, title, body string, authorID *int64, pinned bool, priority string, expiresAt *time.Time) (*model.Note, error) {
 	var aID sql.NullInt64
 	if authorID != nil {
 		aID = sql.NullInt64{Int64: *authorID, Valid: true}
@@ -57,8 +57,8 @@ func (s *NoteStore) Create(title, body string, authorID *int64, pinned bool, pri
 	}
 
 	result, err := s.db.Exec(
-		`INSERT INTO notes (title, body, author_id, pinned, priority, expires_at) VALUES (?, ?, ?, ?, ?, ?)`,
-		title, body, aID, p, priority, exp,
+		`INSERT INTO notes (household_id, title, body, author_id, pinned, priority, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		householdID, title, body, aID, p, priority, exp,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert note: %w", err)
@@ -67,11 +67,11 @@ func (s *NoteStore) Create(title, body string, authorID *int64, pinned bool, pri
 	if err != nil {
 		return nil, fmt.Errorf("last insert id: %w", err)
 	}
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
 }
 
-func (s *NoteStore) GetByID(id int64) (*model.Note, error) {
-	row := s.db.QueryRow(`SELECT `+noteCols+` FROM notes WHERE id = ?`, id)
+func (s *NoteStore) GetByID(id, householdID int64) (*model.Note, error) {
+	row := s.db.QueryRow(`SELECT `+noteCols+` FROM notes WHERE id = ? AND household_id = ?`, id, householdID)
 	n, err := scanNote(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -84,15 +84,16 @@ func (s *NoteStore) GetByID(id int64) (*model.Note, error) {
 
 // List returns non-expired notes ordered by pinned DESC, priority (urgent > normal > fun), created_at DESC.
 // It lazily cleans up expired notes before querying.
-func (s *NoteStore) List() ([]model.Note, error) {
+func (s *NoteStore) List(householdID int64) ([]model.Note, error) {
 	s.DeleteExpired()
 
 	rows, err := s.db.Query(
-		`SELECT ` + noteCols + ` FROM notes
-		 WHERE expires_at IS NULL OR expires_at > datetime('now')
+		`SELECT `+noteCols+` FROM notes
+		 WHERE household_id = ? AND (expires_at IS NULL OR expires_at > datetime('now'))
 		 ORDER BY pinned DESC,
 		   CASE priority WHEN 'urgent' THEN 0 WHEN 'normal' THEN 1 WHEN 'fun' THEN 2 ELSE 3 END,
 		   created_at DESC`,
+		householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("list notes: %w", err)
@@ -111,12 +112,13 @@ func (s *NoteStore) List() ([]model.Note, error) {
 }
 
 // ListPinned returns pinned, non-expired notes for the dashboard widget.
-func (s *NoteStore) ListPinned() ([]model.Note, error) {
+func (s *NoteStore) ListPinned(householdID int64) ([]model.Note, error) {
 	rows, err := s.db.Query(
 		`SELECT `+noteCols+` FROM notes
-		 WHERE pinned = 1 AND (expires_at IS NULL OR expires_at > datetime('now'))
+		 WHERE household_id = ? AND pinned = 1 AND (expires_at IS NULL OR expires_at > datetime('now'))
 		 ORDER BY CASE priority WHEN 'urgent' THEN 0 WHEN 'normal' THEN 1 WHEN 'fun' THEN 2 ELSE 3 END,
 		   created_at DESC`,
+		householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("list pinned notes: %w", err)
@@ -134,7 +136,7 @@ func (s *NoteStore) ListPinned() ([]model.Note, error) {
 	return notes, rows.Err()
 }
 
-func (s *NoteStore) Update(id int64, title, body string, authorID *int64, pinned bool, priority string, expiresAt *time.Time) (*model.Note, error) {
+func (s *NoteStore) Update(id, householdID int64, title, body string, authorID *int64, pinned bool, priority string, expiresAt *time.Time) (*model.Note, error) {
 	var aID sql.NullInt64
 	if authorID != nil {
 		aID = sql.NullInt64{Int64: *authorID, Valid: true}
@@ -149,17 +151,17 @@ func (s *NoteStore) Update(id int64, title, body string, authorID *int64, pinned
 	}
 
 	_, err := s.db.Exec(
-		`UPDATE notes SET title = ?, body = ?, author_id = ?, pinned = ?, priority = ?, expires_at = ? WHERE id = ?`,
-		title, body, aID, p, priority, exp, id,
+		`UPDATE notes SET title = ?, body = ?, author_id = ?, pinned = ?, priority = ?, expires_at = ? WHERE id = ? AND household_id = ?`,
+		title, body, aID, p, priority, exp, id, householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("update note: %w", err)
 	}
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
 }
 
-func (s *NoteStore) TogglePinned(id int64) (*model.Note, error) {
-	note, err := s.GetByID(id)
+func (s *NoteStore) TogglePinned(id, householdID int64) (*model.Note, error) {
+	note, err := s.GetByID(id, householdID)
 	if err != nil {
 		return nil, err
 	}
@@ -172,22 +174,23 @@ func (s *NoteStore) TogglePinned(id int64) (*model.Note, error) {
 		newPinned = 1
 	}
 
-	_, err = s.db.Exec(`UPDATE notes SET pinned = ? WHERE id = ?`, newPinned, id)
+	_, err = s.db.Exec(`UPDATE notes SET pinned = ? WHERE id = ? AND household_id = ?`, newPinned, id, householdID)
 	if err != nil {
 		return nil, fmt.Errorf("toggle pinned: %w", err)
 	}
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
 }
 
-func (s *NoteStore) Delete(id int64) error {
-	_, err := s.db.Exec(`DELETE FROM notes WHERE id = ?`, id)
+func (s *NoteStore) Delete(id, householdID int64) error {
+	_, err := s.db.Exec(`DELETE FROM notes WHERE id = ? AND household_id = ?`, id, householdID)
 	if err != nil {
 		return fmt.Errorf("delete note: %w", err)
 	}
 	return nil
 }
 
-// DeleteExpired removes expired notes and returns the number deleted.
+// DeleteExpired removes expired notes across all households and returns the
+// number deleted.
 func (s *NoteStore) DeleteExpired() (int64, error) {
 	result, err := s.db.Exec(`DELETE FROM notes WHERE expires_at IS NOT NULL AND expires_at <= datetime('now')`)
 	if err != nil {
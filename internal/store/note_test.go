@@ -23,10 +23,10 @@ func setupNoteTestDB(t *testing.T) (*NoteStore, *FamilyMemberStore) {
 func TestNoteCRUD(t *testing.T) {
 	ns, ms := setupNoteTestDB(t)
 
-	member, _ := ms.Create("Alice", "#FF0000", "A")
+	member, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
 
 	// Create
-	note, err := ns.Create("Test Note", "Some body text", &member.ID, false, "normal", nil)
+	note, err := ns.Create(testHouseholdID, "Test Note", "Some body text", &member.ID, false, "normal", nil)
 	if err != nil {
 		t.Fatalf("create note: %v", err)
 	}
@@ -50,7 +50,7 @@ func TestNoteCRUD(t *testing.T) {
 	}
 
 	// Get by ID
-	got, err := ns.GetByID(note.ID)
+	got, err := ns.GetByID(note.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get note: %v", err)
 	}
@@ -62,7 +62,7 @@ func TestNoteCRUD(t *testing.T) {
 	}
 
 	// Update
-	updated, err := ns.Update(note.ID, "Updated Title", "Updated body", &member.ID, true, "urgent", nil)
+	updated, err := ns.Update(note.ID, testHouseholdID, "Updated Title", "Updated body", &member.ID, true, "urgent", nil)
 	if err != nil {
 		t.Fatalf("update note: %v", err)
 	}
@@ -80,10 +80,10 @@ func TestNoteCRUD(t *testing.T) {
 	}
 
 	// Delete
-	if err := ns.Delete(note.ID); err != nil {
+	if err := ns.Delete(note.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete note: %v", err)
 	}
-	got, err = ns.GetByID(note.ID)
+	got, err = ns.GetByID(note.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get deleted note: %v", err)
 	}
@@ -95,7 +95,7 @@ func TestNoteCRUD(t *testing.T) {
 func TestNoteNotFound(t *testing.T) {
 	ns, _ := setupNoteTestDB(t)
 
-	got, err := ns.GetByID(999)
+	got, err := ns.GetByID(999, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get note: %v", err)
 	}
@@ -108,13 +108,13 @@ func TestNoteListOrdering(t *testing.T) {
 	ns, _ := setupNoteTestDB(t)
 
 	// Create notes with different priorities and pinned status
-	ns.Create("Fun unpinned", "", nil, false, "fun", nil)
-	ns.Create("Normal unpinned", "", nil, false, "normal", nil)
-	ns.Create("Urgent unpinned", "", nil, false, "urgent", nil)
-	ns.Create("Normal pinned", "", nil, true, "normal", nil)
-	ns.Create("Urgent pinned", "", nil, true, "urgent", nil)
+	ns.Create(testHouseholdID, "Fun unpinned", "", nil, false, "fun", nil)
+	ns.Create(testHouseholdID, "Normal unpinned", "", nil, false, "normal", nil)
+	ns.Create(testHouseholdID, "Urgent unpinned", "", nil, false, "urgent", nil)
+	ns.Create(testHouseholdID, "Normal pinned", "", nil, true, "normal", nil)
+	ns.Create(testHouseholdID, "Urgent pinned", "", nil, true, "urgent", nil)
 
-	notes, err := ns.List()
+	notes, err := ns.List(testHouseholdID)
 	if err != nil {
 		t.Fatalf("list notes: %v", err)
 	}
@@ -136,16 +136,16 @@ func TestNoteExpiredExclusion(t *testing.T) {
 
 	// Create a note that already expired
 	pastTime := time.Now().Add(-1 * time.Hour).UTC()
-	ns.Create("Expired note", "", nil, false, "normal", &pastTime)
+	ns.Create(testHouseholdID, "Expired note", "", nil, false, "normal", &pastTime)
 
 	// Create a note that hasn't expired
 	futureTime := time.Now().Add(24 * time.Hour).UTC()
-	ns.Create("Future note", "", nil, false, "normal", &futureTime)
+	ns.Create(testHouseholdID, "Future note", "", nil, false, "normal", &futureTime)
 
 	// Create a note with no expiry
-	ns.Create("No expiry", "", nil, false, "normal", nil)
+	ns.Create(testHouseholdID, "No expiry", "", nil, false, "normal", nil)
 
-	notes, err := ns.List()
+	notes, err := ns.List(testHouseholdID)
 	if err != nil {
 		t.Fatalf("list notes: %v", err)
 	}
@@ -163,11 +163,11 @@ func TestNoteExpiredExclusion(t *testing.T) {
 func TestNoteListPinned(t *testing.T) {
 	ns, _ := setupNoteTestDB(t)
 
-	ns.Create("Pinned 1", "", nil, true, "normal", nil)
-	ns.Create("Not pinned", "", nil, false, "normal", nil)
-	ns.Create("Pinned 2", "", nil, true, "urgent", nil)
+	ns.Create(testHouseholdID, "Pinned 1", "", nil, true, "normal", nil)
+	ns.Create(testHouseholdID, "Not pinned", "", nil, false, "normal", nil)
+	ns.Create(testHouseholdID, "Pinned 2", "", nil, true, "urgent", nil)
 
-	pinned, err := ns.ListPinned()
+	pinned, err := ns.ListPinned(testHouseholdID)
 	if err != nil {
 		t.Fatalf("list pinned: %v", err)
 	}
@@ -187,10 +187,10 @@ func TestNoteListPinnedExcludesExpired(t *testing.T) {
 	ns, _ := setupNoteTestDB(t)
 
 	pastTime := time.Now().Add(-1 * time.Hour).UTC()
-	ns.Create("Expired pinned", "", nil, true, "normal", &pastTime)
-	ns.Create("Active pinned", "", nil, true, "normal", nil)
+	ns.Create(testHouseholdID, "Expired pinned", "", nil, true, "normal", &pastTime)
+	ns.Create(testHouseholdID, "Active pinned", "", nil, true, "normal", nil)
 
-	pinned, err := ns.ListPinned()
+	pinned, err := ns.ListPinned(testHouseholdID)
 	if err != nil {
 		t.Fatalf("list pinned: %v", err)
 	}
@@ -205,13 +205,13 @@ func TestNoteListPinnedExcludesExpired(t *testing.T) {
 func TestNoteTogglePinned(t *testing.T) {
 	ns, _ := setupNoteTestDB(t)
 
-	note, _ := ns.Create("Test", "", nil, false, "normal", nil)
+	note, _ := ns.Create(testHouseholdID, "Test", "", nil, false, "normal", nil)
 	if note.Pinned {
 		t.Error("expected not pinned initially")
 	}
 
 	// Toggle to pinned
-	toggled, err := ns.TogglePinned(note.ID)
+	toggled, err := ns.TogglePinned(note.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("toggle pinned: %v", err)
 	}
@@ -220,7 +220,7 @@ func TestNoteTogglePinned(t *testing.T) {
 	}
 
 	// Toggle back to unpinned
-	toggled, err = ns.TogglePinned(note.ID)
+	toggled, err = ns.TogglePinned(note.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("toggle pinned back: %v", err)
 	}
@@ -232,7 +232,7 @@ func TestNoteTogglePinned(t *testing.T) {
 func TestNoteTogglePinnedNotFound(t *testing.T) {
 	ns, _ := setupNoteTestDB(t)
 
-	got, err := ns.TogglePinned(999)
+	got, err := ns.TogglePinned(999, testHouseholdID)
 	if err != nil {
 		t.Fatalf("toggle pinned: %v", err)
 	}
@@ -245,9 +245,9 @@ func TestNoteDeleteExpired(t *testing.T) {
 	ns, _ := setupNoteTestDB(t)
 
 	pastTime := time.Now().Add(-1 * time.Hour).UTC()
-	ns.Create("Expired 1", "", nil, false, "normal", &pastTime)
-	ns.Create("Expired 2", "", nil, false, "normal", &pastTime)
-	ns.Create("Active", "", nil, false, "normal", nil)
+	ns.Create(testHouseholdID, "Expired 1", "", nil, false, "normal", &pastTime)
+	ns.Create(testHouseholdID, "Expired 2", "", nil, false, "normal", &pastTime)
+	ns.Create(testHouseholdID, "Active", "", nil, false, "normal", nil)
 
 	count, err := ns.DeleteExpired()
 	if err != nil {
@@ -258,7 +258,7 @@ func TestNoteDeleteExpired(t *testing.T) {
 	}
 
 	// Verify only active note remains (bypass lazy delete by using GetByID)
-	notes, err := ns.List()
+	notes, err := ns.List(testHouseholdID)
 	if err != nil {
 		t.Fatalf("list notes: %v", err)
 	}
@@ -273,20 +273,20 @@ func TestNoteDeleteExpired(t *testing.T) {
 func TestNoteFKSetNullOnDeleteAuthor(t *testing.T) {
 	ns, ms := setupNoteTestDB(t)
 
-	member, _ := ms.Create("Alice", "#FF0000", "A")
-	note, _ := ns.Create("Test", "", &member.ID, false, "normal", nil)
+	member, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
+	note, _ := ns.Create(testHouseholdID, "Test", "", &member.ID, false, "normal", nil)
 
 	if note.AuthorID == nil || *note.AuthorID != member.ID {
 		t.Fatalf("author_id should be %d", member.ID)
 	}
 
 	// Delete the member
-	if err := ms.Delete(member.ID); err != nil {
+	if err := ms.Delete(member.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete member: %v", err)
 	}
 
 	// Note should still exist with NULL author_id
-	got, err := ns.GetByID(note.ID)
+	got, err := ns.GetByID(note.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get note after author delete: %v", err)
 	}
@@ -301,7 +301,7 @@ func TestNoteFKSetNullOnDeleteAuthor(t *testing.T) {
 func TestNoteNilAuthor(t *testing.T) {
 	ns, _ := setupNoteTestDB(t)
 
-	note, err := ns.Create("No Author", "body", nil, false, "normal", nil)
+	note, err := ns.Create(testHouseholdID, "No Author", "body", nil, false, "normal", nil)
 	if err != nil {
 		t.Fatalf("create note with nil author: %v", err)
 	}
@@ -314,7 +314,7 @@ func TestNoteWithExpiry(t *testing.T) {
 	ns, _ := setupNoteTestDB(t)
 
 	futureTime := time.Now().Add(48 * time.Hour).UTC()
-	note, err := ns.Create("Expiring note", "", nil, false, "normal", &futureTime)
+	note, err := ns.Create(testHouseholdID, "Expiring note", "", nil, false, "normal", &futureTime)
 	if err != nil {
 		t.Fatalf("create note with expiry: %v", err)
 	}
@@ -322,3 +322,36 @@ func TestNoteWithExpiry(t *testing.T) {
 		t.Fatal("expected expires_at to be set")
 	}
 }
+
+func TestNoteHouseholdIsolation(t *testing.T) {
+	ns, _ := setupNoteTestDB(t)
+	otherID := createTestHousehold(t, ns.db, "Other")
+
+	note, err := ns.Create(testHouseholdID, "Ours", "", nil, true, "normal", nil)
+	if err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+
+	if got, _ := ns.GetByID(note.ID, otherID); got != nil {
+		t.Error("expected nil when reading another household's note")
+	}
+	notes, _ := ns.List(otherID)
+	if len(notes) != 0 {
+		t.Errorf("other household sees %d notes, want 0", len(notes))
+	}
+	pinned, _ := ns.ListPinned(otherID)
+	if len(pinned) != 0 {
+		t.Errorf("other household sees %d pinned notes, want 0", len(pinned))
+	}
+	if got, _ := ns.TogglePinned(note.ID, otherID); got != nil {
+		t.Error("expected nil when pinning another household's note")
+	}
+	if err := ns.Delete(note.ID, otherID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	got, _ := ns.GetByID(note.ID, testHouseholdID)
+	if got == nil || !got.Pinned {
+		t.Errorf("note was modified by another household: %+v", got)
+	}
+}
@@ -36,38 +36,36 @@ func (s *PushStore) CreateSubscription(userID, householdID int64, endpoint, p256
 }
 
 func (s *PushStore) GetByID(id, householdID int64) (*model.PushSubscription, error) {
-	var sub model.PushSubscription
-	err := s.db.QueryRow(
-		`SELECT id, user_id, household_id, endpoint, p256dh_key, auth_key, device_name, created_at
+	sub, err := scanPushSubscription(s.db.QueryRow(
+		`SELECT `+pushSubscriptionCols+`
 		 FROM push_subscriptions WHERE id = ? AND household_id = ?`, id, householdID,
-	).Scan(&sub.ID, &sub.UserID, &sub.HouseholdID, &sub.Endpoint, &sub.P256dhKey, &sub.AuthKey, &sub.DeviceName, &sub.CreatedAt)
+	))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("get push subscription: %w", err)
 	}
-	return &sub, nil
+	return sub, nil
 }
 
 func (s *PushStore) getByEndpoint(endpoint string) (*model.PushSubscription, error) {
-	var sub model.PushSubscription
-	err := s.db.QueryRow(
-		`SELECT id, user_id, household_id, endpoint, p256dh_key, auth_key, device_name, created_at
+	sub, err := scanPushSubscription(s.db.QueryRow(
+		`SELECT `+pushSubscriptionCols+`
 		 FROM push_subscriptions WHERE endpoint = ?`, endpoint,
-	).Scan(&sub.ID, &sub.UserID, &sub.HouseholdID, &sub.Endpoint, &sub.P256dhKey, &sub.AuthKey, &sub.DeviceName, &sub.CreatedAt)
+	))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("get push subscription by endpoint: %w", err)
 	}
-	return &sub, nil
+	return sub, nil
 }
 
 func (s *PushStore) ListByUser(userID, householdID int64) ([]model.PushSubscription, error) {
 	rows, err := s.db.Query(
-		`SELECT id, user_id, household_id, endpoint, p256dh_key, auth_key, device_name, created_at
+		`SELECT `+pushSubscriptionCols+`
 		 FROM push_subscriptions WHERE user_id = ? AND household_id = ? ORDER BY created_at DESC`,
 		userID, householdID,
 	)
@@ -80,7 +78,7 @@ func (s *PushStore) ListByUser(userID, householdID int64) ([]model.PushSubscript
 
 func (s *PushStore) ListByHousehold(householdID int64) ([]model.PushSubscription, error) {
 	rows, err := s.db.Query(
-		`SELECT id, user_id, household_id, endpoint, p256dh_key, auth_key, device_name, created_at
+		`SELECT `+pushSubscriptionCols+`
 		 FROM push_subscriptions WHERE household_id = ? ORDER BY created_at DESC`,
 		householdID,
 	)
@@ -91,6 +89,22 @@ func (s *PushStore) ListByHousehold(householdID int64) ([]model.PushSubscription
 	return scanSubscriptions(rows)
 }
 
+// SetSubscriptionMember records which family member a device belongs to,
+// or clears it if memberID is nil. It does nothing if the member is not in
+// the household.
+func (s *PushStore) SetSubscriptionMember(id, householdID int64, memberID *int64) error {
+	_, err := s.db.Exec(
+		`UPDATE push_subscriptions SET family_member_id = ?
+		 WHERE id = ? AND household_id = ?
+		   AND (? IS NULL OR EXISTS (SELECT 1 FROM family_members WHERE id = ? AND household_id = ?))`,
+		nullableID(memberID), id, householdID, nullableID(memberID), nullableID(memberID), householdID,
+	)
+	if err != nil {
+		return fmt.Errorf("set push subscription member: %w", err)
+	}
+	return nil
+}
+
 func (s *PushStore) DeleteSubscription(id, householdID int64) error {
 	_, err := s.db.Exec(`DELETE FROM push_subscriptions WHERE id = ? AND household_id = ?`, id, householdID)
 	if err != nil {
@@ -223,14 +237,28 @@ func (s *PushStore) CleanupSent(before time.Time) error {
 	return nil
 }
 
+const pushSubscriptionCols = `id, user_id, household_id, endpoint, p256dh_key, auth_key, device_name, family_member_id, created_at`
+
+func scanPushSubscription(scanner interface{ Scan(...any) error }) (*model.PushSubscription, error) {
+	var sub model.PushSubscription
+	var memberID sql.NullInt64
+	if err := scanner.Scan(&sub.ID, &sub.UserID, &sub.HouseholdID, &sub.Endpoint, &sub.P256dhKey, &sub.AuthKey, &sub.DeviceName, &memberID, &sub.CreatedAt); err != nil {
+		return nil, err
+	}
+	if memberID.Valid {
+		sub.FamilyMemberID = &memberID.Int64
+	}
+	return &sub, nil
+}
+
 func scanSubscriptions(rows *sql.Rows) ([]model.PushSubscription, error) {
 	var subs []model.PushSubscription
 	for rows.Next() {
-		var sub model.PushSubscription
-		if err := rows.Scan(&sub.ID, &sub.UserID, &sub.HouseholdID, &sub.Endpoint, &sub.P256dhKey, &sub.AuthKey, &sub.DeviceName, &sub.CreatedAt); err != nil {
+		sub, err := scanPushSubscription(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan push subscription: %w", err)
 		}
-		subs = append(subs, sub)
+		subs = append(subs, *sub)
 	}
 	return subs, rows.Err()
 }
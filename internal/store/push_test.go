@@ -79,6 +79,51 @@ func TestCreateSubscriptionUpsert(t *testing.T) {
 	}
 }
 
+func TestSetSubscriptionMember(t *testing.T) {
+	ps, hid, uid := setupPushTestDB(t)
+	ms := NewFamilyMemberStore(ps.db)
+
+	kid, err := ms.Create(hid, "Kid", "#FF0000", "K")
+	if err != nil {
+		t.Fatalf("create member: %v", err)
+	}
+	result, err := ps.db.Exec("INSERT INTO households (name) VALUES ('Other')")
+	if err != nil {
+		t.Fatalf("create household: %v", err)
+	}
+	otherID, _ := result.LastInsertId()
+	stranger, _ := ms.Create(otherID, "Stranger", "#0000FF", "S")
+
+	sub, _ := ps.CreateSubscription(uid, hid, "https://push.example.com/sub1", "key1", "auth1", "Phone")
+	if sub.FamilyMemberID != nil {
+		t.Fatalf("new subscription member = %v, want nil", *sub.FamilyMemberID)
+	}
+
+	if err := ps.SetSubscriptionMember(sub.ID, hid, &kid.ID); err != nil {
+		t.Fatalf("set member: %v", err)
+	}
+	got, _ := ps.GetByID(sub.ID, hid)
+	if got.FamilyMemberID == nil || *got.FamilyMemberID != kid.ID {
+		t.Errorf("member = %v, want %d", got.FamilyMemberID, kid.ID)
+	}
+
+	// A member from another household is ignored.
+	ps.SetSubscriptionMember(sub.ID, hid, &stranger.ID)
+	got, _ = ps.GetByID(sub.ID, hid)
+	if got.FamilyMemberID == nil || *got.FamilyMemberID != kid.ID {
+		t.Errorf("member after cross-household set = %v, want %d", got.FamilyMemberID, kid.ID)
+	}
+
+	// Deleting the member unlinks the device.
+	if err := ms.Delete(kid.ID, hid); err != nil {
+		t.Fatalf("delete member: %v", err)
+	}
+	got, _ = ps.GetByID(sub.ID, hid)
+	if got.FamilyMemberID != nil {
+		t.Errorf("member after delete = %v, want nil", *got.FamilyMemberID)
+	}
+}
+
 func TestListByUser(t *testing.T) {
 	ps, hid, uid := setupPushTestDB(t)
 
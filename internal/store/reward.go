@@ -2,11 +2,20 @@ package store
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dukerupert/gamwich/internal/model"
 )
 
+// Reasons Redeem turns down a redemption.
+var (
+	ErrInsufficientPoints = errors.New("insufficient points")
+	ErrOutOfStock         = errors.New("reward is out of stock")
+	ErrOnCooldown         = errors.New("reward was redeemed too recently")
+)
+
 type RewardStore struct {
 	db *sql.DB
 }
@@ -20,27 +29,32 @@ func NewRewardStore(db *sql.DB) *RewardStore {
 func scanReward(scanner interface{ Scan(...any) error }) (*model.Reward, error) {
 	var r model.Reward
 	var active int
+	var stock sql.NullInt64
 
-	err := scanner.Scan(&r.ID, &r.Title, &r.Description, &r.PointCost, &active, &r.CreatedAt)
+	err := scanner.Scan(&r.ID, &r.Title, &r.Description, &r.PointCost, &active, &stock, &r.CooldownDays, &r.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 
 	r.Active = active != 0
+	if stock.Valid {
+		n := int(stock.Int64)
+		r.Stock = &n
+	}
 	return &r, nil
 }
 
-const rewardCols = `id, title, description, point_cost, active, created_at`
+const rewardCols = `id, title, description, point_cost, active, stock, cooldown_days, created_at`
 
-func (s *RewardStore) Create(title, description string, pointCost int, active bool) (*model.Reward, error) {
+func (s *RewardStore) Create(householdID int64, title, description string, pointCost int, active bool) (*model.Reward, error) {
 	var a int
 	if active {
 		a = 1
 	}
 
 	result, err := s.db.Exec(
-		`INSERT INTO rewards (title, description, point_cost, active) VALUES (?, ?, ?, ?)`,
-		title, description, pointCost, a,
+		`INSERT INTO rewards (household_id, title, description, point_cost, active) VALUES (?, ?, ?, ?, ?)`,
+		householdID, title, description, pointCost, a,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert reward: %w", err)
@@ -49,11 +63,11 @@ func (s *RewardStore) Create(title, description string, pointCost int, active bo
 	if err != nil {
 		return nil, fmt.Errorf("last insert id: %w", err)
 	}
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
 }
 
-func (s *RewardStore) GetByID(id int64) (*model.Reward, error) {
-	row := s.db.QueryRow(`SELECT `+rewardCols+` FROM rewards WHERE id = ?`, id)
+func (s *RewardStore) GetByID(id, householdID int64) (*model.Reward, error) {
+	row := s.db.QueryRow(`SELECT `+rewardCols+` FROM rewards WHERE id = ? AND household_id = ?`, id, householdID)
 	r, err := scanReward(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -65,8 +79,8 @@ func (s *RewardStore) GetByID(id int64) (*model.Reward, error) {
 }
 
 // List returns all rewards, active first, then by title.
-func (s *RewardStore) List() ([]model.Reward, error) {
-	rows, err := s.db.Query(`SELECT ` + rewardCols + ` FROM rewards ORDER BY active DESC, title ASC`)
+func (s *RewardStore) List(householdID int64) ([]model.Reward, error) {
+	rows, err := s.db.Query(`SELECT `+rewardCols+` FROM rewards WHERE household_id = ? ORDER BY active DESC, title ASC`, householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list rewards: %w", err)
 	}
@@ -84,8 +98,8 @@ func (s *RewardStore) List() ([]model.Reward, error) {
 }
 
 // ListActive returns only active rewards, ordered by title.
-func (s *RewardStore) ListActive() ([]model.Reward, error) {
-	rows, err := s.db.Query(`SELECT ` + rewardCols + ` FROM rewards WHERE active = 1 ORDER BY title ASC`)
+func (s *RewardStore) ListActive(householdID int64) ([]model.Reward, error) {
+	rows, err := s.db.Query(`SELECT `+rewardCols+` FROM rewards WHERE household_id = ? AND active = 1 ORDER BY title ASC`, householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list active rewards: %w", err)
 	}
@@ -102,24 +116,38 @@ func (s *RewardStore) ListActive() ([]model.Reward, error) {
 	return rewards, rows.Err()
 }
 
-func (s *RewardStore) Update(id int64, title, description string, pointCost int, active bool) (*model.Reward, error) {
+func (s *RewardStore) Update(id, householdID int64, title, description string, pointCost int, active bool) (*model.Reward, error) {
 	var a int
 	if active {
 		a = 1
 	}
 
 	_, err := s.db.Exec(
-		`UPDATE rewards SET title = ?, description = ?, point_cost = ?, active = ? WHERE id = ?`,
-		title, description, pointCost, a, id,
+		`UPDATE rewards SET title = ?, description = ?, point_cost = ?, active = ? WHERE id = ? AND household_id = ?`,
+		title, description, pointCost, a, id, householdID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("update reward: %w", err)
 	}
-	return s.GetByID(id)
+	return s.GetByID(id, householdID)
+}
+
+// SetLimits sets how many of a reward are left, or nil for no limit, and
+// how many days a member must wait between redeeming it.
+func (s *RewardStore) SetLimits(id, householdID int64, stock *int, cooldownDays int) error {
+	var st sql.NullInt64
+	if stock != nil {
+		st = sql.NullInt64{Int64: int64(*stock), Valid: true}
+	}
+	_, err := s.db.Exec(`UPDATE rewards SET stock = ?, cooldown_days = ? WHERE id = ? AND household_id = ?`, st, cooldownDays, id, householdID)
+	if err != nil {
+		return fmt.Errorf("set reward limits: %w", err)
+	}
+	return nil
 }
 
-func (s *RewardStore) Delete(id int64) error {
-	_, err := s.db.Exec(`DELETE FROM rewards WHERE id = ?`, id)
+func (s *RewardStore) Delete(id, householdID int64) error {
+	_, err := s.db.Exec(`DELETE FROM rewards WHERE id = ? AND household_id = ?`, id, householdID)
 	if err != nil {
 		return fmt.Errorf("delete reward: %w", err)
 	}
@@ -130,9 +158,11 @@ func (s *RewardStore) Delete(id int64) error {
 
 func scanRedemption(scanner interface{ Scan(...any) error }) (*model.RewardRedemption, error) {
 	var r model.RewardRedemption
-	var redeemedBy sql.NullInt64
+	var redeemedBy, reviewedBy, fulfilledBy sql.NullInt64
+	var reviewedAt, fulfilledAt sql.NullTime
 
-	err := scanner.Scan(&r.ID, &r.RewardID, &redeemedBy, &r.PointsSpent, &r.RedeemedAt)
+	err := scanner.Scan(&r.ID, &r.RewardID, &redeemedBy, &r.PointsSpent, &r.RedeemedAt,
+		&r.Status, &reviewedBy, &reviewedAt, &fulfilledBy, &fulfilledAt, &r.Note)
 	if err != nil {
 		return nil, err
 	}
@@ -140,40 +170,136 @@ func scanRedemption(scanner interface{ Scan(...any) error }) (*model.RewardRedem
 	if redeemedBy.Valid {
 		r.RedeemedBy = &redeemedBy.Int64
 	}
+	if reviewedBy.Valid {
+		r.ReviewedBy = &reviewedBy.Int64
+	}
+	if reviewedAt.Valid {
+		r.ReviewedAt = &reviewedAt.Time
+	}
+	if fulfilledBy.Valid {
+		r.FulfilledBy = &fulfilledBy.Int64
+	}
+	if fulfilledAt.Valid {
+		r.FulfilledAt = &fulfilledAt.Time
+	}
 	return &r, nil
 }
 
-const redemptionCols = `id, reward_id, redeemed_by, points_spent, redeemed_at`
-
-func (s *RewardStore) Redeem(rewardID int64, redeemedBy *int64, pointsSpent int) (*model.RewardRedemption, error) {
+const redemptionCols = `id, reward_id, redeemed_by, points_spent, redeemed_at, status, reviewed_by, reviewed_at, fulfilled_by, fulfilled_at, note`
+
+// householdRedemptions restricts a reward_redemptions query to redemptions
+// whose parent reward belongs to the given household.
+const householdRedemptions = `reward_id IN (SELECT id FROM rewards WHERE household_id = ?)`
+
+// onCooldown matches a member's redemptions of reward r that are too recent
+// for them to redeem it again.
+const onCooldown = `SELECT 1 FROM reward_redemptions rr
+	WHERE rr.reward_id = r.id AND rr.redeemed_by = ? AND rr.status != 'cancelled'
+	  AND r.cooldown_days > 0 AND rr.redeemed_at > datetime('now', '-' || r.cooldown_days || ' days')`
+
+// Redeem requests a reward in the given household and charges the points
+// to whoever redeemed it, taking one from the reward's stock. The balance,
+// stock and cooldown are checked in the same statement that records the
+// redemption, so two requests at once cannot both spend the same points or
+// the last one in stock. It returns ErrInsufficientPoints, ErrOutOfStock
+// or ErrOnCooldown if the request is turned down, and nil if the reward
+// does not belong to the household.
+func (s *RewardStore) Redeem(rewardID, householdID int64, redeemedBy *int64, pointsSpent int) (*model.RewardRedemption, error) {
 	var rBy sql.NullInt64
 	if redeemedBy != nil {
 		rBy = sql.NullInt64{Int64: *redeemedBy, Valid: true}
 	}
 
-	result, err := s.db.Exec(
-		`INSERT INTO reward_redemptions (reward_id, redeemed_by, points_spent) VALUES (?, ?, ?)`,
-		rewardID, rBy, pointsSpent,
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO reward_redemptions (reward_id, redeemed_by, points_spent, status)
+		 SELECT r.id, ?, ?, 'requested' FROM rewards r
+		 WHERE r.id = ? AND r.household_id = ?
+		   AND (r.stock IS NULL OR r.stock > 0)
+		   AND (? IS NULL OR ? <= (SELECT COALESCE(SUM(amount), 0) FROM point_transactions WHERE family_member_id = ? AND household_id = r.household_id))
+		   AND NOT EXISTS (`+onCooldown+`)`,
+		rBy, pointsSpent, rewardID, householdID,
+		rBy, pointsSpent, rBy,
+		rBy,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert redemption: %w", err)
 	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return nil, redeemRejection(tx, rewardID, householdID, rBy)
+	}
 	id, err := result.LastInsertId()
 	if err != nil {
 		return nil, fmt.Errorf("last insert id: %w", err)
 	}
+	if _, err := tx.Exec(`UPDATE rewards SET stock = stock - 1 WHERE id = ? AND stock IS NOT NULL`, rewardID); err != nil {
+		return nil, fmt.Errorf("take from stock: %w", err)
+	}
+	if err := recordRedemptionPoints(tx, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
 
 	row := s.db.QueryRow(`SELECT `+redemptionCols+` FROM reward_redemptions WHERE id = ?`, id)
 	return scanRedemption(row)
 }
 
-func (s *RewardStore) ListRedemptionsByMember(memberID int64) ([]model.RewardRedemption, error) {
+// redeemRejection works out why Redeem recorded nothing. It returns nil if
+// the reward does not belong to the household.
+func redeemRejection(tx *sql.Tx, rewardID, householdID int64, redeemedBy sql.NullInt64) error {
+	var stock sql.NullInt64
+	var cooling int
+	err := tx.QueryRow(
+		`SELECT stock, EXISTS (`+onCooldown+`) FROM rewards r WHERE id = ? AND household_id = ?`,
+		redeemedBy, rewardID, householdID,
+	).Scan(&stock, &cooling)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("check redemption: %w", err)
+	}
+	switch {
+	case stock.Valid && stock.Int64 <= 0:
+		return ErrOutOfStock
+	case cooling != 0:
+		return ErrOnCooldown
+	}
+	return ErrInsufficientPoints
+}
+
+// GetRedemption returns a redemption of one of the household's rewards, or
+// nil if there is none.
+func (s *RewardStore) GetRedemption(id, householdID int64) (*model.RewardRedemption, error) {
+	row := s.db.QueryRow(`SELECT `+redemptionCols+` FROM reward_redemptions WHERE id = ? AND `+householdRedemptions, id, householdID)
+	r, err := scanRedemption(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get redemption: %w", err)
+	}
+	return r, nil
+}
+
+// ListOpenRedemptions returns the redemptions still waiting on a parent,
+// oldest first.
+func (s *RewardStore) ListOpenRedemptions(householdID int64) ([]model.RewardRedemption, error) {
 	rows, err := s.db.Query(
-		`SELECT `+redemptionCols+` FROM reward_redemptions WHERE redeemed_by = ? ORDER BY redeemed_at DESC`,
-		memberID,
+		`SELECT `+redemptionCols+` FROM reward_redemptions WHERE `+householdRedemptions+` AND status IN ('requested', 'approved') ORDER BY redeemed_at ASC, id ASC`,
+		householdID,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("list redemptions by member: %w", err)
+		return nil, fmt.Errorf("list open redemptions: %w", err)
 	}
 	defer rows.Close()
 
@@ -188,109 +314,551 @@ func (s *RewardStore) ListRedemptionsByMember(memberID int64) ([]model.RewardRed
 	return redemptions, rows.Err()
 }
 
-// --- Point balance methods ---
+// redemptionMoves lists the statuses a redemption can move to each status
+// from. Fulfilling a requested redemption approves it on the way.
+var redemptionMoves = map[model.RedemptionStatus]string{
+	model.RedemptionApproved:  `'requested'`,
+	model.RedemptionFulfilled: `'requested', 'approved'`,
+	model.RedemptionCancelled: `'requested', 'approved'`,
+}
 
-// GetPointBalance computes the balance for a single member: earned - spent.
-func (s *RewardStore) GetPointBalance(memberID int64) (*model.PointBalance, error) {
-	var earned sql.NullInt64
-	err := s.db.QueryRow(
-		`SELECT COALESCE(SUM(points_earned), 0) FROM chore_completions WHERE completed_by = ?`,
-		memberID,
-	).Scan(&earned)
+// UpdateRedemptionStatus moves a redemption along on behalf of actorID.
+// Approving or cancelling records them as the reviewer, and fulfilling as
+// the one who handed the reward over. A cancelled redemption is refunded
+// and goes back into stock. It returns nil if the redemption does not
+// belong to the household or cannot move to status from where it is.
+func (s *RewardStore) UpdateRedemptionStatus(id, householdID int64, status model.RedemptionStatus, actorID int64, note string) (*model.RewardRedemption, error) {
+	from, ok := redemptionMoves[status]
+	if !ok {
+		return nil, fmt.Errorf("invalid redemption status %q", status)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	result, err := tx.Exec(
+		`UPDATE reward_redemptions SET
+		     reviewed_by = CASE WHEN status = 'requested' OR ? = 'cancelled' THEN ? ELSE reviewed_by END,
+		     reviewed_at = CASE WHEN status = 'requested' OR ? = 'cancelled' THEN ? ELSE reviewed_at END,
+		     fulfilled_by = CASE WHEN ? = 'fulfilled' THEN ? ELSE fulfilled_by END,
+		     fulfilled_at = CASE WHEN ? = 'fulfilled' THEN ? ELSE fulfilled_at END,
+		     note = CASE WHEN ? != '' THEN ? ELSE note END,
+		     status = ?
+		 WHERE id = ? AND status IN (`+from+`) AND `+householdRedemptions,
+		status, actorID, status, now, status, actorID, status, now, note, note, status,
+		id, householdID,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("sum points earned: %w", err)
+		return nil, fmt.Errorf("update redemption status: %w", err)
 	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return nil, nil
+	}
+	if status == model.RedemptionCancelled {
+		if _, err := tx.Exec(
+			`UPDATE rewards SET stock = stock + 1 WHERE stock IS NOT NULL AND id = (SELECT reward_id FROM reward_redemptions WHERE id = ?)`,
+			id,
+		); err != nil {
+			return nil, fmt.Errorf("return to stock: %w", err)
+		}
+		if err := refundRedemptionPoints(tx, id, actorID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetRedemption(id, householdID)
+}
 
-	var spent sql.NullInt64
-	err = s.db.QueryRow(
-		`SELECT COALESCE(SUM(points_spent), 0) FROM reward_redemptions WHERE redeemed_by = ?`,
-		memberID,
-	).Scan(&spent)
+// NextRedeemable returns when a member can next redeem each reward they
+// are waiting out the cooldown on.
+func (s *RewardStore) NextRedeemable(memberID, householdID int64) (map[int64]time.Time, error) {
+	rows, err := s.db.Query(
+		`SELECT r.id, datetime(MAX(rr.redeemed_at), '+' || r.cooldown_days || ' days')
+		 FROM rewards r JOIN reward_redemptions rr ON rr.reward_id = r.id
+		 WHERE r.household_id = ? AND r.cooldown_days > 0 AND rr.redeemed_by = ? AND rr.status != 'cancelled'
+		 GROUP BY r.id
+		 HAVING datetime(MAX(rr.redeemed_at), '+' || r.cooldown_days || ' days') > datetime('now')`,
+		householdID, memberID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list reward cooldowns: %w", err)
+	}
+	defer rows.Close()
+
+	next := make(map[int64]time.Time)
+	for rows.Next() {
+		var id int64
+		var at string
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, fmt.Errorf("scan reward cooldown: %w", err)
+		}
+		t, err := time.Parse(time.DateTime, at)
+		if err != nil {
+			return nil, fmt.Errorf("parse reward cooldown: %w", err)
+		}
+		next[id] = t
+	}
+	return next, rows.Err()
+}
+
+func (s *RewardStore) ListRedemptionsByMember(memberID, householdID int64) ([]model.RewardRedemption, error) {
+	rows, err := s.db.Query(
+		`SELECT `+redemptionCols+` FROM reward_redemptions WHERE redeemed_by = ? AND `+householdRedemptions+` ORDER BY redeemed_at DESC`,
+		memberID, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list redemptions by member: %w", err)
+	}
+	defer rows.Close()
+
+	var redemptions []model.RewardRedemption
+	for rows.Next() {
+		r, err := scanRedemption(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan redemption: %w", err)
+		}
+		redemptions = append(redemptions, *r)
+	}
+	return redemptions, rows.Err()
+}
+
+// --- Point balance methods ---
+
+// pointTotals sums a member's ledger into points earned, including
+// adjustments, and points spent.
+const pointTotals = `COALESCE(SUM(CASE WHEN kind != 'spend' THEN amount END), 0), COALESCE(-SUM(CASE WHEN kind = 'spend' THEN amount END), 0)`
+
+// GetPointBalance computes the balance for a single member from the ledger.
+func (s *RewardStore) GetPointBalance(memberID, householdID int64) (*model.PointBalance, error) {
+	var earned, spent int
+	err := s.db.QueryRow(
+		`SELECT `+pointTotals+` FROM point_transactions WHERE family_member_id = ? AND household_id = ?`,
+		memberID, householdID,
+	).Scan(&earned, &spent)
 	if err != nil {
-		return nil, fmt.Errorf("sum points spent: %w", err)
+		return nil, fmt.Errorf("sum points: %w", err)
 	}
 
-	// Get member name
+	// Get member name and what their points are worth
 	var name string
-	err = s.db.QueryRow(`SELECT name FROM family_members WHERE id = ?`, memberID).Scan(&name)
+	var centsPerPoint int
+	err = s.db.QueryRow(
+		`SELECT fm.name, COALESCE(ma.cents_per_point, 0)
+		 FROM family_members fm LEFT JOIN member_allowances ma ON ma.family_member_id = fm.id
+		 WHERE fm.id = ? AND fm.household_id = ?`,
+		memberID, householdID,
+	).Scan(&name, &centsPerPoint)
 	if err == sql.ErrNoRows {
 		name = "Unknown"
 	} else if err != nil {
 		return nil, fmt.Errorf("get member name: %w", err)
 	}
 
-	totalEarned := int(earned.Int64)
-	totalSpent := int(spent.Int64)
-
 	return &model.PointBalance{
-		MemberID:    memberID,
-		MemberName:  name,
-		TotalEarned: totalEarned,
-		TotalSpent:  totalSpent,
-		Balance:     totalEarned - totalSpent,
+		MemberID:      memberID,
+		MemberName:    name,
+		TotalEarned:   earned,
+		TotalSpent:    spent,
+		Balance:       earned - spent,
+		CentsPerPoint: centsPerPoint,
+		ValueCents:    (earned - spent) * centsPerPoint,
 	}, nil
 }
 
 // GetAllPointBalances returns point balances for all family members, ordered by balance DESC.
-func (s *RewardStore) GetAllPointBalances() ([]model.PointBalance, error) {
-	rows, err := s.db.Query(`SELECT id, name FROM family_members ORDER BY sort_order ASC, name ASC`)
+func (s *RewardStore) GetAllPointBalances(householdID int64) ([]model.PointBalance, error) {
+	rows, err := s.db.Query(
+		`SELECT fm.id, fm.name, `+pointTotals+`, COALESCE(ma.cents_per_point, 0)
+		 FROM family_members fm
+		 LEFT JOIN point_transactions pt ON pt.family_member_id = fm.id AND pt.household_id = fm.household_id
+		 LEFT JOIN member_allowances ma ON ma.family_member_id = fm.id
+		 WHERE fm.household_id = ?
+		 GROUP BY fm.id
+		 ORDER BY COALESCE(SUM(pt.amount), 0) DESC, fm.sort_order ASC, fm.name ASC`,
+		householdID,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("list members: %w", err)
+		return nil, fmt.Errorf("list point balances: %w", err)
 	}
 	defer rows.Close()
 
-	type memberInfo struct {
-		ID   int64
-		Name string
+	var balances []model.PointBalance
+	for rows.Next() {
+		var b model.PointBalance
+		if err := rows.Scan(&b.MemberID, &b.MemberName, &b.TotalEarned, &b.TotalSpent, &b.CentsPerPoint); err != nil {
+			return nil, fmt.Errorf("scan point balance: %w", err)
+		}
+		b.Balance = b.TotalEarned - b.TotalSpent
+		b.ValueCents = b.Balance * b.CentsPerPoint
+		balances = append(balances, b)
+	}
+	return balances, rows.Err()
+}
+
+// --- Point ledger methods ---
+
+func scanPointTransaction(scanner interface{ Scan(...any) error }) (*model.PointTransaction, error) {
+	var t model.PointTransaction
+	var actorID, completionID, redemptionID sql.NullInt64
+
+	err := scanner.Scan(&t.ID, &t.FamilyMemberID, &t.Kind, &t.Amount, &t.Reason, &actorID, &completionID, &redemptionID, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if actorID.Valid {
+		t.ActorID = &actorID.Int64
+	}
+	if completionID.Valid {
+		t.CompletionID = &completionID.Int64
+	}
+	if redemptionID.Valid {
+		t.RedemptionID = &redemptionID.Int64
+	}
+	return &t, nil
+}
+
+const pointTransactionCols = `id, family_member_id, kind, amount, reason, actor_id, completion_id, redemption_id, created_at`
+
+// AdjustPoints records a bonus, penalty or correction for a member. The
+// amount is signed. It returns nil if the member does not belong to the
+// household.
+func (s *RewardStore) AdjustPoints(memberID, householdID int64, kind model.PointTransactionKind, amount int, reason string, actorID *int64) (*model.PointTransaction, error) {
+	var actor sql.NullInt64
+	if actorID != nil {
+		actor = sql.NullInt64{Int64: *actorID, Valid: true}
+	}
+
+	result, err := s.db.Exec(
+		`INSERT INTO point_transactions (household_id, family_member_id, kind, amount, reason, actor_id)
+		 SELECT household_id, id, ?, ?, ?, ? FROM family_members WHERE id = ? AND household_id = ?`,
+		kind, amount, reason, actor, memberID, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert point transaction: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return nil, nil
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("last insert id: %w", err)
+	}
+
+	row := s.db.QueryRow(`SELECT `+pointTransactionCols+` FROM point_transactions WHERE id = ?`, id)
+	return scanPointTransaction(row)
+}
+
+// ListTransactions returns a member's ledger, newest first. A limit of 0
+// returns every transaction.
+func (s *RewardStore) ListTransactions(memberID, householdID int64, limit int) ([]model.PointTransaction, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.Query(
+		`SELECT `+pointTransactionCols+` FROM point_transactions WHERE family_member_id = ? AND household_id = ? ORDER BY created_at DESC, id DESC LIMIT ?`,
+		memberID, householdID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list point transactions: %w", err)
 	}
-	var members []memberInfo
+	defer rows.Close()
+
+	var txns []model.PointTransaction
 	for rows.Next() {
-		var m memberInfo
-		if err := rows.Scan(&m.ID, &m.Name); err != nil {
-			return nil, fmt.Errorf("scan member: %w", err)
+		t, err := scanPointTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan point transaction: %w", err)
 		}
-		members = append(members, m)
+		txns = append(txns, *t)
+	}
+	return txns, rows.Err()
+}
+
+// recordCompletionPoints credits the points a completion earned to whoever
+// completed it. Completions that earned nothing, such as pending ones, or
+// that nobody is credited with are left out of the ledger. A reviewed
+// completion is credited when it was approved, by whoever approved it.
+func recordCompletionPoints(tx *sql.Tx, completionID int64) error {
+	_, err := tx.Exec(
+		`INSERT INTO point_transactions (household_id, family_member_id, kind, amount, reason, actor_id, completion_id, created_at)
+		 SELECT ch.household_id, cc.completed_by, 'earn', cc.points_earned, ch.title, COALESCE(cc.reviewed_by, cc.completed_by), cc.id, COALESCE(cc.reviewed_at, cc.completed_at)
+		 FROM chore_completions cc JOIN chores ch ON ch.id = cc.chore_id
+		 WHERE cc.id = ? AND cc.completed_by IS NOT NULL AND cc.points_earned != 0`,
+		completionID,
+	)
+	if err != nil {
+		return fmt.Errorf("record completion points: %w", err)
 	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate members: %w", err)
+	return nil
+}
+
+// reverseCompletionPoints takes back the points a completion earned when it
+// is undone.
+func reverseCompletionPoints(tx *sql.Tx, completionID, householdID int64) error {
+	_, err := tx.Exec(
+		`INSERT INTO point_transactions (household_id, family_member_id, kind, amount, reason)
+		 SELECT household_id, family_member_id, 'correction', -amount, 'Undone: ' || reason
+		 FROM point_transactions WHERE completion_id = ? AND household_id = ? AND kind = 'earn'`,
+		completionID, householdID,
+	)
+	if err != nil {
+		return fmt.Errorf("reverse completion points: %w", err)
 	}
-	rows.Close()
+	return nil
+}
 
-	var balances []model.PointBalance
-	for _, m := range members {
-		var earned int
-		if err := s.db.QueryRow(
-			`SELECT COALESCE(SUM(points_earned), 0) FROM chore_completions WHERE completed_by = ?`,
-			m.ID,
-		).Scan(&earned); err != nil {
-			return nil, fmt.Errorf("sum points earned for member %d: %w", m.ID, err)
+// refundRedemptionPoints gives back the points a cancelled redemption cost.
+// The refund is a spend entry for a positive amount, so it takes the
+// redemption off the member's total spent.
+func refundRedemptionPoints(tx *sql.Tx, redemptionID, actorID int64) error {
+	_, err := tx.Exec(
+		`INSERT INTO point_transactions (household_id, family_member_id, kind, amount, reason, actor_id, redemption_id)
+		 SELECT household_id, family_member_id, 'spend', -amount, 'Refunded: ' || reason, ?, redemption_id
+		 FROM point_transactions WHERE redemption_id = ? AND kind = 'spend' AND amount < 0`,
+		actorID, redemptionID,
+	)
+	if err != nil {
+		return fmt.Errorf("refund redemption points: %w", err)
+	}
+	return nil
+}
+
+// recordRedemptionPoints charges the points a redemption cost to whoever
+// redeemed it.
+func recordRedemptionPoints(tx *sql.Tx, redemptionID int64) error {
+	_, err := tx.Exec(
+		`INSERT INTO point_transactions (household_id, family_member_id, kind, amount, reason, actor_id, redemption_id, created_at)
+		 SELECT r.household_id, rr.redeemed_by, 'spend', -rr.points_spent, r.title, rr.redeemed_by, rr.id, rr.redeemed_at
+		 FROM reward_redemptions rr JOIN rewards r ON r.id = rr.reward_id
+		 WHERE rr.id = ? AND rr.redeemed_by IS NOT NULL AND rr.points_spent != 0`,
+		redemptionID,
+	)
+	if err != nil {
+		return fmt.Errorf("record redemption points: %w", err)
+	}
+	return nil
+}
+
+// --- Allowance methods ---
+
+func scanAllowance(scanner interface{ Scan(...any) error }) (*model.Allowance, error) {
+	var a model.Allowance
+	var lastDeposit sql.NullString
+
+	err := scanner.Scan(&a.FamilyMemberID, &a.CentsPerPoint, &a.WeeklyPoints, &a.Weekday, &a.StartsOn, &lastDeposit)
+	if err != nil {
+		return nil, err
+	}
+
+	a.LastDeposit = lastDeposit.String
+	return &a, nil
+}
+
+const allowanceCols = `family_member_id, cents_per_point, weekly_points, weekday, starts_on, last_deposit`
+
+// GetAllowance returns a member's allowance, or nil if they have none.
+func (s *RewardStore) GetAllowance(memberID, householdID int64) (*model.Allowance, error) {
+	row := s.db.QueryRow(
+		`SELECT `+allowanceCols+` FROM member_allowances WHERE family_member_id = ? AND household_id = ?`,
+		memberID, householdID,
+	)
+	a, err := scanAllowance(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get allowance: %w", err)
+	}
+	return a, nil
+}
+
+// SetAllowance sets what a member's points are worth and the points paid to
+// them each week. Deposits start from startsOn when the allowance is new or
+// was not paying anything, so weeks without an allowance are never paid
+// after the fact. It returns nil if the member does not belong to the
+// household.
+func (s *RewardStore) SetAllowance(memberID, householdID int64, centsPerPoint, weeklyPoints int, weekday time.Weekday, startsOn string) (*model.Allowance, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO member_allowances (family_member_id, household_id, cents_per_point, weekly_points, weekday, starts_on)
+		 SELECT id, household_id, ?, ?, ?, ? FROM family_members WHERE id = ? AND household_id = ?
+		 ON CONFLICT(family_member_id) DO UPDATE SET
+		   starts_on = CASE WHEN member_allowances.weekly_points = 0 THEN excluded.starts_on ELSE member_allowances.starts_on END,
+		   last_deposit = CASE WHEN member_allowances.weekly_points = 0 THEN NULL ELSE member_allowances.last_deposit END,
+		   cents_per_point = excluded.cents_per_point,
+		   weekly_points = excluded.weekly_points,
+		   weekday = excluded.weekday`,
+		centsPerPoint, weeklyPoints, int(weekday), startsOn, memberID, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("set allowance: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return nil, nil
+	}
+	return s.GetAllowance(memberID, householdID)
+}
+
+// ListAllowances returns the household's allowances that pay out weekly.
+func (s *RewardStore) ListAllowances(householdID int64) ([]model.Allowance, error) {
+	rows, err := s.db.Query(
+		`SELECT `+allowanceCols+` FROM member_allowances WHERE household_id = ? AND weekly_points > 0 ORDER BY family_member_id`,
+		householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list allowances: %w", err)
+	}
+	defer rows.Close()
+
+	var allowances []model.Allowance
+	for rows.Next() {
+		a, err := scanAllowance(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan allowance: %w", err)
 		}
+		allowances = append(allowances, *a)
+	}
+	return allowances, rows.Err()
+}
 
-		var spent int
-		if err := s.db.QueryRow(
-			`SELECT COALESCE(SUM(points_spent), 0) FROM reward_redemptions WHERE redeemed_by = ?`,
-			m.ID,
-		).Scan(&spent); err != nil {
-			return nil, fmt.Errorf("sum points spent for member %d: %w", m.ID, err)
+// ListAllowanceHouseholdIDs returns the households with an allowance that
+// pays out weekly.
+func (s *RewardStore) ListAllowanceHouseholdIDs() ([]int64, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT household_id FROM member_allowances WHERE weekly_points > 0 ORDER BY household_id`)
+	if err != nil {
+		return nil, fmt.Errorf("list allowance households: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan household id: %w", err)
 		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DepositAllowance pays a member's weekly allowance for the given deposit
+// day (YYYY-MM-DD) into their ledger. Days must be paid in order: it
+// reports false and pays nothing if that day or a later one was already
+// paid, the day is before the allowance started, or it pays nothing.
+func (s *RewardStore) DepositAllowance(memberID, householdID int64, day string) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`UPDATE member_allowances SET last_deposit = ?
+		 WHERE family_member_id = ? AND household_id = ? AND weekly_points > 0 AND starts_on <= ?
+		   AND (last_deposit IS NULL OR last_deposit < ?)`,
+		day, memberID, householdID, day, day,
+	)
+	if err != nil {
+		return false, fmt.Errorf("mark allowance paid: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO point_transactions (household_id, family_member_id, kind, amount, reason)
+		 SELECT household_id, family_member_id, 'allowance', weekly_points, 'Weekly allowance'
+		 FROM member_allowances WHERE family_member_id = ?`,
+		memberID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("record allowance points: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+	return true, nil
+}
+
+// --- Savings goal methods ---
 
-		balances = append(balances, model.PointBalance{
-			MemberID:    m.ID,
-			MemberName:  m.Name,
-			TotalEarned: earned,
-			TotalSpent:  spent,
-			Balance:     earned - spent,
-		})
-	}
-
-	// Sort by balance DESC
-	for i := 0; i < len(balances); i++ {
-		for j := i + 1; j < len(balances); j++ {
-			if balances[j].Balance > balances[i].Balance {
-				balances[i], balances[j] = balances[j], balances[i]
-			}
+func scanSavingsGoal(scanner interface{ Scan(...any) error }) (*model.SavingsGoal, error) {
+	var g model.SavingsGoal
+	if err := scanner.Scan(&g.ID, &g.FamilyMemberID, &g.Title, &g.TargetPoints, &g.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+const savingsGoalCols = `id, family_member_id, title, target_points, created_at`
+
+// CreateGoal adds a savings goal for a member. It returns nil if the member
+// does not belong to the household.
+func (s *RewardStore) CreateGoal(memberID, householdID int64, title string, targetPoints int) (*model.SavingsGoal, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO savings_goals (household_id, family_member_id, title, target_points)
+		 SELECT household_id, id, ?, ? FROM family_members WHERE id = ? AND household_id = ?`,
+		title, targetPoints, memberID, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert savings goal: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	} else if n == 0 {
+		return nil, nil
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("last insert id: %w", err)
+	}
+
+	row := s.db.QueryRow(`SELECT `+savingsGoalCols+` FROM savings_goals WHERE id = ?`, id)
+	return scanSavingsGoal(row)
+}
+
+// ListGoals returns a member's savings goals, oldest first.
+func (s *RewardStore) ListGoals(memberID, householdID int64) ([]model.SavingsGoal, error) {
+	rows, err := s.db.Query(
+		`SELECT `+savingsGoalCols+` FROM savings_goals WHERE family_member_id = ? AND household_id = ? ORDER BY created_at, id`,
+		memberID, householdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list savings goals: %w", err)
+	}
+	defer rows.Close()
+
+	var goals []model.SavingsGoal
+	for rows.Next() {
+		g, err := scanSavingsGoal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan savings goal: %w", err)
 		}
+		goals = append(goals, *g)
 	}
+	return goals, rows.Err()
+}
 
-	return balances, rows.Err()
+// DeleteGoal removes one of a member's savings goals.
+func (s *RewardStore) DeleteGoal(id, memberID, householdID int64) error {
+	_, err := s.db.Exec(`DELETE FROM savings_goals WHERE id = ? AND family_member_id = ? AND household_id = ?`, id, memberID, householdID)
+	if err != nil {
+		return fmt.Errorf("delete savings goal: %w", err)
+	}
+	return nil
 }
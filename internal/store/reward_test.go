@@ -2,9 +2,13 @@ package store
 
 import (
 	"database/sql"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/dukerupert/gamwich/internal/database"
+	"github.com/dukerupert/gamwich/internal/model"
 )
 
 func setupRewardTestDB(t *testing.T) (*RewardStore, *ChoreStore, *FamilyMemberStore) {
@@ -24,7 +28,7 @@ func TestRewardCRUD(t *testing.T) {
 	rs, _, _ := setupRewardTestDB(t)
 
 	// Create
-	reward, err := rs.Create("Ice Cream Trip", "Go get ice cream!", 50, true)
+	reward, err := rs.Create(testHouseholdID, "Ice Cream Trip", "Go get ice cream!", 50, true)
 	if err != nil {
 		t.Fatalf("create reward: %v", err)
 	}
@@ -42,7 +46,7 @@ func TestRewardCRUD(t *testing.T) {
 	}
 
 	// Get by ID
-	got, err := rs.GetByID(reward.ID)
+	got, err := rs.GetByID(reward.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get reward: %v", err)
 	}
@@ -54,7 +58,7 @@ func TestRewardCRUD(t *testing.T) {
 	}
 
 	// Update
-	updated, err := rs.Update(reward.ID, "Movie Night", "Watch a movie", 100, true)
+	updated, err := rs.Update(reward.ID, testHouseholdID, "Movie Night", "Watch a movie", 100, true)
 	if err != nil {
 		t.Fatalf("update reward: %v", err)
 	}
@@ -66,10 +70,10 @@ func TestRewardCRUD(t *testing.T) {
 	}
 
 	// Delete
-	if err := rs.Delete(reward.ID); err != nil {
+	if err := rs.Delete(reward.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete reward: %v", err)
 	}
-	got, err = rs.GetByID(reward.ID)
+	got, err = rs.GetByID(reward.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get deleted reward: %v", err)
 	}
@@ -81,7 +85,7 @@ func TestRewardCRUD(t *testing.T) {
 func TestRewardNotFound(t *testing.T) {
 	rs, _, _ := setupRewardTestDB(t)
 
-	got, err := rs.GetByID(999)
+	got, err := rs.GetByID(999, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get reward: %v", err)
 	}
@@ -93,11 +97,11 @@ func TestRewardNotFound(t *testing.T) {
 func TestRewardListOrdering(t *testing.T) {
 	rs, _, _ := setupRewardTestDB(t)
 
-	rs.Create("Zebra Reward", "", 10, true)
-	rs.Create("Alpha Reward", "", 20, true)
-	rs.Create("Beta Inactive", "", 5, false)
+	rs.Create(testHouseholdID, "Zebra Reward", "", 10, true)
+	rs.Create(testHouseholdID, "Alpha Reward", "", 20, true)
+	rs.Create(testHouseholdID, "Beta Inactive", "", 5, false)
 
-	rewards, err := rs.List()
+	rewards, err := rs.List(testHouseholdID)
 	if err != nil {
 		t.Fatalf("list rewards: %v", err)
 	}
@@ -120,11 +124,11 @@ func TestRewardListOrdering(t *testing.T) {
 func TestRewardListActive(t *testing.T) {
 	rs, _, _ := setupRewardTestDB(t)
 
-	rs.Create("Active One", "", 10, true)
-	rs.Create("Inactive", "", 20, false)
-	rs.Create("Active Two", "", 30, true)
+	rs.Create(testHouseholdID, "Active One", "", 10, true)
+	rs.Create(testHouseholdID, "Inactive", "", 20, false)
+	rs.Create(testHouseholdID, "Active Two", "", 30, true)
 
-	active, err := rs.ListActive()
+	active, err := rs.ListActive(testHouseholdID)
 	if err != nil {
 		t.Fatalf("list active: %v", err)
 	}
@@ -141,10 +145,11 @@ func TestRewardListActive(t *testing.T) {
 func TestRewardRedeem(t *testing.T) {
 	rs, _, ms := setupRewardTestDB(t)
 
-	member, _ := ms.Create("Alice", "#FF0000", "A")
-	reward, _ := rs.Create("Treat", "", 25, true)
+	member, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
+	reward, _ := rs.Create(testHouseholdID, "Treat", "", 25, true)
+	rs.AdjustPoints(member.ID, testHouseholdID, model.PointsBonus, 25, "Allowance", nil)
 
-	redemption, err := rs.Redeem(reward.ID, &member.ID, 25)
+	redemption, err := rs.Redeem(reward.ID, testHouseholdID, &member.ID, 25)
 	if err != nil {
 		t.Fatalf("redeem: %v", err)
 	}
@@ -162,15 +167,17 @@ func TestRewardRedeem(t *testing.T) {
 func TestRewardListRedemptionsByMember(t *testing.T) {
 	rs, _, ms := setupRewardTestDB(t)
 
-	alice, _ := ms.Create("Alice", "#FF0000", "A")
-	bob, _ := ms.Create("Bob", "#0000FF", "B")
-	reward, _ := rs.Create("Treat", "", 25, true)
+	alice, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
+	bob, _ := ms.Create(testHouseholdID, "Bob", "#0000FF", "B")
+	reward, _ := rs.Create(testHouseholdID, "Treat", "", 25, true)
+	rs.AdjustPoints(alice.ID, testHouseholdID, model.PointsBonus, 50, "Allowance", nil)
+	rs.AdjustPoints(bob.ID, testHouseholdID, model.PointsBonus, 25, "Allowance", nil)
 
-	rs.Redeem(reward.ID, &alice.ID, 25)
-	rs.Redeem(reward.ID, &alice.ID, 25)
-	rs.Redeem(reward.ID, &bob.ID, 25)
+	rs.Redeem(reward.ID, testHouseholdID, &alice.ID, 25)
+	rs.Redeem(reward.ID, testHouseholdID, &alice.ID, 25)
+	rs.Redeem(reward.ID, testHouseholdID, &bob.ID, 25)
 
-	aliceRedemptions, err := rs.ListRedemptionsByMember(alice.ID)
+	aliceRedemptions, err := rs.ListRedemptionsByMember(alice.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("list alice redemptions: %v", err)
 	}
@@ -178,7 +185,7 @@ func TestRewardListRedemptionsByMember(t *testing.T) {
 		t.Fatalf("expected 2 alice redemptions, got %d", len(aliceRedemptions))
 	}
 
-	bobRedemptions, _ := rs.ListRedemptionsByMember(bob.ID)
+	bobRedemptions, _ := rs.ListRedemptionsByMember(bob.ID, testHouseholdID)
 	if len(bobRedemptions) != 1 {
 		t.Fatalf("expected 1 bob redemption, got %d", len(bobRedemptions))
 	}
@@ -187,18 +194,18 @@ func TestRewardListRedemptionsByMember(t *testing.T) {
 func TestPointBalance(t *testing.T) {
 	rs, cs, ms := setupRewardTestDB(t)
 
-	member, _ := ms.Create("Alice", "#FF0000", "A")
-	chore, _ := cs.Create("Chore A", "", nil, 10, "", nil)
-	reward, _ := rs.Create("Treat", "", 15, true)
+	member, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
+	chore, _ := cs.Create(testHouseholdID, "Chore A", "", nil, 10, "", nil)
+	reward, _ := rs.Create(testHouseholdID, "Treat", "", 15, true)
 
 	// Earn 10+10 = 20 points
-	cs.CreateCompletion(chore.ID, &member.ID, 10)
-	cs.CreateCompletion(chore.ID, &member.ID, 10)
+	cs.CreateCompletion(chore.ID, testHouseholdID, &member.ID, 10)
+	cs.CreateCompletion(chore.ID, testHouseholdID, &member.ID, 10)
 
 	// Spend 15 points
-	rs.Redeem(reward.ID, &member.ID, 15)
+	rs.Redeem(reward.ID, testHouseholdID, &member.ID, 15)
 
-	balance, err := rs.GetPointBalance(member.ID)
+	balance, err := rs.GetPointBalance(member.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get point balance: %v", err)
 	}
@@ -219,9 +226,9 @@ func TestPointBalance(t *testing.T) {
 func TestPointBalanceNoActivity(t *testing.T) {
 	rs, _, ms := setupRewardTestDB(t)
 
-	member, _ := ms.Create("NewMember", "#00FF00", "N")
+	member, _ := ms.Create(testHouseholdID, "NewMember", "#00FF00", "N")
 
-	balance, err := rs.GetPointBalance(member.ID)
+	balance, err := rs.GetPointBalance(member.ID, testHouseholdID)
 	if err != nil {
 		t.Fatalf("get point balance: %v", err)
 	}
@@ -239,17 +246,17 @@ func TestPointBalanceNoActivity(t *testing.T) {
 func TestGetAllPointBalances(t *testing.T) {
 	rs, cs, ms := setupRewardTestDB(t)
 
-	alice, _ := ms.Create("Alice", "#FF0000", "A")
-	bob, _ := ms.Create("Bob", "#0000FF", "B")
-	chore, _ := cs.Create("Chore", "", nil, 10, "", nil)
+	alice, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
+	bob, _ := ms.Create(testHouseholdID, "Bob", "#0000FF", "B")
+	chore, _ := cs.Create(testHouseholdID, "Chore", "", nil, 10, "", nil)
 
 	// Alice earns 30, Bob earns 10
-	cs.CreateCompletion(chore.ID, &alice.ID, 10)
-	cs.CreateCompletion(chore.ID, &alice.ID, 10)
-	cs.CreateCompletion(chore.ID, &alice.ID, 10)
-	cs.CreateCompletion(chore.ID, &bob.ID, 10)
+	cs.CreateCompletion(chore.ID, testHouseholdID, &alice.ID, 10)
+	cs.CreateCompletion(chore.ID, testHouseholdID, &alice.ID, 10)
+	cs.CreateCompletion(chore.ID, testHouseholdID, &alice.ID, 10)
+	cs.CreateCompletion(chore.ID, testHouseholdID, &bob.ID, 10)
 
-	balances, err := rs.GetAllPointBalances()
+	balances, err := rs.GetAllPointBalances(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get all balances: %v", err)
 	}
@@ -275,21 +282,22 @@ func TestGetAllPointBalances(t *testing.T) {
 func TestDeleteRewardCascadesRedemptions(t *testing.T) {
 	rs, _, ms := setupRewardTestDB(t)
 
-	member, _ := ms.Create("Alice", "#FF0000", "A")
-	reward, _ := rs.Create("Treat", "", 25, true)
-	rs.Redeem(reward.ID, &member.ID, 25)
+	member, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
+	reward, _ := rs.Create(testHouseholdID, "Treat", "", 25, true)
+	rs.AdjustPoints(member.ID, testHouseholdID, model.PointsBonus, 25, "Allowance", nil)
+	rs.Redeem(reward.ID, testHouseholdID, &member.ID, 25)
 
-	redemptions, _ := rs.ListRedemptionsByMember(member.ID)
+	redemptions, _ := rs.ListRedemptionsByMember(member.ID, testHouseholdID)
 	if len(redemptions) != 1 {
 		t.Fatalf("expected 1 redemption, got %d", len(redemptions))
 	}
 
 	// Delete reward should cascade
-	if err := rs.Delete(reward.ID); err != nil {
+	if err := rs.Delete(reward.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete reward: %v", err)
 	}
 
-	redemptions, _ = rs.ListRedemptionsByMember(member.ID)
+	redemptions, _ = rs.ListRedemptionsByMember(member.ID, testHouseholdID)
 	if len(redemptions) != 0 {
 		t.Errorf("expected 0 redemptions after cascade, got %d", len(redemptions))
 	}
@@ -298,11 +306,12 @@ func TestDeleteRewardCascadesRedemptions(t *testing.T) {
 func TestDeleteMemberSetsNullOnRedemption(t *testing.T) {
 	rs, _, ms := setupRewardTestDB(t)
 
-	member, _ := ms.Create("Alice", "#FF0000", "A")
-	reward, _ := rs.Create("Treat", "", 25, true)
-	rs.Redeem(reward.ID, &member.ID, 25)
+	member, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "A")
+	reward, _ := rs.Create(testHouseholdID, "Treat", "", 25, true)
+	rs.AdjustPoints(member.ID, testHouseholdID, model.PointsBonus, 25, "Allowance", nil)
+	rs.Redeem(reward.ID, testHouseholdID, &member.ID, 25)
 
-	if err := ms.Delete(member.ID); err != nil {
+	if err := ms.Delete(member.ID, testHouseholdID); err != nil {
 		t.Fatalf("delete member: %v", err)
 	}
 
@@ -333,7 +342,7 @@ func TestLeaderboardSettingSeedData(t *testing.T) {
 	defer db.Close()
 
 	ss := NewSettingsStore(db)
-	val, err := ss.Get("rewards_leaderboard_enabled")
+	val, err := ss.Get(testHouseholdID, "rewards_leaderboard_enabled")
 	if err != nil {
 		t.Fatalf("get setting: %v", err)
 	}
@@ -341,3 +350,419 @@ func TestLeaderboardSettingSeedData(t *testing.T) {
 		t.Errorf("leaderboard setting = %q, want %q", val, "true")
 	}
 }
+
+func TestRewardHouseholdIsolation(t *testing.T) {
+	rs, cs, ms := setupRewardTestDB(t)
+	otherID := createTestHousehold(t, rs.db, "Other")
+
+	member, _ := ms.Create(testHouseholdID, "Alice", "#FF0000", "😀")
+	c, _ := cs.Create(testHouseholdID, "Dishes", "", nil, 10, "", nil)
+	cs.CreateCompletion(c.ID, testHouseholdID, &member.ID, 10)
+	reward, err := rs.Create(testHouseholdID, "Ice cream", "", 5, true)
+	if err != nil {
+		t.Fatalf("create reward: %v", err)
+	}
+
+	if got, _ := rs.GetByID(reward.ID, otherID); got != nil {
+		t.Error("expected nil when reading another household's reward")
+	}
+	rewards, _ := rs.List(otherID)
+	if len(rewards) != 0 {
+		t.Errorf("other household sees %d rewards, want 0", len(rewards))
+	}
+	if got, _ := rs.Redeem(reward.ID, otherID, &member.ID, 5); got != nil {
+		t.Error("expected nil when redeeming another household's reward")
+	}
+	balances, _ := rs.GetAllPointBalances(otherID)
+	if len(balances) != 0 {
+		t.Errorf("other household sees %d balances, want 0", len(balances))
+	}
+	balance, _ := rs.GetPointBalance(member.ID, otherID)
+	if balance.TotalEarned != 0 {
+		t.Errorf("other household sees %d earned points, want 0", balance.TotalEarned)
+	}
+	if err := rs.Delete(reward.ID, otherID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if got, _ := rs.GetByID(reward.ID, testHouseholdID); got == nil {
+		t.Error("reward was deleted by another household")
+	}
+	balance, _ = rs.GetPointBalance(member.ID, testHouseholdID)
+	if balance.Balance != 10 {
+		t.Errorf("balance = %d, want 10", balance.Balance)
+	}
+}
+
+func TestPointLedger(t *testing.T) {
+	rs, cs, ms := setupRewardTestDB(t)
+	otherID := createTestHousehold(t, rs.db, "Other")
+
+	kid, _ := ms.Create(testHouseholdID, "Kid", "#FF0000", "K")
+	parent, _ := ms.Create(testHouseholdID, "Parent", "#0000FF", "P")
+	c, _ := cs.Create(testHouseholdID, "Dishes", "", nil, 10, "", nil)
+	reward, _ := rs.Create(testHouseholdID, "Treat", "", 4, true)
+
+	first, _ := cs.CreateCompletion(c.ID, testHouseholdID, &kid.ID, 10)
+	cs.CreateCompletion(c.ID, testHouseholdID, &kid.ID, 10)
+	cs.CreateCompletion(c.ID, testHouseholdID, nil, 10) // nobody to credit
+	rs.Redeem(reward.ID, testHouseholdID, &kid.ID, 4)
+
+	bonus, err := rs.AdjustPoints(kid.ID, testHouseholdID, model.PointsBonus, 5, "Helped a neighbour", &parent.ID)
+	if err != nil {
+		t.Fatalf("adjust points: %v", err)
+	}
+	if bonus.Kind != model.PointsBonus || bonus.Amount != 5 || bonus.ActorID == nil || *bonus.ActorID != parent.ID {
+		t.Errorf("bonus = %+v", bonus)
+	}
+	rs.AdjustPoints(kid.ID, testHouseholdID, model.PointsPenalty, -3, "Left bike out", &parent.ID)
+	if got, _ := rs.AdjustPoints(kid.ID, otherID, model.PointsBonus, 100, "Not yours", nil); got != nil {
+		t.Error("another household adjusted the member's points")
+	}
+
+	// Undoing a completion takes its points back with a correction.
+	if err := cs.DeleteCompletion(first.ID, testHouseholdID); err != nil {
+		t.Fatalf("delete completion: %v", err)
+	}
+
+	balance, _ := rs.GetPointBalance(kid.ID, testHouseholdID)
+	if balance.TotalEarned != 12 || balance.TotalSpent != 4 || balance.Balance != 8 {
+		t.Errorf("balance = %+v, want earned 12, spent 4, balance 8", balance)
+	}
+
+	txns, err := rs.ListTransactions(kid.ID, testHouseholdID, 0)
+	if err != nil {
+		t.Fatalf("list transactions: %v", err)
+	}
+	wantKinds := []model.PointTransactionKind{model.PointsCorrection, model.PointsPenalty, model.PointsBonus, model.PointsSpent, model.PointsEarned, model.PointsEarned}
+	if len(txns) != len(wantKinds) {
+		t.Fatalf("got %d transactions, want %d", len(txns), len(wantKinds))
+	}
+	for i, k := range wantKinds {
+		if txns[i].Kind != k {
+			t.Errorf("txns[%d].Kind = %s, want %s", i, txns[i].Kind, k)
+		}
+	}
+	if txns[0].Amount != -10 || txns[0].Reason != "Undone: Dishes" {
+		t.Errorf("correction = %+v", txns[0])
+	}
+	if limited, _ := rs.ListTransactions(kid.ID, testHouseholdID, 2); len(limited) != 2 {
+		t.Errorf("limited transactions = %d, want 2", len(limited))
+	}
+	if other, _ := rs.ListTransactions(kid.ID, otherID, 0); len(other) != 0 {
+		t.Errorf("other household sees %d transactions", len(other))
+	}
+
+	// Points outlive the chore and reward they came from.
+	cs.Delete(c.ID, testHouseholdID)
+	rs.Delete(reward.ID, testHouseholdID)
+	if after, _ := rs.GetPointBalance(kid.ID, testHouseholdID); after.Balance != balance.Balance {
+		t.Errorf("balance after deleting chore and reward = %d, want %d", after.Balance, balance.Balance)
+	}
+
+	balances, _ := rs.GetAllPointBalances(testHouseholdID)
+	if len(balances) != 2 || balances[0].MemberID != kid.ID || balances[1].Balance != 0 {
+		t.Errorf("balances = %+v", balances)
+	}
+}
+
+func TestRedemptionLifecycle(t *testing.T) {
+	rs, _, ms := setupRewardTestDB(t)
+	otherID := createTestHousehold(t, rs.db, "Other")
+
+	kid, _ := ms.Create(testHouseholdID, "Kid", "#FF0000", "K")
+	parent, _ := ms.Create(testHouseholdID, "Parent", "#0000FF", "P")
+	reward, _ := rs.Create(testHouseholdID, "Movie night", "", 10, true)
+	rs.AdjustPoints(kid.ID, testHouseholdID, model.PointsBonus, 30, "Allowance", nil)
+
+	first, err := rs.Redeem(reward.ID, testHouseholdID, &kid.ID, 10)
+	if err != nil {
+		t.Fatalf("redeem: %v", err)
+	}
+	if first.Status != model.RedemptionRequested {
+		t.Errorf("status = %s, want requested", first.Status)
+	}
+	second, _ := rs.Redeem(reward.ID, testHouseholdID, &kid.ID, 10)
+
+	open, err := rs.ListOpenRedemptions(testHouseholdID)
+	if err != nil {
+		t.Fatalf("list open redemptions: %v", err)
+	}
+	if len(open) != 2 || open[0].ID != first.ID {
+		t.Fatalf("open = %+v, want both, oldest first", open)
+	}
+
+	// Requested redemptions cannot be marked approved twice, and another
+	// household cannot touch them.
+	approved, err := rs.UpdateRedemptionStatus(first.ID, testHouseholdID, model.RedemptionApproved, parent.ID, "")
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if approved.Status != model.RedemptionApproved || approved.ReviewedBy == nil || *approved.ReviewedBy != parent.ID || approved.ReviewedAt == nil {
+		t.Errorf("approved = %+v", approved)
+	}
+	if got, _ := rs.UpdateRedemptionStatus(first.ID, testHouseholdID, model.RedemptionApproved, parent.ID, ""); got != nil {
+		t.Error("approved a redemption twice")
+	}
+	if got, _ := rs.UpdateRedemptionStatus(second.ID, otherID, model.RedemptionCancelled, parent.ID, ""); got != nil {
+		t.Error("another household cancelled the redemption")
+	}
+
+	fulfilled, _ := rs.UpdateRedemptionStatus(first.ID, testHouseholdID, model.RedemptionFulfilled, parent.ID, "")
+	if fulfilled.Status != model.RedemptionFulfilled || fulfilled.FulfilledBy == nil || fulfilled.FulfilledAt == nil {
+		t.Errorf("fulfilled = %+v", fulfilled)
+	}
+	if got, _ := rs.UpdateRedemptionStatus(first.ID, testHouseholdID, model.RedemptionCancelled, parent.ID, ""); got != nil {
+		t.Error("cancelled a fulfilled redemption")
+	}
+
+	// Cancelling refunds the points.
+	cancelled, _ := rs.UpdateRedemptionStatus(second.ID, testHouseholdID, model.RedemptionCancelled, parent.ID, "Changed plans")
+	if cancelled.Status != model.RedemptionCancelled || cancelled.Note != "Changed plans" {
+		t.Errorf("cancelled = %+v", cancelled)
+	}
+	balance, _ := rs.GetPointBalance(kid.ID, testHouseholdID)
+	if balance.TotalSpent != 10 || balance.Balance != 20 {
+		t.Errorf("balance = %+v, want spent 10, balance 20", balance)
+	}
+	txns, _ := rs.ListTransactions(kid.ID, testHouseholdID, 1)
+	if len(txns) != 1 || txns[0].Amount != 10 || txns[0].Reason != "Refunded: Movie night" {
+		t.Errorf("refund = %+v", txns)
+	}
+	if open, _ := rs.ListOpenRedemptions(testHouseholdID); len(open) != 0 {
+		t.Errorf("open after fulfilling and cancelling = %d, want 0", len(open))
+	}
+}
+
+func TestRedeemLimits(t *testing.T) {
+	rs, _, ms := setupRewardTestDB(t)
+
+	kid, _ := ms.Create(testHouseholdID, "Kid", "#FF0000", "K")
+	parent, _ := ms.Create(testHouseholdID, "Parent", "#0000FF", "P")
+	rs.AdjustPoints(kid.ID, testHouseholdID, model.PointsBonus, 10, "Allowance", nil)
+
+	pricey, _ := rs.Create(testHouseholdID, "Bike", "", 50, true)
+	if _, err := rs.Redeem(pricey.ID, testHouseholdID, &kid.ID, 50); !errors.Is(err, ErrInsufficientPoints) {
+		t.Errorf("redeem without enough points: err = %v, want ErrInsufficientPoints", err)
+	}
+
+	one := 1
+	sticker, _ := rs.Create(testHouseholdID, "Sticker", "", 1, true)
+	if err := rs.SetLimits(sticker.ID, testHouseholdID, &one, 0); err != nil {
+		t.Fatalf("set limits: %v", err)
+	}
+	got, err := rs.Redeem(sticker.ID, testHouseholdID, &kid.ID, 1)
+	if err != nil {
+		t.Fatalf("redeem last sticker: %v", err)
+	}
+	if r, _ := rs.GetByID(sticker.ID, testHouseholdID); r.Stock == nil || *r.Stock != 0 {
+		t.Errorf("stock after redeeming = %v, want 0", r.Stock)
+	}
+	if _, err := rs.Redeem(sticker.ID, testHouseholdID, &kid.ID, 1); !errors.Is(err, ErrOutOfStock) {
+		t.Errorf("redeem out of stock: err = %v, want ErrOutOfStock", err)
+	}
+	rs.UpdateRedemptionStatus(got.ID, testHouseholdID, model.RedemptionCancelled, parent.ID, "")
+	if r, _ := rs.GetByID(sticker.ID, testHouseholdID); r.Stock == nil || *r.Stock != 1 {
+		t.Errorf("stock after cancelling = %v, want 1", r.Stock)
+	}
+
+	movie, _ := rs.Create(testHouseholdID, "Movie night", "", 2, true)
+	rs.SetLimits(movie.ID, testHouseholdID, nil, 7)
+	first, err := rs.Redeem(movie.ID, testHouseholdID, &kid.ID, 2)
+	if err != nil {
+		t.Fatalf("redeem movie night: %v", err)
+	}
+	if _, err := rs.Redeem(movie.ID, testHouseholdID, &kid.ID, 2); !errors.Is(err, ErrOnCooldown) {
+		t.Errorf("redeem again within a week: err = %v, want ErrOnCooldown", err)
+	}
+	next, err := rs.NextRedeemable(kid.ID, testHouseholdID)
+	if err != nil {
+		t.Fatalf("next redeemable: %v", err)
+	}
+	if at, ok := next[movie.ID]; !ok || at.Before(first.RedeemedAt.AddDate(0, 0, 7).Add(-time.Second)) {
+		t.Errorf("next redeemable = %v, want a week after %v", next, first.RedeemedAt)
+	}
+	if _, ok := next[sticker.ID]; ok {
+		t.Error("reward without a cooldown has a next redeemable time")
+	}
+
+	rs.db.Exec(`UPDATE reward_redemptions SET redeemed_at = datetime('now', '-8 days') WHERE id = ?`, first.ID)
+	if _, err := rs.Redeem(movie.ID, testHouseholdID, &kid.ID, 2); err != nil {
+		t.Errorf("redeem after the cooldown: %v", err)
+	}
+}
+
+func TestRedeemConcurrently(t *testing.T) {
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	// Every connection to :memory: is its own database, so share one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	rs, ms := NewRewardStore(db), NewFamilyMemberStore(db)
+
+	kid, _ := ms.Create(testHouseholdID, "Kid", "#FF0000", "K")
+	reward, _ := rs.Create(testHouseholdID, "Treat", "", 10, true)
+	rs.AdjustPoints(kid.ID, testHouseholdID, model.PointsBonus, 10, "Allowance", nil)
+
+	const taps = 8
+	errs := make([]error, taps)
+	var wg sync.WaitGroup
+	for i := range taps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = rs.Redeem(reward.ID, testHouseholdID, &kid.ID, 10)
+		}()
+	}
+	wg.Wait()
+
+	redeemed := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			redeemed++
+		case !errors.Is(err, ErrInsufficientPoints):
+			t.Errorf("redeem: %v", err)
+		}
+	}
+	if redeemed != 1 {
+		t.Errorf("redeemed %d times, want 1", redeemed)
+	}
+	if balance, _ := rs.GetPointBalance(kid.ID, testHouseholdID); balance.Balance != 0 {
+		t.Errorf("balance = %d, want 0", balance.Balance)
+	}
+}
+
+func TestAllowance(t *testing.T) {
+	rs, _, ms := setupRewardTestDB(t)
+	otherID := createTestHousehold(t, rs.db, "Other")
+	kid, _ := ms.Create(testHouseholdID, "Kid", "#FF0000", "K")
+
+	if a, err := rs.GetAllowance(kid.ID, testHouseholdID); err != nil || a != nil {
+		t.Fatalf("allowance before setting = %+v, %v", a, err)
+	}
+	if a, _ := rs.SetAllowance(kid.ID, otherID, 10, 20, time.Saturday, "2026-02-01"); a != nil {
+		t.Error("another household set the member's allowance")
+	}
+
+	// Points are worth money before any allowance is paid.
+	if _, err := rs.SetAllowance(kid.ID, testHouseholdID, 10, 0, time.Saturday, "2026-01-01"); err != nil {
+		t.Fatalf("set allowance: %v", err)
+	}
+	rs.AdjustPoints(kid.ID, testHouseholdID, model.PointsBonus, 5, "Birthday", nil)
+	if b, _ := rs.GetPointBalance(kid.ID, testHouseholdID); b.CentsPerPoint != 10 || b.ValueCents != 50 {
+		t.Errorf("balance = %+v, want worth 50 cents", b)
+	}
+	if ids, _ := rs.ListAllowanceHouseholdIDs(); len(ids) != 0 {
+		t.Errorf("households paying allowances = %v, want none", ids)
+	}
+
+	// Turning the allowance on starts it from then, not from when the
+	// rate was first set.
+	a, err := rs.SetAllowance(kid.ID, testHouseholdID, 10, 20, time.Saturday, "2026-02-01")
+	if err != nil {
+		t.Fatalf("set allowance: %v", err)
+	}
+	if a.StartsOn != "2026-02-01" || a.WeeklyPoints != 20 || a.Weekday != time.Saturday {
+		t.Errorf("allowance = %+v", a)
+	}
+
+	for _, tc := range []struct {
+		day  string
+		want bool
+	}{
+		{"2026-01-31", false}, // before it started
+		{"2026-02-07", true},
+		{"2026-02-07", false}, // already paid
+		{"2026-02-14", true},
+		{"2026-02-07", false}, // out of order
+	} {
+		if paid, err := rs.DepositAllowance(kid.ID, testHouseholdID, tc.day); err != nil || paid != tc.want {
+			t.Errorf("deposit %s = %v, %v; want %v", tc.day, paid, err, tc.want)
+		}
+	}
+	if paid, _ := rs.DepositAllowance(kid.ID, otherID, "2026-02-21"); paid {
+		t.Error("another household paid the member's allowance")
+	}
+
+	// Changing the rate keeps the deposit history.
+	a, _ = rs.SetAllowance(kid.ID, testHouseholdID, 5, 20, time.Sunday, "2026-03-01")
+	if a.StartsOn != "2026-02-01" || a.LastDeposit != "2026-02-14" || a.Weekday != time.Sunday {
+		t.Errorf("allowance after update = %+v", a)
+	}
+
+	b, _ := rs.GetPointBalance(kid.ID, testHouseholdID)
+	if b.Balance != 45 || b.ValueCents != 225 {
+		t.Errorf("balance = %+v, want 45 points worth 225 cents", b)
+	}
+	txns, _ := rs.ListTransactions(kid.ID, testHouseholdID, 1)
+	if len(txns) != 1 || txns[0].Kind != model.PointsAllowance || txns[0].Amount != 20 {
+		t.Errorf("latest transaction = %+v", txns)
+	}
+
+	if list, _ := rs.ListAllowances(testHouseholdID); len(list) != 1 || list[0].FamilyMemberID != kid.ID {
+		t.Errorf("allowances = %+v", list)
+	}
+	if ids, _ := rs.ListAllowanceHouseholdIDs(); len(ids) != 1 || ids[0] != testHouseholdID {
+		t.Errorf("households paying allowances = %v", ids)
+	}
+
+	// Pausing and restarting the allowance does not pay for the pause.
+	rs.SetAllowance(kid.ID, testHouseholdID, 5, 0, time.Sunday, "2026-03-01")
+	a, _ = rs.SetAllowance(kid.ID, testHouseholdID, 5, 20, time.Sunday, "2026-06-01")
+	if a.StartsOn != "2026-06-01" || a.LastDeposit != "" {
+		t.Errorf("restarted allowance = %+v", a)
+	}
+}
+
+func TestSavingsGoals(t *testing.T) {
+	rs, _, ms := setupRewardTestDB(t)
+	otherID := createTestHousehold(t, rs.db, "Other")
+	kid, _ := ms.Create(testHouseholdID, "Kid", "#FF0000", "K")
+
+	bike, err := rs.CreateGoal(kid.ID, testHouseholdID, "Bike", 100)
+	if err != nil {
+		t.Fatalf("create goal: %v", err)
+	}
+	rs.CreateGoal(kid.ID, testHouseholdID, "Game", 20)
+	if g, _ := rs.CreateGoal(kid.ID, otherID, "Not yours", 10); g != nil {
+		t.Error("another household created a goal for the member")
+	}
+
+	goals, err := rs.ListGoals(kid.ID, testHouseholdID)
+	if err != nil {
+		t.Fatalf("list goals: %v", err)
+	}
+	if len(goals) != 2 || goals[0].ID != bike.ID || goals[1].Title != "Game" {
+		t.Fatalf("goals = %+v", goals)
+	}
+
+	if p := goals[0].Progress(25); p.Saved != 25 || p.Percent != 25 || p.Reached {
+		t.Errorf("bike progress = %+v", p)
+	}
+	if p := goals[1].Progress(25); p.Saved != 20 || p.Percent != 100 || !p.Reached {
+		t.Errorf("game progress = %+v", p)
+	}
+	if p := goals[1].Progress(-5); p.Saved != 0 || p.Percent != 0 {
+		t.Errorf("progress in debt = %+v", p)
+	}
+
+	rs.DeleteGoal(bike.ID, kid.ID, otherID)
+	if goals, _ := rs.ListGoals(kid.ID, testHouseholdID); len(goals) != 2 {
+		t.Errorf("another household deleted a goal")
+	}
+	rs.DeleteGoal(bike.ID, kid.ID, testHouseholdID)
+	if goals, _ := rs.ListGoals(kid.ID, testHouseholdID); len(goals) != 1 {
+		t.Errorf("goals after delete = %d, want 1", len(goals))
+	}
+
+	// Goals go with the member.
+	ms.Delete(kid.ID, testHouseholdID)
+	var n int
+	rs.db.QueryRow(`SELECT COUNT(*) FROM savings_goals`).Scan(&n)
+	if n != 0 {
+		t.Errorf("%d goals left after deleting member", n)
+	}
+}
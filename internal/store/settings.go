@@ -53,6 +53,16 @@ var vapidKeys = []string{
 	"vapid_private_key",
 }
 
+// TimezoneKey is the setting holding a household's IANA timezone name, such
+// as "America/Denver". Due dates, calendar days, reminders, and backup
+// schedules are computed in this timezone.
+const TimezoneKey = "timezone"
+
+// DefaultHouseholdID is the household that owns install-wide settings such as
+// the license key, tunnel token, S3 credentials, and VAPID keys. It is the
+// household that pre-multi-tenant data was migrated into.
+const DefaultHouseholdID int64 = 1
+
 type SettingsStore struct {
 	db *sql.DB
 }
@@ -61,9 +71,9 @@ func NewSettingsStore(db *sql.DB) *SettingsStore {
 	return &SettingsStore{db: db}
 }
 
-func (s *SettingsStore) Get(key string) (string, error) {
+func (s *SettingsStore) Get(householdID int64, key string) (string, error) {
 	var value string
-	err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	err := s.db.QueryRow(`SELECT value FROM settings WHERE household_id = ? AND key = ?`, householdID, key).Scan(&value)
 	if err == sql.ErrNoRows {
 		return "", fmt.Errorf("setting %q not found", key)
 	}
@@ -73,8 +83,8 @@ func (s *SettingsStore) Get(key string) (string, error) {
 	return value, nil
 }
 
-func (s *SettingsStore) GetAll() (map[string]string, error) {
-	rows, err := s.db.Query(`SELECT key, value FROM settings ORDER BY key`)
+func (s *SettingsStore) GetAll(householdID int64) (map[string]string, error) {
+	rows, err := s.db.Query(`SELECT key, value FROM settings WHERE household_id = ? ORDER BY key`, householdID)
 	if err != nil {
 		return nil, fmt.Errorf("get all settings: %w", err)
 	}
@@ -91,11 +101,11 @@ func (s *SettingsStore) GetAll() (map[string]string, error) {
 	return settings, rows.Err()
 }
 
-func (s *SettingsStore) Set(key, value string) error {
+func (s *SettingsStore) Set(householdID int64, key, value string) error {
 	_, err := s.db.Exec(
-		`INSERT INTO settings (household_id, key, value, updated_at) VALUES (1, ?, ?, ?)
+		`INSERT INTO settings (household_id, key, value, updated_at) VALUES (?, ?, ?, ?)
 		 ON CONFLICT(household_id, key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
-		key, value, time.Now().UTC(),
+		householdID, key, value, time.Now().UTC(),
 	)
 	if err != nil {
 		return fmt.Errorf("set setting %q: %w", key, err)
@@ -103,11 +113,11 @@ func (s *SettingsStore) Set(key, value string) error {
 	return nil
 }
 
-func (s *SettingsStore) GetKioskSettings() (map[string]string, error) {
+func (s *SettingsStore) GetKioskSettings(householdID int64) (map[string]string, error) {
 	settings := make(map[string]string)
 	for _, key := range kioskKeys {
 		var value string
-		err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+		err := s.db.QueryRow(`SELECT value FROM settings WHERE household_id = ? AND key = ?`, householdID, key).Scan(&value)
 		if err == sql.ErrNoRows {
 			continue
 		}
@@ -119,11 +129,11 @@ func (s *SettingsStore) GetKioskSettings() (map[string]string, error) {
 	return settings, nil
 }
 
-func (s *SettingsStore) GetWeatherSettings() (map[string]string, error) {
+func (s *SettingsStore) GetWeatherSettings(householdID int64) (map[string]string, error) {
 	settings := make(map[string]string)
 	for _, key := range weatherKeys {
 		var value string
-		err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+		err := s.db.QueryRow(`SELECT value FROM settings WHERE household_id = ? AND key = ?`, householdID, key).Scan(&value)
 		if err == sql.ErrNoRows {
 			continue
 		}
@@ -135,11 +145,11 @@ func (s *SettingsStore) GetWeatherSettings() (map[string]string, error) {
 	return settings, nil
 }
 
-func (s *SettingsStore) GetThemeSettings() (map[string]string, error) {
+func (s *SettingsStore) GetThemeSettings(householdID int64) (map[string]string, error) {
 	settings := make(map[string]string)
 	for _, key := range themeKeys {
 		var value string
-		err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+		err := s.db.QueryRow(`SELECT value FROM settings WHERE household_id = ? AND key = ?`, householdID, key).Scan(&value)
 		if err == sql.ErrNoRows {
 			continue
 		}
@@ -151,11 +161,11 @@ func (s *SettingsStore) GetThemeSettings() (map[string]string, error) {
 	return settings, nil
 }
 
-func (s *SettingsStore) GetTunnelSettings() (map[string]string, error) {
+func (s *SettingsStore) GetTunnelSettings(householdID int64) (map[string]string, error) {
 	settings := make(map[string]string)
 	for _, key := range tunnelKeys {
 		var value string
-		err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+		err := s.db.QueryRow(`SELECT value FROM settings WHERE household_id = ? AND key = ?`, householdID, key).Scan(&value)
 		if err == sql.ErrNoRows {
 			continue
 		}
@@ -167,11 +177,11 @@ func (s *SettingsStore) GetTunnelSettings() (map[string]string, error) {
 	return settings, nil
 }
 
-func (s *SettingsStore) GetBackupSettings() (map[string]string, error) {
+func (s *SettingsStore) GetBackupSettings(householdID int64) (map[string]string, error) {
 	settings := make(map[string]string)
 	for _, key := range backupKeys {
 		var value string
-		err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+		err := s.db.QueryRow(`SELECT value FROM settings WHERE household_id = ? AND key = ?`, householdID, key).Scan(&value)
 		if err == sql.ErrNoRows {
 			continue
 		}
@@ -183,11 +193,11 @@ func (s *SettingsStore) GetBackupSettings() (map[string]string, error) {
 	return settings, nil
 }
 
-func (s *SettingsStore) GetS3Settings() (map[string]string, error) {
+func (s *SettingsStore) GetS3Settings(householdID int64) (map[string]string, error) {
 	settings := make(map[string]string)
 	for _, key := range s3Keys {
 		var value string
-		err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+		err := s.db.QueryRow(`SELECT value FROM settings WHERE household_id = ? AND key = ?`, householdID, key).Scan(&value)
 		if err == sql.ErrNoRows {
 			continue
 		}
@@ -199,11 +209,11 @@ func (s *SettingsStore) GetS3Settings() (map[string]string, error) {
 	return settings, nil
 }
 
-func (s *SettingsStore) GetVAPIDSettings() (map[string]string, error) {
+func (s *SettingsStore) GetVAPIDSettings(householdID int64) (map[string]string, error) {
 	settings := make(map[string]string)
 	for _, key := range vapidKeys {
 		var value string
-		err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+		err := s.db.QueryRow(`SELECT value FROM settings WHERE household_id = ? AND key = ?`, householdID, key).Scan(&value)
 		if err == sql.ErrNoRows {
 			continue
 		}
@@ -214,3 +224,22 @@ func (s *SettingsStore) GetVAPIDSettings() (map[string]string, error) {
 	}
 	return settings, nil
 }
+
+// Location returns the household's timezone. Households without a valid
+// timezone setting use UTC. The returned location is never nil, so callers
+// may log the error and carry on.
+func (s *SettingsStore) Location(householdID int64) (*time.Location, error) {
+	var name string
+	err := s.db.QueryRow(`SELECT value FROM settings WHERE household_id = ? AND key = ?`, householdID, TimezoneKey).Scan(&name)
+	if err == sql.ErrNoRows || (err == nil && name == "") {
+		return time.UTC, nil
+	}
+	if err != nil {
+		return time.UTC, fmt.Errorf("get timezone: %w", err)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC, fmt.Errorf("load timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
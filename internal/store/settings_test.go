@@ -2,6 +2,7 @@ package store
 
 import (
 	"testing"
+	"time"
 
 	"github.com/dukerupert/gamwich/internal/database"
 )
@@ -22,7 +23,7 @@ func setupSettingsTestDB(t *testing.T) *SettingsStore {
 func TestSettingsSeedData(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
-	settings, err := ss.GetKioskSettings()
+	settings, err := ss.GetKioskSettings(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get kiosk settings: %v", err)
 	}
@@ -50,7 +51,7 @@ func TestSettingsSeedData(t *testing.T) {
 func TestSettingsGet(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
-	val, err := ss.Get("idle_timeout_minutes")
+	val, err := ss.Get(testHouseholdID, "idle_timeout_minutes")
 	if err != nil {
 		t.Fatalf("get: %v", err)
 	}
@@ -62,7 +63,7 @@ func TestSettingsGet(t *testing.T) {
 func TestSettingsGetNotFound(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
-	_, err := ss.Get("nonexistent_key")
+	_, err := ss.Get(testHouseholdID, "nonexistent_key")
 	if err == nil {
 		t.Fatal("expected error for nonexistent key, got nil")
 	}
@@ -72,11 +73,11 @@ func TestSettingsSet(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
 	// Update existing
-	if err := ss.Set("idle_timeout_minutes", "10"); err != nil {
+	if err := ss.Set(testHouseholdID, "idle_timeout_minutes", "10"); err != nil {
 		t.Fatalf("set: %v", err)
 	}
 
-	val, err := ss.Get("idle_timeout_minutes")
+	val, err := ss.Get(testHouseholdID, "idle_timeout_minutes")
 	if err != nil {
 		t.Fatalf("get after set: %v", err)
 	}
@@ -85,11 +86,11 @@ func TestSettingsSet(t *testing.T) {
 	}
 
 	// Insert new
-	if err := ss.Set("custom_key", "custom_value"); err != nil {
+	if err := ss.Set(testHouseholdID, "custom_key", "custom_value"); err != nil {
 		t.Fatalf("set new key: %v", err)
 	}
 
-	val, err = ss.Get("custom_key")
+	val, err = ss.Get(testHouseholdID, "custom_key")
 	if err != nil {
 		t.Fatalf("get new key: %v", err)
 	}
@@ -101,7 +102,7 @@ func TestSettingsSet(t *testing.T) {
 func TestSettingsGetAll(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
-	all, err := ss.GetAll()
+	all, err := ss.GetAll(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get all: %v", err)
 	}
@@ -120,11 +121,11 @@ func TestSettingsGetKioskSettings(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
 	// Add a non-kiosk setting
-	if err := ss.Set("non_kiosk_key", "value"); err != nil {
+	if err := ss.Set(testHouseholdID, "non_kiosk_key", "value"); err != nil {
 		t.Fatalf("set: %v", err)
 	}
 
-	kiosk, err := ss.GetKioskSettings()
+	kiosk, err := ss.GetKioskSettings(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get kiosk: %v", err)
 	}
@@ -141,7 +142,7 @@ func TestSettingsGetKioskSettings(t *testing.T) {
 func TestSettingsWeatherSeedData(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
-	settings, err := ss.GetWeatherSettings()
+	settings, err := ss.GetWeatherSettings(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get weather settings: %v", err)
 	}
@@ -168,11 +169,11 @@ func TestSettingsGetWeatherSettings(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
 	// Add a non-weather setting
-	if err := ss.Set("non_weather_key", "value"); err != nil {
+	if err := ss.Set(testHouseholdID, "non_weather_key", "value"); err != nil {
 		t.Fatalf("set: %v", err)
 	}
 
-	weather, err := ss.GetWeatherSettings()
+	weather, err := ss.GetWeatherSettings(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get weather: %v", err)
 	}
@@ -189,7 +190,7 @@ func TestSettingsGetWeatherSettings(t *testing.T) {
 func TestSettingsThemeSeedData(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
-	settings, err := ss.GetThemeSettings()
+	settings, err := ss.GetThemeSettings(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get theme settings: %v", err)
 	}
@@ -217,11 +218,11 @@ func TestSettingsGetThemeSettings(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
 	// Add a non-theme setting
-	if err := ss.Set("non_theme_key", "value"); err != nil {
+	if err := ss.Set(testHouseholdID, "non_theme_key", "value"); err != nil {
 		t.Fatalf("set: %v", err)
 	}
 
-	theme, err := ss.GetThemeSettings()
+	theme, err := ss.GetThemeSettings(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get theme: %v", err)
 	}
@@ -238,7 +239,7 @@ func TestSettingsGetThemeSettings(t *testing.T) {
 func TestSettingsS3SeedData(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
-	settings, err := ss.GetS3Settings()
+	settings, err := ss.GetS3Settings(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get s3 settings: %v", err)
 	}
@@ -258,14 +259,14 @@ func TestSettingsS3SeedData(t *testing.T) {
 func TestSettingsGetS3Settings(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
-	if err := ss.Set("backup_s3_bucket", "my-bucket"); err != nil {
+	if err := ss.Set(testHouseholdID, "backup_s3_bucket", "my-bucket"); err != nil {
 		t.Fatalf("set: %v", err)
 	}
-	if err := ss.Set("backup_s3_access_key", "AKID"); err != nil {
+	if err := ss.Set(testHouseholdID, "backup_s3_access_key", "AKID"); err != nil {
 		t.Fatalf("set: %v", err)
 	}
 
-	settings, err := ss.GetS3Settings()
+	settings, err := ss.GetS3Settings(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get s3: %v", err)
 	}
@@ -284,7 +285,7 @@ func TestSettingsGetS3Settings(t *testing.T) {
 func TestSettingsVAPIDSeedData(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
-	settings, err := ss.GetVAPIDSettings()
+	settings, err := ss.GetVAPIDSettings(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get vapid settings: %v", err)
 	}
@@ -304,14 +305,14 @@ func TestSettingsVAPIDSeedData(t *testing.T) {
 func TestSettingsGetVAPIDSettings(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
-	if err := ss.Set("vapid_public_key", "pub-key-123"); err != nil {
+	if err := ss.Set(testHouseholdID, "vapid_public_key", "pub-key-123"); err != nil {
 		t.Fatalf("set: %v", err)
 	}
-	if err := ss.Set("vapid_private_key", "priv-key-456"); err != nil {
+	if err := ss.Set(testHouseholdID, "vapid_private_key", "priv-key-456"); err != nil {
 		t.Fatalf("set: %v", err)
 	}
 
-	settings, err := ss.GetVAPIDSettings()
+	settings, err := ss.GetVAPIDSettings(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get vapid: %v", err)
 	}
@@ -330,17 +331,17 @@ func TestSettingsGetVAPIDSettings(t *testing.T) {
 func TestSettingsWeatherUpdate(t *testing.T) {
 	ss := setupSettingsTestDB(t)
 
-	if err := ss.Set("weather_latitude", "47.6062"); err != nil {
+	if err := ss.Set(testHouseholdID, "weather_latitude", "47.6062"); err != nil {
 		t.Fatalf("set lat: %v", err)
 	}
-	if err := ss.Set("weather_longitude", "-122.3321"); err != nil {
+	if err := ss.Set(testHouseholdID, "weather_longitude", "-122.3321"); err != nil {
 		t.Fatalf("set lon: %v", err)
 	}
-	if err := ss.Set("weather_units", "celsius"); err != nil {
+	if err := ss.Set(testHouseholdID, "weather_units", "celsius"); err != nil {
 		t.Fatalf("set units: %v", err)
 	}
 
-	settings, err := ss.GetWeatherSettings()
+	settings, err := ss.GetWeatherSettings(testHouseholdID)
 	if err != nil {
 		t.Fatalf("get weather: %v", err)
 	}
@@ -355,3 +356,57 @@ func TestSettingsWeatherUpdate(t *testing.T) {
 		t.Errorf("units = %q, want %q", settings["weather_units"], "celsius")
 	}
 }
+
+func TestSettingsHouseholdIsolation(t *testing.T) {
+	ss := setupSettingsTestDB(t)
+	otherID := createTestHousehold(t, ss.db, "Other")
+
+	if err := ss.Set(otherID, "idle_timeout_minutes", "30"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	ours, _ := ss.Get(testHouseholdID, "idle_timeout_minutes")
+	if ours != "5" {
+		t.Errorf("idle_timeout_minutes = %q, want %q", ours, "5")
+	}
+	theirs, _ := ss.Get(otherID, "idle_timeout_minutes")
+	if theirs != "30" {
+		t.Errorf("other idle_timeout_minutes = %q, want %q", theirs, "30")
+	}
+
+	kiosk, _ := ss.GetKioskSettings(otherID)
+	if kiosk["idle_timeout_minutes"] != "30" {
+		t.Errorf("other kiosk idle_timeout_minutes = %q, want %q", kiosk["idle_timeout_minutes"], "30")
+	}
+}
+
+func TestSettingsLocation(t *testing.T) {
+	ss := setupSettingsTestDB(t)
+
+	loc, err := ss.Location(testHouseholdID)
+	if err != nil || loc != time.UTC {
+		t.Errorf("default location = %v, %v; want UTC", loc, err)
+	}
+
+	if err := ss.Set(testHouseholdID, TimezoneKey, "America/Denver"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	loc, err = ss.Location(testHouseholdID)
+	if err != nil || loc.String() != "America/Denver" {
+		t.Errorf("location = %v, %v; want America/Denver", loc, err)
+	}
+
+	// Other households keep their own timezone.
+	otherID := createTestHousehold(t, ss.db, "Other")
+	if loc, _ := ss.Location(otherID); loc != time.UTC {
+		t.Errorf("other household location = %v, want UTC", loc)
+	}
+
+	if err := ss.Set(testHouseholdID, TimezoneKey, "Mars/Olympus_Mons"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	loc, err = ss.Location(testHouseholdID)
+	if err == nil || loc != time.UTC {
+		t.Errorf("invalid timezone = %v, %v; want UTC and an error", loc, err)
+	}
+}
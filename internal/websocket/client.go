@@ -12,19 +12,25 @@ const (
 	pingInterval   = 30 * time.Second
 )
 
-// Client represents a single WebSocket connection.
+// Client represents a single WebSocket connection. It is bound to the
+// household (and user) that opened it so the hub can target messages.
 type Client struct {
-	hub  *Hub
-	conn *ws.Conn
-	send chan []byte
+	hub         *Hub
+	conn        *ws.Conn
+	send        chan []byte
+	householdID int64
+	userID      int64
 }
 
-// NewClient creates a Client tied to the given hub and connection.
-func NewClient(hub *Hub, conn *ws.Conn) *Client {
+// NewClient creates a Client tied to the given hub, connection, household,
+// and user. A zero userID means the client is not bound to a user.
+func NewClient(hub *Hub, conn *ws.Conn, householdID, userID int64) *Client {
 	return &Client{
-		hub:  hub,
-		conn: conn,
-		send: make(chan []byte, sendBufferSize),
+		hub:         hub,
+		conn:        conn,
+		send:        make(chan []byte, sendBufferSize),
+		householdID: householdID,
+		userID:      userID,
 	}
 }
 
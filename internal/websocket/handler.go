@@ -4,10 +4,11 @@ import (
 	"net/http"
 
 	ws "github.com/coder/websocket"
+	"github.com/dukerupert/gamwich/internal/auth"
 )
 
 // HandleWebSocket returns an HTTP handler that upgrades connections to WebSocket
-// and runs them as Hub clients.
+// and runs them as Hub clients bound to the authenticated household and user.
 func HandleWebSocket(hub *Hub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		conn, err := ws.Accept(w, r, &ws.AcceptOptions{
@@ -18,7 +19,8 @@ func HandleWebSocket(hub *Hub) http.HandlerFunc {
 			return
 		}
 
-		client := NewClient(hub, conn)
-		client.Run(r.Context())
+		ctx := r.Context()
+		client := NewClient(hub, conn, auth.HouseholdID(ctx), auth.UserID(ctx))
+		client.Run(ctx)
 	}
 }
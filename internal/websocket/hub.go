@@ -7,7 +7,7 @@ import (
 	"sync"
 )
 
-// Message represents a real-time sync notification broadcast to all clients.
+// Message represents a real-time sync notification sent to connected clients.
 type Message struct {
 	Type   string         `json:"type"`
 	Entity string         `json:"entity"`
@@ -59,8 +59,25 @@ func (h *Hub) Unregister(c *Client) {
 	h.mu.Unlock()
 }
 
-// Broadcast sends a message to all connected clients.
+// Broadcast sends a message to all connected clients. Use it only for
+// system-wide events; household data changes go through BroadcastToHousehold.
 func (h *Hub) Broadcast(msg Message) {
+	h.send(msg, func(*Client) bool { return true })
+}
+
+// BroadcastToHousehold sends a message to every client connected on behalf of
+// the given household.
+func (h *Hub) BroadcastToHousehold(householdID int64, msg Message) {
+	h.send(msg, func(c *Client) bool { return c.householdID == householdID })
+}
+
+// SendToUser sends a message to every client connected as the given user.
+func (h *Hub) SendToUser(userID int64, msg Message) {
+	h.send(msg, func(c *Client) bool { return c.userID == userID })
+}
+
+// send delivers a message to the connected clients accepted by match.
+func (h *Hub) send(msg Message, match func(*Client) bool) {
 	data, err := json.Marshal(msg)
 	if err != nil {
 		h.logger.Error("marshal broadcast", "error", err)
@@ -71,6 +88,9 @@ func (h *Hub) Broadcast(msg Message) {
 	defer h.mu.RUnlock()
 
 	for c := range h.clients {
+		if !match(c) {
+			continue
+		}
 		select {
 		case c.send <- data:
 		default:
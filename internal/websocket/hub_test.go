@@ -10,13 +10,25 @@ import (
 
 // mockClient creates a Client with a send channel but no real connection.
 func mockClient(hub *Hub) *Client {
+	return mockHouseholdClient(hub, 1, 0)
+}
+
+// mockHouseholdClient creates a mock Client bound to a household and user.
+func mockHouseholdClient(hub *Hub, householdID, userID int64) *Client {
 	return &Client{
-		hub:  hub,
-		conn: nil,
-		send: make(chan []byte, sendBufferSize),
+		hub:         hub,
+		conn:        nil,
+		send:        make(chan []byte, sendBufferSize),
+		householdID: householdID,
+		userID:      userID,
 	}
 }
 
+// pending returns the number of messages queued for the client.
+func pending(c *Client) int {
+	return len(c.send)
+}
+
 func TestRegisterUnregister(t *testing.T) {
 	hub := NewHub(slog.Default())
 
@@ -93,6 +105,71 @@ func TestBroadcast(t *testing.T) {
 	hub.Unregister(c2)
 }
 
+func TestBroadcastToHousehold(t *testing.T) {
+	hub := NewHub(slog.Default())
+
+	a1 := mockHouseholdClient(hub, 1, 10)
+	a2 := mockHouseholdClient(hub, 1, 11)
+	b := mockHouseholdClient(hub, 2, 20)
+	for _, c := range []*Client{a1, a2, b} {
+		hub.Register(c)
+	}
+
+	hub.BroadcastToHousehold(1, NewMessage("grocery_item", "created", 1, nil))
+
+	if got := pending(a1); got != 1 {
+		t.Errorf("household 1 client 1: expected 1 message, got %d", got)
+	}
+	if got := pending(a2); got != 1 {
+		t.Errorf("household 1 client 2: expected 1 message, got %d", got)
+	}
+	if got := pending(b); got != 0 {
+		t.Errorf("household 2 client: expected 0 messages, got %d", got)
+	}
+}
+
+func TestSendToUser(t *testing.T) {
+	hub := NewHub(slog.Default())
+
+	kitchen := mockHouseholdClient(hub, 1, 0)
+	phone := mockHouseholdClient(hub, 1, 10)
+	tablet := mockHouseholdClient(hub, 2, 10)
+	other := mockHouseholdClient(hub, 1, 11)
+	for _, c := range []*Client{kitchen, phone, tablet, other} {
+		hub.Register(c)
+	}
+
+	hub.SendToUser(10, NewMessage("push", "test", 0, nil))
+
+	if got := pending(phone); got != 1 {
+		t.Errorf("user 10 phone: expected 1 message, got %d", got)
+	}
+	if got := pending(tablet); got != 1 {
+		t.Errorf("user 10 tablet: expected 1 message, got %d", got)
+	}
+	if got := pending(kitchen); got != 0 {
+		t.Errorf("unbound client: expected 0 messages, got %d", got)
+	}
+	if got := pending(other); got != 0 {
+		t.Errorf("user 11: expected 0 messages, got %d", got)
+	}
+}
+
+func TestBroadcastReachesAllHouseholds(t *testing.T) {
+	hub := NewHub(slog.Default())
+
+	a := mockHouseholdClient(hub, 1, 0)
+	b := mockHouseholdClient(hub, 2, 0)
+	hub.Register(a)
+	hub.Register(b)
+
+	hub.Broadcast(NewMessage("system", "update", 0, nil))
+
+	if pending(a) != 1 || pending(b) != 1 {
+		t.Errorf("expected system message for every household, got %d and %d", pending(a), pending(b))
+	}
+}
+
 func TestBroadcastEmptyHub(t *testing.T) {
 	hub := NewHub(slog.Default())
 	// Should not panic